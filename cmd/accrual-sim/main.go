@@ -0,0 +1,123 @@
+// Команда accrual-sim Имитатор системы расчёта начислений, см. пакет accrualsim.
+// Правила и сценарий читаются из YAML файла -config, явно указанные флаги перекрывают значения из файла
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/ShukinDmitriy/gophermart/internal/accrualsim"
+	"github.com/ShukinDmitriy/gophermart/internal/logging"
+	"go.uber.org/zap"
+)
+
+const defaultAddress = "localhost:8082"
+
+func main() {
+	os.Exit(run(os.Args[0], os.Args[1:]))
+}
+
+// run Запуск имитатора до сигнала остановки. Возвращает код выхода
+func run(name string, args []string) int {
+	// Флаги разбираются первыми, чтобы узнать путь к файлу, но применяются поверх него
+	runAddress := os.Getenv("RUN_ADDRESS")
+	if runAddress == "" {
+		runAddress = defaultAddress
+	}
+
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	address := fs.String("a", runAddress, "Run address, also RUN_ADDRESS")
+	configFile := fs.String("config", "", "YAML file with rewards, orders and scenario")
+	logLevel := fs.String("log-level", "info", "Log level: debug, info, warn or error")
+	bindFlags(fs, &accrualsim.Config{})
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+
+	conf := &accrualsim.Config{}
+	if *configFile != "" {
+		var err error
+		if conf, err = accrualsim.LoadConfig(*configFile); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+	}
+
+	explicit := flag.NewFlagSet(name, flag.ContinueOnError)
+	bindFlags(explicit, conf)
+	var err error
+	fs.Visit(func(f *flag.Flag) {
+		if explicit.Lookup(f.Name) != nil {
+			err = errors.Join(err, explicit.Set(f.Name, f.Value.String()))
+		}
+	})
+	if err == nil {
+		err = conf.Validate()
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid configuration:\n%s\n", err)
+		return 2
+	}
+
+	logger, _, err := logging.New(*logLevel)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	defer logger.Sync()
+
+	server := &http.Server{
+		Addr:              *address,
+		Handler:           accrualsim.NewServer(conf, logger),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go func() {
+		<-ctx.Done()
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			logger.Error("cannot shut down accrual simulator", zap.Error(err))
+		}
+	}()
+
+	logger.Info("Running accrual simulator",
+		zap.String("address", *address),
+		zap.String("config", *configFile),
+		zap.Int("rate_limit", conf.RateLimit),
+		zap.Float64("error_rate", conf.ErrorRate),
+		zap.Duration("processing_delay", conf.ProcessingDelay),
+		zap.Int("scenario_orders", len(conf.Scenario)),
+	)
+
+	if err = server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logger.Error("accrual simulator stopped", zap.Error(err))
+		return 1
+	}
+
+	return 0
+}
+
+// bindFlags Флаги, перекрывающие правила из файла
+func bindFlags(fs *flag.FlagSet, conf *accrualsim.Config) {
+	fs.IntVar(&conf.RateLimit, "rate-limit", conf.RateLimit, "Requests per minute, 0 for unlimited")
+	fs.Float64Var(&conf.ErrorRate, "error-rate", conf.ErrorRate, "Share of 500 responses, from 0 to 1")
+	fs.Int64Var(&conf.Seed, "seed", conf.Seed, "Random seed for -error-rate")
+	fs.DurationVar(&conf.ProcessingDelay, "processing-delay", conf.ProcessingDelay, "Time from registration to PROCESSED")
+	fs.DurationVar(&conf.Latency, "latency", conf.Latency, "Delay of every response")
+	fs.BoolVar(&conf.RegisterUnknown, "register-unknown", conf.RegisterUnknown, "Register unknown orders on the first request instead of 204")
+}
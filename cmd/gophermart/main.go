@@ -2,180 +2,719 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"flag"
+	"fmt"
 	"github.com/ShukinDmitriy/gophermart/internal/auth"
 	"github.com/ShukinDmitriy/gophermart/internal/config"
 	"github.com/ShukinDmitriy/gophermart/internal/controllers"
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+	"github.com/ShukinDmitriy/gophermart/internal/grpcserver"
+	"github.com/ShukinDmitriy/gophermart/internal/logging"
+	"github.com/ShukinDmitriy/gophermart/internal/metrics"
+	"github.com/ShukinDmitriy/gophermart/internal/migrations"
+	"github.com/ShukinDmitriy/gophermart/internal/models"
+	"github.com/ShukinDmitriy/gophermart/internal/netguard"
+	"github.com/ShukinDmitriy/gophermart/internal/openapi"
 	"github.com/ShukinDmitriy/gophermart/internal/repositories"
+	"github.com/ShukinDmitriy/gophermart/internal/repositories/memory"
 	"github.com/ShukinDmitriy/gophermart/internal/services"
+	"github.com/ShukinDmitriy/gophermart/internal/sqlite"
+	"github.com/ShukinDmitriy/gophermart/internal/tracing"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/joho/godotenv"
 	echojwt "github.com/labstack/echo-jwt/v4"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
-	"github.com/labstack/gommon/log"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/labstack/echo/otelecho"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 	"go.uber.org/fx"
+	"go.uber.org/fx/fxevent"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"gopkg.in/yaml.v3"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
-	"gorm.io/gorm/logger"
+	gormtracing "gorm.io/plugin/opentelemetry/tracing"
+	"net"
 	"net/http"
 	"os"
 	"time"
 )
 
+// serviceName Имя сервиса в трейсах
+const serviceName = "gophermart"
+
 func main() {
+	// Конфигурация из файла, окружения (включая .env) и флагов, см. пакет config.
+	// Логгер ещё не создан: его уровень тоже берётся из конфигурации
+	if err := godotenv.Load(); err != nil {
+		fmt.Fprintln(os.Stderr, "No .env file found")
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		os.Exit(runMigrateCommand(os.Args[2:]))
+	}
+
+	conf, err := config.Load(os.Args[0], os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	if conf.PrintConfig {
+		if err = yaml.NewEncoder(os.Stdout).Encode(conf.Redacted()); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if err = conf.Validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "invalid configuration:\n%s\n", err)
+		os.Exit(2)
+	}
+
 	fx.New(
+		fx.Supply(conf),
+		fx.WithLogger(func(logger *zap.Logger) fxevent.Logger {
+			return &fxevent.ZapLogger{Logger: logger.Named("fx")}
+		}),
 		fx.Provide(
 			NewHTTPServer,
-			NewConfig,
-			NewDB,
-			func(DB *gorm.DB) *repositories.AccountRepository {
-				return repositories.NewAccountRepository(DB)
-			},
-			func(DB *gorm.DB) *repositories.OperationRepository {
-				return repositories.NewOperationRepository(DB)
+			NewLogger,
+			metrics.New,
+			NewAdminServer,
+			NewStorage,
+			func(conf *config.Config) *http.Client {
+				return &http.Client{
+					Timeout:   conf.AccrualRequestTimeout,
+					Transport: otelhttp.NewTransport(http.DefaultTransport),
+				}
 			},
-			func(DB *gorm.DB) *repositories.OrderRepository {
-				return repositories.NewOrderRepository(DB)
+			NewOrderEventBroker,
+			NewAccrualProvider,
+			NewEventSink,
+			func(
+				conf *config.Config,
+				storage *repositories.Storage,
+				sink services.EventSinkInterface,
+				webhookService *services.WebhookService,
+				notificationService *services.NotificationService,
+				m *metrics.Metrics,
+				logger *zap.Logger,
+			) *services.OutboxRelay {
+				return services.NewOutboxRelay(
+					services.OutboxOptions{
+						PollInterval: conf.EventsPollInterval,
+						RetryBackoff: conf.EventsRetryBackoff,
+						Retention:    conf.EventsRetention,
+					},
+					storage.Outbox,
+					// вебхуки и уведомления об исходе заказа уходят только по событиям из outbox
+					services.NewUserEventDispatcher(sink, webhookService, notificationService, logger),
+					m,
+					logger,
+				)
 			},
-			func(DB *gorm.DB) *repositories.UserRepository {
-				return repositories.NewUserRepository(DB)
+			func(conf *config.Config, storage *repositories.Storage, m *metrics.Metrics, logger *zap.Logger) *services.WebhookService {
+				return services.NewWebhookService(
+					services.WebhookOptions{
+						MaxAttempts:          conf.WebhookMaxAttempts,
+						RetryBackoff:         conf.WebhookRetryBackoff,
+						PollInterval:         conf.WebhookPollInterval,
+						AllowPrivateNetworks: conf.WebhookAllowPrivateNetworks,
+					},
+					storage.Webhooks,
+					NewUserURLClient(conf, conf.WebhookRequestTimeout),
+					m,
+					logger,
+				)
 			},
-			func() *http.Client {
-				return &http.Client{
-					Timeout: 10 * time.Second,
+			func(conf *config.Config, storage *repositories.Storage, m *metrics.Metrics, logger *zap.Logger) *services.NotificationService {
+				channels := map[entities.NotificationChannel]services.NotificationChannelInterface{
+					entities.NotificationChannelWebhook: services.NewWebhookNotificationChannel(
+						NewUserURLClient(conf, conf.NotificationRequestTimeout),
+					),
+				}
+				if conf.SMTPAddress != "" {
+					channels[entities.NotificationChannelEmail] = services.NewSMTPNotificationChannel(services.SMTPOptions{
+						Address:  conf.SMTPAddress,
+						Username: conf.SMTPUsername,
+						Password: conf.SMTPPassword,
+						From:     conf.SMTPFrom,
+					})
 				}
+
+				return services.NewNotificationService(
+					services.NotificationOptions{
+						MaxAttempts:          conf.NotificationMaxAttempts,
+						RetryBackoff:         conf.NotificationRetryBackoff,
+						PollInterval:         conf.NotificationPollInterval,
+						AllowPrivateNetworks: conf.WebhookAllowPrivateNetworks,
+					},
+					storage.Notifications,
+					storage.Users,
+					channels,
+					m,
+					logger,
+				)
 			},
 			func(
 				conf *config.Config,
-				accountRepository *repositories.AccountRepository,
-				operationRepository *repositories.OperationRepository,
-				orderRepository *repositories.OrderRepository,
-				httpClient *http.Client,
+				storage *repositories.Storage,
+				accrualProvider services.AccrualProvider,
+				orderEventBroker services.OrderEventBrokerInterface,
+				m *metrics.Metrics,
+				logger *zap.Logger,
 			) *services.AccrualService {
+				options := services.AccrualOptions{
+					QueueSize:     conf.AccrualQueueSize,
+					RetryInterval: conf.AccrualRetryInterval,
+					WaitForBasket: conf.AccrualProvider == config.AccrualProviderRules,
+				}
+				// результаты присылает система расчёта, опрос остаётся запасным и идёт реже
+				if conf.AccrualCallbackSecret != "" {
+					options.RetryInterval = conf.AccrualCallbackPollInterval
+				}
+
 				return services.NewAccrualService(
-					conf.AccrualSystemAddress,
-					accountRepository,
-					operationRepository,
-					orderRepository,
-					httpClient,
+					options,
+					storage.Orders,
+					accrualProvider,
+					orderEventBroker,
+					m,
+					logger,
+				)
+			},
+			func(
+				conf *config.Config,
+				accrualService *services.AccrualService,
+				storage *repositories.Storage,
+				m *metrics.Metrics,
+				logger *zap.Logger,
+			) *services.ReconciliationService {
+				return services.NewReconciliationService(
+					conf.ReconciliationAutoCorrect,
+					conf.ReconciliationReportDir,
+					accrualService,
+					storage.Accounts,
+					storage.Operations,
+					storage.Orders,
+					storage.Reconciliations,
+					m,
+					logger,
 				)
 			},
-			func(userRepository *repositories.UserRepository) *auth.AuthUser {
-				return auth.NewAuthUser(userRepository)
+			func(conf *config.Config, storage *repositories.Storage) *auth.AuthUser {
+				return auth.NewAuthUser(storage.Users, conf.JwtSecretKey)
+			},
+			func(conf *config.Config, authUser *auth.AuthUser) *auth.AuthService {
+				return auth.NewAuthService(*authUser, conf.JwtSecretKey, conf.AccessTokenTTL, conf.RefreshTokenTTL)
+			},
+			func(storage *repositories.Storage) *services.RewardService {
+				return services.NewRewardService(storage.Rewards)
+			},
+			func(rewardService *services.RewardService) *controllers.RewardController {
+				return controllers.NewRewardController(rewardService)
+			},
+			func(storage *repositories.Storage) *services.UserService {
+				return services.NewUserService(storage.Users)
 			},
-			func(authUser *auth.AuthUser) *auth.AuthService {
-				return auth.NewAuthService(*authUser)
+			func(storage *repositories.Storage, accrualService *services.AccrualService) *services.OrderService {
+				return services.NewOrderService(storage.Orders, storage.Operations, accrualService)
+			},
+			func(storage *repositories.Storage, webhookService *services.WebhookService, m *metrics.Metrics) *services.LedgerService {
+				return services.NewLedgerService(
+					storage.Accounts,
+					storage.Operations,
+					storage.Orders,
+					webhookService,
+					m,
+				)
 			},
 			func(
 				authService *auth.AuthService,
-				accountRepository *repositories.AccountRepository,
-				operationRepository *repositories.OperationRepository,
+				ledgerService *services.LedgerService,
 			) *controllers.BalanceController {
 				return controllers.NewBalanceController(
 					authService,
-					accountRepository,
-					operationRepository,
+					ledgerService,
 				)
 			},
 			func(
 				authService *auth.AuthService,
-				accountRepository *repositories.AccountRepository,
-				operationRepository *repositories.OperationRepository,
-				orderRepository *repositories.OrderRepository,
+				ledgerService *services.LedgerService,
 			) *controllers.OperationController {
 				return controllers.NewOperationController(
 					authService,
-					accountRepository,
-					operationRepository,
-					orderRepository,
+					ledgerService,
 				)
 			},
 			func(
+				conf *config.Config,
 				authService *auth.AuthService,
-				orderRepository *repositories.OrderRepository,
-				accrualService *services.AccrualService,
+				orderService *services.OrderService,
+				orderEventBroker services.OrderEventBrokerInterface,
 			) *controllers.OrderController {
 				return controllers.NewOrderController(
 					authService,
-					orderRepository,
-					accrualService,
+					orderService,
+					orderEventBroker,
+					conf.OrderBatchLimit,
 				)
 			},
 			func(
 				authService *auth.AuthService,
-				userRepository *repositories.UserRepository,
+				webhookService *services.WebhookService,
+			) *controllers.WebhookController {
+				return controllers.NewWebhookController(
+					authService,
+					webhookService,
+				)
+			},
+			func(
+				authService *auth.AuthService,
+				notificationService *services.NotificationService,
+			) *controllers.NotificationController {
+				return controllers.NewNotificationController(
+					authService,
+					notificationService,
+				)
+			},
+			func(
+				authService *auth.AuthService,
+				userService *services.UserService,
 			) *controllers.UserController {
 				return controllers.NewUserController(
 					authService,
-					userRepository,
+					userService,
+				)
+			},
+			func(
+				authService *auth.AuthService,
+				userService *services.UserService,
+				orderService *services.OrderService,
+				ledgerService *services.LedgerService,
+			) *grpcserver.GophermartServer {
+				return grpcserver.NewGophermartServer(
+					authService,
+					userService,
+					orderService,
+					ledgerService,
 				)
 			},
+			NewGRPCServer,
+			NewHealthService,
+			func(healthService *services.HealthService) *controllers.HealthController {
+				return controllers.NewHealthController(healthService)
+			},
 		),
+		fx.Invoke(NewTracing),
 		fx.Invoke(func(*echo.Echo) {}),
-		fx.Invoke(runMigrate),
-		fx.Invoke(func(accrualService *services.AccrualService, e *echo.Echo) {
-			go accrualService.ProcessOrders(e)
-			go accrualService.ProcessFailedOrders(e)
+		fx.Invoke(func(*grpc.Server) {}),
+		fx.Invoke(func(*http.ServeMux) {}),
+		fx.Invoke(func(m *metrics.Metrics, storage *repositories.Storage) {
+			m.RegisterOrdersByStatus(storage.Orders)
+		}),
+		fx.Invoke(func(conf *config.Config, accrualService *services.AccrualService) {
+			for i := 0; i < conf.AccrualWorkers; i++ {
+				go accrualService.ProcessOrders()
+			}
+			go accrualService.ProcessFailedOrders()
+		}),
+		fx.Invoke(func(lc fx.Lifecycle, webhookService *services.WebhookService) {
+			ctx, cancel := context.WithCancel(context.Background())
+			lc.Append(fx.Hook{
+				OnStart: func(context.Context) error {
+					go webhookService.Run(ctx)
+					return nil
+				},
+				OnStop: func(context.Context) error {
+					cancel()
+					return nil
+				},
+			})
+		}),
+		fx.Invoke(func(lc fx.Lifecycle, notificationService *services.NotificationService) {
+			ctx, cancel := context.WithCancel(context.Background())
+			lc.Append(fx.Hook{
+				OnStart: func(context.Context) error {
+					go notificationService.Run(ctx)
+					return nil
+				},
+				OnStop: func(context.Context) error {
+					cancel()
+					return nil
+				},
+			})
+		}),
+		fx.Invoke(func(lc fx.Lifecycle, outboxRelay *services.OutboxRelay) {
+			ctx, cancel := context.WithCancel(context.Background())
+			lc.Append(fx.Hook{
+				OnStart: func(context.Context) error {
+					go outboxRelay.Run(ctx)
+					return nil
+				},
+				OnStop: func(context.Context) error {
+					cancel()
+					return nil
+				},
+			})
+		}),
+		fx.Invoke(func(lc fx.Lifecycle, orderEventBroker services.OrderEventBrokerInterface) {
+			ctx, cancel := context.WithCancel(context.Background())
+			lc.Append(fx.Hook{
+				OnStart: func(context.Context) error {
+					go orderEventBroker.Listen(ctx)
+					return nil
+				},
+				OnStop: func(context.Context) error {
+					cancel()
+					return nil
+				},
+			})
+		}),
+		fx.Invoke(func(lc fx.Lifecycle, reconciliationService *services.ReconciliationService) {
+			ctx, cancel := context.WithCancel(context.Background())
+			lc.Append(fx.Hook{
+				OnStart: func(context.Context) error {
+					go reconciliationService.RunNightly(ctx)
+					return nil
+				},
+				OnStop: func(context.Context) error {
+					cancel()
+					return nil
+				},
+			})
+		}),
+		// Хуки остановки выполняются в обратном порядке, поэтому этот сработает раньше остановки серверов
+		fx.Invoke(func(lc fx.Lifecycle, conf *config.Config, healthService *services.HealthService, logger *zap.Logger) {
+			lc.Append(fx.Hook{
+				OnStop: func(ctx context.Context) error {
+					healthService.SetShuttingDown()
+					logger.Info("Draining traffic before shutdown", zap.Duration("delay", conf.ShutdownDrainDelay))
+
+					select {
+					case <-time.After(conf.ShutdownDrainDelay):
+					case <-ctx.Done():
+					}
+
+					return nil
+				},
+			})
 		}),
 	).Run()
 }
 
-func NewDB(conf *config.Config) *gorm.DB {
-	postgresqlURL := conf.DatabaseURI
+func NewGRPCServer(
+	lc fx.Lifecycle,
+	conf *config.Config,
+	authService *auth.AuthService,
+	gophermartServer *grpcserver.GophermartServer,
+	logger *zap.Logger,
+) *grpc.Server {
+	server := grpcserver.NewServer(authService, gophermartServer)
 
-	if postgresqlURL == "" {
-		log.Fatal("no DATABASE_URI in .env")
-		return nil
+	// Пустой адрес отключает gRPC API
+	if conf.GRPCAddress == "" {
+		return server
 	}
 
-	db, err := gorm.Open(postgres.Open(postgresqlURL), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Error),
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			listener, err := net.Listen("tcp", conf.GRPCAddress)
+			if err != nil {
+				return err
+			}
+
+			go func() {
+				if err := server.Serve(listener); err != nil {
+					logger.Fatal("shutting down the gophermart grpc server", zap.Error(err))
+				}
+			}()
+
+			logger.Info("Running gophermart grpc server", zap.String("address", conf.GRPCAddress))
+
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			server.GracefulStop()
+			return nil
+		},
 	})
+
+	return server
+}
+
+// NewHealthService Пробы готовности. Ожидаемая версия схемы последняя среди встроенных миграций,
+// у хранилища в памяти схемы нет
+func NewHealthService(conf *config.Config, storage *repositories.Storage, accrualService *services.AccrualService) (*services.HealthService, error) {
+	var version uint
+	if conf.Storage != config.StorageMemory {
+		var err error
+		version, err = migrations.Latest(conf.Storage)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read migrations: %w", err)
+		}
+	}
+
+	return services.NewHealthService(
+		storage.Health,
+		accrualService,
+		version,
+		// обработчики очереди и опрос незавершённых заказов
+		conf.AccrualWorkers+1,
+	), nil
+}
+
+// NewUserURLClient Клиент для адресов, которые задают пользователи. Подключается только к публичным
+// адресам (кроме WebhookAllowPrivateNetworks) и не следует перенаправлениям
+func NewUserURLClient(conf *config.Config, timeout time.Duration) *http.Client {
+	var transport http.RoundTripper = netguard.NewTransport()
+	if conf.WebhookAllowPrivateNetworks {
+		transport = http.DefaultTransport
+	}
+
+	return &http.Client{
+		Timeout:       timeout,
+		Transport:     otelhttp.NewTransport(transport),
+		CheckRedirect: netguard.NoRedirect,
+	}
+}
+
+// NewAccrualProvider Источник начислений: внешняя система расчёта или локальные правила вознаграждения
+func NewAccrualProvider(
+	conf *config.Config,
+	storage *repositories.Storage,
+	httpClient *http.Client,
+	m *metrics.Metrics,
+	logger *zap.Logger,
+) (services.AccrualProvider, error) {
+	switch conf.AccrualProvider {
+	case config.AccrualProviderExternal:
+		return services.NewHTTPAccrualProvider(conf.AccrualSystemAddress, conf.AccrualRateLimitBackoff, httpClient, m, logger), nil
+	case config.AccrualProviderRules:
+		return services.NewRulesAccrualProvider(storage.Rewards, logger), nil
+	default:
+		return nil, fmt.Errorf("unknown accrual provider: %s", conf.AccrualProvider)
+	}
+}
+
+// NewEventSink Получатель доменных событий из outbox. Соединение с брокером закрывается в OnStop,
+// после остановки публикации: хуки остановки выполняются в обратном порядке
+func NewEventSink(lc fx.Lifecycle, conf *config.Config, logger *zap.Logger) (services.EventSinkInterface, error) {
+	var sink services.EventSinkInterface
+	switch conf.EventsSink {
+	case config.EventsSinkLog:
+		sink = services.NewLogEventSink(logger)
+	case config.EventsSinkNATS:
+		natsSink, err := services.NewNATSEventSink(conf.EventsNATSURL, conf.EventsNATSSubject, logger)
+		if err != nil {
+			return nil, err
+		}
+		sink = natsSink
+	case config.EventsSinkKafka:
+		sink = services.NewKafkaEventSink(conf.KafkaBrokers(), conf.EventsKafkaTopic)
+	default:
+		return nil, fmt.Errorf("unknown events sink: %s", conf.EventsSink)
+	}
+
+	lc.Append(fx.Hook{
+		OnStop: func(context.Context) error {
+			return sink.Close()
+		},
+	})
+
+	return sink, nil
+}
+
+// NewTracing Глобальный TracerProvider. Спаны, накопленные к остановке, досылаются в OnStop
+func NewTracing(lc fx.Lifecycle, conf *config.Config, logger *zap.Logger) error {
+	shutdown, err := tracing.Setup(context.Background(), conf.TracingExporter, conf.TracingEndpoint, serviceName)
+	if err != nil {
+		return err
+	}
+
+	lc.Append(fx.Hook{
+		OnStop: func(ctx context.Context) error {
+			return shutdown(ctx)
+		},
+	})
+
+	logger.Info("Tracing configured", zap.String("exporter", conf.TracingExporter))
+
+	return nil
+}
+
+// NewLogger Единый логгер приложения. zap.L() тоже указывает на него
+func NewLogger(conf *config.Config) (*zap.Logger, zap.AtomicLevel, error) {
+	logger, level, err := logging.New(conf.LogLevel)
 	if err != nil {
-		log.Fatal(err)
-		return nil
+		return nil, level, fmt.Errorf("invalid log level: %w", err)
 	}
 
-	return db
+	zap.ReplaceGlobals(logger)
+
+	return logger, level, nil
 }
 
-func NewConfig() *config.Config {
-	conf := config.NewConfig()
+// NewAdminServer Служебный HTTP сервер, по умолчанию слушает только localhost.
+// GET /health подробное состояние сервиса.
+// GET /metrics метрики Prometheus.
+// GET /log/level возвращает текущий уровень логирования, PUT /log/level {"level":"debug"} меняет его.
+// /admin/ служебный API, см. controllers.RegisterAdminRoutes.
+// /log/level и /admin/ требуют токен AdminToken, /health и /metrics открыты для проб и сборщика метрик
+func NewAdminServer(
+	lc fx.Lifecycle,
+	conf *config.Config,
+	logger *zap.Logger,
+	level zap.AtomicLevel,
+	m *metrics.Metrics,
+	healthService *services.HealthService,
+	rewardController *controllers.RewardController,
+	webhookService *services.WebhookService,
+) *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		res := healthService.Health(r.Context())
 
-	if err := godotenv.Load(); err != nil {
-		log.Print("No .env file found")
+		w.Header().Set("Content-Type", "application/json")
+		if res.Status != models.HealthStatusOK {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		if err := json.NewEncoder(w).Encode(res); err != nil {
+			logger.Error("cannot write health response", zap.Error(err))
+		}
+	})
+	mux.Handle("/metrics", promhttp.HandlerFor(m.Registry, promhttp.HandlerOpts{Registry: m.Registry}))
+
+	admin := echo.New()
+	admin.HTTPErrorHandler = controllers.HTTPErrorHandler
+	admin.Use(middleware.RequestID())
+	admin.Use(logging.Middleware(logger.Named("admin")))
+	adminAuth := controllers.AdminAuth(conf.AdminToken)
+	admin.Any("/log/level", echo.WrapHandler(level), adminAuth)
+	controllers.RegisterAdminRoutes(
+		admin,
+		adminAuth,
+		rewardController,
+		controllers.NewIntegrationWebhookController(webhookService),
+	)
+	mux.Handle("/log/level", admin)
+	mux.Handle("/admin/", admin)
+
+	// Пустой адрес отключает служебный сервер
+	if conf.AdminAddress == "" {
+		return mux
 	}
 
-	flag.StringVar(&conf.RunAddress, "a", "localhost:8080", "Run address")
-	flag.StringVar(&conf.DatabaseURI, "d", "", "Database dsn")
-	flag.StringVar(&conf.AccrualSystemAddress, "r", "http://localhost:8082", "Accrual system address")
-	flag.StringVar(&conf.JwtSecretKey, "s", "", "JWT secret key")
+	server := &http.Server{
+		Addr:              conf.AdminAddress,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
 
-	flag.Parse()
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			listener, err := net.Listen("tcp", conf.AdminAddress)
+			if err != nil {
+				return err
+			}
 
-	runAddress, exists := os.LookupEnv("RUN_ADDRESS")
-	if exists {
-		conf.RunAddress = runAddress
+			go func() {
+				if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+					logger.Fatal("shutting down the gophermart admin server", zap.Error(err))
+				}
+			}()
+
+			logger.Info("Running gophermart admin server", zap.String("address", conf.AdminAddress))
+
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			return server.Shutdown(ctx)
+		},
+	})
+
+	return mux
+}
+
+// NewStorage Репозитории выбранного хранилища. Для Postgres и SQLite перед подключением готовится схема,
+// *gorm.DB нужен брокеру событий и равен nil для хранилища в памяти
+func NewStorage(conf *config.Config, logger *zap.Logger, m *metrics.Metrics) (*repositories.Storage, *gorm.DB, error) {
+	if conf.Storage == config.StorageMemory {
+		logger.Warn("Using in-memory storage, data will be lost on exit")
+		return memory.NewStorage(), nil, nil
 	}
 
-	databaseURI, exists := os.LookupEnv("DATABASE_URI")
-	if exists {
-		conf.DatabaseURI = databaseURI
+	if err := prepareSchema(conf, logger); err != nil {
+		return nil, nil, err
 	}
 
-	accrualSystemAddress, exists := os.LookupEnv("ACCRUAL_SYSTEM_ADDRESS")
-	if exists {
-		conf.AccrualSystemAddress = accrualSystemAddress
+	db, err := NewDB(conf, logger, m)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	jwtSecretKey, exists := os.LookupEnv("JWT_SECRET_KEY")
-	if exists {
-		conf.JwtSecretKey = jwtSecretKey
+	return repositories.NewGormStorage(db), db, nil
+}
+
+func NewDB(conf *config.Config, logger *zap.Logger, m *metrics.Metrics) (*gorm.DB, error) {
+	gormConfig := &gorm.Config{
+		Logger: logging.NewGormLogger(logger, conf.DBSlowQueryThreshold),
 	}
 
-	return conf
+	var db *gorm.DB
+	var err error
+	if conf.Storage == config.StorageSQLite {
+		db, err = sqlite.Open(conf.DatabaseURI, gormConfig)
+	} else {
+		db, err = gorm.Open(postgres.Open(conf.DatabaseURI), gormConfig)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// В спаны попадает текст запроса без значений параметров: среди них бывают пароли и токены
+	err = db.Use(gormtracing.NewPlugin(gormtracing.WithoutMetrics(), gormtracing.WithoutQueryVariables()))
+	if err != nil {
+		return nil, err
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	sqlDB.SetMaxOpenConns(conf.DBMaxOpenConns)
+	sqlDB.SetMaxIdleConns(conf.DBMaxIdleConns)
+	sqlDB.SetConnMaxLifetime(conf.DBConnMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(conf.DBConnMaxIdleTime)
+	m.RegisterDBStats(sqlDB)
+
+	return db, nil
+}
+
+func NewOrderEventBroker(conf *config.Config, db *gorm.DB, logger *zap.Logger) (services.OrderEventBrokerInterface, error) {
+	// без Postgres события рассылаются только внутри процесса: хранилище в памяти и SQLite
+	// обслуживают один экземпляр сервиса
+	if conf.Storage != config.StoragePostgres {
+		return services.NewInMemoryOrderEventBroker(), nil
+	}
+
+	switch conf.OrderEventsBroker {
+	case config.OrderEventsBrokerMemory:
+		return services.NewInMemoryOrderEventBroker(), nil
+	case config.OrderEventsBrokerPostgres:
+		return services.NewPostgresOrderEventBroker(db, conf.DatabaseURI, logger), nil
+	default:
+		return nil, fmt.Errorf("unknown order events broker: %s", conf.OrderEventsBroker)
+	}
 }
 
 func NewHTTPServer(
@@ -186,55 +725,95 @@ func NewHTTPServer(
 	operationController *controllers.OperationController,
 	orderController *controllers.OrderController,
 	userController *controllers.UserController,
-) *echo.Echo {
+	webhookController *controllers.WebhookController,
+	notificationController *controllers.NotificationController,
+	healthController *controllers.HealthController,
+	accrualService *services.AccrualService,
+	logger *zap.Logger,
+	m *metrics.Metrics,
+) (*echo.Echo, error) {
 	e := echo.New()
-	e.Logger.SetLevel(log.INFO)
+	e.HideBanner = true
+	e.HidePort = true
+	e.HTTPErrorHandler = controllers.HTTPErrorHandler
 
 	// middleware
+	e.Use(middleware.RequestID())
+	e.Use(otelecho.Middleware(serviceName))
+	e.Use(m.Middleware(func(c echo.Context) bool {
+		// длительность SSE потока говорит о клиенте, а не о сервере
+		return c.Path() == "/api/user/orders/stream"
+	}))
+	e.Use(logging.Middleware(logger.Named("http")))
 	e.Use(middleware.GzipWithConfig(middleware.GzipConfig{
 		Level: 5,
+		Skipper: func(c echo.Context) bool {
+			return c.Path() == "/api/user/orders/stream"
+		},
 	}))
 
 	// decompress
 	e.Use(middleware.Decompress())
 
+	// проверка запросов и ответов по спецификации OpenAPI
+	doc, err := openapi.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	specHandler, err := openapi.SpecHandler(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	switch conf.OpenAPIValidation {
+	case config.OpenAPIValidationOff:
+	case config.OpenAPIValidationRequests, config.OpenAPIValidationFull:
+		validator, err := openapi.NewValidator(doc, conf.OpenAPIValidation == config.OpenAPIValidationFull)
+		if err != nil {
+			return nil, err
+		}
+		e.Use(validator)
+	default:
+		return nil, fmt.Errorf("unknown openapi validation mode: %s", conf.OpenAPIValidation)
+	}
+
 	jwtMiddleware := echojwt.WithConfig(echojwt.Config{
 		BeforeFunc: authService.BeforeFunc,
 		NewClaimsFunc: func(_ echo.Context) jwt.Claims {
 			return &auth.Claims{}
 		},
-		SigningKey:    []byte(auth.GetJWTSecret()),
+		SigningKey:    []byte(conf.JwtSecretKey),
 		SigningMethod: jwt.SigningMethodHS256.Alg(),
 		TokenLookup:   "cookie:access-token", // "<source>:<name>"
 		ErrorHandler:  authService.JWTErrorChecker,
 	})
 
 	// routes
-	// POST /api/user/login — аутентификация пользователя;
-	// POST /api/user/orders — загрузка пользователем номера заказа для расчёта;
-	// GET /api/user/orders — получение списка загруженных пользователем номеров заказов, статусов их обработки и информации о начислениях;
-	// GET /api/user/balance — получение текущего баланса счёта баллов лояльности пользователя;
-	// POST /api/user/balance/withdraw — запрос на списание баллов с накопительного счёта в счёт оплаты нового заказа;
-	// GET /api/user/withdrawals — получение информации о выводе средств с накопительного счёта пользователем.
-
-	e.POST("/api/user/register", userController.UserRegister())
-	e.POST("/api/user/login", userController.UserLogin())
-	e.POST("/api/user/orders", orderController.CreateOrder(), jwtMiddleware)
-	e.GET("/api/user/orders", orderController.GetOrders(), jwtMiddleware)
-	e.GET("/api/user/balance", balanceController.GetBalance(), jwtMiddleware)
-	e.POST("/api/user/balance/withdraw", operationController.CreateWithdraw(), jwtMiddleware)
-	e.GET("/api/user/withdrawals", operationController.GetWithdrawals(), jwtMiddleware)
+	controllers.RegisterRoutes(e, jwtMiddleware, balanceController, operationController, orderController, userController, webhookController, notificationController)
+	controllers.RegisterHealthRoutes(e, healthController)
+	if conf.AccrualCallbackSecret != "" {
+		controllers.RegisterAccrualCallbackRoutes(e, controllers.NewAccrualCallbackController(
+			accrualService,
+			conf.AccrualCallbackSecret,
+			conf.AccrualCallbackTolerance,
+		))
+	}
+
+	// документация API
+	e.GET("/api/openapi.json", specHandler)
+	e.GET("/api/docs", openapi.SwaggerUIHandler("/api/openapi.json"))
 
 	lc.Append(fx.Hook{
 		OnStart: func(ctx context.Context) error {
 			go func() {
 				if err := e.Start(conf.RunAddress); err != nil && !errors.Is(err, http.ErrServerClosed) {
-					e.Logger.Fatal("shutting down the gophermart ", err.Error())
+					logger.Fatal("shutting down the gophermart", zap.Error(err))
 				}
-
-				e.Logger.Info("Running gophermart")
 			}()
 
+			logger.Info("Running gophermart", zap.String("address", conf.RunAddress))
+
 			return nil
 		},
 		OnStop: func(ctx context.Context) error {
@@ -242,5 +821,5 @@ func NewHTTPServer(
 		},
 	})
 
-	return e
+	return e, nil
 }
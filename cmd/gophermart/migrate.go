@@ -1,46 +1,133 @@
 package main
 
 import (
-	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
 	"os"
-	"path"
+	"strconv"
 
 	"github.com/ShukinDmitriy/gophermart/internal/config"
-	"github.com/golang-migrate/migrate/v4"
-	"github.com/golang-migrate/migrate/v4/database/postgres"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
-	"github.com/labstack/echo/v4"
+	"github.com/ShukinDmitriy/gophermart/internal/migrations"
+	"go.uber.org/zap"
 )
 
-func runMigrate(e *echo.Echo, config *config.Config) error {
-	db, err := sql.Open("postgres", config.DatabaseURI)
+const migrateUsage = `Usage: gophermart migrate [flags] <command>
+
+Commands:
+  up            apply all pending migrations
+  down [N|all]  roll back N migrations, 1 by default, or all of them
+  version       print the current schema version
+  force V       set version V without running migrations, after a failed one
+
+Flags are the same as for the server, only the database settings are used.
+`
+
+// runMigrateCommand Подкоманда gophermart migrate. Возвращает код выхода
+func runMigrateCommand(args []string) int {
+	conf, err := config.Load(os.Args[0]+" migrate", args)
+	if errors.Is(err, flag.ErrHelp) {
+		fmt.Fprint(os.Stderr, migrateUsage)
+		return 0
+	}
 	if err != nil {
-		e.Logger.Error("can't connect to db", err.Error())
-		return err
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	if len(conf.Args) == 0 {
+		fmt.Fprint(os.Stderr, migrateUsage)
+		return 2
+	}
+	if err = conf.ValidateDatabase(); err != nil {
+		fmt.Fprintf(os.Stderr, "invalid configuration:\n%s\n", err)
+		return 2
 	}
-	defer func() {
-		db.Close()
-	}()
 
-	driver, err := postgres.WithInstance(db, &postgres.Config{})
+	logger, _, err := NewLogger(conf)
 	if err != nil {
-		e.Logger.Error("can't create driver", err.Error())
-		return err
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	defer logger.Sync()
+
+	migrator, err := migrations.New(conf.Storage, conf.DatabaseURI, logger.Named("migrate"))
+	if err != nil {
+		logger.Error("cannot start migrations", zap.Error(err))
+		return 1
 	}
+	defer migrator.Close()
 
-	currentDir, _ := os.Getwd()
-	m, err := migrate.NewWithDatabaseInstance(
-		"file:///"+path.Join(currentDir, "db", "migrations"),
-		"postgres", driver)
+	command, params := conf.Args[0], conf.Args[1:]
+	switch {
+	case command == "up" && len(params) == 0:
+		err = migrator.Up()
+	case command == "down" && len(params) == 0:
+		err = migrator.Down(1)
+	case command == "down" && len(params) == 1 && params[0] == "all":
+		err = migrator.DownAll()
+	case command == "down" && len(params) == 1:
+		var steps int
+		steps, err = strconv.Atoi(params[0])
+		if err == nil && steps <= 0 {
+			err = errors.New("steps must be positive")
+		}
+		if err == nil {
+			err = migrator.Down(steps)
+		}
+	case command == "force" && len(params) == 1:
+		var version int
+		version, err = strconv.Atoi(params[0])
+		if err == nil {
+			err = migrator.Force(version)
+		}
+	case command == "version" && len(params) == 0:
+		var version uint
+		var dirty bool
+		version, dirty, err = migrator.Version()
+		if err == nil {
+			if dirty {
+				fmt.Printf("%d (dirty)\n", version)
+			} else {
+				fmt.Println(version)
+			}
+		}
+	default:
+		fmt.Fprint(os.Stderr, migrateUsage)
+		return 2
+	}
+	if err != nil {
+		logger.Error("migrate "+command+" failed", zap.Error(err))
+		return 1
+	}
+
+	return 0
+}
+
+// prepareSchema Схема при старте: в режиме auto миграции применяются,
+// в режиме check сервис не запускается, пока схема не поднята командой gophermart migrate up
+func prepareSchema(conf *config.Config, logger *zap.Logger) error {
+	migrator, err := migrations.New(conf.Storage, conf.DatabaseURI, logger.Named("migrate"))
 	if err != nil {
-		e.Logger.Error("can't create new migrate: ", err.Error())
 		return err
 	}
+	defer migrator.Close()
 
-	err = m.Up()
+	if conf.MigrationMode == config.MigrationModeCheck {
+		if err = migrator.Check(); err != nil {
+			return fmt.Errorf("%w, run gophermart migrate up", err)
+		}
+		return nil
+	}
+
+	if err = migrator.Up(); err != nil {
+		return fmt.Errorf("cannot migrate up: %w", err)
+	}
+
+	version, _, err := migrator.Version()
 	if err != nil {
-		e.Logger.Info("can't migrate up: ", err.Error())
+		return err
 	}
+	logger.Info("Database schema is up to date", zap.Uint("version", version))
 
 	return nil
 }
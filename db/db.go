@@ -0,0 +1,10 @@
+// Package db Файлы миграций схемы, встроенные в бинарник
+package db
+
+import "embed"
+
+// Migrations SQL миграции в формате golang-migrate: каталог migrations/<диалект>, файлы NNNNNN_name.up.sql и .down.sql.
+// Номера версий в диалектах совпадают: изменение схемы добавляется в каждый каталог под одним номером
+//
+//go:embed migrations/postgres/*.sql migrations/sqlite/*.sql
+var Migrations embed.FS
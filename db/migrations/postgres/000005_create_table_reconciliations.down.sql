@@ -0,0 +1,9 @@
+drop index if exists idx_reconciliation_discrepancies_run_id;
+
+drop index if exists idx_reconciliation_discrepancies_deleted_at;
+
+drop table if exists reconciliation_discrepancies;
+
+drop index if exists idx_reconciliation_runs_deleted_at;
+
+drop table if exists reconciliation_runs;
@@ -0,0 +1,42 @@
+create table if not exists reconciliation_runs
+(
+    id                 bigserial
+        primary key,
+    created_at         timestamp with time zone,
+    updated_at         timestamp with time zone,
+    deleted_at         timestamp with time zone,
+    period_from        timestamp with time zone not null,
+    period_to          timestamp with time zone not null,
+    auto_correct       boolean not null default false,
+    orders_checked     integer not null default 0,
+    discrepancies      integer not null default 0,
+    corrected          integer not null default 0,
+    finished_at        timestamp with time zone
+);
+
+create index if not exists idx_reconciliation_runs_deleted_at
+    on reconciliation_runs (deleted_at);
+
+create table if not exists reconciliation_discrepancies
+(
+    id             bigserial
+        primary key,
+    created_at     timestamp with time zone,
+    updated_at     timestamp with time zone,
+    deleted_at     timestamp with time zone,
+    run_id         bigint not null,
+    order_number   varchar not null,
+    type           varchar not null,
+    local_status   varchar,
+    remote_status  varchar,
+    local_accrual  decimal(32, 2),
+    remote_accrual decimal(32, 2),
+    operation_sum  decimal(32, 2),
+    corrected      boolean not null default false
+);
+
+create index if not exists idx_reconciliation_discrepancies_deleted_at
+    on reconciliation_discrepancies (deleted_at);
+
+create index if not exists idx_reconciliation_discrepancies_run_id
+    on reconciliation_discrepancies (run_id);
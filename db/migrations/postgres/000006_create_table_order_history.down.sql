@@ -0,0 +1,11 @@
+drop index if exists idx_accrual_attempts_order_number;
+
+drop index if exists idx_accrual_attempts_deleted_at;
+
+drop table if exists accrual_attempts;
+
+drop index if exists idx_order_status_histories_order_id;
+
+drop index if exists idx_order_status_histories_deleted_at;
+
+drop table if exists order_status_histories;
@@ -0,0 +1,42 @@
+create table if not exists order_status_histories
+(
+    id           bigserial
+        primary key,
+    created_at   timestamp with time zone,
+    updated_at   timestamp with time zone,
+    deleted_at   timestamp with time zone,
+    order_id     bigint not null,
+    order_number varchar not null,
+    status       varchar not null,
+    accrual      decimal(32, 2)
+);
+
+create index if not exists idx_order_status_histories_deleted_at
+    on order_status_histories (deleted_at);
+
+create index if not exists idx_order_status_histories_order_id
+    on order_status_histories (order_id);
+
+create table if not exists accrual_attempts
+(
+    id           bigserial
+        primary key,
+    created_at   timestamp with time zone,
+    updated_at   timestamp with time zone,
+    deleted_at   timestamp with time zone,
+    order_number varchar not null,
+    status_code  integer not null default 0,
+    status       varchar,
+    error        varchar
+);
+
+create index if not exists idx_accrual_attempts_deleted_at
+    on accrual_attempts (deleted_at);
+
+create index if not exists idx_accrual_attempts_order_number
+    on accrual_attempts (order_number);
+
+insert into order_status_histories (created_at, updated_at, order_id, order_number, status, accrual)
+select orders.created_at, orders.created_at, orders.id, orders.number, orders.status, orders.accrual
+from orders
+where orders.deleted_at is null;
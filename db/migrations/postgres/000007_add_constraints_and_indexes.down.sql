@@ -0,0 +1,37 @@
+alter table reconciliation_discrepancies
+    drop constraint if exists fk_reconciliation_discrepancies_run;
+
+alter table order_status_histories
+    drop constraint if exists fk_order_status_histories_order;
+
+drop index if exists idx_operations_order_number;
+
+drop index if exists idx_operations_recipient_account_id;
+
+drop index if exists idx_operations_sender_account_id_type_processed_at;
+
+alter table operations
+    drop constraint if exists chk_operations_type,
+    drop constraint if exists fk_operations_recipient_account,
+    drop constraint if exists fk_operations_sender_account,
+    alter column recipient_account_id drop not null,
+    alter column sender_account_id drop not null,
+    alter column type drop not null;
+
+drop index if exists idx_accounts_user_id_type;
+
+alter table accounts
+    drop constraint if exists chk_accounts_type,
+    drop constraint if exists fk_accounts_user,
+    alter column type drop not null;
+
+drop index if exists idx_orders_user_id_created_at;
+
+drop index if exists idx_orders_status_created_at;
+
+alter table orders
+    drop constraint if exists chk_orders_status,
+    drop constraint if exists fk_orders_user,
+    drop constraint if exists uni_orders_number,
+    alter column user_id drop not null,
+    alter column number drop not null;
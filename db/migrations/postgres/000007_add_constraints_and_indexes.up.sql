@@ -0,0 +1,78 @@
+-- Дубли номеров заказов остались от гонки между проверкой и вставкой в CreateOrder. Это загрузки
+-- пользователей, иногда разных, поэтому миграция их не удаляет: до неё дубли разбирает
+-- db/repair/postgres/deduplicate_orders.sql, после чего миграция запускается повторно
+do
+$$
+    declare
+        duplicates text;
+    begin
+        select string_agg(number, ', ' order by number)
+        into duplicates
+        from (select number from orders group by number having count(*) > 1) duplicate;
+
+        if duplicates is not null then
+            raise exception 'orders contain duplicate numbers: %', duplicates
+                using hint = 'resolve them with db/repair/postgres/deduplicate_orders.sql and run the migration again';
+        end if;
+    end
+$$;
+
+alter table orders
+    alter column number set not null,
+    alter column user_id set not null,
+    add constraint uni_orders_number
+        unique (number),
+    add constraint fk_orders_user
+        foreign key (user_id) references users (id),
+    add constraint chk_orders_status
+        check (status in ('NEW', 'PROCESSING', 'INVALID', 'PROCESSED'));
+
+-- GetOrdersForProcess, CountByStatus
+create index if not exists idx_orders_status_created_at
+    on orders (status, created_at);
+
+-- GetOrdersByUserID, GetOrdersPage
+create index if not exists idx_orders_user_id_created_at
+    on orders (user_id, created_at, id);
+
+alter table accounts
+    alter column type set not null,
+    add constraint fk_accounts_user
+        foreign key (user_id) references users (id),
+    add constraint chk_accounts_type
+        check (type in ('system_withdraw', 'free', 'bonus'));
+
+-- FindByUserID
+create index if not exists idx_accounts_user_id_type
+    on accounts (user_id, type);
+
+alter table operations
+    alter column type set not null,
+    alter column sender_account_id set not null,
+    alter column recipient_account_id set not null,
+    add constraint fk_operations_sender_account
+        foreign key (sender_account_id) references accounts (id),
+    add constraint fk_operations_recipient_account
+        foreign key (recipient_account_id) references accounts (id),
+    add constraint chk_operations_type
+        check (type in ('accrual', 'withdraw'));
+
+-- GetWithdrawalsByAccountID, GetWithdrawalsPage, GetWithdrawnByAccountID
+create index if not exists idx_operations_sender_account_id_type_processed_at
+    on operations (sender_account_id, type, processed_at, id);
+
+-- GetOperationsPage
+create index if not exists idx_operations_recipient_account_id
+    on operations (recipient_account_id);
+
+-- FindAccrualByOrderNumber, GetOperationsByOrderNumber
+create index if not exists idx_operations_order_number
+    on operations (order_number);
+
+alter table order_status_histories
+    add constraint fk_order_status_histories_order
+        foreign key (order_id) references orders (id);
+
+alter table reconciliation_discrepancies
+    add constraint fk_reconciliation_discrepancies_run
+        foreign key (run_id) references reconciliation_runs (id);
@@ -0,0 +1,68 @@
+create table if not exists reward_rules
+(
+    id          bigserial
+        primary key,
+    created_at  timestamp with time zone,
+    updated_at  timestamp with time zone,
+    deleted_at  timestamp with time zone,
+    name        varchar not null,
+    mechanic    varchar not null
+        constraint chk_reward_rules_mechanic
+            check (mechanic in ('percent', 'points')),
+    value       decimal(32, 2) not null
+        constraint chk_reward_rules_value
+            check (value >= 0),
+    sku_pattern varchar not null default '',
+    category    varchar not null default '',
+    valid_from  timestamp with time zone,
+    valid_to    timestamp with time zone,
+    order_cap   decimal(32, 2),
+    priority    integer not null default 0,
+    active      boolean not null default true
+);
+
+create index if not exists idx_reward_rules_deleted_at
+    on reward_rules (deleted_at);
+
+create table if not exists order_baskets
+(
+    id           bigserial
+        primary key,
+    created_at   timestamp with time zone,
+    updated_at   timestamp with time zone,
+    deleted_at   timestamp with time zone,
+    order_number varchar not null
+        constraint uni_order_baskets_order_number
+            unique,
+    purchased_at timestamp with time zone not null
+);
+
+create index if not exists idx_order_baskets_deleted_at
+    on order_baskets (deleted_at);
+
+create table if not exists order_basket_items
+(
+    id         bigserial
+        primary key,
+    created_at timestamp with time zone,
+    updated_at timestamp with time zone,
+    deleted_at timestamp with time zone,
+    basket_id  bigint not null
+        constraint fk_order_basket_items_basket
+            references order_baskets (id),
+    sku        varchar not null,
+    category   varchar not null default '',
+    price      decimal(32, 2) not null
+        constraint chk_order_basket_items_price
+            check (price >= 0),
+    quantity   integer not null
+        constraint chk_order_basket_items_quantity
+            check (quantity > 0)
+);
+
+create index if not exists idx_order_basket_items_deleted_at
+    on order_basket_items (deleted_at);
+
+-- FindBasket
+create index if not exists idx_order_basket_items_basket_id
+    on order_basket_items (basket_id);
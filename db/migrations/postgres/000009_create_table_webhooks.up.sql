@@ -0,0 +1,56 @@
+create table if not exists webhooks
+(
+    id         bigserial
+        primary key,
+    created_at timestamp with time zone,
+    updated_at timestamp with time zone,
+    deleted_at timestamp with time zone,
+    user_id    bigint
+        constraint fk_webhooks_user
+            references users (id),
+    url        varchar not null,
+    secret     varchar not null,
+    events     text    not null
+);
+
+create index if not exists idx_webhooks_deleted_at
+    on webhooks (deleted_at);
+
+-- GetWebhooks, GetWebhooksForUser
+create index if not exists idx_webhooks_user_id
+    on webhooks (user_id);
+
+create table if not exists webhook_deliveries
+(
+    id               bigserial
+        primary key,
+    created_at       timestamp with time zone,
+    updated_at       timestamp with time zone,
+    deleted_at       timestamp with time zone,
+    webhook_id       bigint not null
+        constraint fk_webhook_deliveries_webhook
+            references webhooks (id),
+    event_id         varchar not null,
+    event_type       varchar not null,
+    payload          text    not null,
+    status           varchar not null default 'pending'
+        constraint chk_webhook_deliveries_status
+            check (status in ('pending', 'delivered', 'failed')),
+    attempts         integer not null default 0,
+    next_attempt_at  timestamp with time zone not null,
+    last_status_code integer not null default 0,
+    last_error       varchar not null default '',
+    delivered_at     timestamp with time zone
+);
+
+create index if not exists idx_webhook_deliveries_deleted_at
+    on webhook_deliveries (deleted_at);
+
+-- ClaimDeliveries
+create index if not exists idx_webhook_deliveries_due
+    on webhook_deliveries (next_attempt_at)
+    where status = 'pending';
+
+-- GetDeliveries
+create index if not exists idx_webhook_deliveries_webhook_id
+    on webhook_deliveries (webhook_id, id);
@@ -0,0 +1,23 @@
+create table if not exists outbox_events
+(
+    id              bigserial
+        primary key,
+    created_at      timestamp with time zone not null,
+    aggregate_type  varchar not null,
+    aggregate_id    varchar not null,
+    event_type      varchar not null,
+    payload         text    not null,
+    attempts        integer not null default 0,
+    next_attempt_at timestamp with time zone not null,
+    last_error      varchar not null default '',
+    published_at    timestamp with time zone
+);
+
+-- ClaimEvents: первое неопубликованное событие каждого агрегата
+create index if not exists idx_outbox_events_aggregate
+    on outbox_events (aggregate_type, aggregate_id, id)
+    where published_at is null;
+
+-- DeletePublished
+create index if not exists idx_outbox_events_published_at
+    on outbox_events (published_at);
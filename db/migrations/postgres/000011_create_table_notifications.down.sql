@@ -0,0 +1,8 @@
+drop table if exists notification_deliveries;
+
+drop table if exists notifications;
+
+alter table users
+    drop column if exists notify_webhook_secret,
+    drop column if exists notify_webhook_url,
+    drop column if exists notify_email;
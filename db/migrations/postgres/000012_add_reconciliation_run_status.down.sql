@@ -0,0 +1,2 @@
+alter table reconciliation_runs
+    drop column if exists status;
@@ -0,0 +1,7 @@
+-- прерванная сверка остаётся в статусе running или failed, законченные ранее сверки считаются завершёнными
+alter table reconciliation_runs
+    add column if not exists status varchar not null default 'running';
+
+update reconciliation_runs
+set status = 'completed'
+where finished_at is not null;
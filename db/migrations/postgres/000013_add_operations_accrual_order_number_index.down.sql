@@ -0,0 +1 @@
+drop index if exists uni_operations_accrual_order_number;
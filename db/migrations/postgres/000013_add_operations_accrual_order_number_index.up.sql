@@ -0,0 +1,5 @@
+-- не больше одного начисления за заказ. Если повторные начисления уже есть, миграция падает:
+-- их нужно разобрать вручную, баллы по ним уже на счетах
+create unique index if not exists uni_operations_accrual_order_number
+    on operations (order_number)
+    where type = 'accrual';
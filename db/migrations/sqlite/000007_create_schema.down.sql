@@ -0,0 +1,15 @@
+drop table if exists accrual_attempts;
+
+drop table if exists order_status_histories;
+
+drop table if exists reconciliation_discrepancies;
+
+drop table if exists reconciliation_runs;
+
+drop table if exists operations;
+
+drop table if exists accounts;
+
+drop table if exists orders;
+
+drop table if exists users;
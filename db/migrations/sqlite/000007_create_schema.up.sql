@@ -0,0 +1,200 @@
+-- SQLite появился, когда схема Postgres была на версии 7, поэтому схема создаётся сразу целиком
+-- под тем же номером: версии обеих схем совпадают и дальше растут вместе.
+-- Ограничения объявляются в таблицах: SQLite не умеет добавлять их через alter table.
+-- Время хранится текстом в UTC, поэтому сравнение строк совпадает со сравнением времени
+create table if not exists users
+(
+    id          integer
+        primary key autoincrement,
+    created_at  datetime,
+    updated_at  datetime,
+    deleted_at  datetime,
+    last_name   varchar,
+    first_name  varchar,
+    middle_name varchar,
+    login       varchar not null
+        constraint uni_users_login
+            unique,
+    password    varchar not null,
+    email       varchar
+);
+
+create index if not exists idx_users_deleted_at
+    on users (deleted_at);
+
+create table if not exists orders
+(
+    id         integer
+        primary key autoincrement,
+    created_at datetime,
+    updated_at datetime,
+    deleted_at datetime,
+    number     varchar not null
+        constraint uni_orders_number
+            unique,
+    user_id    integer not null
+        constraint fk_orders_user
+            references users (id),
+    status     varchar not null default 'NEW'
+        constraint chk_orders_status
+            check (status in ('NEW', 'PROCESSING', 'INVALID', 'PROCESSED')),
+    accrual    decimal(32, 2)
+);
+
+create index if not exists idx_orders_deleted_at
+    on orders (deleted_at);
+
+-- GetOrdersForProcess, CountByStatus
+create index if not exists idx_orders_status_created_at
+    on orders (status, created_at);
+
+-- GetOrdersByUserID, GetOrdersPage
+create index if not exists idx_orders_user_id_created_at
+    on orders (user_id, created_at, id);
+
+create table if not exists accounts
+(
+    id         integer
+        primary key autoincrement,
+    created_at datetime,
+    updated_at datetime,
+    deleted_at datetime,
+    type       varchar not null
+        constraint chk_accounts_type
+            check (type in ('system_withdraw', 'free', 'bonus')),
+    sum        decimal(32, 2) not null default 0,
+    user_id    integer
+        constraint fk_accounts_user
+            references users (id)
+);
+
+create index if not exists idx_accounts_deleted_at
+    on accounts (deleted_at);
+
+-- FindByUserID
+create index if not exists idx_accounts_user_id_type
+    on accounts (user_id, type);
+
+insert into accounts (created_at, updated_at, type)
+values (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now'), strftime('%Y-%m-%d %H:%M:%f+00:00', 'now'), 'system_withdraw');
+
+create table if not exists operations
+(
+    id                   integer
+        primary key autoincrement,
+    created_at           datetime,
+    updated_at           datetime,
+    deleted_at           datetime,
+    processed_at         datetime,
+    type                 varchar not null
+        constraint chk_operations_type
+            check (type in ('accrual', 'withdraw')),
+    order_number         varchar,
+    sum                  decimal(32, 2),
+    sender_account_id    integer not null
+        constraint fk_operations_sender_account
+            references accounts (id),
+    recipient_account_id integer not null
+        constraint fk_operations_recipient_account
+            references accounts (id)
+);
+
+create index if not exists idx_operations_deleted_at
+    on operations (deleted_at);
+
+-- GetWithdrawalsByAccountID, GetWithdrawalsPage, GetWithdrawnByAccountID
+create index if not exists idx_operations_sender_account_id_type_processed_at
+    on operations (sender_account_id, type, processed_at, id);
+
+-- GetOperationsPage
+create index if not exists idx_operations_recipient_account_id
+    on operations (recipient_account_id);
+
+-- FindAccrualByOrderNumber, GetOperationsByOrderNumber
+create index if not exists idx_operations_order_number
+    on operations (order_number);
+
+create table if not exists reconciliation_runs
+(
+    id             integer
+        primary key autoincrement,
+    created_at     datetime,
+    updated_at     datetime,
+    deleted_at     datetime,
+    period_from    datetime not null,
+    period_to      datetime not null,
+    auto_correct   boolean not null default false,
+    orders_checked integer not null default 0,
+    discrepancies  integer not null default 0,
+    corrected      integer not null default 0,
+    finished_at    datetime
+);
+
+create index if not exists idx_reconciliation_runs_deleted_at
+    on reconciliation_runs (deleted_at);
+
+create table if not exists reconciliation_discrepancies
+(
+    id             integer
+        primary key autoincrement,
+    created_at     datetime,
+    updated_at     datetime,
+    deleted_at     datetime,
+    run_id         integer not null
+        constraint fk_reconciliation_discrepancies_run
+            references reconciliation_runs (id),
+    order_number   varchar not null,
+    type           varchar not null,
+    local_status   varchar,
+    remote_status  varchar,
+    local_accrual  decimal(32, 2),
+    remote_accrual decimal(32, 2),
+    operation_sum  decimal(32, 2),
+    corrected      boolean not null default false
+);
+
+create index if not exists idx_reconciliation_discrepancies_deleted_at
+    on reconciliation_discrepancies (deleted_at);
+
+create index if not exists idx_reconciliation_discrepancies_run_id
+    on reconciliation_discrepancies (run_id);
+
+create table if not exists order_status_histories
+(
+    id           integer
+        primary key autoincrement,
+    created_at   datetime,
+    updated_at   datetime,
+    deleted_at   datetime,
+    order_id     integer not null
+        constraint fk_order_status_histories_order
+            references orders (id),
+    order_number varchar not null,
+    status       varchar not null,
+    accrual      decimal(32, 2)
+);
+
+create index if not exists idx_order_status_histories_deleted_at
+    on order_status_histories (deleted_at);
+
+create index if not exists idx_order_status_histories_order_id
+    on order_status_histories (order_id);
+
+create table if not exists accrual_attempts
+(
+    id           integer
+        primary key autoincrement,
+    created_at   datetime,
+    updated_at   datetime,
+    deleted_at   datetime,
+    order_number varchar not null,
+    status_code  integer not null default 0,
+    status       varchar,
+    error        varchar
+);
+
+create index if not exists idx_accrual_attempts_deleted_at
+    on accrual_attempts (deleted_at);
+
+create index if not exists idx_accrual_attempts_order_number
+    on accrual_attempts (order_number);
@@ -0,0 +1,5 @@
+drop table if exists order_basket_items;
+
+drop table if exists order_baskets;
+
+drop table if exists reward_rules;
@@ -0,0 +1,12 @@
+drop table if exists notification_deliveries;
+
+drop table if exists notifications;
+
+alter table users
+    drop column notify_webhook_secret;
+
+alter table users
+    drop column notify_webhook_url;
+
+alter table users
+    drop column notify_email;
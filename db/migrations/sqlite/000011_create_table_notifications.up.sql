@@ -0,0 +1,68 @@
+-- настройки уведомлений хранятся на пользователе, уведомления в приложении приходят всегда
+alter table users
+    add column notify_email boolean not null default false;
+
+alter table users
+    add column notify_webhook_url varchar not null default '';
+
+alter table users
+    add column notify_webhook_secret varchar not null default '';
+
+create table if not exists notifications
+(
+    id           integer
+        primary key autoincrement,
+    created_at   datetime,
+    updated_at   datetime,
+    deleted_at   datetime,
+    user_id      integer not null
+        constraint fk_notifications_user
+            references users (id),
+    type         varchar not null,
+    order_number varchar not null default '',
+    subject      varchar not null,
+    body         text    not null,
+    read_at      datetime
+);
+
+create index if not exists idx_notifications_deleted_at
+    on notifications (deleted_at);
+
+-- GetNotifications
+create index if not exists idx_notifications_user_id
+    on notifications (user_id, id);
+
+-- CountUnread, MarkAllRead
+create index if not exists idx_notifications_unread
+    on notifications (user_id)
+    where read_at is null;
+
+create table if not exists notification_deliveries
+(
+    id              integer
+        primary key autoincrement,
+    created_at      datetime,
+    updated_at      datetime,
+    deleted_at      datetime,
+    notification_id integer not null
+        constraint fk_notification_deliveries_notification
+            references notifications (id),
+    channel         varchar not null
+        constraint chk_notification_deliveries_channel
+            check (channel in ('email', 'webhook')),
+    status          varchar not null default 'pending'
+        constraint chk_notification_deliveries_status
+            check (status in ('pending', 'delivered', 'failed')),
+    attempts        integer not null default 0,
+    next_attempt_at datetime not null,
+    last_error      varchar not null default '',
+    delivered_at    datetime
+);
+
+create index if not exists idx_notification_deliveries_deleted_at
+    on notification_deliveries (deleted_at);
+
+-- ClaimDeliveries
+create index if not exists idx_notification_deliveries_due
+    on notification_deliveries (next_attempt_at)
+    where status = 'pending';
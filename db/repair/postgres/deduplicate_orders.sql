@@ -0,0 +1,85 @@
+-- Разбор дублей номеров заказов перед миграцией 000007, которая добавляет uni_orders_number.
+-- Запускается вручную и только после резервной копии базы:
+--
+--   gophermart migrate force 6
+--   psql "$DATABASE_URI" -v ON_ERROR_STOP=1 -f db/repair/postgres/deduplicate_orders.sql
+--   gophermart migrate up
+--
+-- Остаётся первая загрузка номера: её находил FindByNumber, по ней шли расчёт и начисления.
+-- Остальные загрузки и их история статусов не удаляются бесследно, а переносятся в таблицы
+-- repair_duplicate_orders и repair_duplicate_order_status_histories.
+--
+-- Операции и обращения к системе расчёта ссылаются на номер, а не на строку заказа, поэтому остаются
+-- за первой загрузкой. Баллы лежат на счетах и при удалении дублей не меняются, но операции по номеру
+-- на счёт не владельца первой загрузки скрипт выводит отдельным отчётом: их нужно разобрать вручную.
+begin;
+
+create table if not exists repair_duplicate_orders
+(
+    like orders,
+    kept_order_id bigint                   not null,
+    repaired_at   timestamp with time zone not null default now()
+);
+
+create table if not exists repair_duplicate_order_status_histories
+(
+    like order_status_histories,
+    repaired_at timestamp with time zone not null default now()
+);
+
+create temporary table duplicate_orders on commit drop as
+select orders.id, kept.id as kept_order_id
+from orders
+         join lateral (select first.id
+                       from orders first
+                       where first.number = orders.number
+                       order by first.id
+                       limit 1) kept on kept.id <> orders.id;
+
+-- Загрузки, которые будут удалены; other_user — дубль загружен не владельцем первой загрузки
+select orders.number,
+       orders.id                                 as duplicate_order_id,
+       orders.user_id                            as duplicate_user_id,
+       kept.id                                   as kept_order_id,
+       kept.user_id                              as kept_user_id,
+       orders.user_id is distinct from kept.user_id as other_user
+from duplicate_orders
+         join orders on orders.id = duplicate_orders.id
+         join orders kept on kept.id = duplicate_orders.kept_order_id
+order by orders.number, orders.id;
+
+-- Операции по номеру с дублем на счета не владельца первой загрузки: проверить вручную
+select operations.id as operation_id,
+       operations.order_number,
+       operations.type,
+       operations.sum,
+       accounts.user_id as operation_user_id,
+       kept.user_id     as kept_user_id
+from operations
+         join accounts on accounts.id = case operations.type
+                                            when 'accrual' then operations.recipient_account_id
+                                            else operations.sender_account_id end
+         join orders kept on kept.number = operations.order_number
+    and kept.id in (select kept_order_id from duplicate_orders)
+where accounts.user_id is distinct from kept.user_id
+order by operations.order_number, operations.id;
+
+insert into repair_duplicate_orders
+select orders.*, duplicate_orders.kept_order_id
+from orders
+         join duplicate_orders on duplicate_orders.id = orders.id;
+
+insert into repair_duplicate_order_status_histories
+select order_status_histories.*
+from order_status_histories
+where order_id in (select id from duplicate_orders);
+
+delete
+from order_status_histories
+where order_id in (select id from duplicate_orders);
+
+delete
+from orders
+where id in (select id from duplicate_orders);
+
+commit;
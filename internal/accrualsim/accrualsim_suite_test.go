@@ -0,0 +1,13 @@
+package accrualsim_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestAccrualSim(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Accrual Simulator Suite")
+}
@@ -0,0 +1,152 @@
+// Package accrualsim Имитатор системы расчёта начислений для разработки и интеграционных тестов.
+// Отвечает на GET /api/orders/{number} как настоящая система и воспроизводит ответы 204, 429 и 500
+// по правилам из конфигурации или по сценарию
+package accrualsim
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// RewardTypePercent вознаграждение в процентах от цены товара
+	RewardTypePercent = "%"
+	// RewardTypePoints вознаграждение фиксированным числом баллов за товар
+	RewardTypePoints = "pt"
+)
+
+// StatusRegistered заказ зарегистрирован, расчёт ещё не начат. В gophermart такого статуса нет
+const StatusRegistered entities.OrderStatus = "REGISTERED"
+
+// Config Правила имитатора. Нулевые значения отключают соответствующее поведение
+type Config struct {
+	// Rewards правила вознаграждения за товары, применяется первое подходящее
+	Rewards []Reward `yaml:"rewards"`
+	// Orders заказы, зарегистрированные при запуске
+	Orders []Order `yaml:"orders"`
+	// RegisterUnknown незнакомый номер регистрируется при первом запросе с товарами DefaultGoods,
+	// иначе на него отвечает 204
+	RegisterUnknown bool   `yaml:"register_unknown"`
+	DefaultGoods    []Good `yaml:"default_goods"`
+	// ProcessingDelay время от регистрации заказа до окончания расчёта.
+	// Первую половину заказ в статусе REGISTERED, вторую в PROCESSING
+	ProcessingDelay time.Duration `yaml:"processing_delay"`
+	// Latency задержка каждого ответа
+	Latency time.Duration `yaml:"latency"`
+	// RateLimit запросов в минуту, сверх лимита ответ 429 с Retry-After до начала следующей минуты
+	RateLimit int `yaml:"rate_limit"`
+	// ErrorRate доля ответов 500 от 0 до 1. Заказы из сценария не затрагивает
+	ErrorRate float64 `yaml:"error_rate"`
+	// Seed начальное значение генератора для ErrorRate, чтобы ошибки повторялись от запуска к запуску
+	Seed int64 `yaml:"seed"`
+	// Scenario ответы по номеру заказа в порядке запросов, последний шаг повторяется
+	Scenario map[string][]Step `yaml:"scenario"`
+}
+
+// Reward Вознаграждение за товары, в описании которых встречается Match (без учёта регистра)
+type Reward struct {
+	Match      string  `yaml:"match" json:"match"`
+	Reward     float64 `yaml:"reward" json:"reward"`
+	RewardType string  `yaml:"reward_type" json:"reward_type"`
+}
+
+type Good struct {
+	Description string  `yaml:"description" json:"description"`
+	Price       float64 `yaml:"price" json:"price"`
+}
+
+type Order struct {
+	Number string `yaml:"number" json:"order"`
+	Goods  []Good `yaml:"goods" json:"goods"`
+}
+
+// Step Один ответ сценария. Без Code отвечает 200 со статусом Status
+type Step struct {
+	Code    int                  `yaml:"code"`
+	Status  entities.OrderStatus `yaml:"status"`
+	Accrual *float64             `yaml:"accrual"`
+	// RetryAfter заголовок Retry-After для ответа 429, по умолчанию минута
+	RetryAfter time.Duration `yaml:"retry_after"`
+	// Delay задержка ответа вдобавок к Latency
+	Delay time.Duration `yaml:"delay"`
+}
+
+// LoadConfig Конфигурация из YAML файла path
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	conf := &Config{}
+	if err = yaml.Unmarshal(data, conf); err != nil {
+		return nil, fmt.Errorf("cannot parse %s: %w", path, err)
+	}
+
+	return conf, nil
+}
+
+// Validate Проверяет все правила сразу и возвращает все найденные ошибки
+func (c *Config) Validate() error {
+	var errs []error
+
+	for i, reward := range c.Rewards {
+		if err := reward.validate(); err != nil {
+			errs = append(errs, fmt.Errorf("rewards[%d]: %w", i, err))
+		}
+	}
+	if c.RateLimit < 0 {
+		errs = append(errs, errors.New("rate_limit: must not be negative"))
+	}
+	if c.ErrorRate < 0 || c.ErrorRate > 1 {
+		errs = append(errs, errors.New("error_rate: must be between 0 and 1"))
+	}
+	for number, steps := range c.Scenario {
+		if len(steps) == 0 {
+			errs = append(errs, fmt.Errorf("scenario[%s]: must have at least one step", number))
+		}
+		for i, step := range steps {
+			if err := step.validate(); err != nil {
+				errs = append(errs, fmt.Errorf("scenario[%s][%d]: %w", number, i, err))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (r Reward) validate() error {
+	if r.Match == "" {
+		return errors.New("match is required")
+	}
+	if r.RewardType != RewardTypePercent && r.RewardType != RewardTypePoints {
+		return fmt.Errorf("unknown reward_type %q, want %s or %s", r.RewardType, RewardTypePercent, RewardTypePoints)
+	}
+	if r.Reward < 0 {
+		return errors.New("reward must not be negative")
+	}
+
+	return nil
+}
+
+func (s Step) validate() error {
+	switch s.Code {
+	case 0, http.StatusOK:
+		switch s.Status {
+		case StatusRegistered, entities.OrderStatusProcessing, entities.OrderStatusInvalid, entities.OrderStatusProcessed:
+			return nil
+		default:
+			return fmt.Errorf("unknown status %q", s.Status)
+		}
+	case http.StatusNoContent, http.StatusTooManyRequests, http.StatusInternalServerError:
+		return nil
+	default:
+		return fmt.Errorf("unsupported code %d, want 200, 204, 429 or 500", s.Code)
+	}
+}
@@ -0,0 +1,11 @@
+package accrualsim
+
+import "time"
+
+// SetNow Подмена часов имитатора: задержка расчёта и окно лимита запросов считаются от now
+func (s *Server) SetNow(now func() time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.now = now
+}
@@ -0,0 +1,267 @@
+package accrualsim
+
+import (
+	"encoding/json"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+	"github.com/ShukinDmitriy/gophermart/internal/logging"
+	"github.com/theplant/luhn"
+	"go.uber.org/zap"
+)
+
+// defaultRetryAfter Retry-After для шага сценария с кодом 429, если он не задан
+const defaultRetryAfter = time.Minute
+
+// Server HTTP API системы расчёта:
+//   - GET /api/orders/{number} информация о расчёте начислений по заказу;
+//   - POST /api/orders регистрация заказа с товарами;
+//   - POST /api/goods новое правило вознаграждения.
+type Server struct {
+	conf   Config
+	mux    *http.ServeMux
+	logger *zap.Logger
+	// now текущее время, в тестах подменяется
+	now func() time.Time
+
+	mu          sync.Mutex
+	rewards     []Reward
+	orders      map[string]*registeredOrder
+	scenario    map[string][]Step
+	random      *rand.Rand
+	windowStart time.Time
+	windowCount int
+}
+
+type registeredOrder struct {
+	goods        []Good
+	registeredAt time.Time
+}
+
+// orderResponse Ответ GET /api/orders/{number}. Без начисления поле accrual отсутствует
+type orderResponse struct {
+	Order   string               `json:"order"`
+	Status  entities.OrderStatus `json:"status"`
+	Accrual *float64             `json:"accrual,omitempty"`
+}
+
+// reply Ответ на запрос заказа, собранный под блокировкой и отправляемый после задержки
+type reply struct {
+	code       int
+	order      *orderResponse
+	retryAfter time.Duration
+	delay      time.Duration
+}
+
+// NewServer Имитатор с правилами conf. Конфигурация должна пройти Validate
+func NewServer(conf *Config, logger *zap.Logger) *Server {
+	s := &Server{
+		conf:     *conf,
+		mux:      http.NewServeMux(),
+		logger:   logger,
+		now:      time.Now,
+		rewards:  append([]Reward(nil), conf.Rewards...),
+		orders:   make(map[string]*registeredOrder, len(conf.Orders)),
+		scenario: make(map[string][]Step, len(conf.Scenario)),
+		random:   rand.New(rand.NewSource(conf.Seed)),
+	}
+
+	for _, order := range conf.Orders {
+		s.orders[order.Number] = &registeredOrder{goods: order.Goods, registeredAt: s.now()}
+	}
+	for number, steps := range conf.Scenario {
+		s.scenario[number] = steps
+	}
+
+	s.mux.HandleFunc("GET /api/orders/{number}", s.getOrder)
+	s.mux.HandleFunc("POST /api/orders", s.registerOrder)
+	s.mux.HandleFunc("POST /api/goods", s.registerReward)
+
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) getOrder(w http.ResponseWriter, r *http.Request) {
+	number := r.PathValue("number")
+	res := s.reply(number)
+
+	if delay := s.conf.Latency + res.delay; delay > 0 {
+		select {
+		case <-time.After(delay):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	s.logger.Debug("order requested", logging.OrderNumber(number), zap.Int("status", res.code))
+
+	switch res.code {
+	case http.StatusOK:
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(res.order); err != nil {
+			s.logger.Error("cannot write order response", zap.Error(err))
+		}
+	case http.StatusTooManyRequests:
+		w.Header().Set("Content-Type", "text/plain")
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(res.retryAfter.Seconds()))))
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write([]byte("No more than " + strconv.Itoa(s.conf.RateLimit) + " requests per minute allowed"))
+	default:
+		w.WriteHeader(res.code)
+	}
+}
+
+// reply Ответ по порядку проверок: лимит запросов, сценарий, случайная ошибка, расчёт по товарам
+func (s *Server) reply(number string) reply {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+
+	if s.conf.RateLimit > 0 {
+		window := now.Truncate(time.Minute)
+		if !window.Equal(s.windowStart) {
+			s.windowStart, s.windowCount = window, 0
+		}
+		s.windowCount++
+		if s.windowCount > s.conf.RateLimit {
+			return reply{code: http.StatusTooManyRequests, retryAfter: window.Add(time.Minute).Sub(now)}
+		}
+	}
+
+	if steps, ok := s.scenario[number]; ok {
+		if len(steps) > 1 {
+			s.scenario[number] = steps[1:]
+		}
+
+		return stepReply(number, steps[0])
+	}
+
+	if s.conf.ErrorRate > 0 && s.random.Float64() < s.conf.ErrorRate {
+		return reply{code: http.StatusInternalServerError}
+	}
+
+	order, ok := s.orders[number]
+	if !ok && s.conf.RegisterUnknown {
+		order = &registeredOrder{goods: s.conf.DefaultGoods, registeredAt: now}
+		s.orders[number] = order
+	}
+	if order == nil {
+		return reply{code: http.StatusNoContent}
+	}
+
+	res := &orderResponse{Order: number}
+	elapsed := now.Sub(order.registeredAt)
+	switch {
+	case !validNumber(number):
+		res.Status = entities.OrderStatusInvalid
+	case elapsed < s.conf.ProcessingDelay/2:
+		res.Status = StatusRegistered
+	case elapsed < s.conf.ProcessingDelay:
+		res.Status = entities.OrderStatusProcessing
+	default:
+		accrual := s.accrual(order.goods)
+		res.Status = entities.OrderStatusProcessed
+		res.Accrual = &accrual
+	}
+
+	return reply{code: http.StatusOK, order: res}
+}
+
+func stepReply(number string, step Step) reply {
+	res := reply{code: step.Code, delay: step.Delay}
+
+	switch step.Code {
+	case 0, http.StatusOK:
+		res.code = http.StatusOK
+		res.order = &orderResponse{Order: number, Status: step.Status, Accrual: step.Accrual}
+	case http.StatusTooManyRequests:
+		res.retryAfter = step.RetryAfter
+		if res.retryAfter == 0 {
+			res.retryAfter = defaultRetryAfter
+		}
+	}
+
+	return res
+}
+
+// accrual Сумма вознаграждений за товары: к каждому товару применяется первое подходящее правило
+func (s *Server) accrual(goods []Good) float64 {
+	var sum float64
+	for _, good := range goods {
+		description := strings.ToLower(good.Description)
+		for _, reward := range s.rewards {
+			if !strings.Contains(description, strings.ToLower(reward.Match)) {
+				continue
+			}
+
+			if reward.RewardType == RewardTypePercent {
+				sum += good.Price * reward.Reward / 100
+			} else {
+				sum += reward.Reward
+			}
+			break
+		}
+	}
+
+	return math.Round(sum*100) / 100
+}
+
+func (s *Server) registerOrder(w http.ResponseWriter, r *http.Request) {
+	var order Order
+	if err := json.NewDecoder(r.Body).Decode(&order); err != nil || order.Number == "" {
+		http.Error(w, "invalid order", http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.orders[order.Number]; ok {
+		http.Error(w, "order already registered", http.StatusConflict)
+		return
+	}
+	s.orders[order.Number] = &registeredOrder{goods: order.Goods, registeredAt: s.now()}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (s *Server) registerReward(w http.ResponseWriter, r *http.Request) {
+	var reward Reward
+	if err := json.NewDecoder(r.Body).Decode(&reward); err != nil {
+		http.Error(w, "invalid reward", http.StatusBadRequest)
+		return
+	}
+	if err := reward.validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, existing := range s.rewards {
+		if strings.EqualFold(existing.Match, reward.Match) {
+			http.Error(w, "reward already registered", http.StatusConflict)
+			return
+		}
+	}
+	s.rewards = append(s.rewards, reward)
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func validNumber(number string) bool {
+	value, err := strconv.Atoi(number)
+
+	return err == nil && luhn.Valid(value)
+}
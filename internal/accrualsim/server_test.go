@@ -0,0 +1,247 @@
+package accrualsim_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/ShukinDmitriy/gophermart/internal/accrualsim"
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+)
+
+var _ = Describe("Server", func() {
+	var conf *accrualsim.Config
+	var now time.Time
+
+	newServer := func() *accrualsim.Server {
+		Expect(conf.Validate()).To(Succeed())
+		server := accrualsim.NewServer(conf, zap.NewNop())
+		server.SetNow(func() time.Time { return now })
+
+		return server
+	}
+
+	do := func(server *accrualsim.Server, method string, target string, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, httptest.NewRequest(method, target, strings.NewReader(body)))
+
+		return rec
+	}
+
+	getOrder := func(server *accrualsim.Server, number string) (*httptest.ResponseRecorder, map[string]any) {
+		rec := do(server, http.MethodGet, "/api/orders/"+number, "")
+		if rec.Code != http.StatusOK {
+			return rec, nil
+		}
+
+		res := map[string]any{}
+		Expect(json.Unmarshal(rec.Body.Bytes(), &res)).To(Succeed())
+
+		return rec, res
+	}
+
+	BeforeEach(func() {
+		conf = &accrualsim.Config{
+			Rewards: []accrualsim.Reward{
+				{Match: "bork", Reward: 10, RewardType: accrualsim.RewardTypePercent},
+				{Match: "acer", Reward: 50, RewardType: accrualsim.RewardTypePoints},
+			},
+		}
+		now = time.Date(2024, 5, 1, 12, 0, 30, 0, time.UTC)
+	})
+
+	It("must answer 204 for an unregistered order", func() {
+		// Arrange
+		server := newServer()
+
+		// Act
+		rec, _ := getOrder(server, "12345678903")
+
+		// Assert
+		Expect(rec.Code).To(Equal(http.StatusNoContent))
+	})
+
+	It("must move a registered order through REGISTERED and PROCESSING to PROCESSED", func() {
+		// Arrange
+		conf.ProcessingDelay = 10 * time.Second
+		server := newServer()
+		rec := do(server, http.MethodPost, "/api/orders", `{"order":"12345678903","goods":[
+			{"description":"Чайник BORK","price":7000},
+			{"description":"Ноутбук Acer","price":50000},
+			{"description":"Кабель","price":300}]}`)
+		Expect(rec.Code).To(Equal(http.StatusAccepted))
+
+		// Act
+		_, registered := getOrder(server, "12345678903")
+		now = now.Add(6 * time.Second)
+		_, processing := getOrder(server, "12345678903")
+		now = now.Add(4 * time.Second)
+		_, processed := getOrder(server, "12345678903")
+
+		// Assert
+		Expect(registered).To(Equal(map[string]any{"order": "12345678903", "status": "REGISTERED"}))
+		Expect(processing).To(Equal(map[string]any{"order": "12345678903", "status": "PROCESSING"}))
+		Expect(processed).To(Equal(map[string]any{"order": "12345678903", "status": "PROCESSED", "accrual": 750.0}))
+	})
+
+	It("must mark an order with a wrong check digit as INVALID", func() {
+		// Arrange
+		conf.Orders = []accrualsim.Order{{Number: "12345678900"}}
+		server := newServer()
+
+		// Act
+		_, res := getOrder(server, "12345678900")
+
+		// Assert
+		Expect(res["status"]).To(Equal(string(entities.OrderStatusInvalid)))
+	})
+
+	It("must register unknown orders with the default goods", func() {
+		// Arrange
+		conf.RegisterUnknown = true
+		conf.DefaultGoods = []accrualsim.Good{{Description: "Утюг Bork", Price: 3000}}
+		server := newServer()
+
+		// Act
+		_, res := getOrder(server, "12345678903")
+
+		// Assert
+		Expect(res).To(Equal(map[string]any{"order": "12345678903", "status": "PROCESSED", "accrual": 300.0}))
+	})
+
+	It("must reject a duplicate order and reward", func() {
+		// Arrange
+		server := newServer()
+		Expect(do(server, http.MethodPost, "/api/orders", `{"order":"12345678903"}`).Code).To(Equal(http.StatusAccepted))
+
+		// Act
+		order := do(server, http.MethodPost, "/api/orders", `{"order":"12345678903"}`)
+		reward := do(server, http.MethodPost, "/api/goods", `{"match":"Bork","reward":5,"reward_type":"pt"}`)
+		invalid := do(server, http.MethodPost, "/api/goods", `{"match":"Dyson","reward":5,"reward_type":"usd"}`)
+
+		// Assert
+		Expect(order.Code).To(Equal(http.StatusConflict))
+		Expect(reward.Code).To(Equal(http.StatusConflict))
+		Expect(invalid.Code).To(Equal(http.StatusBadRequest))
+	})
+
+	It("must play the scenario in order and repeat the last step", func() {
+		// Arrange
+		accrual := 729.98
+		conf.Scenario = map[string][]accrualsim.Step{
+			"9278923470": {
+				{Code: http.StatusNoContent},
+				{Code: http.StatusTooManyRequests, RetryAfter: 2 * time.Second},
+				{Code: http.StatusInternalServerError},
+				{Status: entities.OrderStatusProcessed, Accrual: &accrual},
+			},
+		}
+		server := newServer()
+
+		// Act
+		noContent, _ := getOrder(server, "9278923470")
+		tooMany, _ := getOrder(server, "9278923470")
+		failed, _ := getOrder(server, "9278923470")
+		_, processed := getOrder(server, "9278923470")
+		_, repeated := getOrder(server, "9278923470")
+
+		// Assert
+		Expect(noContent.Code).To(Equal(http.StatusNoContent))
+		Expect(tooMany.Code).To(Equal(http.StatusTooManyRequests))
+		Expect(tooMany.Header().Get("Retry-After")).To(Equal("2"))
+		Expect(failed.Code).To(Equal(http.StatusInternalServerError))
+		Expect(processed).To(Equal(map[string]any{"order": "9278923470", "status": "PROCESSED", "accrual": accrual}))
+		Expect(repeated).To(Equal(processed))
+	})
+
+	It("must limit requests per minute and reset the limit at the next minute", func() {
+		// Arrange
+		conf.RateLimit = 2
+		server := newServer()
+
+		// Act
+		first, _ := getOrder(server, "12345678903")
+		second, _ := getOrder(server, "12345678903")
+		limited, _ := getOrder(server, "12345678903")
+		now = now.Add(30 * time.Second)
+		nextMinute, _ := getOrder(server, "12345678903")
+
+		// Assert
+		Expect(first.Code).To(Equal(http.StatusNoContent))
+		Expect(second.Code).To(Equal(http.StatusNoContent))
+		Expect(limited.Code).To(Equal(http.StatusTooManyRequests))
+		Expect(limited.Header().Get("Retry-After")).To(Equal("30"))
+		Expect(limited.Body.String()).To(Equal("No more than 2 requests per minute allowed"))
+		Expect(nextMinute.Code).To(Equal(http.StatusNoContent))
+	})
+
+	It("must inject the same errors for the same seed", func() {
+		// Arrange
+		conf.ErrorRate = 0.5
+		conf.Seed = 42
+		codes := func() []int {
+			server := newServer()
+			res := make([]int, 0, 20)
+			for i := 0; i < 20; i++ {
+				rec, _ := getOrder(server, "12345678903")
+				res = append(res, rec.Code)
+			}
+
+			return res
+		}
+
+		// Act
+		first := codes()
+		second := codes()
+
+		// Assert
+		Expect(first).To(Equal(second))
+		Expect(first).To(ContainElement(http.StatusInternalServerError))
+		Expect(first).To(ContainElement(http.StatusNoContent))
+	})
+
+	Describe("Config", func() {
+		It("must load the example configuration", func() {
+			// Act
+			loaded, err := accrualsim.LoadConfig("../../cmd/accrual-sim/config.example.yaml")
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(loaded.Validate()).To(Succeed())
+			Expect(loaded.ProcessingDelay).To(Equal(10 * time.Second))
+			Expect(loaded.Scenario["9278923470"]).To(HaveLen(5))
+			Expect(loaded.Scenario["9278923470"][1].RetryAfter).To(Equal(2 * time.Second))
+		})
+
+		It("must report every invalid rule", func() {
+			// Arrange
+			conf = &accrualsim.Config{
+				Rewards:   []accrualsim.Reward{{Match: "", RewardType: "usd"}},
+				RateLimit: -1,
+				ErrorRate: 2,
+				Scenario: map[string][]accrualsim.Step{
+					"9278923470": {{Code: http.StatusAccepted}, {Status: "DONE"}},
+					"2377225624": {},
+				},
+			}
+
+			// Act
+			err := conf.Validate()
+
+			// Assert
+			Expect(err).To(MatchError(And(
+				ContainSubstring("rewards[0]: match is required"),
+				ContainSubstring("rate_limit: must not be negative"),
+				ContainSubstring("error_rate: must be between 0 and 1"),
+				ContainSubstring("scenario[9278923470][0]: unsupported code 202"),
+				ContainSubstring(`scenario[9278923470][1]: unknown status "DONE"`),
+				ContainSubstring("scenario[2377225624]: must have at least one step"),
+			)))
+		})
+	})
+})
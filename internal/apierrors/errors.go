@@ -0,0 +1,65 @@
+package apierrors
+
+import (
+	"fmt"
+
+	"github.com/ShukinDmitriy/gophermart/internal/models"
+)
+
+// Стабильные коды ошибок API, общие для REST и gRPC. Клиенты опираются на них, поэтому существующие коды не меняются
+const (
+	ErrorCodeBadRequest              = "bad_request"
+	ErrorCodeValidationFailed        = "validation_failed"
+	ErrorCodeInvalidOrderNumber      = "invalid_order_number"
+	ErrorCodeInvalidCursor           = "invalid_cursor"
+	ErrorCodeUnauthorized            = "unauthorized"
+	ErrorCodeInvalidSignature        = "invalid_signature"
+	ErrorCodeInvalidCredentials      = "invalid_credentials"
+	ErrorCodeLoginAlreadyExists      = "login_already_exists"
+	ErrorCodeOrderOwnedByAnotherUser = "order_owned_by_another_user"
+	ErrorCodeInsufficientFunds       = "insufficient_funds"
+	ErrorCodeBasketAlreadyExists     = "basket_already_exists"
+	ErrorCodeChannelUnavailable      = "notification_channel_unavailable"
+	ErrorCodeURLNotAllowed           = "url_not_allowed"
+	ErrorCodeNotFound                = "not_found"
+	ErrorCodeMethodNotAllowed        = "method_not_allowed"
+	ErrorCodePayloadTooLarge         = "payload_too_large"
+	ErrorCodeUnsupportedMediaType    = "unsupported_media_type"
+	ErrorCodeTooManyRequests         = "too_many_requests"
+	ErrorCodeInternal                = "internal_error"
+)
+
+// APIError Ошибка API со стабильным кодом. Status — код ответа HTTP, gRPC сопоставляет свой статус по Code
+type APIError struct {
+	Status int
+	Code   string
+	Detail string
+	Errors models.ValidationError
+	Err    error
+}
+
+func NewAPIError(status int, code string, detail string) *APIError {
+	return &APIError{
+		Status: status,
+		Code:   code,
+		Detail: detail,
+	}
+}
+
+func (e *APIError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %s: %v", e.Code, e.Detail, e.Err)
+	}
+
+	return fmt.Sprintf("%s: %s", e.Code, e.Detail)
+}
+
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// WithCause Причина ошибки попадает только в лог, клиенту она не отдаётся
+func (e *APIError) WithCause(err error) *APIError {
+	e.Err = err
+	return e
+}
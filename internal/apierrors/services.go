@@ -0,0 +1,48 @@
+package apierrors
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/ShukinDmitriy/gophermart/internal/models"
+	"github.com/ShukinDmitriy/gophermart/internal/services"
+)
+
+// FromServiceError Переводит ошибку бизнес-правила из слоя сервисов в ошибку API.
+// Для ошибок, которые не относятся к бизнес-правилам, возвращает nil
+func FromServiceError(err error) *APIError {
+	switch {
+	case errors.Is(err, services.ErrValidation):
+		apiErr := NewAPIError(http.StatusBadRequest, ErrorCodeValidationFailed, "request validation failed")
+		apiErr.Errors = models.ExtractErrors(err)
+		return apiErr
+	case errors.Is(err, services.ErrInvalidCursor):
+		return NewAPIError(http.StatusBadRequest, ErrorCodeInvalidCursor, "invalid pagination cursor").WithCause(err)
+	case errors.Is(err, services.ErrLoginAlreadyExists):
+		return NewAPIError(http.StatusConflict, ErrorCodeLoginAlreadyExists, err.Error())
+	case errors.Is(err, services.ErrInvalidCredentials):
+		return NewAPIError(http.StatusUnauthorized, ErrorCodeInvalidCredentials, err.Error())
+	case errors.Is(err, services.ErrURLNotAllowed):
+		return NewAPIError(http.StatusBadRequest, ErrorCodeURLNotAllowed, err.Error())
+	case errors.Is(err, services.ErrInvalidOrderFormat):
+		return NewAPIError(http.StatusBadRequest, ErrorCodeBadRequest, err.Error())
+	case errors.Is(err, services.ErrInvalidOrderNumber):
+		return NewAPIError(http.StatusUnprocessableEntity, ErrorCodeInvalidOrderNumber, err.Error())
+	case errors.Is(err, services.ErrOrderNotFound),
+		errors.Is(err, services.ErrRewardRuleNotFound),
+		errors.Is(err, services.ErrWebhookNotFound),
+		errors.Is(err, services.ErrUserNotFound),
+		errors.Is(err, services.ErrNotificationNotFound):
+		return NewAPIError(http.StatusNotFound, ErrorCodeNotFound, err.Error())
+	case errors.Is(err, services.ErrOrderOwnedByOtherUser):
+		return NewAPIError(http.StatusConflict, ErrorCodeOrderOwnedByAnotherUser, err.Error())
+	case errors.Is(err, services.ErrInsufficientFunds):
+		return NewAPIError(http.StatusPaymentRequired, ErrorCodeInsufficientFunds, err.Error())
+	case errors.Is(err, services.ErrNotificationChannelUnavailable):
+		return NewAPIError(http.StatusUnprocessableEntity, ErrorCodeChannelUnavailable, err.Error())
+	case errors.Is(err, services.ErrBasketAlreadyExists):
+		return NewAPIError(http.StatusConflict, ErrorCodeBasketAlreadyExists, err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package apierrors
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/ShukinDmitriy/gophermart/internal/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromServiceError(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		code   string
+	}{
+		{
+			name:   "Wrapped insufficient funds",
+			err:    fmt.Errorf("withdraw: %w", services.ErrInsufficientFunds),
+			status: http.StatusPaymentRequired,
+			code:   ErrorCodeInsufficientFunds,
+		},
+		{
+			name:   "Order of another user",
+			err:    services.ErrOrderOwnedByOtherUser,
+			status: http.StatusConflict,
+			code:   ErrorCodeOrderOwnedByAnotherUser,
+		},
+		{
+			name:   "Webhook not found",
+			err:    services.ErrWebhookNotFound,
+			status: http.StatusNotFound,
+			code:   ErrorCodeNotFound,
+		},
+		{
+			name:   "Invalid order format",
+			err:    services.ErrInvalidOrderFormat,
+			status: http.StatusBadRequest,
+			code:   ErrorCodeBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apiErr := FromServiceError(tt.err)
+
+			if assert.NotNil(t, apiErr) {
+				assert.Equal(t, tt.status, apiErr.Status)
+				assert.Equal(t, tt.code, apiErr.Code)
+			}
+		})
+	}
+}
+
+func TestFromServiceError_Unknown(t *testing.T) {
+	assert.Nil(t, FromServiceError(errors.New("pq: connection refused")))
+}
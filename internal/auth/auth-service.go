@@ -1,17 +1,20 @@
 package auth
 
 import (
+	"errors"
 	"net/http"
-	"os"
 	"strconv"
 	"time"
 
+	"github.com/ShukinDmitriy/gophermart/internal/logging"
 	"github.com/ShukinDmitriy/gophermart/internal/models"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/labstack/echo/v4"
 	"go.uber.org/zap"
 )
 
+var ErrInvalidToken = errors.New("invalid token")
+
 const (
 	userTokenCookieName    = "user"
 	accessTokenCookieName  = "access-token"
@@ -23,21 +26,28 @@ type Claims struct {
 	jwt.RegisteredClaims
 }
 
-type AuthService struct {
-	authUser AuthUser
+// Tokens Токены для клиентов, которые передают их в заголовке Authorization, а не в cookie
+type Tokens struct {
+	AccessToken           string
+	AccessTokenExpiresAt  time.Time
+	RefreshToken          string
+	RefreshTokenExpiresAt time.Time
 }
 
-func NewAuthService(authUser AuthUser) *AuthService {
-	return &AuthService{authUser: authUser}
+type AuthService struct {
+	authUser        AuthUser
+	secret          []byte
+	accessTokenTTL  time.Duration
+	refreshTokenTTL time.Duration
 }
 
-func GetJWTSecret() string {
-	jwtSecretKey, exists := os.LookupEnv("JWT_SECRET_KEY")
-
-	if !exists {
-		panic("no JWT_SECRET_KEY in .env")
+func NewAuthService(authUser AuthUser, secret string, accessTokenTTL time.Duration, refreshTokenTTL time.Duration) *AuthService {
+	return &AuthService{
+		authUser:        authUser,
+		secret:          []byte(secret),
+		accessTokenTTL:  accessTokenTTL,
+		refreshTokenTTL: refreshTokenTTL,
 	}
-	return jwtSecretKey
 }
 
 func (authService *AuthService) GetAccessTokenCookieName() string {
@@ -96,6 +106,43 @@ func (authService *AuthService) GenerateTokensAndSetCookies(c echo.Context, user
 	return nil
 }
 
+// GenerateTokens Выпускает access и refresh токены пользователя
+func (authService *AuthService) GenerateTokens(user *models.UserInfoResponse) (*Tokens, error) {
+	_, accessTokenString, exp, err := authService.generateAccessToken(user)
+	if err != nil {
+		return nil, err
+	}
+
+	_, refreshTokenString, refreshExp, err := authService.generateRefreshToken(user)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Tokens{
+		AccessToken:           accessTokenString,
+		AccessTokenExpiresAt:  exp,
+		RefreshToken:          refreshTokenString,
+		RefreshTokenExpiresAt: refreshExp,
+	}, nil
+}
+
+// ParseAccessToken Проверяет подпись и срок действия токена и возвращает ID пользователя
+func (authService *AuthService) ParseAccessToken(tokenString string) (uint, error) {
+	claims := &Claims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		return authService.secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	if err != nil {
+		return 0, err
+	}
+
+	if claims.ID == 0 {
+		return 0, ErrInvalidToken
+	}
+
+	return claims.ID, nil
+}
+
 func (authService *AuthService) GetUserID(c echo.Context) uint {
 	if c.Get("user") == nil {
 		return 0
@@ -115,7 +162,7 @@ func (authService *AuthService) GetUserID(c echo.Context) uint {
 
 func (authService *AuthService) JWTErrorChecker(c echo.Context, err error) error {
 	if err != nil {
-		zap.L().Error(
+		logging.FromContext(c).Warn(
 			"JWTErrorChecker",
 			zap.Error(err),
 		)
@@ -125,15 +172,15 @@ func (authService *AuthService) JWTErrorChecker(c echo.Context, err error) error
 }
 
 func (authService *AuthService) generateAccessToken(user *models.UserInfoResponse) (*jwt.Token, string, time.Time, error) {
-	expirationTime := time.Now().Add(24 * time.Hour)
+	expirationTime := time.Now().Add(authService.accessTokenTTL)
 
-	return authService.generateToken(user, expirationTime, []byte(GetJWTSecret()))
+	return authService.generateToken(user, expirationTime, authService.secret)
 }
 
 func (authService *AuthService) generateRefreshToken(user *models.UserInfoResponse) (*jwt.Token, string, time.Time, error) {
-	expirationTime := time.Now().Add(30 * 24 * time.Hour)
+	expirationTime := time.Now().Add(authService.refreshTokenTTL)
 
-	return authService.generateToken(user, expirationTime, []byte(GetJWTSecret()))
+	return authService.generateToken(user, expirationTime, authService.secret)
 }
 
 func (authService *AuthService) generateToken(user *models.UserInfoResponse, expirationTime time.Time, secret []byte) (*jwt.Token, string, time.Time, error) {
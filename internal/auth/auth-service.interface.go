@@ -8,4 +8,6 @@ import (
 type AuthServiceInterface interface {
 	GetUserID(c echo.Context) uint
 	GenerateTokensAndSetCookies(c echo.Context, user *models.UserInfoResponse) error
+	GenerateTokens(user *models.UserInfoResponse) (*Tokens, error)
+	ParseAccessToken(tokenString string) (uint, error)
 }
@@ -4,29 +4,33 @@ import (
 	"errors"
 	"net/http"
 
+	"github.com/ShukinDmitriy/gophermart/internal/logging"
 	"github.com/ShukinDmitriy/gophermart/internal/models"
 	"github.com/ShukinDmitriy/gophermart/internal/repositories"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
 )
 
 type AuthUser struct {
 	userRepository repositories.UserRepositoryInterface
+	secret         []byte
 }
 
-func NewAuthUser(userRepository repositories.UserRepositoryInterface) *AuthUser {
+func NewAuthUser(userRepository repositories.UserRepositoryInterface, secret string) *AuthUser {
 	return &AuthUser{
 		userRepository: userRepository,
+		secret:         []byte(secret),
 	}
 }
 
 func (aUser *AuthUser) getUserIDByCookie(c echo.Context, cookie *http.Cookie) (*uint, error) {
 	claims := &Claims{}
 	_, err := jwt.ParseWithClaims(cookie.Value, claims, func(token *jwt.Token) (interface{}, error) {
-		return []byte(GetJWTSecret()), nil
+		return aUser.secret, nil
 	})
 	if errors.Is(err, jwt.ErrSignatureInvalid) {
-		c.Logger().Error(err)
+		logging.FromContext(c).Warn("invalid token signature", zap.Error(err))
 		return nil, err
 	}
 
@@ -36,7 +40,7 @@ func (aUser *AuthUser) getUserIDByCookie(c echo.Context, cookie *http.Cookie) (*
 func (aUser *AuthUser) getUserByID(c echo.Context, id uint) *models.UserInfoResponse {
 	user, err := aUser.userRepository.Find(id)
 	if err != nil {
-		c.Logger().Error(err)
+		logging.FromContext(c).Error("cannot find user", logging.UserID(id), zap.Error(err))
 		return nil
 	}
 
@@ -1,12 +1,201 @@
+// Package config Настройки приложения.
+//
+// Источники применяются по очереди, каждый следующий перекрывает предыдущий:
+//  1. значения по умолчанию (Default);
+//  2. YAML или TOML (расширение .toml) файл из флага -config или переменной CONFIG_FILE;
+//  3. переменные окружения (теги env, .env файл тоже попадает сюда);
+//  4. флаги командной строки, но только явно указанные.
 package config
 
+import (
+	"time"
+
+	"github.com/ShukinDmitriy/gophermart/internal/tracing"
+)
+
+const (
+	OrderEventsBrokerMemory   = "memory"
+	OrderEventsBrokerPostgres = "postgres"
+)
+
+const (
+	// OpenAPIValidationOff запросы и ответы не проверяются
+	OpenAPIValidationOff = "off"
+	// OpenAPIValidationRequests проверяются только входящие запросы
+	OpenAPIValidationRequests = "requests"
+	// OpenAPIValidationFull проверяются запросы и ответы, режим для тестов
+	OpenAPIValidationFull = "full"
+)
+
+const (
+	// StoragePostgres данные в Postgres
+	StoragePostgres = "postgres"
+	// StorageSQLite данные в файле SQLite, для одного экземпляра сервиса без Postgres
+	StorageSQLite = "sqlite"
+	// StorageMemory данные в памяти процесса и теряются при остановке, база не нужна
+	StorageMemory = "memory"
+)
+
+const (
+	// AccrualProviderExternal начисления рассчитывает внешняя система расчёта по ACCRUAL_SYSTEM_ADDRESS
+	AccrualProviderExternal = "external"
+	// AccrualProviderRules начисления рассчитываются локально по правилам вознаграждения и составам заказов
+	AccrualProviderRules = "rules"
+)
+
+const (
+	// EventsSinkLog доменные события пишутся в журнал приложения
+	EventsSinkLog = "log"
+	// EventsSinkNATS доменные события публикуются в NATS JetStream
+	EventsSinkNATS = "nats"
+	// EventsSinkKafka доменные события публикуются в топик Kafka
+	EventsSinkKafka = "kafka"
+)
+
+const (
+	// MigrationModeAuto миграции применяются при старте, включается явно и подходит для одного экземпляра
+	MigrationModeAuto = "auto"
+	// MigrationModeCheck при старте схема только проверяется, миграции запускает gophermart migrate up.
+	// Режим по умолчанию: несколько экземпляров не начнут менять схему одновременно
+	MigrationModeCheck = "check"
+)
+
 type Config struct {
-	RunAddress           string `env:"RUN_ADDRESS"`
-	DatabaseURI          string `env:"DATABASE_URI"`
-	AccrualSystemAddress string `env:"ACCRUAL_SYSTEM_ADDRESS"`
-	JwtSecretKey         string `env:"JWT_SECRET_KEY"`
+	RunAddress   string `yaml:"run_address" env:"RUN_ADDRESS"`
+	GRPCAddress  string `yaml:"grpc_address" env:"GRPC_ADDRESS"`
+	AdminAddress string `yaml:"admin_address" env:"ADMIN_ADDRESS"`
+	// AdminToken токен служебного API в заголовке Authorization: Bearer. Пустой отключает проверку,
+	// это допустимо только для служебного сервера на loopback
+	AdminToken string `yaml:"admin_token" env:"ADMIN_TOKEN"`
+	LogLevel   string `yaml:"log_level" env:"LOG_LEVEL"`
+
+	Storage              string        `yaml:"storage" env:"STORAGE"`
+	DatabaseURI          string        `yaml:"database_uri" env:"DATABASE_URI"`
+	DBMaxOpenConns       int           `yaml:"db_max_open_conns" env:"DB_MAX_OPEN_CONNS"`
+	DBMaxIdleConns       int           `yaml:"db_max_idle_conns" env:"DB_MAX_IDLE_CONNS"`
+	DBConnMaxLifetime    time.Duration `yaml:"db_conn_max_lifetime" env:"DB_CONN_MAX_LIFETIME"`
+	DBConnMaxIdleTime    time.Duration `yaml:"db_conn_max_idle_time" env:"DB_CONN_MAX_IDLE_TIME"`
+	DBSlowQueryThreshold time.Duration `yaml:"db_slow_query_threshold" env:"DB_SLOW_QUERY_THRESHOLD"`
+	MigrationMode        string        `yaml:"migration_mode" env:"MIGRATION_MODE"`
+
+	AccrualProvider         string        `yaml:"accrual_provider" env:"ACCRUAL_PROVIDER"`
+	AccrualSystemAddress    string        `yaml:"accrual_system_address" env:"ACCRUAL_SYSTEM_ADDRESS"`
+	AccrualWorkers          int           `yaml:"accrual_workers" env:"ACCRUAL_WORKERS"`
+	AccrualQueueSize        int           `yaml:"accrual_queue_size" env:"ACCRUAL_QUEUE_SIZE"`
+	AccrualRequestTimeout   time.Duration `yaml:"accrual_request_timeout" env:"ACCRUAL_REQUEST_TIMEOUT"`
+	AccrualRetryInterval    time.Duration `yaml:"accrual_retry_interval" env:"ACCRUAL_RETRY_INTERVAL"`
+	AccrualRateLimitBackoff time.Duration `yaml:"accrual_rate_limit_backoff" env:"ACCRUAL_RATE_LIMIT_BACKOFF"`
+	// AccrualCallbackSecret ключ подписи результатов, присылаемых на /api/internal/accrual/callback,
+	// пустой отключает приём. С включённым приёмом опрос идёт реже, раз в AccrualCallbackPollInterval
+	AccrualCallbackSecret       string        `yaml:"accrual_callback_secret" env:"ACCRUAL_CALLBACK_SECRET"`
+	AccrualCallbackTolerance    time.Duration `yaml:"accrual_callback_tolerance" env:"ACCRUAL_CALLBACK_TOLERANCE"`
+	AccrualCallbackPollInterval time.Duration `yaml:"accrual_callback_poll_interval" env:"ACCRUAL_CALLBACK_POLL_INTERVAL"`
+
+	WebhookMaxAttempts    int           `yaml:"webhook_max_attempts" env:"WEBHOOK_MAX_ATTEMPTS"`
+	WebhookRetryBackoff   time.Duration `yaml:"webhook_retry_backoff" env:"WEBHOOK_RETRY_BACKOFF"`
+	WebhookRequestTimeout time.Duration `yaml:"webhook_request_timeout" env:"WEBHOOK_REQUEST_TIMEOUT"`
+	WebhookPollInterval   time.Duration `yaml:"webhook_poll_interval" env:"WEBHOOK_POLL_INTERVAL"`
+	// WebhookAllowPrivateNetworks разрешает адреса вебхуков и уведомлений во внутренних сетях
+	// и на localhost. Только для разработки: иначе через вебхук можно обратиться к внутренним сервисам
+	WebhookAllowPrivateNetworks bool `yaml:"webhook_allow_private_networks" env:"WEBHOOK_ALLOW_PRIVATE_NETWORKS"`
+
+	// SMTPAddress host:port почтового сервера, пустой отключает уведомления по почте
+	SMTPAddress  string `yaml:"smtp_address" env:"SMTP_ADDRESS"`
+	SMTPUsername string `yaml:"smtp_username" env:"SMTP_USERNAME"`
+	SMTPPassword string `yaml:"smtp_password" env:"SMTP_PASSWORD"`
+	SMTPFrom     string `yaml:"smtp_from" env:"SMTP_FROM"`
+
+	NotificationMaxAttempts    int           `yaml:"notification_max_attempts" env:"NOTIFICATION_MAX_ATTEMPTS"`
+	NotificationRetryBackoff   time.Duration `yaml:"notification_retry_backoff" env:"NOTIFICATION_RETRY_BACKOFF"`
+	NotificationRequestTimeout time.Duration `yaml:"notification_request_timeout" env:"NOTIFICATION_REQUEST_TIMEOUT"`
+	NotificationPollInterval   time.Duration `yaml:"notification_poll_interval" env:"NOTIFICATION_POLL_INTERVAL"`
+
+	EventsSink        string `yaml:"events_sink" env:"EVENTS_SINK"`
+	EventsNATSURL     string `yaml:"events_nats_url" env:"EVENTS_NATS_URL"`
+	EventsNATSSubject string `yaml:"events_nats_subject" env:"EVENTS_NATS_SUBJECT"`
+	// EventsKafkaBrokers адреса брокеров через запятую
+	EventsKafkaBrokers string        `yaml:"events_kafka_brokers" env:"EVENTS_KAFKA_BROKERS"`
+	EventsKafkaTopic   string        `yaml:"events_kafka_topic" env:"EVENTS_KAFKA_TOPIC"`
+	EventsPollInterval time.Duration `yaml:"events_poll_interval" env:"EVENTS_POLL_INTERVAL"`
+	EventsRetryBackoff time.Duration `yaml:"events_retry_backoff" env:"EVENTS_RETRY_BACKOFF"`
+	EventsRetention    time.Duration `yaml:"events_retention" env:"EVENTS_RETENTION"`
+
+	JwtSecretKey    string        `yaml:"jwt_secret_key" env:"JWT_SECRET_KEY"`
+	AccessTokenTTL  time.Duration `yaml:"access_token_ttl" env:"ACCESS_TOKEN_TTL"`
+	RefreshTokenTTL time.Duration `yaml:"refresh_token_ttl" env:"REFRESH_TOKEN_TTL"`
+
+	ReconciliationAutoCorrect bool   `yaml:"reconciliation_auto_correct" env:"RECONCILIATION_AUTO_CORRECT"`
+	ReconciliationReportDir   string `yaml:"reconciliation_report_dir" env:"RECONCILIATION_REPORT_DIR"`
+
+	OrderEventsBroker  string        `yaml:"order_events_broker" env:"ORDER_EVENTS_BROKER"`
+	OrderBatchLimit    int           `yaml:"order_batch_limit" env:"ORDER_BATCH_LIMIT"`
+	OpenAPIValidation  string        `yaml:"openapi_validation" env:"OPENAPI_VALIDATION"`
+	TracingExporter    string        `yaml:"tracing_exporter" env:"TRACING_EXPORTER"`
+	TracingEndpoint    string        `yaml:"tracing_endpoint" env:"TRACING_ENDPOINT"`
+	ShutdownDrainDelay time.Duration `yaml:"shutdown_drain_delay" env:"SHUTDOWN_DRAIN_DELAY"`
+
+	// PrintConfig вывести итоговую конфигурацию и выйти, задаётся только флагом
+	PrintConfig bool `yaml:"-"`
+	// Args позиционные аргументы после флагов, например команда gophermart migrate
+	Args []string `yaml:"-"`
 }
 
-func NewConfig() *Config {
-	return &Config{}
+// Default Значения по умолчанию, подходят для локального запуска
+func Default() *Config {
+	return &Config{
+		RunAddress:   "localhost:8080",
+		GRPCAddress:  "localhost:3200",
+		AdminAddress: "localhost:9090",
+		LogLevel:     "info",
+
+		Storage:              StoragePostgres,
+		DBMaxOpenConns:       25,
+		DBMaxIdleConns:       5,
+		DBConnMaxLifetime:    30 * time.Minute,
+		DBConnMaxIdleTime:    5 * time.Minute,
+		DBSlowQueryThreshold: 200 * time.Millisecond,
+		MigrationMode:        MigrationModeCheck,
+
+		AccrualProvider:         AccrualProviderExternal,
+		AccrualSystemAddress:    "http://localhost:8082",
+		AccrualWorkers:          1,
+		AccrualQueueSize:        1000,
+		AccrualRequestTimeout:   10 * time.Second,
+		AccrualRetryInterval:    10 * time.Second,
+		AccrualRateLimitBackoff: 60 * time.Second,
+
+		AccrualCallbackTolerance:    5 * time.Minute,
+		AccrualCallbackPollInterval: 5 * time.Minute,
+
+		WebhookMaxAttempts:    8,
+		WebhookRetryBackoff:   30 * time.Second,
+		WebhookRequestTimeout: 10 * time.Second,
+		WebhookPollInterval:   5 * time.Second,
+
+		NotificationMaxAttempts:    5,
+		NotificationRetryBackoff:   time.Minute,
+		NotificationRequestTimeout: 10 * time.Second,
+		NotificationPollInterval:   5 * time.Second,
+
+		EventsSink:         EventsSinkLog,
+		EventsNATSURL:      "nats://localhost:4222",
+		EventsNATSSubject:  "gophermart.events",
+		EventsKafkaBrokers: "localhost:9092",
+		EventsKafkaTopic:   "gophermart.events",
+		EventsPollInterval: time.Second,
+		EventsRetryBackoff: 5 * time.Second,
+		EventsRetention:    7 * 24 * time.Hour,
+
+		AccessTokenTTL:  24 * time.Hour,
+		RefreshTokenTTL: 30 * 24 * time.Hour,
+
+		ReconciliationReportDir: "reports",
+
+		OrderEventsBroker:  OrderEventsBrokerPostgres,
+		OrderBatchLimit:    1000,
+		OpenAPIValidation:  OpenAPIValidationOff,
+		TracingExporter:    tracing.ExporterNone,
+		TracingEndpoint:    "localhost:4317",
+		ShutdownDrainDelay: 5 * time.Second,
+	}
 }
@@ -0,0 +1,200 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/ShukinDmitriy/gophermart/internal/tracing"
+	"github.com/caarlos0/env/v11"
+	"gopkg.in/yaml.v3"
+)
+
+// configFileEnv Переменная с путём к файлу конфигурации, если он не передан флагом -config
+const configFileEnv = "CONFIG_FILE"
+
+// Load Собирает конфигурацию из всех источников в порядке, описанном в документации пакета.
+// Проверку значений делает Validate
+func Load(name string, args []string) (*Config, error) {
+	// Флаги разбираются первыми, чтобы узнать путь к файлу, но применяются последними
+	var configFile string
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	bindFlags(fs, Default(), &configFile)
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	conf := Default()
+
+	if configFile == "" {
+		configFile = os.Getenv(configFileEnv)
+	}
+	if configFile != "" {
+		if err := loadFile(configFile, conf); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := env.Parse(conf); err != nil {
+		return nil, envError(err)
+	}
+
+	explicit := flag.NewFlagSet(name, flag.ContinueOnError)
+	bindFlags(explicit, conf, new(string))
+	var err error
+	fs.Visit(func(f *flag.Flag) {
+		if setErr := explicit.Set(f.Name, f.Value.String()); setErr != nil {
+			err = errors.Join(err, fmt.Errorf("flag -%s: %w", f.Name, setErr))
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	conf.Args = fs.Args()
+
+	return conf, nil
+}
+
+// envError Ошибки разбора с именами переменных окружения вместо имён полей структуры
+func envError(err error) error {
+	var aggregate env.AggregateError
+	if !errors.As(err, &aggregate) {
+		return err
+	}
+
+	errs := make([]error, 0, len(aggregate.Errors))
+	for _, e := range aggregate.Errors {
+		var parseErr env.ParseError
+		if errors.As(e, &parseErr) {
+			if field, ok := reflect.TypeOf(Config{}).FieldByName(parseErr.Name); ok {
+				errs = append(errs, fmt.Errorf("%s: %w", field.Tag.Get("env"), parseErr.Err))
+				continue
+			}
+		}
+		errs = append(errs, e)
+	}
+
+	return errors.Join(errs...)
+}
+
+// loadFile Формат определяется по расширению: .toml или YAML для остальных. Неизвестные ключи
+// считаются ошибкой, чтобы опечатка не превращалась в молча проигнорированную настройку
+func loadFile(path string, conf *Config) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("config file: %w", err)
+	}
+	defer file.Close()
+
+	var source io.Reader = file
+	if strings.EqualFold(filepath.Ext(path), ".toml") {
+		if source, err = tomlToYAML(file); err != nil {
+			return fmt.Errorf("config file %s: %w", path, err)
+		}
+	}
+
+	decoder := yaml.NewDecoder(source)
+	decoder.KnownFields(true)
+	if err = decoder.Decode(conf); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("config file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// tomlToYAML TOML файл с теми же ключами, что и YAML. Значения переводятся в YAML, чтобы имена ключей,
+// проверка неизвестных ключей и разбор длительностей были общими для обоих форматов
+func tomlToYAML(r io.Reader) (io.Reader, error) {
+	values := map[string]any{}
+	if _, err := toml.NewDecoder(r).Decode(&values); err != nil {
+		return nil, err
+	}
+	if len(values) == 0 {
+		return strings.NewReader(""), nil
+	}
+
+	data, err := yaml.Marshal(values)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes.NewReader(data), nil
+}
+
+// bindFlags Описание флагов. Значения по умолчанию берутся из conf, чтобы -h показывал итоговые умолчания.
+// Короткие -a, -d, -r, -s заданы спецификацией сервиса
+func bindFlags(fs *flag.FlagSet, conf *Config, configFile *string) {
+	fs.StringVar(configFile, "config", "", "YAML or TOML (.toml) config file, also "+configFileEnv)
+	fs.BoolVar(&conf.PrintConfig, "print-config", false, "Print the effective config with secrets redacted and exit")
+
+	fs.StringVar(&conf.RunAddress, "a", conf.RunAddress, "Run address")
+	fs.StringVar(&conf.GRPCAddress, "grpc-address", conf.GRPCAddress, "gRPC API address, empty to disable")
+	fs.StringVar(&conf.AdminAddress, "admin-address", conf.AdminAddress, "Admin HTTP server address, empty to disable")
+	fs.StringVar(&conf.AdminToken, "admin-token", conf.AdminToken, "Bearer token of the admin API, required unless the admin server listens on loopback")
+	fs.StringVar(&conf.LogLevel, "log-level", conf.LogLevel, "Log level: debug, info, warn or error")
+
+	fs.StringVar(&conf.Storage, "storage", conf.Storage, "Storage: "+StoragePostgres+", "+StorageSQLite+" (-d is a file path) or "+StorageMemory+" (no database, data is lost on exit)")
+	fs.StringVar(&conf.DatabaseURI, "d", conf.DatabaseURI, "Database dsn")
+	fs.IntVar(&conf.DBMaxOpenConns, "db-max-open-conns", conf.DBMaxOpenConns, "Max open database connections")
+	fs.IntVar(&conf.DBMaxIdleConns, "db-max-idle-conns", conf.DBMaxIdleConns, "Max idle database connections")
+	fs.DurationVar(&conf.DBConnMaxLifetime, "db-conn-max-lifetime", conf.DBConnMaxLifetime, "Max database connection lifetime, 0 for unlimited")
+	fs.DurationVar(&conf.DBConnMaxIdleTime, "db-conn-max-idle-time", conf.DBConnMaxIdleTime, "Max database connection idle time, 0 for unlimited")
+	fs.DurationVar(&conf.DBSlowQueryThreshold, "db-slow-query-threshold", conf.DBSlowQueryThreshold, "Queries slower than this are logged")
+	fs.StringVar(&conf.MigrationMode, "migration-mode", conf.MigrationMode, "On start: "+MigrationModeAuto+" applies migrations, "+MigrationModeCheck+" refuses to start if the schema is behind")
+
+	fs.StringVar(&conf.AccrualProvider, "accrual-provider", conf.AccrualProvider, "Accrual provider: "+AccrualProviderExternal+" (accrual system at -r) or "+AccrualProviderRules+" (local reward rules and order baskets)")
+	fs.StringVar(&conf.AccrualSystemAddress, "r", conf.AccrualSystemAddress, "Accrual system address")
+	fs.IntVar(&conf.AccrualWorkers, "accrual-workers", conf.AccrualWorkers, "Accrual queue workers")
+	fs.IntVar(&conf.AccrualQueueSize, "accrual-queue-size", conf.AccrualQueueSize, "Accrual queue capacity")
+	fs.DurationVar(&conf.AccrualRequestTimeout, "accrual-request-timeout", conf.AccrualRequestTimeout, "Accrual system request timeout")
+	fs.DurationVar(&conf.AccrualRetryInterval, "accrual-retry-interval", conf.AccrualRetryInterval, "Interval between polls of unfinished orders")
+	fs.DurationVar(&conf.AccrualRateLimitBackoff, "accrual-rate-limit-backoff", conf.AccrualRateLimitBackoff, "Pause after 429 from the accrual system")
+	fs.StringVar(&conf.AccrualCallbackSecret, "accrual-callback-secret", conf.AccrualCallbackSecret, "HMAC secret of pushed accrual results, empty disables the callback endpoint")
+	fs.DurationVar(&conf.AccrualCallbackTolerance, "accrual-callback-tolerance", conf.AccrualCallbackTolerance, "Max difference between a callback timestamp and the server time")
+	fs.DurationVar(&conf.AccrualCallbackPollInterval, "accrual-callback-poll-interval", conf.AccrualCallbackPollInterval, "Fallback poll interval of unfinished orders while callbacks are enabled")
+
+	fs.IntVar(&conf.WebhookMaxAttempts, "webhook-max-attempts", conf.WebhookMaxAttempts, "Webhook delivery attempts before the delivery is marked failed")
+	fs.DurationVar(&conf.WebhookRetryBackoff, "webhook-retry-backoff", conf.WebhookRetryBackoff, "Pause before the second webhook delivery attempt, doubled for each next one")
+	fs.DurationVar(&conf.WebhookRequestTimeout, "webhook-request-timeout", conf.WebhookRequestTimeout, "Webhook receiver request timeout")
+	fs.DurationVar(&conf.WebhookPollInterval, "webhook-poll-interval", conf.WebhookPollInterval, "Interval between checks for due webhook deliveries")
+	fs.BoolVar(&conf.WebhookAllowPrivateNetworks, "webhook-allow-private-networks", conf.WebhookAllowPrivateNetworks, "Allow webhook and notification URLs on localhost and private networks (development only)")
+
+	fs.StringVar(&conf.SMTPAddress, "smtp-address", conf.SMTPAddress, "SMTP server host:port for email notifications, empty disables email")
+	fs.StringVar(&conf.SMTPUsername, "smtp-username", conf.SMTPUsername, "SMTP username, empty disables authentication")
+	fs.StringVar(&conf.SMTPPassword, "smtp-password", conf.SMTPPassword, "SMTP password")
+	fs.StringVar(&conf.SMTPFrom, "smtp-from", conf.SMTPFrom, "Sender address of email notifications")
+	fs.IntVar(&conf.NotificationMaxAttempts, "notification-max-attempts", conf.NotificationMaxAttempts, "Email and webhook notification attempts before the delivery is marked failed")
+	fs.DurationVar(&conf.NotificationRetryBackoff, "notification-retry-backoff", conf.NotificationRetryBackoff, "Pause before the second notification attempt, doubled for each next one")
+	fs.DurationVar(&conf.NotificationRequestTimeout, "notification-request-timeout", conf.NotificationRequestTimeout, "Notification webhook request timeout")
+	fs.DurationVar(&conf.NotificationPollInterval, "notification-poll-interval", conf.NotificationPollInterval, "Interval between checks for due notification deliveries")
+
+	fs.StringVar(&conf.EventsSink, "events-sink", conf.EventsSink, "Domain events sink: "+EventsSinkLog+", "+EventsSinkNATS+" (JetStream) or "+EventsSinkKafka)
+	fs.StringVar(&conf.EventsNATSURL, "events-nats-url", conf.EventsNATSURL, "NATS server URL")
+	fs.StringVar(&conf.EventsNATSSubject, "events-nats-subject", conf.EventsNATSSubject, "NATS subject prefix, the event type is appended")
+	fs.StringVar(&conf.EventsKafkaBrokers, "events-kafka-brokers", conf.EventsKafkaBrokers, "Comma-separated Kafka brokers")
+	fs.StringVar(&conf.EventsKafkaTopic, "events-kafka-topic", conf.EventsKafkaTopic, "Kafka topic of domain events")
+	fs.DurationVar(&conf.EventsPollInterval, "events-poll-interval", conf.EventsPollInterval, "Interval between checks for unpublished domain events")
+	fs.DurationVar(&conf.EventsRetryBackoff, "events-retry-backoff", conf.EventsRetryBackoff, "Pause before the second publish attempt, doubled for each next one")
+	fs.DurationVar(&conf.EventsRetention, "events-retention", conf.EventsRetention, "How long published domain events are kept in the outbox")
+
+	fs.StringVar(&conf.JwtSecretKey, "s", conf.JwtSecretKey, "JWT secret key")
+	fs.DurationVar(&conf.AccessTokenTTL, "access-token-ttl", conf.AccessTokenTTL, "Access token lifetime")
+	fs.DurationVar(&conf.RefreshTokenTTL, "refresh-token-ttl", conf.RefreshTokenTTL, "Refresh token lifetime")
+
+	fs.BoolVar(&conf.ReconciliationAutoCorrect, "reconciliation-auto-correct", conf.ReconciliationAutoCorrect, "Auto-correct safe reconciliation discrepancies")
+	fs.StringVar(&conf.ReconciliationReportDir, "reconciliation-report-dir", conf.ReconciliationReportDir, "Reconciliation CSV reports directory")
+
+	fs.StringVar(&conf.OrderEventsBroker, "order-events-broker", conf.OrderEventsBroker, "Order events broker: postgres or memory, always memory unless -storage postgres")
+	fs.IntVar(&conf.OrderBatchLimit, "order-batch-limit", conf.OrderBatchLimit, "Max order numbers in a batch upload")
+	fs.StringVar(&conf.OpenAPIValidation, "openapi-validation", conf.OpenAPIValidation, "OpenAPI validation: off, requests or full (requests and responses)")
+	fs.StringVar(&conf.TracingExporter, "tracing-exporter", conf.TracingExporter, "Trace exporter: "+tracing.ExporterNone+", "+tracing.ExporterOTLP+" or "+tracing.ExporterStdout)
+	fs.StringVar(&conf.TracingEndpoint, "tracing-endpoint", conf.TracingEndpoint, "OTLP gRPC collector endpoint (host:port)")
+	fs.DurationVar(&conf.ShutdownDrainDelay, "shutdown-drain-delay", conf.ShutdownDrainDelay, "Delay between failing readiness and stopping servers on shutdown")
+}
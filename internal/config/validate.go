@@ -0,0 +1,300 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/ShukinDmitriy/gophermart/internal/tracing"
+	"go.uber.org/zap/zapcore"
+)
+
+// Validate Проверяет все значения сразу и возвращает все найденные ошибки, а не только первую
+func (c *Config) Validate() error {
+	var errs []error
+	add := func(name string, err error) {
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+	}
+
+	add("RUN_ADDRESS", hostPort(c.RunAddress))
+	if c.GRPCAddress != "" {
+		add("GRPC_ADDRESS", hostPort(c.GRPCAddress))
+	}
+	if c.AdminAddress != "" {
+		add("ADMIN_ADDRESS", hostPort(c.AdminAddress))
+		// служебный API начисляет баллы, без токена он допустим только на локальном интерфейсе
+		if c.AdminToken == "" && !loopback(c.AdminAddress) {
+			add("ADMIN_TOKEN", errors.New("is required when ADMIN_ADDRESS is not a loopback address"))
+		}
+	}
+	if _, err := zapcore.ParseLevel(c.LogLevel); err != nil {
+		add("LOG_LEVEL", err)
+	}
+
+	add("STORAGE", oneOf(c.Storage, StoragePostgres, StorageSQLite, StorageMemory))
+	// настройки базы не нужны, если данные хранятся в памяти
+	if c.Storage != StorageMemory {
+		add("DATABASE_URI", databaseURI(c.Storage, c.DatabaseURI))
+		add("DB_MAX_OPEN_CONNS", positive(c.DBMaxOpenConns))
+		if c.DBMaxIdleConns < 0 || c.DBMaxIdleConns > c.DBMaxOpenConns {
+			add("DB_MAX_IDLE_CONNS", fmt.Errorf("must be between 0 and DB_MAX_OPEN_CONNS (%d)", c.DBMaxOpenConns))
+		}
+		add("DB_CONN_MAX_LIFETIME", notNegative(int64(c.DBConnMaxLifetime)))
+		add("DB_CONN_MAX_IDLE_TIME", notNegative(int64(c.DBConnMaxIdleTime)))
+		add("DB_SLOW_QUERY_THRESHOLD", positive(int64(c.DBSlowQueryThreshold)))
+		add("MIGRATION_MODE", oneOf(c.MigrationMode, MigrationModeAuto, MigrationModeCheck))
+	}
+
+	add("ACCRUAL_PROVIDER", oneOf(c.AccrualProvider, AccrualProviderExternal, AccrualProviderRules))
+	// при локальном расчёте внешняя система расчёта не нужна
+	if c.AccrualProvider != AccrualProviderRules {
+		add("ACCRUAL_SYSTEM_ADDRESS", httpURL(c.AccrualSystemAddress))
+		add("ACCRUAL_REQUEST_TIMEOUT", positive(int64(c.AccrualRequestTimeout)))
+		add("ACCRUAL_RATE_LIMIT_BACKOFF", positive(int64(c.AccrualRateLimitBackoff)))
+	}
+	add("ACCRUAL_WORKERS", positive(c.AccrualWorkers))
+	add("ACCRUAL_QUEUE_SIZE", positive(c.AccrualQueueSize))
+	add("ACCRUAL_RETRY_INTERVAL", positive(int64(c.AccrualRetryInterval)))
+	if c.AccrualCallbackSecret != "" {
+		add("ACCRUAL_CALLBACK_TOLERANCE", positive(int64(c.AccrualCallbackTolerance)))
+		add("ACCRUAL_CALLBACK_POLL_INTERVAL", positive(int64(c.AccrualCallbackPollInterval)))
+	}
+
+	add("WEBHOOK_MAX_ATTEMPTS", positive(c.WebhookMaxAttempts))
+	add("WEBHOOK_RETRY_BACKOFF", positive(int64(c.WebhookRetryBackoff)))
+	add("WEBHOOK_REQUEST_TIMEOUT", positive(int64(c.WebhookRequestTimeout)))
+	add("WEBHOOK_POLL_INTERVAL", positive(int64(c.WebhookPollInterval)))
+
+	if c.SMTPAddress != "" {
+		add("SMTP_ADDRESS", hostPort(c.SMTPAddress))
+		add("SMTP_FROM", emailAddress(c.SMTPFrom))
+	}
+	add("NOTIFICATION_MAX_ATTEMPTS", positive(c.NotificationMaxAttempts))
+	add("NOTIFICATION_RETRY_BACKOFF", positive(int64(c.NotificationRetryBackoff)))
+	add("NOTIFICATION_REQUEST_TIMEOUT", positive(int64(c.NotificationRequestTimeout)))
+	add("NOTIFICATION_POLL_INTERVAL", positive(int64(c.NotificationPollInterval)))
+
+	add("EVENTS_SINK", oneOf(c.EventsSink, EventsSinkLog, EventsSinkNATS, EventsSinkKafka))
+	switch c.EventsSink {
+	case EventsSinkNATS:
+		if c.EventsNATSURL == "" {
+			add("EVENTS_NATS_URL", errors.New("is required"))
+		}
+		if c.EventsNATSSubject == "" {
+			add("EVENTS_NATS_SUBJECT", errors.New("is required"))
+		}
+	case EventsSinkKafka:
+		for _, broker := range c.KafkaBrokers() {
+			add("EVENTS_KAFKA_BROKERS", hostPort(broker))
+		}
+		if len(c.KafkaBrokers()) == 0 {
+			add("EVENTS_KAFKA_BROKERS", errors.New("is required"))
+		}
+		if c.EventsKafkaTopic == "" {
+			add("EVENTS_KAFKA_TOPIC", errors.New("is required"))
+		}
+	}
+	add("EVENTS_POLL_INTERVAL", positive(int64(c.EventsPollInterval)))
+	add("EVENTS_RETRY_BACKOFF", positive(int64(c.EventsRetryBackoff)))
+	add("EVENTS_RETENTION", positive(int64(c.EventsRetention)))
+
+	if c.JwtSecretKey == "" {
+		add("JWT_SECRET_KEY", errors.New("is required"))
+	}
+	add("ACCESS_TOKEN_TTL", positive(int64(c.AccessTokenTTL)))
+	if c.RefreshTokenTTL <= c.AccessTokenTTL {
+		add("REFRESH_TOKEN_TTL", fmt.Errorf("must be longer than ACCESS_TOKEN_TTL (%s)", c.AccessTokenTTL))
+	}
+
+	add("ORDER_EVENTS_BROKER", oneOf(c.OrderEventsBroker, OrderEventsBrokerPostgres, OrderEventsBrokerMemory))
+	add("ORDER_BATCH_LIMIT", positive(c.OrderBatchLimit))
+	add("OPENAPI_VALIDATION", oneOf(c.OpenAPIValidation, OpenAPIValidationOff, OpenAPIValidationRequests, OpenAPIValidationFull))
+	add("TRACING_EXPORTER", oneOf(c.TracingExporter, tracing.ExporterNone, tracing.ExporterOTLP, tracing.ExporterStdout))
+	if c.TracingExporter == tracing.ExporterOTLP {
+		add("TRACING_ENDPOINT", hostPort(c.TracingEndpoint))
+	}
+	add("SHUTDOWN_DRAIN_DELAY", notNegative(int64(c.ShutdownDrainDelay)))
+
+	return errors.Join(errs...)
+}
+
+// KafkaBrokers Адреса из EventsKafkaBrokers без пустых элементов
+func (c *Config) KafkaBrokers() []string {
+	var brokers []string
+	for _, broker := range strings.Split(c.EventsKafkaBrokers, ",") {
+		if broker = strings.TrimSpace(broker); broker != "" {
+			brokers = append(brokers, broker)
+		}
+	}
+
+	return brokers
+}
+
+// ValidateDatabase Проверка для команд, которым нужна только база, например gophermart migrate
+func (c *Config) ValidateDatabase() error {
+	if err := oneOf(c.Storage, StoragePostgres, StorageSQLite); err != nil {
+		return fmt.Errorf("STORAGE: %w", err)
+	}
+	if err := databaseURI(c.Storage, c.DatabaseURI); err != nil {
+		return fmt.Errorf("DATABASE_URI: %w", err)
+	}
+
+	return nil
+}
+
+// Redacted Копия для вывода: секреты скрыты целиком, в DSN скрыт пароль
+func (c *Config) Redacted() *Config {
+	redacted := *c
+	if redacted.JwtSecretKey != "" {
+		redacted.JwtSecretKey = redactedValue
+	}
+	if redacted.AdminToken != "" {
+		redacted.AdminToken = redactedValue
+	}
+	if redacted.AccrualCallbackSecret != "" {
+		redacted.AccrualCallbackSecret = redactedValue
+	}
+	if redacted.SMTPPassword != "" {
+		redacted.SMTPPassword = redactedValue
+	}
+	redacted.DatabaseURI = redactDSN(redacted.DatabaseURI)
+
+	return &redacted
+}
+
+const redactedValue = "xxxxx"
+
+var dsnPassword = regexp.MustCompile(`(password=)(\S+)`)
+
+func redactDSN(dsn string) string {
+	if !strings.Contains(dsn, "://") {
+		return dsnPassword.ReplaceAllString(dsn, "${1}"+redactedValue)
+	}
+
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return redactedValue
+	}
+
+	return u.Redacted()
+}
+
+func hostPort(address string) error {
+	if address == "" {
+		return errors.New("is required")
+	}
+	if _, _, err := net.SplitHostPort(address); err != nil {
+		return fmt.Errorf("must be host:port: %w", err)
+	}
+
+	return nil
+}
+
+// loopback Адрес слушает только локальный интерфейс. Пустой хост — все интерфейсы
+func loopback(address string) bool {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+
+	return ip != nil && ip.IsLoopback()
+}
+
+// databaseURI Для Postgres принимается URL postgres:// или postgresql:// и формат key=value,
+// для SQLite путь к файлу базы
+func databaseURI(storage string, dsn string) error {
+	if dsn == "" {
+		return errors.New("is required")
+	}
+	if storage == StorageSQLite {
+		return sqlitePath(dsn)
+	}
+	if !strings.Contains(dsn, "://") {
+		if !strings.Contains(dsn, "=") {
+			return errors.New("must be a postgres URL or key=value DSN")
+		}
+		return nil
+	}
+
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return fmt.Errorf("invalid URL: %w", errors.Unwrap(err))
+	}
+	if u.Scheme != "postgres" && u.Scheme != "postgresql" {
+		return fmt.Errorf("unsupported scheme %q, want postgres or postgresql", u.Scheme)
+	}
+
+	return nil
+}
+
+// sqlitePath У каждого соединения своя база в памяти, а в пуле соединений их несколько
+func sqlitePath(dsn string) error {
+	if strings.Contains(dsn, ":memory:") || strings.Contains(dsn, "mode=memory") {
+		return errors.New("must be a file, an in-memory SQLite database is not shared between connections")
+	}
+
+	return nil
+}
+
+func httpURL(address string) error {
+	if address == "" {
+		return errors.New("is required")
+	}
+
+	u, err := url.Parse(address)
+	if err != nil {
+		return fmt.Errorf("invalid URL: %w", errors.Unwrap(err))
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("must be an http(s) URL with a host")
+	}
+
+	return nil
+}
+
+func emailAddress(address string) error {
+	if address == "" {
+		return errors.New("is required")
+	}
+	if _, err := mail.ParseAddress(address); err != nil {
+		return fmt.Errorf("must be an email address: %w", err)
+	}
+
+	return nil
+}
+
+func positive[T int | int64](value T) error {
+	if value <= 0 {
+		return errors.New("must be positive")
+	}
+
+	return nil
+}
+
+func notNegative(value int64) error {
+	if value < 0 {
+		return errors.New("must not be negative")
+	}
+
+	return nil
+}
+
+func oneOf(value string, allowed ...string) error {
+	for _, a := range allowed {
+		if value == a {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown value %q, want one of %s", value, strings.Join(allowed, ", "))
+}
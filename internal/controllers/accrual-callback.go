@@ -0,0 +1,66 @@
+package controllers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/ShukinDmitriy/gophermart/internal/apierrors"
+	"github.com/ShukinDmitriy/gophermart/internal/models"
+	"github.com/ShukinDmitriy/gophermart/internal/services"
+	"github.com/ShukinDmitriy/gophermart/internal/signature"
+	"github.com/labstack/echo/v4"
+)
+
+// maxCallbackBodySize Результат по одному заказу занимает меньше сотни байт
+const maxCallbackBodySize = 64 << 10
+
+type AccrualCallbackController struct {
+	accrualService services.AccrualServiceInterface
+	secret         string
+	tolerance      time.Duration
+}
+
+func NewAccrualCallbackController(
+	accrualService services.AccrualServiceInterface,
+	secret string,
+	tolerance time.Duration,
+) *AccrualCallbackController {
+	return &AccrualCallbackController{
+		accrualService: accrualService,
+		secret:         secret,
+		tolerance:      tolerance,
+	}
+}
+
+// Callback Результат расчёта по заказу, присланный системой расчёта. Запрос подписан ключом
+// AccrualCallbackSecret, подпись проверяется по телу запроса до разбора JSON
+func (controller *AccrualCallbackController) Callback() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		body, err := io.ReadAll(io.LimitReader(c.Request().Body, maxCallbackBodySize+1))
+		if err != nil {
+			return errBadRequest("cannot read request body", err)
+		}
+		if len(body) > maxCallbackBodySize {
+			return apierrors.NewAPIError(http.StatusRequestEntityTooLarge, apierrors.ErrorCodePayloadTooLarge, "request body is too large")
+		}
+
+		err = signature.Verify(c.Request().Header, controller.secret, body, time.Now(), controller.tolerance)
+		if err != nil {
+			return apierrors.NewAPIError(http.StatusUnauthorized, apierrors.ErrorCodeInvalidSignature, err.Error())
+		}
+
+		var request models.AccrualOrderResponse
+		if err = json.Unmarshal(body, &request); err != nil {
+			return errBadRequest("invalid request body", err)
+		}
+
+		err = controller.accrualService.ApplyCallback(c.Request().Context(), request)
+		if err != nil {
+			return err
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,109 @@
+package controllers_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/ShukinDmitriy/gophermart/internal/apierrors"
+	"github.com/ShukinDmitriy/gophermart/internal/controllers"
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+	"github.com/ShukinDmitriy/gophermart/internal/models"
+	appservices "github.com/ShukinDmitriy/gophermart/internal/services"
+	"github.com/ShukinDmitriy/gophermart/internal/signature"
+	"github.com/ShukinDmitriy/gophermart/mocks/internal_/services"
+	"github.com/labstack/echo/v4"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+)
+
+var _ = Describe("AccrualCallback", func() {
+	secret := "callback-secret"
+	body := `{"order":"12345678903","status":"PROCESSED","accrual":500}`
+	result := models.AccrualOrderResponse{Order: "12345678903", Status: entities.OrderStatusProcessed, Accrual: 500}
+
+	var e *echo.Echo
+	var accrualService *services.AccrualServiceInterface
+
+	send := func(body string, sign func(header http.Header)) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/internal/accrual/callback", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		sign(req.Header)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		return rec
+	}
+	signedAt := func(secret string, at time.Time) func(header http.Header) {
+		return func(header http.Header) {
+			signature.SetHeaders(header, secret, []byte(body), at)
+		}
+	}
+
+	BeforeEach(func() {
+		e = echo.New()
+		e.HTTPErrorHandler = controllers.HTTPErrorHandler
+		accrualService = new(services.AccrualServiceInterface)
+		controllers.RegisterAccrualCallbackRoutes(e, controllers.NewAccrualCallbackController(accrualService, secret, 5*time.Minute))
+	})
+
+	It("must apply a signed result", func() {
+		// Arrange
+		accrualService.EXPECT().ApplyCallback(mock.Anything, result).Return(nil)
+
+		// Act
+		rec := send(body, signedAt(secret, time.Now()))
+
+		// Assertions
+		Expect(rec.Code).To(Equal(http.StatusNoContent))
+		accrualService.AssertExpectations(GinkgoT())
+	})
+
+	It("must reject unsigned, stale and foreign results before applying them", func() {
+		// Act
+		unsigned := send(body, func(header http.Header) {})
+		stale := send(body, signedAt(secret, time.Now().Add(-time.Hour)))
+		foreign := send(body, signedAt("other-secret", time.Now()))
+		tampered := send(strings.Replace(body, "500", "5000", 1), signedAt(secret, time.Now()))
+
+		// Assertions
+		for _, rec := range []*httptest.ResponseRecorder{unsigned, stale, foreign, tampered} {
+			var problem models.Problem
+			Expect(json.Unmarshal(rec.Body.Bytes(), &problem)).To(Succeed())
+			Expect(rec.Code).To(Equal(http.StatusUnauthorized))
+			Expect(problem.Code).To(Equal(apierrors.ErrorCodeInvalidSignature))
+		}
+		accrualService.AssertNotCalled(GinkgoT(), "ApplyCallback", mock.Anything, mock.Anything)
+	})
+
+	It("must answer 404 for an unknown order and 400 for a malformed result", func() {
+		// Arrange
+		accrualService.EXPECT().ApplyCallback(mock.Anything, result).Return(appservices.ErrOrderNotFound)
+		malformed := `{"order":"12345678903","status":`
+
+		// Act
+		unknown := send(body, signedAt(secret, time.Now()))
+		broken := send(malformed, func(header http.Header) {
+			signature.SetHeaders(header, secret, []byte(malformed), time.Now())
+		})
+
+		// Assertions
+		Expect(unknown.Code).To(Equal(http.StatusNotFound))
+		Expect(broken.Code).To(Equal(http.StatusBadRequest))
+	})
+
+	It("must answer 500 when the result cannot be applied, so the sender retries", func() {
+		// Arrange
+		accrualService.EXPECT().ApplyCallback(mock.Anything, result).Return(errors.New("database is down"))
+
+		// Act
+		rec := send(body, signedAt(secret, time.Now()))
+
+		// Assertions
+		Expect(rec.Code).To(Equal(http.StatusInternalServerError))
+	})
+})
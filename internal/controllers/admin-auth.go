@@ -0,0 +1,28 @@
+package controllers
+
+import (
+	"crypto/subtle"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// AdminAuth Проверка токена служебного API в заголовке Authorization: Bearer <token>.
+// Пустой token отключает проверку: конфигурация допускает это только для служебного сервера на loopback
+func AdminAuth(token string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		if token == "" {
+			return next
+		}
+
+		return func(c echo.Context) error {
+			presented, ok := strings.CutPrefix(c.Request().Header.Get(echo.HeaderAuthorization), "Bearer ")
+			if !ok || subtle.ConstantTimeCompare([]byte(presented), []byte(token)) != 1 {
+				c.Response().Header().Set(echo.HeaderWWWAuthenticate, `Bearer realm="gophermart-admin"`)
+				return errUnauthorized()
+			}
+
+			return next(c)
+		}
+	}
+}
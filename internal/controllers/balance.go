@@ -4,50 +4,35 @@ import (
 	"net/http"
 
 	"github.com/ShukinDmitriy/gophermart/internal/auth"
-	"github.com/ShukinDmitriy/gophermart/internal/entities"
-	"github.com/ShukinDmitriy/gophermart/internal/models"
-	"github.com/ShukinDmitriy/gophermart/internal/repositories"
+	"github.com/ShukinDmitriy/gophermart/internal/logging"
+	"github.com/ShukinDmitriy/gophermart/internal/services"
 	"github.com/labstack/echo/v4"
 )
 
 type BalanceController struct {
-	authService         auth.AuthServiceInterface
-	accountRepository   repositories.AccountRepositoryInterface
-	operationRepository repositories.OperationRepositoryInterface
+	authService   auth.AuthServiceInterface
+	ledgerService services.LedgerServiceInterface
 }
 
 func NewBalanceController(
 	authService auth.AuthServiceInterface,
-	accountRepository repositories.AccountRepositoryInterface,
-	operationRepository repositories.OperationRepositoryInterface,
+	ledgerService services.LedgerServiceInterface,
 ) *BalanceController {
 	return &BalanceController{
-		authService:         authService,
-		accountRepository:   accountRepository,
-		operationRepository: operationRepository,
+		authService:   authService,
+		ledgerService: ledgerService,
 	}
 }
 
 // GetBalance Получение баланса пользователя
 func (controller *BalanceController) GetBalance() echo.HandlerFunc {
 	return func(c echo.Context) error {
-		resp := &models.GetBalanceResponse{}
 		currentUserID := controller.authService.GetUserID(c)
+		logging.With(c, logging.UserID(currentUserID))
 
-		bonusAccount, err := controller.accountRepository.FindByUserID(currentUserID, entities.AccountTypeBonus)
-		if err != nil || bonusAccount == nil {
-			return c.JSON(http.StatusInternalServerError, nil)
-		}
-
-		resp.Current = bonusAccount.Sum
-
-		withdrawn, err := controller.operationRepository.GetWithdrawnByAccountID(bonusAccount.ID)
+		resp, err := controller.ledgerService.GetBalance(currentUserID)
 		if err != nil {
-			return c.JSON(http.StatusInternalServerError, nil)
-		}
-
-		if withdrawn != 0 {
-			resp.Withdrawn = withdrawn
+			return err
 		}
 
 		return c.JSON(http.StatusOK, resp)
@@ -8,9 +8,12 @@ import (
 
 	"github.com/ShukinDmitriy/gophermart/internal/controllers"
 	"github.com/ShukinDmitriy/gophermart/internal/entities"
+	"github.com/ShukinDmitriy/gophermart/internal/metrics"
 	"github.com/ShukinDmitriy/gophermart/internal/models"
+	appservices "github.com/ShukinDmitriy/gophermart/internal/services"
 	"github.com/ShukinDmitriy/gophermart/mocks/internal_/auth"
 	"github.com/ShukinDmitriy/gophermart/mocks/internal_/repositories"
+	"github.com/ShukinDmitriy/gophermart/mocks/internal_/services"
 	"github.com/labstack/echo/v4"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -45,8 +48,13 @@ var _ = Describe("Balance", func() {
 		operationRepository = new(repositories.OperationRepositoryInterface)
 		controller = controllers.NewBalanceController(
 			authService,
-			accountRepository,
-			operationRepository,
+			appservices.NewLedgerService(
+				accountRepository,
+				operationRepository,
+				new(repositories.OrderRepositoryInterface),
+				new(services.WebhookServiceInterface),
+				metrics.New(),
+			),
 		)
 	})
 
@@ -61,14 +69,13 @@ var _ = Describe("Balance", func() {
 			operationRepository.EXPECT().GetWithdrawnByAccountID(account.ID).Return(response.Withdrawn, nil)
 
 			// Act
-			err := controller.GetBalance()(c)
+			serve(c, controller.GetBalance())
 
 			// Assertions
-			Expect(err).NotTo(HaveOccurred())
 			Expect(rec.Code).To(Equal(http.StatusOK))
 
 			resJ := &models.GetBalanceResponse{}
-			err = json.Unmarshal(rec.Body.Bytes(), resJ)
+			err := json.Unmarshal(rec.Body.Bytes(), resJ)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(resJ.Current).To(Equal(response.Current))
 			Expect(resJ.Withdrawn).To(Equal(response.Withdrawn))
@@ -84,10 +91,9 @@ var _ = Describe("Balance", func() {
 			operationRepository.EXPECT().GetWithdrawnByAccountID(account.ID).Return(0, errors.New("test error"))
 
 			// Act
-			err := controller.GetBalance()(c)
+			serve(c, controller.GetBalance())
 
 			// Assertions
-			Expect(err).NotTo(HaveOccurred())
 			Expect(rec.Code).To(Equal(http.StatusInternalServerError))
 		})
 
@@ -100,10 +106,9 @@ var _ = Describe("Balance", func() {
 			accountRepository.EXPECT().FindByUserID(userID, entities.AccountTypeBonus).Return(nil, nil)
 
 			// Act
-			err := controller.GetBalance()(c)
+			serve(c, controller.GetBalance())
 
 			// Assertions
-			Expect(err).NotTo(HaveOccurred())
 			Expect(rec.Code).To(Equal(http.StatusInternalServerError))
 		})
 	})
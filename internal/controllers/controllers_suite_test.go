@@ -3,6 +3,9 @@ package controllers_test
 import (
 	"testing"
 
+	"github.com/ShukinDmitriy/gophermart/internal/controllers"
+	"github.com/labstack/echo/v4"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
@@ -11,3 +14,10 @@ func TestControllers(t *testing.T) {
 	RegisterFailHandler(Fail)
 	RunSpecs(t, "Controllers Suite")
 }
+
+// serve Выполняет обработчик и, как это делает Echo, передаёт его ошибку в HTTPErrorHandler
+func serve(c echo.Context, handler echo.HandlerFunc) {
+	if err := handler(c); err != nil {
+		controllers.HTTPErrorHandler(err, c)
+	}
+}
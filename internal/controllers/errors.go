@@ -0,0 +1,110 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/ShukinDmitriy/gophermart/internal/apierrors"
+	"github.com/ShukinDmitriy/gophermart/internal/logging"
+	"github.com/ShukinDmitriy/gophermart/internal/models"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+func errBadRequest(detail string, cause error) *apierrors.APIError {
+	return apierrors.NewAPIError(http.StatusBadRequest, apierrors.ErrorCodeBadRequest, detail).WithCause(cause)
+}
+
+func errUnauthorized() *apierrors.APIError {
+	return apierrors.NewAPIError(http.StatusUnauthorized, apierrors.ErrorCodeUnauthorized, "authentication required")
+}
+
+func errNotFound(detail string) *apierrors.APIError {
+	return apierrors.NewAPIError(http.StatusNotFound, apierrors.ErrorCodeNotFound, detail)
+}
+
+func errInternal(cause error) *apierrors.APIError {
+	return apierrors.NewAPIError(http.StatusInternalServerError, apierrors.ErrorCodeInternal, "internal gophermart error").WithCause(cause)
+}
+
+// HTTPErrorHandler Единый обработчик ошибок Echo: все ошибки отдаются в формате RFC 7807
+func HTTPErrorHandler(err error, c echo.Context) {
+	if c.Response().Committed {
+		logging.FromContext(c).Error("error after response was committed", zap.Error(err))
+		return
+	}
+
+	apiErr := toAPIError(err)
+	if apiErr.Status >= http.StatusInternalServerError {
+		logging.FromContext(c).Error("request failed", zap.String("code", apiErr.Code), zap.Error(err))
+	}
+
+	problem := models.Problem{
+		Type:      models.ProblemTypePrefix + apiErr.Code,
+		Title:     http.StatusText(apiErr.Status),
+		Status:    apiErr.Status,
+		Code:      apiErr.Code,
+		Detail:    apiErr.Detail,
+		Instance:  c.Request().URL.Path,
+		RequestID: c.Response().Header().Get(echo.HeaderXRequestID),
+		Errors:    apiErr.Errors,
+	}
+
+	if c.Request().Method == http.MethodHead {
+		err = c.NoContent(apiErr.Status)
+	} else {
+		c.Response().Header().Set(echo.HeaderContentType, models.MIMEApplicationProblemJSON)
+		err = c.JSON(apiErr.Status, problem)
+	}
+	if err != nil {
+		logging.FromContext(c).Error("cannot write error response", zap.Error(err))
+	}
+}
+
+func toAPIError(err error) *apierrors.APIError {
+	var apiErr *apierrors.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr
+	}
+
+	if apiErr := apierrors.FromServiceError(err); apiErr != nil {
+		return apiErr
+	}
+
+	var httpErr *echo.HTTPError
+	if errors.As(err, &httpErr) {
+		detail := http.StatusText(httpErr.Code)
+		if message, ok := httpErr.Message.(string); ok {
+			detail = message
+		}
+
+		return apierrors.NewAPIError(httpErr.Code, errorCodeByStatus(httpErr.Code), detail).WithCause(httpErr.Internal)
+	}
+
+	return errInternal(err)
+}
+
+func errorCodeByStatus(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return apierrors.ErrorCodeBadRequest
+	case http.StatusUnauthorized:
+		return apierrors.ErrorCodeUnauthorized
+	case http.StatusNotFound:
+		return apierrors.ErrorCodeNotFound
+	case http.StatusMethodNotAllowed:
+		return apierrors.ErrorCodeMethodNotAllowed
+	case http.StatusRequestEntityTooLarge:
+		return apierrors.ErrorCodePayloadTooLarge
+	case http.StatusUnsupportedMediaType:
+		return apierrors.ErrorCodeUnsupportedMediaType
+	case http.StatusTooManyRequests:
+		return apierrors.ErrorCodeTooManyRequests
+	}
+
+	if status >= http.StatusInternalServerError {
+		return apierrors.ErrorCodeInternal
+	}
+
+	return apierrors.ErrorCodeBadRequest
+}
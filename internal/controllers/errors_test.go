@@ -0,0 +1,94 @@
+package controllers_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/ShukinDmitriy/gophermart/internal/apierrors"
+	"github.com/ShukinDmitriy/gophermart/internal/controllers"
+	"github.com/ShukinDmitriy/gophermart/internal/models"
+	"github.com/labstack/echo/v4"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("HTTPErrorHandler", func() {
+	var e *echo.Echo
+	var rec *httptest.ResponseRecorder
+	var c echo.Context
+
+	BeforeEach(func() {
+		e = echo.New()
+		rec = httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/api/user/orders", nil)
+		c = e.NewContext(req, rec)
+	})
+
+	decodeProblem := func() *models.Problem {
+		problem := &models.Problem{}
+		err := json.Unmarshal(rec.Body.Bytes(), problem)
+		Expect(err).NotTo(HaveOccurred())
+
+		return problem
+	}
+
+	It("should write an api error as problem details", func() {
+		// Arrange
+		c.Response().Header().Set(echo.HeaderXRequestID, "request-1")
+		apiErr := apierrors.NewAPIError(http.StatusConflict, apierrors.ErrorCodeOrderOwnedByAnotherUser, "order uploaded by another user")
+
+		// Act
+		controllers.HTTPErrorHandler(apiErr, c)
+
+		// Assertions
+		Expect(rec.Code).To(Equal(http.StatusConflict))
+		Expect(rec.Header().Get(echo.HeaderContentType)).To(Equal(models.MIMEApplicationProblemJSON))
+
+		problem := decodeProblem()
+		Expect(problem.Type).To(Equal(models.ProblemTypePrefix + apierrors.ErrorCodeOrderOwnedByAnotherUser))
+		Expect(problem.Title).To(Equal(http.StatusText(http.StatusConflict)))
+		Expect(problem.Status).To(Equal(http.StatusConflict))
+		Expect(problem.Code).To(Equal(apierrors.ErrorCodeOrderOwnedByAnotherUser))
+		Expect(problem.Detail).To(Equal("order uploaded by another user"))
+		Expect(problem.Instance).To(Equal("/api/user/orders"))
+		Expect(problem.RequestID).To(Equal("request-1"))
+	})
+
+	It("should map an echo error by status code", func() {
+		// Act
+		controllers.HTTPErrorHandler(echo.NewHTTPError(http.StatusUnauthorized, "Unauthorized"), c)
+
+		// Assertions
+		Expect(rec.Code).To(Equal(http.StatusUnauthorized))
+
+		problem := decodeProblem()
+		Expect(problem.Code).To(Equal(apierrors.ErrorCodeUnauthorized))
+		Expect(problem.Detail).To(Equal("Unauthorized"))
+	})
+
+	It("should hide the cause of an unknown error", func() {
+		// Act
+		controllers.HTTPErrorHandler(errors.New("pq: connection refused"), c)
+
+		// Assertions
+		Expect(rec.Code).To(Equal(http.StatusInternalServerError))
+
+		problem := decodeProblem()
+		Expect(problem.Code).To(Equal(apierrors.ErrorCodeInternal))
+		Expect(problem.Detail).NotTo(ContainSubstring("connection refused"))
+	})
+
+	It("should not write a response that is already committed", func() {
+		// Arrange
+		_ = c.NoContent(http.StatusNoContent)
+
+		// Act
+		controllers.HTTPErrorHandler(errors.New("test error"), c)
+
+		// Assertions
+		Expect(rec.Code).To(Equal(http.StatusNoContent))
+		Expect(rec.Body.Len()).To(BeZero())
+	})
+})
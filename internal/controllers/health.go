@@ -0,0 +1,39 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/ShukinDmitriy/gophermart/internal/models"
+	"github.com/ShukinDmitriy/gophermart/internal/services"
+	"github.com/labstack/echo/v4"
+)
+
+type HealthController struct {
+	healthService services.HealthServiceInterface
+}
+
+func NewHealthController(healthService services.HealthServiceInterface) *HealthController {
+	return &HealthController{
+		healthService: healthService,
+	}
+}
+
+// Liveness Процесс жив и обрабатывает запросы. Зависимости не проверяются,
+// иначе недоступная БД приводила бы к перезапуску всех экземпляров
+func (controller *HealthController) Liveness() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		return c.JSON(http.StatusOK, models.HealthCheck{Status: models.HealthStatusOK})
+	}
+}
+
+// Readiness Готовность принимать трафик. 503, если хотя бы одна проверка не прошла или идёт остановка
+func (controller *HealthController) Readiness() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		res := controller.healthService.Ready(c.Request().Context())
+		if res.Status != models.HealthStatusOK {
+			return c.JSON(http.StatusServiceUnavailable, res)
+		}
+
+		return c.JSON(http.StatusOK, res)
+	}
+}
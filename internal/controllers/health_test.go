@@ -0,0 +1,71 @@
+package controllers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/ShukinDmitriy/gophermart/internal/controllers"
+	"github.com/ShukinDmitriy/gophermart/internal/models"
+	"github.com/ShukinDmitriy/gophermart/mocks/internal_/services"
+	"github.com/labstack/echo/v4"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+)
+
+var _ = Describe("Health", func() {
+	var e *echo.Echo
+	var rec *httptest.ResponseRecorder
+	var healthService *services.HealthServiceInterface
+
+	BeforeEach(func() {
+		e = echo.New()
+		rec = httptest.NewRecorder()
+		healthService = new(services.HealthServiceInterface)
+		controllers.RegisterHealthRoutes(e, controllers.NewHealthController(healthService))
+	})
+
+	It("must answer liveness without checking dependencies", func() {
+		// Act
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+		// Assertions
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		healthService.AssertNotCalled(GinkgoT(), "Ready", mock.Anything)
+	})
+
+	It("must answer readiness with 200 when ready", func() {
+		// Arrange
+		healthService.EXPECT().Ready(mock.Anything).Return(&models.ReadinessResponse{
+			Status: models.HealthStatusOK,
+			Checks: map[string]models.HealthCheck{"database": {Status: models.HealthStatusOK}},
+		})
+
+		// Act
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+		// Assertions
+		Expect(rec.Code).To(Equal(http.StatusOK))
+	})
+
+	It("must answer readiness with 503 and the failed checks when not ready", func() {
+		// Arrange
+		healthService.EXPECT().Ready(mock.Anything).Return(&models.ReadinessResponse{
+			Status: models.HealthStatusFail,
+			Checks: map[string]models.HealthCheck{
+				"shutdown": {Status: models.HealthStatusFail, Error: "server is shutting down"},
+			},
+		})
+
+		// Act
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+		// Assertions
+		Expect(rec.Code).To(Equal(http.StatusServiceUnavailable))
+
+		res := &models.ReadinessResponse{}
+		Expect(json.Unmarshal(rec.Body.Bytes(), res)).To(Succeed())
+		Expect(res.Checks["shutdown"].Error).To(Equal("server is shutting down"))
+	})
+})
@@ -0,0 +1,116 @@
+package controllers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+
+	"github.com/ShukinDmitriy/gophermart/internal/controllers"
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+	"github.com/ShukinDmitriy/gophermart/internal/metrics"
+	"github.com/ShukinDmitriy/gophermart/internal/models"
+	"github.com/ShukinDmitriy/gophermart/internal/repositories/memory"
+	appservices "github.com/ShukinDmitriy/gophermart/internal/services"
+	"github.com/ShukinDmitriy/gophermart/mocks/internal_/auth"
+	"github.com/ShukinDmitriy/gophermart/mocks/internal_/services"
+	"github.com/labstack/echo/v4"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+// Настоящие сервисы поверх хранилища в памяти: проверяются правила, которые моки репозиториев только имитируют
+var _ = Describe("Memory storage", func() {
+	var e *echo.Echo
+
+	// userHeader Пользователь запроса вместо JWT
+	const userHeader = "X-Test-User"
+
+	request := func(userID uint, method string, target string, contentType string, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set(userHeader, strconv.FormatUint(uint64(userID), 10))
+		if contentType != "" {
+			req.Header.Set(echo.HeaderContentType, contentType)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		return rec
+	}
+
+	BeforeEach(func() {
+		storage := memory.NewStorage()
+		authService := new(auth.AuthServiceInterface)
+		authService.EXPECT().GetUserID(mock.Anything).RunAndReturn(func(c echo.Context) uint {
+			userID, _ := strconv.ParseUint(c.Request().Header.Get(userHeader), 10, 64)
+			return uint(userID)
+		}).Maybe()
+		authService.EXPECT().GenerateTokensAndSetCookies(mock.Anything, mock.Anything).Return(nil).Maybe()
+		accrualService := new(services.AccrualServiceInterface)
+		accrualService.EXPECT().SendOrderToQueue(mock.Anything, mock.Anything).Return().Maybe()
+		accrualService.EXPECT().TrySendOrderToQueue(mock.Anything, mock.Anything).Return(true).Maybe()
+
+		webhookService := appservices.NewWebhookService(
+			appservices.WebhookOptions{MaxAttempts: 1},
+			storage.Webhooks,
+			http.DefaultClient,
+			metrics.New(),
+			zap.NewNop(),
+		)
+		ledgerService := appservices.NewLedgerService(storage.Accounts, storage.Operations, storage.Orders, webhookService, metrics.New())
+		orderService := appservices.NewOrderService(storage.Orders, storage.Operations, accrualService)
+
+		e = echo.New()
+		e.HTTPErrorHandler = controllers.HTTPErrorHandler
+		controllers.RegisterRoutes(
+			e,
+			func(next echo.HandlerFunc) echo.HandlerFunc { return next },
+			controllers.NewBalanceController(authService, ledgerService),
+			controllers.NewOperationController(authService, ledgerService),
+			controllers.NewOrderController(authService, orderService, new(services.OrderEventBrokerInterface), 3),
+			controllers.NewUserController(authService, appservices.NewUserService(storage.Users)),
+			controllers.NewWebhookController(authService, webhookService),
+			controllers.NewNotificationController(authService, appservices.NewNotificationService(
+				appservices.NotificationOptions{MaxAttempts: 1},
+				storage.Notifications,
+				storage.Users,
+				nil,
+				metrics.New(),
+				zap.NewNop(),
+			)),
+		)
+	})
+
+	It("should register users, upload orders and resolve conflicts", func() {
+		for _, login := range []string{"user1", "user2"} {
+			rec := request(0, http.MethodPost, "/api/user/register", echo.MIMEApplicationJSON, `{"login":"`+login+`","password":"password"}`)
+			Expect(rec.Code).To(Equal(http.StatusOK), rec.Body.String())
+		}
+		rec := request(0, http.MethodPost, "/api/user/register", echo.MIMEApplicationJSON, `{"login":"user1","password":"password"}`)
+		Expect(rec.Code).To(Equal(http.StatusConflict), rec.Body.String())
+
+		rec = request(1, http.MethodPost, "/api/user/orders", echo.MIMETextPlain, "12345678903")
+		Expect(rec.Code).To(Equal(http.StatusAccepted), rec.Body.String())
+		rec = request(1, http.MethodPost, "/api/user/orders", echo.MIMETextPlain, "12345678903")
+		Expect(rec.Code).To(Equal(http.StatusOK), rec.Body.String())
+		rec = request(2, http.MethodPost, "/api/user/orders", echo.MIMETextPlain, "12345678903")
+		Expect(rec.Code).To(Equal(http.StatusConflict), rec.Body.String())
+
+		rec = request(1, http.MethodGet, "/api/user/orders", "", "")
+		Expect(rec.Code).To(Equal(http.StatusOK), rec.Body.String())
+		var orders []models.GetOrdersResponse
+		Expect(json.Unmarshal(rec.Body.Bytes(), &orders)).To(Succeed())
+		Expect(orders).To(HaveLen(1))
+		Expect(orders[0].Status).To(Equal(entities.OrderStatusNew))
+
+		rec = request(2, http.MethodGet, "/api/user/orders", "", "")
+		Expect(rec.Code).To(Equal(http.StatusNoContent), rec.Body.String())
+
+		rec = request(1, http.MethodGet, "/api/user/balance", "", "")
+		Expect(rec.Code).To(Equal(http.StatusOK), rec.Body.String())
+		Expect(rec.Body.String()).To(MatchJSON(`{"current":0,"withdrawn":0}`))
+	})
+})
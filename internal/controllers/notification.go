@@ -0,0 +1,134 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/ShukinDmitriy/gophermart/internal/auth"
+	"github.com/ShukinDmitriy/gophermart/internal/logging"
+	"github.com/ShukinDmitriy/gophermart/internal/models"
+	"github.com/ShukinDmitriy/gophermart/internal/services"
+	"github.com/labstack/echo/v4"
+)
+
+type NotificationController struct {
+	authService         auth.AuthServiceInterface
+	notificationService services.NotificationServiceInterface
+}
+
+func NewNotificationController(
+	authService auth.AuthServiceInterface,
+	notificationService services.NotificationServiceInterface,
+) *NotificationController {
+	return &NotificationController{
+		authService:         authService,
+		notificationService: notificationService,
+	}
+}
+
+// GetNotifications Уведомления пользователя, новые первыми, и число непрочитанных
+func (controller *NotificationController) GetNotifications() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		currentUserID, err := controller.currentUserID(c)
+		if err != nil {
+			return err
+		}
+
+		var request models.GetNotificationsRequest
+		if err = c.Bind(&request); err != nil {
+			return errBadRequest("invalid query parameters", err)
+		}
+
+		notifications, err := controller.notificationService.GetNotifications(currentUserID, request)
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(http.StatusOK, notifications)
+	}
+}
+
+// MarkRead Отметка одного уведомления прочитанным, повторная отметка не меняет время прочтения
+func (controller *NotificationController) MarkRead() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		currentUserID, err := controller.currentUserID(c)
+		if err != nil {
+			return err
+		}
+		id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil || id == 0 {
+			return errNotFound(services.ErrNotificationNotFound.Error())
+		}
+
+		if err = controller.notificationService.MarkRead(currentUserID, uint(id)); err != nil {
+			return err
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+// MarkAllRead Отметка всех уведомлений пользователя прочитанными
+func (controller *NotificationController) MarkAllRead() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		currentUserID, err := controller.currentUserID(c)
+		if err != nil {
+			return err
+		}
+
+		if err = controller.notificationService.MarkAllRead(currentUserID); err != nil {
+			return err
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+// GetPreferences Каналы уведомлений пользователя
+func (controller *NotificationController) GetPreferences() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		currentUserID, err := controller.currentUserID(c)
+		if err != nil {
+			return err
+		}
+
+		preferences, err := controller.notificationService.GetPreferences(currentUserID)
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(http.StatusOK, preferences)
+	}
+}
+
+// UpdatePreferences Замена каналов уведомлений. Секрет подписи нового адреса вебхука есть только в этом ответе
+func (controller *NotificationController) UpdatePreferences() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		currentUserID, err := controller.currentUserID(c)
+		if err != nil {
+			return err
+		}
+
+		var request models.NotificationPreferencesRequest
+		if err = c.Bind(&request); err != nil {
+			return errBadRequest("invalid request body", err)
+		}
+
+		preferences, err := controller.notificationService.UpdatePreferences(currentUserID, request)
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(http.StatusOK, preferences)
+	}
+}
+
+func (controller *NotificationController) currentUserID(c echo.Context) (uint, error) {
+	currentUserID := controller.authService.GetUserID(c)
+	logging.With(c, logging.UserID(currentUserID))
+	if currentUserID == 0 {
+		return 0, errUnauthorized()
+	}
+
+	return currentUserID, nil
+}
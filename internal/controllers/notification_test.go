@@ -0,0 +1,163 @@
+package controllers_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+
+	"github.com/ShukinDmitriy/gophermart/internal/controllers"
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+	"github.com/ShukinDmitriy/gophermart/internal/metrics"
+	"github.com/ShukinDmitriy/gophermart/internal/models"
+	"github.com/ShukinDmitriy/gophermart/internal/repositories/memory"
+	appservices "github.com/ShukinDmitriy/gophermart/internal/services"
+	"github.com/ShukinDmitriy/gophermart/internal/webhooktest"
+	"github.com/ShukinDmitriy/gophermart/mocks/internal_/auth"
+	"github.com/labstack/echo/v4"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+var _ = Describe("Notification", func() {
+	const userHeader = "X-Test-User"
+
+	var e *echo.Echo
+	var notificationService *appservices.NotificationService
+	var receiver *webhooktest.Receiver
+	var userID uint
+
+	request := func(userID uint, method string, target string, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set(userHeader, strconv.FormatUint(uint64(userID), 10))
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		return rec
+	}
+
+	getNotifications := func(target string) models.GetNotificationsResponse {
+		rec := request(userID, http.MethodGet, target, "")
+		Expect(rec.Code).To(Equal(http.StatusOK), rec.Body.String())
+
+		var res models.GetNotificationsResponse
+		Expect(json.Unmarshal(rec.Body.Bytes(), &res)).To(Succeed())
+
+		return res
+	}
+
+	BeforeEach(func() {
+		storage := memory.NewStorage()
+		user, err := storage.Users.Create(models.UserRegisterRequest{Login: "user1", Password: "password"})
+		Expect(err).NotTo(HaveOccurred())
+		userID = user.ID
+		authService := new(auth.AuthServiceInterface)
+		authService.EXPECT().GetUserID(mock.Anything).RunAndReturn(func(c echo.Context) uint {
+			userID, _ := strconv.ParseUint(c.Request().Header.Get(userHeader), 10, 64)
+			return uint(userID)
+		}).Maybe()
+		receiver = webhooktest.NewReceiver()
+		DeferCleanup(receiver.Close)
+		// Почта на сервере не настроена
+		notificationService = appservices.NewNotificationService(
+			appservices.NotificationOptions{MaxAttempts: 3, AllowPrivateNetworks: true},
+			storage.Notifications,
+			storage.Users,
+			map[entities.NotificationChannel]appservices.NotificationChannelInterface{
+				entities.NotificationChannelWebhook: appservices.NewWebhookNotificationChannel(http.DefaultClient),
+			},
+			metrics.New(),
+			zap.NewNop(),
+		)
+
+		e = echo.New()
+		e.HTTPErrorHandler = controllers.HTTPErrorHandler
+		controller := controllers.NewNotificationController(authService, notificationService)
+		e.GET("/notifications", controller.GetNotifications())
+		e.POST("/notifications/read", controller.MarkAllRead())
+		e.POST("/notifications/:id/read", controller.MarkRead())
+		e.GET("/notifications/preferences", controller.GetPreferences())
+		e.PUT("/notifications/preferences", controller.UpdatePreferences())
+	})
+
+	It("must list notifications and mark them read", func() {
+		// Arrange
+		for _, event := range []models.NotificationEvent{
+			{Type: entities.NotificationOrderInvalid, UserID: userID, Order: "12345678903"},
+			{Type: entities.NotificationOrderProcessed, UserID: userID, Order: "79927398713", Accrual: 729.98},
+		} {
+			notificationService.Notify(context.Background(), event)
+		}
+
+		// Act
+		all := getNotifications("/notifications")
+		marked := request(userID, http.MethodPost, "/notifications/"+strconv.FormatUint(uint64(all.Notifications[0].ID), 10)+"/read", "")
+		foreign := request(userID+1, http.MethodPost, "/notifications/"+strconv.FormatUint(uint64(all.Notifications[1].ID), 10)+"/read", "")
+		unread := getNotifications("/notifications?unread=true")
+		markedAll := request(userID, http.MethodPost, "/notifications/read", "")
+		afterAll := getNotifications("/notifications?unread=true")
+
+		// Assert
+		Expect(all.Unread).To(Equal(int64(2)))
+		Expect(all.Notifications).To(HaveLen(2))
+		Expect(all.Notifications[0].Type).To(Equal(entities.NotificationOrderProcessed))
+		Expect(all.Notifications[0].Body).To(ContainSubstring("729.98"))
+		Expect(marked.Code).To(Equal(http.StatusNoContent))
+		Expect(foreign.Code).To(Equal(http.StatusNotFound))
+		Expect(unread.Unread).To(Equal(int64(1)))
+		Expect(unread.Notifications).To(HaveLen(1))
+		Expect(unread.Notifications[0].Type).To(Equal(entities.NotificationOrderInvalid))
+		Expect(markedAll.Code).To(Equal(http.StatusNoContent))
+		Expect(afterAll.Unread).To(BeZero())
+		Expect(afterAll.Notifications).To(BeEmpty())
+	})
+
+	It("must deliver a signed webhook with the secret from preferences", func() {
+		// Arrange
+		rec := request(userID, http.MethodPut, "/notifications/preferences", `{"webhook_url":"`+receiver.URL+`"}`)
+		Expect(rec.Code).To(Equal(http.StatusOK), rec.Body.String())
+		var preferences models.NotificationPreferencesResponse
+		Expect(json.Unmarshal(rec.Body.Bytes(), &preferences)).To(Succeed())
+		notificationService.Notify(context.Background(), models.NotificationEvent{
+			Type:    entities.NotificationOrderProcessed,
+			UserID:  userID,
+			Order:   "12345678903",
+			Accrual: 500,
+		})
+
+		// Act
+		delivered := notificationService.DeliverPending(context.Background())
+		stored := request(userID, http.MethodGet, "/notifications/preferences", "")
+
+		// Assert
+		Expect(preferences.WebhookSecret).To(HaveLen(64))
+		Expect(delivered).To(Equal(1))
+		Expect(receiver.Deliveries()).To(HaveLen(1))
+		Expect(receiver.Deliveries()[0].Verify(preferences.WebhookSecret)).To(Succeed())
+		Expect(stored.Code).To(Equal(http.StatusOK))
+		Expect(stored.Body.String()).To(ContainSubstring(receiver.URL))
+		Expect(stored.Body.String()).NotTo(ContainSubstring("webhook_secret"))
+	})
+
+	It("must reject invalid preferences and unavailable channels", func() {
+		// Act
+		withoutEmail := request(userID, http.MethodPut, "/notifications/preferences", `{"email_enabled":true}`)
+		wrongURL := request(userID, http.MethodPut, "/notifications/preferences", `{"webhook_url":"ftp://example.com"}`)
+		noSMTP := request(userID, http.MethodPut, "/notifications/preferences", `{"email":"user1@example.com","email_enabled":true}`)
+		wrongID := request(userID, http.MethodPost, "/notifications/abc/read", "")
+		unauthorized := request(0, http.MethodGet, "/notifications", "")
+
+		// Assert
+		Expect(withoutEmail.Code).To(Equal(http.StatusBadRequest))
+		Expect(wrongURL.Code).To(Equal(http.StatusBadRequest))
+		Expect(noSMTP.Code).To(Equal(http.StatusUnprocessableEntity))
+		Expect(noSMTP.Body.String()).To(ContainSubstring("notification_channel_unavailable"))
+		Expect(wrongID.Code).To(Equal(http.StatusNotFound))
+		Expect(unauthorized.Code).To(Equal(http.StatusUnauthorized))
+	})
+})
@@ -0,0 +1,312 @@
+package controllers_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ShukinDmitriy/gophermart/internal/apierrors"
+	"github.com/ShukinDmitriy/gophermart/internal/controllers"
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+	"github.com/ShukinDmitriy/gophermart/internal/metrics"
+	"github.com/ShukinDmitriy/gophermart/internal/models"
+	"github.com/ShukinDmitriy/gophermart/internal/openapi"
+	"github.com/ShukinDmitriy/gophermart/internal/repositories/memory"
+	appservices "github.com/ShukinDmitriy/gophermart/internal/services"
+	"github.com/ShukinDmitriy/gophermart/mocks/internal_/auth"
+	"github.com/ShukinDmitriy/gophermart/mocks/internal_/repositories"
+	"github.com/ShukinDmitriy/gophermart/mocks/internal_/services"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/labstack/echo/v4"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// Все маршруты проходят через проверку запросов и ответов по спецификации:
+// если обработчик разойдётся со спецификацией, он ответит 500 и тест упадёт
+var _ = Describe("OpenAPI", func() {
+	var e *echo.Echo
+	var doc *openapi3.T
+	var authService *auth.AuthServiceInterface
+	var accountRepository *repositories.AccountRepositoryInterface
+	var operationRepository *repositories.OperationRepositoryInterface
+	var orderRepository *repositories.OrderRepositoryInterface
+	var userRepository *repositories.UserRepositoryInterface
+	var accrualService *services.AccrualServiceInterface
+	var orderEventBroker *services.OrderEventBrokerInterface
+	userID := uint(1)
+	orderNumber := "12345678903"
+	processedAt := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	account := &entities.Account{
+		Model: gorm.Model{
+			ID: 7,
+		},
+		Sum: 789.58,
+	}
+	order := &entities.Order{
+		Model: gorm.Model{
+			ID:        3,
+			CreatedAt: processedAt,
+		},
+		Number:  orderNumber,
+		UserID:  userID,
+		Status:  entities.OrderStatusProcessed,
+		Accrual: 500,
+	}
+	routeParam := regexp.MustCompile(`:(\w+)`)
+
+	request := func(method string, target string, contentType string, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		if contentType != "" {
+			req.Header.Set(echo.HeaderContentType, contentType)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		return rec
+	}
+
+	BeforeEach(func() {
+		authService = new(auth.AuthServiceInterface)
+		accountRepository = new(repositories.AccountRepositoryInterface)
+		operationRepository = new(repositories.OperationRepositoryInterface)
+		orderRepository = new(repositories.OrderRepositoryInterface)
+		orderRepository.EXPECT().WithContext(mock.Anything).Return(orderRepository).Maybe()
+		userRepository = new(repositories.UserRepositoryInterface)
+		accrualService = new(services.AccrualServiceInterface)
+		orderEventBroker = new(services.OrderEventBrokerInterface)
+		authService.EXPECT().GetUserID(mock.Anything).Return(userID)
+		accountRepository.EXPECT().FindByUserID(userID, entities.AccountTypeBonus).Return(account, nil)
+
+		var err error
+		doc, err = openapi.Load()
+		Expect(err).NotTo(HaveOccurred())
+
+		validator, err := openapi.NewValidator(doc, true)
+		Expect(err).NotTo(HaveOccurred())
+
+		webhookService := appservices.NewWebhookService(
+			appservices.WebhookOptions{MaxAttempts: 1},
+			memory.NewStorage().Webhooks,
+			http.DefaultClient,
+			metrics.New(),
+			zap.NewNop(),
+		)
+		ledgerService := appservices.NewLedgerService(accountRepository, operationRepository, orderRepository, webhookService, metrics.New())
+		orderService := appservices.NewOrderService(orderRepository, operationRepository, accrualService)
+
+		e = echo.New()
+		e.HTTPErrorHandler = controllers.HTTPErrorHandler
+		e.Use(validator)
+		controllers.RegisterRoutes(
+			e,
+			func(next echo.HandlerFunc) echo.HandlerFunc { return next },
+			controllers.NewBalanceController(authService, ledgerService),
+			controllers.NewOperationController(authService, ledgerService),
+			controllers.NewOrderController(authService, orderService, orderEventBroker, 3),
+			controllers.NewUserController(authService, appservices.NewUserService(userRepository)),
+			controllers.NewWebhookController(authService, webhookService),
+			controllers.NewNotificationController(authService, new(services.NotificationServiceInterface)),
+		)
+	})
+
+	It("should describe every registered route", func() {
+		for _, route := range e.Routes() {
+			pathItem := doc.Paths.Value(routeParam.ReplaceAllString(route.Path, "{$1}"))
+			Expect(pathItem).NotTo(BeNil(), route.Path)
+			Expect(pathItem.GetOperation(route.Method)).NotTo(BeNil(), route.Method+" "+route.Path)
+		}
+	})
+
+	It("should register every described route", func() {
+		registered := map[string]bool{}
+		for _, route := range e.Routes() {
+			registered[route.Method+" "+routeParam.ReplaceAllString(route.Path, "{$1}")] = true
+		}
+
+		for path, pathItem := range doc.Paths.Map() {
+			for method, operation := range pathItem.Operations() {
+				// Документацию регистрирует сам сервер
+				if len(operation.Tags) > 0 && operation.Tags[0] == "docs" {
+					continue
+				}
+				Expect(registered).To(HaveKey(method + " " + path))
+			}
+		}
+	})
+
+	It("should match the spec on user register", func() {
+		userRepository.EXPECT().FindBy(models.UserSearchFilter{Login: "user1"}).Return(nil, nil)
+		userRepository.EXPECT().Create(mock.Anything).Return(&models.UserInfoResponse{ID: userID, Login: "user1"}, nil)
+		authService.EXPECT().GenerateTokensAndSetCookies(mock.Anything, mock.Anything).Return(nil)
+
+		rec := request(http.MethodPost, "/api/user/register", echo.MIMEApplicationJSON, `{"login":"user1","password":"password"}`)
+
+		Expect(rec.Code).To(Equal(http.StatusOK), rec.Body.String())
+	})
+
+	It("should match the spec on login conflict and failure", func() {
+		password, _ := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+		userRepository.EXPECT().FindBy(models.UserSearchFilter{Login: "user1"}).Return(&entities.User{Login: "user1", Password: string(password)}, nil)
+
+		rec := request(http.MethodPost, "/api/user/register", echo.MIMEApplicationJSON, `{"login":"user1","password":"password"}`)
+		Expect(rec.Code).To(Equal(http.StatusConflict), rec.Body.String())
+
+		rec = request(http.MethodPost, "/api/user/login", echo.MIMEApplicationJSON, `{"login":"user1","password":"wrong1"}`)
+		Expect(rec.Code).To(Equal(http.StatusUnauthorized), rec.Body.String())
+	})
+
+	It("should match the spec on order upload", func() {
+		orderRepository.EXPECT().FindByNumber(orderNumber).Return(nil, nil)
+		orderRepository.EXPECT().Create(orderNumber, userID).Return(order, nil)
+		accrualService.EXPECT().SendOrderToQueue(mock.Anything, *order).Return()
+
+		rec := request(http.MethodPost, "/api/user/orders", echo.MIMETextPlain, orderNumber)
+		Expect(rec.Code).To(Equal(http.StatusAccepted), rec.Body.String())
+
+		rec = request(http.MethodPost, "/api/user/orders", echo.MIMETextPlain, "12345678900")
+		Expect(rec.Code).To(Equal(http.StatusUnprocessableEntity), rec.Body.String())
+
+		rec = request(http.MethodPost, "/api/user/orders", echo.MIMETextPlain, "abc")
+		Expect(rec.Code).To(Equal(http.StatusBadRequest), rec.Body.String())
+	})
+
+	It("should match the spec on batch upload", func() {
+		orderRepository.EXPECT().FindByNumbers([]string{orderNumber}).Return(nil, nil)
+		orderRepository.EXPECT().CreateBatch([]string{orderNumber}, userID).Return([]*entities.Order{order}, nil)
+		accrualService.EXPECT().TrySendOrderToQueue(mock.Anything, *order).Return(true)
+
+		rec := request(http.MethodPost, "/api/user/orders/batch", echo.MIMEApplicationJSON, `["12345678903", "abc"]`)
+		Expect(rec.Code).To(Equal(http.StatusAccepted), rec.Body.String())
+
+		rec = request(http.MethodPost, "/api/user/orders/batch", "text/csv", "1\n2\n3\n4\n")
+		Expect(rec.Code).To(Equal(http.StatusRequestEntityTooLarge), rec.Body.String())
+	})
+
+	It("should match the spec on orders list", func() {
+		orders := []*models.GetOrdersResponse{
+			{Number: orderNumber, Status: entities.OrderStatusProcessed, Accrual: 500, UploadedAt: models.JSONTime(processedAt)},
+			{Number: "9278923470", Status: entities.OrderStatusNew, UploadedAt: models.JSONTime(processedAt)},
+		}
+		orderRepository.EXPECT().GetOrdersPage(mock.Anything).Return(&models.GetOrdersPage{Orders: orders}, nil).Once()
+		orderRepository.EXPECT().GetOrdersPage(mock.Anything).Return(&models.GetOrdersPage{Orders: orders[:1], NextCursor: "next"}, nil).Once()
+
+		rec := request(http.MethodGet, "/api/user/orders", "", "")
+		Expect(rec.Code).To(Equal(http.StatusOK), rec.Body.String())
+
+		rec = request(http.MethodGet, "/api/user/orders?limit=1&status=PROCESSED,NEW&sort=desc", "", "")
+		Expect(rec.Code).To(Equal(http.StatusOK), rec.Body.String())
+		Expect(rec.Header().Get("Link")).To(ContainSubstring(`rel="next"`))
+	})
+
+	It("should match the spec on order details", func() {
+		orderRepository.EXPECT().FindByNumber(orderNumber).Return(order, nil)
+		orderRepository.EXPECT().FindByNumber("9278923470").Return(nil, nil)
+		orderRepository.EXPECT().GetStatusHistory(order.ID).Return([]*entities.OrderStatusHistory{
+			{OrderID: order.ID, Status: entities.OrderStatusNew},
+			{OrderID: order.ID, Status: entities.OrderStatusProcessed, Accrual: 500},
+		}, nil)
+		orderRepository.EXPECT().GetAccrualAttempts(orderNumber, 50).Return([]*entities.AccrualAttempt{
+			{OrderNumber: orderNumber, StatusCode: http.StatusTooManyRequests, Error: "rate limited"},
+			{OrderNumber: orderNumber, StatusCode: http.StatusOK, Status: "REGISTERED"},
+		}, nil)
+		operationRepository.EXPECT().GetOperationsByOrderNumber(orderNumber).Return([]*entities.Operation{
+			{Type: entities.OperationTypeAccrual, OrderNumber: orderNumber, Sum: 500, ProcessedAt: processedAt},
+		}, nil)
+
+		rec := request(http.MethodGet, "/api/user/orders/"+orderNumber, "", "")
+		Expect(rec.Code).To(Equal(http.StatusOK), rec.Body.String())
+
+		rec = request(http.MethodGet, "/api/user/orders/9278923470", "", "")
+		Expect(rec.Code).To(Equal(http.StatusNotFound), rec.Body.String())
+	})
+
+	It("should match the spec on balance and withdraw", func() {
+		operationRepository.EXPECT().GetWithdrawnByAccountID(account.ID).Return(42, nil)
+		orderRepository.EXPECT().FindByNumber(orderNumber).Return(nil, nil)
+		operationRepository.EXPECT().CreateWithdrawn(account.ID, orderNumber, float32(100)).Return(nil)
+		operationRepository.EXPECT().CreateWithdrawn(account.ID, orderNumber, float32(100000)).Return(appservices.ErrInsufficientFunds)
+
+		rec := request(http.MethodGet, "/api/user/balance", "", "")
+		Expect(rec.Code).To(Equal(http.StatusOK), rec.Body.String())
+
+		rec = request(http.MethodPost, "/api/user/balance/withdraw", echo.MIMEApplicationJSON, `{"order":"12345678903","sum":100}`)
+		Expect(rec.Code).To(Equal(http.StatusOK), rec.Body.String())
+
+		rec = request(http.MethodPost, "/api/user/balance/withdraw", echo.MIMEApplicationJSON, `{"order":"12345678903","sum":100000}`)
+		Expect(rec.Code).To(Equal(http.StatusPaymentRequired), rec.Body.String())
+	})
+
+	It("should match the spec on withdrawals and operations", func() {
+		at := models.JSONTime(processedAt)
+		operationRepository.EXPECT().GetWithdrawalsByAccountID(account.ID).Return([]models.GetWithdrawalsResponse{
+			{Order: orderNumber, Sum: 100, ProcessedAt: &at},
+		}, nil)
+		operationRepository.EXPECT().GetOperationsPage(mock.Anything).Return(&models.GetOperationsPage{
+			Operations: []models.GetOperationsResponse{
+				{Type: entities.OperationTypeAccrual, Order: orderNumber, Sum: 500, Balance: 500, ProcessedAt: at},
+				{Type: entities.OperationTypeWithdraw, Order: orderNumber, Sum: -100, Balance: 400, ProcessedAt: at},
+			},
+		}, nil)
+
+		rec := request(http.MethodGet, "/api/user/withdrawals", "", "")
+		Expect(rec.Code).To(Equal(http.StatusOK), rec.Body.String())
+
+		rec = request(http.MethodGet, "/api/user/operations?type=accrual&type=withdraw", "", "")
+		Expect(rec.Code).To(Equal(http.StatusOK), rec.Body.String())
+	})
+
+	It("should match the spec on webhooks", func() {
+		rec := request(http.MethodPost, "/api/user/webhooks", echo.MIMEApplicationJSON,
+			`{"url":"https://example.com/hook","events":["order.processed","balance.withdrawn"]}`)
+		Expect(rec.Code).To(Equal(http.StatusCreated), rec.Body.String())
+
+		rec = request(http.MethodGet, "/api/user/webhooks", "", "")
+		Expect(rec.Code).To(Equal(http.StatusOK), rec.Body.String())
+
+		rec = request(http.MethodGet, "/api/user/webhooks/1/deliveries?limit=10&status=pending&status=failed", "", "")
+		Expect(rec.Code).To(Equal(http.StatusOK), rec.Body.String())
+
+		rec = request(http.MethodDelete, "/api/user/webhooks/1", "", "")
+		Expect(rec.Code).To(Equal(http.StatusNoContent), rec.Body.String())
+
+		rec = request(http.MethodDelete, "/api/user/webhooks/1", "", "")
+		Expect(rec.Code).To(Equal(http.StatusNotFound), rec.Body.String())
+
+		rec = request(http.MethodPost, "/api/user/webhooks", echo.MIMEApplicationJSON, `{"url":"https://example.com/hook","events":[]}`)
+		Expect(rec.Code).To(Equal(http.StatusBadRequest), rec.Body.String())
+	})
+
+	It("should match the spec on internal errors", func() {
+		operationRepository.EXPECT().GetWithdrawnByAccountID(account.ID).Return(0, errors.New("test error"))
+
+		rec := request(http.MethodGet, "/api/user/balance", "", "")
+
+		Expect(rec.Code).To(Equal(http.StatusInternalServerError))
+		problem := &models.Problem{}
+		err := json.Unmarshal(rec.Body.Bytes(), problem)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(problem.Detail).To(Equal("internal gophermart error"))
+	})
+
+	It("should reject a request that does not match the spec", func() {
+		rec := request(http.MethodGet, "/api/user/withdrawals?limit=0", "", "")
+
+		Expect(rec.Code).To(Equal(http.StatusBadRequest))
+		Expect(rec.Header().Get(echo.HeaderContentType)).To(Equal(models.MIMEApplicationProblemJSON))
+
+		problem := &models.Problem{}
+		err := json.Unmarshal(rec.Body.Bytes(), problem)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(problem.Code).To(Equal(apierrors.ErrorCodeValidationFailed))
+		Expect(problem.Errors).To(HaveKeyWithValue("limit", HaveKeyWithValue("minimum", true)))
+	})
+})
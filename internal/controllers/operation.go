@@ -4,30 +4,24 @@ import (
 	"net/http"
 
 	"github.com/ShukinDmitriy/gophermart/internal/auth"
-	"github.com/ShukinDmitriy/gophermart/internal/entities"
+	"github.com/ShukinDmitriy/gophermart/internal/logging"
 	"github.com/ShukinDmitriy/gophermart/internal/models"
-	"github.com/ShukinDmitriy/gophermart/internal/repositories"
+	"github.com/ShukinDmitriy/gophermart/internal/services"
 	"github.com/labstack/echo/v4"
 )
 
 type OperationController struct {
-	authService         auth.AuthServiceInterface
-	accountRepository   repositories.AccountRepositoryInterface
-	operationRepository repositories.OperationRepositoryInterface
-	orderRepository     repositories.OrderRepositoryInterface
+	authService   auth.AuthServiceInterface
+	ledgerService services.LedgerServiceInterface
 }
 
 func NewOperationController(
 	authService auth.AuthServiceInterface,
-	accountRepository repositories.AccountRepositoryInterface,
-	operationRepository repositories.OperationRepositoryInterface,
-	orderRepository repositories.OrderRepositoryInterface,
+	ledgerService services.LedgerServiceInterface,
 ) *OperationController {
 	return &OperationController{
-		authService:         authService,
-		accountRepository:   accountRepository,
-		operationRepository: operationRepository,
-		orderRepository:     orderRepository,
+		authService:   authService,
+		ledgerService: ledgerService,
 	}
 }
 
@@ -37,65 +31,84 @@ func (controller *OperationController) CreateWithdraw() echo.HandlerFunc {
 		var createWithdrawRequest models.CreateWithdrawRequest
 		err := c.Bind(&createWithdrawRequest)
 		if err != nil {
-			c.Logger().Error(err)
-			return c.JSON(http.StatusInternalServerError, nil)
+			return errBadRequest("invalid request body", err)
 		}
 
 		currentUserID := controller.authService.GetUserID(c)
+		logging.With(c, logging.UserID(currentUserID))
+		withOrderNumber(c, createWithdrawRequest.Order)
 		if currentUserID == 0 {
-			c.Logger().Error("Unauthorized user create withdraw")
-			return c.JSON(http.StatusUnauthorized, nil)
+			return errUnauthorized()
 		}
 
-		bonusAccount, err := controller.accountRepository.FindByUserID(currentUserID, entities.AccountTypeBonus)
-		if err != nil || bonusAccount == nil {
-			c.Logger().Error("Can't find bonus account", err)
-			return c.JSON(http.StatusInternalServerError, nil)
+		err = controller.ledgerService.Withdraw(currentUserID, createWithdrawRequest)
+		if err != nil {
+			return err
 		}
-		if bonusAccount.Sum < createWithdrawRequest.Sum {
-			c.Logger().Error("Balance error")
-			return c.JSON(http.StatusPaymentRequired, nil)
+
+		return c.JSON(http.StatusOK, nil)
+	}
+}
+
+// GetWithdrawals Получение информации о выводе средств.
+// Без параметров возвращает все списания пользователя. С параметрами limit, cursor, order,
+// processed_from, processed_to, sort возвращает страницу, а курсор следующей страницы
+// передаёт в заголовке Link.
+func (controller *OperationController) GetWithdrawals() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var getWithdrawalsRequest models.GetWithdrawalsRequest
+		err := c.Bind(&getWithdrawalsRequest)
+		if err != nil {
+			return errBadRequest("invalid query parameters", err)
 		}
 
-		order, err := controller.orderRepository.FindByNumber(createWithdrawRequest.Order)
-		if err != nil || (order != nil && order.UserID != currentUserID) {
-			c.Logger().Error("Can't find order", createWithdrawRequest.Order, currentUserID, err)
-			return c.JSON(http.StatusUnprocessableEntity, nil)
+		currentUserID := controller.authService.GetUserID(c)
+		logging.With(c, logging.UserID(currentUserID))
+		if currentUserID == 0 {
+			return errUnauthorized()
 		}
 
-		err = controller.operationRepository.CreateWithdrawn(bonusAccount.ID, createWithdrawRequest.Order, createWithdrawRequest.Sum)
+		page, err := controller.ledgerService.ListWithdrawals(currentUserID, getWithdrawalsRequest)
 		if err != nil {
-			return c.JSON(http.StatusInternalServerError, nil)
+			return err
 		}
 
-		return c.JSON(http.StatusOK, nil)
+		setNextPageLink(c, page.NextCursor)
+
+		if len(page.Withdrawals) == 0 {
+			return c.NoContent(http.StatusNoContent)
+		}
+
+		return c.JSON(http.StatusOK, page.Withdrawals)
 	}
 }
 
-// GetWithdrawals Получение информации о выводе средств
-func (controller *OperationController) GetWithdrawals() echo.HandlerFunc {
+// GetOperations Получение всех движений по счёту с остатком после каждой операции
+func (controller *OperationController) GetOperations() echo.HandlerFunc {
 	return func(c echo.Context) error {
-		currentUserID := controller.authService.GetUserID(c)
-		if currentUserID == 0 {
-			c.Logger().Error("Unauthorized user create withdraw")
-			return c.JSON(http.StatusUnauthorized, nil)
+		var getOperationsRequest models.GetOperationsRequest
+		err := c.Bind(&getOperationsRequest)
+		if err != nil {
+			return errBadRequest("invalid query parameters", err)
 		}
 
-		bonusAccount, err := controller.accountRepository.FindByUserID(currentUserID, entities.AccountTypeBonus)
-		if err != nil || bonusAccount == nil {
-			return c.JSON(http.StatusInternalServerError, nil)
+		currentUserID := controller.authService.GetUserID(c)
+		logging.With(c, logging.UserID(currentUserID))
+		if currentUserID == 0 {
+			return errUnauthorized()
 		}
 
-		operations, err := controller.operationRepository.GetWithdrawalsByAccountID(bonusAccount.ID)
+		page, err := controller.ledgerService.ListOperations(currentUserID, getOperationsRequest)
 		if err != nil {
-			c.Logger().Error(err)
-			return c.JSON(http.StatusInternalServerError, nil)
+			return err
 		}
 
-		if len(operations) == 0 {
-			return c.JSON(http.StatusNoContent, nil)
+		setNextPageLink(c, page.NextCursor)
+
+		if len(page.Operations) == 0 {
+			return c.NoContent(http.StatusNoContent)
 		}
 
-		return c.JSON(http.StatusOK, operations)
+		return c.JSON(http.StatusOK, page.Operations)
 	}
 }
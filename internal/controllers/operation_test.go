@@ -9,12 +9,16 @@ import (
 
 	"github.com/ShukinDmitriy/gophermart/internal/controllers"
 	"github.com/ShukinDmitriy/gophermart/internal/entities"
+	"github.com/ShukinDmitriy/gophermart/internal/metrics"
 	"github.com/ShukinDmitriy/gophermart/internal/models"
+	appservices "github.com/ShukinDmitriy/gophermart/internal/services"
 	"github.com/ShukinDmitriy/gophermart/mocks/internal_/auth"
 	"github.com/ShukinDmitriy/gophermart/mocks/internal_/repositories"
+	"github.com/ShukinDmitriy/gophermart/mocks/internal_/services"
 	"github.com/labstack/echo/v4"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
 	"gorm.io/gorm"
 )
 
@@ -52,11 +56,17 @@ var _ = Describe("Operation", func() {
 		accountRepository = new(repositories.AccountRepositoryInterface)
 		operationRepository = new(repositories.OperationRepositoryInterface)
 		orderRepository = new(repositories.OrderRepositoryInterface)
+		webhookService := new(services.WebhookServiceInterface)
+		webhookService.EXPECT().Emit(mock.Anything, mock.Anything).Return().Maybe()
 		controller = controllers.NewOperationController(
 			authService,
-			accountRepository,
-			operationRepository,
-			orderRepository,
+			appservices.NewLedgerService(
+				accountRepository,
+				operationRepository,
+				orderRepository,
+				webhookService,
+				metrics.New(),
+			),
 		)
 	})
 
@@ -72,10 +82,9 @@ var _ = Describe("Operation", func() {
 			operationRepository.EXPECT().CreateWithdrawn(account.ID, createWithdrawRequest.Order, createWithdrawRequest.Sum).Return(nil)
 
 			// Act
-			err := controller.CreateWithdraw()(c)
+			serve(c, controller.CreateWithdraw())
 
 			// Assertions
-			Expect(err).NotTo(HaveOccurred())
 			Expect(rec.Code).To(Equal(http.StatusOK))
 		})
 
@@ -87,10 +96,9 @@ var _ = Describe("Operation", func() {
 			authService.EXPECT().GetUserID(c).Return(0)
 
 			// Act
-			err := controller.CreateWithdraw()(c)
+			serve(c, controller.CreateWithdraw())
 
 			// Assertions
-			Expect(err).NotTo(HaveOccurred())
 			Expect(rec.Code).To(Equal(http.StatusUnauthorized))
 		})
 
@@ -103,10 +111,9 @@ var _ = Describe("Operation", func() {
 			accountRepository.EXPECT().FindByUserID(userID, entities.AccountTypeBonus).Return(nil, nil)
 
 			// Act
-			err := controller.CreateWithdraw()(c)
+			serve(c, controller.CreateWithdraw())
 
 			// Assertions
-			Expect(err).NotTo(HaveOccurred())
 			Expect(rec.Code).To(Equal(http.StatusInternalServerError))
 		})
 
@@ -122,12 +129,13 @@ var _ = Describe("Operation", func() {
 				},
 				Sum: 100,
 			}, nil)
+			orderRepository.EXPECT().FindByNumber(createWithdrawRequest.Order).Return(nil, nil)
+			operationRepository.EXPECT().CreateWithdrawn(uint(7), createWithdrawRequest.Order, createWithdrawRequest.Sum).Return(appservices.ErrInsufficientFunds)
 
 			// Act
-			err := controller.CreateWithdraw()(c)
+			serve(c, controller.CreateWithdraw())
 
 			// Assertions
-			Expect(err).NotTo(HaveOccurred())
 			Expect(rec.Code).To(Equal(http.StatusPaymentRequired))
 		})
 
@@ -143,10 +151,9 @@ var _ = Describe("Operation", func() {
 			}, nil)
 
 			// Act
-			err := controller.CreateWithdraw()(c)
+			serve(c, controller.CreateWithdraw())
 
 			// Assertions
-			Expect(err).NotTo(HaveOccurred())
 			Expect(rec.Code).To(Equal(http.StatusUnprocessableEntity))
 		})
 
@@ -161,10 +168,9 @@ var _ = Describe("Operation", func() {
 			operationRepository.EXPECT().CreateWithdrawn(account.ID, createWithdrawRequest.Order, createWithdrawRequest.Sum).Return(errors.New("test error"))
 
 			// Act
-			err := controller.CreateWithdraw()(c)
+			serve(c, controller.CreateWithdraw())
 
 			// Assertions
-			Expect(err).NotTo(HaveOccurred())
 			Expect(rec.Code).To(Equal(http.StatusInternalServerError))
 		})
 	})
@@ -180,14 +186,13 @@ var _ = Describe("Operation", func() {
 			operationRepository.EXPECT().GetWithdrawalsByAccountID(account.ID).Return(withdrawals, nil)
 
 			// Act
-			err := controller.GetWithdrawals()(c)
+			serve(c, controller.GetWithdrawals())
 
 			// Assertions
-			Expect(err).NotTo(HaveOccurred())
 			Expect(rec.Code).To(Equal(http.StatusOK))
 
 			var resJ []models.GetWithdrawalsResponse
-			err = json.Unmarshal(rec.Body.Bytes(), &resJ)
+			err := json.Unmarshal(rec.Body.Bytes(), &resJ)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(len(resJ)).To(Equal(len(withdrawals)))
 		})
@@ -202,13 +207,50 @@ var _ = Describe("Operation", func() {
 			operationRepository.EXPECT().GetWithdrawalsByAccountID(account.ID).Return([]models.GetWithdrawalsResponse{}, nil)
 
 			// Act
-			err := controller.GetWithdrawals()(c)
+			serve(c, controller.GetWithdrawals())
 
 			// Assertions
-			Expect(err).NotTo(HaveOccurred())
 			Expect(rec.Code).To(Equal(http.StatusNoContent))
 		})
 
+		It("should return a page of withdrawals if pagination parameters are passed", func() {
+			// Arrange
+			req := httptest.NewRequest(http.MethodGet, "/api/user/withdrawals?limit=3&order=12345678903", nil)
+			c = e.NewContext(req, rec)
+			authService.EXPECT().GetUserID(c).Return(userID)
+			accountRepository.EXPECT().FindByUserID(userID, entities.AccountTypeBonus).Return(account, nil)
+			operationRepository.EXPECT().GetWithdrawalsPage(models.OperationSearchFilter{
+				AccountID: account.ID,
+				Types:     []entities.OperationType{entities.OperationTypeWithdraw},
+				Order:     "12345678903",
+				Sort:      models.SortDirectionDesc,
+				Limit:     3,
+			}).Return(&models.GetWithdrawalsPage{
+				Withdrawals: withdrawals,
+				NextCursor:  "next",
+			}, nil)
+
+			// Act
+			serve(c, controller.GetWithdrawals())
+
+			// Assertions
+			Expect(rec.Code).To(Equal(http.StatusOK))
+			Expect(rec.Header().Get("Link")).To(Equal(`</api/user/withdrawals?cursor=next&limit=3&order=12345678903>; rel="next"`))
+		})
+
+		It("should return an error if the pagination parameters are invalid", func() {
+			// Arrange
+			req := httptest.NewRequest(http.MethodGet, "/api/user/withdrawals?limit=0&processed_from=yesterday", nil)
+			c = e.NewContext(req, rec)
+			authService.EXPECT().GetUserID(c).Return(userID)
+
+			// Act
+			serve(c, controller.GetWithdrawals())
+
+			// Assertions
+			Expect(rec.Code).To(Equal(http.StatusBadRequest))
+		})
+
 		It("should return an error if the user is not found", func() {
 			// Arrange
 			req := httptest.NewRequest(http.MethodPost, "/", nil)
@@ -217,10 +259,9 @@ var _ = Describe("Operation", func() {
 			authService.EXPECT().GetUserID(c).Return(0)
 
 			// Act
-			err := controller.GetWithdrawals()(c)
+			serve(c, controller.GetWithdrawals())
 
 			// Assertions
-			Expect(err).NotTo(HaveOccurred())
 			Expect(rec.Code).To(Equal(http.StatusUnauthorized))
 		})
 
@@ -233,10 +274,9 @@ var _ = Describe("Operation", func() {
 			accountRepository.EXPECT().FindByUserID(userID, entities.AccountTypeBonus).Return(nil, nil)
 
 			// Act
-			err := controller.GetWithdrawals()(c)
+			serve(c, controller.GetWithdrawals())
 
 			// Assertions
-			Expect(err).NotTo(HaveOccurred())
 			Expect(rec.Code).To(Equal(http.StatusInternalServerError))
 		})
 
@@ -250,10 +290,104 @@ var _ = Describe("Operation", func() {
 			operationRepository.EXPECT().GetWithdrawalsByAccountID(account.ID).Return(withdrawals, errors.New("test error"))
 
 			// Act
-			err := controller.GetWithdrawals()(c)
+			serve(c, controller.GetWithdrawals())
+
+			// Assertions
+			Expect(rec.Code).To(Equal(http.StatusInternalServerError))
+		})
+	})
+
+	Describe("GetOperations", func() {
+		operations := []models.GetOperationsResponse{
+			{Type: entities.OperationTypeAccrual, Sum: 500, Balance: 500},
+			{Type: entities.OperationTypeWithdraw, Sum: -100, Balance: 400},
+		}
+
+		It("should return the operations with the running balance", func() {
+			// Arrange
+			req := httptest.NewRequest(http.MethodGet, "/api/user/operations", nil)
+			c = e.NewContext(req, rec)
+			authService.EXPECT().GetUserID(c).Return(userID)
+			accountRepository.EXPECT().FindByUserID(userID, entities.AccountTypeBonus).Return(account, nil)
+			operationRepository.EXPECT().GetOperationsPage(models.OperationSearchFilter{
+				AccountID: account.ID,
+				Sort:      models.SortDirectionAsc,
+				Limit:     models.GetOperationsDefaultLimit,
+			}).Return(&models.GetOperationsPage{Operations: operations}, nil)
+
+			// Act
+			serve(c, controller.GetOperations())
 
 			// Assertions
+			Expect(rec.Code).To(Equal(http.StatusOK))
+
+			var resJ []models.GetOperationsResponse
+			err := json.Unmarshal(rec.Body.Bytes(), &resJ)
 			Expect(err).NotTo(HaveOccurred())
+			Expect(resJ).To(HaveLen(2))
+			Expect(resJ[1].Balance).To(Equal(float32(400)))
+		})
+
+		It("should filter the operations by type", func() {
+			// Arrange
+			req := httptest.NewRequest(http.MethodGet, "/api/user/operations?type=accrual", nil)
+			c = e.NewContext(req, rec)
+			authService.EXPECT().GetUserID(c).Return(userID)
+			accountRepository.EXPECT().FindByUserID(userID, entities.AccountTypeBonus).Return(account, nil)
+			operationRepository.EXPECT().GetOperationsPage(models.OperationSearchFilter{
+				AccountID: account.ID,
+				Types:     []entities.OperationType{entities.OperationTypeAccrual},
+				Sort:      models.SortDirectionAsc,
+				Limit:     models.GetOperationsDefaultLimit,
+			}).Return(&models.GetOperationsPage{Operations: operations[:1]}, nil)
+
+			// Act
+			serve(c, controller.GetOperations())
+
+			// Assertions
+			Expect(rec.Code).To(Equal(http.StatusOK))
+		})
+
+		It("should return status no content if there are no operations", func() {
+			// Arrange
+			req := httptest.NewRequest(http.MethodGet, "/api/user/operations", nil)
+			c = e.NewContext(req, rec)
+			authService.EXPECT().GetUserID(c).Return(userID)
+			accountRepository.EXPECT().FindByUserID(userID, entities.AccountTypeBonus).Return(account, nil)
+			operationRepository.EXPECT().GetOperationsPage(mock.Anything).Return(&models.GetOperationsPage{}, nil)
+
+			// Act
+			serve(c, controller.GetOperations())
+
+			// Assertions
+			Expect(rec.Code).To(Equal(http.StatusNoContent))
+		})
+
+		It("should return an error if the operation type is unknown", func() {
+			// Arrange
+			req := httptest.NewRequest(http.MethodGet, "/api/user/operations?type=refund", nil)
+			c = e.NewContext(req, rec)
+			authService.EXPECT().GetUserID(c).Return(userID)
+
+			// Act
+			serve(c, controller.GetOperations())
+
+			// Assertions
+			Expect(rec.Code).To(Equal(http.StatusBadRequest))
+		})
+
+		It("should return an error if the operations could not be received", func() {
+			// Arrange
+			req := httptest.NewRequest(http.MethodGet, "/api/user/operations", nil)
+			c = e.NewContext(req, rec)
+			authService.EXPECT().GetUserID(c).Return(userID)
+			accountRepository.EXPECT().FindByUserID(userID, entities.AccountTypeBonus).Return(account, nil)
+			operationRepository.EXPECT().GetOperationsPage(mock.Anything).Return(nil, errors.New("test error"))
+
+			// Act
+			serve(c, controller.GetOperations())
+
+			// Assertions
 			Expect(rec.Code).To(Equal(http.StatusInternalServerError))
 		})
 	})
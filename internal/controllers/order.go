@@ -1,32 +1,53 @@
 package controllers
 
 import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
+	"mime"
 	"net/http"
-	"strconv"
+	"strings"
+	"time"
 
+	"github.com/ShukinDmitriy/gophermart/internal/apierrors"
 	"github.com/ShukinDmitriy/gophermart/internal/auth"
-	"github.com/ShukinDmitriy/gophermart/internal/repositories"
+	"github.com/ShukinDmitriy/gophermart/internal/logging"
+	"github.com/ShukinDmitriy/gophermart/internal/models"
 	"github.com/ShukinDmitriy/gophermart/internal/services"
 	"github.com/labstack/echo/v4"
-	"github.com/theplant/luhn"
+	"go.uber.org/zap"
 )
 
+// orderStreamHeartbeat Интервал комментариев-пингов, чтобы прокси не закрывали простаивающее соединение
+const orderStreamHeartbeat = 15 * time.Second
+
+// maxOrderLineSize Предельный размер одного номера в пакете вместе с кавычками, разделителями и прочими колонками CSV
+const maxOrderLineSize = 64
+
+// errOrderBatchTooLarge Номеров в пакете больше лимита, дальше тело не читается
+var errOrderBatchTooLarge = errors.New("too many order numbers")
+
 type OrderController struct {
-	authService     auth.AuthServiceInterface
-	orderRepository repositories.OrderRepositoryInterface
-	accrualService  services.AccrualServiceInterface
+	authService      auth.AuthServiceInterface
+	orderService     services.OrderServiceInterface
+	orderEventBroker services.OrderEventBrokerInterface
+	orderBatchLimit  int
 }
 
 func NewOrderController(
 	authService auth.AuthServiceInterface,
-	orderRepository repositories.OrderRepositoryInterface,
-	accrualService services.AccrualServiceInterface,
+	orderService services.OrderServiceInterface,
+	orderEventBroker services.OrderEventBrokerInterface,
+	orderBatchLimit int,
 ) *OrderController {
 	return &OrderController{
-		authService:     authService,
-		orderRepository: orderRepository,
-		accrualService:  accrualService,
+		authService:      authService,
+		orderService:     orderService,
+		orderEventBroker: orderEventBroker,
+		orderBatchLimit:  orderBatchLimit,
 	}
 }
 
@@ -36,58 +57,267 @@ func (controller *OrderController) CreateOrder() echo.HandlerFunc {
 		body, err := io.ReadAll(c.Request().Body)
 		defer c.Request().Body.Close()
 		if err != nil {
-			return c.JSON(http.StatusInternalServerError, nil)
+			return errBadRequest("can't read request body", err)
 		}
 
-		orderNumberString := string(body)
-		orderNumber, err := strconv.Atoi(orderNumberString)
+		currentUserID := controller.authService.GetUserID(c)
+		logging.With(c, logging.UserID(currentUserID))
+		withOrderNumber(c, string(body))
+
+		created, err := controller.orderService.UploadOrder(c.Request().Context(), currentUserID, string(body))
 		if err != nil {
-			return c.JSON(http.StatusInternalServerError, nil)
+			return err
 		}
 
-		if !luhn.Valid(orderNumber) {
-			return c.JSON(http.StatusUnprocessableEntity, nil)
+		if !created {
+			return c.JSON(http.StatusOK, nil)
 		}
 
-		existOrder, err := controller.orderRepository.FindByNumber(orderNumberString)
+		return c.JSON(http.StatusAccepted, nil)
+	}
+}
+
+// CreateOrdersBatch Загрузка пакета номеров заказов.
+// Принимает JSON-массив, номера по одному на строку (text/plain) или CSV с номером в первой колонке.
+// Корректные новые номера создаются одной транзакцией, для каждого номера возвращается результат.
+func (controller *OrderController) CreateOrdersBatch() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		req := c.Request()
+		req.Body = http.MaxBytesReader(c.Response(), req.Body, int64(controller.orderBatchLimit)*maxOrderLineSize)
+
+		numbers, err := parseOrderNumbers(req, controller.orderBatchLimit)
+		var maxBytesErr *http.MaxBytesError
+		if errors.Is(err, errOrderBatchTooLarge) || errors.As(err, &maxBytesErr) {
+			return apierrors.NewAPIError(
+				http.StatusRequestEntityTooLarge,
+				apierrors.ErrorCodePayloadTooLarge,
+				fmt.Sprintf("batch exceeds the limit of %d order numbers", controller.orderBatchLimit),
+			)
+		}
 		if err != nil {
-			return c.JSON(http.StatusInternalServerError, nil)
+			return errBadRequest("can't parse order numbers", err)
+		}
+
+		if len(numbers) == 0 {
+			return errBadRequest("no order numbers in request", nil)
 		}
 
 		currentUserID := controller.authService.GetUserID(c)
-		if existOrder != nil {
-			if existOrder.UserID == currentUserID {
-				return c.JSON(http.StatusOK, nil)
+		logging.With(c, logging.UserID(currentUserID))
+
+		results, err := controller.orderService.UploadOrders(c.Request().Context(), currentUserID, numbers)
+		if err != nil {
+			return err
+		}
+
+		status := http.StatusOK
+		for _, result := range results {
+			if result.Result == models.CreateOrdersBatchResultAccepted {
+				status = http.StatusAccepted
+				break
 			}
+		}
+
+		return c.JSON(status, results)
+	}
+}
 
-			return c.JSON(http.StatusConflict, nil)
+// GetOrders Получение списка загруженных номеров заказов.
+// Возвращает страницу заказов, по умолчанию от самых новых к самым старым. Параметры limit, cursor,
+// status, uploaded_from, uploaded_to, sort задают страницу, курсор следующей страницы
+// передаётся в заголовке Link.
+func (controller *OrderController) GetOrders() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var getOrdersRequest models.GetOrdersRequest
+		err := c.Bind(&getOrdersRequest)
+		if err != nil {
+			return errBadRequest("invalid query parameters", err)
 		}
 
-		order, err := controller.orderRepository.Create(orderNumberString, currentUserID)
+		currentUserID := controller.authService.GetUserID(c)
+		logging.With(c, logging.UserID(currentUserID))
+
+		page, err := controller.orderService.ListOrders(currentUserID, getOrdersRequest)
 		if err != nil {
-			return c.JSON(http.StatusInternalServerError, nil)
+			return err
 		}
 
-		controller.accrualService.SendOrderToQueue(*order)
+		setNextPageLink(c, page.NextCursor)
 
-		return c.JSON(http.StatusAccepted, nil)
+		if len(page.Orders) == 0 {
+			return c.NoContent(http.StatusNoContent)
+		}
+
+		return c.JSON(http.StatusOK, page.Orders)
 	}
 }
 
-// GetOrders Получение списка загруженных номеров заказов
-func (controller *OrderController) GetOrders() echo.HandlerFunc {
+// GetOrder Получение заказа с историей статусов, обращениями к системе расчёта и операциями по счёту
+func (controller *OrderController) GetOrder() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		orderNumber := c.Param("number")
+		currentUserID := controller.authService.GetUserID(c)
+		logging.With(c, logging.UserID(currentUserID))
+		withOrderNumber(c, orderNumber)
+
+		order, err := controller.orderService.GetOrder(currentUserID, orderNumber)
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(http.StatusOK, order)
+	}
+}
+
+// StreamOrders Поток изменений статусов заказов пользователя (Server-Sent Events)
+func (controller *OrderController) StreamOrders() echo.HandlerFunc {
 	return func(c echo.Context) error {
 		currentUserID := controller.authService.GetUserID(c)
+		logging.With(c, logging.UserID(currentUserID))
+		if currentUserID == 0 {
+			return errUnauthorized()
+		}
+
+		events, unsubscribe := controller.orderEventBroker.Subscribe(currentUserID)
+		defer unsubscribe()
+
+		res := c.Response()
+		res.Header().Set(echo.HeaderContentType, "text/event-stream")
+		res.Header().Set(echo.HeaderCacheControl, "no-cache")
+		res.Header().Set(echo.HeaderConnection, "keep-alive")
+		res.Header().Set("X-Accel-Buffering", "no")
+		res.WriteHeader(http.StatusOK)
+		res.Flush()
+
+		heartbeat := time.NewTicker(orderStreamHeartbeat)
+		defer heartbeat.Stop()
+
+		for {
+			select {
+			case <-c.Request().Context().Done():
+				return nil
+			case <-heartbeat.C:
+				if _, err := fmt.Fprint(res, ": ping\n\n"); err != nil {
+					return nil
+				}
+				res.Flush()
+			case event, ok := <-events:
+				if !ok {
+					return nil
+				}
+
+				data, err := json.Marshal(event)
+				if err != nil {
+					logging.FromContext(c).Error("cannot encode order event", zap.Error(err))
+					continue
+				}
+
+				if _, err = fmt.Fprintf(res, "event: %s\ndata: %s\n\n", event.Type, data); err != nil {
+					return nil
+				}
+				res.Flush()
+			}
+		}
+	}
+}
+
+// withOrderNumber Номер заказа попадает в лог запроса только после проверки: до неё это произвольная
+// строка от клиента любой длины, в том числе с переводами строк и чужими данными
+func withOrderNumber(c echo.Context, number string) {
+	if services.CheckOrderNumber(number) == nil {
+		logging.With(c, logging.OrderNumber(number))
+	}
+}
+
+// parseOrderNumbers Номера заказов из тела запроса. Тело читается по одному номеру,
+// чтение прекращается, как только номеров становится больше limit
+func parseOrderNumbers(req *http.Request, limit int) ([]string, error) {
+	defer req.Body.Close()
+
+	var numbers []string
+	add := func(number string) error {
+		if len(numbers) == limit {
+			return errOrderBatchTooLarge
+		}
+		numbers = append(numbers, number)
+
+		return nil
+	}
+
+	contentType, _, _ := mime.ParseMediaType(req.Header.Get(echo.HeaderContentType))
 
-		orders, err := controller.orderRepository.GetOrdersByUserID(currentUserID)
+	switch contentType {
+	case echo.MIMEApplicationJSON:
+		decoder := json.NewDecoder(req.Body)
+		token, err := decoder.Token()
 		if err != nil {
-			return c.JSON(http.StatusInternalServerError, nil)
+			return nil, err
 		}
+		if delim, ok := token.(json.Delim); !ok || delim != '[' {
+			return nil, errors.New("order numbers must be a JSON array")
+		}
+
+		for decoder.More() {
+			var value json.RawMessage
+			if err = decoder.Decode(&value); err != nil {
+				return nil, err
+			}
+
+			var number string
+			if err = json.Unmarshal(value, &number); err != nil {
+				// Номер передан числом, а не строкой
+				number = string(value)
+			}
+			if err = add(strings.TrimSpace(number)); err != nil {
+				return nil, err
+			}
+		}
+
+		if _, err = decoder.Token(); err != nil {
+			return nil, err
+		}
+
+		return numbers, nil
+	case "text/csv":
+		reader := csv.NewReader(req.Body)
+		reader.FieldsPerRecord = -1
+		reader.TrimLeadingSpace = true
 
-		if len(orders) == 0 {
-			return c.JSON(http.StatusNoContent, nil)
+		for i := 0; ; i++ {
+			record, err := reader.Read()
+			if errors.Is(err, io.EOF) {
+				return numbers, nil
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			if len(record) == 0 {
+				continue
+			}
+			number := strings.TrimSpace(record[0])
+			if i == 0 && strings.EqualFold(number, "number") {
+				continue
+			}
+			if err = add(number); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		scanner := bufio.NewScanner(req.Body)
+		for scanner.Scan() {
+			number := strings.TrimSpace(scanner.Text())
+			if number == "" {
+				continue
+			}
+			if err := add(number); err != nil {
+				return nil, err
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, err
 		}
 
-		return c.JSON(http.StatusOK, orders)
+		return numbers, nil
 	}
 }
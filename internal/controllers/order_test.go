@@ -1,18 +1,24 @@
 package controllers_test
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"testing/iotest"
 
+	"github.com/ShukinDmitriy/gophermart/internal/apierrors"
+	"github.com/ShukinDmitriy/gophermart/internal/logging"
 	"github.com/ShukinDmitriy/gophermart/internal/models"
 
 	//"encoding/json"
 	//"errors"
 	"github.com/ShukinDmitriy/gophermart/internal/controllers"
 	"github.com/ShukinDmitriy/gophermart/internal/entities"
+	appservices "github.com/ShukinDmitriy/gophermart/internal/services"
 	//"github.com/ShukinDmitriy/gophermart/internal/models"
 	"github.com/ShukinDmitriy/gophermart/mocks/internal_/auth"
 	"github.com/ShukinDmitriy/gophermart/mocks/internal_/repositories"
@@ -20,7 +26,10 @@ import (
 	"github.com/labstack/echo/v4"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
-	//"gorm.io/gorm"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+	"gorm.io/gorm"
 	//"strconv"
 )
 
@@ -30,7 +39,9 @@ var _ = Describe("Order", func() {
 	var rec *httptest.ResponseRecorder
 	var authService *auth.AuthServiceInterface
 	var orderRepository *repositories.OrderRepositoryInterface
+	var operationRepository *repositories.OperationRepositoryInterface
 	var accrualService *services.AccrualServiceInterface
+	var orderEventBroker *services.OrderEventBrokerInterface
 	var controller *controllers.OrderController
 	createOrderRequestString := "12345678903"
 	order := &entities.Order{}
@@ -51,11 +62,15 @@ var _ = Describe("Order", func() {
 		rec = httptest.NewRecorder()
 		authService = new(auth.AuthServiceInterface)
 		orderRepository = new(repositories.OrderRepositoryInterface)
+		orderRepository.EXPECT().WithContext(mock.Anything).Return(orderRepository).Maybe()
+		operationRepository = new(repositories.OperationRepositoryInterface)
 		accrualService = new(services.AccrualServiceInterface)
+		orderEventBroker = new(services.OrderEventBrokerInterface)
 		controller = controllers.NewOrderController(
 			authService,
-			orderRepository,
-			accrualService,
+			appservices.NewOrderService(orderRepository, operationRepository, accrualService),
+			orderEventBroker,
+			3,
 		)
 	})
 
@@ -68,13 +83,12 @@ var _ = Describe("Order", func() {
 			orderRepository.EXPECT().FindByNumber(createOrderRequestString).Return(nil, nil)
 			authService.EXPECT().GetUserID(c).Return(userID)
 			orderRepository.EXPECT().Create(createOrderRequestString, userID).Return(order, nil)
-			accrualService.EXPECT().SendOrderToQueue(*order).Return()
+			accrualService.EXPECT().SendOrderToQueue(mock.Anything, *order).Return()
 
 			// Act
-			err := controller.CreateOrder()(c)
+			serve(c, controller.CreateOrder())
 
 			// Assertions
-			Expect(err).NotTo(HaveOccurred())
 			Expect(rec.Code).To(Equal(http.StatusAccepted))
 		})
 
@@ -88,10 +102,9 @@ var _ = Describe("Order", func() {
 			orderRepository.EXPECT().Create(createOrderRequestString, userID).Return(order, errors.New("test error"))
 
 			// Act
-			err := controller.CreateOrder()(c)
+			serve(c, controller.CreateOrder())
 
 			// Assertions
-			Expect(err).NotTo(HaveOccurred())
 			Expect(rec.Code).To(Equal(http.StatusInternalServerError))
 		})
 
@@ -107,10 +120,9 @@ var _ = Describe("Order", func() {
 			authService.EXPECT().GetUserID(c).Return(userID)
 
 			// Act
-			err := controller.CreateOrder()(c)
+			serve(c, controller.CreateOrder())
 
 			// Assertions
-			Expect(err).NotTo(HaveOccurred())
 			Expect(rec.Code).To(Equal(http.StatusConflict))
 		})
 
@@ -126,10 +138,9 @@ var _ = Describe("Order", func() {
 			authService.EXPECT().GetUserID(c).Return(userID)
 
 			// Act
-			err := controller.CreateOrder()(c)
+			serve(c, controller.CreateOrder())
 
 			// Assertions
-			Expect(err).NotTo(HaveOccurred())
 			Expect(rec.Code).To(Equal(http.StatusOK))
 		})
 
@@ -138,13 +149,13 @@ var _ = Describe("Order", func() {
 			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(createOrderRequestString))
 			req.Header.Set(echo.HeaderContentType, echo.MIMETextPlain)
 			c = e.NewContext(req, rec)
+			authService.EXPECT().GetUserID(c).Return(userID)
 			orderRepository.EXPECT().FindByNumber(createOrderRequestString).Return(nil, errors.New("test error"))
 
 			// Act
-			err := controller.CreateOrder()(c)
+			serve(c, controller.CreateOrder())
 
 			// Assertions
-			Expect(err).NotTo(HaveOccurred())
 			Expect(rec.Code).To(Equal(http.StatusInternalServerError))
 		})
 
@@ -153,48 +164,84 @@ var _ = Describe("Order", func() {
 			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("12345678904"))
 			req.Header.Set(echo.HeaderContentType, echo.MIMETextPlain)
 			c = e.NewContext(req, rec)
+			authService.EXPECT().GetUserID(c).Return(userID)
 
 			// Act
-			err := controller.CreateOrder()(c)
+			serve(c, controller.CreateOrder())
 
 			// Assertions
-			Expect(err).NotTo(HaveOccurred())
 			Expect(rec.Code).To(Equal(http.StatusUnprocessableEntity))
 		})
 
+		It("should log the order number only after it passes validation", func() {
+			// Arrange
+			core, logs := observer.New(zap.InfoLevel)
+			handler := logging.Middleware(zap.New(core))(controller.CreateOrder())
+			invalid := "test\n" + strings.Repeat("x", 1024)
+			authService.EXPECT().GetUserID(mock.Anything).Return(userID)
+			orderRepository.EXPECT().FindByNumber(createOrderRequestString).Return(&entities.Order{
+				Number: createOrderRequestString,
+				UserID: userID,
+			}, nil)
+
+			// Act
+			for _, body := range []string{invalid, createOrderRequestString} {
+				req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+				req.Header.Set(echo.HeaderContentType, echo.MIMETextPlain)
+				serve(e.NewContext(req, httptest.NewRecorder()), handler)
+			}
+
+			// Assertions
+			access := logs.FilterMessage("request").All()
+			Expect(access).To(HaveLen(2))
+			Expect(access[0].ContextMap()).To(HaveKeyWithValue("user_id", uint64(userID)))
+			Expect(access[0].ContextMap()).NotTo(HaveKey("order_number"))
+			Expect(access[1].ContextMap()).To(HaveKeyWithValue("order_number", createOrderRequestString))
+		})
+
 		It("should return an error if the order number consists of letters", func() {
 			// Arrange
 			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("test"))
 			req.Header.Set(echo.HeaderContentType, echo.MIMETextPlain)
 			c = e.NewContext(req, rec)
+			authService.EXPECT().GetUserID(c).Return(userID)
 
 			// Act
-			err := controller.CreateOrder()(c)
+			serve(c, controller.CreateOrder())
 
 			// Assertions
+			Expect(rec.Code).To(Equal(http.StatusBadRequest))
+			Expect(rec.Header().Get(echo.HeaderContentType)).To(Equal(models.MIMEApplicationProblemJSON))
+
+			problem := &models.Problem{}
+			err := json.Unmarshal(rec.Body.Bytes(), problem)
 			Expect(err).NotTo(HaveOccurred())
-			Expect(rec.Code).To(Equal(http.StatusInternalServerError))
+			Expect(problem.Status).To(Equal(http.StatusBadRequest))
+			Expect(problem.Code).To(Equal(apierrors.ErrorCodeBadRequest))
 		})
 	})
 
 	Describe("Get Orders", func() {
-		It("should return the correct answer", func() {
+		It("should return the first page of the newest orders without parameters", func() {
 			// Arrange
 			req := httptest.NewRequest(http.MethodGet, "/", nil)
 			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 			c = e.NewContext(req, rec)
 			authService.EXPECT().GetUserID(c).Return(userID)
-			orderRepository.EXPECT().GetOrdersByUserID(userID).Return(getOrdersResponse, nil)
+			orderRepository.EXPECT().GetOrdersPage(models.OrderSearchFilter{
+				UserID: userID,
+				Sort:   models.SortDirectionDesc,
+				Limit:  models.GetOrdersDefaultLimit,
+			}).Return(&models.GetOrdersPage{Orders: getOrdersResponse}, nil)
 
 			// Act
-			err := controller.GetOrders()(c)
+			serve(c, controller.GetOrders())
 
 			// Assertions
-			Expect(err).NotTo(HaveOccurred())
 			Expect(rec.Code).To(Equal(http.StatusOK))
 
 			var resJ []*models.GetOrdersResponse
-			err = json.Unmarshal(rec.Body.Bytes(), &resJ)
+			err := json.Unmarshal(rec.Body.Bytes(), &resJ)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(len(resJ)).To(Equal(len(getOrdersResponse)))
 		})
@@ -205,29 +252,394 @@ var _ = Describe("Order", func() {
 			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 			c = e.NewContext(req, rec)
 			authService.EXPECT().GetUserID(c).Return(userID)
-			orderRepository.EXPECT().GetOrdersByUserID(userID).Return([]*models.GetOrdersResponse{}, nil)
+			orderRepository.EXPECT().GetOrdersPage(models.OrderSearchFilter{
+				UserID: userID,
+				Sort:   models.SortDirectionDesc,
+				Limit:  models.GetOrdersDefaultLimit,
+			}).Return(&models.GetOrdersPage{Orders: []*models.GetOrdersResponse{}}, nil)
 
 			// Act
-			err := controller.GetOrders()(c)
+			serve(c, controller.GetOrders())
 
 			// Assertions
-			Expect(err).NotTo(HaveOccurred())
 			Expect(rec.Code).To(Equal(http.StatusNoContent))
 		})
 
+		It("should return a page and the next cursor if pagination parameters are passed", func() {
+			// Arrange
+			req := httptest.NewRequest(http.MethodGet, "/api/user/orders?limit=2&status=NEW,PROCESSING&sort=desc", nil)
+			c = e.NewContext(req, rec)
+			authService.EXPECT().GetUserID(c).Return(userID)
+			orderRepository.EXPECT().GetOrdersPage(models.OrderSearchFilter{
+				UserID:   userID,
+				Statuses: []entities.OrderStatus{entities.OrderStatusNew, entities.OrderStatusProcessing},
+				Sort:     models.SortDirectionDesc,
+				Limit:    2,
+			}).Return(&models.GetOrdersPage{
+				Orders:     getOrdersResponse,
+				NextCursor: "next",
+			}, nil)
+
+			// Act
+			serve(c, controller.GetOrders())
+
+			// Assertions
+			Expect(rec.Code).To(Equal(http.StatusOK))
+			Expect(rec.Header().Get("Link")).To(Equal(`</api/user/orders?cursor=next&limit=2&sort=desc&status=NEW%2CPROCESSING>; rel="next"`))
+
+			var resJ []*models.GetOrdersResponse
+			err := json.Unmarshal(rec.Body.Bytes(), &resJ)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(len(resJ)).To(Equal(len(getOrdersResponse)))
+		})
+
+		It("should not return the next cursor on the last page", func() {
+			// Arrange
+			req := httptest.NewRequest(http.MethodGet, "/api/user/orders?limit=5&sort=asc", nil)
+			c = e.NewContext(req, rec)
+			authService.EXPECT().GetUserID(c).Return(userID)
+			orderRepository.EXPECT().GetOrdersPage(models.OrderSearchFilter{
+				UserID: userID,
+				Sort:   models.SortDirectionAsc,
+				Limit:  5,
+			}).Return(&models.GetOrdersPage{Orders: getOrdersResponse}, nil)
+
+			// Act
+			serve(c, controller.GetOrders())
+
+			// Assertions
+			Expect(rec.Code).To(Equal(http.StatusOK))
+			Expect(rec.Header().Get("Link")).To(BeEmpty())
+		})
+
+		It("should return an error if the status filter is invalid", func() {
+			// Arrange
+			req := httptest.NewRequest(http.MethodGet, "/api/user/orders?status=DONE", nil)
+			c = e.NewContext(req, rec)
+			authService.EXPECT().GetUserID(c).Return(userID)
+
+			// Act
+			serve(c, controller.GetOrders())
+
+			// Assertions
+			Expect(rec.Code).To(Equal(http.StatusBadRequest))
+		})
+
+		It("should return an error if the cursor is invalid", func() {
+			// Arrange
+			req := httptest.NewRequest(http.MethodGet, "/api/user/orders?cursor=invalid", nil)
+			c = e.NewContext(req, rec)
+			authService.EXPECT().GetUserID(c).Return(userID)
+
+			// Act
+			serve(c, controller.GetOrders())
+
+			// Assertions
+			Expect(rec.Code).To(Equal(http.StatusBadRequest))
+		})
+
 		It("should return an error if orders could not be received", func() {
 			// Arrange
 			req := httptest.NewRequest(http.MethodGet, "/", nil)
 			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 			c = e.NewContext(req, rec)
 			authService.EXPECT().GetUserID(c).Return(userID)
-			orderRepository.EXPECT().GetOrdersByUserID(userID).Return(getOrdersResponse, errors.New("test error"))
+			orderRepository.EXPECT().GetOrdersPage(models.OrderSearchFilter{
+				UserID: userID,
+				Sort:   models.SortDirectionDesc,
+				Limit:  models.GetOrdersDefaultLimit,
+			}).Return(nil, errors.New("test error"))
+
+			// Act
+			serve(c, controller.GetOrders())
+
+			// Assertions
+			Expect(rec.Code).To(Equal(http.StatusInternalServerError))
+		})
+	})
+
+	Describe("Get Order", func() {
+		orderNumber := "12345678903"
+		existOrder := &entities.Order{
+			Model:  gorm.Model{ID: 5},
+			Number: orderNumber,
+			UserID: userID,
+			Status: entities.OrderStatusProcessing,
+		}
+
+		newRequest := func() echo.Context {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			ctx := e.NewContext(req, rec)
+			ctx.SetParamNames("number")
+			ctx.SetParamValues(orderNumber)
+
+			return ctx
+		}
+
+		It("should return the order with its timeline", func() {
+			// Arrange
+			c = newRequest()
+			authService.EXPECT().GetUserID(c).Return(userID)
+			orderRepository.EXPECT().FindByNumber(orderNumber).Return(existOrder, nil)
+			orderRepository.EXPECT().GetStatusHistory(existOrder.ID).Return([]*entities.OrderStatusHistory{
+				{Status: entities.OrderStatusNew},
+				{Status: entities.OrderStatusProcessing},
+			}, nil)
+			orderRepository.EXPECT().GetAccrualAttempts(orderNumber, 50).Return([]*entities.AccrualAttempt{
+				{StatusCode: http.StatusTooManyRequests, Error: "response too many request"},
+				{StatusCode: http.StatusOK, Status: entities.OrderStatusProcessing},
+			}, nil)
+			operationRepository.EXPECT().GetOperationsByOrderNumber(orderNumber).Return([]*entities.Operation{}, nil)
 
 			// Act
-			err := controller.GetOrders()(c)
+			serve(c, controller.GetOrder())
 
 			// Assertions
+			Expect(rec.Code).To(Equal(http.StatusOK))
+
+			var resJ models.GetOrderResponse
+			err := json.Unmarshal(rec.Body.Bytes(), &resJ)
 			Expect(err).NotTo(HaveOccurred())
+			Expect(resJ.Number).To(Equal(orderNumber))
+			Expect(resJ.History).To(HaveLen(2))
+			Expect(resJ.AccrualAttempts).To(HaveLen(2))
+			Expect(resJ.AccrualAttempts[0].StatusCode).To(Equal(http.StatusTooManyRequests))
+			Expect(resJ.Operations).To(BeEmpty())
+		})
+
+		It("should return status not found if the order does not exist", func() {
+			// Arrange
+			c = newRequest()
+			authService.EXPECT().GetUserID(c).Return(userID)
+			orderRepository.EXPECT().FindByNumber(orderNumber).Return(nil, nil)
+
+			// Act
+			serve(c, controller.GetOrder())
+
+			// Assertions
+			Expect(rec.Code).To(Equal(http.StatusNotFound))
+		})
+
+		It("should return status not found if the order belongs to another user", func() {
+			// Arrange
+			c = newRequest()
+			authService.EXPECT().GetUserID(c).Return(userID + 1)
+			orderRepository.EXPECT().FindByNumber(orderNumber).Return(existOrder, nil)
+
+			// Act
+			serve(c, controller.GetOrder())
+
+			// Assertions
+			Expect(rec.Code).To(Equal(http.StatusNotFound))
+		})
+
+		It("should return an error if the history could not be received", func() {
+			// Arrange
+			c = newRequest()
+			authService.EXPECT().GetUserID(c).Return(userID)
+			orderRepository.EXPECT().FindByNumber(orderNumber).Return(existOrder, nil)
+			orderRepository.EXPECT().GetStatusHistory(existOrder.ID).Return(nil, errors.New("test error"))
+
+			// Act
+			serve(c, controller.GetOrder())
+
+			// Assertions
+			Expect(rec.Code).To(Equal(http.StatusInternalServerError))
+		})
+	})
+
+	Describe("Stream Orders", func() {
+		It("should push order events to the client", func() {
+			// Arrange
+			ctx, cancel := context.WithCancel(context.Background())
+			req := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx)
+			c = e.NewContext(req, rec)
+			events := make(chan models.OrderEvent, 1)
+			unsubscribed := false
+			authService.EXPECT().GetUserID(c).Return(userID)
+			orderEventBroker.EXPECT().Subscribe(userID).Return(events, func() {
+				unsubscribed = true
+			})
+			events <- models.OrderEvent{
+				Type:   models.OrderEventTypeStatusChanged,
+				UserID: userID,
+				Number: createOrderRequestString,
+				Status: entities.OrderStatusProcessed,
+			}
+
+			// Act
+			done := make(chan error)
+			go func() {
+				done <- controller.StreamOrders()(c)
+			}()
+			Eventually(events).Should(BeEmpty())
+			cancel()
+
+			// Assertions
+			Eventually(done).Should(Receive(BeNil()))
+			Expect(unsubscribed).To(BeTrue())
+			Expect(rec.Code).To(Equal(http.StatusOK))
+			Expect(rec.Header().Get(echo.HeaderContentType)).To(Equal("text/event-stream"))
+			Expect(rec.Body.String()).To(ContainSubstring("event: order.status_changed\n"))
+			Expect(rec.Body.String()).To(ContainSubstring(`"number":"12345678903","status":"PROCESSED"`))
+		})
+
+		It("should return status unauthorized if the user is unknown", func() {
+			// Arrange
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			c = e.NewContext(req, rec)
+			authService.EXPECT().GetUserID(c).Return(0)
+
+			// Act
+			serve(c, controller.StreamOrders())
+
+			// Assertions
+			Expect(rec.Code).To(Equal(http.StatusUnauthorized))
+		})
+	})
+
+	Describe("Create Orders Batch", func() {
+		anotherOrderNumber := "79927398713"
+		newOrder := &entities.Order{Number: createOrderRequestString, UserID: userID}
+
+		It("should return a result for every number", func() {
+			// Arrange
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`["12345678903", 79927398713, "12345678904"]`))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			c = e.NewContext(req, rec)
+			authService.EXPECT().GetUserID(c).Return(userID)
+			orderRepository.EXPECT().FindByNumbers([]string{createOrderRequestString, anotherOrderNumber}).Return([]*entities.Order{
+				{Number: anotherOrderNumber, UserID: userID + 1},
+			}, nil)
+			orderRepository.EXPECT().CreateBatch([]string{createOrderRequestString}, userID).Return([]*entities.Order{newOrder}, nil)
+			accrualService.EXPECT().TrySendOrderToQueue(mock.Anything, *newOrder).Return(true)
+
+			// Act
+			serve(c, controller.CreateOrdersBatch())
+
+			// Assertions
+			Expect(rec.Code).To(Equal(http.StatusAccepted))
+			accrualService.AssertCalled(GinkgoT(), "TrySendOrderToQueue", mock.Anything, *newOrder)
+
+			var resJ []models.CreateOrdersBatchResponse
+			err := json.Unmarshal(rec.Body.Bytes(), &resJ)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resJ).To(Equal([]models.CreateOrdersBatchResponse{
+				{Number: createOrderRequestString, Result: models.CreateOrdersBatchResultAccepted},
+				{Number: anotherOrderNumber, Result: models.CreateOrdersBatchResultConflict},
+				{Number: "12345678904", Result: models.CreateOrdersBatchResultInvalidFormat},
+			}))
+		})
+
+		It("should accept newline-delimited numbers", func() {
+			// Arrange
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("12345678903\n\n12345678903\n"))
+			req.Header.Set(echo.HeaderContentType, echo.MIMETextPlain)
+			c = e.NewContext(req, rec)
+			authService.EXPECT().GetUserID(c).Return(userID)
+			orderRepository.EXPECT().FindByNumbers([]string{createOrderRequestString, createOrderRequestString}).Return([]*entities.Order{
+				{Number: createOrderRequestString, UserID: userID},
+			}, nil)
+			orderRepository.EXPECT().CreateBatch([]string(nil), userID).Return([]*entities.Order{}, nil)
+
+			// Act
+			serve(c, controller.CreateOrdersBatch())
+
+			// Assertions
+			Expect(rec.Code).To(Equal(http.StatusOK))
+
+			var resJ []models.CreateOrdersBatchResponse
+			err := json.Unmarshal(rec.Body.Bytes(), &resJ)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resJ).To(HaveLen(2))
+			Expect(resJ[0].Result).To(Equal(models.CreateOrdersBatchResultAlreadyUploaded))
+			Expect(resJ[1].Result).To(Equal(models.CreateOrdersBatchResultAlreadyUploaded))
+		})
+
+		It("should accept CSV with a header", func() {
+			// Arrange
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("number,comment\n12345678903,first\n"))
+			req.Header.Set(echo.HeaderContentType, "text/csv; charset=utf-8")
+			c = e.NewContext(req, rec)
+			authService.EXPECT().GetUserID(c).Return(userID)
+			orderRepository.EXPECT().FindByNumbers([]string{createOrderRequestString}).Return(nil, nil)
+			orderRepository.EXPECT().CreateBatch([]string{createOrderRequestString}, userID).Return([]*entities.Order{newOrder}, nil)
+			accrualService.EXPECT().TrySendOrderToQueue(mock.Anything, *newOrder).Return(true)
+
+			// Act
+			serve(c, controller.CreateOrdersBatch())
+
+			// Assertions
+			Expect(rec.Code).To(Equal(http.StatusAccepted))
+		})
+
+		It("should return an error if the batch is too large", func() {
+			// Arrange
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`["1", "2", "3", "4"]`))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			c = e.NewContext(req, rec)
+
+			// Act
+			serve(c, controller.CreateOrdersBatch())
+
+			// Assertions
+			Expect(rec.Code).To(Equal(http.StatusRequestEntityTooLarge))
+		})
+
+		It("should stop reading the body once the batch exceeds the limit", func() {
+			// Arrange
+			body := io.MultiReader(strings.NewReader("1\n2\n3\n4\n"), iotest.ErrReader(errors.New("body must not be read further")))
+			req := httptest.NewRequest(http.MethodPost, "/", body)
+			req.Header.Set(echo.HeaderContentType, echo.MIMETextPlain)
+			c = e.NewContext(req, rec)
+
+			// Act
+			serve(c, controller.CreateOrdersBatch())
+
+			// Assertions
+			Expect(rec.Code).To(Equal(http.StatusRequestEntityTooLarge))
+		})
+
+		It("should return an error if the body exceeds the size limit", func() {
+			// Arrange
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`["`+strings.Repeat("1", 200)+`"]`))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			c = e.NewContext(req, rec)
+
+			// Act
+			serve(c, controller.CreateOrdersBatch())
+
+			// Assertions
+			Expect(rec.Code).To(Equal(http.StatusRequestEntityTooLarge))
+			Expect(rec.Body.String()).To(ContainSubstring(apierrors.ErrorCodePayloadTooLarge))
+		})
+
+		It("should return an error if the body is not a JSON array", func() {
+			// Arrange
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"number": "12345678903"}`))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			c = e.NewContext(req, rec)
+
+			// Act
+			serve(c, controller.CreateOrdersBatch())
+
+			// Assertions
+			Expect(rec.Code).To(Equal(http.StatusBadRequest))
+		})
+
+		It("should return an error if the orders could not be created", func() {
+			// Arrange
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(createOrderRequestString))
+			req.Header.Set(echo.HeaderContentType, echo.MIMETextPlain)
+			c = e.NewContext(req, rec)
+			authService.EXPECT().GetUserID(c).Return(userID)
+			orderRepository.EXPECT().FindByNumbers([]string{createOrderRequestString}).Return(nil, nil)
+			orderRepository.EXPECT().CreateBatch([]string{createOrderRequestString}, userID).Return(nil, errors.New("test error"))
+
+			// Act
+			serve(c, controller.CreateOrdersBatch())
+
+			// Assertions
 			Expect(rec.Code).To(Equal(http.StatusInternalServerError))
 		})
 	})
@@ -0,0 +1,22 @@
+package controllers
+
+import (
+	"fmt"
+
+	"github.com/labstack/echo/v4"
+)
+
+// setNextPageLink Передаёт курсор следующей страницы в заголовке Link (RFC 8288),
+// не меняя формат тела ответа. Остальные параметры запроса, включая limit, сохраняются
+func setNextPageLink(c echo.Context, cursor string) {
+	if cursor == "" {
+		return
+	}
+
+	nextURL := *c.Request().URL
+	query := nextURL.Query()
+	query.Set("cursor", cursor)
+	nextURL.RawQuery = query.Encode()
+
+	c.Response().Header().Set("Link", fmt.Sprintf(`<%s>; rel="next"`, nextURL.RequestURI()))
+}
@@ -0,0 +1,114 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/ShukinDmitriy/gophermart/internal/models"
+	"github.com/ShukinDmitriy/gophermart/internal/services"
+	"github.com/labstack/echo/v4"
+)
+
+type RewardController struct {
+	rewardService services.RewardServiceInterface
+}
+
+func NewRewardController(rewardService services.RewardServiceInterface) *RewardController {
+	return &RewardController{
+		rewardService: rewardService,
+	}
+}
+
+// GetRules Все правила вознаграждения в порядке применения
+func (controller *RewardController) GetRules() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		rules, err := controller.rewardService.GetRules()
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(http.StatusOK, rules)
+	}
+}
+
+// CreateRule Новое правило вознаграждения
+func (controller *RewardController) CreateRule() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var request models.RewardRuleRequest
+		if err := c.Bind(&request); err != nil {
+			return errBadRequest("invalid request body", err)
+		}
+
+		rule, err := controller.rewardService.CreateRule(request)
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(http.StatusCreated, rule)
+	}
+}
+
+// UpdateRule Замена правила вознаграждения целиком
+func (controller *RewardController) UpdateRule() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id, err := ruleID(c)
+		if err != nil {
+			return err
+		}
+
+		var request models.RewardRuleRequest
+		if err = c.Bind(&request); err != nil {
+			return errBadRequest("invalid request body", err)
+		}
+
+		rule, err := controller.rewardService.UpdateRule(id, request)
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(http.StatusOK, rule)
+	}
+}
+
+// DeleteRule Удаление правила. На уже обработанные заказы не влияет
+func (controller *RewardController) DeleteRule() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id, err := ruleID(c)
+		if err != nil {
+			return err
+		}
+
+		if err = controller.rewardService.DeleteRule(id); err != nil {
+			return err
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+// SubmitBasket Загрузка состава заказа для локального расчёта начислений
+func (controller *RewardController) SubmitBasket() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var request models.OrderBasketRequest
+		if err := c.Bind(&request); err != nil {
+			return errBadRequest("invalid request body", err)
+		}
+		withOrderNumber(c, request.Order)
+
+		basket, err := controller.rewardService.SubmitBasket(request)
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(http.StatusCreated, basket)
+	}
+}
+
+func ruleID(c echo.Context) (uint, error) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || id == 0 {
+		return 0, errNotFound(services.ErrRewardRuleNotFound.Error())
+	}
+
+	return uint(id), nil
+}
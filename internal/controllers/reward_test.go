@@ -0,0 +1,174 @@
+package controllers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/ShukinDmitriy/gophermart/internal/apierrors"
+	"github.com/ShukinDmitriy/gophermart/internal/controllers"
+	"github.com/ShukinDmitriy/gophermart/internal/metrics"
+	"github.com/ShukinDmitriy/gophermart/internal/models"
+	"github.com/ShukinDmitriy/gophermart/internal/repositories/memory"
+	appservices "github.com/ShukinDmitriy/gophermart/internal/services"
+	"github.com/labstack/echo/v4"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+)
+
+var _ = Describe("Reward", func() {
+	const adminToken = "admin-token"
+
+	var e *echo.Echo
+
+	request := func(method string, target string, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set(echo.HeaderAuthorization, "Bearer "+adminToken)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		return rec
+	}
+
+	BeforeEach(func() {
+		storage := memory.NewStorage()
+
+		e = echo.New()
+		e.HTTPErrorHandler = controllers.HTTPErrorHandler
+		controllers.RegisterAdminRoutes(
+			e,
+			controllers.AdminAuth(adminToken),
+			controllers.NewRewardController(appservices.NewRewardService(storage.Rewards)),
+			controllers.NewIntegrationWebhookController(appservices.NewWebhookService(
+				appservices.WebhookOptions{MaxAttempts: 1},
+				storage.Webhooks,
+				http.DefaultClient,
+				metrics.New(),
+				zap.NewNop(),
+			)),
+		)
+	})
+
+	Describe("Auth", func() {
+		It("must reject requests without the admin token", func() {
+			// Arrange
+			send := func(method string, target string, authorization string) *httptest.ResponseRecorder {
+				req := httptest.NewRequest(method, target, strings.NewReader(`{}`))
+				req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+				if authorization != "" {
+					req.Header.Set(echo.HeaderAuthorization, authorization)
+				}
+				rec := httptest.NewRecorder()
+				e.ServeHTTP(rec, req)
+
+				return rec
+			}
+
+			// Act
+			missing := send(http.MethodPost, "/admin/rewards/rules", "")
+			wrong := send(http.MethodPost, "/admin/rewards/baskets", "Bearer other-token")
+			basic := send(http.MethodGet, "/admin/rewards/rules", "Basic YWRtaW46YWRtaW4=")
+			list := send(http.MethodGet, "/admin/rewards/rules", "Bearer "+adminToken)
+			webhooks := send(http.MethodGet, "/admin/webhooks", "")
+			createWebhook := send(http.MethodPost, "/admin/webhooks", "Bearer other-token")
+			deliveries := send(http.MethodGet, "/admin/webhooks/1/deliveries", "")
+			integratorWebhooks := send(http.MethodGet, "/admin/webhooks", "Bearer "+adminToken)
+
+			// Assert
+			Expect(missing.Code).To(Equal(http.StatusUnauthorized))
+			Expect(missing.Header().Get(echo.HeaderWWWAuthenticate)).To(HavePrefix("Bearer"))
+			Expect(wrong.Code).To(Equal(http.StatusUnauthorized))
+			Expect(basic.Code).To(Equal(http.StatusUnauthorized))
+			Expect(list.Code).To(Equal(http.StatusOK))
+			Expect(webhooks.Code).To(Equal(http.StatusUnauthorized))
+			Expect(createWebhook.Code).To(Equal(http.StatusUnauthorized))
+			Expect(deliveries.Code).To(Equal(http.StatusUnauthorized))
+			Expect(integratorWebhooks.Code).To(Equal(http.StatusOK))
+		})
+	})
+
+	Describe("Rules", func() {
+		It("must create, update, list and delete a rule", func() {
+			// Act
+			created := request(http.MethodPost, "/admin/rewards/rules",
+				`{"name":"Bork","mechanic":"percent","value":10,"sku_pattern":"BORK-*"}`)
+			var rule models.RewardRuleResponse
+			Expect(json.Unmarshal(created.Body.Bytes(), &rule)).To(Succeed())
+			updated := request(http.MethodPut, "/admin/rewards/rules/1",
+				`{"name":"Bork","mechanic":"points","value":50,"sku_pattern":"BORK-*","order_cap":100,"active":false}`)
+			list := request(http.MethodGet, "/admin/rewards/rules", "")
+			var rules []models.RewardRuleResponse
+			Expect(json.Unmarshal(list.Body.Bytes(), &rules)).To(Succeed())
+			deleted := request(http.MethodDelete, "/admin/rewards/rules/1", "")
+			deletedAgain := request(http.MethodDelete, "/admin/rewards/rules/1", "")
+
+			// Assert
+			Expect(created.Code).To(Equal(http.StatusCreated))
+			Expect(rule.ID).To(Equal(uint(1)))
+			Expect(rule.Active).To(BeTrue())
+			Expect(updated.Code).To(Equal(http.StatusOK))
+			Expect(rules).To(HaveLen(1))
+			Expect(rules[0].Mechanic).To(BeEquivalentTo("points"))
+			Expect(*rules[0].OrderCap).To(BeNumerically("==", 100))
+			Expect(rules[0].Active).To(BeFalse())
+			Expect(deleted.Code).To(Equal(http.StatusNoContent))
+			Expect(deletedAgain.Code).To(Equal(http.StatusNotFound))
+		})
+
+		It("must reject an invalid rule with the failed checks", func() {
+			// Act
+			rec := request(http.MethodPost, "/admin/rewards/rules",
+				`{"name":"Bork","mechanic":"percent","value":150,"sku_pattern":"BORK-[",
+					"valid_from":"2024-06-01T00:00:00Z","valid_to":"2024-05-01T00:00:00Z"}`)
+			var problem models.Problem
+			Expect(json.Unmarshal(rec.Body.Bytes(), &problem)).To(Succeed())
+
+			// Assert
+			Expect(rec.Code).To(Equal(http.StatusBadRequest))
+			Expect(problem.Code).To(Equal(apierrors.ErrorCodeValidationFailed))
+			Expect(problem.Errors).To(HaveKey("value"))
+			Expect(problem.Errors).To(HaveKey("skupattern"))
+			Expect(problem.Errors).To(HaveKey("validto"))
+		})
+
+		It("must answer 404 for an unknown rule", func() {
+			// Act
+			rec := request(http.MethodPut, "/admin/rewards/rules/7", `{"name":"Bork","mechanic":"points","value":5}`)
+
+			// Assert
+			Expect(rec.Code).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	Describe("Baskets", func() {
+		It("must accept a basket once per order", func() {
+			// Arrange
+			body := `{"order":"12345678903","purchased_at":"2024-05-01T12:00:00Z",
+				"items":[{"sku":"BORK-K1","category":"kettles","price":7000,"quantity":1}]}`
+
+			// Act
+			created := request(http.MethodPost, "/admin/rewards/baskets", body)
+			duplicate := request(http.MethodPost, "/admin/rewards/baskets", body)
+
+			// Assert
+			Expect(created.Code).To(Equal(http.StatusCreated))
+			Expect(created.Body.String()).To(MatchJSON(`{"order":"12345678903","purchased_at":"2024-05-01T12:00:00Z",
+				"items":[{"sku":"BORK-K1","category":"kettles","price":7000,"quantity":1}]}`))
+			Expect(duplicate.Code).To(Equal(http.StatusConflict))
+		})
+
+		It("must reject a wrong order number and an empty basket", func() {
+			// Act
+			wrongNumber := request(http.MethodPost, "/admin/rewards/baskets",
+				`{"order":"12345678900","items":[{"sku":"BORK-K1","price":7000,"quantity":1}]}`)
+			empty := request(http.MethodPost, "/admin/rewards/baskets", `{"order":"12345678903","items":[]}`)
+
+			// Assert
+			Expect(wrongNumber.Code).To(Equal(http.StatusUnprocessableEntity))
+			Expect(empty.Code).To(Equal(http.StatusBadRequest))
+		})
+	})
+})
@@ -0,0 +1,98 @@
+package controllers
+
+import "github.com/labstack/echo/v4"
+
+// RegisterRoutes Регистрирует маршруты API. authMiddleware проверяет аутентификацию пользователя
+func RegisterRoutes(
+	e *echo.Echo,
+	authMiddleware echo.MiddlewareFunc,
+	balanceController *BalanceController,
+	operationController *OperationController,
+	orderController *OrderController,
+	userController *UserController,
+	webhookController *WebhookController,
+	notificationController *NotificationController,
+) {
+	// POST /api/user/register — регистрация пользователя;
+	// POST /api/user/login — аутентификация пользователя;
+	// POST /api/user/orders — загрузка пользователем номера заказа для расчёта;
+	// POST /api/user/orders/batch — загрузка пакета номеров заказов;
+	// GET /api/user/orders — получение списка загруженных пользователем номеров заказов, статусов их обработки и информации о начислениях;
+	// GET /api/user/orders/stream — поток изменений статусов заказов (SSE);
+	// GET /api/user/orders/{number} — получение заказа с историей статусов и обращений к системе расчёта;
+	// GET /api/user/balance — получение текущего баланса счёта баллов лояльности пользователя;
+	// POST /api/user/balance/withdraw — запрос на списание баллов с накопительного счёта в счёт оплаты нового заказа;
+	// GET /api/user/withdrawals — получение информации о выводе средств с накопительного счёта пользователем;
+	// GET /api/user/operations — получение всех движений по накопительному счёту с остатком;
+	// GET /api/user/webhooks — вебхуки пользователя;
+	// POST /api/user/webhooks — новый вебхук на события пользователя;
+	// DELETE /api/user/webhooks/{id} — удаление вебхука;
+	// GET /api/user/webhooks/{id}/deliveries — журнал доставок вебхука;
+	// GET /api/user/notifications — уведомления пользователя;
+	// POST /api/user/notifications/read — отметка всех уведомлений прочитанными;
+	// POST /api/user/notifications/{id}/read — отметка уведомления прочитанным;
+	// GET /api/user/notifications/preferences — каналы уведомлений;
+	// PUT /api/user/notifications/preferences — изменение каналов уведомлений.
+
+	e.POST("/api/user/register", userController.UserRegister())
+	e.POST("/api/user/login", userController.UserLogin())
+	e.POST("/api/user/orders", orderController.CreateOrder(), authMiddleware)
+	e.POST("/api/user/orders/batch", orderController.CreateOrdersBatch(), authMiddleware)
+	e.GET("/api/user/orders", orderController.GetOrders(), authMiddleware)
+	e.GET("/api/user/orders/stream", orderController.StreamOrders(), authMiddleware)
+	e.GET("/api/user/orders/:number", orderController.GetOrder(), authMiddleware)
+	e.GET("/api/user/balance", balanceController.GetBalance(), authMiddleware)
+	e.POST("/api/user/balance/withdraw", operationController.CreateWithdraw(), authMiddleware)
+	e.GET("/api/user/withdrawals", operationController.GetWithdrawals(), authMiddleware)
+	e.GET("/api/user/operations", operationController.GetOperations(), authMiddleware)
+	e.GET("/api/user/webhooks", webhookController.GetWebhooks(), authMiddleware)
+	e.POST("/api/user/webhooks", webhookController.CreateWebhook(), authMiddleware)
+	e.DELETE("/api/user/webhooks/:id", webhookController.DeleteWebhook(), authMiddleware)
+	e.GET("/api/user/webhooks/:id/deliveries", webhookController.GetDeliveries(), authMiddleware)
+	e.GET("/api/user/notifications", notificationController.GetNotifications(), authMiddleware)
+	e.POST("/api/user/notifications/read", notificationController.MarkAllRead(), authMiddleware)
+	e.POST("/api/user/notifications/:id/read", notificationController.MarkRead(), authMiddleware)
+	e.GET("/api/user/notifications/preferences", notificationController.GetPreferences(), authMiddleware)
+	e.PUT("/api/user/notifications/preferences", notificationController.UpdatePreferences(), authMiddleware)
+}
+
+// RegisterHealthRoutes Пробы для оркестратора, без аутентификации:
+// GET /healthz — процесс жив;
+// GET /readyz — экземпляр готов принимать трафик.
+func RegisterHealthRoutes(e *echo.Echo, healthController *HealthController) {
+	e.GET("/healthz", healthController.Liveness())
+	e.GET("/readyz", healthController.Readiness())
+}
+
+// RegisterAccrualCallbackRoutes Приём результатов расчёта от системы расчёта, аутентификация подписью запроса:
+// POST /api/internal/accrual/callback — результат расчёта по заказу.
+func RegisterAccrualCallbackRoutes(e *echo.Echo, accrualCallbackController *AccrualCallbackController) {
+	e.POST("/api/internal/accrual/callback", accrualCallbackController.Callback())
+}
+
+// RegisterAdminRoutes Служебный API, доступен только на служебном сервере. authMiddleware проверяет токен служебного API:
+// GET /admin/rewards/rules — правила вознаграждения;
+// POST /admin/rewards/rules — новое правило;
+// PUT /admin/rewards/rules/{id} — замена правила;
+// DELETE /admin/rewards/rules/{id} — удаление правила;
+// POST /admin/rewards/baskets — состав заказа для локального расчёта начислений;
+// GET /admin/webhooks — вебхуки интеграторов;
+// POST /admin/webhooks — новый вебхук на события всех пользователей;
+// DELETE /admin/webhooks/{id} — удаление вебхука;
+// GET /admin/webhooks/{id}/deliveries — журнал доставок вебхука.
+func RegisterAdminRoutes(
+	e *echo.Echo,
+	authMiddleware echo.MiddlewareFunc,
+	rewardController *RewardController,
+	webhookController *WebhookController,
+) {
+	e.GET("/admin/rewards/rules", rewardController.GetRules(), authMiddleware)
+	e.POST("/admin/rewards/rules", rewardController.CreateRule(), authMiddleware)
+	e.PUT("/admin/rewards/rules/:id", rewardController.UpdateRule(), authMiddleware)
+	e.DELETE("/admin/rewards/rules/:id", rewardController.DeleteRule(), authMiddleware)
+	e.POST("/admin/rewards/baskets", rewardController.SubmitBasket(), authMiddleware)
+	e.GET("/admin/webhooks", webhookController.GetWebhooks(), authMiddleware)
+	e.POST("/admin/webhooks", webhookController.CreateWebhook(), authMiddleware)
+	e.DELETE("/admin/webhooks/:id", webhookController.DeleteWebhook(), authMiddleware)
+	e.GET("/admin/webhooks/:id/deliveries", webhookController.GetDeliveries(), authMiddleware)
+}
@@ -5,24 +5,22 @@ import (
 
 	"github.com/ShukinDmitriy/gophermart/internal/auth"
 	"github.com/ShukinDmitriy/gophermart/internal/models"
-	"github.com/ShukinDmitriy/gophermart/internal/repositories"
-	"github.com/go-playground/validator/v10"
+	"github.com/ShukinDmitriy/gophermart/internal/services"
 	"github.com/labstack/echo/v4"
-	"golang.org/x/crypto/bcrypt"
 )
 
 type UserController struct {
-	authService    auth.AuthServiceInterface
-	userRepository repositories.UserRepositoryInterface
+	authService auth.AuthServiceInterface
+	userService services.UserServiceInterface
 }
 
 func NewUserController(
 	authService auth.AuthServiceInterface,
-	userRepository repositories.UserRepositoryInterface,
+	userService services.UserServiceInterface,
 ) *UserController {
 	return &UserController{
-		authService:    authService,
-		userRepository: userRepository,
+		authService: authService,
+		userService: userService,
 	}
 }
 
@@ -31,36 +29,17 @@ func (controller *UserController) UserRegister() echo.HandlerFunc {
 		var userRegisterRequest models.UserRegisterRequest
 		err := c.Bind(&userRegisterRequest)
 		if err != nil {
-			c.Logger().Error(err)
-			return c.JSON(http.StatusBadRequest, nil)
+			return errBadRequest("invalid request body", err)
 		}
 
-		validate := validator.New(validator.WithRequiredStructEnabled())
-		err = validate.Struct(userRegisterRequest)
+		user, err := controller.userService.Register(userRegisterRequest)
 		if err != nil {
-			return c.JSON(http.StatusBadRequest, models.ExtractErrors(err))
-		}
-
-		existUser, err := controller.userRepository.FindBy(models.UserSearchFilter{Login: userRegisterRequest.Login})
-		if err != nil {
-			c.Logger().Error(err)
-			return c.JSON(http.StatusInternalServerError, "internal gophermart error")
-		}
-		if existUser != nil {
-			c.Logger().Error("login already exist")
-			return c.JSON(http.StatusConflict, "login already exist")
-		}
-
-		user, err := controller.userRepository.Create(userRegisterRequest)
-		if err != nil {
-			c.Logger().Error(err)
-			return c.JSON(http.StatusInternalServerError, "internal gophermart error")
+			return err
 		}
 
 		err = controller.authService.GenerateTokensAndSetCookies(c, user)
 		if err != nil {
-			c.Logger().Error(err)
-			return c.JSON(http.StatusInternalServerError, "internal gophermart error")
+			return errInternal(err)
 		}
 
 		return c.JSON(http.StatusOK, user)
@@ -72,29 +51,12 @@ func (controller *UserController) UserLogin() echo.HandlerFunc {
 		var userLoginRequest models.UserLoginRequest
 		err := c.Bind(&userLoginRequest)
 		if err != nil {
-			c.Logger().Error(err)
-			return c.JSON(http.StatusBadRequest, nil)
+			return errBadRequest("invalid request body", err)
 		}
 
-		validate := validator.New(validator.WithRequiredStructEnabled())
-		err = validate.Struct(userLoginRequest)
+		existUser, err := controller.userService.Login(userLoginRequest)
 		if err != nil {
-			return c.JSON(http.StatusBadRequest, models.ExtractErrors(err))
-		}
-
-		existUser, err := controller.userRepository.FindBy(models.UserSearchFilter{Login: userLoginRequest.Login})
-		if err != nil {
-			c.Logger().Error(err)
-			return c.JSON(http.StatusInternalServerError, "internal gophermart error")
-		}
-		if existUser == nil {
-			c.Logger().Error("user not exist")
-			return c.JSON(http.StatusUnauthorized, "user not exist")
-		}
-
-		if bcrypt.CompareHashAndPassword([]byte(existUser.Password), []byte(userLoginRequest.Password)) != nil {
-			c.Logger().Error("invalid password")
-			return c.JSON(http.StatusUnauthorized, "invalid password")
+			return err
 		}
 
 		err = controller.authService.GenerateTokensAndSetCookies(c, &models.UserInfoResponse{
@@ -106,8 +68,7 @@ func (controller *UserController) UserLogin() echo.HandlerFunc {
 			Email:      existUser.Email,
 		})
 		if err != nil {
-			c.Logger().Error(err)
-			return c.JSON(http.StatusInternalServerError, "internal gophermart error")
+			return errInternal(err)
 		}
 
 		return c.JSON(http.StatusOK, models.MapUserToUserLoginResponse(existUser))
@@ -7,9 +7,11 @@ import (
 	"net/http/httptest"
 	"strings"
 
+	"github.com/ShukinDmitriy/gophermart/internal/apierrors"
 	"github.com/ShukinDmitriy/gophermart/internal/controllers"
 	"github.com/ShukinDmitriy/gophermart/internal/entities"
 	"github.com/ShukinDmitriy/gophermart/internal/models"
+	appservices "github.com/ShukinDmitriy/gophermart/internal/services"
 	"github.com/ShukinDmitriy/gophermart/mocks/internal_/auth"
 	"github.com/ShukinDmitriy/gophermart/mocks/internal_/repositories"
 	"github.com/labstack/echo/v4"
@@ -54,7 +56,7 @@ var _ = Describe("User", func() {
 		userRepository = new(repositories.UserRepositoryInterface)
 		controller = controllers.NewUserController(
 			authService,
-			userRepository,
+			appservices.NewUserService(userRepository),
 		)
 	})
 
@@ -69,14 +71,13 @@ var _ = Describe("User", func() {
 			authService.EXPECT().GenerateTokensAndSetCookies(c, userRegisterResponse).Return(nil)
 
 			// Act
-			err := controller.UserRegister()(c)
+			serve(c, controller.UserRegister())
 
 			// Assertions
-			Expect(err).NotTo(HaveOccurred())
 			Expect(rec.Code).To(Equal(http.StatusOK))
 
 			resJ := &models.UserInfoResponse{}
-			err = json.Unmarshal(rec.Body.Bytes(), resJ)
+			err := json.Unmarshal(rec.Body.Bytes(), resJ)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(resJ.Login).To(Equal(userRegisterResponse.Login))
 			Expect(resJ.ID).To(Equal(userRegisterResponse.ID))
@@ -92,10 +93,9 @@ var _ = Describe("User", func() {
 			authService.EXPECT().GenerateTokensAndSetCookies(c, userRegisterResponse).Return(errors.New("test error"))
 
 			// Act
-			err := controller.UserRegister()(c)
+			serve(c, controller.UserRegister())
 
 			// Assertions
-			Expect(err).NotTo(HaveOccurred())
 			Expect(rec.Code).To(Equal(http.StatusInternalServerError))
 		})
 
@@ -108,10 +108,9 @@ var _ = Describe("User", func() {
 			userRepository.EXPECT().Create(userRequest).Return(userRegisterResponse, errors.New("test error"))
 
 			// Act
-			err := controller.UserRegister()(c)
+			serve(c, controller.UserRegister())
 
 			// Assertions
-			Expect(err).NotTo(HaveOccurred())
 			Expect(rec.Code).To(Equal(http.StatusInternalServerError))
 		})
 
@@ -124,10 +123,9 @@ var _ = Describe("User", func() {
 			userRepository.EXPECT().Create(userRequest).Return(userRegisterResponse, errors.New("test error"))
 
 			// Act
-			err := controller.UserRegister()(c)
+			serve(c, controller.UserRegister())
 
 			// Assertions
-			Expect(err).NotTo(HaveOccurred())
 			Expect(rec.Code).To(Equal(http.StatusInternalServerError))
 		})
 
@@ -139,11 +137,15 @@ var _ = Describe("User", func() {
 			userRepository.EXPECT().FindBy(models.UserSearchFilter{Login: userRequest.Login}).Return(&entities.User{Login: userRequest.Login}, nil)
 
 			// Act
-			err := controller.UserRegister()(c)
+			serve(c, controller.UserRegister())
 
 			// Assertions
-			Expect(err).NotTo(HaveOccurred())
 			Expect(rec.Code).To(Equal(http.StatusConflict))
+
+			problem := &models.Problem{}
+			err := json.Unmarshal(rec.Body.Bytes(), problem)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(problem.Code).To(Equal(apierrors.ErrorCodeLoginAlreadyExists))
 		})
 
 		It("should return an error if the login could not be verified", func() {
@@ -154,10 +156,9 @@ var _ = Describe("User", func() {
 			userRepository.EXPECT().FindBy(models.UserSearchFilter{Login: userRequest.Login}).Return(nil, errors.New("test error"))
 
 			// Act
-			err := controller.UserRegister()(c)
+			serve(c, controller.UserRegister())
 
 			// Assertions
-			Expect(err).NotTo(HaveOccurred())
 			Expect(rec.Code).To(Equal(http.StatusInternalServerError))
 		})
 
@@ -168,20 +169,23 @@ var _ = Describe("User", func() {
 			c = e.NewContext(req, rec)
 
 			// Act
-			err := controller.UserRegister()(c)
+			serve(c, controller.UserRegister())
 
 			// Assertions
-			Expect(err).NotTo(HaveOccurred())
 			Expect(rec.Code).To(Equal(http.StatusBadRequest))
 
 			resJ := &struct {
-				Login    map[string]bool `json:"login"`
-				Password map[string]bool `json:"password"`
+				Code   string `json:"code"`
+				Errors struct {
+					Login    map[string]bool `json:"login"`
+					Password map[string]bool `json:"password"`
+				} `json:"errors"`
 			}{}
-			err = json.Unmarshal(rec.Body.Bytes(), resJ)
+			err := json.Unmarshal(rec.Body.Bytes(), resJ)
 			Expect(err).NotTo(HaveOccurred())
-			Expect(resJ.Login["min"]).To(BeTrue())
-			Expect(resJ.Password["min"]).To(BeTrue())
+			Expect(resJ.Code).To(Equal(apierrors.ErrorCodeValidationFailed))
+			Expect(resJ.Errors.Login["min"]).To(BeTrue())
+			Expect(resJ.Errors.Password["min"]).To(BeTrue())
 		})
 	})
 
@@ -202,14 +206,13 @@ var _ = Describe("User", func() {
 			}).Return(nil)
 
 			// Act
-			err := controller.UserLogin()(c)
+			serve(c, controller.UserLogin())
 
 			// Assertions
-			Expect(err).NotTo(HaveOccurred())
 			Expect(rec.Code).To(Equal(http.StatusOK))
 
 			resJ := &models.UserInfoResponse{}
-			err = json.Unmarshal(rec.Body.Bytes(), resJ)
+			err := json.Unmarshal(rec.Body.Bytes(), resJ)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(resJ.Login).To(Equal(userRegisterResponse.Login))
 		})
@@ -230,10 +233,9 @@ var _ = Describe("User", func() {
 			}).Return(errors.New("test error"))
 
 			// Act
-			err := controller.UserLogin()(c)
+			serve(c, controller.UserLogin())
 
 			// Assertions
-			Expect(err).NotTo(HaveOccurred())
 			Expect(rec.Code).To(Equal(http.StatusInternalServerError))
 		})
 
@@ -248,10 +250,9 @@ var _ = Describe("User", func() {
 			}, nil)
 
 			// Act
-			err := controller.UserLogin()(c)
+			serve(c, controller.UserLogin())
 
 			// Assertions
-			Expect(err).NotTo(HaveOccurred())
 			Expect(rec.Code).To(Equal(http.StatusUnauthorized))
 		})
 
@@ -263,10 +264,9 @@ var _ = Describe("User", func() {
 			userRepository.EXPECT().FindBy(models.UserSearchFilter{Login: userRequest.Login}).Return(nil, nil)
 
 			// Act
-			err := controller.UserLogin()(c)
+			serve(c, controller.UserLogin())
 
 			// Assertions
-			Expect(err).NotTo(HaveOccurred())
 			Expect(rec.Code).To(Equal(http.StatusUnauthorized))
 		})
 
@@ -278,10 +278,9 @@ var _ = Describe("User", func() {
 			userRepository.EXPECT().FindBy(models.UserSearchFilter{Login: userRequest.Login}).Return(user, errors.New("test error"))
 
 			// Act
-			err := controller.UserLogin()(c)
+			serve(c, controller.UserLogin())
 
 			// Assertions
-			Expect(err).NotTo(HaveOccurred())
 			Expect(rec.Code).To(Equal(http.StatusInternalServerError))
 		})
 
@@ -292,20 +291,23 @@ var _ = Describe("User", func() {
 			c = e.NewContext(req, rec)
 
 			// Act
-			err := controller.UserLogin()(c)
+			serve(c, controller.UserLogin())
 
 			// Assertions
-			Expect(err).NotTo(HaveOccurred())
 			Expect(rec.Code).To(Equal(http.StatusBadRequest))
 
 			resJ := &struct {
-				Login    map[string]bool `json:"login"`
-				Password map[string]bool `json:"password"`
+				Code   string `json:"code"`
+				Errors struct {
+					Login    map[string]bool `json:"login"`
+					Password map[string]bool `json:"password"`
+				} `json:"errors"`
 			}{}
-			err = json.Unmarshal(rec.Body.Bytes(), resJ)
+			err := json.Unmarshal(rec.Body.Bytes(), resJ)
 			Expect(err).NotTo(HaveOccurred())
-			Expect(resJ.Login["min"]).To(BeTrue())
-			Expect(resJ.Password["min"]).To(BeTrue())
+			Expect(resJ.Code).To(Equal(apierrors.ErrorCodeValidationFailed))
+			Expect(resJ.Errors.Login["min"]).To(BeTrue())
+			Expect(resJ.Errors.Password["min"]).To(BeTrue())
 		})
 	})
 })
@@ -0,0 +1,142 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/ShukinDmitriy/gophermart/internal/auth"
+	"github.com/ShukinDmitriy/gophermart/internal/logging"
+	"github.com/ShukinDmitriy/gophermart/internal/models"
+	"github.com/ShukinDmitriy/gophermart/internal/services"
+	"github.com/labstack/echo/v4"
+)
+
+// WebhookController Один контроллер для вебхуков пользователя в API и вебхуков интеграторов
+// на служебном сервере, отличается только владелец
+type WebhookController struct {
+	webhookService services.WebhookServiceInterface
+	owner          func(c echo.Context) (*uint, error)
+}
+
+// NewWebhookController Вебхуки текущего пользователя: только его события
+func NewWebhookController(
+	authService auth.AuthServiceInterface,
+	webhookService services.WebhookServiceInterface,
+) *WebhookController {
+	return &WebhookController{
+		webhookService: webhookService,
+		owner: func(c echo.Context) (*uint, error) {
+			currentUserID := authService.GetUserID(c)
+			logging.With(c, logging.UserID(currentUserID))
+			if currentUserID == 0 {
+				return nil, errUnauthorized()
+			}
+
+			return &currentUserID, nil
+		},
+	}
+}
+
+// NewIntegrationWebhookController Вебхуки интеграторов: события всех пользователей
+func NewIntegrationWebhookController(webhookService services.WebhookServiceInterface) *WebhookController {
+	return &WebhookController{
+		webhookService: webhookService,
+		owner: func(echo.Context) (*uint, error) {
+			return nil, nil
+		},
+	}
+}
+
+// GetWebhooks Вебхуки владельца без секретов
+func (controller *WebhookController) GetWebhooks() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		owner, err := controller.owner(c)
+		if err != nil {
+			return err
+		}
+
+		webhooks, err := controller.webhookService.GetWebhooks(owner)
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(http.StatusOK, webhooks)
+	}
+}
+
+// CreateWebhook Новый вебхук, секрет подписи есть только в этом ответе
+func (controller *WebhookController) CreateWebhook() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		owner, err := controller.owner(c)
+		if err != nil {
+			return err
+		}
+
+		var request models.WebhookRequest
+		if err = c.Bind(&request); err != nil {
+			return errBadRequest("invalid request body", err)
+		}
+
+		webhook, err := controller.webhookService.CreateWebhook(owner, request)
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(http.StatusCreated, webhook)
+	}
+}
+
+// DeleteWebhook Удаление вебхука, журнал его доставок остаётся
+func (controller *WebhookController) DeleteWebhook() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		owner, err := controller.owner(c)
+		if err != nil {
+			return err
+		}
+		id, err := webhookID(c)
+		if err != nil {
+			return err
+		}
+
+		if err = controller.webhookService.DeleteWebhook(owner, id); err != nil {
+			return err
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+// GetDeliveries Журнал доставок вебхука, новые первыми
+func (controller *WebhookController) GetDeliveries() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		owner, err := controller.owner(c)
+		if err != nil {
+			return err
+		}
+		id, err := webhookID(c)
+		if err != nil {
+			return err
+		}
+
+		var request models.GetWebhookDeliveriesRequest
+		if err = c.Bind(&request); err != nil {
+			return errBadRequest("invalid query parameters", err)
+		}
+
+		deliveries, err := controller.webhookService.GetDeliveries(owner, id, request)
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(http.StatusOK, deliveries)
+	}
+}
+
+func webhookID(c echo.Context) (uint, error) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || id == 0 {
+		return 0, errNotFound(services.ErrWebhookNotFound.Error())
+	}
+
+	return uint(id), nil
+}
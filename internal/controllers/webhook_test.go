@@ -0,0 +1,168 @@
+package controllers_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+
+	"github.com/ShukinDmitriy/gophermart/internal/controllers"
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+	"github.com/ShukinDmitriy/gophermart/internal/metrics"
+	"github.com/ShukinDmitriy/gophermart/internal/models"
+	"github.com/ShukinDmitriy/gophermart/internal/repositories/memory"
+	appservices "github.com/ShukinDmitriy/gophermart/internal/services"
+	"github.com/ShukinDmitriy/gophermart/internal/webhooktest"
+	"github.com/ShukinDmitriy/gophermart/mocks/internal_/auth"
+	"github.com/labstack/echo/v4"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+var _ = Describe("Webhook", func() {
+	const userHeader = "X-Test-User"
+
+	var e *echo.Echo
+	var admin *echo.Echo
+	var webhookService *appservices.WebhookService
+	var receiver *webhooktest.Receiver
+
+	request := func(server *echo.Echo, userID uint, method string, target string, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set(userHeader, strconv.FormatUint(uint64(userID), 10))
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, req)
+
+		return rec
+	}
+
+	createWebhook := func(server *echo.Echo, userID uint) models.WebhookResponse {
+		rec := request(server, userID, http.MethodPost, "/webhooks",
+			`{"url":"`+receiver.URL+`","events":["order.processed"]}`)
+		Expect(rec.Code).To(Equal(http.StatusCreated), rec.Body.String())
+
+		var webhook models.WebhookResponse
+		Expect(json.Unmarshal(rec.Body.Bytes(), &webhook)).To(Succeed())
+
+		return webhook
+	}
+
+	BeforeEach(func() {
+		storage := memory.NewStorage()
+		authService := new(auth.AuthServiceInterface)
+		authService.EXPECT().GetUserID(mock.Anything).RunAndReturn(func(c echo.Context) uint {
+			userID, _ := strconv.ParseUint(c.Request().Header.Get(userHeader), 10, 64)
+			return uint(userID)
+		}).Maybe()
+		receiver = webhooktest.NewReceiver()
+		DeferCleanup(receiver.Close)
+		webhookService = appservices.NewWebhookService(
+			appservices.WebhookOptions{MaxAttempts: 3, AllowPrivateNetworks: true},
+			storage.Webhooks,
+			http.DefaultClient,
+			metrics.New(),
+			zap.NewNop(),
+		)
+
+		// Одни и те же пути в обеих областях, чтобы сравнивать их поведение
+		e = echo.New()
+		e.HTTPErrorHandler = controllers.HTTPErrorHandler
+		controller := controllers.NewWebhookController(authService, webhookService)
+		e.GET("/webhooks", controller.GetWebhooks())
+		e.POST("/webhooks", controller.CreateWebhook())
+		e.DELETE("/webhooks/:id", controller.DeleteWebhook())
+		e.GET("/webhooks/:id/deliveries", controller.GetDeliveries())
+
+		admin = echo.New()
+		admin.HTTPErrorHandler = controllers.HTTPErrorHandler
+		integration := controllers.NewIntegrationWebhookController(webhookService)
+		admin.GET("/webhooks", integration.GetWebhooks())
+		admin.POST("/webhooks", integration.CreateWebhook())
+		admin.DELETE("/webhooks/:id", integration.DeleteWebhook())
+		admin.GET("/webhooks/:id/deliveries", integration.GetDeliveries())
+	})
+
+	It("must return the secret only on create", func() {
+		// Act
+		created := createWebhook(e, 1)
+		rec := request(e, 1, http.MethodGet, "/webhooks", "")
+		var webhooks []models.WebhookResponse
+		Expect(json.Unmarshal(rec.Body.Bytes(), &webhooks)).To(Succeed())
+
+		// Assert
+		Expect(created.Secret).To(HaveLen(64))
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(webhooks).To(HaveLen(1))
+		Expect(webhooks[0].ID).To(Equal(created.ID))
+		Expect(webhooks[0].Secret).To(BeEmpty())
+	})
+
+	It("must keep webhooks of users and integrators apart", func() {
+		// Arrange
+		own := createWebhook(e, 1)
+		integration := createWebhook(admin, 0)
+		path := "/webhooks/" + strconv.FormatUint(uint64(own.ID), 10)
+
+		// Act
+		foreign := request(e, 2, http.MethodDelete, path, "")
+		foreignDeliveries := request(e, 2, http.MethodGet, path+"/deliveries", "")
+		fromAdmin := request(admin, 0, http.MethodDelete, path, "")
+		adminList := request(admin, 0, http.MethodGet, "/webhooks", "")
+		var webhooks []models.WebhookResponse
+		Expect(json.Unmarshal(adminList.Body.Bytes(), &webhooks)).To(Succeed())
+		deleted := request(e, 1, http.MethodDelete, path, "")
+
+		// Assert
+		Expect(foreign.Code).To(Equal(http.StatusNotFound))
+		Expect(foreignDeliveries.Code).To(Equal(http.StatusNotFound))
+		Expect(fromAdmin.Code).To(Equal(http.StatusNotFound))
+		Expect(webhooks).To(HaveLen(1))
+		Expect(webhooks[0].ID).To(Equal(integration.ID))
+		Expect(deleted.Code).To(Equal(http.StatusNoContent))
+	})
+
+	It("must log a delivered event", func() {
+		// Arrange
+		webhook := createWebhook(e, 1)
+		webhookService.Emit(context.Background(), models.WebhookEvent{
+			Type:   entities.WebhookEventOrderProcessed,
+			UserID: 1,
+			Order:  "12345678903",
+			Status: entities.OrderStatusProcessed,
+			Sum:    500,
+		})
+
+		// Act
+		delivered := webhookService.DeliverPending(context.Background())
+		rec := request(e, 1, http.MethodGet, "/webhooks/"+strconv.FormatUint(uint64(webhook.ID), 10)+"/deliveries?status=delivered", "")
+		var deliveries []models.WebhookDeliveryResponse
+		Expect(json.Unmarshal(rec.Body.Bytes(), &deliveries)).To(Succeed())
+
+		// Assert
+		Expect(delivered).To(Equal(1))
+		Expect(receiver.Deliveries()).To(HaveLen(1))
+		Expect(receiver.Deliveries()[0].Verify(webhook.Secret)).To(Succeed())
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(deliveries).To(HaveLen(1))
+		Expect(deliveries[0].EventType).To(Equal(entities.WebhookEventOrderProcessed))
+		Expect(deliveries[0].Attempts).To(Equal(1))
+		Expect(deliveries[0].DeliveredAt).NotTo(BeNil())
+	})
+
+	It("must reject a wrong id and filters", func() {
+		// Act
+		wrongID := request(e, 1, http.MethodDelete, "/webhooks/abc", "")
+		wrongStatus := request(e, 1, http.MethodGet, "/webhooks/1/deliveries?status=lost", "")
+		unauthorized := request(e, 0, http.MethodGet, "/webhooks", "")
+
+		// Assert
+		Expect(wrongID.Code).To(Equal(http.StatusNotFound))
+		Expect(wrongStatus.Code).To(Equal(http.StatusBadRequest))
+		Expect(unauthorized.Code).To(Equal(http.StatusUnauthorized))
+	})
+})
@@ -0,0 +1,69 @@
+package entities
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type NotificationType string
+
+const (
+	// NotificationOrderProcessed заказ рассчитан, баллы начислены
+	NotificationOrderProcessed NotificationType = "order.processed"
+	// NotificationOrderInvalid заказ не принят системой расчёта
+	NotificationOrderInvalid NotificationType = "order.invalid"
+)
+
+type NotificationChannel string
+
+const (
+	// NotificationChannelEmail письмо на адрес пользователя через SMTP
+	NotificationChannelEmail NotificationChannel = "email"
+	// NotificationChannelWebhook подписанный запрос на адрес из настроек пользователя
+	NotificationChannelWebhook NotificationChannel = "webhook"
+)
+
+type NotificationDeliveryStatus string
+
+const (
+	// NotificationDeliveryPending отправка ожидает очередной попытки
+	NotificationDeliveryPending NotificationDeliveryStatus = "pending"
+	// NotificationDeliveryDelivered уведомление отправлено
+	NotificationDeliveryDelivered NotificationDeliveryStatus = "delivered"
+	// NotificationDeliveryFailed попытки закончились
+	NotificationDeliveryFailed NotificationDeliveryStatus = "failed"
+)
+
+// NotificationPreferences Внешние каналы уведомлений пользователя. Уведомления в приложении приходят всегда
+type NotificationPreferences struct {
+	// Email письма на User.Email
+	Email      bool   `json:"email" gorm:"column:notify_email"`
+	WebhookURL string `json:"webhook_url" gorm:"column:notify_webhook_url;type:varchar"`
+	// WebhookSecret ключ подписи запросов на WebhookURL
+	WebhookSecret string `json:"-" gorm:"column:notify_webhook_secret;type:varchar"`
+}
+
+// Notification Уведомление пользователя, текст уже подставлен в шаблон
+type Notification struct {
+	gorm.Model
+	UserID      uint             `json:"user_id"`
+	Type        NotificationType `json:"type" gorm:"type:varchar"`
+	OrderNumber string           `json:"order_number" gorm:"type:varchar"`
+	Subject     string           `json:"subject" gorm:"type:varchar"`
+	Body        string           `json:"body" gorm:"type:text"`
+	ReadAt      *time.Time       `json:"read_at"`
+}
+
+// NotificationDelivery Отправка уведомления по одному внешнему каналу
+type NotificationDelivery struct {
+	gorm.Model
+	NotificationID uint                       `json:"notification_id"`
+	Channel        NotificationChannel        `json:"channel" gorm:"type:varchar"`
+	Status         NotificationDeliveryStatus `json:"status" gorm:"type:varchar"`
+	// Attempts число начатых попыток, включая текущую
+	Attempts      int        `json:"attempts"`
+	NextAttemptAt time.Time  `json:"next_attempt_at"`
+	LastError     string     `json:"last_error" gorm:"type:varchar"`
+	DeliveredAt   *time.Time `json:"delivered_at"`
+}
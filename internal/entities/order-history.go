@@ -0,0 +1,26 @@
+package entities
+
+import "gorm.io/gorm"
+
+// OrderStatusHistory Переход заказа в новый статус
+type OrderStatusHistory struct {
+	gorm.Model
+	OrderID     uint        `json:"order_id"`
+	OrderNumber string      `json:"order_number" gorm:"type:varchar"`
+	Status      OrderStatus `json:"status" gorm:"type:varchar"`
+	Accrual     float32     `json:"accrual"`
+}
+
+// AccrualAttempt Обращение к системе расчёта начислений по заказу
+type AccrualAttempt struct {
+	gorm.Model
+	OrderNumber string      `json:"order_number" gorm:"type:varchar"`
+	StatusCode  int         `json:"status_code"`
+	Status      OrderStatus `json:"status" gorm:"type:varchar"`
+	Error       string      `json:"error" gorm:"type:varchar"`
+}
+
+// SameResult Обращения дали один и тот же результат
+func (a *AccrualAttempt) SameResult(other *AccrualAttempt) bool {
+	return a.StatusCode == other.StatusCode && a.Status == other.Status && a.Error == other.Error
+}
@@ -13,10 +13,15 @@ const (
 	OrderStatusProcessed  OrderStatus = "PROCESSED"
 )
 
+// Final Конечный статус: результаты расчёта к такому заказу больше не применяются
+func (s OrderStatus) Final() bool {
+	return s == OrderStatusProcessed || s == OrderStatusInvalid
+}
+
 type Order struct {
 	gorm.Model
-	Number  string      `json:"number" gorm:"type:varchar"`
-	UserID  uint        `json:"user_id"`
+	Number  string      `json:"number" gorm:"type:varchar;not null;unique"`
+	UserID  uint        `json:"user_id" gorm:"not null"`
 	Status  OrderStatus `json:"status"`
 	Accrual float32     `json:"accrual"`
 }
@@ -0,0 +1,42 @@
+package entities
+
+import "time"
+
+type DomainEventType string
+
+const (
+	// DomainEventUserRegistered пользователь зарегистрирован
+	DomainEventUserRegistered DomainEventType = "UserRegistered"
+	// DomainEventOrderUploaded пользователь загрузил заказ
+	DomainEventOrderUploaded DomainEventType = "OrderUploaded"
+	// DomainEventOrderStatusChanged заказ перешёл в новый статус по ответу системы расчёта
+	DomainEventOrderStatusChanged DomainEventType = "OrderStatusChanged"
+	// DomainEventPointsAccrued баллы начислены на счёт
+	DomainEventPointsAccrued DomainEventType = "PointsAccrued"
+	// DomainEventPointsWithdrawn баллы списаны со счёта
+	DomainEventPointsWithdrawn DomainEventType = "PointsWithdrawn"
+)
+
+type AggregateType string
+
+const (
+	AggregateUser    AggregateType = "user"
+	AggregateOrder   AggregateType = "order"
+	AggregateAccount AggregateType = "account"
+)
+
+// OutboxEvent Доменное событие, записанное в одной транзакции с изменением состояния.
+// События одного агрегата публикуются по порядку id: следующее ждёт, пока не опубликовано предыдущее
+type OutboxEvent struct {
+	ID            uint            `json:"id" gorm:"primarykey"`
+	CreatedAt     time.Time       `json:"created_at"`
+	AggregateType AggregateType   `json:"aggregate_type" gorm:"type:varchar"`
+	AggregateID   string          `json:"aggregate_id" gorm:"type:varchar"`
+	EventType     DomainEventType `json:"event_type" gorm:"type:varchar"`
+	Payload       string          `json:"payload" gorm:"type:text"`
+	// Attempts число начатых попыток публикации, включая текущую
+	Attempts      int        `json:"attempts"`
+	NextAttemptAt time.Time  `json:"next_attempt_at"`
+	LastError     string     `json:"last_error" gorm:"type:varchar"`
+	PublishedAt   *time.Time `json:"published_at"`
+}
@@ -0,0 +1,60 @@
+package entities
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type ReconciliationDiscrepancyType string
+
+const (
+	// ReconciliationDiscrepancyTypeStatusMismatch статус заказа отличается от статуса в системе расчёта
+	ReconciliationDiscrepancyTypeStatusMismatch ReconciliationDiscrepancyType = "status_mismatch"
+	// ReconciliationDiscrepancyTypeAccrualMismatch orders.accrual отличается от начисления в системе расчёта
+	ReconciliationDiscrepancyTypeAccrualMismatch ReconciliationDiscrepancyType = "accrual_mismatch"
+	// ReconciliationDiscrepancyTypeOperationMissing по обработанному заказу нет операции начисления
+	ReconciliationDiscrepancyTypeOperationMissing ReconciliationDiscrepancyType = "operation_missing"
+	// ReconciliationDiscrepancyTypeOperationMismatch сумма операции начисления отличается от начисления в системе расчёта
+	ReconciliationDiscrepancyTypeOperationMismatch ReconciliationDiscrepancyType = "operation_mismatch"
+	// ReconciliationDiscrepancyTypeRemoteUnavailable система расчёта не вернула информацию о заказе
+	ReconciliationDiscrepancyTypeRemoteUnavailable ReconciliationDiscrepancyType = "remote_unavailable"
+	// ReconciliationDiscrepancyTypeCheckFailed заказ не удалось сверить из-за ошибки на нашей стороне
+	ReconciliationDiscrepancyTypeCheckFailed ReconciliationDiscrepancyType = "check_failed"
+)
+
+type ReconciliationRunStatus string
+
+const (
+	// ReconciliationRunStatusRunning сверка идёт или процесс остановился, не завершив её
+	ReconciliationRunStatusRunning ReconciliationRunStatus = "running"
+	// ReconciliationRunStatusCompleted проверены все заказы периода
+	ReconciliationRunStatusCompleted ReconciliationRunStatus = "completed"
+	// ReconciliationRunStatusFailed сверка прервана, проверена только часть заказов
+	ReconciliationRunStatusFailed ReconciliationRunStatus = "failed"
+)
+
+type ReconciliationRun struct {
+	gorm.Model
+	PeriodFrom    time.Time               `json:"period_from"`
+	PeriodTo      time.Time               `json:"period_to"`
+	AutoCorrect   bool                    `json:"auto_correct"`
+	Status        ReconciliationRunStatus `json:"status" gorm:"type:varchar"`
+	OrdersChecked int                     `json:"orders_checked"`
+	Discrepancies int                     `json:"discrepancies"`
+	Corrected     int                     `json:"corrected"`
+	FinishedAt    *time.Time              `json:"finished_at"`
+}
+
+type ReconciliationDiscrepancy struct {
+	gorm.Model
+	RunID         uint                          `json:"run_id"`
+	OrderNumber   string                        `json:"order_number" gorm:"type:varchar"`
+	Type          ReconciliationDiscrepancyType `json:"type" gorm:"type:varchar"`
+	LocalStatus   OrderStatus                   `json:"local_status" gorm:"type:varchar"`
+	RemoteStatus  OrderStatus                   `json:"remote_status" gorm:"type:varchar"`
+	LocalAccrual  float32                       `json:"local_accrual"`
+	RemoteAccrual float32                       `json:"remote_accrual"`
+	OperationSum  *float32                      `json:"operation_sum"`
+	Corrected     bool                          `json:"corrected"`
+}
@@ -0,0 +1,50 @@
+package entities
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type RewardMechanic string
+
+const (
+	// RewardMechanicPercent процент от стоимости позиции
+	RewardMechanicPercent RewardMechanic = "percent"
+	// RewardMechanicPoints фиксированное число баллов за единицу товара
+	RewardMechanicPoints RewardMechanic = "points"
+)
+
+// RewardRule Правило начисления баллов за товары корзины при локальном расчёте.
+// Пустые SKUPattern и Category подходят к любому товару, пустые границы ValidFrom и ValidTo не ограничивают период
+type RewardRule struct {
+	gorm.Model
+	Name       string         `json:"name" gorm:"type:varchar"`
+	Mechanic   RewardMechanic `json:"mechanic" gorm:"type:varchar"`
+	Value      float32        `json:"value"`
+	SKUPattern string         `json:"sku_pattern" gorm:"column:sku_pattern;type:varchar"`
+	Category   string         `json:"category" gorm:"type:varchar"`
+	ValidFrom  *time.Time     `json:"valid_from"`
+	ValidTo    *time.Time     `json:"valid_to"`
+	// OrderCap наибольшее начисление по правилу за один заказ
+	OrderCap *float32 `json:"order_cap"`
+	Priority int      `json:"priority"`
+	Active   bool     `json:"active"`
+}
+
+// OrderBasket Состав заказа для локального расчёта начислений
+type OrderBasket struct {
+	gorm.Model
+	OrderNumber string             `json:"order_number" gorm:"type:varchar"`
+	PurchasedAt time.Time          `json:"purchased_at"`
+	Items       []*OrderBasketItem `json:"items" gorm:"foreignKey:BasketID"`
+}
+
+type OrderBasketItem struct {
+	gorm.Model
+	BasketID uint    `json:"-"`
+	SKU      string  `json:"sku" gorm:"column:sku;type:varchar"`
+	Category string  `json:"category" gorm:"type:varchar"`
+	Price    float32 `json:"price"`
+	Quantity int     `json:"quantity"`
+}
@@ -12,4 +12,6 @@ type User struct {
 	Login      string `json:"login" gorm:"type:varchar;not null;unique"`
 	Password   string `json:"password" gorm:"type:varchar;not null"`
 	Email      string `json:"email" gorm:"type:varchar"`
+
+	Notifications NotificationPreferences `json:"notifications" gorm:"embedded"`
 }
@@ -0,0 +1,63 @@
+package entities
+
+import (
+	"slices"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type WebhookEventType string
+
+const (
+	// WebhookEventOrderProcessed заказ рассчитан
+	WebhookEventOrderProcessed WebhookEventType = "order.processed"
+	// WebhookEventOrderInvalid заказ не принят системой расчёта
+	WebhookEventOrderInvalid WebhookEventType = "order.invalid"
+	// WebhookEventBalanceWithdrawn баллы списаны в счёт оплаты заказа
+	WebhookEventBalanceWithdrawn WebhookEventType = "balance.withdrawn"
+	// WebhookEventBalanceAccrued баллы начислены за заказ
+	WebhookEventBalanceAccrued WebhookEventType = "balance.accrued"
+)
+
+type WebhookDeliveryStatus string
+
+const (
+	// WebhookDeliveryPending доставка ожидает очередной попытки
+	WebhookDeliveryPending WebhookDeliveryStatus = "pending"
+	// WebhookDeliveryDelivered получатель ответил 2xx
+	WebhookDeliveryDelivered WebhookDeliveryStatus = "delivered"
+	// WebhookDeliveryFailed попытки закончились
+	WebhookDeliveryFailed WebhookDeliveryStatus = "failed"
+)
+
+// Webhook Адрес, на который отправляются события. Секрет подписывает каждую доставку
+type Webhook struct {
+	gorm.Model
+	// UserID nil у вебхука интегратора: он получает события всех пользователей
+	UserID *uint              `json:"user_id"`
+	URL    string             `json:"url" gorm:"column:url;type:varchar"`
+	Secret string             `json:"-" gorm:"type:varchar"`
+	Events []WebhookEventType `json:"events" gorm:"serializer:json;type:text"`
+}
+
+// Subscribed Вебхук подписан на события eventType
+func (w *Webhook) Subscribed(eventType WebhookEventType) bool {
+	return slices.Contains(w.Events, eventType)
+}
+
+// WebhookDelivery Отправка одного события на один вебхук, она же запись журнала доставок
+type WebhookDelivery struct {
+	gorm.Model
+	WebhookID uint                  `json:"webhook_id"`
+	EventID   string                `json:"event_id" gorm:"type:varchar"`
+	EventType WebhookEventType      `json:"event_type" gorm:"type:varchar"`
+	Payload   string                `json:"payload" gorm:"type:text"`
+	Status    WebhookDeliveryStatus `json:"status" gorm:"type:varchar"`
+	// Attempts число начатых попыток, включая текущую
+	Attempts       int        `json:"attempts"`
+	NextAttemptAt  time.Time  `json:"next_attempt_at"`
+	LastStatusCode int        `json:"last_status_code"`
+	LastError      string     `json:"last_error" gorm:"type:varchar"`
+	DeliveredAt    *time.Time `json:"delivered_at"`
+}
@@ -0,0 +1,89 @@
+package grpcserver
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ShukinDmitriy/gophermart/internal/auth"
+	"github.com/ShukinDmitriy/gophermart/internal/pb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const bearerPrefix = "bearer "
+
+// publicMethods Методы, доступные без токена
+var publicMethods = map[string]bool{
+	pb.Gophermart_Register_FullMethodName: true,
+	pb.Gophermart_Login_FullMethodName:    true,
+}
+
+type userIDKey struct{}
+
+// UnaryAuthInterceptor Проверяет токен из заголовка authorization: Bearer <access token>
+func UnaryAuthInterceptor(authService auth.AuthServiceInterface) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if publicMethods[info.FullMethod] {
+			return handler(ctx, req)
+		}
+
+		ctx, err := authenticate(ctx, authService)
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamAuthInterceptor То же для потоковых методов
+func StreamAuthInterceptor(authService auth.AuthServiceInterface) grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if publicMethods[info.FullMethod] {
+			return handler(srv, stream)
+		}
+
+		ctx, err := authenticate(stream.Context(), authService)
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, &authenticatedStream{ServerStream: stream, ctx: ctx})
+	}
+}
+
+func authenticate(ctx context.Context, authService auth.AuthServiceInterface) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "missing bearer token")
+	}
+
+	token := values[0]
+	if len(token) <= len(bearerPrefix) || !strings.EqualFold(token[:len(bearerPrefix)], bearerPrefix) {
+		return nil, status.Error(codes.Unauthenticated, "authorization must be a bearer token")
+	}
+
+	userID, err := authService.ParseAccessToken(token[len(bearerPrefix):])
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid bearer token")
+	}
+
+	return context.WithValue(ctx, userIDKey{}, userID), nil
+}
+
+func userIDFromContext(ctx context.Context) uint {
+	userID, _ := ctx.Value(userIDKey{}).(uint)
+	return userID
+}
+
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
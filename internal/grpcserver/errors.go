@@ -0,0 +1,80 @@
+package grpcserver
+
+import (
+	"github.com/ShukinDmitriy/gophermart/internal/apierrors"
+	"github.com/ShukinDmitriy/gophermart/internal/models"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+)
+
+// errorDomain Домен ошибок в google.rpc.ErrorInfo, в Reason тот же стабильный код, что и в REST API
+const errorDomain = "gophermart"
+
+// toStatus Переводит ошибку слоя сервисов в статус gRPC. Код в ErrorInfo берётся из того же
+// сопоставления, что и в REST API
+func toStatus(err error) error {
+	code, reason := codes.Internal, apierrors.ErrorCodeInternal
+	message := "internal gophermart error"
+
+	if apiErr := apierrors.FromServiceError(err); apiErr != nil {
+		code, reason = grpcCode(apiErr.Code), apiErr.Code
+		message = err.Error()
+	}
+
+	st := status.New(code, message)
+	details := []protoadapt.MessageV1{&errdetails.ErrorInfo{Reason: reason, Domain: errorDomain}}
+
+	if violations := fieldViolations(err); len(violations) > 0 {
+		details = append(details, &errdetails.BadRequest{FieldViolations: violations})
+	}
+
+	withDetails, detailsErr := st.WithDetails(details...)
+	if detailsErr != nil {
+		return st.Err()
+	}
+
+	return withDetails.Err()
+}
+
+// grpcCode Статус gRPC для стабильного кода ошибки API
+func grpcCode(reason string) codes.Code {
+	switch reason {
+	case apierrors.ErrorCodeValidationFailed,
+		apierrors.ErrorCodeInvalidCursor,
+		apierrors.ErrorCodeBadRequest,
+		apierrors.ErrorCodeInvalidOrderNumber:
+		return codes.InvalidArgument
+	case apierrors.ErrorCodeNotFound:
+		return codes.NotFound
+	case apierrors.ErrorCodeLoginAlreadyExists,
+		apierrors.ErrorCodeOrderOwnedByAnotherUser,
+		apierrors.ErrorCodeBasketAlreadyExists:
+		return codes.AlreadyExists
+	case apierrors.ErrorCodeInvalidCredentials, apierrors.ErrorCodeUnauthorized:
+		return codes.Unauthenticated
+	case apierrors.ErrorCodeInsufficientFunds, apierrors.ErrorCodeChannelUnavailable:
+		return codes.FailedPrecondition
+	}
+
+	return codes.Unknown
+}
+
+func fieldViolations(err error) []*errdetails.BadRequest_FieldViolation {
+	var violations []*errdetails.BadRequest_FieldViolation
+	for field, rules := range models.ExtractErrors(err) {
+		ruleMap, ok := rules.(map[string]bool)
+		if !ok {
+			continue
+		}
+		for rule := range ruleMap {
+			violations = append(violations, &errdetails.BadRequest_FieldViolation{
+				Field:       field,
+				Description: rule,
+			})
+		}
+	}
+
+	return violations
+}
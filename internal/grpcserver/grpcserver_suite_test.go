@@ -0,0 +1,13 @@
+package grpcserver_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestGrpcserver(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Grpcserver Suite")
+}
@@ -0,0 +1,297 @@
+package grpcserver_test
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/ShukinDmitriy/gophermart/internal/apierrors"
+	"github.com/ShukinDmitriy/gophermart/internal/controllers"
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+	"github.com/ShukinDmitriy/gophermart/internal/grpcserver"
+	"github.com/ShukinDmitriy/gophermart/internal/metrics"
+	"github.com/ShukinDmitriy/gophermart/internal/models"
+	"github.com/ShukinDmitriy/gophermart/internal/pb"
+	appservices "github.com/ShukinDmitriy/gophermart/internal/services"
+	"github.com/ShukinDmitriy/gophermart/mocks/internal_/auth"
+	"github.com/ShukinDmitriy/gophermart/mocks/internal_/repositories"
+	"github.com/ShukinDmitriy/gophermart/mocks/internal_/services"
+	"github.com/labstack/echo/v4"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"gorm.io/gorm"
+)
+
+// REST и gRPC работают поверх одних и тех же сервисов, поэтому на одинаковых данных
+// должны отдавать одинаковые ответы и одинаковые коды ошибок
+var _ = Describe("Gophermart gRPC API", func() {
+	var e *echo.Echo
+	var server *grpc.Server
+	var conn *grpc.ClientConn
+	var client pb.GophermartClient
+	var authService *auth.AuthServiceInterface
+	var accountRepository *repositories.AccountRepositoryInterface
+	var operationRepository *repositories.OperationRepositoryInterface
+	var orderRepository *repositories.OrderRepositoryInterface
+	var userRepository *repositories.UserRepositoryInterface
+	var accrualService *services.AccrualServiceInterface
+	userID := uint(1)
+	uploadedAt := models.JSONTime(time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC))
+	account := &entities.Account{
+		Model: gorm.Model{
+			ID: 7,
+		},
+		Sum: 789.58,
+	}
+	orders := []*models.GetOrdersResponse{
+		{
+			Number:     "12345678903",
+			Status:     entities.OrderStatusProcessed,
+			Accrual:    123.45,
+			UploadedAt: uploadedAt,
+		},
+		{
+			Number:     "9278923470",
+			Status:     entities.OrderStatusProcessing,
+			UploadedAt: uploadedAt,
+		},
+	}
+	withdrawals := []models.GetWithdrawalsResponse{
+		{
+			Order:       "2377225624",
+			Sum:         500.5,
+			ProcessedAt: &uploadedAt,
+		},
+	}
+
+	request := func(method string, target string, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		return rec
+	}
+
+	authorized := func() context.Context {
+		return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer token")
+	}
+
+	errorReason := func(err error) string {
+		for _, detail := range status.Convert(err).Details() {
+			if info, ok := detail.(*errdetails.ErrorInfo); ok {
+				return info.Reason
+			}
+		}
+
+		return ""
+	}
+
+	problemCode := func(rec *httptest.ResponseRecorder) string {
+		problem := &models.Problem{}
+		Expect(json.Unmarshal(rec.Body.Bytes(), problem)).To(Succeed())
+
+		return problem.Code
+	}
+
+	BeforeEach(func() {
+		authService = new(auth.AuthServiceInterface)
+		accountRepository = new(repositories.AccountRepositoryInterface)
+		operationRepository = new(repositories.OperationRepositoryInterface)
+		orderRepository = new(repositories.OrderRepositoryInterface)
+		orderRepository.EXPECT().WithContext(mock.Anything).Return(orderRepository).Maybe()
+		userRepository = new(repositories.UserRepositoryInterface)
+		accrualService = new(services.AccrualServiceInterface)
+		authService.EXPECT().GetUserID(mock.Anything).Return(userID)
+		authService.EXPECT().ParseAccessToken("token").Return(userID, nil)
+
+		userService := appservices.NewUserService(userRepository)
+		orderService := appservices.NewOrderService(orderRepository, operationRepository, accrualService)
+		webhookService := new(services.WebhookServiceInterface)
+		webhookService.EXPECT().Emit(mock.Anything, mock.Anything).Return().Maybe()
+		ledgerService := appservices.NewLedgerService(accountRepository, operationRepository, orderRepository, webhookService, metrics.New())
+
+		e = echo.New()
+		e.HTTPErrorHandler = controllers.HTTPErrorHandler
+		controllers.RegisterRoutes(
+			e,
+			func(next echo.HandlerFunc) echo.HandlerFunc { return next },
+			controllers.NewBalanceController(authService, ledgerService),
+			controllers.NewOperationController(authService, ledgerService),
+			controllers.NewOrderController(
+				authService,
+				orderService,
+				new(services.OrderEventBrokerInterface),
+				3,
+			),
+			controllers.NewUserController(authService, userService),
+			controllers.NewWebhookController(authService, webhookService),
+			controllers.NewNotificationController(authService, new(services.NotificationServiceInterface)),
+		)
+
+		listener := bufconn.Listen(1024 * 1024)
+		server = grpcserver.NewServer(
+			authService,
+			grpcserver.NewGophermartServer(authService, userService, orderService, ledgerService),
+		)
+		go func() {
+			_ = server.Serve(listener)
+		}()
+
+		var err error
+		conn, err = grpc.NewClient(
+			"passthrough:///bufnet",
+			grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+				return listener.DialContext(ctx)
+			}),
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+		)
+		Expect(err).NotTo(HaveOccurred())
+		client = pb.NewGophermartClient(conn)
+	})
+
+	AfterEach(func() {
+		_ = conn.Close()
+		server.Stop()
+	})
+
+	It("should return the same balance as the REST API", func() {
+		accountRepository.EXPECT().FindByUserID(userID, entities.AccountTypeBonus).Return(account, nil)
+		operationRepository.EXPECT().GetWithdrawnByAccountID(account.ID).Return(456.25, nil)
+
+		rec := request(http.MethodGet, "/api/user/balance", "")
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		restBalance := map[string]float64{}
+		Expect(json.Unmarshal(rec.Body.Bytes(), &restBalance)).To(Succeed())
+
+		balance, err := client.GetBalance(authorized(), &pb.GetBalanceRequest{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(balance.GetCurrent()).To(Equal(restBalance["current"]))
+		Expect(balance.GetWithdrawn()).To(Equal(restBalance["withdrawn"]))
+	})
+
+	It("should return the same orders as the REST API", func() {
+		orderRepository.EXPECT().GetOrdersPage(models.OrderSearchFilter{
+			UserID: userID,
+			Sort:   models.SortDirectionDesc,
+			Limit:  models.GetOrdersDefaultLimit,
+		}).Return(&models.GetOrdersPage{Orders: orders}, nil)
+
+		rec := request(http.MethodGet, "/api/user/orders", "")
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		var restOrders []struct {
+			Number     string    `json:"number"`
+			Status     string    `json:"status"`
+			Accrual    float64   `json:"accrual"`
+			UploadedAt time.Time `json:"uploaded_at"`
+		}
+		Expect(json.Unmarshal(rec.Body.Bytes(), &restOrders)).To(Succeed())
+
+		res, err := client.ListOrders(authorized(), &pb.ListOrdersRequest{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.GetNextCursor()).To(BeEmpty())
+		Expect(res.GetOrders()).To(HaveLen(len(restOrders)))
+		for i, order := range res.GetOrders() {
+			Expect(order.GetNumber()).To(Equal(restOrders[i].Number))
+			Expect(order.GetStatus().String()).To(Equal("ORDER_STATUS_" + restOrders[i].Status))
+			Expect(order.GetAccrual()).To(Equal(restOrders[i].Accrual))
+			Expect(order.GetUploadedAt().AsTime()).To(BeTemporally("==", restOrders[i].UploadedAt))
+		}
+	})
+
+	It("should return the same withdrawals as the REST API", func() {
+		accountRepository.EXPECT().FindByUserID(userID, entities.AccountTypeBonus).Return(account, nil)
+		operationRepository.EXPECT().GetWithdrawalsByAccountID(account.ID).Return(withdrawals, nil)
+
+		rec := request(http.MethodGet, "/api/user/withdrawals", "")
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		var restWithdrawals []struct {
+			Order       string    `json:"order"`
+			Sum         float64   `json:"sum"`
+			ProcessedAt time.Time `json:"processed_at"`
+		}
+		Expect(json.Unmarshal(rec.Body.Bytes(), &restWithdrawals)).To(Succeed())
+
+		res, err := client.ListWithdrawals(authorized(), &pb.ListWithdrawalsRequest{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.GetWithdrawals()).To(HaveLen(len(restWithdrawals)))
+		for i, withdrawal := range res.GetWithdrawals() {
+			Expect(withdrawal.GetOrder()).To(Equal(restWithdrawals[i].Order))
+			Expect(withdrawal.GetSum()).To(Equal(restWithdrawals[i].Sum))
+			Expect(withdrawal.GetProcessedAt().AsTime()).To(BeTemporally("==", restWithdrawals[i].ProcessedAt))
+		}
+	})
+
+	It("should report insufficient funds with the same error code", func() {
+		accountRepository.EXPECT().FindByUserID(userID, entities.AccountTypeBonus).Return(account, nil)
+		orderRepository.EXPECT().FindByNumber("2377225624").Return(nil, nil)
+		operationRepository.EXPECT().CreateWithdrawn(account.ID, "2377225624", float32(1000)).Return(appservices.ErrInsufficientFunds)
+
+		rec := request(http.MethodPost, "/api/user/balance/withdraw", `{"order":"2377225624","sum":1000}`)
+		Expect(rec.Code).To(Equal(http.StatusPaymentRequired))
+
+		_, err := client.Withdraw(authorized(), &pb.WithdrawRequest{Order: "2377225624", Sum: 1000})
+		Expect(status.Code(err)).To(Equal(codes.FailedPrecondition))
+		Expect(errorReason(err)).To(Equal(problemCode(rec)))
+	})
+
+	It("should report an order of another user on withdrawal as an invalid order number", func() {
+		accountRepository.EXPECT().FindByUserID(userID, entities.AccountTypeBonus).Return(account, nil)
+		orderRepository.EXPECT().FindByNumber("2377225624").Return(&entities.Order{Number: "2377225624", UserID: userID + 1}, nil)
+
+		rec := request(http.MethodPost, "/api/user/balance/withdraw", `{"order":"2377225624","sum":100}`)
+		Expect(rec.Code).To(Equal(http.StatusUnprocessableEntity))
+
+		_, err := client.Withdraw(authorized(), &pb.WithdrawRequest{Order: "2377225624", Sum: 100})
+		Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+		Expect(errorReason(err)).To(Equal(problemCode(rec)))
+	})
+
+	It("should report an invalid order number with the same error code", func() {
+		req := httptest.NewRequest(http.MethodPost, "/api/user/orders", strings.NewReader("12345678904"))
+		req.Header.Set(echo.HeaderContentType, echo.MIMETextPlain)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		Expect(rec.Code).To(Equal(http.StatusUnprocessableEntity))
+
+		_, err := client.UploadOrder(authorized(), &pb.UploadOrderRequest{Number: "12345678904"})
+		Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+		Expect(errorReason(err)).To(Equal(problemCode(rec)))
+	})
+
+	It("should report validation errors with field violations", func() {
+		_, err := client.Withdraw(authorized(), &pb.WithdrawRequest{Order: "2377225624"})
+		Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+		Expect(errorReason(err)).To(Equal(apierrors.ErrorCodeValidationFailed))
+
+		var violations []*errdetails.BadRequest_FieldViolation
+		for _, detail := range status.Convert(err).Details() {
+			if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+				violations = badRequest.GetFieldViolations()
+			}
+		}
+		Expect(violations).To(HaveLen(1))
+		Expect(violations[0].GetDescription()).To(Equal("gt"))
+	})
+
+	It("should reject calls without a bearer token", func() {
+		_, err := client.GetBalance(context.Background(), &pb.GetBalanceRequest{})
+		Expect(status.Code(err)).To(Equal(codes.Unauthenticated))
+
+		ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Basic token")
+		_, err = client.GetBalance(ctx, &pb.GetBalanceRequest{})
+		Expect(status.Code(err)).To(Equal(codes.Unauthenticated))
+	})
+})
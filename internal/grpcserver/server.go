@@ -0,0 +1,217 @@
+package grpcserver
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ShukinDmitriy/gophermart/internal/auth"
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+	"github.com/ShukinDmitriy/gophermart/internal/models"
+	"github.com/ShukinDmitriy/gophermart/internal/pb"
+	"github.com/ShukinDmitriy/gophermart/internal/services"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const orderStatusPrefix = "ORDER_STATUS_"
+
+// GophermartServer gRPC API поверх тех же сервисов, что и REST контроллеры
+type GophermartServer struct {
+	pb.UnimplementedGophermartServer
+	authService   auth.AuthServiceInterface
+	userService   services.UserServiceInterface
+	orderService  services.OrderServiceInterface
+	ledgerService services.LedgerServiceInterface
+}
+
+func NewGophermartServer(
+	authService auth.AuthServiceInterface,
+	userService services.UserServiceInterface,
+	orderService services.OrderServiceInterface,
+	ledgerService services.LedgerServiceInterface,
+) *GophermartServer {
+	return &GophermartServer{
+		authService:   authService,
+		userService:   userService,
+		orderService:  orderService,
+		ledgerService: ledgerService,
+	}
+}
+
+// NewServer Создаёт gRPC сервер с проверкой токена и зарегистрированным GophermartServer
+func NewServer(authService auth.AuthServiceInterface, gophermartServer *GophermartServer) *grpc.Server {
+	server := grpc.NewServer(
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(UnaryAuthInterceptor(authService)),
+		grpc.ChainStreamInterceptor(StreamAuthInterceptor(authService)),
+	)
+	pb.RegisterGophermartServer(server, gophermartServer)
+
+	return server
+}
+
+// Register Регистрация пользователя
+func (s *GophermartServer) Register(_ context.Context, req *pb.RegisterRequest) (*pb.AuthResponse, error) {
+	user, err := s.userService.Register(models.UserRegisterRequest{
+		Login:    req.GetLogin(),
+		Password: req.GetPassword(),
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return s.authResponse(user)
+}
+
+// Login Аутентификация пользователя
+func (s *GophermartServer) Login(_ context.Context, req *pb.LoginRequest) (*pb.AuthResponse, error) {
+	user, err := s.userService.Login(models.UserLoginRequest{
+		Login:    req.GetLogin(),
+		Password: req.GetPassword(),
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return s.authResponse(&models.UserInfoResponse{
+		ID:         user.ID,
+		LastName:   user.LastName,
+		FirstName:  user.FirstName,
+		MiddleName: user.MiddleName,
+		Login:      user.Login,
+		Email:      user.Email,
+	})
+}
+
+// UploadOrder Загрузка номера заказа для расчёта
+func (s *GophermartServer) UploadOrder(ctx context.Context, req *pb.UploadOrderRequest) (*pb.UploadOrderResponse, error) {
+	created, err := s.orderService.UploadOrder(ctx, userIDFromContext(ctx), req.GetNumber())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &pb.UploadOrderResponse{AlreadyUploaded: !created}, nil
+}
+
+// ListOrders Список загруженных номеров заказов
+func (s *GophermartServer) ListOrders(ctx context.Context, req *pb.ListOrdersRequest) (*pb.ListOrdersResponse, error) {
+	request := models.GetOrdersRequest{
+		Limit:  int(req.GetLimit()),
+		Cursor: req.GetCursor(),
+	}
+	for _, status := range req.GetStatuses() {
+		request.Status = append(request.Status, strings.TrimPrefix(status.String(), orderStatusPrefix))
+	}
+	if req.GetAscending() {
+		request.Sort = string(models.SortDirectionAsc)
+	}
+
+	page, err := s.orderService.ListOrders(userIDFromContext(ctx), request)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	res := &pb.ListOrdersResponse{
+		Orders:     make([]*pb.Order, 0, len(page.Orders)),
+		NextCursor: page.NextCursor,
+	}
+	for _, order := range page.Orders {
+		res.Orders = append(res.Orders, &pb.Order{
+			Number:     order.Number,
+			Status:     toPBOrderStatus(order.Status),
+			Accrual:    toDouble(order.Accrual),
+			UploadedAt: timestamppb.New(time.Time(order.UploadedAt)),
+		})
+	}
+
+	return res, nil
+}
+
+// GetBalance Текущий баланс счёта баллов лояльности
+func (s *GophermartServer) GetBalance(ctx context.Context, _ *pb.GetBalanceRequest) (*pb.Balance, error) {
+	balance, err := s.ledgerService.GetBalance(userIDFromContext(ctx))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &pb.Balance{
+		Current:   toDouble(balance.Current),
+		Withdrawn: toDouble(balance.Withdrawn),
+	}, nil
+}
+
+// Withdraw Списание баллов в счёт оплаты нового заказа
+func (s *GophermartServer) Withdraw(ctx context.Context, req *pb.WithdrawRequest) (*pb.WithdrawResponse, error) {
+	err := s.ledgerService.Withdraw(userIDFromContext(ctx), models.CreateWithdrawRequest{
+		Order: req.GetOrder(),
+		Sum:   float32(req.GetSum()),
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &pb.WithdrawResponse{}, nil
+}
+
+// ListWithdrawals Списания баллов, от самых новых к самым старым
+func (s *GophermartServer) ListWithdrawals(ctx context.Context, req *pb.ListWithdrawalsRequest) (*pb.ListWithdrawalsResponse, error) {
+	page, err := s.ledgerService.ListWithdrawals(userIDFromContext(ctx), models.GetWithdrawalsRequest{
+		Limit:  int(req.GetLimit()),
+		Cursor: req.GetCursor(),
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	res := &pb.ListWithdrawalsResponse{
+		Withdrawals: make([]*pb.Withdrawal, 0, len(page.Withdrawals)),
+		NextCursor:  page.NextCursor,
+	}
+	for _, withdrawal := range page.Withdrawals {
+		item := &pb.Withdrawal{
+			Order: withdrawal.Order,
+			Sum:   toDouble(withdrawal.Sum),
+		}
+		if withdrawal.ProcessedAt != nil {
+			item.ProcessedAt = timestamppb.New(time.Time(*withdrawal.ProcessedAt))
+		}
+		res.Withdrawals = append(res.Withdrawals, item)
+	}
+
+	return res, nil
+}
+
+func (s *GophermartServer) authResponse(user *models.UserInfoResponse) (*pb.AuthResponse, error) {
+	tokens, err := s.authService.GenerateTokens(user)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &pb.AuthResponse{
+		User: &pb.User{
+			Id:         uint64(user.ID),
+			Login:      user.Login,
+			LastName:   user.LastName,
+			FirstName:  user.FirstName,
+			MiddleName: user.MiddleName,
+			Email:      user.Email,
+		},
+		AccessToken:           tokens.AccessToken,
+		AccessTokenExpiresAt:  timestamppb.New(tokens.AccessTokenExpiresAt),
+		RefreshToken:          tokens.RefreshToken,
+		RefreshTokenExpiresAt: timestamppb.New(tokens.RefreshTokenExpiresAt),
+	}, nil
+}
+
+func toPBOrderStatus(status entities.OrderStatus) pb.OrderStatus {
+	return pb.OrderStatus(pb.OrderStatus_value[orderStatusPrefix+string(status)])
+}
+
+// toDouble Суммы хранятся во float32, а прямое приведение даёт 123.44999694824219 вместо 123.45
+func toDouble(value float32) float64 {
+	res, _ := strconv.ParseFloat(strconv.FormatFloat(float64(value), 'f', -1, 32), 64)
+	return res
+}
@@ -0,0 +1,69 @@
+package logging
+
+import (
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+const loggerContextKey = "logger"
+
+// Middleware Кладёт в контекст запроса логгер с request_id (и trace_id, если запрос трассируется)
+// и по завершении пишет строку access-лога. Должен стоять после middleware.RequestID и otelecho
+func Middleware(logger *zap.Logger) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+			req := c.Request()
+			res := c.Response()
+
+			requestLogger := logger.With(RequestID(res.Header().Get(echo.HeaderXRequestID))).With(TraceID(req.Context())...)
+			c.Set(loggerContextKey, requestLogger)
+
+			// Ошибку обрабатываем здесь, чтобы в логе был итоговый статус ответа
+			if err := next(c); err != nil {
+				c.Error(err)
+			}
+
+			fields := []zap.Field{
+				zap.String("method", req.Method),
+				zap.String("uri", req.RequestURI),
+				zap.String("route", c.Path()),
+				zap.Int("status", res.Status),
+				zap.Duration("latency", time.Since(start)),
+				zap.Int64("bytes_out", res.Size),
+				zap.String("remote_ip", c.RealIP()),
+			}
+
+			log := FromContext(c)
+			switch {
+			case res.Status >= 500:
+				log.Error("request", fields...)
+			case res.Status >= 400:
+				log.Warn("request", fields...)
+			default:
+				log.Info("request", fields...)
+			}
+
+			return nil
+		}
+	}
+}
+
+// FromContext Логгер текущего запроса. Вне Middleware возвращает глобальный zap.L()
+func FromContext(c echo.Context) *zap.Logger {
+	if logger, ok := c.Get(loggerContextKey).(*zap.Logger); ok {
+		return logger
+	}
+
+	return zap.L()
+}
+
+// With Добавляет поля к логгеру запроса: они попадут во все последующие записи, включая access-лог
+func With(c echo.Context, fields ...zap.Field) *zap.Logger {
+	logger := FromContext(c).With(fields...)
+	c.Set(loggerContextKey, logger)
+
+	return logger
+}
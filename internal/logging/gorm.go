@@ -0,0 +1,72 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// GormLogger Пишет логи GORM в zap: ошибки запросов и запросы дольше slowThreshold
+type GormLogger struct {
+	logger        *zap.Logger
+	level         gormlogger.LogLevel
+	slowThreshold time.Duration
+}
+
+func NewGormLogger(logger *zap.Logger, slowThreshold time.Duration) *GormLogger {
+	return &GormLogger{
+		logger:        logger.Named("gorm"),
+		level:         gormlogger.Warn,
+		slowThreshold: slowThreshold,
+	}
+}
+
+func (l *GormLogger) LogMode(level gormlogger.LogLevel) gormlogger.Interface {
+	clone := *l
+	clone.level = level
+
+	return &clone
+}
+
+func (l *GormLogger) Info(_ context.Context, msg string, args ...interface{}) {
+	if l.level >= gormlogger.Info {
+		l.logger.Info(fmt.Sprintf(msg, args...))
+	}
+}
+
+func (l *GormLogger) Warn(_ context.Context, msg string, args ...interface{}) {
+	if l.level >= gormlogger.Warn {
+		l.logger.Warn(fmt.Sprintf(msg, args...))
+	}
+}
+
+func (l *GormLogger) Error(_ context.Context, msg string, args ...interface{}) {
+	if l.level >= gormlogger.Error {
+		l.logger.Error(fmt.Sprintf(msg, args...))
+	}
+}
+
+func (l *GormLogger) Trace(_ context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	if l.level <= gormlogger.Silent {
+		return
+	}
+
+	elapsed := time.Since(begin)
+	switch {
+	// Отсутствие записи для репозиториев штатная ситуация
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound) && l.level >= gormlogger.Error:
+		sql, rows := fc()
+		l.logger.Error("query failed", zap.Error(err), zap.String("sql", sql), zap.Int64("rows", rows), zap.Duration("elapsed", elapsed))
+	case l.slowThreshold > 0 && elapsed > l.slowThreshold && l.level >= gormlogger.Warn:
+		sql, rows := fc()
+		l.logger.Warn("slow query", zap.String("sql", sql), zap.Int64("rows", rows), zap.Duration("elapsed", elapsed))
+	case l.level >= gormlogger.Info:
+		sql, rows := fc()
+		l.logger.Debug("query", zap.String("sql", sql), zap.Int64("rows", rows), zap.Duration("elapsed", elapsed))
+	}
+}
@@ -0,0 +1,59 @@
+package logging
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// New JSON логгер. Уровень можно менять во время работы через возвращаемый zap.AtomicLevel,
+// он же умеет отдавать и принимать уровень по HTTP (GET/PUT)
+func New(level string) (*zap.Logger, zap.AtomicLevel, error) {
+	atomicLevel, err := zap.ParseAtomicLevel(level)
+	if err != nil {
+		return nil, atomicLevel, err
+	}
+
+	conf := zap.NewProductionConfig()
+	conf.Level = atomicLevel
+	conf.EncoderConfig.TimeKey = "time"
+	conf.EncoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+
+	logger, err := conf.Build()
+	if err != nil {
+		return nil, atomicLevel, err
+	}
+
+	return logger, atomicLevel, nil
+}
+
+// UserID Поле с идентификатором пользователя, одно имя для всех логов
+func UserID(userID uint) zap.Field {
+	return zap.Uint("user_id", userID)
+}
+
+// OrderNumber Поле с номером заказа, одно имя для всех логов
+func OrderNumber(number string) zap.Field {
+	return zap.String("order_number", number)
+}
+
+// RequestID Поле с идентификатором запроса
+func RequestID(requestID string) zap.Field {
+	return zap.String("request_id", requestID)
+}
+
+// TraceID Поля с идентификаторами трейса и спана из ctx, чтобы от записи лога перейти к трейсу.
+// Без активного трейса полей нет
+func TraceID(ctx context.Context) []zap.Field {
+	spanContext := trace.SpanContextFromContext(ctx)
+	if !spanContext.IsValid() {
+		return nil
+	}
+
+	return []zap.Field{
+		zap.String("trace_id", spanContext.TraceID().String()),
+		zap.String("span_id", spanContext.SpanID().String()),
+	}
+}
@@ -0,0 +1,139 @@
+package logging_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/ShukinDmitriy/gophermart/internal/logging"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+var _ = Describe("Logging", func() {
+	var core zapcore.Core
+	var logs *observer.ObservedLogs
+
+	BeforeEach(func() {
+		core, logs = observer.New(zap.DebugLevel)
+	})
+
+	Describe("New", func() {
+		It("must change the level at runtime", func() {
+			logger, level, err := logging.New("warn")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(logger.Core().Enabled(zap.InfoLevel)).To(BeFalse())
+
+			level.SetLevel(zap.DebugLevel)
+			Expect(logger.Core().Enabled(zap.DebugLevel)).To(BeTrue())
+		})
+
+		It("must reject an unknown level", func() {
+			_, _, err := logging.New("verbose")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("Middleware", func() {
+		It("must write the access log with the request ID and the fields added by the handler", func() {
+			// Arrange
+			e := echo.New()
+			e.Use(middleware.RequestID())
+			e.Use(logging.Middleware(zap.New(core)))
+			e.GET("/api/user/orders/:number", func(c echo.Context) error {
+				logging.With(c, logging.UserID(7), logging.OrderNumber(c.Param("number")))
+				logging.FromContext(c).Info("handled")
+
+				return echo.NewHTTPError(http.StatusNotFound)
+			})
+			req := httptest.NewRequest(http.MethodGet, "/api/user/orders/12345678903", nil)
+			rec := httptest.NewRecorder()
+
+			// Act
+			e.ServeHTTP(rec, req)
+
+			// Assert
+			Expect(rec.Code).To(Equal(http.StatusNotFound))
+			Expect(logs.Len()).To(Equal(2))
+
+			requestID := rec.Header().Get(echo.HeaderXRequestID)
+			Expect(requestID).NotTo(BeEmpty())
+			for _, entry := range logs.All() {
+				fields := entry.ContextMap()
+				Expect(fields).To(HaveKeyWithValue("request_id", requestID))
+				Expect(fields).To(HaveKeyWithValue("user_id", uint64(7)))
+				Expect(fields).To(HaveKeyWithValue("order_number", "12345678903"))
+			}
+
+			access := logs.FilterMessage("request").All()
+			Expect(access).To(HaveLen(1))
+			Expect(access[0].Level).To(Equal(zap.WarnLevel))
+			Expect(access[0].ContextMap()).To(HaveKeyWithValue("status", int64(http.StatusNotFound)))
+			Expect(access[0].ContextMap()).To(HaveKeyWithValue("route", "/api/user/orders/:number"))
+		})
+
+		It("must add the trace ID of a traced request", func() {
+			// Arrange
+			traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+			spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+			ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+				TraceID: traceID,
+				SpanID:  spanID,
+			}))
+
+			e := echo.New()
+			e.Use(logging.Middleware(zap.New(core)))
+			e.GET("/", func(c echo.Context) error {
+				return c.NoContent(http.StatusOK)
+			})
+			req := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx)
+
+			// Act
+			e.ServeHTTP(httptest.NewRecorder(), req)
+
+			// Assert
+			Expect(logs.Len()).To(Equal(1))
+			Expect(logs.All()[0].ContextMap()).To(HaveKeyWithValue("trace_id", traceID.String()))
+			Expect(logs.All()[0].ContextMap()).To(HaveKeyWithValue("span_id", spanID.String()))
+		})
+	})
+
+	Describe("GormLogger", func() {
+		trace := func(logger gormlogger.Interface, elapsed time.Duration, err error) {
+			logger.Trace(context.Background(), time.Now().Add(-elapsed), func() (string, int64) {
+				return "select 1", 1
+			}, err)
+		}
+
+		It("must log slow queries and failed queries", func() {
+			logger := logging.NewGormLogger(zap.New(core), 100*time.Millisecond)
+
+			trace(logger, time.Millisecond, nil)
+			trace(logger, time.Second, nil)
+			trace(logger, time.Millisecond, errors.New("test error"))
+			trace(logger, time.Millisecond, gorm.ErrRecordNotFound)
+
+			Expect(logs.FilterMessage("slow query").Len()).To(Equal(1))
+			Expect(logs.FilterMessage("query failed").Len()).To(Equal(1))
+			Expect(logs.Len()).To(Equal(2))
+		})
+
+		It("must log every query in info mode", func() {
+			logger := logging.NewGormLogger(zap.New(core), 100*time.Millisecond).LogMode(gormlogger.Info)
+
+			trace(logger, time.Millisecond, nil)
+
+			Expect(logs.FilterMessage("query").Len()).To(Equal(1))
+		})
+	})
+})
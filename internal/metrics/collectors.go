@@ -0,0 +1,53 @@
+package metrics
+
+import (
+	"database/sql"
+
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+// OrderStatusCounter Источник количества заказов по статусам, обычно репозиторий заказов
+type OrderStatusCounter interface {
+	CountByStatus() (map[entities.OrderStatus]int64, error)
+}
+
+// RegisterOrdersByStatus Количество заказов по статусам считается в БД при каждом опросе /metrics
+func (m *Metrics) RegisterOrdersByStatus(counter OrderStatusCounter) {
+	m.Registry.MustRegister(&ordersCollector{
+		counter: counter,
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "orders"),
+			"Orders by status.",
+			[]string{"status"},
+			nil,
+		),
+	})
+}
+
+// RegisterDBStats Статистика пула соединений с БД
+func (m *Metrics) RegisterDBStats(db *sql.DB) {
+	m.Registry.MustRegister(collectors.NewDBStatsCollector(db, namespace))
+}
+
+type ordersCollector struct {
+	counter OrderStatusCounter
+	desc    *prometheus.Desc
+}
+
+func (c *ordersCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *ordersCollector) Collect(ch chan<- prometheus.Metric) {
+	counts, err := c.counter.CountByStatus()
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(c.desc, err)
+		return
+	}
+
+	for _, status := range orderStatuses {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(counts[status]), string(status))
+	}
+}
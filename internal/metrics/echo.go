@@ -0,0 +1,35 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Middleware Гистограмма времени ответа по маршрутам и статусам.
+// Ошибку обработчика обрабатывает сам, чтобы учесть итоговый статус ответа
+func (m *Metrics) Middleware(skipper func(c echo.Context) bool) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if skipper != nil && skipper(c) {
+				return next(c)
+			}
+
+			start := time.Now()
+			if err := next(c); err != nil {
+				c.Error(err)
+			}
+
+			// Для ненайденных путей Echo отдаёт шаблон ближайшего узла маршрутизатора,
+			// так что число рядов ограничено набором маршрутов
+			route := c.Path()
+			if route == "" {
+				route = "unmatched"
+			}
+
+			m.ObserveHTTPRequest(c.Request().Method, route, c.Response().Status, time.Since(start))
+
+			return nil
+		}
+	}
+}
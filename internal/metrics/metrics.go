@@ -0,0 +1,191 @@
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+const namespace = "gophermart"
+
+// accrualStatusError Метка статуса для запросов, на которые система расчёта не ответила
+const accrualStatusError = "error"
+
+// orderStatuses Статусы, которые всегда есть в gophermart_orders, даже с нулевым количеством
+var orderStatuses = []entities.OrderStatus{
+	entities.OrderStatusNew,
+	entities.OrderStatusProcessing,
+	entities.OrderStatusInvalid,
+	entities.OrderStatusProcessed,
+}
+
+// Metrics Метрики приложения в собственном реестре, который отдаётся на /metrics служебного сервера
+type Metrics struct {
+	Registry *prometheus.Registry
+
+	httpRequestDuration    *prometheus.HistogramVec
+	accrualQueueDepth      prometheus.Gauge
+	accrualRequestDuration *prometheus.HistogramVec
+	accrualRateLimited     prometheus.Counter
+	accrualBackoff         prometheus.Gauge
+	pointsAccrued          prometheus.Counter
+	pointsWithdrawn        prometheus.Counter
+	webhookDeliveries      *prometheus.CounterVec
+	outboxPublished        *prometheus.CounterVec
+	outboxPending          prometheus.Gauge
+	notificationDeliveries *prometheus.CounterVec
+}
+
+func New() *Metrics {
+	m := &Metrics{
+		Registry: prometheus.NewRegistry(),
+		httpRequestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "HTTP request latency by route and response status.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		accrualQueueDepth: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "accrual",
+			Name:      "queue_depth",
+			Help:      "Orders waiting in the in-process accrual queue.",
+		}),
+		accrualRequestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "accrual",
+			Name:      "request_duration_seconds",
+			Help:      "Accrual system poll latency by response status code, \"error\" if there was no response.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"status_code"}),
+		accrualRateLimited: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "accrual",
+			Name:      "rate_limited_total",
+			Help:      "Accrual system responses with 429 Too Many Requests.",
+		}),
+		accrualBackoff: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "accrual",
+			Name:      "backoff_seconds",
+			Help:      "Current pause before the next accrual system request, 0 if not backing off.",
+		}),
+		pointsAccrued: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "ledger",
+			Name:      "points_accrued_total",
+			Help:      "Loyalty points accrued to users.",
+		}),
+		pointsWithdrawn: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "ledger",
+			Name:      "points_withdrawn_total",
+			Help:      "Loyalty points withdrawn by users.",
+		}),
+		webhookDeliveries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "webhook",
+			Name:      "delivery_attempts_total",
+			Help:      "Webhook delivery attempts by result: delivered, retry or failed.",
+		}, []string{"result"}),
+		outboxPublished: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "outbox",
+			Name:      "publish_attempts_total",
+			Help:      "Domain event publish attempts by result: published or retry.",
+		}, []string{"result"}),
+		outboxPending: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "outbox",
+			Name:      "pending_events",
+			Help:      "Domain events written to the outbox and not published yet.",
+		}),
+		notificationDeliveries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "notification",
+			Name:      "delivery_attempts_total",
+			Help:      "Notification delivery attempts by channel and result: delivered, retry or failed.",
+		}, []string{"channel", "result"}),
+	}
+
+	m.Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequestDuration,
+		m.accrualQueueDepth,
+		m.accrualRequestDuration,
+		m.accrualRateLimited,
+		m.accrualBackoff,
+		m.pointsAccrued,
+		m.pointsWithdrawn,
+		m.webhookDeliveries,
+		m.outboxPublished,
+		m.outboxPending,
+		m.notificationDeliveries,
+	)
+
+	return m
+}
+
+// ObserveHTTPRequest Запрос к API. route шаблон маршрута, а не путь, чтобы не плодить метки по номерам заказов
+func (m *Metrics) ObserveHTTPRequest(method string, route string, status int, duration time.Duration) {
+	m.httpRequestDuration.WithLabelValues(method, route, strconv.Itoa(status)).Observe(duration.Seconds())
+}
+
+// SetAccrualQueueDepth Текущая длина очереди заказов на расчёт
+func (m *Metrics) SetAccrualQueueDepth(depth int) {
+	m.accrualQueueDepth.Set(float64(depth))
+}
+
+// ObserveAccrualRequest Запрос к системе расчёта. statusCode 0 означает, что ответа не было
+func (m *Metrics) ObserveAccrualRequest(statusCode int, duration time.Duration) {
+	status := accrualStatusError
+	if statusCode != 0 {
+		status = strconv.Itoa(statusCode)
+	}
+
+	m.accrualRequestDuration.WithLabelValues(status).Observe(duration.Seconds())
+}
+
+// AccrualRateLimited Система расчёта ответила 429 и обработка приостановлена на backoff
+func (m *Metrics) AccrualRateLimited(backoff time.Duration) {
+	m.accrualRateLimited.Inc()
+	m.accrualBackoff.Set(backoff.Seconds())
+}
+
+// AccrualBackoffFinished Пауза закончилась
+func (m *Metrics) AccrualBackoffFinished() {
+	m.accrualBackoff.Set(0)
+}
+
+func (m *Metrics) PointsAccrued(sum float32) {
+	m.pointsAccrued.Add(float64(sum))
+}
+
+func (m *Metrics) PointsWithdrawn(sum float32) {
+	m.pointsWithdrawn.Add(float64(sum))
+}
+
+// WebhookDeliveryAttempt Попытка доставки вебхука: delivered, retry или failed, если попытки закончились
+func (m *Metrics) WebhookDeliveryAttempt(result string) {
+	m.webhookDeliveries.WithLabelValues(result).Inc()
+}
+
+// OutboxPublishAttempt Попытка публикации события: published или retry
+func (m *Metrics) OutboxPublishAttempt(result string, events int) {
+	m.outboxPublished.WithLabelValues(result).Add(float64(events))
+}
+
+// SetOutboxPending Число неопубликованных событий, растёт, если получатель событий недоступен
+func (m *Metrics) SetOutboxPending(pending int64) {
+	m.outboxPending.Set(float64(pending))
+}
+
+// NotificationDeliveryAttempt Попытка отправки уведомления по каналу channel: delivered, retry или failed
+func (m *Metrics) NotificationDeliveryAttempt(channel string, result string) {
+	m.notificationDeliveries.WithLabelValues(channel, result).Inc()
+}
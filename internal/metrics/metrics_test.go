@@ -0,0 +1,148 @@
+package metrics_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+	"github.com/ShukinDmitriy/gophermart/internal/metrics"
+	mocks "github.com/ShukinDmitriy/gophermart/mocks/internal_/metrics"
+	"github.com/labstack/echo/v4"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+var _ = Describe("Metrics", func() {
+	var m *metrics.Metrics
+
+	BeforeEach(func() {
+		m = metrics.New()
+	})
+
+	Describe("Middleware", func() {
+		var e *echo.Echo
+
+		BeforeEach(func() {
+			e = echo.New()
+			e.Use(m.Middleware(func(c echo.Context) bool {
+				return c.Path() == "/skipped"
+			}))
+			e.GET("/api/user/orders/:number", func(c echo.Context) error {
+				return c.NoContent(http.StatusNoContent)
+			})
+			e.GET("/failed", func(c echo.Context) error {
+				return echo.NewHTTPError(http.StatusBadRequest)
+			})
+			e.GET("/skipped", func(c echo.Context) error {
+				return c.NoContent(http.StatusOK)
+			})
+		})
+
+		serve := func(path string) *httptest.ResponseRecorder {
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+			return rec
+		}
+
+		It("must label requests by route template and status", func() {
+			serve("/api/user/orders/1")
+			serve("/api/user/orders/2")
+			rec := serve("/failed")
+			Expect(rec.Code).To(Equal(http.StatusBadRequest))
+			serve("/skipped")
+
+			Expect(testutil.GatherAndCount(m.Registry, "gophermart_http_request_duration_seconds")).To(Equal(2))
+
+			families, err := m.Registry.Gather()
+			Expect(err).NotTo(HaveOccurred())
+
+			counts := map[string]uint64{}
+			for _, family := range families {
+				if family.GetName() != "gophermart_http_request_duration_seconds" {
+					continue
+				}
+				for _, metric := range family.GetMetric() {
+					labels := map[string]string{}
+					for _, label := range metric.GetLabel() {
+						labels[label.GetName()] = label.GetValue()
+					}
+					counts[labels["method"]+" "+labels["route"]+" "+labels["status"]] = metric.GetHistogram().GetSampleCount()
+				}
+			}
+			Expect(counts).To(Equal(map[string]uint64{
+				"GET /api/user/orders/:number 204": 2,
+				"GET /failed 400":                  1,
+			}))
+		})
+	})
+
+	Describe("RegisterOrdersByStatus", func() {
+		It("must report every status, including missing ones", func() {
+			counter := new(mocks.OrderStatusCounter)
+			counter.EXPECT().CountByStatus().Return(map[entities.OrderStatus]int64{
+				entities.OrderStatusNew:       2,
+				entities.OrderStatusProcessed: 5,
+			}, nil)
+			m.RegisterOrdersByStatus(counter)
+
+			expected := `
+# HELP gophermart_orders Orders by status.
+# TYPE gophermart_orders gauge
+gophermart_orders{status="INVALID"} 0
+gophermart_orders{status="NEW"} 2
+gophermart_orders{status="PROCESSED"} 5
+gophermart_orders{status="PROCESSING"} 0
+`
+			Expect(testutil.GatherAndCompare(m.Registry, strings.NewReader(expected), "gophermart_orders")).To(Succeed())
+		})
+
+		It("must fail the scrape when the count is unavailable", func() {
+			counter := new(mocks.OrderStatusCounter)
+			counter.EXPECT().CountByStatus().Return(nil, errors.New("db is down"))
+			m.RegisterOrdersByStatus(counter)
+
+			_, err := m.Registry.Gather()
+			Expect(err).To(MatchError(ContainSubstring("db is down")))
+		})
+	})
+
+	Describe("Accrual", func() {
+		It("must track rate limiting and points", func() {
+			m.AccrualRateLimited(time.Minute)
+			m.PointsAccrued(500.5)
+			m.PointsWithdrawn(100)
+
+			expected := `
+# HELP gophermart_accrual_backoff_seconds Current pause before the next accrual system request, 0 if not backing off.
+# TYPE gophermart_accrual_backoff_seconds gauge
+gophermart_accrual_backoff_seconds 60
+# HELP gophermart_accrual_rate_limited_total Accrual system responses with 429 Too Many Requests.
+# TYPE gophermart_accrual_rate_limited_total counter
+gophermart_accrual_rate_limited_total 1
+# HELP gophermart_ledger_points_accrued_total Loyalty points accrued to users.
+# TYPE gophermart_ledger_points_accrued_total counter
+gophermart_ledger_points_accrued_total 500.5
+# HELP gophermart_ledger_points_withdrawn_total Loyalty points withdrawn by users.
+# TYPE gophermart_ledger_points_withdrawn_total counter
+gophermart_ledger_points_withdrawn_total 100
+`
+			Expect(testutil.GatherAndCompare(m.Registry, strings.NewReader(expected),
+				"gophermart_accrual_backoff_seconds",
+				"gophermart_accrual_rate_limited_total",
+				"gophermart_ledger_points_accrued_total",
+				"gophermart_ledger_points_withdrawn_total",
+			)).To(Succeed())
+
+			m.AccrualBackoffFinished()
+			Expect(testutil.GatherAndCompare(m.Registry, strings.NewReader(`
+# HELP gophermart_accrual_backoff_seconds Current pause before the next accrual system request, 0 if not backing off.
+# TYPE gophermart_accrual_backoff_seconds gauge
+gophermart_accrual_backoff_seconds 0
+`), "gophermart_accrual_backoff_seconds")).To(Succeed())
+		})
+	})
+})
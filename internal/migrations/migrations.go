@@ -0,0 +1,222 @@
+// Package migrations Применение встроенных миграций схемы базы данных
+package migrations
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+
+	"github.com/ShukinDmitriy/gophermart/db"
+	"github.com/ShukinDmitriy/gophermart/internal/sqlite"
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database"
+	"github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/database/sqlite3"
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"go.uber.org/zap"
+)
+
+const (
+	// DialectPostgres миграции из db/migrations/postgres
+	DialectPostgres = "postgres"
+	// DialectSQLite миграции из db/migrations/sqlite
+	DialectSQLite = "sqlite"
+)
+
+// sourceDir Каталог с миграциями диалекта внутри db.Migrations
+func sourceDir(dialect string) string {
+	return "migrations/" + dialect
+}
+
+// ErrSchemaBehind схема в базе старше последней встроенной миграции
+var ErrSchemaBehind = errors.New("database schema is behind")
+
+// ErrSchemaDirty последняя миграция упала на середине, нужен ручной разбор и force
+var ErrSchemaDirty = errors.New("database schema is dirty")
+
+type Migrator struct {
+	m       *migrate.Migrate
+	dialect string
+}
+
+// New Мигратор для базы databaseURI диалекта dialect. Соединение закрывает Close
+func New(dialect string, databaseURI string, logger *zap.Logger) (*Migrator, error) {
+	src, err := newSource(dialect)
+	if err != nil {
+		return nil, err
+	}
+
+	driver, err := newDriver(dialect, databaseURI)
+	if err != nil {
+		src.Close()
+		return nil, err
+	}
+
+	m, err := migrate.NewWithInstance("iofs", src, dialect, driver)
+	if err != nil {
+		src.Close()
+		driver.Close()
+		return nil, fmt.Errorf("cannot create migrate: %w", err)
+	}
+	m.Log = &migrateLogger{logger: logger}
+
+	return &Migrator{m: m, dialect: dialect}, nil
+}
+
+// Up Применяет все новые миграции. Отсутствие новых миграций не ошибка
+func (mg *Migrator) Up() error {
+	if err := mg.m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return err
+	}
+
+	return nil
+}
+
+// Down Откатывает steps последних миграций
+func (mg *Migrator) Down(steps int) error {
+	if err := mg.m.Steps(-steps); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return err
+	}
+
+	return nil
+}
+
+// DownAll Откатывает все миграции
+func (mg *Migrator) DownAll() error {
+	if err := mg.m.Down(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return err
+	}
+
+	return nil
+}
+
+// Force Записывает версию без выполнения миграций и снимает признак dirty
+func (mg *Migrator) Force(version int) error {
+	return mg.m.Force(version)
+}
+
+// Version Текущая версия схемы, 0 если миграции ещё не применялись
+func (mg *Migrator) Version() (uint, bool, error) {
+	version, dirty, err := mg.m.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		return 0, false, nil
+	}
+
+	return version, dirty, err
+}
+
+// Check Ошибка, если схема не дотягивает до последней встроенной миграции или осталась dirty.
+// Схема новее допустима: её уже подняла более свежая версия сервиса
+func (mg *Migrator) Check() error {
+	latest, err := Latest(mg.dialect)
+	if err != nil {
+		return err
+	}
+
+	version, dirty, err := mg.Version()
+	if err != nil {
+		return err
+	}
+	if dirty {
+		return fmt.Errorf("%w at version %d", ErrSchemaDirty, version)
+	}
+	if version < latest {
+		return fmt.Errorf("%w: version %d, want %d", ErrSchemaBehind, version, latest)
+	}
+
+	return nil
+}
+
+func (mg *Migrator) Close() error {
+	sourceErr, databaseErr := mg.m.Close()
+
+	return errors.Join(sourceErr, databaseErr)
+}
+
+// Latest Версия последней встроенной миграции диалекта dialect
+func Latest(dialect string) (uint, error) {
+	src, err := newSource(dialect)
+	if err != nil {
+		return 0, err
+	}
+	defer src.Close()
+
+	version, err := src.First()
+	if err != nil {
+		return 0, err
+	}
+
+	for {
+		next, err := src.Next(version)
+		if errors.Is(err, fs.ErrNotExist) {
+			return version, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		version = next
+	}
+}
+
+func newSource(dialect string) (source.Driver, error) {
+	src, err := iofs.New(db.Migrations, sourceDir(dialect))
+	if err != nil {
+		return nil, fmt.Errorf("cannot read embedded %s migrations: %w", dialect, err)
+	}
+
+	return src, nil
+}
+
+func newDriver(dialect string, databaseURI string) (database.Driver, error) {
+	switch dialect {
+	case DialectPostgres:
+		conn, err := sql.Open("postgres", databaseURI)
+		if err != nil {
+			return nil, fmt.Errorf("cannot open database: %w", err)
+		}
+
+		driver, err := postgres.WithInstance(conn, &postgres.Config{})
+		if err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("cannot create migrate driver: %w", err)
+		}
+
+		return driver, nil
+	case DialectSQLite:
+		dsn, err := sqlite.DSN(databaseURI)
+		if err != nil {
+			return nil, err
+		}
+
+		conn, err := sql.Open(sqlite.DriverName, dsn)
+		if err != nil {
+			return nil, fmt.Errorf("cannot open database: %w", err)
+		}
+
+		driver, err := sqlite3.WithInstance(conn, &sqlite3.Config{})
+		if err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("cannot create migrate driver: %w", err)
+		}
+
+		return driver, nil
+	default:
+		return nil, fmt.Errorf("unknown migrations dialect: %s", dialect)
+	}
+}
+
+// migrateLogger Сообщения golang-migrate о применённых миграциях
+type migrateLogger struct {
+	logger *zap.Logger
+}
+
+func (l *migrateLogger) Printf(format string, v ...interface{}) {
+	l.logger.Info(strings.TrimSpace(fmt.Sprintf(format, v...)))
+}
+
+func (l *migrateLogger) Verbose() bool {
+	return false
+}
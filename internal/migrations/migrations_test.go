@@ -0,0 +1,83 @@
+package migrations
+
+import (
+	"io/fs"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/ShukinDmitriy/gophermart/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+var migrationFile = regexp.MustCompile(`^(\d+)_\w+\.(up|down)\.sql$`)
+
+func TestEmbeddedMigrations(t *testing.T) {
+	for _, dialect := range []string{DialectPostgres, DialectSQLite} {
+		t.Run(dialect, func(t *testing.T) {
+			entries, err := fs.ReadDir(db.Migrations, sourceDir(dialect))
+			require.NoError(t, err)
+			require.NotEmpty(t, entries)
+
+			directions := map[uint]map[string]bool{}
+			var minVersion, maxVersion uint
+			for _, entry := range entries {
+				match := migrationFile.FindStringSubmatch(entry.Name())
+				require.NotNil(t, match, "unexpected file %s", entry.Name())
+
+				version, err := strconv.ParseUint(match[1], 10, 64)
+				require.NoError(t, err)
+				if directions[uint(version)] == nil {
+					directions[uint(version)] = map[string]bool{}
+				}
+				directions[uint(version)][match[2]] = true
+				if minVersion == 0 || uint(version) < minVersion {
+					minVersion = uint(version)
+				}
+				maxVersion = max(maxVersion, uint(version))
+			}
+
+			for version := minVersion; version <= maxVersion; version++ {
+				assert.Equal(t, map[string]bool{"up": true, "down": true}, directions[version], "migration %d", version)
+			}
+
+			latest, err := Latest(dialect)
+			require.NoError(t, err)
+			assert.Equal(t, maxVersion, latest)
+		})
+	}
+}
+
+// TestDialectsInSync Изменение схемы добавляется во все диалекты под одним номером
+func TestDialectsInSync(t *testing.T) {
+	postgresLatest, err := Latest(DialectPostgres)
+	require.NoError(t, err)
+	sqliteLatest, err := Latest(DialectSQLite)
+	require.NoError(t, err)
+
+	assert.Equal(t, postgresLatest, sqliteLatest)
+}
+
+func TestMigrator_SQLite(t *testing.T) {
+	migrator, err := New(DialectSQLite, t.TempDir()+"/gophermart.db", zap.NewNop())
+	require.NoError(t, err)
+	defer migrator.Close()
+
+	require.Error(t, migrator.Check())
+	require.NoError(t, migrator.Up())
+	require.NoError(t, migrator.Check())
+
+	version, dirty, err := migrator.Version()
+	require.NoError(t, err)
+	latest, err := Latest(DialectSQLite)
+	require.NoError(t, err)
+	assert.Equal(t, latest, version)
+	assert.False(t, dirty)
+
+	require.NoError(t, migrator.DownAll())
+	version, _, err = migrator.Version()
+	require.NoError(t, err)
+	assert.Zero(t, version)
+}
@@ -2,8 +2,13 @@ package models
 
 import "github.com/ShukinDmitriy/gophermart/internal/entities"
 
+// AccrualStatusRegistered заказ принят системой расчёта, расчёт не начат. Статусом заказа не бывает
+const AccrualStatusRegistered entities.OrderStatus = "REGISTERED"
+
+// AccrualOrderResponse Результат расчёта по заказу: ответ системы расчёта на опрос или присланный ею результат.
+// Теги validate проверяют присланный результат
 type AccrualOrderResponse struct {
-	Order   string               `json:"order"`
-	Status  entities.OrderStatus `json:"status"`
-	Accrual float32              `json:"accrual"`
+	Order   string               `json:"order" validate:"required"`
+	Status  entities.OrderStatus `json:"status" validate:"oneof=REGISTERED PROCESSING INVALID PROCESSED"`
+	Accrual float32              `json:"accrual" validate:"gte=0"`
 }
@@ -0,0 +1,19 @@
+package models
+
+type CreateOrdersBatchResult string
+
+const (
+	// CreateOrdersBatchResultAccepted номер принят в обработку
+	CreateOrdersBatchResultAccepted CreateOrdersBatchResult = "accepted"
+	// CreateOrdersBatchResultAlreadyUploaded номер уже был загружен этим пользователем
+	CreateOrdersBatchResultAlreadyUploaded CreateOrdersBatchResult = "already_uploaded"
+	// CreateOrdersBatchResultConflict номер уже был загружен другим пользователем
+	CreateOrdersBatchResultConflict CreateOrdersBatchResult = "conflict"
+	// CreateOrdersBatchResultInvalidFormat неверный формат номера заказа
+	CreateOrdersBatchResultInvalidFormat CreateOrdersBatchResult = "invalid_format"
+)
+
+type CreateOrdersBatchResponse struct {
+	Number string                  `json:"number"`
+	Result CreateOrdersBatchResult `json:"result"`
+}
@@ -1,6 +1,6 @@
 package models
 
 type CreateWithdrawRequest struct {
-	Order string  `json:"order"`
-	Sum   float32 `json:"sum"`
+	Order string  `json:"order" validate:"required"`
+	Sum   float32 `json:"sum" validate:"gt=0"`
 }
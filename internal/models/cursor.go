@@ -0,0 +1,36 @@
+package models
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor Позиция последней отданной записи для постраничной выборки
+type Cursor struct {
+	Time time.Time `json:"t"`
+	ID   uint      `json:"id"`
+}
+
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func DecodeCursor(value string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	cursor := &Cursor{}
+	if err = json.Unmarshal(data, cursor); err != nil || cursor.ID == 0 {
+		return nil, ErrInvalidCursor
+	}
+
+	return cursor, nil
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCursor_Encode(t *testing.T) {
+	uploadedAt, _ := time.Parse(time.RFC3339Nano, "2009-11-10T23:00:00.123456Z")
+	cursor := Cursor{Time: uploadedAt, ID: 42}
+
+	decoded, err := DecodeCursor(cursor.Encode())
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(42), decoded.ID)
+	assert.True(t, uploadedAt.Equal(decoded.Time))
+}
+
+func TestDecodeCursor(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{
+			name:  "Not base64",
+			value: "!!!",
+		},
+		{
+			name:  "Not json",
+			value: "dGVzdA",
+		},
+		{
+			name:  "Without id",
+			value: "e30",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DecodeCursor(tt.value)
+			assert.ErrorIs(t, err, ErrInvalidCursor)
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package models
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+)
+
+// DomainEvent Доменное событие в том виде, в котором оно уходит во внешние системы.
+// ID не меняется при повторной публикации, по нему получатель отбрасывает дубли
+type DomainEvent struct {
+	ID            string                   `json:"id"`
+	Type          entities.DomainEventType `json:"type"`
+	AggregateType entities.AggregateType   `json:"aggregate_type"`
+	AggregateID   string                   `json:"aggregate_id"`
+	OccurredAt    JSONTime                 `json:"occurred_at"`
+	Payload       json.RawMessage          `json:"payload"`
+}
+
+// Key Ключ агрегата: события с одним ключом публикуются по порядку
+func (e DomainEvent) Key() string {
+	return string(e.AggregateType) + ":" + e.AggregateID
+}
+
+func MapDomainEvent(event *entities.OutboxEvent) DomainEvent {
+	return DomainEvent{
+		ID:            strconv.FormatUint(uint64(event.ID), 10),
+		Type:          event.EventType,
+		AggregateType: event.AggregateType,
+		AggregateID:   event.AggregateID,
+		OccurredAt:    JSONTime(event.CreatedAt),
+		Payload:       json.RawMessage(event.Payload),
+	}
+}
+
+type UserRegisteredEvent struct {
+	UserID uint   `json:"user_id"`
+	Login  string `json:"login"`
+}
+
+type OrderUploadedEvent struct {
+	Order  string `json:"order"`
+	UserID uint   `json:"user_id"`
+}
+
+type OrderStatusChangedEvent struct {
+	Order          string               `json:"order"`
+	UserID         uint                 `json:"user_id"`
+	Status         entities.OrderStatus `json:"status"`
+	PreviousStatus entities.OrderStatus `json:"previous_status"`
+	Accrual        float32              `json:"accrual"`
+}
+
+// PointsEvent Тело PointsAccrued и PointsWithdrawn
+type PointsEvent struct {
+	AccountID uint    `json:"account_id"`
+	UserID    uint    `json:"user_id"`
+	Order     string  `json:"order"`
+	Sum       float32 `json:"sum"`
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMapDomainEvent(t *testing.T) {
+	event := MapDomainEvent(&entities.OutboxEvent{
+		ID:            42,
+		CreatedAt:     time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		AggregateType: entities.AggregateOrder,
+		AggregateID:   "12345678903",
+		EventType:     entities.DomainEventOrderStatusChanged,
+		Payload:       `{"order":"12345678903","user_id":1,"status":"PROCESSED","previous_status":"NEW","accrual":500}`,
+		Attempts:      2,
+	})
+
+	data, err := json.Marshal(event)
+	require.NoError(t, err)
+
+	assert.Equal(t, "order:12345678903", event.Key())
+	assert.JSONEq(t, `{
+		"id": "42",
+		"type": "OrderStatusChanged",
+		"aggregate_type": "order",
+		"aggregate_id": "12345678903",
+		"occurred_at": "2024-05-01T12:00:00Z",
+		"payload": {"order":"12345678903","user_id":1,"status":"PROCESSED","previous_status":"NEW","accrual":500}
+	}`, string(data))
+}
@@ -0,0 +1,11 @@
+package models
+
+const (
+	GetNotificationsDefaultLimit = 100
+)
+
+type GetNotificationsRequest struct {
+	Limit int `query:"limit" validate:"omitempty,min=1,max=1000"`
+	// Unread только непрочитанные
+	Unread bool `query:"unread"`
+}
@@ -0,0 +1,7 @@
+package models
+
+type GetNotificationsResponse struct {
+	// Unread всего непрочитанных, не только на этой странице
+	Unread        int64                  `json:"unread"`
+	Notifications []NotificationResponse `json:"notifications"`
+}
@@ -0,0 +1,13 @@
+package models
+
+import "github.com/ShukinDmitriy/gophermart/internal/entities"
+
+// GetOperationsResponse Движение по счёту. Sum положительна для поступлений и отрицательна для списаний,
+// Balance — остаток на счёте после операции
+type GetOperationsResponse struct {
+	Type        entities.OperationType `json:"type"`
+	Order       string                 `json:"order"`
+	Sum         float32                `json:"sum"`
+	Balance     float32                `json:"balance"`
+	ProcessedAt JSONTime               `json:"processed_at"`
+}
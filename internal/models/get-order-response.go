@@ -0,0 +1,78 @@
+package models
+
+import (
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+)
+
+type GetOrderResponse struct {
+	Number          string                        `json:"number"`
+	Status          entities.OrderStatus          `json:"status"`
+	Accrual         float32                       `json:"accrual,omitempty"`
+	UploadedAt      JSONTime                      `json:"uploaded_at"`
+	History         []OrderStatusHistoryResponse  `json:"history"`
+	AccrualAttempts []OrderAccrualAttemptResponse `json:"accrual_attempts"`
+	Operations      []OrderOperationResponse      `json:"operations"`
+}
+
+type OrderStatusHistoryResponse struct {
+	Status    entities.OrderStatus `json:"status"`
+	Accrual   float32              `json:"accrual,omitempty"`
+	ChangedAt JSONTime             `json:"changed_at"`
+}
+
+type OrderAccrualAttemptResponse struct {
+	StatusCode  int                  `json:"status_code"`
+	Status      entities.OrderStatus `json:"status,omitempty"`
+	Error       string               `json:"error,omitempty"`
+	AttemptedAt JSONTime             `json:"attempted_at"`
+}
+
+type OrderOperationResponse struct {
+	Type        entities.OperationType `json:"type"`
+	Sum         float32                `json:"sum"`
+	ProcessedAt JSONTime               `json:"processed_at"`
+}
+
+func MapOrderToGetOrderResponse(
+	order *entities.Order,
+	history []*entities.OrderStatusHistory,
+	attempts []*entities.AccrualAttempt,
+	operations []*entities.Operation,
+) GetOrderResponse {
+	res := GetOrderResponse{
+		Number:          order.Number,
+		Status:          order.Status,
+		Accrual:         order.Accrual,
+		UploadedAt:      JSONTime(order.CreatedAt),
+		History:         make([]OrderStatusHistoryResponse, 0, len(history)),
+		AccrualAttempts: make([]OrderAccrualAttemptResponse, 0, len(attempts)),
+		Operations:      make([]OrderOperationResponse, 0, len(operations)),
+	}
+
+	for _, h := range history {
+		res.History = append(res.History, OrderStatusHistoryResponse{
+			Status:    h.Status,
+			Accrual:   h.Accrual,
+			ChangedAt: JSONTime(h.CreatedAt),
+		})
+	}
+
+	for _, a := range attempts {
+		res.AccrualAttempts = append(res.AccrualAttempts, OrderAccrualAttemptResponse{
+			StatusCode:  a.StatusCode,
+			Status:      a.Status,
+			Error:       a.Error,
+			AttemptedAt: JSONTime(a.CreatedAt),
+		})
+	}
+
+	for _, o := range operations {
+		res.Operations = append(res.Operations, OrderOperationResponse{
+			Type:        o.Type,
+			Sum:         o.Sum,
+			ProcessedAt: JSONTime(o.ProcessedAt),
+		})
+	}
+
+	return res
+}
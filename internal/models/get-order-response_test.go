@@ -0,0 +1,51 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestMapOrderToGetOrderResponse(t *testing.T) {
+	uploadedAt, _ := time.Parse("2006-01-02 15:04:05", "2009-11-10 23:00:00")
+	processedAt := uploadedAt.Add(time.Minute)
+
+	order := &entities.Order{
+		Model:   gorm.Model{ID: 1, CreatedAt: uploadedAt},
+		Number:  "12345678903",
+		Status:  entities.OrderStatusProcessed,
+		Accrual: 500,
+	}
+	history := []*entities.OrderStatusHistory{
+		{Model: gorm.Model{CreatedAt: uploadedAt}, Status: entities.OrderStatusNew},
+		{Model: gorm.Model{CreatedAt: processedAt}, Status: entities.OrderStatusProcessed, Accrual: 500},
+	}
+	attempts := []*entities.AccrualAttempt{
+		{Model: gorm.Model{CreatedAt: processedAt}, StatusCode: 200, Status: entities.OrderStatusProcessed},
+	}
+	operations := []*entities.Operation{
+		{ProcessedAt: processedAt, Type: entities.OperationTypeAccrual, Sum: 500},
+	}
+
+	got := MapOrderToGetOrderResponse(order, history, attempts, operations)
+
+	assert.Equal(t, GetOrderResponse{
+		Number:     "12345678903",
+		Status:     entities.OrderStatusProcessed,
+		Accrual:    500,
+		UploadedAt: JSONTime(uploadedAt),
+		History: []OrderStatusHistoryResponse{
+			{Status: entities.OrderStatusNew, ChangedAt: JSONTime(uploadedAt)},
+			{Status: entities.OrderStatusProcessed, Accrual: 500, ChangedAt: JSONTime(processedAt)},
+		},
+		AccrualAttempts: []OrderAccrualAttemptResponse{
+			{StatusCode: 200, Status: entities.OrderStatusProcessed, AttemptedAt: JSONTime(processedAt)},
+		},
+		Operations: []OrderOperationResponse{
+			{Type: entities.OperationTypeAccrual, Sum: 500, ProcessedAt: JSONTime(processedAt)},
+		},
+	}, got)
+}
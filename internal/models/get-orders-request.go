@@ -0,0 +1,89 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+)
+
+const (
+	GetOrdersDefaultLimit = 50
+	GetOrdersMaxLimit     = 1000
+)
+
+type SortDirection string
+
+const (
+	SortDirectionAsc  SortDirection = "asc"
+	SortDirectionDesc SortDirection = "desc"
+)
+
+type GetOrdersRequest struct {
+	Limit        int      `query:"limit" validate:"omitempty,min=1,max=1000"`
+	Cursor       string   `query:"cursor"`
+	Status       []string `query:"status" validate:"dive,oneof=NEW PROCESSING INVALID PROCESSED"`
+	UploadedFrom string   `query:"uploaded_from" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	UploadedTo   string   `query:"uploaded_to" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	Sort         string   `query:"sort" validate:"omitempty,oneof=asc desc"`
+}
+
+// Normalize Разбирает статусы, переданные через запятую (status=NEW,PROCESSING)
+func (r *GetOrdersRequest) Normalize() {
+	var statuses []string
+	for _, status := range r.Status {
+		for _, s := range strings.Split(status, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				statuses = append(statuses, strings.ToUpper(s))
+			}
+		}
+	}
+	r.Status = statuses
+}
+
+func (r *GetOrdersRequest) ToFilter(userID uint) (OrderSearchFilter, error) {
+	filter := OrderSearchFilter{
+		UserID: userID,
+		Limit:  r.Limit,
+		Sort:   SortDirection(r.Sort),
+	}
+
+	if filter.Limit == 0 {
+		filter.Limit = GetOrdersDefaultLimit
+	}
+
+	// по спецификации заказы отдаются от самых новых к самым старым
+	if filter.Sort == "" {
+		filter.Sort = SortDirectionDesc
+	}
+
+	for _, status := range r.Status {
+		filter.Statuses = append(filter.Statuses, entities.OrderStatus(status))
+	}
+
+	if r.UploadedFrom != "" {
+		uploadedFrom, err := time.Parse(time.RFC3339, r.UploadedFrom)
+		if err != nil {
+			return filter, err
+		}
+		filter.UploadedFrom = &uploadedFrom
+	}
+
+	if r.UploadedTo != "" {
+		uploadedTo, err := time.Parse(time.RFC3339, r.UploadedTo)
+		if err != nil {
+			return filter, err
+		}
+		filter.UploadedTo = &uploadedTo
+	}
+
+	if r.Cursor != "" {
+		cursor, err := DecodeCursor(r.Cursor)
+		if err != nil {
+			return filter, err
+		}
+		filter.Cursor = cursor
+	}
+
+	return filter, nil
+}
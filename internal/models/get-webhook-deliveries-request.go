@@ -0,0 +1,22 @@
+package models
+
+import "github.com/ShukinDmitriy/gophermart/internal/entities"
+
+const (
+	GetWebhookDeliveriesDefaultLimit = 100
+)
+
+type GetWebhookDeliveriesRequest struct {
+	Limit  int      `query:"limit" validate:"omitempty,min=1,max=1000"`
+	Status []string `query:"status" validate:"dive,oneof=pending delivered failed"`
+}
+
+// Statuses Статусы фильтра в типе сущности, пустой список не фильтрует
+func (r *GetWebhookDeliveriesRequest) Statuses() []entities.WebhookDeliveryStatus {
+	statuses := make([]entities.WebhookDeliveryStatus, 0, len(r.Status))
+	for _, status := range r.Status {
+		statuses = append(statuses, entities.WebhookDeliveryStatus(status))
+	}
+
+	return statuses
+}
@@ -0,0 +1,129 @@
+package models
+
+import (
+	"time"
+
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+)
+
+const (
+	GetOperationsDefaultLimit = 50
+)
+
+type GetWithdrawalsRequest struct {
+	Limit         int    `query:"limit" validate:"omitempty,min=1,max=1000"`
+	Cursor        string `query:"cursor"`
+	Order         string `query:"order" validate:"omitempty,numeric"`
+	ProcessedFrom string `query:"processed_from" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	ProcessedTo   string `query:"processed_to" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	Sort          string `query:"sort" validate:"omitempty,oneof=asc desc"`
+}
+
+// IsEmpty Клиент не передал ни одного параметра, нужно вернуть все списания как раньше
+func (r *GetWithdrawalsRequest) IsEmpty() bool {
+	return r.Limit == 0 &&
+		r.Cursor == "" &&
+		r.Order == "" &&
+		r.ProcessedFrom == "" &&
+		r.ProcessedTo == "" &&
+		r.Sort == ""
+}
+
+// ToFilter Списания по спецификации отдаются от самых новых к самым старым
+func (r *GetWithdrawalsRequest) ToFilter(accountID uint) (OperationSearchFilter, error) {
+	return newOperationSearchFilter(
+		accountID,
+		[]entities.OperationType{entities.OperationTypeWithdraw},
+		r.Order,
+		r.ProcessedFrom,
+		r.ProcessedTo,
+		r.Sort,
+		SortDirectionDesc,
+		r.Limit,
+		r.Cursor,
+	)
+}
+
+type GetOperationsRequest struct {
+	Limit         int      `query:"limit" validate:"omitempty,min=1,max=1000"`
+	Cursor        string   `query:"cursor"`
+	Type          []string `query:"type" validate:"dive,oneof=accrual withdraw"`
+	Order         string   `query:"order" validate:"omitempty,numeric"`
+	ProcessedFrom string   `query:"processed_from" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	ProcessedTo   string   `query:"processed_to" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	Sort          string   `query:"sort" validate:"omitempty,oneof=asc desc"`
+}
+
+// ToFilter Движения по счёту по умолчанию отдаются в хронологическом порядке
+func (r *GetOperationsRequest) ToFilter(accountID uint) (OperationSearchFilter, error) {
+	var types []entities.OperationType
+	for _, operationType := range r.Type {
+		types = append(types, entities.OperationType(operationType))
+	}
+
+	return newOperationSearchFilter(
+		accountID,
+		types,
+		r.Order,
+		r.ProcessedFrom,
+		r.ProcessedTo,
+		r.Sort,
+		SortDirectionAsc,
+		r.Limit,
+		r.Cursor,
+	)
+}
+
+func newOperationSearchFilter(
+	accountID uint,
+	types []entities.OperationType,
+	order string,
+	processedFrom string,
+	processedTo string,
+	sort string,
+	defaultSort SortDirection,
+	limit int,
+	cursor string,
+) (OperationSearchFilter, error) {
+	filter := OperationSearchFilter{
+		AccountID: accountID,
+		Types:     types,
+		Order:     order,
+		Sort:      SortDirection(sort),
+		Limit:     limit,
+	}
+
+	if filter.Limit == 0 {
+		filter.Limit = GetOperationsDefaultLimit
+	}
+
+	if filter.Sort == "" {
+		filter.Sort = defaultSort
+	}
+
+	if processedFrom != "" {
+		from, err := time.Parse(time.RFC3339, processedFrom)
+		if err != nil {
+			return filter, err
+		}
+		filter.ProcessedFrom = &from
+	}
+
+	if processedTo != "" {
+		to, err := time.Parse(time.RFC3339, processedTo)
+		if err != nil {
+			return filter, err
+		}
+		filter.ProcessedTo = &to
+	}
+
+	if cursor != "" {
+		c, err := DecodeCursor(cursor)
+		if err != nil {
+			return filter, err
+		}
+		filter.Cursor = c
+	}
+
+	return filter, nil
+}
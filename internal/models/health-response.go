@@ -0,0 +1,51 @@
+package models
+
+const (
+	HealthStatusOK   = "ok"
+	HealthStatusFail = "fail"
+)
+
+// HealthCheck Результат одной проверки
+type HealthCheck struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// ReadinessResponse Готовность принимать трафик, ответ /readyz
+type ReadinessResponse struct {
+	Status string                 `json:"status"`
+	Checks map[string]HealthCheck `json:"checks"`
+}
+
+// AccrualQueueStats Состояние очереди заказов на расчёт.
+// LastWaitMs сколько ждал в очереди последний взятый в обработку заказ
+type AccrualQueueStats struct {
+	Depth      int   `json:"depth"`
+	Capacity   int   `json:"capacity"`
+	LastWaitMs int64 `json:"last_wait_ms"`
+}
+
+// AccrualHealth Доступность системы расчёта и состояние обработчиков очереди
+type AccrualHealth struct {
+	Reachable       bool              `json:"reachable"`
+	LatencyMs       int64             `json:"latency_ms"`
+	Error           string            `json:"error,omitempty"`
+	WorkersRunning  int               `json:"workers_running"`
+	WorkersExpected int               `json:"workers_expected"`
+	Queue           AccrualQueueStats `json:"queue"`
+}
+
+// MigrationsHealth Версия схемы БД и версия, которую ожидает приложение
+type MigrationsHealth struct {
+	Version  uint `json:"version"`
+	Expected uint `json:"expected"`
+	Dirty    bool `json:"dirty"`
+}
+
+// HealthResponse Подробное состояние сервиса для администраторов, ответ /health служебного сервера
+type HealthResponse struct {
+	ReadinessResponse
+	ShuttingDown bool             `json:"shutting_down"`
+	Accrual      AccrualHealth    `json:"accrual"`
+	Migrations   MigrationsHealth `json:"migrations"`
+}
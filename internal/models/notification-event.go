@@ -0,0 +1,11 @@
+package models
+
+import "github.com/ShukinDmitriy/gophermart/internal/entities"
+
+// NotificationEvent Исход обработки заказа, о котором уведомляется пользователь
+type NotificationEvent struct {
+	Type    entities.NotificationType
+	UserID  uint
+	Order   string
+	Accrual float32
+}
@@ -0,0 +1,8 @@
+package models
+
+type NotificationPreferencesRequest struct {
+	Email        string `json:"email" validate:"required_if=EmailEnabled true,omitempty,email,max=254"`
+	EmailEnabled bool   `json:"email_enabled"`
+	// WebhookURL пустой отключает уведомления вебхуком
+	WebhookURL string `json:"webhook_url" validate:"omitempty,http_url,max=2048"`
+}
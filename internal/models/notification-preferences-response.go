@@ -0,0 +1,19 @@
+package models
+
+import "github.com/ShukinDmitriy/gophermart/internal/entities"
+
+type NotificationPreferencesResponse struct {
+	Email        string `json:"email"`
+	EmailEnabled bool   `json:"email_enabled"`
+	WebhookURL   string `json:"webhook_url"`
+	// WebhookSecret ключ подписи уведомлений, отдаётся только при смене WebhookURL
+	WebhookSecret string `json:"webhook_secret,omitempty"`
+}
+
+func MapNotificationPreferences(user *entities.User) NotificationPreferencesResponse {
+	return NotificationPreferencesResponse{
+		Email:        user.Email,
+		EmailEnabled: user.Notifications.Email,
+		WebhookURL:   user.Notifications.WebhookURL,
+	}
+}
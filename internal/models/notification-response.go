@@ -0,0 +1,27 @@
+package models
+
+import "github.com/ShukinDmitriy/gophermart/internal/entities"
+
+type NotificationResponse struct {
+	ID        uint                      `json:"id"`
+	Type      entities.NotificationType `json:"type"`
+	Order     string                    `json:"order,omitempty"`
+	Subject   string                    `json:"subject"`
+	Body      string                    `json:"body"`
+	Read      bool                      `json:"read"`
+	ReadAt    *JSONTime                 `json:"read_at,omitempty"`
+	CreatedAt JSONTime                  `json:"created_at"`
+}
+
+func MapNotification(notification *entities.Notification) NotificationResponse {
+	return NotificationResponse{
+		ID:        notification.ID,
+		Type:      notification.Type,
+		Order:     notification.OrderNumber,
+		Subject:   notification.Subject,
+		Body:      notification.Body,
+		Read:      notification.ReadAt != nil,
+		ReadAt:    jsonTimePtr(notification.ReadAt),
+		CreatedAt: JSONTime(notification.CreatedAt),
+	}
+}
@@ -0,0 +1,75 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestMapNotification(t *testing.T) {
+	createdAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	readAt := createdAt.Add(time.Hour)
+
+	tests := []struct {
+		name         string
+		notification entities.Notification
+		want         string
+	}{
+		{
+			name: "unread",
+			notification: entities.Notification{
+				Model:       gorm.Model{ID: 3, CreatedAt: createdAt},
+				UserID:      1,
+				Type:        entities.NotificationOrderProcessed,
+				OrderNumber: "12345678903",
+				Subject:     "Order 12345678903 is processed",
+				Body:        "500 points accrued",
+			},
+			want: `{
+				"id": 3,
+				"type": "order.processed",
+				"order": "12345678903",
+				"subject": "Order 12345678903 is processed",
+				"body": "500 points accrued",
+				"read": false,
+				"created_at": "2024-05-01T12:00:00Z"
+			}`,
+		},
+		{
+			name: "read",
+			notification: entities.Notification{
+				Model:       gorm.Model{ID: 4, CreatedAt: createdAt},
+				UserID:      1,
+				Type:        entities.NotificationOrderInvalid,
+				OrderNumber: "9278923470",
+				Subject:     "Order 9278923470 is rejected",
+				Body:        "No points",
+				ReadAt:      &readAt,
+			},
+			want: `{
+				"id": 4,
+				"type": "order.invalid",
+				"order": "9278923470",
+				"subject": "Order 9278923470 is rejected",
+				"body": "No points",
+				"read": true,
+				"read_at": "2024-05-01T13:00:00Z",
+				"created_at": "2024-05-01T12:00:00Z"
+			}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(MapNotification(&tt.notification))
+			require.NoError(t, err)
+
+			assert.JSONEq(t, tt.want, string(got))
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+)
+
+type OperationSearchFilter struct {
+	AccountID     uint
+	Types         []entities.OperationType
+	Order         string
+	ProcessedFrom *time.Time
+	ProcessedTo   *time.Time
+	Sort          SortDirection
+	Limit         int
+	Cursor        *Cursor
+}
+
+type GetWithdrawalsPage struct {
+	Withdrawals []GetWithdrawalsResponse
+	NextCursor  string
+}
+
+type GetOperationsPage struct {
+	Operations []GetOperationsResponse
+	NextCursor string
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// OrderBasketRequest Состав заказа для локального расчёта начислений. Без PurchasedAt заказ считается купленным сейчас
+type OrderBasketRequest struct {
+	Order       string                   `json:"order" validate:"required"`
+	PurchasedAt *time.Time               `json:"purchased_at"`
+	Items       []OrderBasketItemRequest `json:"items" validate:"required,min=1,max=1000,dive"`
+}
+
+type OrderBasketItemRequest struct {
+	SKU      string  `json:"sku" validate:"required,max=128"`
+	Category string  `json:"category" validate:"max=128"`
+	Price    float32 `json:"price" validate:"gte=0"`
+	Quantity int     `json:"quantity" validate:"gt=0"`
+}
@@ -0,0 +1,34 @@
+package models
+
+import "github.com/ShukinDmitriy/gophermart/internal/entities"
+
+type OrderBasketResponse struct {
+	Order       string                    `json:"order"`
+	PurchasedAt JSONTime                  `json:"purchased_at"`
+	Items       []OrderBasketItemResponse `json:"items"`
+}
+
+type OrderBasketItemResponse struct {
+	SKU      string  `json:"sku"`
+	Category string  `json:"category"`
+	Price    float32 `json:"price"`
+	Quantity int     `json:"quantity"`
+}
+
+func MapOrderBasket(basket *entities.OrderBasket) OrderBasketResponse {
+	items := make([]OrderBasketItemResponse, 0, len(basket.Items))
+	for _, item := range basket.Items {
+		items = append(items, OrderBasketItemResponse{
+			SKU:      item.SKU,
+			Category: item.Category,
+			Price:    item.Price,
+			Quantity: item.Quantity,
+		})
+	}
+
+	return OrderBasketResponse{
+		Order:       basket.OrderNumber,
+		PurchasedAt: JSONTime(basket.PurchasedAt),
+		Items:       items,
+	}
+}
@@ -0,0 +1,21 @@
+package models
+
+import "github.com/ShukinDmitriy/gophermart/internal/entities"
+
+type OrderEventType string
+
+const (
+	// OrderEventTypeStatusChanged изменился статус заказа
+	OrderEventTypeStatusChanged OrderEventType = "order.status_changed"
+	// OrderEventTypeAccrued по заказу начислены баллы
+	OrderEventTypeAccrued OrderEventType = "order.accrued"
+)
+
+type OrderEvent struct {
+	Type       OrderEventType       `json:"type"`
+	UserID     uint                 `json:"-"`
+	Number     string               `json:"number"`
+	Status     entities.OrderStatus `json:"status"`
+	Accrual    float32              `json:"accrual,omitempty"`
+	OccurredAt JSONTime             `json:"occurred_at"`
+}
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+)
+
+type OrderSearchFilter struct {
+	UserID       uint
+	Statuses     []entities.OrderStatus
+	UploadedFrom *time.Time
+	UploadedTo   *time.Time
+	Sort         SortDirection
+	Limit        int
+	Cursor       *Cursor
+}
+
+type GetOrdersPage struct {
+	Orders     []*GetOrdersResponse
+	NextCursor string
+}
@@ -0,0 +1,19 @@
+package models
+
+// MIMEApplicationProblemJSON Тип содержимого ответа с ошибкой (RFC 7807)
+const MIMEApplicationProblemJSON = "application/problem+json"
+
+// ProblemTypePrefix Префикс URI типа ошибки, к нему добавляется стабильный код ошибки
+const ProblemTypePrefix = "urn:gophermart:problem:"
+
+// Problem Описание ошибки в формате RFC 7807
+type Problem struct {
+	Type      string          `json:"type"`
+	Title     string          `json:"title"`
+	Status    int             `json:"status"`
+	Code      string          `json:"code"`
+	Detail    string          `json:"detail,omitempty"`
+	Instance  string          `json:"instance,omitempty"`
+	RequestID string          `json:"request_id,omitempty"`
+	Errors    ValidationError `json:"errors,omitempty"`
+}
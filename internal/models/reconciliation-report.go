@@ -0,0 +1,68 @@
+package models
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+)
+
+type ReconciliationReport struct {
+	Run           entities.ReconciliationRun           `json:"run"`
+	Discrepancies []entities.ReconciliationDiscrepancy `json:"discrepancies"`
+}
+
+var reconciliationReportCSVHeader = []string{
+	"run_id",
+	"order_number",
+	"type",
+	"local_status",
+	"remote_status",
+	"local_accrual",
+	"remote_accrual",
+	"operation_sum",
+	"corrected",
+	"detected_at",
+}
+
+// WriteCSV Выгрузка расхождений в формате CSV
+func (r *ReconciliationReport) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+
+	if err := writer.Write(reconciliationReportCSVHeader); err != nil {
+		return err
+	}
+
+	for _, d := range r.Discrepancies {
+		operationSum := ""
+		if d.OperationSum != nil {
+			operationSum = formatSum(*d.OperationSum)
+		}
+
+		err := writer.Write([]string{
+			strconv.FormatUint(uint64(r.Run.ID), 10),
+			d.OrderNumber,
+			string(d.Type),
+			string(d.LocalStatus),
+			string(d.RemoteStatus),
+			formatSum(d.LocalAccrual),
+			formatSum(d.RemoteAccrual),
+			operationSum,
+			strconv.FormatBool(d.Corrected),
+			d.CreatedAt.Format(time.RFC3339),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+
+	return writer.Error()
+}
+
+func formatSum(sum float32) string {
+	return strconv.FormatFloat(float64(sum), 'f', 2, 32)
+}
@@ -0,0 +1,69 @@
+package models
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestReconciliationReport_WriteCSV(t *testing.T) {
+	detectedAt, _ := time.Parse("2006-01-02 15:04:05", "2009-11-10 23:00:00")
+	operationSum := float32(100)
+
+	tests := []struct {
+		name   string
+		report ReconciliationReport
+		want   string
+	}{
+		{
+			name: "Empty report",
+			report: ReconciliationReport{
+				Run: entities.ReconciliationRun{Model: gorm.Model{ID: 1}},
+			},
+			want: "run_id,order_number,type,local_status,remote_status,local_accrual,remote_accrual,operation_sum,corrected,detected_at\n",
+		},
+		{
+			name: "Report with discrepancies",
+			report: ReconciliationReport{
+				Run: entities.ReconciliationRun{Model: gorm.Model{ID: 3}},
+				Discrepancies: []entities.ReconciliationDiscrepancy{
+					{
+						Model:         gorm.Model{CreatedAt: detectedAt},
+						OrderNumber:   "12345678903",
+						Type:          entities.ReconciliationDiscrepancyTypeOperationMismatch,
+						LocalStatus:   entities.OrderStatusProcessed,
+						RemoteStatus:  entities.OrderStatusProcessed,
+						LocalAccrual:  123.45,
+						RemoteAccrual: 123.45,
+						OperationSum:  &operationSum,
+					},
+					{
+						Model:         gorm.Model{CreatedAt: detectedAt},
+						OrderNumber:   "24619735244",
+						Type:          entities.ReconciliationDiscrepancyTypeOperationMissing,
+						LocalStatus:   entities.OrderStatusProcessed,
+						RemoteStatus:  entities.OrderStatusProcessed,
+						LocalAccrual:  500,
+						RemoteAccrual: 500,
+						Corrected:     true,
+					},
+				},
+			},
+			want: "run_id,order_number,type,local_status,remote_status,local_accrual,remote_accrual,operation_sum,corrected,detected_at\n" +
+				"3,12345678903,operation_mismatch,PROCESSED,PROCESSED,123.45,123.45,100.00,false,2009-11-10T23:00:00Z\n" +
+				"3,24619735244,operation_missing,PROCESSED,PROCESSED,500.00,500.00,,true,2009-11-10T23:00:00Z\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			err := tt.report.WriteCSV(buf)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, buf.String())
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+)
+
+// RewardRuleRequest Создание и изменение правила вознаграждения.
+// SKUPattern шаблон в синтаксисе path.Match, например "BORK-*". Без Active правило включено
+type RewardRuleRequest struct {
+	Name       string                  `json:"name" validate:"required,max=128"`
+	Mechanic   entities.RewardMechanic `json:"mechanic" validate:"required,oneof=percent points"`
+	Value      float32                 `json:"value" validate:"gt=0"`
+	SKUPattern string                  `json:"sku_pattern" validate:"max=128"`
+	Category   string                  `json:"category" validate:"max=128"`
+	ValidFrom  *time.Time              `json:"valid_from"`
+	ValidTo    *time.Time              `json:"valid_to"`
+	OrderCap   *float32                `json:"order_cap" validate:"omitempty,gt=0"`
+	Priority   int                     `json:"priority"`
+	Active     *bool                   `json:"active"`
+}
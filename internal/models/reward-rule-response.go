@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+)
+
+type RewardRuleResponse struct {
+	ID         uint                    `json:"id"`
+	Name       string                  `json:"name"`
+	Mechanic   entities.RewardMechanic `json:"mechanic"`
+	Value      float32                 `json:"value"`
+	SKUPattern string                  `json:"sku_pattern"`
+	Category   string                  `json:"category"`
+	ValidFrom  *JSONTime               `json:"valid_from"`
+	ValidTo    *JSONTime               `json:"valid_to"`
+	OrderCap   *float32                `json:"order_cap"`
+	Priority   int                     `json:"priority"`
+	Active     bool                    `json:"active"`
+	UpdatedAt  JSONTime                `json:"updated_at"`
+}
+
+func MapRewardRule(rule *entities.RewardRule) RewardRuleResponse {
+	return RewardRuleResponse{
+		ID:         rule.ID,
+		Name:       rule.Name,
+		Mechanic:   rule.Mechanic,
+		Value:      rule.Value,
+		SKUPattern: rule.SKUPattern,
+		Category:   rule.Category,
+		ValidFrom:  jsonTimePtr(rule.ValidFrom),
+		ValidTo:    jsonTimePtr(rule.ValidTo),
+		OrderCap:   rule.OrderCap,
+		Priority:   rule.Priority,
+		Active:     rule.Active,
+		UpdatedAt:  JSONTime(rule.UpdatedAt),
+	}
+}
+
+func jsonTimePtr(t *time.Time) *JSONTime {
+	if t == nil {
+		return nil
+	}
+	res := JSONTime(*t)
+
+	return &res
+}
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestMapRewardRule(t *testing.T) {
+	updatedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	validTo := updatedAt.Add(24 * time.Hour)
+
+	got, err := json.Marshal(MapRewardRule(&entities.RewardRule{
+		Model:      gorm.Model{ID: 3, UpdatedAt: updatedAt},
+		Name:       "Bork",
+		Mechanic:   entities.RewardMechanicPercent,
+		Value:      10,
+		SKUPattern: "BORK-*",
+		ValidTo:    &validTo,
+		Active:     true,
+	}))
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{
+		"id": 3,
+		"name": "Bork",
+		"mechanic": "percent",
+		"value": 10,
+		"sku_pattern": "BORK-*",
+		"category": "",
+		"valid_from": null,
+		"valid_to": "2024-05-02T12:00:00Z",
+		"order_cap": null,
+		"priority": 0,
+		"active": true,
+		"updated_at": "2024-05-01T12:00:00Z"
+	}`, string(got))
+}
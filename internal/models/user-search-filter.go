@@ -1,5 +1,6 @@
 package models
 
 type UserSearchFilter struct {
+	ID    uint   `json:"id" query:"id"`
 	Login string `json:"login" query:"login"`
 }
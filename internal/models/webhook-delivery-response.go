@@ -0,0 +1,36 @@
+package models
+
+import "github.com/ShukinDmitriy/gophermart/internal/entities"
+
+type WebhookDeliveryResponse struct {
+	ID             uint                           `json:"id"`
+	EventID        string                         `json:"event_id"`
+	EventType      entities.WebhookEventType      `json:"event_type"`
+	Status         entities.WebhookDeliveryStatus `json:"status"`
+	Attempts       int                            `json:"attempts"`
+	LastStatusCode int                            `json:"last_status_code,omitempty"`
+	LastError      string                         `json:"last_error,omitempty"`
+	// NextAttemptAt есть только у доставки, которая ещё будет повторена
+	NextAttemptAt *JSONTime `json:"next_attempt_at,omitempty"`
+	DeliveredAt   *JSONTime `json:"delivered_at,omitempty"`
+	CreatedAt     JSONTime  `json:"created_at"`
+}
+
+func MapWebhookDelivery(delivery *entities.WebhookDelivery) WebhookDeliveryResponse {
+	res := WebhookDeliveryResponse{
+		ID:             delivery.ID,
+		EventID:        delivery.EventID,
+		EventType:      delivery.EventType,
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		LastStatusCode: delivery.LastStatusCode,
+		LastError:      delivery.LastError,
+		DeliveredAt:    jsonTimePtr(delivery.DeliveredAt),
+		CreatedAt:      JSONTime(delivery.CreatedAt),
+	}
+	if delivery.Status == entities.WebhookDeliveryPending {
+		res.NextAttemptAt = jsonTimePtr(&delivery.NextAttemptAt)
+	}
+
+	return res
+}
@@ -0,0 +1,80 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestMapWebhookDelivery(t *testing.T) {
+	createdAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	deliveredAt := createdAt.Add(time.Minute)
+
+	tests := []struct {
+		name     string
+		delivery entities.WebhookDelivery
+		want     string
+	}{
+		{
+			name: "pending after a failed attempt",
+			delivery: entities.WebhookDelivery{
+				Model:          gorm.Model{ID: 3, CreatedAt: createdAt},
+				EventID:        "4f2c",
+				EventType:      entities.WebhookEventBalanceAccrued,
+				Status:         entities.WebhookDeliveryPending,
+				Attempts:       1,
+				NextAttemptAt:  createdAt.Add(30 * time.Second),
+				LastStatusCode: 503,
+				LastError:      "unexpected status 503",
+			},
+			want: `{
+				"id": 3,
+				"event_id": "4f2c",
+				"event_type": "balance.accrued",
+				"status": "pending",
+				"attempts": 1,
+				"last_status_code": 503,
+				"last_error": "unexpected status 503",
+				"next_attempt_at": "2024-05-01T12:00:30Z",
+				"created_at": "2024-05-01T12:00:00Z"
+			}`,
+		},
+		{
+			name: "delivered",
+			delivery: entities.WebhookDelivery{
+				Model:          gorm.Model{ID: 4, CreatedAt: createdAt},
+				EventID:        "4f2c",
+				EventType:      entities.WebhookEventBalanceAccrued,
+				Status:         entities.WebhookDeliveryDelivered,
+				Attempts:       2,
+				NextAttemptAt:  createdAt.Add(time.Hour),
+				LastStatusCode: 204,
+				DeliveredAt:    &deliveredAt,
+			},
+			want: `{
+				"id": 4,
+				"event_id": "4f2c",
+				"event_type": "balance.accrued",
+				"status": "delivered",
+				"attempts": 2,
+				"last_status_code": 204,
+				"delivered_at": "2024-05-01T12:01:00Z",
+				"created_at": "2024-05-01T12:00:00Z"
+			}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(MapWebhookDelivery(&tt.delivery))
+			require.NoError(t, err)
+
+			assert.JSONEq(t, tt.want, string(got))
+		})
+	}
+}
@@ -0,0 +1,15 @@
+package models
+
+import "github.com/ShukinDmitriy/gophermart/internal/entities"
+
+// WebhookEvent Тело доставки вебхука. ID общий у доставок одного события на разные вебхуки
+// и не меняется при повторах, по нему получатель отбрасывает дубли
+type WebhookEvent struct {
+	ID         string                    `json:"id"`
+	Type       entities.WebhookEventType `json:"type"`
+	UserID     uint                      `json:"user_id"`
+	Order      string                    `json:"order"`
+	Status     entities.OrderStatus      `json:"status,omitempty"`
+	Sum        float32                   `json:"sum"`
+	OccurredAt JSONTime                  `json:"occurred_at"`
+}
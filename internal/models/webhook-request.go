@@ -0,0 +1,8 @@
+package models
+
+import "github.com/ShukinDmitriy/gophermart/internal/entities"
+
+type WebhookRequest struct {
+	URL    string                      `json:"url" validate:"required,http_url,max=2048"`
+	Events []entities.WebhookEventType `json:"events" validate:"required,min=1,unique,dive,oneof=order.processed order.invalid balance.withdrawn balance.accrued"`
+}
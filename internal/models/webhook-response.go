@@ -0,0 +1,21 @@
+package models
+
+import "github.com/ShukinDmitriy/gophermart/internal/entities"
+
+type WebhookResponse struct {
+	ID     uint                        `json:"id"`
+	URL    string                      `json:"url"`
+	Events []entities.WebhookEventType `json:"events"`
+	// Secret ключ подписи доставок, отдаётся только при создании вебхука
+	Secret    string   `json:"secret,omitempty"`
+	CreatedAt JSONTime `json:"created_at"`
+}
+
+func MapWebhook(webhook *entities.Webhook) WebhookResponse {
+	return WebhookResponse{
+		ID:        webhook.ID,
+		URL:       webhook.URL,
+		Events:    webhook.Events,
+		CreatedAt: JSONTime(webhook.CreatedAt),
+	}
+}
@@ -0,0 +1,109 @@
+// Package netguard Исходящие запросы по адресам, которые задают пользователи (вебхуки, уведомления).
+// Подключение разрешено только к публичным адресам. Адрес проверяется в момент соединения, после
+// разрешения имени, поэтому имя, которое сначала указывало на публичный адрес, а потом на внутренний
+// (DNS rebinding), тоже не проходит
+package netguard
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// ErrForbiddenAddress адрес из внутренней или служебной сети
+var ErrForbiddenAddress = errors.New("destination address is not allowed")
+
+// reserved Служебные сети, которых нет среди проверок netip.Addr
+var reserved = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+	netip.MustParsePrefix("64:ff9b:1::/48"),
+}
+
+// Public Адрес из публичной сети: не loopback, не частная сеть, не link-local (в том числе адрес
+// метаданных облака 169.254.169.254), не multicast и не служебные сети
+func Public(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsValid() ||
+		addr.IsUnspecified() ||
+		addr.IsLoopback() ||
+		addr.IsPrivate() ||
+		addr.IsLinkLocalUnicast() ||
+		addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() ||
+		addr.IsMulticast() {
+		return false
+	}
+
+	for _, prefix := range reserved {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// CheckURL Ранняя проверка адреса при сохранении: хост не localhost и не внутренний IP.
+// Имена здесь не разрешаются, их адреса проверяет Transport при каждом соединении
+func CheckURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return ErrForbiddenAddress
+	}
+	if addr, err := netip.ParseAddr(host); err == nil && !Public(addr) {
+		return ErrForbiddenAddress
+	}
+
+	return nil
+}
+
+// NewTransport Транспорт, который подключается только к публичным адресам. Прокси из окружения
+// не используется: через него соединение ушло бы по адресу, который здесь не проверить
+func NewTransport() *http.Transport {
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   control,
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return transport
+}
+
+// NoRedirect CheckRedirect для http.Client: ответ 3xx возвращается как есть, и запрос не уходит
+// по адресу, который назначил получатель
+func NoRedirect(*http.Request, []*http.Request) error {
+	return http.ErrUseLastResponse
+}
+
+// control Проверка адреса уже после разрешения имени, непосредственно перед connect
+func control(_ string, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+	if !Public(addrPort.Addr()) {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, addrPort.Addr())
+	}
+
+	return nil
+}
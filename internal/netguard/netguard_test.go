@@ -0,0 +1,85 @@
+package netguard
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublic(t *testing.T) {
+	tests := []struct {
+		addr string
+		want bool
+	}{
+		{addr: "8.8.8.8", want: true},
+		{addr: "2a00:1450:4001:82b::200e", want: true},
+		{addr: "127.0.0.1", want: false},
+		{addr: "::1", want: false},
+		{addr: "10.1.2.3", want: false},
+		{addr: "172.16.0.1", want: false},
+		{addr: "192.168.1.1", want: false},
+		{addr: "169.254.169.254", want: false},
+		{addr: "100.64.0.1", want: false},
+		{addr: "0.0.0.0", want: false},
+		{addr: "fd00:ec2::254", want: false},
+		{addr: "fe80::1", want: false},
+		{addr: "::ffff:127.0.0.1", want: false},
+		{addr: "64:ff9b::a00:1", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			assert.Equal(t, tt.want, Public(netip.MustParseAddr(tt.addr)))
+		})
+	}
+}
+
+func TestCheckURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		url     string
+		wantErr error
+	}{
+		{name: "public name", url: "https://example.com/hooks"},
+		{name: "public ip", url: "http://8.8.8.8:8080/hooks"},
+		{name: "localhost", url: "http://localhost:8080/hooks", wantErr: ErrForbiddenAddress},
+		{name: "localhost subdomain", url: "http://api.localhost/hooks", wantErr: ErrForbiddenAddress},
+		{name: "loopback", url: "http://127.0.0.1/hooks", wantErr: ErrForbiddenAddress},
+		{name: "ipv6 loopback", url: "http://[::1]:8080/hooks", wantErr: ErrForbiddenAddress},
+		{name: "metadata", url: "http://169.254.169.254/latest/meta-data", wantErr: ErrForbiddenAddress},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, CheckURL(tt.url), tt.wantErr)
+		})
+	}
+}
+
+func TestTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: NewTransport(), CheckRedirect: NoRedirect}
+	_, err := client.Get(server.URL)
+
+	assert.ErrorIs(t, err, ErrForbiddenAddress)
+}
+
+func TestNoRedirect(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "http://169.254.169.254/latest/meta-data", http.StatusFound)
+	}))
+	defer server.Close()
+
+	client := &http.Client{CheckRedirect: NoRedirect}
+	response, err := client.Get(server.URL)
+
+	require.NoError(t, err)
+	defer response.Body.Close()
+	assert.Equal(t, http.StatusFound, response.StatusCode)
+}
@@ -0,0 +1,70 @@
+package openapi
+
+import (
+	_ "embed"
+	"fmt"
+	"net/http"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/labstack/echo/v4"
+)
+
+//go:embed openapi.yaml
+var specYAML []byte
+
+// swaggerUIPage Страница Swagger UI, сами скрипты и стили загружаются с CDN
+const swaggerUIPage = `<!DOCTYPE html>
+<html lang="ru">
+<head>
+  <meta charset="utf-8">
+  <title>Gophermart API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5.17.14/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5.17.14/swagger-ui-bundle.js" crossorigin></script>
+  <script>
+    window.onload = function () {
+      window.ui = SwaggerUIBundle({url: "%s", dom_id: "#swagger-ui"});
+    };
+  </script>
+</body>
+</html>
+`
+
+// Load Загружает встроенную спецификацию OpenAPI 3 и проверяет её корректность
+func Load() (*openapi3.T, error) {
+	loader := openapi3.NewLoader()
+
+	doc, err := loader.LoadFromData(specYAML)
+	if err != nil {
+		return nil, fmt.Errorf("load openapi spec: %w", err)
+	}
+
+	if err = doc.Validate(loader.Context); err != nil {
+		return nil, fmt.Errorf("validate openapi spec: %w", err)
+	}
+
+	return doc, nil
+}
+
+// SpecHandler Отдаёт спецификацию в JSON
+func SpecHandler(doc *openapi3.T) (echo.HandlerFunc, error) {
+	data, err := doc.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	return func(c echo.Context) error {
+		return c.Blob(http.StatusOK, echo.MIMEApplicationJSONCharsetUTF8, data)
+	}, nil
+}
+
+// SwaggerUIHandler Отдаёт страницу Swagger UI для спецификации по адресу specURL
+func SwaggerUIHandler(specURL string) echo.HandlerFunc {
+	page := fmt.Sprintf(swaggerUIPage, specURL)
+
+	return func(c echo.Context) error {
+		return c.HTML(http.StatusOK, page)
+	}
+}
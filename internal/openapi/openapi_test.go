@@ -0,0 +1,125 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/ShukinDmitriy/gophermart/internal/controllers"
+	"github.com/ShukinDmitriy/gophermart/internal/models"
+	"github.com/ShukinDmitriy/gophermart/internal/openapi"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/labstack/echo/v4"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("OpenAPI", func() {
+	var e *echo.Echo
+	var doc *openapi3.T
+	var rec *httptest.ResponseRecorder
+
+	BeforeEach(func() {
+		var err error
+		doc, err = openapi.Load()
+		Expect(err).NotTo(HaveOccurred())
+
+		e = echo.New()
+		e.HTTPErrorHandler = controllers.HTTPErrorHandler
+		rec = httptest.NewRecorder()
+	})
+
+	It("should serve the spec as json", func() {
+		// Arrange
+		specHandler, err := openapi.SpecHandler(doc)
+		Expect(err).NotTo(HaveOccurred())
+		e.GET("/api/openapi.json", specHandler)
+
+		// Act
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/openapi.json", nil))
+
+		// Assertions
+		Expect(rec.Code).To(Equal(http.StatusOK))
+
+		resJ := &struct {
+			OpenAPI string         `json:"openapi"`
+			Paths   map[string]any `json:"paths"`
+		}{}
+		err = json.Unmarshal(rec.Body.Bytes(), resJ)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resJ.OpenAPI).To(HavePrefix("3."))
+		Expect(resJ.Paths).To(HaveKey("/api/user/orders"))
+	})
+
+	It("should serve the swagger ui page", func() {
+		// Arrange
+		e.GET("/api/docs", openapi.SwaggerUIHandler("/api/openapi.json"))
+
+		// Act
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/docs", nil))
+
+		// Assertions
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Body.String()).To(ContainSubstring(`url: "/api/openapi.json"`))
+	})
+
+	It("should replace a response that drifts from the spec", func() {
+		// Arrange
+		validator, err := openapi.NewValidator(doc, true)
+		Expect(err).NotTo(HaveOccurred())
+		e.Use(validator)
+		e.GET("/api/user/balance", func(c echo.Context) error {
+			return c.JSON(http.StatusOK, map[string]string{"current": "many"})
+		})
+
+		// Act
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/user/balance", nil))
+
+		// Assertions
+		Expect(rec.Code).To(Equal(http.StatusInternalServerError))
+		Expect(rec.Header().Get(echo.HeaderContentType)).To(Equal(models.MIMEApplicationProblemJSON))
+		Expect(rec.Body.String()).To(ContainSubstring("does not match the API specification"))
+	})
+
+	It("should reject an undocumented response status", func() {
+		// Arrange
+		validator, err := openapi.NewValidator(doc, true)
+		Expect(err).NotTo(HaveOccurred())
+		e.Use(validator)
+		e.GET("/api/user/balance", func(c echo.Context) error {
+			return c.NoContent(http.StatusTeapot)
+		})
+
+		// Act
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/user/balance", nil))
+
+		// Assertions
+		Expect(rec.Code).To(Equal(http.StatusInternalServerError))
+	})
+
+	It("should not check responses in request mode", func() {
+		// Arrange
+		validator, err := openapi.NewValidator(doc, false)
+		Expect(err).NotTo(HaveOccurred())
+		e.Use(validator)
+		e.POST("/api/user/balance/withdraw", func(c echo.Context) error {
+			return c.NoContent(http.StatusTeapot)
+		})
+
+		// Act
+		req := httptest.NewRequest(http.MethodPost, "/api/user/balance/withdraw", strings.NewReader(`{"order":"12345678903"}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		e.ServeHTTP(rec, req)
+		missingSum := rec.Code
+
+		rec = httptest.NewRecorder()
+		req = httptest.NewRequest(http.MethodPost, "/api/user/balance/withdraw", strings.NewReader(`{"order":"12345678903","sum":10}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		e.ServeHTTP(rec, req)
+
+		// Assertions
+		Expect(missingSum).To(Equal(http.StatusBadRequest))
+		Expect(rec.Code).To(Equal(http.StatusTeapot))
+	})
+})
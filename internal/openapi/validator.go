@@ -0,0 +1,162 @@
+package openapi
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/ShukinDmitriy/gophermart/internal/apierrors"
+	"github.com/ShukinDmitriy/gophermart/internal/models"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/labstack/echo/v4"
+)
+
+// NewValidator Middleware проверки запросов по спецификации.
+// При validateResponses ответы буферизуются и тоже проверяются: ответ, который расходится
+// со спецификацией, заменяется ошибкой 500. Это нужно для тестов, в продакшене ответы не проверяются.
+func NewValidator(doc *openapi3.T, validateResponses bool) (echo.MiddlewareFunc, error) {
+	router, err := gorillamux.NewRouter(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	options := &openapi3filter.Options{
+		// Аутентификацию проверяет JWT middleware
+		AuthenticationFunc:    openapi3filter.NoopAuthenticationFunc,
+		IncludeResponseStatus: true,
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+
+			route, pathParams, err := router.FindRoute(req)
+			if err != nil {
+				// Маршрута нет в спецификации, ответит роутер Echo
+				return next(c)
+			}
+
+			input := &openapi3filter.RequestValidationInput{
+				Request:    req,
+				PathParams: pathParams,
+				Route:      route,
+				Options:    options,
+			}
+			if err = openapi3filter.ValidateRequest(req.Context(), input); err != nil {
+				return requestValidationError(err)
+			}
+
+			if !validateResponses || isEventStream(route) {
+				return next(c)
+			}
+
+			return validateResponse(c, next, input)
+		}
+	}, nil
+}
+
+func validateResponse(c echo.Context, next echo.HandlerFunc, input *openapi3filter.RequestValidationInput) error {
+	res := c.Response()
+	writer := res.Writer
+	buffer := &responseBuffer{header: writer.Header()}
+	res.Writer = buffer
+
+	if err := next(c); err != nil {
+		c.Error(err)
+	}
+	res.Writer = writer
+
+	if !res.Committed {
+		return nil
+	}
+
+	err := openapi3filter.ValidateResponse(c.Request().Context(), &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: input,
+		Status:                 res.Status,
+		Header:                 writer.Header(),
+		Body:                   io.NopCloser(bytes.NewReader(buffer.body.Bytes())),
+		Options:                input.Options,
+	})
+	if err != nil {
+		res.Committed = false
+		res.Size = 0
+
+		return apierrors.NewAPIError(
+			http.StatusInternalServerError,
+			apierrors.ErrorCodeInternal,
+			fmt.Sprintf("response %d does not match the API specification: %v", res.Status, err),
+		)
+	}
+
+	writer.WriteHeader(res.Status)
+	_, err = writer.Write(buffer.body.Bytes())
+
+	return err
+}
+
+func requestValidationError(err error) error {
+	apiErr := apierrors.NewAPIError(
+		http.StatusBadRequest,
+		apierrors.ErrorCodeValidationFailed,
+		"request does not match the API specification",
+	).WithCause(err)
+
+	var requestErr *openapi3filter.RequestError
+	if !errors.As(err, &requestErr) {
+		return apiErr
+	}
+
+	field := "body"
+	if requestErr.Parameter != nil {
+		field = requestErr.Parameter.Name
+	}
+
+	rule := "invalid"
+	var schemaErr *openapi3.SchemaError
+	if errors.As(err, &schemaErr) {
+		rule = schemaErr.SchemaField
+		if pointer := schemaErr.JSONPointer(); requestErr.Parameter == nil && len(pointer) > 0 {
+			field = strings.Join(pointer, ".")
+		}
+	} else if errors.Is(err, openapi3filter.ErrInvalidRequired) {
+		rule = "required"
+	}
+
+	apiErr.Errors = models.ValidationError{
+		field: map[string]bool{rule: true},
+	}
+
+	return apiErr
+}
+
+func isEventStream(route *routers.Route) bool {
+	for _, response := range route.Operation.Responses.Map() {
+		if response.Value != nil && response.Value.Content.Get("text/event-stream") != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// responseBuffer Копит ответ обработчика до проверки по спецификации
+type responseBuffer struct {
+	header http.Header
+	body   bytes.Buffer
+}
+
+func (b *responseBuffer) Header() http.Header {
+	return b.header
+}
+
+func (b *responseBuffer) Write(data []byte) (int, error) {
+	return b.body.Write(data)
+}
+
+func (b *responseBuffer) WriteHeader(_ int) {}
@@ -3,6 +3,8 @@ package repositories
 import (
 	"context"
 	"errors"
+	"fmt"
+	"math"
 
 	"github.com/ShukinDmitriy/gophermart/internal/entities"
 	"gorm.io/gorm"
@@ -27,6 +29,13 @@ func (r *AccountRepository) Migrate(ctx context.Context) error {
 	return r.db.WithContext(ctx).AutoMigrate(&m)
 }
 
+// WithContext Копия репозитория, запросы которой выполняются в контексте ctx (отмена, трассировка)
+func (r *AccountRepository) WithContext(ctx context.Context) AccountRepositoryInterface {
+	return &AccountRepository{
+		db: r.db.WithContext(ctx),
+	}
+}
+
 func (r *AccountRepository) Create(userID uint, accountType entities.AccountType) (*entities.Account, error) {
 	account := &entities.Account{
 		Type:   accountType,
@@ -79,29 +88,38 @@ func (r *AccountRepository) GetSystemWithdrawnAccountID() (uint, error) {
 	return accountID, nil
 }
 
+// Transaction Перевод суммы между счетами. Остатки меняются выражением в запросе,
+// чтобы параллельные переводы не затирали друг друга
 func (r *AccountRepository) Transaction(senderAccountID uint, recipientAccountID uint, sum float32) error {
-	var senderAccount entities.Account
-
-	err := r.db.Table("accounts").Where("accounts.id = ?", senderAccountID).Find(&senderAccount).Error
+	err := r.db.Table("accounts").Where("accounts.id = ?", senderAccountID).Update("sum", gorm.Expr("accounts.sum - ?", sum)).Error
 	if err != nil {
 		return err
 	}
 
-	var recipientAccount entities.Account
-	err = r.db.Table("accounts").Where("accounts.id = ?", recipientAccountID).Find(&recipientAccount).Error
-	if err != nil {
-		return err
-	}
+	return r.db.Table("accounts").Where("accounts.id = ?", recipientAccountID).Update("sum", gorm.Expr("accounts.sum + ?", sum)).Error
+}
 
-	err = r.db.Table("accounts").Where("accounts.id = ?", senderAccountID).Update("sum", senderAccount.Sum-sum).Error
-	if err != nil {
-		return err
+// Withdraw Перевод, для которого остатка отправителя должно хватать. Проверка в том же UPDATE:
+// параллельное списание ждёт блокировку строки счёта и проверяет уже новый остаток
+func (r *AccountRepository) Withdraw(senderAccountID uint, recipientAccountID uint, sum float32) error {
+	// остаток хранится с точностью до копеек (в SQLite — как REAL из float32), сравниваются округлённые значения
+	result := r.db.Table("accounts").
+		Where("accounts.id = ? AND ROUND(accounts.sum, 2) >= ?", senderAccountID, math.Round(float64(sum)*100)/100).
+		Update("sum", gorm.Expr("accounts.sum - ?", sum))
+	if result.Error != nil {
+		return result.Error
 	}
+	if result.RowsAffected == 0 {
+		var count int64
+		if err := r.db.Table("accounts").Where("accounts.id = ?", senderAccountID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			return fmt.Errorf("account %d not found", senderAccountID)
+		}
 
-	err = r.db.Table("accounts").Where("accounts.id = ?", recipientAccountID).Update("sum", recipientAccount.Sum+sum).Error
-	if err != nil {
-		return err
+		return ErrInsufficientFunds
 	}
 
-	return nil
+	return r.db.Table("accounts").Where("accounts.id = ?", recipientAccountID).Update("sum", gorm.Expr("accounts.sum + ?", sum)).Error
 }
@@ -1,7 +1,12 @@
 package repositories
 
-import "github.com/ShukinDmitriy/gophermart/internal/entities"
+import (
+	"context"
+
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+)
 
 type AccountRepositoryInterface interface {
+	WithContext(ctx context.Context) AccountRepositoryInterface
 	FindByUserID(userID uint, accountType entities.AccountType) (*entities.Account, error)
 }
@@ -0,0 +1,62 @@
+package repositories
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/mattn/go-sqlite3"
+)
+
+// Нарушения ограничений уникальности. Проверка перед вставкой не защищает от параллельных запросов,
+// поэтому окончательный ответ даёт база, а сервисы переводят эти ошибки в ответы API
+var (
+	// ErrLoginAlreadyExists пользователь с таким логином уже есть
+	ErrLoginAlreadyExists = errors.New("login already exists")
+	// ErrOrderAlreadyExists заказ с таким номером уже загружен
+	ErrOrderAlreadyExists = errors.New("order already exists")
+	// ErrBasketAlreadyExists состав заказа уже загружен
+	ErrBasketAlreadyExists = errors.New("order basket already exists")
+	// ErrAccrualAlreadyExists баллы за заказ уже начислены
+	ErrAccrualAlreadyExists = errors.New("order accrual already exists")
+)
+
+// ErrOrderFinished заказ уже в конечном статусе (PROCESSED, INVALID), результат расчёта к нему не применяется
+var ErrOrderFinished = errors.New("order is already finished")
+
+// ErrInsufficientFunds на счёте не хватает баллов для списания. Проверяется вместе с изменением
+// остатка, иначе два параллельных списания проходят проверку по одному и тому же остатку
+var ErrInsufficientFunds = errors.New("insufficient funds on the bonus account")
+
+// uniqueConstraint Ограничение уникальности. Postgres называет в ошибке имя ограничения,
+// SQLite только колонки: "UNIQUE constraint failed: orders.number"
+type uniqueConstraint struct {
+	name    string
+	columns string
+}
+
+var (
+	constraintUsersLogin   = uniqueConstraint{name: "uni_users_login", columns: "users.login"}
+	constraintOrdersNumber = uniqueConstraint{name: "uni_orders_number", columns: "orders.number"}
+	constraintBasketsOrder = uniqueConstraint{name: "uni_order_baskets_order_number", columns: "order_baskets.order_number"}
+	// constraintOperationsAccrual частичный индекс, только для операций начисления
+	constraintOperationsAccrual = uniqueConstraint{name: "uni_operations_accrual_order_number", columns: "operations.order_number"}
+)
+
+// uniqueViolationCode SQLSTATE unique_violation
+const uniqueViolationCode = "23505"
+
+// uniqueViolation Ошибка вызвана нарушением ограничения constraint
+func uniqueViolation(err error, constraint uniqueConstraint) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == uniqueViolationCode && pgErr.ConstraintName == constraint.name
+	}
+
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique && strings.HasSuffix(sqliteErr.Error(), ": "+constraint.columns)
+	}
+
+	return false
+}
@@ -0,0 +1,49 @@
+package repositories
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// HealthRepository Проверки состояния БД для проб готовности
+type HealthRepository struct {
+	db *gorm.DB
+}
+
+func NewHealthRepository(db *gorm.DB) *HealthRepository {
+	return &HealthRepository{
+		db: db,
+	}
+}
+
+// WithContext Копия репозитория, запросы которой выполняются в контексте ctx (отмена, трассировка)
+func (r *HealthRepository) WithContext(ctx context.Context) HealthRepositoryInterface {
+	return &HealthRepository{
+		db: r.db.WithContext(ctx),
+	}
+}
+
+func (r *HealthRepository) Ping() error {
+	sqlDB, err := r.db.DB()
+	if err != nil {
+		return err
+	}
+
+	return sqlDB.PingContext(r.db.Statement.Context)
+}
+
+// MigrationVersion Версия схемы из таблицы golang-migrate. Если миграции не применялись, версия 0
+func (r *HealthRepository) MigrationVersion() (uint, bool, error) {
+	var row struct {
+		Version uint
+		Dirty   bool
+	}
+
+	err := r.db.Raw("SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&row).Error
+	if err != nil {
+		return 0, false, err
+	}
+
+	return row.Version, row.Dirty, nil
+}
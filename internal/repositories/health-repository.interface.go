@@ -0,0 +1,9 @@
+package repositories
+
+import "context"
+
+type HealthRepositoryInterface interface {
+	WithContext(ctx context.Context) HealthRepositoryInterface
+	Ping() error
+	MigrationVersion() (version uint, dirty bool, err error)
+}
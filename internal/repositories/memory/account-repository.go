@@ -0,0 +1,30 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+	"github.com/ShukinDmitriy/gophermart/internal/repositories"
+)
+
+type AccountRepository struct {
+	store *store
+}
+
+// WithContext В памяти запросы не отменяются, контекст не нужен
+func (r *AccountRepository) WithContext(context.Context) repositories.AccountRepositoryInterface {
+	return r
+}
+
+func (r *AccountRepository) FindByUserID(userID uint, accountType entities.AccountType) (*entities.Account, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	account := r.store.accountByUserID(userID, accountType)
+	if account == nil {
+		return nil, nil
+	}
+	found := *account
+
+	return &found, nil
+}
@@ -0,0 +1,23 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/ShukinDmitriy/gophermart/internal/repositories"
+)
+
+// HealthRepository Хранилище в памяти всегда доступно, а схемы и миграций у него нет
+type HealthRepository struct{}
+
+func (r *HealthRepository) WithContext(context.Context) repositories.HealthRepositoryInterface {
+	return r
+}
+
+func (r *HealthRepository) Ping() error {
+	return nil
+}
+
+// MigrationVersion Версия 0: миграции к памяти не применяются
+func (r *HealthRepository) MigrationVersion() (uint, bool, error) {
+	return 0, false, nil
+}
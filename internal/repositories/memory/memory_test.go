@@ -0,0 +1,11 @@
+package memory_test
+
+import (
+	"github.com/ShukinDmitriy/gophermart/internal/repositories/memory"
+	"github.com/ShukinDmitriy/gophermart/internal/repositories/repositoriestest"
+	. "github.com/onsi/ginkgo/v2"
+)
+
+var _ = Describe("Memory storage", func() {
+	repositoriestest.Conformance(memory.NewStorage)
+})
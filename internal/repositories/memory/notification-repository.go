@@ -0,0 +1,149 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+	"github.com/ShukinDmitriy/gophermart/internal/repositories"
+)
+
+type NotificationRepository struct {
+	store *store
+}
+
+func (r *NotificationRepository) WithContext(context.Context) repositories.NotificationRepositoryInterface {
+	return r
+}
+
+func (r *NotificationRepository) CreateNotification(
+	notification *entities.Notification,
+	deliveries []*entities.NotificationDelivery,
+) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	now := currentTime()
+	notification.Model = newModel(nextID(r.store.notifications), now)
+	stored := *notification
+	r.store.notifications = append(r.store.notifications, &stored)
+
+	for _, delivery := range deliveries {
+		delivery.Model = newModel(nextID(r.store.notificationDeliveries), now)
+		delivery.NotificationID = notification.ID
+		storedDelivery := *delivery
+		r.store.notificationDeliveries = append(r.store.notificationDeliveries, &storedDelivery)
+	}
+
+	return nil
+}
+
+func (r *NotificationRepository) FindNotification(id uint) (*entities.Notification, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, stored := range r.store.notifications {
+		if stored.ID == id {
+			found := *stored
+			return &found, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (r *NotificationRepository) GetNotifications(userID uint, unreadOnly bool, limit int) ([]*entities.Notification, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	notifications := make([]*entities.Notification, 0)
+	for i := len(r.store.notifications) - 1; i >= 0 && len(notifications) < limit; i-- {
+		stored := r.store.notifications[i]
+		if stored.UserID != userID || unreadOnly && stored.ReadAt != nil {
+			continue
+		}
+		found := *stored
+		notifications = append(notifications, &found)
+	}
+
+	return notifications, nil
+}
+
+func (r *NotificationRepository) CountUnread(userID uint) (int64, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var count int64
+	for _, stored := range r.store.notifications {
+		if stored.UserID == userID && stored.ReadAt == nil {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+func (r *NotificationRepository) MarkRead(userID uint, ids []uint, readAt time.Time) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var updated int64
+	for _, stored := range r.store.notifications {
+		if stored.UserID != userID || stored.ReadAt != nil || len(ids) > 0 && !slices.Contains(ids, stored.ID) {
+			continue
+		}
+		at := readAt
+		stored.ReadAt = &at
+		stored.UpdatedAt = currentTime()
+		updated++
+	}
+
+	return updated, nil
+}
+
+// ClaimDeliveries Под одной блокировкой забирать отправку параллельно некому
+func (r *NotificationRepository) ClaimDeliveries(now time.Time, lease time.Duration, limit int) ([]*entities.NotificationDelivery, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var due []*entities.NotificationDelivery
+	for _, stored := range r.store.notificationDeliveries {
+		if stored.Status == entities.NotificationDeliveryPending && !stored.NextAttemptAt.After(now) {
+			due = append(due, stored)
+		}
+	}
+	slices.SortStableFunc(due, func(a, b *entities.NotificationDelivery) int {
+		return a.NextAttemptAt.Compare(b.NextAttemptAt)
+	})
+
+	claimed := make([]*entities.NotificationDelivery, 0, min(len(due), limit))
+	for _, stored := range due[:min(len(due), limit)] {
+		stored.Attempts++
+		stored.NextAttemptAt = now.Add(lease)
+		stored.UpdatedAt = currentTime()
+		found := *stored
+		claimed = append(claimed, &found)
+	}
+
+	return claimed, nil
+}
+
+func (r *NotificationRepository) UpdateDelivery(delivery *entities.NotificationDelivery) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, stored := range r.store.notificationDeliveries {
+		if stored.ID != delivery.ID {
+			continue
+		}
+
+		stored.Status = delivery.Status
+		stored.NextAttemptAt = delivery.NextAttemptAt
+		stored.LastError = delivery.LastError
+		stored.DeliveredAt = delivery.DeliveredAt
+		stored.UpdatedAt = currentTime()
+	}
+
+	return nil
+}
@@ -0,0 +1,302 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+	"github.com/ShukinDmitriy/gophermart/internal/models"
+	"github.com/ShukinDmitriy/gophermart/internal/repositories"
+)
+
+type OperationRepository struct {
+	store *store
+}
+
+func (r *OperationRepository) WithContext(context.Context) repositories.OperationRepositoryInterface {
+	return r
+}
+
+func (r *OperationRepository) CreateAccrual(accountID uint, orderNumber string, sum float32) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	return r.accrue(accountID, orderNumber, sum)
+}
+
+// accrue Начисление за заказ, не больше одного на номер (в Postgres это частичный уникальный индекс).
+// Вызывается под Lock
+func (r *OperationRepository) accrue(accountID uint, orderNumber string, sum float32) error {
+	if r.accrualByOrderNumber(orderNumber) != nil {
+		return repositories.ErrAccrualAlreadyExists
+	}
+
+	systemAccount := r.systemWithdrawnAccount()
+	if systemAccount == nil {
+		return errors.New("cannot create accrual")
+	}
+
+	err := r.transfer(entities.OperationTypeAccrual, orderNumber, systemAccount.ID, accountID, sum)
+	if err != nil {
+		return err
+	}
+
+	return r.addPointsEvent(entities.DomainEventPointsAccrued, accountID, orderNumber, sum)
+}
+
+func (r *OperationRepository) CreateWithdrawn(accountID uint, orderNumber string, sum float32) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	systemAccount := r.systemWithdrawnAccount()
+	if systemAccount == nil {
+		return errors.New("cannot create withdrawn")
+	}
+
+	err := r.transfer(entities.OperationTypeWithdraw, orderNumber, accountID, systemAccount.ID, sum)
+	if err != nil {
+		return err
+	}
+
+	return r.addPointsEvent(entities.DomainEventPointsWithdrawn, accountID, orderNumber, sum)
+}
+
+// addPointsEvent Вызывается под Lock после transfer, счёт уже проверен
+func (r *OperationRepository) addPointsEvent(eventType entities.DomainEventType, accountID uint, orderNumber string, sum float32) error {
+	account := r.store.account(accountID)
+
+	return r.store.addOutboxEvent(entities.AggregateAccount, strconv.FormatUint(uint64(accountID), 10), eventType, models.PointsEvent{
+		AccountID: accountID,
+		UserID:    account.UserID,
+		Order:     orderNumber,
+		Sum:       sum,
+	}, account.UpdatedAt)
+}
+
+// transfer Операция и изменение обоих остатков. Вызывается под Lock
+func (r *OperationRepository) transfer(
+	operationType entities.OperationType,
+	orderNumber string,
+	senderAccountID uint,
+	recipientAccountID uint,
+	sum float32,
+) error {
+	sender, recipient := r.store.account(senderAccountID), r.store.account(recipientAccountID)
+	// в Postgres это внешние ключи operations.*_account_id
+	if sender == nil {
+		return fmt.Errorf("account %d not found", senderAccountID)
+	}
+	if recipient == nil {
+		return fmt.Errorf("account %d not found", recipientAccountID)
+	}
+	// системный счёт уходит в минус при начислениях, остаток проверяется только у списаний
+	if operationType == entities.OperationTypeWithdraw && sender.Sum < sum {
+		return repositories.ErrInsufficientFunds
+	}
+
+	now := currentTime()
+	r.store.operations = append(r.store.operations, &entities.Operation{
+		Model:              newModel(nextID(r.store.operations), now),
+		ProcessedAt:        now,
+		Type:               operationType,
+		OrderNumber:        orderNumber,
+		Sum:                sum,
+		SenderAccountID:    senderAccountID,
+		RecipientAccountID: recipientAccountID,
+	})
+	sender.Sum -= sum
+	recipient.Sum += sum
+	sender.UpdatedAt, recipient.UpdatedAt = now, now
+
+	return nil
+}
+
+func (r *OperationRepository) systemWithdrawnAccount() *entities.Account {
+	for _, account := range r.store.accounts {
+		if account.Type == entities.AccountTypeSystemWithdraw {
+			return account
+		}
+	}
+
+	return nil
+}
+
+func (r *OperationRepository) GetWithdrawnByAccountID(accountID uint) (float32, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var withdrawn float32
+	for _, operation := range r.withdrawals(accountID) {
+		withdrawn += operation.Sum
+	}
+
+	return withdrawn, nil
+}
+
+func (r *OperationRepository) GetWithdrawalsByAccountID(accountID uint) ([]models.GetWithdrawalsResponse, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	withdrawals, _ := paginate(keyedOperations(r.withdrawals(accountID)), models.SortDirectionDesc, nil, len(r.store.operations))
+
+	res := make([]models.GetWithdrawalsResponse, 0, len(withdrawals))
+	for _, operation := range withdrawals {
+		res = append(res, mapWithdrawal(operation))
+	}
+
+	return res, nil
+}
+
+func (r *OperationRepository) GetWithdrawalsPage(filter models.OperationSearchFilter) (*models.GetWithdrawalsPage, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var rows []keyed[*entities.Operation]
+	for _, row := range keyedOperations(r.withdrawals(filter.AccountID)) {
+		if matchOperation(row.item.Type, row.item.OrderNumber, row.item.ProcessedAt, filter) {
+			rows = append(rows, row)
+		}
+	}
+
+	withdrawals, nextCursor := paginate(rows, filter.Sort, filter.Cursor, filter.Limit)
+
+	page := &models.GetWithdrawalsPage{
+		Withdrawals: make([]models.GetWithdrawalsResponse, 0, len(withdrawals)),
+		NextCursor:  nextCursor,
+	}
+	for _, operation := range withdrawals {
+		page.Withdrawals = append(page.Withdrawals, mapWithdrawal(operation))
+	}
+
+	return page, nil
+}
+
+// GetOperationsPage Остаток считается по всем операциям счёта до применения фильтров, как и в Postgres
+func (r *OperationRepository) GetOperationsPage(filter models.OperationSearchFilter) (*models.GetOperationsPage, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var ledger []keyed[*entities.Operation]
+	for _, operation := range r.store.operations {
+		if operation.SenderAccountID == filter.AccountID || operation.RecipientAccountID == filter.AccountID {
+			ledger = append(ledger, keyed[*entities.Operation]{time: operation.ProcessedAt, id: operation.ID, item: operation})
+		}
+	}
+	sortKeyed(ledger, false)
+
+	var balance float32
+	var rows []keyed[models.GetOperationsResponse]
+	for _, row := range ledger {
+		sum := row.item.Sum
+		if row.item.RecipientAccountID != filter.AccountID {
+			sum = -sum
+		}
+		balance += sum
+
+		if !matchOperation(row.item.Type, row.item.OrderNumber, row.item.ProcessedAt, filter) {
+			continue
+		}
+		rows = append(rows, keyed[models.GetOperationsResponse]{time: row.time, id: row.id, item: models.GetOperationsResponse{
+			Type:        row.item.Type,
+			Order:       row.item.OrderNumber,
+			Sum:         sum,
+			Balance:     balance,
+			ProcessedAt: models.JSONTime(row.item.ProcessedAt),
+		}})
+	}
+
+	operations, nextCursor := paginate(rows, filter.Sort, filter.Cursor, filter.Limit)
+
+	return &models.GetOperationsPage{
+		Operations: operations,
+		NextCursor: nextCursor,
+	}, nil
+}
+
+func (r *OperationRepository) FindAccrualByOrderNumber(orderNumber string) (*entities.Operation, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	operation := r.accrualByOrderNumber(orderNumber)
+	if operation == nil {
+		return nil, nil
+	}
+	found := *operation
+
+	return &found, nil
+}
+
+// accrualByOrderNumber Вызывается под Lock или RLock
+func (r *OperationRepository) accrualByOrderNumber(orderNumber string) *entities.Operation {
+	for _, operation := range r.store.operations {
+		if operation.Type == entities.OperationTypeAccrual && operation.OrderNumber == orderNumber {
+			return operation
+		}
+	}
+
+	return nil
+}
+
+func (r *OperationRepository) GetOperationsByOrderNumber(orderNumber string) ([]*entities.Operation, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var rows []keyed[*entities.Operation]
+	for _, operation := range r.store.operations {
+		if operation.OrderNumber == orderNumber {
+			found := *operation
+			rows = append(rows, keyed[*entities.Operation]{time: found.ProcessedAt, id: found.ID, item: &found})
+		}
+	}
+
+	operations, _ := paginate(rows, models.SortDirectionAsc, nil, len(rows))
+
+	return operations, nil
+}
+
+// withdrawals Списания со счёта. Вызывается под RLock
+func (r *OperationRepository) withdrawals(accountID uint) []*entities.Operation {
+	var withdrawals []*entities.Operation
+	for _, operation := range r.store.operations {
+		if operation.SenderAccountID == accountID && operation.Type == entities.OperationTypeWithdraw {
+			withdrawals = append(withdrawals, operation)
+		}
+	}
+
+	return withdrawals
+}
+
+func keyedOperations(operations []*entities.Operation) []keyed[*entities.Operation] {
+	rows := make([]keyed[*entities.Operation], 0, len(operations))
+	for _, operation := range operations {
+		rows = append(rows, keyed[*entities.Operation]{time: operation.ProcessedAt, id: operation.ID, item: operation})
+	}
+
+	return rows
+}
+
+// matchOperation Фильтры выборки, кроме курсора и сортировки
+func matchOperation(operationType entities.OperationType, orderNumber string, processedAt time.Time, filter models.OperationSearchFilter) bool {
+	if len(filter.Types) > 0 && !slices.Contains(filter.Types, operationType) {
+		return false
+	}
+	if filter.Order != "" && orderNumber != filter.Order {
+		return false
+	}
+
+	return inPeriod(processedAt, filter.ProcessedFrom, filter.ProcessedTo)
+}
+
+func mapWithdrawal(operation *entities.Operation) models.GetWithdrawalsResponse {
+	processedAt := models.JSONTime(operation.ProcessedAt)
+
+	return models.GetWithdrawalsResponse{
+		Order:       operation.OrderNumber,
+		Sum:         operation.Sum,
+		ProcessedAt: &processedAt,
+	}
+}
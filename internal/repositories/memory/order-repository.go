@@ -0,0 +1,303 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+	"github.com/ShukinDmitriy/gophermart/internal/models"
+	"github.com/ShukinDmitriy/gophermart/internal/repositories"
+)
+
+// ordersForProcessLimit Размер выборки GetOrdersForProcess, как в Postgres
+const ordersForProcessLimit = 100
+
+type OrderRepository struct {
+	store *store
+}
+
+func (r *OrderRepository) WithContext(context.Context) repositories.OrderRepositoryInterface {
+	return r
+}
+
+// Create Заказ с первой записью истории. Занятый номер даёт repositories.ErrOrderAlreadyExists
+func (r *OrderRepository) Create(number string, userID uint) (*entities.Order, error) {
+	orders, err := r.CreateBatch([]string{number}, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return orders[0], nil
+}
+
+// CreateBatch Все заказы пакета или ни одного, если хотя бы один номер занят
+func (r *OrderRepository) CreateBatch(numbers []string, userID uint) ([]*entities.Order, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for i, number := range numbers {
+		if r.store.orderByNumber(number) != nil || slices.Contains(numbers[:i], number) {
+			return nil, repositories.ErrOrderAlreadyExists
+		}
+	}
+
+	now := currentTime()
+	orders := make([]*entities.Order, 0, len(numbers))
+	for _, number := range numbers {
+		order := &entities.Order{
+			Model:  newModel(nextID(r.store.orders), now),
+			Number: number,
+			UserID: userID,
+			Status: entities.OrderStatusNew,
+		}
+		r.store.orders = append(r.store.orders, order)
+		r.addHistory(order, now)
+		err := r.store.addOutboxEvent(entities.AggregateOrder, order.Number, entities.DomainEventOrderUploaded,
+			models.OrderUploadedEvent{Order: order.Number, UserID: order.UserID}, now)
+		if err != nil {
+			return nil, err
+		}
+
+		created := *order
+		orders = append(orders, &created)
+	}
+
+	return orders, nil
+}
+
+// UpdateOrderByAccrualOrder Обновление заказа по ответу системы расчёта.
+// Каждое изменение статуса или начисления записывается в историю заказа.
+// Завершённый заказ не меняется, при переходе в PROCESSED баллы начисляются под той же блокировкой
+func (r *OrderRepository) UpdateOrderByAccrualOrder(accrualOrder *models.AccrualOrderResponse) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	order := r.store.orderByNumber(accrualOrder.Order)
+	if order == nil {
+		return nil
+	}
+	if order.Status.Final() {
+		return repositories.ErrOrderFinished
+	}
+
+	if accrualOrder.Status == entities.OrderStatusProcessed {
+		// начисление первым: при ошибке заказ остаётся прежним, как после отката транзакции
+		bonusAccount := r.store.accountByUserID(order.UserID, entities.AccountTypeBonus)
+		if bonusAccount == nil {
+			return fmt.Errorf("bonus account of user %d not found", order.UserID)
+		}
+		err := (&OperationRepository{store: r.store}).accrue(bonusAccount.ID, order.Number, accrualOrder.Accrual)
+		if err != nil {
+			return err
+		}
+	}
+
+	return r.update(order, accrualOrder.Status, accrualOrder.Accrual)
+}
+
+func (r *OrderRepository) CorrectOrderAccrual(orderNumber string, accrual float32) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	order := r.store.orderByNumber(orderNumber)
+	if order == nil {
+		return nil
+	}
+
+	return r.update(order, order.Status, accrual)
+}
+
+// update Вызывается под Lock
+func (r *OrderRepository) update(order *entities.Order, status entities.OrderStatus, accrual float32) error {
+	now := currentTime()
+	changed := order.Status != status || order.Accrual != accrual
+	previousStatus := order.Status
+	order.Status = status
+	order.Accrual = accrual
+	order.UpdatedAt = now
+
+	if !changed {
+		return nil
+	}
+
+	r.addHistory(order, now)
+
+	return r.store.addOutboxEvent(entities.AggregateOrder, order.Number, entities.DomainEventOrderStatusChanged, models.OrderStatusChangedEvent{
+		Order:          order.Number,
+		UserID:         order.UserID,
+		Status:         order.Status,
+		PreviousStatus: previousStatus,
+		Accrual:        order.Accrual,
+	}, now)
+}
+
+// addHistory Вызывается под Lock
+func (r *OrderRepository) addHistory(order *entities.Order, now time.Time) {
+	r.store.orderHistories = append(r.store.orderHistories, &entities.OrderStatusHistory{
+		Model:       newModel(nextID(r.store.orderHistories), now),
+		OrderID:     order.ID,
+		OrderNumber: order.Number,
+		Status:      order.Status,
+		Accrual:     order.Accrual,
+	})
+}
+
+func (r *OrderRepository) GetOrdersForProcess() ([]*entities.Order, error) {
+	return r.findOrders(func(order *entities.Order) (time.Time, bool) {
+		return order.CreatedAt, order.Status == entities.OrderStatusNew || order.Status == entities.OrderStatusProcessing
+	}, ordersForProcessLimit), nil
+}
+
+func (r *OrderRepository) GetOrdersWithBasketForProcess() ([]*entities.Order, error) {
+	return r.findOrders(func(order *entities.Order) (time.Time, bool) {
+		return order.CreatedAt, order.Status == entities.OrderStatusNew ||
+			order.Status == entities.OrderStatusProcessing && r.store.basketByNumber(order.Number) != nil
+	}, ordersForProcessLimit), nil
+}
+
+func (r *OrderRepository) GetProcessedOrdersByPeriod(from time.Time, to time.Time) ([]*entities.Order, error) {
+	return r.findOrders(func(order *entities.Order) (time.Time, bool) {
+		return order.UpdatedAt, (order.Status == entities.OrderStatusProcessed || order.Status == entities.OrderStatusInvalid) &&
+			inPeriod(order.UpdatedAt, &from, &to)
+	}, 0), nil
+}
+
+func (r *OrderRepository) FindByNumber(number string) (*entities.Order, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	order := r.store.orderByNumber(number)
+	if order == nil {
+		return nil, nil
+	}
+	found := *order
+
+	return &found, nil
+}
+
+func (r *OrderRepository) FindByNumbers(numbers []string) ([]*entities.Order, error) {
+	return r.findOrders(func(order *entities.Order) (time.Time, bool) {
+		return order.CreatedAt, slices.Contains(numbers, order.Number)
+	}, 0), nil
+}
+
+func (r *OrderRepository) GetOrdersPage(filter models.OrderSearchFilter) (*models.GetOrdersPage, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var rows []keyed[*entities.Order]
+	for _, order := range r.store.orders {
+		if order.UserID != filter.UserID ||
+			len(filter.Statuses) > 0 && !slices.Contains(filter.Statuses, order.Status) ||
+			!inPeriod(order.CreatedAt, filter.UploadedFrom, filter.UploadedTo) {
+			continue
+		}
+		rows = append(rows, keyed[*entities.Order]{time: order.CreatedAt, id: order.ID, item: order})
+	}
+
+	orders, nextCursor := paginate(rows, filter.Sort, filter.Cursor, filter.Limit)
+
+	page := &models.GetOrdersPage{
+		Orders:     make([]*models.GetOrdersResponse, 0, len(orders)),
+		NextCursor: nextCursor,
+	}
+	for _, order := range orders {
+		page.Orders = append(page.Orders, mapOrder(order))
+	}
+
+	return page, nil
+}
+
+func (r *OrderRepository) CreateAccrualAttempt(attempt *entities.AccrualAttempt) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for i := len(r.store.attempts) - 1; i >= 0; i-- {
+		if last := r.store.attempts[i]; last.OrderNumber == attempt.OrderNumber {
+			if last.SameResult(attempt) {
+				return nil
+			}
+			break
+		}
+	}
+
+	attempt.Model = newModel(nextID(r.store.attempts), currentTime())
+	stored := *attempt
+	r.store.attempts = append(r.store.attempts, &stored)
+
+	return nil
+}
+
+func (r *OrderRepository) GetStatusHistory(orderID uint) ([]*entities.OrderStatusHistory, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	history := make([]*entities.OrderStatusHistory, 0)
+	for _, item := range r.store.orderHistories {
+		if item.OrderID == orderID {
+			found := *item
+			history = append(history, &found)
+		}
+	}
+
+	return history, nil
+}
+
+func (r *OrderRepository) GetAccrualAttempts(orderNumber string, limit int) ([]*entities.AccrualAttempt, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	attempts := make([]*entities.AccrualAttempt, 0)
+	for _, attempt := range r.store.attempts {
+		if attempt.OrderNumber == orderNumber {
+			found := *attempt
+			attempts = append(attempts, &found)
+		}
+	}
+
+	return attempts[max(len(attempts)-limit, 0):], nil
+}
+
+func (r *OrderRepository) CountByStatus() (map[entities.OrderStatus]int64, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	counts := make(map[entities.OrderStatus]int64)
+	for _, order := range r.store.orders {
+		counts[order.Status]++
+	}
+
+	return counts, nil
+}
+
+// findOrders Копии подходящих заказов по возрастанию ключа сортировки, не больше limit, 0 без ограничения
+func (r *OrderRepository) findOrders(match func(order *entities.Order) (time.Time, bool), limit int) []*entities.Order {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var rows []keyed[*entities.Order]
+	for _, order := range r.store.orders {
+		if key, ok := match(order); ok {
+			found := *order
+			rows = append(rows, keyed[*entities.Order]{time: key, id: found.ID, item: &found})
+		}
+	}
+
+	if limit == 0 {
+		limit = len(rows)
+	}
+	orders, _ := paginate(rows, models.SortDirectionAsc, nil, limit)
+
+	return orders
+}
+
+func mapOrder(order *entities.Order) *models.GetOrdersResponse {
+	return &models.GetOrdersResponse{
+		Number:     order.Number,
+		Status:     order.Status,
+		Accrual:    order.Accrual,
+		UploadedAt: models.JSONTime(order.CreatedAt),
+	}
+}
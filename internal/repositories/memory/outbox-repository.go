@@ -0,0 +1,126 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+	"github.com/ShukinDmitriy/gophermart/internal/repositories"
+)
+
+type OutboxRepository struct {
+	store *store
+}
+
+func (r *OutboxRepository) WithContext(context.Context) repositories.OutboxRepositoryInterface {
+	return r
+}
+
+// ClaimEvents Первые неопубликованные события агрегатов, события хранятся в порядке id
+func (r *OutboxRepository) ClaimEvents(now time.Time, lease time.Duration, limit int) ([]*entities.OutboxEvent, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	blocked := make(map[string]bool)
+	claimed := make([]*entities.OutboxEvent, 0, limit)
+	for _, stored := range r.store.outboxEvents {
+		if len(claimed) == limit {
+			break
+		}
+		if stored.PublishedAt != nil {
+			continue
+		}
+		key := string(stored.AggregateType) + ":" + stored.AggregateID
+		if blocked[key] {
+			continue
+		}
+		blocked[key] = true
+		if stored.NextAttemptAt.After(now) {
+			continue
+		}
+
+		stored.Attempts++
+		stored.NextAttemptAt = now.Add(lease)
+		found := *stored
+		claimed = append(claimed, &found)
+	}
+
+	return claimed, nil
+}
+
+func (r *OutboxRepository) UpdateEvent(event *entities.OutboxEvent) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, stored := range r.store.outboxEvents {
+		if stored.ID != event.ID {
+			continue
+		}
+
+		stored.NextAttemptAt = event.NextAttemptAt
+		stored.LastError = event.LastError
+		stored.PublishedAt = event.PublishedAt
+	}
+
+	return nil
+}
+
+func (r *OutboxRepository) CountPending() (int64, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var count int64
+	for _, stored := range r.store.outboxEvents {
+		if stored.PublishedAt == nil {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+func (r *OutboxRepository) DeletePublished(before time.Time) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	kept := r.store.outboxEvents[:0]
+	for _, stored := range r.store.outboxEvents {
+		if stored.PublishedAt == nil || !stored.PublishedAt.Before(before) {
+			kept = append(kept, stored)
+		}
+	}
+	deleted := int64(len(r.store.outboxEvents) - len(kept))
+	clear(r.store.outboxEvents[len(kept):])
+	r.store.outboxEvents = kept
+
+	return deleted, nil
+}
+
+// addOutboxEvent Событие пишется под тем же Lock, что и изменение состояния. Вызывается под Lock
+func (s *store) addOutboxEvent(
+	aggregateType entities.AggregateType,
+	aggregateID string,
+	eventType entities.DomainEventType,
+	payload any,
+	now time.Time,
+) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	// события удаляются после публикации, поэтому id считается отдельно, а не по длине таблицы
+	s.outboxSeq++
+	s.outboxEvents = append(s.outboxEvents, &entities.OutboxEvent{
+		ID:            s.outboxSeq,
+		CreatedAt:     now,
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		EventType:     eventType,
+		Payload:       string(data),
+		NextAttemptAt: now,
+	})
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+	"github.com/ShukinDmitriy/gophermart/internal/repositories"
+)
+
+type ReconciliationRepository struct {
+	store *store
+}
+
+// WithContext В памяти запросы не отменяются, контекст не нужен
+func (r *ReconciliationRepository) WithContext(context.Context) repositories.ReconciliationRepositoryInterface {
+	return r
+}
+
+func (r *ReconciliationRepository) CreateRun(run *entities.ReconciliationRun) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	run.Model = newModel(nextID(r.store.runs), currentTime())
+	stored := *run
+	r.store.runs = append(r.store.runs, &stored)
+
+	return nil
+}
+
+func (r *ReconciliationRepository) UpdateRun(run *entities.ReconciliationRun) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, stored := range r.store.runs {
+		if stored.ID == run.ID {
+			stored.Status = run.Status
+			stored.OrdersChecked = run.OrdersChecked
+			stored.Discrepancies = run.Discrepancies
+			stored.Corrected = run.Corrected
+			stored.FinishedAt = run.FinishedAt
+			stored.UpdatedAt = currentTime()
+			return nil
+		}
+	}
+
+	return nil
+}
+
+func (r *ReconciliationRepository) CreateDiscrepancy(discrepancy *entities.ReconciliationDiscrepancy) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	// в Postgres это внешний ключ reconciliation_discrepancies.run_id
+	if discrepancy.RunID == 0 || discrepancy.RunID > uint(len(r.store.runs)) {
+		return fmt.Errorf("reconciliation run %d not found", discrepancy.RunID)
+	}
+
+	discrepancy.Model = newModel(nextID(r.store.discrepancies), currentTime())
+	stored := *discrepancy
+	r.store.discrepancies = append(r.store.discrepancies, &stored)
+
+	return nil
+}
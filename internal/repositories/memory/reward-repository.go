@@ -0,0 +1,155 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+	"github.com/ShukinDmitriy/gophermart/internal/repositories"
+	"gorm.io/gorm"
+)
+
+type RewardRepository struct {
+	store *store
+}
+
+func (r *RewardRepository) WithContext(context.Context) repositories.RewardRepositoryInterface {
+	return r
+}
+
+func (r *RewardRepository) CreateRule(rule *entities.RewardRule) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	rule.Model = newModel(nextID(r.store.rewardRules), currentTime())
+	stored := *rule
+	r.store.rewardRules = append(r.store.rewardRules, &stored)
+
+	return nil
+}
+
+func (r *RewardRepository) UpdateRule(rule *entities.RewardRule) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	stored := r.store.rewardRule(rule.ID)
+	if stored == nil {
+		return nil
+	}
+
+	updated := *rule
+	updated.Model = stored.Model
+	updated.UpdatedAt = currentTime()
+	*stored = updated
+
+	return nil
+}
+
+// DeleteRule Мягкое удаление, как у GORM: идентификаторы удалённых правил не переиспользуются
+func (r *RewardRepository) DeleteRule(id uint) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if stored := r.store.rewardRule(id); stored != nil {
+		stored.DeletedAt = gorm.DeletedAt{Time: currentTime(), Valid: true}
+	}
+
+	return nil
+}
+
+func (r *RewardRepository) FindRule(id uint) (*entities.RewardRule, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	stored := r.store.rewardRule(id)
+	if stored == nil {
+		return nil, nil
+	}
+	found := *stored
+
+	return &found, nil
+}
+
+func (r *RewardRepository) GetRules() ([]*entities.RewardRule, error) {
+	return r.findRules(func(*entities.RewardRule) bool { return true }), nil
+}
+
+func (r *RewardRepository) GetActiveRules(at time.Time) ([]*entities.RewardRule, error) {
+	return r.findRules(func(rule *entities.RewardRule) bool {
+		return rule.Active &&
+			(rule.ValidFrom == nil || !at.Before(*rule.ValidFrom)) &&
+			(rule.ValidTo == nil || at.Before(*rule.ValidTo))
+	}), nil
+}
+
+// CreateBasket Занятый номер заказа даёт repositories.ErrBasketAlreadyExists
+func (r *RewardRepository) CreateBasket(basket *entities.OrderBasket) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, stored := range r.store.baskets {
+		if stored.OrderNumber == basket.OrderNumber {
+			return repositories.ErrBasketAlreadyExists
+		}
+	}
+
+	now := currentTime()
+	basket.Model = newModel(nextID(r.store.baskets), now)
+	for _, item := range basket.Items {
+		item.Model = newModel(nextID(r.store.basketItems), now)
+		item.BasketID = basket.ID
+		storedItem := *item
+		r.store.basketItems = append(r.store.basketItems, &storedItem)
+	}
+	stored := *basket
+	stored.Items = nil
+	r.store.baskets = append(r.store.baskets, &stored)
+
+	return nil
+}
+
+func (r *RewardRepository) FindBasket(orderNumber string) (*entities.OrderBasket, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	stored := r.store.basketByNumber(orderNumber)
+	if stored == nil {
+		return nil, nil
+	}
+
+	found := *stored
+	for _, item := range r.store.basketItems {
+		if item.BasketID == found.ID {
+			foundItem := *item
+			found.Items = append(found.Items, &foundItem)
+		}
+	}
+
+	return &found, nil
+}
+
+// findRules Копии неудалённых правил в порядке применения, как в GORM реализации
+func (r *RewardRepository) findRules(match func(rule *entities.RewardRule) bool) []*entities.RewardRule {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	rules := make([]*entities.RewardRule, 0, len(r.store.rewardRules))
+	for _, stored := range r.store.rewardRules {
+		if stored.DeletedAt.Valid || !match(stored) {
+			continue
+		}
+		found := *stored
+		rules = append(rules, &found)
+	}
+	slices.SortStableFunc(rules, func(a, b *entities.RewardRule) int {
+		if a.Priority != b.Priority {
+			return cmp.Compare(b.Priority, a.Priority)
+		}
+
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	return rules
+}
@@ -0,0 +1,204 @@
+// Package memory Репозитории в памяти процесса: запуск без базы для демонстрации и разработки фронтенда.
+// Данные теряются при остановке
+package memory
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+	"github.com/ShukinDmitriy/gophermart/internal/models"
+	"github.com/ShukinDmitriy/gophermart/internal/repositories"
+	"gorm.io/gorm"
+)
+
+// store Данные всех репозиториев под одной блокировкой. То, что в Postgres выполняется транзакцией
+// (пользователь со счетами, операция с изменением остатков, пакет заказов), здесь делается под одним Lock
+type store struct {
+	mu sync.RWMutex
+
+	users          []*entities.User
+	accounts       []*entities.Account
+	operations     []*entities.Operation
+	orders         []*entities.Order
+	orderHistories []*entities.OrderStatusHistory
+	attempts       []*entities.AccrualAttempt
+	runs           []*entities.ReconciliationRun
+	discrepancies  []*entities.ReconciliationDiscrepancy
+	rewardRules    []*entities.RewardRule
+	baskets        []*entities.OrderBasket
+	basketItems    []*entities.OrderBasketItem
+
+	webhooks          []*entities.Webhook
+	webhookDeliveries []*entities.WebhookDelivery
+
+	outboxEvents []*entities.OutboxEvent
+	outboxSeq    uint
+
+	notifications          []*entities.Notification
+	notificationDeliveries []*entities.NotificationDelivery
+}
+
+// NewStorage Пустое хранилище со служебным счётом списаний, как после миграций
+func NewStorage() *repositories.Storage {
+	s := &store{}
+	now := currentTime()
+	s.accounts = append(s.accounts, &entities.Account{
+		Model: newModel(1, now),
+		Type:  entities.AccountTypeSystemWithdraw,
+	})
+
+	return &repositories.Storage{
+		Users:           &UserRepository{store: s},
+		Accounts:        &AccountRepository{store: s},
+		Operations:      &OperationRepository{store: s},
+		Orders:          &OrderRepository{store: s},
+		Reconciliations: &ReconciliationRepository{store: s},
+		Rewards:         &RewardRepository{store: s},
+		Webhooks:        &WebhookRepository{store: s},
+		Outbox:          &OutboxRepository{store: s},
+		Notifications:   &NotificationRepository{store: s},
+		Health:          &HealthRepository{},
+	}
+}
+
+// currentTime Время с точностью Postgres и без показаний монотонных часов, чтобы курсоры сравнивались так же
+func currentTime() time.Time {
+	return time.Now().Truncate(time.Microsecond)
+}
+
+func newModel(id uint, now time.Time) gorm.Model {
+	return gorm.Model{ID: id, CreatedAt: now, UpdatedAt: now}
+}
+
+// nextID Следующий идентификатор: записи не удаляются, поэтому это длина таблицы плюс один
+func nextID[T any](rows []T) uint {
+	return uint(len(rows)) + 1
+}
+
+func (s *store) account(id uint) *entities.Account {
+	for _, account := range s.accounts {
+		if account.ID == id {
+			return account
+		}
+	}
+
+	return nil
+}
+
+func (s *store) accountByUserID(userID uint, accountType entities.AccountType) *entities.Account {
+	for _, account := range s.accounts {
+		if account.UserID == userID && account.Type == accountType {
+			return account
+		}
+	}
+
+	return nil
+}
+
+func (s *store) orderByNumber(number string) *entities.Order {
+	for _, order := range s.orders {
+		if order.Number == number {
+			return order
+		}
+	}
+
+	return nil
+}
+
+func (s *store) basketByNumber(orderNumber string) *entities.OrderBasket {
+	for _, basket := range s.baskets {
+		if basket.OrderNumber == orderNumber {
+			return basket
+		}
+	}
+
+	return nil
+}
+
+// keyed Запись с ключом сортировки (время, id), по которому строятся курсоры
+type keyed[T any] struct {
+	time time.Time
+	id   uint
+	item T
+}
+
+// paginate Сортировка, курсор и лимит так же, как в запросах Postgres: limit+1 строк,
+// лишняя строка означает, что есть следующая страница
+func paginate[T any](rows []keyed[T], direction models.SortDirection, cursor *models.Cursor, limit int) ([]T, string) {
+	desc := direction == models.SortDirectionDesc
+	sortKeyed(rows, desc)
+
+	items := make([]T, 0, len(rows))
+	var last keyed[T]
+	var nextCursor string
+	for _, row := range rows {
+		if cursor != nil && !afterCursor(row, cursor, desc) {
+			continue
+		}
+		if len(items) == limit {
+			nextCursor = models.Cursor{Time: last.time, ID: last.id}.Encode()
+			break
+		}
+		items = append(items, row.item)
+		last = row
+	}
+
+	return items, nextCursor
+}
+
+func sortKeyed[T any](rows []keyed[T], desc bool) {
+	less := func(a, b keyed[T]) bool {
+		return a.time.Before(b.time) || a.time.Equal(b.time) && a.id < b.id
+	}
+
+	sort.Slice(rows, func(i, j int) bool {
+		if desc {
+			return less(rows[j], rows[i])
+		}
+
+		return less(rows[i], rows[j])
+	})
+}
+
+func afterCursor[T any](row keyed[T], cursor *models.Cursor, desc bool) bool {
+	if row.time.Equal(cursor.Time) {
+		if desc {
+			return row.id < cursor.ID
+		}
+		return row.id > cursor.ID
+	}
+	if desc {
+		return row.time.Before(cursor.Time)
+	}
+
+	return row.time.After(cursor.Time)
+}
+
+// inPeriod Полуинтервал [from, to), границы необязательны
+func inPeriod(t time.Time, from *time.Time, to *time.Time) bool {
+	return (from == nil || !t.Before(*from)) && (to == nil || t.Before(*to))
+}
+
+// rewardRule Неудалённое правило по id
+func (s *store) rewardRule(id uint) *entities.RewardRule {
+	for _, rule := range s.rewardRules {
+		if rule.ID == id && !rule.DeletedAt.Valid {
+			return rule
+		}
+	}
+
+	return nil
+}
+
+// webhook Неудалённый вебхук по id
+func (s *store) webhook(id uint) *entities.Webhook {
+	for _, webhook := range s.webhooks {
+		if webhook.ID == id && !webhook.DeletedAt.Valid {
+			return webhook
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,116 @@
+package memory
+
+import (
+	"strconv"
+
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+	"github.com/ShukinDmitriy/gophermart/internal/models"
+	"github.com/ShukinDmitriy/gophermart/internal/repositories"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type UserRepository struct {
+	store *store
+}
+
+// Create Пользователь со свободным и бонусным счётом. Занятый логин даёт repositories.ErrLoginAlreadyExists
+func (r *UserRepository) Create(userRegister models.UserRegisterRequest) (*models.UserInfoResponse, error) {
+	passwordHash, err := r.GeneratePasswordHash(userRegister.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, user := range r.store.users {
+		if user.Login == userRegister.Login {
+			return nil, repositories.ErrLoginAlreadyExists
+		}
+	}
+
+	now := currentTime()
+	user := &entities.User{
+		Model:    newModel(nextID(r.store.users), now),
+		Login:    userRegister.Login,
+		Password: string(passwordHash),
+	}
+	r.store.users = append(r.store.users, user)
+
+	for _, accountType := range []entities.AccountType{entities.AccountTypeFree, entities.AccountTypeBonus} {
+		r.store.accounts = append(r.store.accounts, &entities.Account{
+			Model:  newModel(nextID(r.store.accounts), now),
+			Type:   accountType,
+			UserID: user.ID,
+		})
+	}
+
+	userID := strconv.FormatUint(uint64(user.ID), 10)
+	err = r.store.addOutboxEvent(entities.AggregateUser, userID, entities.DomainEventUserRegistered, models.UserRegisteredEvent{
+		UserID: user.ID,
+		Login:  user.Login,
+	}, now)
+	if err != nil {
+		return nil, err
+	}
+
+	return mapUserInfo(user), nil
+}
+
+// Find Как и в Postgres, для неизвестного id возвращается пустой пользователь без ошибки
+func (r *UserRepository) Find(id uint) (*models.UserInfoResponse, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, user := range r.store.users {
+		if user.ID == id {
+			return mapUserInfo(user), nil
+		}
+	}
+
+	return &models.UserInfoResponse{}, nil
+}
+
+func (r *UserRepository) FindBy(filter models.UserSearchFilter) (*entities.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, user := range r.store.users {
+		if (filter.ID == 0 || user.ID == filter.ID) && (filter.Login == "" || user.Login == filter.Login) {
+			found := *user
+			return &found, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (r *UserRepository) UpdateNotificationPreferences(userID uint, email string, preferences entities.NotificationPreferences) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, user := range r.store.users {
+		if user.ID == userID {
+			user.Email = email
+			user.Notifications = preferences
+			user.UpdatedAt = currentTime()
+		}
+	}
+
+	return nil
+}
+
+func (r *UserRepository) GeneratePasswordHash(password string) ([]byte, error) {
+	return bcrypt.GenerateFromPassword([]byte(password), 8)
+}
+
+func mapUserInfo(user *entities.User) *models.UserInfoResponse {
+	return &models.UserInfoResponse{
+		ID:         user.ID,
+		LastName:   user.LastName,
+		FirstName:  user.FirstName,
+		MiddleName: user.MiddleName,
+		Login:      user.Login,
+		Email:      user.Email,
+	}
+}
@@ -0,0 +1,179 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+	"github.com/ShukinDmitriy/gophermart/internal/repositories"
+	"gorm.io/gorm"
+)
+
+type WebhookRepository struct {
+	store *store
+}
+
+func (r *WebhookRepository) WithContext(context.Context) repositories.WebhookRepositoryInterface {
+	return r
+}
+
+func (r *WebhookRepository) CreateWebhook(webhook *entities.Webhook) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	webhook.Model = newModel(nextID(r.store.webhooks), currentTime())
+	r.store.webhooks = append(r.store.webhooks, copyWebhook(webhook))
+
+	return nil
+}
+
+// DeleteWebhook Мягкое удаление, как у GORM
+func (r *WebhookRepository) DeleteWebhook(id uint) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if stored := r.store.webhook(id); stored != nil {
+		stored.DeletedAt = gorm.DeletedAt{Time: currentTime(), Valid: true}
+	}
+
+	return nil
+}
+
+func (r *WebhookRepository) FindWebhook(id uint) (*entities.Webhook, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	stored := r.store.webhook(id)
+	if stored == nil {
+		return nil, nil
+	}
+
+	return copyWebhook(stored), nil
+}
+
+func (r *WebhookRepository) GetWebhooks(userID *uint) ([]*entities.Webhook, error) {
+	return r.findWebhooks(func(webhook *entities.Webhook) bool {
+		if userID == nil {
+			return webhook.UserID == nil
+		}
+
+		return webhook.UserID != nil && *webhook.UserID == *userID
+	}), nil
+}
+
+func (r *WebhookRepository) GetWebhooksForUser(userID uint) ([]*entities.Webhook, error) {
+	return r.findWebhooks(func(webhook *entities.Webhook) bool {
+		return webhook.UserID == nil || *webhook.UserID == userID
+	}), nil
+}
+
+func (r *WebhookRepository) CreateDeliveries(deliveries []*entities.WebhookDelivery) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	now := currentTime()
+	for _, delivery := range deliveries {
+		delivery.Model = newModel(nextID(r.store.webhookDeliveries), now)
+		stored := *delivery
+		r.store.webhookDeliveries = append(r.store.webhookDeliveries, &stored)
+	}
+
+	return nil
+}
+
+// ClaimDeliveries Под одной блокировкой забирать доставку параллельно некому
+func (r *WebhookRepository) ClaimDeliveries(now time.Time, lease time.Duration, limit int) ([]*entities.WebhookDelivery, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var due []*entities.WebhookDelivery
+	for _, stored := range r.store.webhookDeliveries {
+		if stored.Status == entities.WebhookDeliveryPending && !stored.NextAttemptAt.After(now) {
+			due = append(due, stored)
+		}
+	}
+	slices.SortStableFunc(due, func(a, b *entities.WebhookDelivery) int {
+		return a.NextAttemptAt.Compare(b.NextAttemptAt)
+	})
+
+	claimed := make([]*entities.WebhookDelivery, 0, min(len(due), limit))
+	for _, stored := range due[:min(len(due), limit)] {
+		stored.Attempts++
+		stored.NextAttemptAt = now.Add(lease)
+		stored.UpdatedAt = currentTime()
+		found := *stored
+		claimed = append(claimed, &found)
+	}
+
+	return claimed, nil
+}
+
+func (r *WebhookRepository) UpdateDelivery(delivery *entities.WebhookDelivery) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, stored := range r.store.webhookDeliveries {
+		if stored.ID != delivery.ID {
+			continue
+		}
+
+		stored.Status = delivery.Status
+		stored.NextAttemptAt = delivery.NextAttemptAt
+		stored.LastStatusCode = delivery.LastStatusCode
+		stored.LastError = delivery.LastError
+		stored.DeliveredAt = delivery.DeliveredAt
+		stored.UpdatedAt = currentTime()
+	}
+
+	return nil
+}
+
+func (r *WebhookRepository) GetDeliveries(
+	webhookID uint,
+	statuses []entities.WebhookDeliveryStatus,
+	limit int,
+) ([]*entities.WebhookDelivery, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	deliveries := make([]*entities.WebhookDelivery, 0)
+	for i := len(r.store.webhookDeliveries) - 1; i >= 0 && len(deliveries) < limit; i-- {
+		stored := r.store.webhookDeliveries[i]
+		if stored.WebhookID != webhookID || len(statuses) > 0 && !slices.Contains(statuses, stored.Status) {
+			continue
+		}
+		found := *stored
+		deliveries = append(deliveries, &found)
+	}
+
+	return deliveries, nil
+}
+
+// findWebhooks Копии неудалённых вебхуков в порядке создания
+func (r *WebhookRepository) findWebhooks(match func(webhook *entities.Webhook) bool) []*entities.Webhook {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	webhooks := make([]*entities.Webhook, 0)
+	for _, stored := range r.store.webhooks {
+		if stored.DeletedAt.Valid || !match(stored) {
+			continue
+		}
+		webhooks = append(webhooks, copyWebhook(stored))
+	}
+
+	return webhooks
+}
+
+// copyWebhook Копия без общих с хранилищем указателя на владельца и списка событий
+func copyWebhook(webhook *entities.Webhook) *entities.Webhook {
+	found := *webhook
+	if webhook.UserID != nil {
+		userID := *webhook.UserID
+		found.UserID = &userID
+	}
+	found.Events = slices.Clone(webhook.Events)
+
+	return &found
+}
@@ -0,0 +1,158 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+	"gorm.io/gorm"
+)
+
+var notificationRepository *NotificationRepository
+
+type NotificationRepository struct {
+	db *gorm.DB
+}
+
+func NewNotificationRepository(db *gorm.DB) *NotificationRepository {
+	notificationRepository = &NotificationRepository{
+		db: db,
+	}
+
+	return notificationRepository
+}
+
+// WithContext Копия репозитория, запросы которой выполняются в контексте ctx (отмена, трассировка)
+func (r *NotificationRepository) WithContext(ctx context.Context) NotificationRepositoryInterface {
+	return &NotificationRepository{
+		db: r.db.WithContext(ctx),
+	}
+}
+
+// CreateNotification Уведомление и его отправки по внешним каналам в одной транзакции
+func (r *NotificationRepository) CreateNotification(
+	notification *entities.Notification,
+	deliveries []*entities.NotificationDelivery,
+) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entities.Notification{}).Create(notification).Error; err != nil {
+			return err
+		}
+		if len(deliveries) == 0 {
+			return nil
+		}
+		for _, delivery := range deliveries {
+			delivery.NotificationID = notification.ID
+			delivery.NextAttemptAt = dbTime(delivery.NextAttemptAt)
+		}
+
+		return tx.Model(&entities.NotificationDelivery{}).Create(deliveries).Error
+	})
+}
+
+func (r *NotificationRepository) FindNotification(id uint) (*entities.Notification, error) {
+	notification := &entities.Notification{}
+
+	if err := r.db.Where("notifications.id = ?", id).First(notification).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return notification, nil
+}
+
+// GetNotifications Уведомления пользователя, новые первыми
+func (r *NotificationRepository) GetNotifications(userID uint, unreadOnly bool, limit int) ([]*entities.Notification, error) {
+	var notifications []*entities.Notification
+
+	query := r.db.Where("notifications.user_id = ?", userID)
+	if unreadOnly {
+		query = query.Where("notifications.read_at is null")
+	}
+	err := query.Order("notifications.id desc").Limit(limit).Find(&notifications).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return notifications, nil
+}
+
+func (r *NotificationRepository) CountUnread(userID uint) (int64, error) {
+	var count int64
+
+	err := r.db.Model(&entities.Notification{}).
+		Where("notifications.user_id = ?", userID).
+		Where("notifications.read_at is null").
+		Count(&count).Error
+
+	return count, err
+}
+
+// MarkRead Отмечает прочитанными непрочитанные уведомления пользователя ids, пустой ids отмечает все.
+// Время прочтения уже прочитанных не меняется
+func (r *NotificationRepository) MarkRead(userID uint, ids []uint, readAt time.Time) (int64, error) {
+	query := r.db.Model(&entities.Notification{}).
+		Where("notifications.user_id = ?", userID).
+		Where("notifications.read_at is null")
+	if len(ids) > 0 {
+		query = query.Where("notifications.id in ?", ids)
+	}
+	result := query.Update("read_at", dbTime(readAt))
+
+	return result.RowsAffected, result.Error
+}
+
+// ClaimDeliveries Отправки, время попытки которых наступило. Забираются так же, как доставки вебхуков
+// в WebhookRepository.ClaimDeliveries: условным обновлением по числу попыток с арендой lease
+func (r *NotificationRepository) ClaimDeliveries(now time.Time, lease time.Duration, limit int) ([]*entities.NotificationDelivery, error) {
+	var due []*entities.NotificationDelivery
+
+	err := r.db.
+		Where("notification_deliveries.status = ?", entities.NotificationDeliveryPending).
+		Where("notification_deliveries.next_attempt_at <= ?", dbTime(now)).
+		Order("notification_deliveries.next_attempt_at, notification_deliveries.id").
+		Limit(limit).
+		Find(&due).Error
+	if err != nil {
+		return nil, err
+	}
+
+	leasedUntil := dbTime(now.Add(lease))
+	claimed := make([]*entities.NotificationDelivery, 0, len(due))
+	for _, delivery := range due {
+		result := r.db.Model(&entities.NotificationDelivery{}).
+			Where("notification_deliveries.id = ?", delivery.ID).
+			Where("notification_deliveries.status = ?", entities.NotificationDeliveryPending).
+			Where("notification_deliveries.attempts = ?", delivery.Attempts).
+			Updates(map[string]interface{}{
+				"attempts":        delivery.Attempts + 1,
+				"next_attempt_at": leasedUntil,
+			})
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		if result.RowsAffected == 0 {
+			continue
+		}
+
+		delivery.Attempts++
+		delivery.NextAttemptAt = leasedUntil
+		claimed = append(claimed, delivery)
+	}
+
+	return claimed, nil
+}
+
+// UpdateDelivery Итог попытки: статус, время следующей попытки и ошибка
+func (r *NotificationRepository) UpdateDelivery(delivery *entities.NotificationDelivery) error {
+	return r.db.Model(&entities.NotificationDelivery{}).Where("notification_deliveries.id = ?", delivery.ID).Updates(map[string]interface{}{
+		"status":          delivery.Status,
+		"next_attempt_at": dbTime(delivery.NextAttemptAt),
+		"last_error":      delivery.LastError,
+		"delivered_at":    dbTimePtr(delivery.DeliveredAt),
+	}).Error
+}
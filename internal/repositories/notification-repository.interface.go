@@ -0,0 +1,19 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+)
+
+type NotificationRepositoryInterface interface {
+	WithContext(ctx context.Context) NotificationRepositoryInterface
+	CreateNotification(notification *entities.Notification, deliveries []*entities.NotificationDelivery) error
+	FindNotification(id uint) (*entities.Notification, error)
+	GetNotifications(userID uint, unreadOnly bool, limit int) ([]*entities.Notification, error)
+	CountUnread(userID uint) (int64, error)
+	MarkRead(userID uint, ids []uint, readAt time.Time) (int64, error)
+	ClaimDeliveries(now time.Time, lease time.Duration, limit int) ([]*entities.NotificationDelivery, error)
+	UpdateDelivery(delivery *entities.NotificationDelivery) error
+}
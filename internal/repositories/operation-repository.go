@@ -2,7 +2,9 @@ package repositories
 
 import (
 	"context"
+	"database/sql"
 	"errors"
+	"strconv"
 	"time"
 
 	"github.com/ShukinDmitriy/gophermart/internal/entities"
@@ -29,6 +31,13 @@ func (r *OperationRepository) Migrate(ctx context.Context) error {
 	return r.db.WithContext(ctx).AutoMigrate(&m)
 }
 
+// WithContext Копия репозитория, запросы которой выполняются в контексте ctx (отмена, трассировка)
+func (r *OperationRepository) WithContext(ctx context.Context) OperationRepositoryInterface {
+	return &OperationRepository{
+		db: r.db.WithContext(ctx),
+	}
+}
+
 func (r *OperationRepository) GetWithdrawnByAccountID(accountID uint) (float32, error) {
 	var withdrawn float32
 
@@ -50,7 +59,7 @@ func (r *OperationRepository) GetWithdrawnByAccountID(accountID uint) (float32,
 }
 
 func (r *OperationRepository) CreateWithdrawn(accountID uint, orderNumber string, sum float32) error {
-	systemWithdrawnAccount, err := accountRepository.GetSystemWithdrawnAccountID()
+	systemWithdrawnAccount, err := (&AccountRepository{db: r.db}).GetSystemWithdrawnAccountID()
 	if err != nil {
 		return err
 	}
@@ -58,38 +67,43 @@ func (r *OperationRepository) CreateWithdrawn(accountID uint, orderNumber string
 		return errors.New("cannot create withdrawn")
 	}
 
-	now := time.Now()
-
-	tx := r.db.Begin()
-	err = tx.Table("operations").
-		Create(map[string]interface{}{
-			"created_at":           now,
-			"updated_at":           now,
-			"processed_at":         now,
-			"type":                 entities.OperationTypeWithdraw,
-			"order_number":         orderNumber,
-			"sum":                  sum,
-			"sender_account_id":    accountID,
-			"recipient_account_id": systemWithdrawnAccount,
-		}).Error
-	if err != nil {
-		tx.Rollback()
-		return err
-	}
+	now := r.db.NowFunc()
 
-	err = accountRepository.Transaction(accountID, systemWithdrawnAccount, sum)
-	if err != nil {
-		tx.Rollback()
-		return err
-	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// остаток списывается первым, чтобы блокировка счёта бралась до остальных записей
+		if err := (&AccountRepository{db: tx}).Withdraw(accountID, systemWithdrawnAccount, sum); err != nil {
+			return err
+		}
 
-	tx.Commit()
+		err := tx.Table("operations").
+			Create(map[string]interface{}{
+				"created_at":           now,
+				"updated_at":           now,
+				"processed_at":         now,
+				"type":                 entities.OperationTypeWithdraw,
+				"order_number":         orderNumber,
+				"sum":                  sum,
+				"sender_account_id":    accountID,
+				"recipient_account_id": systemWithdrawnAccount,
+			}).Error
+		if err != nil {
+			return err
+		}
 
-	return nil
+		return createPointsEvent(tx, entities.DomainEventPointsWithdrawn, accountID, orderNumber, sum)
+	})
 }
 
 func (r *OperationRepository) CreateAccrual(accountID uint, orderNumber string, sum float32) error {
-	systemWithdrawnAccount, err := accountRepository.GetSystemWithdrawnAccountID()
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return createAccrual(tx, accountID, orderNumber, sum)
+	})
+}
+
+// createAccrual Начисление за заказ в транзакции tx. Второе начисление за тот же заказ
+// отклоняет уникальный индекс, даже если его проводят параллельно
+func createAccrual(tx *gorm.DB, accountID uint, orderNumber string, sum float32) error {
+	systemWithdrawnAccount, err := (&AccountRepository{db: tx}).GetSystemWithdrawnAccountID()
 	if err != nil {
 		return err
 	}
@@ -97,9 +111,8 @@ func (r *OperationRepository) CreateAccrual(accountID uint, orderNumber string,
 		return errors.New("cannot create accrual")
 	}
 
-	now := time.Now()
+	now := tx.NowFunc()
 
-	tx := r.db.Begin()
 	err = tx.Table("operations").
 		Create(map[string]interface{}{
 			"created_at":           now,
@@ -111,20 +124,34 @@ func (r *OperationRepository) CreateAccrual(accountID uint, orderNumber string,
 			"sender_account_id":    systemWithdrawnAccount,
 			"recipient_account_id": accountID,
 		}).Error
+	if uniqueViolation(err, constraintOperationsAccrual) {
+		return ErrAccrualAlreadyExists
+	}
 	if err != nil {
-		tx.Rollback()
 		return err
 	}
 
-	err = accountRepository.Transaction(systemWithdrawnAccount, accountID, sum)
-	if err != nil {
-		tx.Rollback()
+	if err = (&AccountRepository{db: tx}).Transaction(systemWithdrawnAccount, accountID, sum); err != nil {
 		return err
 	}
 
-	tx.Commit()
+	return createPointsEvent(tx, entities.DomainEventPointsAccrued, accountID, orderNumber, sum)
+}
+
+// createPointsEvent События по баллам упорядочены в пределах счёта
+func createPointsEvent(tx *gorm.DB, eventType entities.DomainEventType, accountID uint, orderNumber string, sum float32) error {
+	var userID uint
+	err := tx.Table("accounts").Select("accounts.user_id").Where("accounts.id = ?", accountID).Scan(&userID).Error
+	if err != nil {
+		return err
+	}
 
-	return nil
+	return createOutboxEvent(tx, entities.AggregateAccount, strconv.FormatUint(uint64(accountID), 10), eventType, models.PointsEvent{
+		AccountID: accountID,
+		UserID:    userID,
+		Order:     orderNumber,
+		Sum:       sum,
+	})
 }
 
 func (r *OperationRepository) GetWithdrawalsByAccountID(accountID uint) ([]models.GetWithdrawalsResponse, error) {
@@ -132,11 +159,188 @@ func (r *OperationRepository) GetWithdrawalsByAccountID(accountID uint) ([]model
 
 	err := r.db.Table("operations").
 		Select(`
-			operations.order_number as order,
+			operations.order_number as "order",
+			operations.sum as sum,
+			operations.processed_at as processed_at
+		`).
+		Where("operations.sender_account_id = ?", accountID).
+		Where("operations.type = ?", entities.OperationTypeWithdraw).
+		Where("operations.deleted_at is null").
+		Where("operations.processed_at is not null").
+		Order("operations.processed_at desc, operations.id desc").
+		Scan(&operations).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return operations, nil
+}
+
+func (r *OperationRepository) GetWithdrawalsPage(filter models.OperationSearchFilter) (*models.GetWithdrawalsPage, error) {
+	var rows []struct {
+		ID          uint
+		Order       string
+		Sum         float32
+		ProcessedAt time.Time
+	}
+
+	query := r.db.Table("operations").
+		Select(`
+			operations.id as id,
+			operations.order_number as "order",
 			operations.sum as sum,
 			operations.processed_at as processed_at
 		`).
-		Where("operations.sender_account_id = ?", accountID).Scan(&operations).Error
+		Where("operations.sender_account_id = ?", filter.AccountID).
+		Where("operations.type = ?", entities.OperationTypeWithdraw).
+		Where("operations.deleted_at is null").
+		Where("operations.processed_at is not null")
+
+	query = applyOperationFilter(query, "operations", filter)
+
+	if err := query.Limit(filter.Limit + 1).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	page := &models.GetWithdrawalsPage{
+		Withdrawals: make([]models.GetWithdrawalsResponse, 0, len(rows)),
+	}
+
+	if len(rows) > filter.Limit {
+		rows = rows[:filter.Limit]
+		last := rows[len(rows)-1]
+		page.NextCursor = models.Cursor{Time: last.ProcessedAt, ID: last.ID}.Encode()
+	}
+
+	for _, row := range rows {
+		processedAt := models.JSONTime(row.ProcessedAt)
+		page.Withdrawals = append(page.Withdrawals, models.GetWithdrawalsResponse{
+			Order:       row.Order,
+			Sum:         row.Sum,
+			ProcessedAt: &processedAt,
+		})
+	}
+
+	return page, nil
+}
+
+// GetOperationsPage Все движения по счёту с остатком после каждой операции.
+// Остаток считается по всем операциям счёта до применения фильтров, поэтому
+// он корректен на любой странице выборки.
+func (r *OperationRepository) GetOperationsPage(filter models.OperationSearchFilter) (*models.GetOperationsPage, error) {
+	var rows []struct {
+		ID          uint
+		Type        entities.OperationType
+		OrderNumber string
+		Sum         float32
+		Balance     float32
+		ProcessedAt time.Time
+	}
+
+	ledger := r.db.Table("operations").
+		Select(`
+			operations.id           as id,
+			operations.type         as type,
+			operations.order_number as order_number,
+			operations.processed_at as processed_at,
+			case when operations.recipient_account_id = @account then operations.sum else -operations.sum end as sum,
+			sum(case when operations.recipient_account_id = @account then operations.sum else -operations.sum end)
+				over (order by operations.processed_at, operations.id) as balance
+		`, sql.Named("account", filter.AccountID)).
+		Where("(operations.sender_account_id = @account or operations.recipient_account_id = @account)", sql.Named("account", filter.AccountID)).
+		Where("operations.deleted_at is null").
+		Where("operations.processed_at is not null")
+
+	query := r.db.Table("(?) as ledger", ledger).
+		Select("ledger.id, ledger.type, ledger.order_number, ledger.sum, ledger.balance, ledger.processed_at")
+
+	query = applyOperationFilter(query, "ledger", filter)
+
+	if err := query.Limit(filter.Limit + 1).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	page := &models.GetOperationsPage{
+		Operations: make([]models.GetOperationsResponse, 0, len(rows)),
+	}
+
+	if len(rows) > filter.Limit {
+		rows = rows[:filter.Limit]
+		last := rows[len(rows)-1]
+		page.NextCursor = models.Cursor{Time: last.ProcessedAt, ID: last.ID}.Encode()
+	}
+
+	for _, row := range rows {
+		page.Operations = append(page.Operations, models.GetOperationsResponse{
+			Type:        row.Type,
+			Order:       row.OrderNumber,
+			Sum:         row.Sum,
+			Balance:     row.Balance,
+			ProcessedAt: models.JSONTime(row.ProcessedAt),
+		})
+	}
+
+	return page, nil
+}
+
+func applyOperationFilter(query *gorm.DB, table string, filter models.OperationSearchFilter) *gorm.DB {
+	if len(filter.Types) > 0 {
+		query = query.Where(table+".type in ?", filter.Types)
+	}
+
+	if filter.Order != "" {
+		query = query.Where(table+".order_number = ?", filter.Order)
+	}
+
+	if filter.ProcessedFrom != nil {
+		query = query.Where(table+".processed_at >= ?", dbTime(*filter.ProcessedFrom))
+	}
+
+	if filter.ProcessedTo != nil {
+		query = query.Where(table+".processed_at < ?", dbTime(*filter.ProcessedTo))
+	}
+
+	if filter.Sort == models.SortDirectionDesc {
+		if filter.Cursor != nil {
+			query = query.Where("("+table+".processed_at, "+table+".id) < (?, ?)", dbTime(filter.Cursor.Time), filter.Cursor.ID)
+		}
+
+		return query.Order(table + ".processed_at desc, " + table + ".id desc")
+	}
+
+	if filter.Cursor != nil {
+		query = query.Where("("+table+".processed_at, "+table+".id) > (?, ?)", dbTime(filter.Cursor.Time), filter.Cursor.ID)
+	}
+
+	return query.Order(table + ".processed_at, " + table + ".id")
+}
+
+func (r *OperationRepository) FindAccrualByOrderNumber(orderNumber string) (*entities.Operation, error) {
+	operation := &entities.Operation{}
+
+	query := r.db.
+		Where("operations.type = ?", entities.OperationTypeAccrual).
+		Where("operations.order_number = ?", orderNumber)
+
+	if err := query.First(&operation).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return operation, nil
+}
+
+func (r *OperationRepository) GetOperationsByOrderNumber(orderNumber string) ([]*entities.Operation, error) {
+	var operations []*entities.Operation
+
+	err := r.db.
+		Where("operations.order_number = ?", orderNumber).
+		Where("operations.processed_at is not null").
+		Order("operations.processed_at, operations.id").
+		Find(&operations).Error
 	if err != nil {
 		return nil, err
 	}
@@ -1,10 +1,20 @@
 package repositories
 
-import "github.com/ShukinDmitriy/gophermart/internal/models"
+import (
+	"context"
+
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+	"github.com/ShukinDmitriy/gophermart/internal/models"
+)
 
 type OperationRepositoryInterface interface {
+	WithContext(ctx context.Context) OperationRepositoryInterface
 	CreateAccrual(accountID uint, orderNumber string, sum float32) error
 	CreateWithdrawn(accountID uint, orderNumber string, sum float32) error
 	GetWithdrawnByAccountID(accountID uint) (float32, error)
 	GetWithdrawalsByAccountID(accountID uint) ([]models.GetWithdrawalsResponse, error)
+	GetWithdrawalsPage(filter models.OperationSearchFilter) (*models.GetWithdrawalsPage, error)
+	GetOperationsPage(filter models.OperationSearchFilter) (*models.GetOperationsPage, error)
+	FindAccrualByOrderNumber(orderNumber string) (*entities.Operation, error)
+	GetOperationsByOrderNumber(orderNumber string) ([]*entities.Operation, error)
 }
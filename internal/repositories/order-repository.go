@@ -3,10 +3,14 @@ package repositories
 import (
 	"context"
 	"errors"
+	"fmt"
+	"slices"
+	"time"
 
 	"github.com/ShukinDmitriy/gophermart/internal/entities"
 	"github.com/ShukinDmitriy/gophermart/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 var orderRepository *OrderRepository
@@ -28,6 +32,13 @@ func (r *OrderRepository) Migrate(ctx context.Context) error {
 	return r.db.WithContext(ctx).AutoMigrate(&m)
 }
 
+// WithContext Копия репозитория, запросы которой выполняются в контексте ctx (отмена, трассировка)
+func (r *OrderRepository) WithContext(ctx context.Context) OrderRepositoryInterface {
+	return &OrderRepository{
+		db: r.db.WithContext(ctx),
+	}
+}
+
 func (r *OrderRepository) Create(number string, userID uint) (*entities.Order, error) {
 	order := &entities.Order{
 		Number: number,
@@ -35,9 +46,27 @@ func (r *OrderRepository) Create(number string, userID uint) (*entities.Order, e
 		Status: entities.OrderStatusNew,
 	}
 
-	tx := r.db.Model(&entities.Order{}).
-		Create(&order)
-	err := tx.Error
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&entities.Order{}).
+			Create(&order).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Create(&entities.OrderStatusHistory{
+			OrderID:     order.ID,
+			OrderNumber: order.Number,
+			Status:      order.Status,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		return createOrderUploadedEvent(tx, order)
+	})
+	if uniqueViolation(err, constraintOrdersNumber) {
+		return nil, ErrOrderAlreadyExists
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -45,6 +74,73 @@ func (r *OrderRepository) Create(number string, userID uint) (*entities.Order, e
 	return order, nil
 }
 
+// CreateBatch Создание заказов одной транзакцией
+func (r *OrderRepository) CreateBatch(numbers []string, userID uint) ([]*entities.Order, error) {
+	orders := make([]*entities.Order, 0, len(numbers))
+	for _, number := range numbers {
+		orders = append(orders, &entities.Order{
+			Number: number,
+			UserID: userID,
+			Status: entities.OrderStatusNew,
+		})
+	}
+
+	if len(orders) == 0 {
+		return orders, nil
+	}
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&entities.Order{}).
+			Create(&orders).Error
+		if err != nil {
+			return err
+		}
+
+		history := make([]*entities.OrderStatusHistory, 0, len(orders))
+		for _, order := range orders {
+			history = append(history, &entities.OrderStatusHistory{
+				OrderID:     order.ID,
+				OrderNumber: order.Number,
+				Status:      order.Status,
+			})
+		}
+
+		if err = tx.Create(&history).Error; err != nil {
+			return err
+		}
+
+		for _, order := range orders {
+			if err = createOrderUploadedEvent(tx, order); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if uniqueViolation(err, constraintOrdersNumber) {
+		return nil, ErrOrderAlreadyExists
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return orders, nil
+}
+
+func (r *OrderRepository) FindByNumbers(numbers []string) ([]*entities.Order, error) {
+	var orders []*entities.Order
+
+	if len(numbers) == 0 {
+		return orders, nil
+	}
+
+	if err := r.db.Where("orders.number in ?", numbers).Find(&orders).Error; err != nil {
+		return nil, err
+	}
+
+	return orders, nil
+}
+
 func (r *OrderRepository) FindByNumber(number string) (*entities.Order, error) {
 	order := &entities.Order{}
 
@@ -61,42 +157,112 @@ func (r *OrderRepository) FindByNumber(number string) (*entities.Order, error) {
 	return order, nil
 }
 
-func (r *OrderRepository) GetOrdersByUserID(userID uint) ([]*models.GetOrdersResponse, error) {
-	var orders []*models.GetOrdersResponse
+func (r *OrderRepository) GetOrdersPage(filter models.OrderSearchFilter) (*models.GetOrdersPage, error) {
+	var rows []struct {
+		ID         uint
+		Number     string
+		Status     entities.OrderStatus
+		Accrual    float32
+		UploadedAt time.Time
+	}
 
 	query := r.db.
 		Table("orders").
 		Select(`
+			orders.id         as id,
 			orders.number     as number,
 			orders.status     as status,
 			orders.accrual    as accrual,
 			orders.created_at as uploaded_at
 		`).
-		Order("orders.created_at").
-		Where("orders.user_id = ?", userID)
+		Where("orders.user_id = ?", filter.UserID).
+		Where("orders.deleted_at is null")
 
-	if err := query.Scan(&orders).Error; err != nil {
+	if len(filter.Statuses) > 0 {
+		query = query.Where("orders.status in ?", filter.Statuses)
+	}
+
+	if filter.UploadedFrom != nil {
+		query = query.Where("orders.created_at >= ?", dbTime(*filter.UploadedFrom))
+	}
+
+	if filter.UploadedTo != nil {
+		query = query.Where("orders.created_at < ?", dbTime(*filter.UploadedTo))
+	}
+
+	if filter.Sort == models.SortDirectionDesc {
+		if filter.Cursor != nil {
+			query = query.Where("(orders.created_at, orders.id) < (?, ?)", dbTime(filter.Cursor.Time), filter.Cursor.ID)
+		}
+		query = query.Order("orders.created_at desc, orders.id desc")
+	} else {
+		if filter.Cursor != nil {
+			query = query.Where("(orders.created_at, orders.id) > (?, ?)", dbTime(filter.Cursor.Time), filter.Cursor.ID)
+		}
+		query = query.Order("orders.created_at, orders.id")
+	}
+
+	// Запрашиваем на одну запись больше, чтобы понять, есть ли следующая страница
+	if err := query.Limit(filter.Limit + 1).Scan(&rows).Error; err != nil {
 		return nil, err
 	}
 
-	return orders, nil
+	page := &models.GetOrdersPage{
+		Orders: make([]*models.GetOrdersResponse, 0, len(rows)),
+	}
+
+	if len(rows) > filter.Limit {
+		rows = rows[:filter.Limit]
+		last := rows[len(rows)-1]
+		page.NextCursor = models.Cursor{Time: last.UploadedAt, ID: last.ID}.Encode()
+	}
+
+	for _, row := range rows {
+		page.Orders = append(page.Orders, &models.GetOrdersResponse{
+			Number:     row.Number,
+			Status:     row.Status,
+			Accrual:    row.Accrual,
+			UploadedAt: models.JSONTime(row.UploadedAt),
+		})
+	}
+
+	return page, nil
 }
 
 func (r *OrderRepository) GetOrdersForProcess() ([]*entities.Order, error) {
+	return r.getOrdersForProcess(r.db.Where("orders.status in ?", []entities.OrderStatus{entities.OrderStatusNew, entities.OrderStatusProcessing}))
+}
+
+// GetOrdersWithBasketForProcess Заказы для локального расчёта: новые и те заказы в обработке,
+// состав которых уже загружен. Заказ без состава ждёт его загрузки и не опрашивается
+func (r *OrderRepository) GetOrdersWithBasketForProcess() ([]*entities.Order, error) {
+	return r.getOrdersForProcess(r.db.Where(
+		"orders.status = ? or (orders.status = ? and exists (?))",
+		entities.OrderStatusNew,
+		entities.OrderStatusProcessing,
+		r.db.Table("order_baskets").
+			Select("1").
+			Where("order_baskets.order_number = orders.number").
+			Where("order_baskets.deleted_at is null"),
+	))
+}
+
+func (r *OrderRepository) getOrdersForProcess(condition *gorm.DB) ([]*entities.Order, error) {
 	var orders []*entities.Order
 
 	query := r.db.
 		Table("orders").
 		Select(`
-			orders.number     as id,
+			orders.id         as id,
 			orders.created_at as created_at,
 			orders.updated_at as updated_at,
 			orders.number     as number,
+			orders.user_id    as user_id,
 			orders.status     as status,
 			orders.accrual    as accrual
 		`).
 		Order("orders.created_at").
-		Where("orders.status in ?", []entities.OrderStatus{entities.OrderStatusNew, entities.OrderStatusProcessing}).
+		Where(condition).
 		Where("orders.deleted_at is null").
 		Limit(100)
 
@@ -107,9 +273,201 @@ func (r *OrderRepository) GetOrdersForProcess() ([]*entities.Order, error) {
 	return orders, nil
 }
 
+func (r *OrderRepository) GetProcessedOrdersByPeriod(from time.Time, to time.Time) ([]*entities.Order, error) {
+	var orders []*entities.Order
+
+	query := r.db.
+		Order("orders.updated_at").
+		Where("orders.status in ?", []entities.OrderStatus{entities.OrderStatusProcessed, entities.OrderStatusInvalid}).
+		Where("orders.updated_at >= ?", dbTime(from)).
+		Where("orders.updated_at < ?", dbTime(to))
+
+	if err := query.Find(&orders).Error; err != nil {
+		return nil, err
+	}
+
+	return orders, nil
+}
+
+// UpdateOrderByAccrualOrder Обновление заказа по ответу системы расчёта.
+// Каждое изменение статуса или начисления записывается в историю заказа.
+// Завершённый заказ не меняется (ErrOrderFinished), при переходе в PROCESSED в той же транзакции
+// баллы начисляются на бонусный счёт владельца
 func (r *OrderRepository) UpdateOrderByAccrualOrder(accrualOrder *models.AccrualOrderResponse) error {
-	return r.db.Table("orders").Where("orders.number = ?", accrualOrder.Order).Updates(map[string]interface{}{
-		"status":  accrualOrder.Status,
-		"accrual": accrualOrder.Accrual,
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		order, err := findOrderForUpdate(tx, accrualOrder.Order)
+		if err != nil || order == nil {
+			return err
+		}
+		if order.Status.Final() {
+			return ErrOrderFinished
+		}
+
+		err = updateOrder(tx, order, accrualOrder.Status, accrualOrder.Accrual)
+		if err != nil {
+			return err
+		}
+
+		if accrualOrder.Status != entities.OrderStatusProcessed {
+			return nil
+		}
+
+		bonusAccount, err := (&AccountRepository{db: tx}).FindByUserID(order.UserID, entities.AccountTypeBonus)
+		if err != nil {
+			return err
+		}
+		if bonusAccount == nil {
+			return fmt.Errorf("bonus account of user %d not found", order.UserID)
+		}
+
+		return createAccrual(tx, bonusAccount.ID, order.Number, accrualOrder.Accrual)
+	})
+}
+
+// CorrectOrderAccrual Исправление начисления обработанного заказа по данным сверки. Статус не меняется,
+// операция по счёту не проводится
+func (r *OrderRepository) CorrectOrderAccrual(orderNumber string, accrual float32) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		order, err := findOrderForUpdate(tx, orderNumber)
+		if err != nil || order == nil {
+			return err
+		}
+
+		return updateOrder(tx, order, order.Status, accrual)
+	})
+}
+
+// findOrderForUpdate Заказ по номеру с блокировкой строки до конца транзакции
+func findOrderForUpdate(tx *gorm.DB, number string) (*entities.Order, error) {
+	order := &entities.Order{}
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("orders.number = ?", number).
+		First(order).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// updateOrder Новые статус и начисление заказа. Изменение записывается в историю и в outbox
+func updateOrder(tx *gorm.DB, order *entities.Order, status entities.OrderStatus, accrual float32) error {
+	// Table без модели не обновляет updated_at сам, а по нему GetProcessedOrdersByPeriod выбирает заказы
+	err := tx.Table("orders").Where("orders.id = ?", order.ID).Updates(map[string]interface{}{
+		"status":     status,
+		"accrual":    accrual,
+		"updated_at": tx.NowFunc(),
+	}).Error
+	if err != nil {
+		return err
+	}
+
+	if order.Status == status && order.Accrual == accrual {
+		return nil
+	}
+
+	err = tx.Create(&entities.OrderStatusHistory{
+		OrderID:     order.ID,
+		OrderNumber: order.Number,
+		Status:      status,
+		Accrual:     accrual,
 	}).Error
+	if err != nil {
+		return err
+	}
+
+	return createOutboxEvent(tx, entities.AggregateOrder, order.Number, entities.DomainEventOrderStatusChanged, models.OrderStatusChangedEvent{
+		Order:          order.Number,
+		UserID:         order.UserID,
+		Status:         status,
+		PreviousStatus: order.Status,
+		Accrual:        accrual,
+	})
+}
+
+func createOrderUploadedEvent(tx *gorm.DB, order *entities.Order) error {
+	return createOutboxEvent(tx, entities.AggregateOrder, order.Number, entities.DomainEventOrderUploaded, models.OrderUploadedEvent{
+		Order:  order.Number,
+		UserID: order.UserID,
+	})
+}
+
+// CreateAccrualAttempt Запись обращения к системе расчёта. Повтор результата последнего обращения
+// по заказу не записывается, поэтому опрос заказа в обработке не растит таблицу
+func (r *OrderRepository) CreateAccrualAttempt(attempt *entities.AccrualAttempt) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var last entities.AccrualAttempt
+		err := tx.
+			Where("accrual_attempts.order_number = ?", attempt.OrderNumber).
+			Order("accrual_attempts.id desc").
+			Limit(1).
+			Find(&last).Error
+		if err != nil {
+			return err
+		}
+		if last.ID != 0 && last.SameResult(attempt) {
+			return nil
+		}
+
+		return tx.Model(&entities.AccrualAttempt{}).Create(attempt).Error
+	})
+}
+
+func (r *OrderRepository) GetStatusHistory(orderID uint) ([]*entities.OrderStatusHistory, error) {
+	var history []*entities.OrderStatusHistory
+
+	err := r.db.
+		Where("order_status_histories.order_id = ?", orderID).
+		Order("order_status_histories.created_at, order_status_histories.id").
+		Find(&history).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return history, nil
+}
+
+// GetAccrualAttempts Последние limit обращений по заказу в порядке времени
+func (r *OrderRepository) GetAccrualAttempts(orderNumber string, limit int) ([]*entities.AccrualAttempt, error) {
+	var attempts []*entities.AccrualAttempt
+
+	err := r.db.
+		Where("accrual_attempts.order_number = ?", orderNumber).
+		Order("accrual_attempts.created_at desc, accrual_attempts.id desc").
+		Limit(limit).
+		Find(&attempts).Error
+	if err != nil {
+		return nil, err
+	}
+	slices.Reverse(attempts)
+
+	return attempts, nil
+}
+
+// CountByStatus Количество заказов в каждом статусе
+func (r *OrderRepository) CountByStatus() (map[entities.OrderStatus]int64, error) {
+	var rows []struct {
+		Status entities.OrderStatus
+		Count  int64
+	}
+
+	err := r.db.
+		Model(&entities.Order{}).
+		Select("orders.status as status, count(*) as count").
+		Group("orders.status").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[entities.OrderStatus]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+
+	return counts, nil
 }
@@ -1,14 +1,28 @@
 package repositories
 
 import (
+	"context"
+
+	"time"
+
 	"github.com/ShukinDmitriy/gophermart/internal/entities"
 	"github.com/ShukinDmitriy/gophermart/internal/models"
 )
 
 type OrderRepositoryInterface interface {
+	WithContext(ctx context.Context) OrderRepositoryInterface
 	Create(number string, userID uint) (*entities.Order, error)
+	CreateBatch(numbers []string, userID uint) ([]*entities.Order, error)
 	UpdateOrderByAccrualOrder(accrualOrder *models.AccrualOrderResponse) error
+	CorrectOrderAccrual(orderNumber string, accrual float32) error
 	GetOrdersForProcess() ([]*entities.Order, error)
+	GetOrdersWithBasketForProcess() ([]*entities.Order, error)
 	FindByNumber(number string) (*entities.Order, error)
-	GetOrdersByUserID(userID uint) ([]*models.GetOrdersResponse, error)
+	FindByNumbers(numbers []string) ([]*entities.Order, error)
+	GetOrdersPage(filter models.OrderSearchFilter) (*models.GetOrdersPage, error)
+	GetProcessedOrdersByPeriod(from time.Time, to time.Time) ([]*entities.Order, error)
+	CreateAccrualAttempt(attempt *entities.AccrualAttempt) error
+	GetStatusHistory(orderID uint) ([]*entities.OrderStatusHistory, error)
+	GetAccrualAttempts(orderNumber string, limit int) ([]*entities.AccrualAttempt, error)
+	CountByStatus() (map[entities.OrderStatus]int64, error)
 }
@@ -0,0 +1,133 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+	"gorm.io/gorm"
+)
+
+var outboxRepository *OutboxRepository
+
+type OutboxRepository struct {
+	db *gorm.DB
+}
+
+func NewOutboxRepository(db *gorm.DB) *OutboxRepository {
+	outboxRepository = &OutboxRepository{
+		db: db,
+	}
+
+	return outboxRepository
+}
+
+// WithContext Копия репозитория, запросы которой выполняются в контексте ctx (отмена, трассировка)
+func (r *OutboxRepository) WithContext(ctx context.Context) OutboxRepositoryInterface {
+	return &OutboxRepository{
+		db: r.db.WithContext(ctx),
+	}
+}
+
+// ClaimEvents Первые неопубликованные события агрегатов, время попытки которых наступило.
+// Следующее событие агрегата не выбирается, пока не опубликовано предыдущее, так сохраняется порядок.
+// Событие забирается условным обновлением по числу попыток, как доставки вебхуков в ClaimDeliveries
+func (r *OutboxRepository) ClaimEvents(now time.Time, lease time.Duration, limit int) ([]*entities.OutboxEvent, error) {
+	var due []*entities.OutboxEvent
+
+	err := r.db.
+		Where("outbox_events.published_at is null").
+		Where("outbox_events.next_attempt_at <= ?", dbTime(now)).
+		Where(`not exists (
+			select 1 from outbox_events previous
+			where previous.published_at is null
+			  and previous.aggregate_type = outbox_events.aggregate_type
+			  and previous.aggregate_id = outbox_events.aggregate_id
+			  and previous.id < outbox_events.id
+		)`).
+		Order("outbox_events.id").
+		Limit(limit).
+		Find(&due).Error
+	if err != nil {
+		return nil, err
+	}
+
+	leasedUntil := dbTime(now.Add(lease))
+	claimed := make([]*entities.OutboxEvent, 0, len(due))
+	for _, event := range due {
+		result := r.db.Model(&entities.OutboxEvent{}).
+			Where("outbox_events.id = ?", event.ID).
+			Where("outbox_events.published_at is null").
+			Where("outbox_events.attempts = ?", event.Attempts).
+			Updates(map[string]interface{}{
+				"attempts":        event.Attempts + 1,
+				"next_attempt_at": leasedUntil,
+			})
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		if result.RowsAffected == 0 {
+			continue
+		}
+
+		event.Attempts++
+		event.NextAttemptAt = leasedUntil
+		claimed = append(claimed, event)
+	}
+
+	return claimed, nil
+}
+
+// UpdateEvent Итог попытки публикации
+func (r *OutboxRepository) UpdateEvent(event *entities.OutboxEvent) error {
+	return r.db.Model(&entities.OutboxEvent{}).Where("outbox_events.id = ?", event.ID).Updates(map[string]interface{}{
+		"next_attempt_at": dbTime(event.NextAttemptAt),
+		"last_error":      event.LastError,
+		"published_at":    dbTimePtr(event.PublishedAt),
+	}).Error
+}
+
+// CountPending Число неопубликованных событий
+func (r *OutboxRepository) CountPending() (int64, error) {
+	var count int64
+
+	err := r.db.Model(&entities.OutboxEvent{}).Where("outbox_events.published_at is null").Count(&count).Error
+
+	return count, err
+}
+
+// DeletePublished Удаление опубликованных раньше before, неопубликованные не удаляются никогда
+func (r *OutboxRepository) DeletePublished(before time.Time) (int64, error) {
+	result := r.db.
+		Where("outbox_events.published_at is not null").
+		Where("outbox_events.published_at < ?", dbTime(before)).
+		Delete(&entities.OutboxEvent{})
+
+	return result.RowsAffected, result.Error
+}
+
+// createOutboxEvent Запись события в транзакции tx, которая меняет состояние агрегата
+func createOutboxEvent(
+	tx *gorm.DB,
+	aggregateType entities.AggregateType,
+	aggregateID string,
+	eventType entities.DomainEventType,
+	payload any,
+) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	now := dbTime(tx.NowFunc())
+
+	return tx.Create(&entities.OutboxEvent{
+		CreatedAt:     now,
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		EventType:     eventType,
+		Payload:       string(data),
+		NextAttemptAt: now,
+	}).Error
+}
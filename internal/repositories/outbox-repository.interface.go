@@ -0,0 +1,18 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+)
+
+// OutboxRepositoryInterface События пишут репозитории, меняющие состояние, в своих транзакциях.
+// Здесь только выборка событий для публикации и учёт её результата
+type OutboxRepositoryInterface interface {
+	WithContext(ctx context.Context) OutboxRepositoryInterface
+	ClaimEvents(now time.Time, lease time.Duration, limit int) ([]*entities.OutboxEvent, error)
+	UpdateEvent(event *entities.OutboxEvent) error
+	CountPending() (int64, error)
+	DeletePublished(before time.Time) (int64, error)
+}
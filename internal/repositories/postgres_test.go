@@ -0,0 +1,49 @@
+package repositories_test
+
+import (
+	"os"
+
+	"github.com/ShukinDmitriy/gophermart/internal/migrations"
+	"github.com/ShukinDmitriy/gophermart/internal/repositories"
+	"github.com/ShukinDmitriy/gophermart/internal/repositories/repositoriestest"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// testDatabaseEnv База для проверки репозиториев Postgres. Все таблицы в ней очищаются перед каждой спецификацией
+const testDatabaseEnv = "TEST_DATABASE_URI"
+
+var _ = Describe("Postgres storage", Ordered, func() {
+	var db *gorm.DB
+
+	BeforeAll(func() {
+		databaseURI := os.Getenv(testDatabaseEnv)
+		if databaseURI == "" {
+			Skip(testDatabaseEnv + " is not set")
+		}
+
+		migrator, err := migrations.New(migrations.DialectPostgres, databaseURI, zap.NewNop())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(migrator.Up()).To(Succeed())
+		Expect(migrator.Close()).To(Succeed())
+
+		db, err = gorm.Open(postgres.Open(databaseURI), &gorm.Config{Logger: logger.Discard})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	repositoriestest.Conformance(func() *repositories.Storage {
+		err := db.Exec(`truncate table operations, accounts, order_status_histories, accrual_attempts, orders, users,
+			reconciliation_discrepancies, reconciliation_runs, order_basket_items, order_baskets, reward_rules,
+			webhook_deliveries, webhooks, outbox_events, notification_deliveries, notifications restart identity`).Error
+		Expect(err).NotTo(HaveOccurred())
+		// служебный счёт списаний создаёт миграция
+		err = db.Exec("insert into accounts (created_at, updated_at, type) values (now(), now(), 'system_withdraw')").Error
+		Expect(err).NotTo(HaveOccurred())
+
+		return repositories.NewGormStorage(db)
+	})
+})
@@ -0,0 +1,51 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+	"gorm.io/gorm"
+)
+
+var reconciliationRepository *ReconciliationRepository
+
+type ReconciliationRepository struct {
+	db *gorm.DB
+}
+
+func NewReconciliationRepository(db *gorm.DB) *ReconciliationRepository {
+	reconciliationRepository = &ReconciliationRepository{
+		db: db,
+	}
+
+	return reconciliationRepository
+}
+
+func (r *ReconciliationRepository) Migrate(ctx context.Context) error {
+	return r.db.WithContext(ctx).AutoMigrate(&entities.ReconciliationRun{}, &entities.ReconciliationDiscrepancy{})
+}
+
+// WithContext Копия репозитория, запросы которой выполняются в контексте ctx (отмена, трассировка)
+func (r *ReconciliationRepository) WithContext(ctx context.Context) ReconciliationRepositoryInterface {
+	return &ReconciliationRepository{
+		db: r.db.WithContext(ctx),
+	}
+}
+
+func (r *ReconciliationRepository) CreateRun(run *entities.ReconciliationRun) error {
+	return r.db.Model(&entities.ReconciliationRun{}).Create(run).Error
+}
+
+func (r *ReconciliationRepository) UpdateRun(run *entities.ReconciliationRun) error {
+	return r.db.Table("reconciliation_runs").Where("reconciliation_runs.id = ?", run.ID).Updates(map[string]interface{}{
+		"status":         run.Status,
+		"orders_checked": run.OrdersChecked,
+		"discrepancies":  run.Discrepancies,
+		"corrected":      run.Corrected,
+		"finished_at":    run.FinishedAt,
+	}).Error
+}
+
+func (r *ReconciliationRepository) CreateDiscrepancy(discrepancy *entities.ReconciliationDiscrepancy) error {
+	return r.db.Model(&entities.ReconciliationDiscrepancy{}).Create(discrepancy).Error
+}
@@ -0,0 +1,14 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+)
+
+type ReconciliationRepositoryInterface interface {
+	WithContext(ctx context.Context) ReconciliationRepositoryInterface
+	CreateRun(run *entities.ReconciliationRun) error
+	UpdateRun(run *entities.ReconciliationRun) error
+	CreateDiscrepancy(discrepancy *entities.ReconciliationDiscrepancy) error
+}
@@ -0,0 +1,1070 @@
+// Package repositoriestest Общие спецификации для всех реализаций репозиториев.
+// Одни и те же проверки выполняются для Postgres, SQLite и для хранилища в памяти
+package repositoriestest
+
+import (
+	"encoding/json"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+	"github.com/ShukinDmitriy/gophermart/internal/models"
+	"github.com/ShukinDmitriy/gophermart/internal/repositories"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// Conformance Регистрирует спецификации внутри Describe набора тестов хранилища.
+// newStorage вызывается перед каждой спецификацией и должен возвращать пустое хранилище
+func Conformance(newStorage func() *repositories.Storage) {
+	var storage *repositories.Storage
+
+	BeforeEach(func() {
+		storage = newStorage()
+	})
+
+	register := func(login string) *models.UserInfoResponse {
+		user, err := storage.Users.Create(models.UserRegisterRequest{Login: login, Password: "password"})
+		Expect(err).NotTo(HaveOccurred())
+
+		return user
+	}
+
+	// createOrders Заказы по одному, чтобы время загрузки различалось: пакет получает одно время на все заказы
+	createOrders := func(userID uint, numbers ...string) {
+		for _, number := range numbers {
+			_, err := storage.Orders.Create(number, userID)
+			Expect(err).NotTo(HaveOccurred())
+		}
+	}
+
+	bonusAccount := func(userID uint) *entities.Account {
+		account, err := storage.Accounts.FindByUserID(userID, entities.AccountTypeBonus)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(account).NotTo(BeNil())
+
+		return account
+	}
+
+	Describe("Users", func() {
+		It("must create a user with free and bonus accounts", func() {
+			// Act
+			user := register("user")
+
+			// Assert
+			Expect(user.ID).NotTo(BeZero())
+			Expect(user.Login).To(Equal("user"))
+			for _, accountType := range []entities.AccountType{entities.AccountTypeFree, entities.AccountTypeBonus} {
+				account, err := storage.Accounts.FindByUserID(user.ID, accountType)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(account.Sum).To(BeZero())
+			}
+		})
+
+		It("must find a user by login and id", func() {
+			// Arrange
+			created := register("user")
+
+			// Act
+			byLogin, err := storage.Users.FindBy(models.UserSearchFilter{Login: "user"})
+			Expect(err).NotTo(HaveOccurred())
+			byID, err := storage.Users.Find(created.ID)
+			Expect(err).NotTo(HaveOccurred())
+			missing, err := storage.Users.FindBy(models.UserSearchFilter{Login: "nobody"})
+			Expect(err).NotTo(HaveOccurred())
+
+			// Assert
+			Expect(byLogin.ID).To(Equal(created.ID))
+			Expect(byLogin.Password).NotTo(Equal("password"))
+			Expect(byID).To(Equal(created))
+			Expect(missing).To(BeNil())
+		})
+
+		It("must store notification preferences on the user", func() {
+			// Arrange
+			created := register("user")
+			other := register("other")
+			preferences := entities.NotificationPreferences{Email: true, WebhookURL: "https://example.com/hook", WebhookSecret: "secret"}
+
+			// Act
+			err := storage.Users.UpdateNotificationPreferences(created.ID, "user@example.com", preferences)
+			Expect(err).NotTo(HaveOccurred())
+			user, err := storage.Users.FindBy(models.UserSearchFilter{ID: created.ID})
+			Expect(err).NotTo(HaveOccurred())
+			untouched, err := storage.Users.FindBy(models.UserSearchFilter{ID: other.ID})
+			Expect(err).NotTo(HaveOccurred())
+
+			// Assert
+			Expect(user.Email).To(Equal("user@example.com"))
+			Expect(user.Notifications).To(Equal(preferences))
+			Expect(untouched.Login).To(Equal("other"))
+			Expect(untouched.Notifications).To(BeZero())
+		})
+
+		It("must reject a taken login", func() {
+			// Arrange
+			register("user")
+
+			// Act
+			_, err := storage.Users.Create(models.UserRegisterRequest{Login: "user", Password: "other"})
+
+			// Assert
+			Expect(err).To(MatchError(repositories.ErrLoginAlreadyExists))
+		})
+	})
+
+	Describe("Accounts", func() {
+		It("must not find an account of an unknown user", func() {
+			// Act
+			account, err := storage.Accounts.FindByUserID(1000, entities.AccountTypeBonus)
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(account).To(BeNil())
+		})
+	})
+
+	Describe("Orders", func() {
+		var user *models.UserInfoResponse
+
+		BeforeEach(func() {
+			user = register("user")
+		})
+
+		It("must create an order with the first history entry", func() {
+			// Act
+			order, err := storage.Orders.Create("12345678903", user.ID)
+			Expect(err).NotTo(HaveOccurred())
+
+			// Assert
+			Expect(order.ID).NotTo(BeZero())
+			Expect(order.Status).To(Equal(entities.OrderStatusNew))
+			found, err := storage.Orders.FindByNumber("12345678903")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found.UserID).To(Equal(user.ID))
+			history, err := storage.Orders.GetStatusHistory(order.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(history).To(HaveLen(1))
+			Expect(history[0].Status).To(Equal(entities.OrderStatusNew))
+		})
+
+		It("must not find an unknown order", func() {
+			// Act
+			order, err := storage.Orders.FindByNumber("12345678903")
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(order).To(BeNil())
+		})
+
+		It("must reject a taken number", func() {
+			// Arrange
+			other := register("other")
+			_, err := storage.Orders.Create("12345678903", user.ID)
+			Expect(err).NotTo(HaveOccurred())
+
+			// Act
+			_, err = storage.Orders.Create("12345678903", other.ID)
+
+			// Assert
+			Expect(err).To(MatchError(repositories.ErrOrderAlreadyExists))
+		})
+
+		It("must let exactly one of concurrent uploads of a number win", func() {
+			// Arrange
+			const uploads = 8
+			var wg sync.WaitGroup
+			errs := make(chan error, uploads)
+
+			// Act
+			for i := 0; i < uploads; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					_, err := storage.Orders.Create("12345678903", user.ID)
+					errs <- err
+				}()
+			}
+			wg.Wait()
+			close(errs)
+
+			// Assert
+			created := 0
+			for err := range errs {
+				if err == nil {
+					created++
+					continue
+				}
+				Expect(err).To(MatchError(repositories.ErrOrderAlreadyExists))
+			}
+			Expect(created).To(Equal(1))
+		})
+
+		It("must create a batch entirely or not at all", func() {
+			// Arrange
+			_, err := storage.Orders.Create("2377225624", user.ID)
+			Expect(err).NotTo(HaveOccurred())
+
+			// Act
+			_, err = storage.Orders.CreateBatch([]string{"12345678903", "2377225624"}, user.ID)
+			Expect(err).To(MatchError(repositories.ErrOrderAlreadyExists))
+			orders, batchErr := storage.Orders.CreateBatch([]string{"12345678903", "9278923470"}, user.ID)
+
+			// Assert
+			Expect(batchErr).NotTo(HaveOccurred())
+			Expect(orders).To(HaveLen(2))
+			found, err := storage.Orders.FindByNumbers([]string{"12345678903", "9278923470", "2377225624", "79927398713"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(HaveLen(3))
+		})
+
+		It("must list orders of the user by upload time", func() {
+			// Arrange
+			other := register("other")
+			createOrders(user.ID, "12345678903", "9278923470")
+			createOrders(other.ID, "2377225624")
+
+			// Act
+			page, err := storage.Orders.GetOrdersPage(models.OrderSearchFilter{UserID: user.ID, Sort: models.SortDirectionAsc, Limit: 10})
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(page.Orders).To(HaveLen(2))
+			Expect(page.Orders[0].Number).To(Equal("12345678903"))
+			Expect(page.Orders[1].Number).To(Equal("9278923470"))
+			Expect(page.NextCursor).To(BeEmpty())
+		})
+
+		It("must page through orders with a cursor", func() {
+			// Arrange
+			createOrders(user.ID, "12345678903", "9278923470", "2377225624")
+			filter := models.OrderSearchFilter{UserID: user.ID, Sort: models.SortDirectionDesc, Limit: 2}
+
+			// Act
+			first, err := storage.Orders.GetOrdersPage(filter)
+			Expect(err).NotTo(HaveOccurred())
+			filter.Cursor, err = models.DecodeCursor(first.NextCursor)
+			Expect(err).NotTo(HaveOccurred())
+			second, err := storage.Orders.GetOrdersPage(filter)
+			Expect(err).NotTo(HaveOccurred())
+
+			// Assert
+			Expect(first.Orders).To(HaveLen(2))
+			Expect(first.Orders[0].Number).To(Equal("2377225624"))
+			Expect(first.Orders[1].Number).To(Equal("9278923470"))
+			Expect(second.Orders).To(HaveLen(1))
+			Expect(second.Orders[0].Number).To(Equal("12345678903"))
+			Expect(second.NextCursor).To(BeEmpty())
+		})
+
+		It("must filter the orders page by status", func() {
+			// Arrange
+			_, err := storage.Orders.CreateBatch([]string{"12345678903", "9278923470"}, user.ID)
+			Expect(err).NotTo(HaveOccurred())
+			err = storage.Orders.UpdateOrderByAccrualOrder(&models.AccrualOrderResponse{Order: "9278923470", Status: entities.OrderStatusInvalid})
+			Expect(err).NotTo(HaveOccurred())
+
+			// Act
+			page, err := storage.Orders.GetOrdersPage(models.OrderSearchFilter{
+				UserID:   user.ID,
+				Statuses: []entities.OrderStatus{entities.OrderStatusInvalid},
+				Sort:     models.SortDirectionAsc,
+				Limit:    10,
+			})
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(page.Orders).To(HaveLen(1))
+			Expect(page.Orders[0].Number).To(Equal("9278923470"))
+		})
+
+		It("must record every change from the accrual system in the history", func() {
+			// Arrange
+			order, err := storage.Orders.Create("12345678903", user.ID)
+			Expect(err).NotTo(HaveOccurred())
+			from := time.Now().Add(-time.Minute)
+
+			// Act
+			for _, update := range []models.AccrualOrderResponse{
+				{Order: "12345678903", Status: entities.OrderStatusProcessing},
+				{Order: "12345678903", Status: entities.OrderStatusProcessing},
+				{Order: "12345678903", Status: entities.OrderStatusProcessed, Accrual: 500},
+				{Order: "79927398713", Status: entities.OrderStatusProcessed, Accrual: 100},
+			} {
+				Expect(storage.Orders.UpdateOrderByAccrualOrder(&update)).To(Succeed())
+			}
+
+			// Assert
+			found, err := storage.Orders.FindByNumber("12345678903")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found.Status).To(Equal(entities.OrderStatusProcessed))
+			Expect(found.Accrual).To(BeNumerically("==", 500))
+			history, err := storage.Orders.GetStatusHistory(order.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(history).To(HaveLen(3))
+			Expect(history[2].Accrual).To(BeNumerically("==", 500))
+			processed, err := storage.Orders.GetProcessedOrdersByPeriod(from, time.Now().Add(time.Minute))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(processed).To(HaveLen(1))
+			Expect(processed[0].Number).To(Equal("12345678903"))
+		})
+
+		It("must accrue points with the processed status and keep a finished order unchanged", func() {
+			// Arrange
+			createOrders(user.ID, "12345678903")
+			Expect(storage.Orders.UpdateOrderByAccrualOrder(&models.AccrualOrderResponse{Order: "12345678903", Status: entities.OrderStatusProcessed, Accrual: 500})).To(Succeed())
+
+			// Act
+			late := storage.Orders.UpdateOrderByAccrualOrder(&models.AccrualOrderResponse{Order: "12345678903", Status: entities.OrderStatusProcessing})
+			repeated := storage.Orders.UpdateOrderByAccrualOrder(&models.AccrualOrderResponse{Order: "12345678903", Status: entities.OrderStatusProcessed, Accrual: 500})
+			corrected := storage.Orders.CorrectOrderAccrual("12345678903", 450)
+
+			// Assert
+			Expect(late).To(MatchError(repositories.ErrOrderFinished))
+			Expect(repeated).To(MatchError(repositories.ErrOrderFinished))
+			Expect(corrected).To(Succeed())
+			found, err := storage.Orders.FindByNumber("12345678903")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found.Status).To(Equal(entities.OrderStatusProcessed))
+			Expect(found.Accrual).To(BeNumerically("==", 450))
+			Expect(bonusAccount(user.ID).Sum).To(BeNumerically("==", 500))
+			accrual, err := storage.Operations.FindAccrualByOrderNumber("12345678903")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(accrual.Sum).To(BeNumerically("==", 500))
+		})
+
+		It("must return unfinished orders for processing and count orders by status", func() {
+			// Arrange
+			createOrders(user.ID, "12345678903", "9278923470", "2377225624")
+			Expect(storage.Orders.UpdateOrderByAccrualOrder(&models.AccrualOrderResponse{Order: "9278923470", Status: entities.OrderStatusProcessing})).To(Succeed())
+			Expect(storage.Orders.UpdateOrderByAccrualOrder(&models.AccrualOrderResponse{Order: "2377225624", Status: entities.OrderStatusInvalid})).To(Succeed())
+
+			// Act
+			orders, err := storage.Orders.GetOrdersForProcess()
+			Expect(err).NotTo(HaveOccurred())
+			counts, err := storage.Orders.CountByStatus()
+			Expect(err).NotTo(HaveOccurred())
+
+			// Assert
+			Expect(orders).To(HaveLen(2))
+			Expect(orders[0].Number).To(Equal("12345678903"))
+			Expect(orders[1].Number).To(Equal("9278923470"))
+			Expect(counts).To(Equal(map[entities.OrderStatus]int64{
+				entities.OrderStatusNew:        1,
+				entities.OrderStatusProcessing: 1,
+				entities.OrderStatusInvalid:    1,
+			}))
+		})
+
+		It("must return processing orders for the local accrual only once their basket is submitted", func() {
+			// Arrange
+			createOrders(user.ID, "12345678903", "9278923470", "2377225624")
+			for _, number := range []string{"9278923470", "2377225624"} {
+				Expect(storage.Orders.UpdateOrderByAccrualOrder(&models.AccrualOrderResponse{Order: number, Status: entities.OrderStatusProcessing})).To(Succeed())
+			}
+			Expect(storage.Rewards.CreateBasket(&entities.OrderBasket{
+				OrderNumber: "2377225624",
+				PurchasedAt: time.Now(),
+				Items:       []*entities.OrderBasketItem{{SKU: "BORK-K1", Price: 100, Quantity: 1}},
+			})).To(Succeed())
+
+			// Act
+			orders, err := storage.Orders.GetOrdersWithBasketForProcess()
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(orders).To(HaveLen(2))
+			Expect(orders[0].Number).To(Equal("12345678903"))
+			Expect(orders[1].Number).To(Equal("2377225624"))
+		})
+
+		It("must return the order's own id and user id for processing", func() {
+			// Arrange
+			// идентификаторы пользователя и заказа расходятся, иначе подмену одного другим не видно
+			register("second")
+			owner := register("owner")
+			order, err := storage.Orders.Create("12345678903", owner.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(order.ID).NotTo(Equal(owner.ID))
+
+			// Act
+			orders, err := storage.Orders.GetOrdersForProcess()
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(orders).To(HaveLen(1))
+			Expect(orders[0].ID).To(Equal(order.ID))
+			Expect(orders[0].UserID).To(Equal(owner.ID))
+			Expect(orders[0].Number).To(Equal("12345678903"))
+		})
+
+		It("must store accrual attempts only when the result changes", func() {
+			// Arrange
+			results := []entities.AccrualAttempt{
+				{StatusCode: 204, Status: entities.OrderStatusProcessing},
+				{StatusCode: 204, Status: entities.OrderStatusProcessing},
+				{StatusCode: 429, Error: "response too many request"},
+				{StatusCode: 429, Error: "response too many request"},
+				{StatusCode: 204, Status: entities.OrderStatusProcessing},
+				{StatusCode: 200, Status: entities.OrderStatusProcessed},
+			}
+
+			// Act
+			for _, result := range results {
+				attempt := result
+				attempt.OrderNumber = "12345678903"
+				Expect(storage.Orders.CreateAccrualAttempt(&attempt)).To(Succeed())
+			}
+			Expect(storage.Orders.CreateAccrualAttempt(&entities.AccrualAttempt{OrderNumber: "9278923470", StatusCode: 200})).To(Succeed())
+			attempts, err := storage.Orders.GetAccrualAttempts("12345678903", 10)
+			Expect(err).NotTo(HaveOccurred())
+			latest, err := storage.Orders.GetAccrualAttempts("12345678903", 2)
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(attempts).To(HaveLen(4))
+			Expect(attempts[0].StatusCode).To(Equal(204))
+			Expect(attempts[1].StatusCode).To(Equal(429))
+			Expect(attempts[2].StatusCode).To(Equal(204))
+			Expect(attempts[3].StatusCode).To(Equal(200))
+			Expect(latest).To(HaveLen(2))
+			Expect(latest[0].ID).To(Equal(attempts[2].ID))
+			Expect(latest[1].ID).To(Equal(attempts[3].ID))
+		})
+	})
+
+	Describe("Operations", func() {
+		var account *entities.Account
+
+		BeforeEach(func() {
+			account = bonusAccount(register("user").ID)
+		})
+
+		It("must move points between the system and the user account", func() {
+			// Act
+			Expect(storage.Operations.CreateAccrual(account.ID, "12345678903", 500)).To(Succeed())
+			Expect(storage.Operations.CreateWithdrawn(account.ID, "2377225624", 200)).To(Succeed())
+
+			// Assert
+			Expect(bonusAccount(account.UserID).Sum).To(BeNumerically("==", 300))
+			withdrawn, err := storage.Operations.GetWithdrawnByAccountID(account.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(withdrawn).To(BeNumerically("==", 200))
+			accrual, err := storage.Operations.FindAccrualByOrderNumber("12345678903")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(accrual.Sum).To(BeNumerically("==", 500))
+			Expect(accrual.RecipientAccountID).To(Equal(account.ID))
+			missing, err := storage.Operations.FindAccrualByOrderNumber("2377225624")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(missing).To(BeNil())
+		})
+
+		It("must keep balances consistent under concurrent operations", func() {
+			// Arrange
+			const operations = 10
+			var wg sync.WaitGroup
+			// списаниям хватает остатка, даже если все они пройдут раньше начислений
+			Expect(storage.Operations.CreateAccrual(account.ID, "79927398713", operations*5)).To(Succeed())
+
+			// Act
+			for i := 0; i < operations; i++ {
+				wg.Add(2)
+				go func() {
+					defer wg.Done()
+					Expect(storage.Operations.CreateAccrual(account.ID, "1234567890"+strconv.Itoa(i), 10)).To(Succeed())
+				}()
+				go func() {
+					defer wg.Done()
+					Expect(storage.Operations.CreateWithdrawn(account.ID, "2377225624", 5)).To(Succeed())
+				}()
+			}
+			wg.Wait()
+
+			// Assert
+			Expect(bonusAccount(account.UserID).Sum).To(BeNumerically("==", operations*10))
+		})
+
+		It("must reject a withdrawal exceeding the balance", func() {
+			// Arrange
+			Expect(storage.Operations.CreateAccrual(account.ID, "12345678903", 100.1)).To(Succeed())
+
+			// Act
+			exceeding := storage.Operations.CreateWithdrawn(account.ID, "2377225624", 100.2)
+			whole := storage.Operations.CreateWithdrawn(account.ID, "2377225624", 100.1)
+
+			// Assert
+			Expect(exceeding).To(MatchError(repositories.ErrInsufficientFunds))
+			Expect(whole).To(Succeed())
+			Expect(bonusAccount(account.UserID).Sum).To(BeNumerically("~", 0, 0.001))
+			withdrawn, err := storage.Operations.GetWithdrawnByAccountID(account.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(withdrawn).To(BeNumerically("~", 100.1, 0.001))
+		})
+
+		It("must let only one of concurrent withdrawals spend the same balance", func() {
+			// Arrange
+			const withdrawals = 10
+			Expect(storage.Operations.CreateAccrual(account.ID, "12345678903", 100)).To(Succeed())
+			var wg sync.WaitGroup
+			var mu sync.Mutex
+			var succeeded int
+			var errs []error
+
+			// Act
+			for range withdrawals {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					err := storage.Operations.CreateWithdrawn(account.ID, "2377225624", 60)
+
+					mu.Lock()
+					defer mu.Unlock()
+					if err == nil {
+						succeeded++
+					} else {
+						errs = append(errs, err)
+					}
+				}()
+			}
+			wg.Wait()
+
+			// Assert
+			Expect(succeeded).To(Equal(1))
+			for _, err := range errs {
+				Expect(err).To(MatchError(repositories.ErrInsufficientFunds))
+			}
+			Expect(bonusAccount(account.UserID).Sum).To(BeNumerically("==", 40))
+			withdrawn, err := storage.Operations.GetWithdrawnByAccountID(account.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(withdrawn).To(BeNumerically("==", 60))
+		})
+
+		It("must accrue points for an order only once", func() {
+			// Arrange
+			Expect(storage.Operations.CreateAccrual(account.ID, "12345678903", 500)).To(Succeed())
+			Expect(storage.Operations.CreateWithdrawn(account.ID, "12345678903", 100)).To(Succeed())
+
+			// Act
+			err := storage.Operations.CreateAccrual(account.ID, "12345678903", 500)
+
+			// Assert
+			Expect(err).To(MatchError(repositories.ErrAccrualAlreadyExists))
+			Expect(bonusAccount(account.UserID).Sum).To(BeNumerically("==", 400))
+		})
+
+		It("must reject an operation on an unknown account", func() {
+			// Act
+			err := storage.Operations.CreateAccrual(1000, "12345678903", 500)
+
+			// Assert
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("must list withdrawals newest first", func() {
+			// Arrange
+			Expect(storage.Operations.CreateAccrual(account.ID, "12345678903", 500)).To(Succeed())
+			Expect(storage.Operations.CreateWithdrawn(account.ID, "2377225624", 100)).To(Succeed())
+			Expect(storage.Operations.CreateWithdrawn(account.ID, "9278923470", 50)).To(Succeed())
+
+			// Act
+			withdrawals, err := storage.Operations.GetWithdrawalsByAccountID(account.ID)
+			Expect(err).NotTo(HaveOccurred())
+			page, err := storage.Operations.GetWithdrawalsPage(models.OperationSearchFilter{
+				AccountID: account.ID,
+				Sort:      models.SortDirectionAsc,
+				Limit:     1,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			// Assert
+			Expect(withdrawals).To(HaveLen(2))
+			Expect(withdrawals[0].Order).To(Equal("9278923470"))
+			Expect(withdrawals[1].Order).To(Equal("2377225624"))
+			Expect(page.Withdrawals).To(HaveLen(1))
+			Expect(page.Withdrawals[0].Order).To(Equal("2377225624"))
+			Expect(page.NextCursor).NotTo(BeEmpty())
+		})
+
+		It("must compute the running balance before filters", func() {
+			// Arrange
+			Expect(storage.Operations.CreateAccrual(account.ID, "12345678903", 500)).To(Succeed())
+			Expect(storage.Operations.CreateWithdrawn(account.ID, "2377225624", 100)).To(Succeed())
+			Expect(storage.Operations.CreateAccrual(account.ID, "9278923470", 50)).To(Succeed())
+
+			// Act
+			page, err := storage.Operations.GetOperationsPage(models.OperationSearchFilter{
+				AccountID: account.ID,
+				Types:     []entities.OperationType{entities.OperationTypeAccrual},
+				Sort:      models.SortDirectionDesc,
+				Limit:     10,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			byOrder, err := storage.Operations.GetOperationsByOrderNumber("2377225624")
+			Expect(err).NotTo(HaveOccurred())
+
+			// Assert
+			Expect(page.Operations).To(HaveLen(2))
+			Expect(page.Operations[0].Order).To(Equal("9278923470"))
+			Expect(page.Operations[0].Balance).To(BeNumerically("==", 450))
+			Expect(page.Operations[1].Balance).To(BeNumerically("==", 500))
+			Expect(byOrder).To(HaveLen(1))
+			Expect(byOrder[0].Type).To(Equal(entities.OperationTypeWithdraw))
+		})
+	})
+
+	Describe("Reconciliations", func() {
+		It("must store a run with its discrepancies", func() {
+			// Arrange
+			run := &entities.ReconciliationRun{PeriodFrom: time.Now().Add(-24 * time.Hour), PeriodTo: time.Now()}
+
+			// Act
+			Expect(storage.Reconciliations.CreateRun(run)).To(Succeed())
+			err := storage.Reconciliations.CreateDiscrepancy(&entities.ReconciliationDiscrepancy{
+				RunID:       run.ID,
+				OrderNumber: "12345678903",
+				Type:        entities.ReconciliationDiscrepancyTypeOperationMissing,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			run.Discrepancies = 1
+			run.Status = entities.ReconciliationRunStatusCompleted
+			Expect(storage.Reconciliations.UpdateRun(run)).To(Succeed())
+
+			// Assert
+			Expect(run.ID).NotTo(BeZero())
+		})
+	})
+
+	Describe("Rewards", func() {
+		It("must create, update and delete rules and list them by priority", func() {
+			// Arrange
+			orderCap := float32(500)
+			low := &entities.RewardRule{Name: "low", Mechanic: entities.RewardMechanicPoints, Value: 5, Active: true}
+			high := &entities.RewardRule{
+				Name:       "high",
+				Mechanic:   entities.RewardMechanicPercent,
+				Value:      10,
+				SKUPattern: "BORK-*",
+				OrderCap:   &orderCap,
+				Priority:   10,
+				Active:     true,
+			}
+			removed := &entities.RewardRule{Name: "removed", Mechanic: entities.RewardMechanicPoints, Value: 1, Active: true}
+
+			// Act
+			Expect(storage.Rewards.CreateRule(low)).To(Succeed())
+			Expect(storage.Rewards.CreateRule(high)).To(Succeed())
+			Expect(storage.Rewards.CreateRule(removed)).To(Succeed())
+			high.OrderCap = nil
+			high.Category = "kettles"
+			Expect(storage.Rewards.UpdateRule(high)).To(Succeed())
+			Expect(storage.Rewards.DeleteRule(removed.ID)).To(Succeed())
+			rules, err := storage.Rewards.GetRules()
+			Expect(err).NotTo(HaveOccurred())
+			found, err := storage.Rewards.FindRule(high.ID)
+			Expect(err).NotTo(HaveOccurred())
+			missing, err := storage.Rewards.FindRule(removed.ID)
+			Expect(err).NotTo(HaveOccurred())
+
+			// Assert
+			Expect(rules).To(HaveLen(2))
+			Expect(rules[0].Name).To(Equal("high"))
+			Expect(rules[1].Name).To(Equal("low"))
+			Expect(found.Category).To(Equal("kettles"))
+			Expect(found.SKUPattern).To(Equal("BORK-*"))
+			Expect(found.OrderCap).To(BeNil())
+			Expect(missing).To(BeNil())
+		})
+
+		It("must return only active rules valid at the given time", func() {
+			// Arrange
+			at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+			from, to := at.Add(-time.Hour), at.Add(time.Hour)
+			for _, rule := range []*entities.RewardRule{
+				{Name: "always", Mechanic: entities.RewardMechanicPoints, Active: true},
+				{Name: "disabled", Mechanic: entities.RewardMechanicPoints},
+				{Name: "window", Mechanic: entities.RewardMechanicPoints, ValidFrom: &from, ValidTo: &to, Active: true},
+				{Name: "expired", Mechanic: entities.RewardMechanicPoints, ValidTo: &at, Active: true},
+				{Name: "future", Mechanic: entities.RewardMechanicPoints, ValidFrom: &to, Active: true},
+			} {
+				Expect(storage.Rewards.CreateRule(rule)).To(Succeed())
+			}
+
+			// Act
+			// время в другом поясе: сравнение с хранимым временем не должно от него зависеть
+			rules, err := storage.Rewards.GetActiveRules(at.In(time.FixedZone("UTC+3", 3*60*60)))
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			names := make([]string, 0, len(rules))
+			for _, rule := range rules {
+				names = append(names, rule.Name)
+			}
+			Expect(names).To(Equal([]string{"always", "window"}))
+		})
+
+		It("must store a basket with its items once per order", func() {
+			// Arrange
+			basket := &entities.OrderBasket{
+				OrderNumber: "12345678903",
+				PurchasedAt: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+				Items: []*entities.OrderBasketItem{
+					{SKU: "BORK-K1", Category: "kettles", Price: 7000, Quantity: 1},
+					{SKU: "CABLE", Price: 300, Quantity: 2},
+				},
+			}
+
+			// Act
+			Expect(storage.Rewards.CreateBasket(basket)).To(Succeed())
+			duplicate := storage.Rewards.CreateBasket(&entities.OrderBasket{
+				OrderNumber: "12345678903",
+				PurchasedAt: time.Now(),
+				Items:       []*entities.OrderBasketItem{{SKU: "CABLE", Price: 300, Quantity: 1}},
+			})
+			found, err := storage.Rewards.FindBasket("12345678903")
+			Expect(err).NotTo(HaveOccurred())
+			missing, err := storage.Rewards.FindBasket("9278923470")
+			Expect(err).NotTo(HaveOccurred())
+
+			// Assert
+			Expect(duplicate).To(MatchError(repositories.ErrBasketAlreadyExists))
+			Expect(found.PurchasedAt.Equal(basket.PurchasedAt)).To(BeTrue())
+			Expect(found.Items).To(HaveLen(2))
+			Expect(found.Items[0].SKU).To(Equal("BORK-K1"))
+			Expect(found.Items[1].Quantity).To(Equal(2))
+			Expect(missing).To(BeNil())
+		})
+	})
+
+	Describe("Webhooks", func() {
+		It("must keep webhooks of users and integrators apart", func() {
+			// Arrange
+			user, other := register("user"), register("other")
+			own := &entities.Webhook{
+				UserID: &user.ID,
+				URL:    "https://crm.example.com/hooks",
+				Secret: "secret",
+				Events: []entities.WebhookEventType{entities.WebhookEventOrderProcessed, entities.WebhookEventBalanceAccrued},
+			}
+			foreign := &entities.Webhook{UserID: &other.ID, URL: "https://other.example.com", Secret: "secret"}
+			integrator := &entities.Webhook{URL: "https://push.example.com", Secret: "secret", Events: []entities.WebhookEventType{entities.WebhookEventBalanceWithdrawn}}
+			removed := &entities.Webhook{UserID: &user.ID, URL: "https://removed.example.com", Secret: "secret"}
+
+			// Act
+			for _, webhook := range []*entities.Webhook{own, foreign, integrator, removed} {
+				Expect(storage.Webhooks.CreateWebhook(webhook)).To(Succeed())
+			}
+			Expect(storage.Webhooks.DeleteWebhook(removed.ID)).To(Succeed())
+			owned, err := storage.Webhooks.GetWebhooks(&user.ID)
+			Expect(err).NotTo(HaveOccurred())
+			integrators, err := storage.Webhooks.GetWebhooks(nil)
+			Expect(err).NotTo(HaveOccurred())
+			forUser, err := storage.Webhooks.GetWebhooksForUser(user.ID)
+			Expect(err).NotTo(HaveOccurred())
+			found, err := storage.Webhooks.FindWebhook(own.ID)
+			Expect(err).NotTo(HaveOccurred())
+			missing, err := storage.Webhooks.FindWebhook(removed.ID)
+			Expect(err).NotTo(HaveOccurred())
+
+			// Assert
+			Expect(owned).To(HaveLen(1))
+			Expect(owned[0].ID).To(Equal(own.ID))
+			Expect(integrators).To(HaveLen(1))
+			Expect(integrators[0].UserID).To(BeNil())
+			Expect(forUser).To(HaveLen(2))
+			Expect(forUser[0].ID).To(Equal(own.ID))
+			Expect(forUser[1].ID).To(Equal(integrator.ID))
+			Expect(*found.UserID).To(Equal(user.ID))
+			Expect(found.Secret).To(Equal("secret"))
+			Expect(found.Events).To(Equal(own.Events))
+			Expect(missing).To(BeNil())
+		})
+
+		It("must claim each due delivery once and keep the delivery log", func() {
+			// Arrange
+			now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+			webhook := &entities.Webhook{URL: "https://push.example.com", Secret: "secret"}
+			Expect(storage.Webhooks.CreateWebhook(webhook)).To(Succeed())
+			delivery := func(eventID string, nextAttemptAt time.Time) *entities.WebhookDelivery {
+				return &entities.WebhookDelivery{
+					WebhookID:     webhook.ID,
+					EventID:       eventID,
+					EventType:     entities.WebhookEventBalanceAccrued,
+					Payload:       `{"id":"` + eventID + `"}`,
+					Status:        entities.WebhookDeliveryPending,
+					NextAttemptAt: nextAttemptAt,
+				}
+			}
+			Expect(storage.Webhooks.CreateDeliveries([]*entities.WebhookDelivery{
+				delivery("due", now.Add(-time.Minute)),
+				delivery("later", now.Add(time.Hour)),
+			})).To(Succeed())
+
+			// Act
+			// время в другом поясе: сравнение с хранимым временем не должно от него зависеть
+			claimed, err := storage.Webhooks.ClaimDeliveries(now.In(time.FixedZone("UTC+3", 3*60*60)), time.Minute, 10)
+			Expect(err).NotTo(HaveOccurred())
+			claimedAgain, err := storage.Webhooks.ClaimDeliveries(now, time.Minute, 10)
+			Expect(err).NotTo(HaveOccurred())
+			deliveredAt := now.Add(time.Second)
+			claimed[0].Status = entities.WebhookDeliveryDelivered
+			claimed[0].LastStatusCode = 204
+			claimed[0].DeliveredAt = &deliveredAt
+			Expect(storage.Webhooks.UpdateDelivery(claimed[0])).To(Succeed())
+			log, err := storage.Webhooks.GetDeliveries(webhook.ID, nil, 10)
+			Expect(err).NotTo(HaveOccurred())
+			pending, err := storage.Webhooks.GetDeliveries(webhook.ID, []entities.WebhookDeliveryStatus{entities.WebhookDeliveryPending}, 10)
+			Expect(err).NotTo(HaveOccurred())
+			reclaimed, err := storage.Webhooks.ClaimDeliveries(now.Add(2*time.Hour), time.Minute, 10)
+			Expect(err).NotTo(HaveOccurred())
+
+			// Assert
+			Expect(claimed).To(HaveLen(1))
+			Expect(claimed[0].EventID).To(Equal("due"))
+			Expect(claimed[0].Attempts).To(Equal(1))
+			Expect(claimedAgain).To(BeEmpty())
+			Expect(log).To(HaveLen(2))
+			Expect(log[0].EventID).To(Equal("later"))
+			Expect(log[1].Status).To(Equal(entities.WebhookDeliveryDelivered))
+			Expect(log[1].Attempts).To(Equal(1))
+			Expect(log[1].LastStatusCode).To(Equal(204))
+			Expect(log[1].DeliveredAt.Equal(deliveredAt)).To(BeTrue())
+			Expect(pending).To(HaveLen(1))
+			Expect(reclaimed).To(HaveLen(1))
+			Expect(reclaimed[0].EventID).To(Equal("later"))
+		})
+	})
+
+	Describe("Outbox", func() {
+		// publishAll Публикует события так же, как relay, и возвращает их в порядке публикации
+		publishAll := func(now time.Time) [][]*entities.OutboxEvent {
+			var passes [][]*entities.OutboxEvent
+			for {
+				claimed, err := storage.Outbox.ClaimEvents(now, time.Minute, 100)
+				Expect(err).NotTo(HaveOccurred())
+				if len(claimed) == 0 {
+					return passes
+				}
+				for _, event := range claimed {
+					publishedAt := now
+					event.PublishedAt = &publishedAt
+					Expect(storage.Outbox.UpdateEvent(event)).To(Succeed())
+				}
+				passes = append(passes, claimed)
+			}
+		}
+
+		It("must write an event with every state change and publish each aggregate in order", func() {
+			// Arrange
+			user := register("user")
+			_, err := storage.Users.Create(models.UserRegisterRequest{Login: "user", Password: "password"})
+			Expect(err).To(HaveOccurred())
+			createOrders(user.ID, "12345678903")
+			_, err = storage.Orders.CreateBatch([]string{"9278923470"}, user.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(storage.Orders.UpdateOrderByAccrualOrder(&models.AccrualOrderResponse{Order: "12345678903", Status: entities.OrderStatusProcessing})).To(Succeed())
+			Expect(storage.Orders.UpdateOrderByAccrualOrder(&models.AccrualOrderResponse{Order: "12345678903", Status: entities.OrderStatusProcessing})).To(Succeed())
+			Expect(storage.Orders.UpdateOrderByAccrualOrder(&models.AccrualOrderResponse{Order: "12345678903", Status: entities.OrderStatusProcessed, Accrual: 500})).To(Succeed())
+			account := bonusAccount(user.ID)
+			Expect(storage.Operations.CreateWithdrawn(account.ID, "2377225624", 100)).To(Succeed())
+			pending, err := storage.Outbox.CountPending()
+			Expect(err).NotTo(HaveOccurred())
+
+			// Act
+			passes := publishAll(time.Now().Add(time.Minute))
+
+			// Assert
+			Expect(pending).To(BeEquivalentTo(7))
+			byAggregate := map[string][]entities.DomainEventType{}
+			for _, pass := range passes {
+				seen := map[string]bool{}
+				for _, event := range pass {
+					key := string(event.AggregateType) + ":" + event.AggregateID
+					Expect(seen).NotTo(HaveKey(key), "one event of an aggregate per pass")
+					seen[key] = true
+					byAggregate[key] = append(byAggregate[key], event.EventType)
+				}
+			}
+			Expect(passes[0]).To(HaveLen(4))
+			Expect(byAggregate).To(Equal(map[string][]entities.DomainEventType{
+				"user:" + strconv.FormatUint(uint64(user.ID), 10): {entities.DomainEventUserRegistered},
+				"order:12345678903": {
+					entities.DomainEventOrderUploaded,
+					entities.DomainEventOrderStatusChanged,
+					entities.DomainEventOrderStatusChanged,
+				},
+				"order:9278923470": {entities.DomainEventOrderUploaded},
+				"account:" + strconv.FormatUint(uint64(account.ID), 10): {
+					entities.DomainEventPointsAccrued,
+					entities.DomainEventPointsWithdrawn,
+				},
+			}))
+			var processed models.OrderStatusChangedEvent
+			Expect(json.Unmarshal([]byte(passes[2][0].Payload), &processed)).To(Succeed())
+			Expect(processed).To(Equal(models.OrderStatusChangedEvent{
+				Order:          "12345678903",
+				UserID:         user.ID,
+				Status:         entities.OrderStatusProcessed,
+				PreviousStatus: entities.OrderStatusProcessing,
+				Accrual:        500,
+			}))
+			var withdrawn models.PointsEvent
+			Expect(json.Unmarshal([]byte(passes[1][1].Payload), &withdrawn)).To(Succeed())
+			Expect(withdrawn).To(Equal(models.PointsEvent{AccountID: account.ID, UserID: user.ID, Order: "2377225624", Sum: 100}))
+		})
+
+		It("must hold back the next event of an aggregate until the previous one is published", func() {
+			// Arrange
+			user := register("user")
+			publishAll(time.Now().Add(time.Minute))
+			createOrders(user.ID, "12345678903")
+			Expect(storage.Orders.UpdateOrderByAccrualOrder(&models.AccrualOrderResponse{Order: "12345678903", Status: entities.OrderStatusInvalid})).To(Succeed())
+			now := time.Now().Add(time.Minute)
+
+			// Act
+			claimed, err := storage.Outbox.ClaimEvents(now, time.Minute, 10)
+			Expect(err).NotTo(HaveOccurred())
+			leased, err := storage.Outbox.ClaimEvents(now, time.Minute, 10)
+			Expect(err).NotTo(HaveOccurred())
+			claimed[0].NextAttemptAt = now.Add(time.Second)
+			claimed[0].LastError = "sink unavailable"
+			Expect(storage.Outbox.UpdateEvent(claimed[0])).To(Succeed())
+			retried, err := storage.Outbox.ClaimEvents(now.Add(time.Second), time.Minute, 10)
+			Expect(err).NotTo(HaveOccurred())
+			passes := publishAll(now.Add(time.Second))
+			pending, err := storage.Outbox.CountPending()
+			Expect(err).NotTo(HaveOccurred())
+			deleted, err := storage.Outbox.DeletePublished(now.Add(time.Hour))
+			Expect(err).NotTo(HaveOccurred())
+
+			// Assert
+			Expect(claimed).To(HaveLen(1))
+			Expect(claimed[0].EventType).To(Equal(entities.DomainEventOrderUploaded))
+			Expect(claimed[0].Attempts).To(Equal(1))
+			Expect(leased).To(BeEmpty())
+			Expect(retried).To(HaveLen(1))
+			Expect(retried[0].ID).To(Equal(claimed[0].ID))
+			Expect(retried[0].Attempts).To(Equal(2))
+			Expect(retried[0].LastError).To(Equal("sink unavailable"))
+			// повтор ещё не опубликован и держит аренду, статус заказа ждёт его
+			Expect(passes).To(BeEmpty())
+			Expect(pending).To(BeEquivalentTo(2))
+			Expect(deleted).To(BeEquivalentTo(1))
+		})
+	})
+
+	Describe("Notifications", func() {
+		notify := func(userID uint, order string, channels ...entities.NotificationChannel) *entities.Notification {
+			notification := &entities.Notification{
+				UserID:      userID,
+				Type:        entities.NotificationOrderProcessed,
+				OrderNumber: order,
+				Subject:     "Order " + order,
+				Body:        "Processed",
+			}
+			deliveries := make([]*entities.NotificationDelivery, 0, len(channels))
+			for _, channel := range channels {
+				deliveries = append(deliveries, &entities.NotificationDelivery{
+					Channel:       channel,
+					Status:        entities.NotificationDeliveryPending,
+					NextAttemptAt: time.Now(),
+				})
+			}
+			Expect(storage.Notifications.CreateNotification(notification, deliveries)).To(Succeed())
+
+			return notification
+		}
+
+		It("must list notifications of the user newest first and mark them read", func() {
+			// Arrange
+			user := register("user")
+			other := register("other")
+			first := notify(user.ID, "12345678903")
+			second := notify(user.ID, "9278923470")
+			third := notify(user.ID, "2377225624")
+			foreign := notify(other.ID, "12345678903")
+			readAt := time.Now().Add(-time.Minute).Truncate(time.Second)
+
+			// Act
+			markedOne, err := storage.Notifications.MarkRead(user.ID, []uint{second.ID, foreign.ID}, readAt)
+			Expect(err).NotTo(HaveOccurred())
+			unread, err := storage.Notifications.GetNotifications(user.ID, true, 10)
+			Expect(err).NotTo(HaveOccurred())
+			limited, err := storage.Notifications.GetNotifications(user.ID, false, 2)
+			Expect(err).NotTo(HaveOccurred())
+			markedAll, err := storage.Notifications.MarkRead(user.ID, nil, time.Now())
+			Expect(err).NotTo(HaveOccurred())
+			unreadCount, err := storage.Notifications.CountUnread(user.ID)
+			Expect(err).NotTo(HaveOccurred())
+			otherUnread, err := storage.Notifications.CountUnread(other.ID)
+			Expect(err).NotTo(HaveOccurred())
+			found, err := storage.Notifications.FindNotification(second.ID)
+			Expect(err).NotTo(HaveOccurred())
+			missing, err := storage.Notifications.FindNotification(100)
+			Expect(err).NotTo(HaveOccurred())
+
+			// Assert
+			Expect(markedOne).To(BeEquivalentTo(1))
+			Expect(unread).To(HaveLen(2))
+			Expect(unread[0].ID).To(Equal(third.ID))
+			Expect(unread[1].ID).To(Equal(first.ID))
+			Expect(limited).To(HaveLen(2))
+			Expect(limited[1].ID).To(Equal(second.ID))
+			Expect(limited[1].ReadAt).NotTo(BeNil())
+			Expect(markedAll).To(BeEquivalentTo(2))
+			Expect(unreadCount).To(BeZero())
+			Expect(otherUnread).To(BeEquivalentTo(1))
+			// повторная отметка не меняет время прочтения
+			Expect(found.ReadAt.Equal(readAt)).To(BeTrue())
+			Expect(found.Subject).To(Equal("Order 9278923470"))
+			Expect(missing).To(BeNil())
+		})
+
+		It("must claim each due delivery once", func() {
+			// Arrange
+			user := register("user")
+			notification := notify(user.ID, "12345678903", entities.NotificationChannelEmail, entities.NotificationChannelWebhook)
+			notify(user.ID, "9278923470")
+			now := time.Now().Add(time.Second)
+
+			// Act
+			claimed, err := storage.Notifications.ClaimDeliveries(now, time.Minute, 10)
+			Expect(err).NotTo(HaveOccurred())
+			leased, err := storage.Notifications.ClaimDeliveries(now, time.Minute, 10)
+			Expect(err).NotTo(HaveOccurred())
+			deliveredAt := now
+			claimed[0].Status = entities.NotificationDeliveryDelivered
+			claimed[0].DeliveredAt = &deliveredAt
+			Expect(storage.Notifications.UpdateDelivery(claimed[0])).To(Succeed())
+			claimed[1].NextAttemptAt = now.Add(time.Second)
+			claimed[1].LastError = "smtp unavailable"
+			Expect(storage.Notifications.UpdateDelivery(claimed[1])).To(Succeed())
+			retried, err := storage.Notifications.ClaimDeliveries(now.Add(time.Second), time.Minute, 10)
+			Expect(err).NotTo(HaveOccurred())
+
+			// Assert
+			Expect(claimed).To(HaveLen(2))
+			Expect(claimed[0].NotificationID).To(Equal(notification.ID))
+			Expect([]entities.NotificationChannel{claimed[0].Channel, claimed[1].Channel}).To(ConsistOf(
+				entities.NotificationChannelEmail,
+				entities.NotificationChannelWebhook,
+			))
+			Expect(claimed[0].Attempts).To(Equal(1))
+			Expect(leased).To(BeEmpty())
+			Expect(retried).To(HaveLen(1))
+			Expect(retried[0].ID).To(Equal(claimed[1].ID))
+			Expect(retried[0].Attempts).To(Equal(2))
+			Expect(retried[0].LastError).To(Equal("smtp unavailable"))
+		})
+	})
+
+	Describe("Health", func() {
+		It("must be reachable", func() {
+			// Act
+			err := storage.Health.Ping()
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+}
@@ -0,0 +1,132 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+	"gorm.io/gorm"
+)
+
+var rewardRepository *RewardRepository
+
+type RewardRepository struct {
+	db *gorm.DB
+}
+
+func NewRewardRepository(db *gorm.DB) *RewardRepository {
+	rewardRepository = &RewardRepository{
+		db: db,
+	}
+
+	return rewardRepository
+}
+
+// WithContext Копия репозитория, запросы которой выполняются в контексте ctx (отмена, трассировка)
+func (r *RewardRepository) WithContext(ctx context.Context) RewardRepositoryInterface {
+	return &RewardRepository{
+		db: r.db.WithContext(ctx),
+	}
+}
+
+func (r *RewardRepository) CreateRule(rule *entities.RewardRule) error {
+	rule.ValidFrom, rule.ValidTo = dbTimePtr(rule.ValidFrom), dbTimePtr(rule.ValidTo)
+
+	return r.db.Model(&entities.RewardRule{}).Create(rule).Error
+}
+
+// UpdateRule Все изменяемые поля, в том числе пустые границы периода и лимит
+func (r *RewardRepository) UpdateRule(rule *entities.RewardRule) error {
+	return r.db.Model(&entities.RewardRule{}).Where("reward_rules.id = ?", rule.ID).Updates(map[string]interface{}{
+		"name":        rule.Name,
+		"mechanic":    rule.Mechanic,
+		"value":       rule.Value,
+		"sku_pattern": rule.SKUPattern,
+		"category":    rule.Category,
+		"valid_from":  dbTimePtr(rule.ValidFrom),
+		"valid_to":    dbTimePtr(rule.ValidTo),
+		"order_cap":   rule.OrderCap,
+		"priority":    rule.Priority,
+		"active":      rule.Active,
+	}).Error
+}
+
+func (r *RewardRepository) DeleteRule(id uint) error {
+	return r.db.Delete(&entities.RewardRule{}, id).Error
+}
+
+func (r *RewardRepository) FindRule(id uint) (*entities.RewardRule, error) {
+	rule := &entities.RewardRule{}
+
+	if err := r.db.Where("reward_rules.id = ?", id).First(rule).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return rule, nil
+}
+
+// GetRules Все правила в порядке применения: по убыванию приоритета, затем по порядку создания
+func (r *RewardRepository) GetRules() ([]*entities.RewardRule, error) {
+	var rules []*entities.RewardRule
+
+	err := r.db.Order("reward_rules.priority desc, reward_rules.id").Find(&rules).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+// GetActiveRules Включённые правила, период действия которых содержит at
+func (r *RewardRepository) GetActiveRules(at time.Time) ([]*entities.RewardRule, error) {
+	var rules []*entities.RewardRule
+
+	err := r.db.
+		Where("reward_rules.active = ?", true).
+		Where("reward_rules.valid_from is null or reward_rules.valid_from <= ?", dbTime(at)).
+		Where("reward_rules.valid_to is null or reward_rules.valid_to > ?", dbTime(at)).
+		Order("reward_rules.priority desc, reward_rules.id").
+		Find(&rules).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+// CreateBasket Корзина вместе с позициями одной транзакцией
+func (r *RewardRepository) CreateBasket(basket *entities.OrderBasket) error {
+	basket.PurchasedAt = dbTime(basket.PurchasedAt)
+
+	err := r.db.Model(&entities.OrderBasket{}).Create(basket).Error
+	if uniqueViolation(err, constraintBasketsOrder) {
+		return ErrBasketAlreadyExists
+	}
+
+	return err
+}
+
+func (r *RewardRepository) FindBasket(orderNumber string) (*entities.OrderBasket, error) {
+	basket := &entities.OrderBasket{}
+
+	err := r.db.
+		Preload("Items", func(db *gorm.DB) *gorm.DB {
+			return db.Order("order_basket_items.id")
+		}).
+		Where("order_baskets.order_number = ?", orderNumber).
+		First(basket).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return basket, nil
+}
@@ -0,0 +1,20 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+)
+
+type RewardRepositoryInterface interface {
+	WithContext(ctx context.Context) RewardRepositoryInterface
+	CreateRule(rule *entities.RewardRule) error
+	UpdateRule(rule *entities.RewardRule) error
+	DeleteRule(id uint) error
+	FindRule(id uint) (*entities.RewardRule, error)
+	GetRules() ([]*entities.RewardRule, error)
+	GetActiveRules(at time.Time) ([]*entities.RewardRule, error)
+	CreateBasket(basket *entities.OrderBasket) error
+	FindBasket(orderNumber string) (*entities.OrderBasket, error)
+}
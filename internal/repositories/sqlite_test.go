@@ -0,0 +1,35 @@
+package repositories_test
+
+import (
+	"path/filepath"
+
+	"github.com/ShukinDmitriy/gophermart/internal/migrations"
+	"github.com/ShukinDmitriy/gophermart/internal/repositories"
+	"github.com/ShukinDmitriy/gophermart/internal/repositories/repositoriestest"
+	"github.com/ShukinDmitriy/gophermart/internal/sqlite"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+var _ = Describe("SQLite storage", func() {
+	// Каждая спецификация получает новый файл базы, схема создаётся миграциями
+	repositoriestest.Conformance(func() *repositories.Storage {
+		path := filepath.Join(GinkgoT().TempDir(), "gophermart.db")
+
+		migrator, err := migrations.New(migrations.DialectSQLite, path, zap.NewNop())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(migrator.Up()).To(Succeed())
+		Expect(migrator.Close()).To(Succeed())
+
+		db, err := sqlite.Open(path, &gorm.Config{Logger: logger.Discard})
+		Expect(err).NotTo(HaveOccurred())
+		sqlDB, err := db.DB()
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(sqlDB.Close)
+
+		return repositories.NewGormStorage(db)
+	})
+})
@@ -0,0 +1,54 @@
+package repositories
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Storage Репозитории одного хранилища. Сервисы зависят только от интерфейсов,
+// поэтому хранилище выбирается при запуске (Postgres, SQLite или память, см. пакет memory)
+type Storage struct {
+	Users           UserRepositoryInterface
+	Accounts        AccountRepositoryInterface
+	Operations      OperationRepositoryInterface
+	Orders          OrderRepositoryInterface
+	Reconciliations ReconciliationRepositoryInterface
+	Rewards         RewardRepositoryInterface
+	Webhooks        WebhookRepositoryInterface
+	Outbox          OutboxRepositoryInterface
+	Notifications   NotificationRepositoryInterface
+	Health          HealthRepositoryInterface
+}
+
+// NewGormStorage Репозитории поверх GORM. Запросы общие для Postgres и SQLite,
+// схемы обоих диалектов описаны миграциями в db/migrations
+func NewGormStorage(db *gorm.DB) *Storage {
+	return &Storage{
+		Users:           NewUserRepository(db),
+		Accounts:        NewAccountRepository(db),
+		Operations:      NewOperationRepository(db),
+		Orders:          NewOrderRepository(db),
+		Reconciliations: NewReconciliationRepository(db),
+		Rewards:         NewRewardRepository(db),
+		Webhooks:        NewWebhookRepository(db),
+		Outbox:          NewOutboxRepository(db),
+		Notifications:   NewNotificationRepository(db),
+		Health:          NewHealthRepository(db),
+	}
+}
+
+// dbTime Время для условия запроса. SQLite хранит время строкой и сравнивает строки,
+// поэтому время в базе и в условиях всегда в UTC
+func dbTime(t time.Time) time.Time {
+	return t.UTC()
+}
+
+func dbTimePtr(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	utc := dbTime(*t)
+
+	return &utc
+}
@@ -3,6 +3,7 @@ package repositories
 import (
 	"context"
 	"errors"
+	"strconv"
 
 	"github.com/ShukinDmitriy/gophermart/internal/entities"
 	"github.com/ShukinDmitriy/gophermart/internal/models"
@@ -40,30 +41,36 @@ func (r *UserRepository) Create(userRegister models.UserRegisterRequest) (*model
 		Password: string(passwordHash),
 	}
 
-	tx := r.db.Begin()
+	// Счета создаются в той же транзакции: внешний ключ accounts.user_id не видит незафиксированного пользователя
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&entities.User{}).
+			Create(&user).Error
+		if err != nil {
+			return err
+		}
 
-	query := tx.Model(&entities.User{}).
-		Create(&user)
-	err = query.Error
-	if err != nil {
-		tx.Rollback()
-		return nil, err
-	}
+		accounts := &AccountRepository{db: tx}
+		if _, err = accounts.Create(user.ID, entities.AccountTypeFree); err != nil {
+			return err
+		}
 
-	_, err = accountRepository.Create(user.ID, entities.AccountTypeFree)
-	if err != nil {
-		tx.Rollback()
-		return nil, err
-	}
+		if _, err = accounts.Create(user.ID, entities.AccountTypeBonus); err != nil {
+			return err
+		}
 
-	_, err = accountRepository.Create(user.ID, entities.AccountTypeBonus)
+		userID := strconv.FormatUint(uint64(user.ID), 10)
+		return createOutboxEvent(tx, entities.AggregateUser, userID, entities.DomainEventUserRegistered, models.UserRegisteredEvent{
+			UserID: user.ID,
+			Login:  user.Login,
+		})
+	})
+	if uniqueViolation(err, constraintUsersLogin) {
+		return nil, ErrLoginAlreadyExists
+	}
 	if err != nil {
-		tx.Rollback()
 		return nil, err
 	}
 
-	tx.Commit()
-
 	return &models.UserInfoResponse{
 		ID:         user.ID,
 		LastName:   user.LastName,
@@ -100,6 +107,9 @@ func (r *UserRepository) FindBy(filter models.UserSearchFilter) (*entities.User,
 
 	query := r.db
 
+	if filter.ID != 0 {
+		query = query.Where("\"users\".\"id\" = ?", filter.ID)
+	}
 	if filter.Login != "" {
 		query = query.Where("\"users\".\"login\" = ?", filter.Login)
 	}
@@ -115,6 +125,16 @@ func (r *UserRepository) FindBy(filter models.UserSearchFilter) (*entities.User,
 	return user, nil
 }
 
+// UpdateNotificationPreferences Адрес почты и каналы уведомлений пользователя
+func (r *UserRepository) UpdateNotificationPreferences(userID uint, email string, preferences entities.NotificationPreferences) error {
+	return r.db.Model(&entities.User{}).Where("users.id = ?", userID).Updates(map[string]interface{}{
+		"email":                 email,
+		"notify_email":          preferences.Email,
+		"notify_webhook_url":    preferences.WebhookURL,
+		"notify_webhook_secret": preferences.WebhookSecret,
+	}).Error
+}
+
 func (r *UserRepository) GeneratePasswordHash(password string) ([]byte, error) {
 	return bcrypt.GenerateFromPassword([]byte(password), 8)
 }
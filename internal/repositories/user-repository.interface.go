@@ -9,5 +9,6 @@ type UserRepositoryInterface interface {
 	Create(userRegister models.UserRegisterRequest) (*models.UserInfoResponse, error)
 	Find(id uint) (*models.UserInfoResponse, error)
 	FindBy(filter models.UserSearchFilter) (*entities.User, error)
+	UpdateNotificationPreferences(userID uint, email string, preferences entities.NotificationPreferences) error
 	GeneratePasswordHash(password string) ([]byte, error)
 }
@@ -0,0 +1,175 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+	"gorm.io/gorm"
+)
+
+var webhookRepository *WebhookRepository
+
+type WebhookRepository struct {
+	db *gorm.DB
+}
+
+func NewWebhookRepository(db *gorm.DB) *WebhookRepository {
+	webhookRepository = &WebhookRepository{
+		db: db,
+	}
+
+	return webhookRepository
+}
+
+// WithContext Копия репозитория, запросы которой выполняются в контексте ctx (отмена, трассировка)
+func (r *WebhookRepository) WithContext(ctx context.Context) WebhookRepositoryInterface {
+	return &WebhookRepository{
+		db: r.db.WithContext(ctx),
+	}
+}
+
+func (r *WebhookRepository) CreateWebhook(webhook *entities.Webhook) error {
+	// без подписок в колонку пишется пустой список, а не null
+	if webhook.Events == nil {
+		webhook.Events = []entities.WebhookEventType{}
+	}
+
+	return r.db.Model(&entities.Webhook{}).Create(webhook).Error
+}
+
+// DeleteWebhook Мягкое удаление: журнал доставок удалённого вебхука остаётся
+func (r *WebhookRepository) DeleteWebhook(id uint) error {
+	return r.db.Delete(&entities.Webhook{}, id).Error
+}
+
+func (r *WebhookRepository) FindWebhook(id uint) (*entities.Webhook, error) {
+	webhook := &entities.Webhook{}
+
+	if err := r.db.Where("webhooks.id = ?", id).First(webhook).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return webhook, nil
+}
+
+// GetWebhooks Вебхуки владельца userID, при nil вебхуки интеграторов
+func (r *WebhookRepository) GetWebhooks(userID *uint) ([]*entities.Webhook, error) {
+	var webhooks []*entities.Webhook
+
+	query := r.db.Order("webhooks.id")
+	if userID == nil {
+		query = query.Where("webhooks.user_id is null")
+	} else {
+		query = query.Where("webhooks.user_id = ?", *userID)
+	}
+	if err := query.Find(&webhooks).Error; err != nil {
+		return nil, err
+	}
+
+	return webhooks, nil
+}
+
+// GetWebhooksForUser Вебхуки, которые получают события пользователя: его собственные и интеграторов
+func (r *WebhookRepository) GetWebhooksForUser(userID uint) ([]*entities.Webhook, error) {
+	var webhooks []*entities.Webhook
+
+	err := r.db.
+		Where("webhooks.user_id = ? or webhooks.user_id is null", userID).
+		Order("webhooks.id").
+		Find(&webhooks).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return webhooks, nil
+}
+
+func (r *WebhookRepository) CreateDeliveries(deliveries []*entities.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	for _, delivery := range deliveries {
+		delivery.NextAttemptAt = dbTime(delivery.NextAttemptAt)
+	}
+
+	return r.db.Model(&entities.WebhookDelivery{}).Create(deliveries).Error
+}
+
+// ClaimDeliveries Доставки, время попытки которых наступило. Каждая забирается условным обновлением
+// по числу попыток, поэтому при нескольких экземплярах сервиса одну попытку выполняет один из них.
+// Следующая попытка откладывается на lease: если экземпляр упадёт, доставку заберёт другой
+func (r *WebhookRepository) ClaimDeliveries(now time.Time, lease time.Duration, limit int) ([]*entities.WebhookDelivery, error) {
+	var due []*entities.WebhookDelivery
+
+	err := r.db.
+		Where("webhook_deliveries.status = ?", entities.WebhookDeliveryPending).
+		Where("webhook_deliveries.next_attempt_at <= ?", dbTime(now)).
+		Order("webhook_deliveries.next_attempt_at, webhook_deliveries.id").
+		Limit(limit).
+		Find(&due).Error
+	if err != nil {
+		return nil, err
+	}
+
+	leasedUntil := dbTime(now.Add(lease))
+	claimed := make([]*entities.WebhookDelivery, 0, len(due))
+	for _, delivery := range due {
+		result := r.db.Model(&entities.WebhookDelivery{}).
+			Where("webhook_deliveries.id = ?", delivery.ID).
+			Where("webhook_deliveries.status = ?", entities.WebhookDeliveryPending).
+			Where("webhook_deliveries.attempts = ?", delivery.Attempts).
+			Updates(map[string]interface{}{
+				"attempts":        delivery.Attempts + 1,
+				"next_attempt_at": leasedUntil,
+			})
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		if result.RowsAffected == 0 {
+			continue
+		}
+
+		delivery.Attempts++
+		delivery.NextAttemptAt = leasedUntil
+		claimed = append(claimed, delivery)
+	}
+
+	return claimed, nil
+}
+
+// UpdateDelivery Итог попытки: статус, время следующей попытки и ответ получателя
+func (r *WebhookRepository) UpdateDelivery(delivery *entities.WebhookDelivery) error {
+	return r.db.Model(&entities.WebhookDelivery{}).Where("webhook_deliveries.id = ?", delivery.ID).Updates(map[string]interface{}{
+		"status":           delivery.Status,
+		"next_attempt_at":  dbTime(delivery.NextAttemptAt),
+		"last_status_code": delivery.LastStatusCode,
+		"last_error":       delivery.LastError,
+		"delivered_at":     dbTimePtr(delivery.DeliveredAt),
+	}).Error
+}
+
+// GetDeliveries Журнал доставок вебхука, новые первыми. Пустой statuses не фильтрует по статусу
+func (r *WebhookRepository) GetDeliveries(
+	webhookID uint,
+	statuses []entities.WebhookDeliveryStatus,
+	limit int,
+) ([]*entities.WebhookDelivery, error) {
+	var deliveries []*entities.WebhookDelivery
+
+	query := r.db.Where("webhook_deliveries.webhook_id = ?", webhookID)
+	if len(statuses) > 0 {
+		query = query.Where("webhook_deliveries.status in ?", statuses)
+	}
+	err := query.Order("webhook_deliveries.id desc").Limit(limit).Find(&deliveries).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
@@ -0,0 +1,21 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+)
+
+type WebhookRepositoryInterface interface {
+	WithContext(ctx context.Context) WebhookRepositoryInterface
+	CreateWebhook(webhook *entities.Webhook) error
+	DeleteWebhook(id uint) error
+	FindWebhook(id uint) (*entities.Webhook, error)
+	GetWebhooks(userID *uint) ([]*entities.Webhook, error)
+	GetWebhooksForUser(userID uint) ([]*entities.Webhook, error)
+	CreateDeliveries(deliveries []*entities.WebhookDelivery) error
+	ClaimDeliveries(now time.Time, lease time.Duration, limit int) ([]*entities.WebhookDelivery, error)
+	UpdateDelivery(delivery *entities.WebhookDelivery) error
+	GetDeliveries(webhookID uint, statuses []entities.WebhookDeliveryStatus, limit int) ([]*entities.WebhookDelivery, error)
+}
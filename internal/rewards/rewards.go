@@ -0,0 +1,87 @@
+// Package rewards Локальный расчёт начислений: правила вознаграждения применяются к составу заказа
+package rewards
+
+import (
+	"cmp"
+	"math"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+)
+
+// Calculate Начисление за корзину. К каждой позиции применяется одно правило: подходящее с наибольшим приоритетом,
+// при равном приоритете созданное раньше. Сумма начислений по правилу за заказ не превышает его OrderCap
+func Calculate(rules []*entities.RewardRule, basket *entities.OrderBasket) float32 {
+	ordered := slices.Clone(rules)
+	slices.SortStableFunc(ordered, func(a, b *entities.RewardRule) int {
+		if a.Priority != b.Priority {
+			return cmp.Compare(b.Priority, a.Priority)
+		}
+
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	byRule := make([]float64, len(ordered))
+	for _, item := range basket.Items {
+		for i, rule := range ordered {
+			if Matches(rule, item, basket.PurchasedAt) {
+				byRule[i] += itemReward(rule, item)
+				break
+			}
+		}
+	}
+
+	var sum float64
+	for i, rule := range ordered {
+		reward := byRule[i]
+		if rule.OrderCap != nil {
+			reward = min(reward, float64(*rule.OrderCap))
+		}
+		sum += reward
+	}
+
+	return float32(math.Round(sum*100) / 100)
+}
+
+// Matches Правило подходит к позиции заказа, купленной в момент at. SKU сравнивается с шаблоном
+// в синтаксисе path.Match, категория целиком, и то и другое без учёта регистра
+func Matches(rule *entities.RewardRule, item *entities.OrderBasketItem, at time.Time) bool {
+	if !rule.Active {
+		return false
+	}
+	if rule.ValidFrom != nil && at.Before(*rule.ValidFrom) {
+		return false
+	}
+	if rule.ValidTo != nil && !at.Before(*rule.ValidTo) {
+		return false
+	}
+	if rule.Category != "" && !strings.EqualFold(rule.Category, item.Category) {
+		return false
+	}
+	if rule.SKUPattern == "" {
+		return true
+	}
+
+	matched, err := path.Match(strings.ToLower(rule.SKUPattern), strings.ToLower(item.SKU))
+
+	return err == nil && matched
+}
+
+// ValidatePattern Проверка синтаксиса шаблона SKU
+func ValidatePattern(pattern string) error {
+	_, err := path.Match(pattern, "")
+
+	return err
+}
+
+func itemReward(rule *entities.RewardRule, item *entities.OrderBasketItem) float64 {
+	quantity := float64(item.Quantity)
+	if rule.Mechanic == entities.RewardMechanicPercent {
+		return float64(item.Price) * quantity * float64(rule.Value) / 100
+	}
+
+	return float64(rule.Value) * quantity
+}
@@ -0,0 +1,114 @@
+package rewards
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestCalculate(t *testing.T) {
+	purchasedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	cap100 := float32(100)
+	may := purchasedAt.Add(-24 * time.Hour)
+	june := purchasedAt.Add(30 * 24 * time.Hour)
+
+	tests := []struct {
+		name  string
+		rules []*entities.RewardRule
+		items []*entities.OrderBasketItem
+		want  float32
+	}{
+		{
+			name:  "no rules",
+			items: []*entities.OrderBasketItem{{SKU: "BORK-K1", Price: 7000, Quantity: 1}},
+			want:  0,
+		},
+		{
+			name: "percent of price and quantity",
+			rules: []*entities.RewardRule{
+				{Mechanic: entities.RewardMechanicPercent, Value: 10, SKUPattern: "bork-*", Active: true},
+			},
+			items: []*entities.OrderBasketItem{{SKU: "BORK-K1", Price: 7000, Quantity: 2}},
+			want:  1400,
+		},
+		{
+			name: "fixed points per unit",
+			rules: []*entities.RewardRule{
+				{Mechanic: entities.RewardMechanicPoints, Value: 50, Category: "Laptops", Active: true},
+			},
+			items: []*entities.OrderBasketItem{
+				{SKU: "ACER-1", Category: "laptops", Price: 50000, Quantity: 3},
+				{SKU: "CABLE", Category: "cables", Price: 300, Quantity: 1},
+			},
+			want: 150,
+		},
+		{
+			name: "highest priority rule wins",
+			rules: []*entities.RewardRule{
+				{Model: gorm.Model{ID: 1}, Mechanic: entities.RewardMechanicPoints, Value: 5, Active: true},
+				{Model: gorm.Model{ID: 2}, Mechanic: entities.RewardMechanicPercent, Value: 10, SKUPattern: "BORK-*", Priority: 10, Active: true},
+			},
+			items: []*entities.OrderBasketItem{
+				{SKU: "BORK-K1", Price: 7000, Quantity: 1},
+				{SKU: "CABLE", Price: 300, Quantity: 1},
+			},
+			want: 705,
+		},
+		{
+			name: "earlier rule wins a tie",
+			rules: []*entities.RewardRule{
+				{Model: gorm.Model{ID: 2}, Mechanic: entities.RewardMechanicPoints, Value: 5, Active: true},
+				{Model: gorm.Model{ID: 1}, Mechanic: entities.RewardMechanicPoints, Value: 7, Active: true},
+			},
+			items: []*entities.OrderBasketItem{{SKU: "CABLE", Price: 300, Quantity: 1}},
+			want:  7,
+		},
+		{
+			name: "cap per order",
+			rules: []*entities.RewardRule{
+				{Mechanic: entities.RewardMechanicPercent, Value: 10, OrderCap: &cap100, Active: true},
+			},
+			items: []*entities.OrderBasketItem{
+				{SKU: "BORK-K1", Price: 700, Quantity: 1},
+				{SKU: "BORK-K2", Price: 700, Quantity: 1},
+			},
+			want: 100,
+		},
+		{
+			name: "inactive and out of window rules are skipped",
+			rules: []*entities.RewardRule{
+				{Mechanic: entities.RewardMechanicPoints, Value: 1, Active: false},
+				{Mechanic: entities.RewardMechanicPoints, Value: 2, ValidFrom: &june, Active: true},
+				{Mechanic: entities.RewardMechanicPoints, Value: 3, ValidTo: &may, Active: true},
+				{Mechanic: entities.RewardMechanicPoints, Value: 4, ValidFrom: &may, ValidTo: &june, Active: true},
+			},
+			items: []*entities.OrderBasketItem{{SKU: "CABLE", Price: 300, Quantity: 1}},
+			want:  4,
+		},
+		{
+			name: "rounded to kopecks",
+			rules: []*entities.RewardRule{
+				{Mechanic: entities.RewardMechanicPercent, Value: 3.33, Active: true},
+			},
+			items: []*entities.OrderBasketItem{{SKU: "CABLE", Price: 99.99, Quantity: 1}},
+			want:  3.33,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Calculate(tt.rules, &entities.OrderBasket{PurchasedAt: purchasedAt, Items: tt.items})
+
+			assert.InDelta(t, tt.want, got, 0.001)
+		})
+	}
+}
+
+func TestValidatePattern(t *testing.T) {
+	assert.NoError(t, ValidatePattern("BORK-*"))
+	assert.NoError(t, ValidatePattern(""))
+	assert.Error(t, ValidatePattern("BORK-["))
+}
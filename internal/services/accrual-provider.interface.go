@@ -0,0 +1,14 @@
+package services
+
+import (
+	"context"
+
+	"github.com/ShukinDmitriy/gophermart/internal/models"
+)
+
+// AccrualProvider Источник расчёта начислений по заказу: внешняя система расчёта или локальные правила.
+// statusCode код ответа в терминах HTTP API системы расчёта, он сохраняется в истории обращений
+type AccrualProvider interface {
+	FetchOrder(ctx context.Context, orderNumber string) (res *models.AccrualOrderResponse, statusCode int, err error)
+	Ping(ctx context.Context) error
+}
@@ -1,163 +1,320 @@
 package services
 
 import (
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
-	"strconv"
+	"context"
+	"errors"
+	"sync/atomic"
 	"time"
 
 	"github.com/ShukinDmitriy/gophermart/internal/entities"
+	"github.com/ShukinDmitriy/gophermart/internal/logging"
+	"github.com/ShukinDmitriy/gophermart/internal/metrics"
 	"github.com/ShukinDmitriy/gophermart/internal/models"
 	"github.com/ShukinDmitriy/gophermart/internal/repositories"
-	"github.com/labstack/echo/v4"
-	"github.com/pkg/errors"
+	"github.com/ShukinDmitriy/gophermart/internal/tracing"
+	"github.com/go-playground/validator/v10"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
 )
 
+// AccrualOptions Настройки очереди расчёта. Настройки источника начислений задаются в его реализации AccrualProvider
+type AccrualOptions struct {
+	// QueueSize ёмкость очереди заказов на расчёт
+	QueueSize int
+	// RetryInterval период опроса незавершённых заказов из БД. Заказ в обработке опрашивается
+	// повторно только здесь, а не сразу после ответа PROCESSING
+	RetryInterval time.Duration
+	// WaitForBasket локальный расчёт: заказ в обработке опрашивается только после загрузки его состава
+	WaitForBasket bool
+}
+
+// accrualJob Заказ в очереди на расчёт вместе с контекстом трассировки запроса, который его поставил.
+// Обработка заказа становится дочерним спаном этого запроса
+type accrualJob struct {
+	order       entities.Order
+	spanContext trace.SpanContext
+	enqueuedAt  time.Time
+}
+
 type AccrualService struct {
-	accrualBaseURL      string
-	httpClient          *http.Client
-	orderChan           chan entities.Order
-	failedOrderChan     chan entities.Order
-	accountRepository   repositories.AccountRepositoryInterface
-	operationRepository repositories.OperationRepositoryInterface
-	orderRepository     repositories.OrderRepositoryInterface
+	options          AccrualOptions
+	provider         AccrualProvider
+	orderChan        chan accrualJob
+	orderRepository  repositories.OrderRepositoryInterface
+	orderEventBroker OrderEventBrokerInterface
+	metrics          *metrics.Metrics
+	logger           *zap.Logger
+	validate         *validator.Validate
+	runningWorkers   atomic.Int32
+	lastQueueWait    atomic.Int64
 }
 
 func NewAccrualService(
-	accrualBaseURL string,
-	accountRepository repositories.AccountRepositoryInterface,
-	operationRepository repositories.OperationRepositoryInterface,
+	options AccrualOptions,
 	orderRepository repositories.OrderRepositoryInterface,
-	httpClient *http.Client,
+	provider AccrualProvider,
+	orderEventBroker OrderEventBrokerInterface,
+	metrics *metrics.Metrics,
+	logger *zap.Logger,
 ) *AccrualService {
-	orderChan := make(chan entities.Order, 1000)
-	failedOrderChan := make(chan entities.Order, 1000)
-
 	instance := &AccrualService{
-		accrualBaseURL,
-		httpClient,
-		orderChan,
-		failedOrderChan,
-		accountRepository,
-		operationRepository,
-		orderRepository,
+		options:          options,
+		provider:         provider,
+		orderChan:        make(chan accrualJob, options.QueueSize),
+		orderRepository:  orderRepository,
+		orderEventBroker: orderEventBroker,
+		metrics:          metrics,
+		logger:           logger.Named("accrual"),
+		validate:         validator.New(validator.WithRequiredStructEnabled()),
 	}
 
 	return instance
 }
 
-func (ac *AccrualService) SendOrderToQueue(order entities.Order) {
-	ac.orderChan <- order
+// SendOrderToQueue Постановка заказа в очередь на расчёт. Из ctx берётся только контекст трассировки,
+// его отмена на обработку не влияет
+func (ac *AccrualService) SendOrderToQueue(ctx context.Context, order entities.Order) {
+	ac.orderChan <- accrualJob{
+		order:       order,
+		spanContext: trace.SpanContextFromContext(ctx),
+		enqueuedAt:  time.Now(),
+	}
+	ac.metrics.SetAccrualQueueDepth(len(ac.orderChan))
+}
+
+// TrySendOrderToQueue Постановка заказа в очередь без ожидания. Если очередь заполнена, заказ
+// остаётся в статусе NEW и его заберёт ProcessFailedOrders
+func (ac *AccrualService) TrySendOrderToQueue(ctx context.Context, order entities.Order) bool {
+	select {
+	case ac.orderChan <- accrualJob{
+		order:       order,
+		spanContext: trace.SpanContextFromContext(ctx),
+		enqueuedAt:  time.Now(),
+	}:
+		ac.metrics.SetAccrualQueueDepth(len(ac.orderChan))
+		return true
+	default:
+		return false
+	}
 }
 
-func (ac *AccrualService) ProcessOrders(e *echo.Echo) {
-	for order := range ac.orderChan {
-		ac.processOrder(e, order)
+func (ac *AccrualService) ProcessOrders() {
+	ac.runningWorkers.Add(1)
+	defer ac.runningWorkers.Add(-1)
+
+	for job := range ac.orderChan {
+		ac.metrics.SetAccrualQueueDepth(len(ac.orderChan))
+		ac.lastQueueWait.Store(int64(time.Since(job.enqueuedAt)))
+		ac.processOrder(trace.ContextWithSpanContext(context.Background(), job.spanContext), job.order)
 	}
 }
 
-func (ac *AccrualService) ProcessFailedOrders(e *echo.Echo) {
-	ticker := time.NewTicker(10 * time.Second)
+func (ac *AccrualService) ProcessFailedOrders() {
+	ac.runningWorkers.Add(1)
+	defer ac.runningWorkers.Add(-1)
+
+	ticker := time.NewTicker(ac.options.RetryInterval)
 
 	for range ticker.C {
-		orders, err := ac.orderRepository.GetOrdersForProcess()
+		orders, err := ac.ordersForProcess()
 		if err != nil {
-			e.Logger.Error(err.Error())
+			ac.logger.Error("cannot get orders for process", zap.Error(err))
 			continue
 		}
 		for _, order := range orders {
-			ac.processOrder(e, *order)
+			ac.processOrder(context.Background(), *order)
 		}
 
 	}
 }
 
-func (ac *AccrualService) processOrder(e *echo.Echo, order entities.Order) {
-	accrualOrder, err := ac.fetchOrder(e, order.Number)
+func (ac *AccrualService) ordersForProcess() ([]*entities.Order, error) {
+	if ac.options.WaitForBasket {
+		return ac.orderRepository.GetOrdersWithBasketForProcess()
+	}
+
+	return ac.orderRepository.GetOrdersForProcess()
+}
+
+// RunningWorkers Количество запущенных обработчиков: ProcessOrders и ProcessFailedOrders
+func (ac *AccrualService) RunningWorkers() int {
+	return int(ac.runningWorkers.Load())
+}
+
+// QueueStats Заполненность очереди и время ожидания последнего взятого из неё заказа
+func (ac *AccrualService) QueueStats() models.AccrualQueueStats {
+	return models.AccrualQueueStats{
+		Depth:      len(ac.orderChan),
+		Capacity:   cap(ac.orderChan),
+		LastWaitMs: time.Duration(ac.lastQueueWait.Load()).Milliseconds(),
+	}
+}
+
+// Ping Проверка доступности источника начислений
+func (ac *AccrualService) Ping(ctx context.Context) error {
+	return ac.provider.Ping(ctx)
+}
+
+// processOrder Один опрос системы расчёта по заказу. parent контекст запроса, загрузившего заказ.
+// Заказ, оставшийся в обработке, повторно опрашивает ProcessFailedOrders
+func (ac *AccrualService) processOrder(parent context.Context, order entities.Order) {
+	ctx, span := tracing.Tracer().Start(parent, "accrual.process_order",
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			attribute.String("order.number", order.Number),
+			attribute.Int("user.id", int(order.UserID)),
+		),
+	)
+	defer span.End()
+
+	log := ac.logger.With(logging.OrderNumber(order.Number), logging.UserID(order.UserID)).With(logging.TraceID(ctx)...)
+	orderRepository := ac.orderRepository.WithContext(ctx)
+
+	accrualOrder, statusCode, err := ac.provider.FetchOrder(ctx, order.Number)
+	ac.saveAccrualAttempt(log, orderRepository, order.Number, statusCode, accrualOrder, err)
 	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
 		return
 	}
+	span.SetAttributes(attribute.String("order.status", string(accrualOrder.Status)))
 
-	switch accrualOrder.Status {
-	case entities.OrderStatusNew:
-	case entities.OrderStatusProcessing:
-		err = ac.orderRepository.UpdateOrderByAccrualOrder(accrualOrder)
-		if err != nil {
-			return
-		}
-		ac.orderChan <- order
-	case entities.OrderStatusProcessed:
-		err = ac.orderRepository.UpdateOrderByAccrualOrder(accrualOrder)
-		if err != nil {
-			return
-		}
+	err = ac.applyAccrualOrder(ctx, log, accrualOrder)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
 
-		bonusAccount, err := ac.accountRepository.FindByUserID(order.UserID, entities.AccountTypeBonus)
-		if err != nil || bonusAccount == nil {
-			return
-		}
-		err = ac.operationRepository.CreateAccrual(bonusAccount.ID, accrualOrder.Order, accrualOrder.Accrual)
-		if err != nil {
-			return
-		}
-	case entities.OrderStatusInvalid:
-		_ = ac.orderRepository.UpdateOrderByAccrualOrder(accrualOrder)
-		return
+// ApplyCallback Применение результата расчёта, присланного системой расчёта. Повторная доставка того же
+// результата ничего не меняет. REGISTERED означает, что заказ принят в расчёт, и заказ не меняет
+func (ac *AccrualService) ApplyCallback(ctx context.Context, accrualOrder models.AccrualOrderResponse) error {
+	if err := ac.validate.Struct(accrualOrder); err != nil {
+		return newValidationError(err)
 	}
+
+	ctx, span := tracing.Tracer().Start(ctx, "accrual.apply_callback",
+		trace.WithAttributes(
+			attribute.String("order.number", accrualOrder.Order),
+			attribute.String("order.status", string(accrualOrder.Status)),
+		),
+	)
+	defer span.End()
+
+	log := ac.logger.With(logging.OrderNumber(accrualOrder.Order)).With(logging.TraceID(ctx)...)
+	if accrualOrder.Status == models.AccrualStatusRegistered {
+		return nil
+	}
+
+	err := ac.applyAccrualOrder(ctx, log, &accrualOrder)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	return err
 }
 
-func (ac *AccrualService) fetchOrder(e *echo.Echo, orderNumber string) (*models.AccrualOrderResponse, error) {
-	res := &models.AccrualOrderResponse{
-		Order:  orderNumber,
-		Status: entities.OrderStatusProcessing,
+// applyAccrualOrder Применение результата расчёта к заказу, общее для опроса и присланных результатов.
+// Репозиторий не меняет завершённый заказ и начисляет баллы в той же транзакции, что и смену статуса,
+// поэтому баллы за заказ начисляются один раз. Вебхуки и уведомления об исходе заказа отправляет
+// UserEventDispatcher по событиям outbox, записанным в этой транзакции
+func (ac *AccrualService) applyAccrualOrder(ctx context.Context, log *zap.Logger, accrualOrder *models.AccrualOrderResponse) error {
+	if accrualOrder.Status == entities.OrderStatusNew {
+		return nil
 	}
 
-	url := fmt.Sprintf("%s/api/orders/%s", ac.accrualBaseURL, orderNumber)
-	request, err := http.NewRequest(http.MethodGet, url, http.NoBody)
+	orderRepository := ac.orderRepository.WithContext(ctx)
+	order, err := orderRepository.FindByNumber(accrualOrder.Order)
 	if err != nil {
-		e.Logger.Error(err)
-		return res, errors.New("cannot create request: " + err.Error())
+		log.Error("cannot find order", zap.Error(err))
+		return err
+	}
+	if order == nil {
+		return ErrOrderNotFound
+	}
+	if order.Status.Final() {
+		log.Debug("order is already finished", zap.String("status", string(order.Status)))
+		return nil
 	}
 
-	response, err := ac.httpClient.Do(request)
+	err = orderRepository.UpdateOrderByAccrualOrder(accrualOrder)
+	if errors.Is(err, repositories.ErrOrderFinished) {
+		log.Debug("order has been finished concurrently")
+		return nil
+	}
 	if err != nil {
-		e.Logger.Error(err)
-		return res, errors.New("cannot get order: " + err.Error())
+		log.Error("cannot update order", zap.Error(err))
+		return err
 	}
-	defer response.Body.Close()
+	ac.publishStatusChanged(log, *order, accrualOrder)
 
-	switch response.StatusCode {
-	case http.StatusOK:
-		body, err := io.ReadAll(response.Body)
-		if err != nil {
-			e.Logger.Error(err)
-			return res, errors.New("cannot read response: " + err.Error())
-		}
+	if accrualOrder.Status != entities.OrderStatusProcessed {
+		return nil
+	}
 
-		err = json.Unmarshal(body, &res)
-		if err != nil {
-			e.Logger.Error(err)
-			return res, errors.New("cannot json unmarshal: " + err.Error())
-		}
+	ac.metrics.PointsAccrued(accrualOrder.Accrual)
+	log.Info("order accrued", zap.Float32("accrual", accrualOrder.Accrual))
+	ac.publish(log, models.OrderEvent{
+		Type:       models.OrderEventTypeAccrued,
+		UserID:     order.UserID,
+		Number:     accrualOrder.Order,
+		Status:     accrualOrder.Status,
+		Accrual:    accrualOrder.Accrual,
+		OccurredAt: models.JSONTime(time.Now()),
+	})
+
+	return nil
+}
 
-		return res, nil
-	case http.StatusNoContent:
-		res.Status = entities.OrderStatusProcessing
-		return res, nil
-	case http.StatusTooManyRequests:
-		e.Logger.Info("many requests ", orderNumber)
+func (ac *AccrualService) publishStatusChanged(log *zap.Logger, order entities.Order, accrualOrder *models.AccrualOrderResponse) {
+	if order.Status == accrualOrder.Status {
+		return
+	}
 
-		// В задании указано "No more than N requests per minute allowed" значит будет простаивать 60 секунд
-		time.Sleep(60 * time.Second)
+	ac.publish(log, models.OrderEvent{
+		Type:       models.OrderEventTypeStatusChanged,
+		UserID:     order.UserID,
+		Number:     accrualOrder.Order,
+		Status:     accrualOrder.Status,
+		Accrual:    accrualOrder.Accrual,
+		OccurredAt: models.JSONTime(time.Now()),
+	})
+}
 
-		return res, errors.New("response too many request")
-	case http.StatusInternalServerError:
-		e.Logger.Info("internal server error", orderNumber)
-		return res, errors.New("response internal server error")
-	default:
-		e.Logger.Info("response unknown status: "+strconv.Itoa(response.StatusCode), orderNumber)
-		return res, errors.New("response unknown status: " + strconv.Itoa(response.StatusCode))
+func (ac *AccrualService) publish(log *zap.Logger, event models.OrderEvent) {
+	err := ac.orderEventBroker.Publish(event)
+	if err != nil {
+		log.Error("cannot publish order event", zap.String("event_type", string(event.Type)), zap.Error(err))
 	}
 }
+
+func (ac *AccrualService) saveAccrualAttempt(
+	log *zap.Logger,
+	orderRepository repositories.OrderRepositoryInterface,
+	orderNumber string,
+	statusCode int,
+	accrualOrder *models.AccrualOrderResponse,
+	fetchErr error,
+) {
+	attempt := &entities.AccrualAttempt{
+		OrderNumber: orderNumber,
+		StatusCode:  statusCode,
+	}
+	if fetchErr != nil {
+		attempt.Error = fetchErr.Error()
+	} else {
+		attempt.Status = accrualOrder.Status
+	}
+
+	err := orderRepository.CreateAccrualAttempt(attempt)
+	if err != nil {
+		log.Error("cannot save accrual attempt", zap.Error(err))
+	}
+}
+
+// FetchOrder Получение информации о расчёте начислений по заказу
+func (ac *AccrualService) FetchOrder(ctx context.Context, orderNumber string) (*models.AccrualOrderResponse, error) {
+	res, _, err := ac.provider.FetchOrder(ctx, orderNumber)
+
+	return res, err
+}
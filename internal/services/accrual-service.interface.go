@@ -1,12 +1,20 @@
 package services
 
 import (
+	"context"
+
 	"github.com/ShukinDmitriy/gophermart/internal/entities"
-	"github.com/labstack/echo/v4"
+	"github.com/ShukinDmitriy/gophermart/internal/models"
 )
 
 type AccrualServiceInterface interface {
-	SendOrderToQueue(order entities.Order)
-	ProcessOrders(e *echo.Echo)
-	ProcessFailedOrders(e *echo.Echo)
+	SendOrderToQueue(ctx context.Context, order entities.Order)
+	TrySendOrderToQueue(ctx context.Context, order entities.Order) bool
+	ProcessOrders()
+	ProcessFailedOrders()
+	FetchOrder(ctx context.Context, orderNumber string) (*models.AccrualOrderResponse, error)
+	ApplyCallback(ctx context.Context, accrualOrder models.AccrualOrderResponse) error
+	RunningWorkers() int
+	QueueStats() models.AccrualQueueStats
+	Ping(ctx context.Context) error
 }
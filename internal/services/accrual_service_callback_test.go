@@ -0,0 +1,146 @@
+package services_test
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+	"github.com/ShukinDmitriy/gophermart/internal/metrics"
+	"github.com/ShukinDmitriy/gophermart/internal/models"
+	apprepositories "github.com/ShukinDmitriy/gophermart/internal/repositories"
+	"github.com/ShukinDmitriy/gophermart/internal/repositories/memory"
+	"github.com/ShukinDmitriy/gophermart/internal/services"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+)
+
+var _ = Describe("AccrualService callbacks", func() {
+	orderNumber := "12345678903"
+
+	var storage *apprepositories.Storage
+	var service *services.AccrualService
+	var webhookService *services.WebhookService
+	var relay *services.OutboxRelay
+	var user *models.UserInfoResponse
+
+	// вебхуки и уведомления уходят только после публикации событий outbox
+	relayAll := func() {
+		for relay.RelayPending(context.Background()) > 0 {
+		}
+	}
+
+	BeforeEach(func() {
+		// Arrange
+		storage = memory.NewStorage()
+		var err error
+		user, err = storage.Users.Create(models.UserRegisterRequest{Login: "user", Password: "password"})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = storage.Orders.Create(orderNumber, user.ID)
+		Expect(err).NotTo(HaveOccurred())
+
+		webhookService = services.NewWebhookService(services.WebhookOptions{MaxAttempts: 1}, storage.Webhooks, http.DefaultClient, metrics.New(), zap.NewNop())
+		notificationService := services.NewNotificationService(services.NotificationOptions{MaxAttempts: 1}, storage.Notifications, storage.Users, nil, metrics.New(), zap.NewNop())
+		relay = services.NewOutboxRelay(
+			services.OutboxOptions{Retention: time.Hour},
+			storage.Outbox,
+			services.NewUserEventDispatcher(services.NewInMemoryEventSink(), webhookService, notificationService, zap.NewNop()),
+			metrics.New(),
+			zap.NewNop(),
+		)
+		service = services.NewAccrualService(
+			services.AccrualOptions{QueueSize: 100, RetryInterval: time.Hour},
+			storage.Orders,
+			services.NewRulesAccrualProvider(storage.Rewards, zap.NewNop()),
+			services.NewInMemoryOrderEventBroker(),
+			metrics.New(),
+			zap.NewNop(),
+		)
+	})
+
+	It("must accrue a result delivered several times at once only once", func() {
+		// Arrange
+		result := models.AccrualOrderResponse{Order: orderNumber, Status: entities.OrderStatusProcessed, Accrual: 500}
+		webhook, err := webhookService.CreateWebhook(&user.ID, models.WebhookRequest{
+			URL:    "https://example.com/hook",
+			Events: []entities.WebhookEventType{entities.WebhookEventOrderProcessed, entities.WebhookEventBalanceAccrued},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		// Act
+		var wg sync.WaitGroup
+		errs := make(chan error, 5)
+		for range 5 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				errs <- service.ApplyCallback(context.Background(), result)
+			}()
+		}
+		wg.Wait()
+		close(errs)
+		relayAll()
+
+		// Assert
+		for err := range errs {
+			Expect(err).NotTo(HaveOccurred())
+		}
+		account, err := storage.Accounts.FindByUserID(user.ID, entities.AccountTypeBonus)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(account.Sum).To(BeNumerically("~", 500, 0.001))
+		order, err := storage.Orders.FindByNumber(orderNumber)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(order.Status).To(Equal(entities.OrderStatusProcessed))
+		deliveries, err := storage.Webhooks.GetDeliveries(webhook.ID, nil, 10)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(deliveries).To(HaveLen(2))
+		Expect([]entities.WebhookEventType{deliveries[0].EventType, deliveries[1].EventType}).To(ConsistOf(
+			entities.WebhookEventOrderProcessed,
+			entities.WebhookEventBalanceAccrued,
+		))
+		notifications, err := storage.Notifications.GetNotifications(user.ID, true, 10)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(notifications).To(HaveLen(1))
+		Expect(notifications[0].Type).To(Equal(entities.NotificationOrderProcessed))
+		Expect(notifications[0].Body).To(ContainSubstring("500.00 points"))
+	})
+
+	It("must move the order through processing to processed", func() {
+		// Act
+		registeredErr := service.ApplyCallback(context.Background(), models.AccrualOrderResponse{Order: orderNumber, Status: models.AccrualStatusRegistered})
+		registered, _ := storage.Orders.FindByNumber(orderNumber)
+		processingErr := service.ApplyCallback(context.Background(), models.AccrualOrderResponse{Order: orderNumber, Status: entities.OrderStatusProcessing})
+		processing, _ := storage.Orders.FindByNumber(orderNumber)
+		invalidErr := service.ApplyCallback(context.Background(), models.AccrualOrderResponse{Order: orderNumber, Status: entities.OrderStatusInvalid})
+		lateErr := service.ApplyCallback(context.Background(), models.AccrualOrderResponse{Order: orderNumber, Status: entities.OrderStatusProcessed, Accrual: 500})
+		relayAll()
+
+		// Assert
+		Expect(registeredErr).NotTo(HaveOccurred())
+		Expect(registered.Status).To(Equal(entities.OrderStatusNew))
+		Expect(processingErr).NotTo(HaveOccurred())
+		Expect(processing.Status).To(Equal(entities.OrderStatusProcessing))
+		Expect(invalidErr).NotTo(HaveOccurred())
+		Expect(lateErr).NotTo(HaveOccurred())
+		order, err := storage.Orders.FindByNumber(orderNumber)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(order.Status).To(Equal(entities.OrderStatusInvalid))
+		notifications, err := storage.Notifications.GetNotifications(user.ID, false, 10)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(notifications).To(HaveLen(1))
+		Expect(notifications[0].Type).To(Equal(entities.NotificationOrderInvalid))
+		Expect(notifications[0].Subject).To(Equal("Order " + orderNumber + " is rejected"))
+	})
+
+	It("must reject an unknown order and a malformed result", func() {
+		// Act
+		unknownErr := service.ApplyCallback(context.Background(), models.AccrualOrderResponse{Order: "79927398713", Status: entities.OrderStatusProcessed, Accrual: 5})
+		malformedErr := service.ApplyCallback(context.Background(), models.AccrualOrderResponse{Order: orderNumber, Status: "DONE", Accrual: -5})
+
+		// Assert
+		Expect(unknownErr).To(MatchError(services.ErrOrderNotFound))
+		Expect(malformedErr).To(MatchError(services.ErrValidation))
+	})
+})
@@ -0,0 +1,98 @@
+package services_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/ShukinDmitriy/gophermart/internal/accrualsim"
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+	"github.com/ShukinDmitriy/gophermart/internal/metrics"
+	"github.com/ShukinDmitriy/gophermart/internal/models"
+	apprepositories "github.com/ShukinDmitriy/gophermart/internal/repositories"
+	"github.com/ShukinDmitriy/gophermart/internal/repositories/memory"
+	"github.com/ShukinDmitriy/gophermart/internal/services"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+)
+
+var _ = Describe("AccrualService with the accrual simulator", func() {
+	orderNumber := "9278923470"
+	accrual := 729.98
+
+	var storage *apprepositories.Storage
+	var service *services.AccrualService
+	var order *entities.Order
+
+	BeforeEach(func() {
+		// Arrange
+		conf := &accrualsim.Config{
+			Scenario: map[string][]accrualsim.Step{
+				orderNumber: {
+					{Code: http.StatusNoContent},
+					{Code: http.StatusTooManyRequests, RetryAfter: time.Second},
+					{Code: http.StatusInternalServerError},
+					{Status: entities.OrderStatusProcessing},
+					{Status: entities.OrderStatusProcessed, Accrual: &accrual},
+				},
+			},
+		}
+		Expect(conf.Validate()).To(Succeed())
+		server := httptest.NewServer(accrualsim.NewServer(conf, zap.NewNop()))
+		DeferCleanup(server.Close)
+
+		storage = memory.NewStorage()
+		user, err := storage.Users.Create(models.UserRegisterRequest{Login: "user", Password: "password"})
+		Expect(err).NotTo(HaveOccurred())
+		order, err = storage.Orders.Create(orderNumber, user.ID)
+		Expect(err).NotTo(HaveOccurred())
+
+		service = services.NewAccrualService(
+			services.AccrualOptions{
+				QueueSize:     100,
+				RetryInterval: 5 * time.Millisecond,
+			},
+			storage.Orders,
+			services.NewHTTPAccrualProvider(server.URL, time.Millisecond, server.Client(), metrics.New(), zap.NewNop()),
+			services.NewInMemoryOrderEventBroker(),
+			metrics.New(),
+			zap.NewNop(),
+		)
+	})
+
+	It("must survive 204, 429 and 500 and accrue the processed order", func() {
+		// Act
+		// Незавершённые заказы опрашиваются по одному за период, поэтому запросы идут строго по сценарию
+		go service.ProcessFailedOrders()
+
+		// Assert
+		Eventually(func() entities.OrderStatus {
+			found, err := storage.Orders.FindByNumber(orderNumber)
+			Expect(err).NotTo(HaveOccurred())
+
+			return found.Status
+		}).WithTimeout(5 * time.Second).Should(Equal(entities.OrderStatusProcessed))
+
+		Eventually(func() float32 {
+			account, err := storage.Accounts.FindByUserID(order.UserID, entities.AccountTypeBonus)
+			Expect(err).NotTo(HaveOccurred())
+
+			return account.Sum
+		}).Should(BeNumerically("~", accrual, 0.001))
+
+		attempts, err := storage.Orders.GetAccrualAttempts(orderNumber, 100)
+		Expect(err).NotTo(HaveOccurred())
+		codes := make([]int, 0, len(attempts))
+		for _, attempt := range attempts {
+			codes = append(codes, attempt.StatusCode)
+		}
+		Expect(codes).To(Equal([]int{
+			http.StatusNoContent,
+			http.StatusTooManyRequests,
+			http.StatusInternalServerError,
+			http.StatusOK,
+			http.StatusOK,
+		}))
+	})
+})
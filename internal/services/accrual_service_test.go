@@ -1,31 +1,38 @@
 package services_test
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"sync/atomic"
 	"time"
 
 	"github.com/ShukinDmitriy/gophermart/internal/entities"
+	"github.com/ShukinDmitriy/gophermart/internal/metrics"
 	"github.com/ShukinDmitriy/gophermart/internal/models"
+	apprepositories "github.com/ShukinDmitriy/gophermart/internal/repositories"
 	"github.com/ShukinDmitriy/gophermart/internal/services"
 	"github.com/ShukinDmitriy/gophermart/mocks/internal_/repositories"
 	"github.com/jfrog/go-mockhttp"
-	"github.com/labstack/echo/v4"
 	. "github.com/onsi/ginkgo/v2"
-	"gorm.io/gorm"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace/noop"
+	"go.uber.org/zap"
 )
 
 var _ = Describe("AccrualService", func() {
-	var e *echo.Echo
-	var accountRepository *repositories.AccountRepositoryInterface
-	var operationRepository *repositories.OperationRepositoryInterface
 	var orderRepository *repositories.OrderRepositoryInterface
-	var httpClient *http.Client
+	var provider *services.HTTPAccrualProvider
+	var orderEventBroker *services.InMemoryOrderEventBroker
 	var service *services.AccrualService
 
 	userID := uint(1)
-	accountID := uint(2)
 	processingOrderNumber := "24619735244"
 	noContentOrderNumber := "62794305672"
 	processedOrderNumber := "61508349208"
@@ -44,12 +51,17 @@ var _ = Describe("AccrualService", func() {
 		Accrual: 123.45,
 	}
 	accrualProcessedResponseJSON, _ := json.Marshal(accrualProcessedResponse)
+	accrualOptions := services.AccrualOptions{
+		QueueSize:     1000,
+		RetryInterval: 10 * time.Second,
+	}
+	findNewOrder := func(number string) (*entities.Order, error) {
+		return &entities.Order{Number: number, UserID: userID, Status: entities.OrderStatusNew}, nil
+	}
 
 	BeforeEach(func() {
-		e = echo.New()
-		accountRepository = new(repositories.AccountRepositoryInterface)
-		operationRepository = new(repositories.OperationRepositoryInterface)
 		orderRepository = new(repositories.OrderRepositoryInterface)
+		orderRepository.EXPECT().WithContext(mock.Anything).Return(orderRepository).Maybe()
 		client := mockhttp.NewClient(
 			mockhttp.NewClientEndpoint().
 				When(mockhttp.Request().GET(fmt.Sprintf("/api/orders/%s", processingOrderNumber))).
@@ -62,13 +74,18 @@ var _ = Describe("AccrualService", func() {
 				Respond(mockhttp.Response().StatusCode(http.StatusNoContent)),
 		)
 
-		httpClient = client.HttpClient()
+		orderRepository.EXPECT().CreateAccrualAttempt(mock.Anything).Return(nil).Maybe()
+		orderRepository.EXPECT().FindByNumber(mock.Anything).RunAndReturn(findNewOrder).Maybe()
+		orderEventBroker = services.NewInMemoryOrderEventBroker()
+
+		provider = services.NewHTTPAccrualProvider("", 60*time.Second, client.HttpClient(), metrics.New(), zap.NewNop())
 		service = services.NewAccrualService(
-			"",
-			accountRepository,
-			operationRepository,
+			accrualOptions,
 			orderRepository,
-			httpClient,
+			provider,
+			orderEventBroker,
+			metrics.New(),
+			zap.NewNop(),
 		)
 	})
 
@@ -85,8 +102,8 @@ var _ = Describe("AccrualService", func() {
 			})
 
 			// Act
-			go service.ProcessOrders(e)
-			service.SendOrderToQueue(entities.Order{
+			go service.ProcessOrders()
+			service.SendOrderToQueue(context.Background(), entities.Order{
 				Number: processingOrderNumber,
 				UserID: userID,
 			})
@@ -104,19 +121,15 @@ var _ = Describe("AccrualService", func() {
 			timeout := time.After(time.Second * 1)
 
 			// Arrange
-			orderRepository.EXPECT().UpdateOrderByAccrualOrder(&accrualProcessedResponse).Return(nil)
-			accountRepository.EXPECT().FindByUserID(userID, entities.AccountTypeBonus).Return(&entities.Account{
-				Model: gorm.Model{ID: accountID},
-			}, nil)
-			operationRepository.EXPECT().CreateAccrual(accountID, accrualProcessedResponse.Order, accrualProcessedResponse.Accrual).RunAndReturn(func(accountID uint, orderNumber string, sum float32) error {
+			orderRepository.EXPECT().UpdateOrderByAccrualOrder(&accrualProcessedResponse).RunAndReturn(func(response *models.AccrualOrderResponse) error {
 				finishedChan <- true
 
 				return nil
 			})
 
 			// Act
-			go service.ProcessOrders(e)
-			service.SendOrderToQueue(entities.Order{
+			go service.ProcessOrders()
+			service.SendOrderToQueue(context.Background(), entities.Order{
 				Number: processedOrderNumber,
 				UserID: userID,
 			})
@@ -144,8 +157,8 @@ var _ = Describe("AccrualService", func() {
 			})
 
 			// Act
-			go service.ProcessOrders(e)
-			service.SendOrderToQueue(entities.Order{
+			go service.ProcessOrders()
+			service.SendOrderToQueue(context.Background(), entities.Order{
 				Number: noContentOrderNumber,
 				UserID: userID,
 			})
@@ -157,5 +170,177 @@ var _ = Describe("AccrualService", func() {
 			// Assertions
 			orderRepository.MethodCalled("UpdateOrderByAccrualOrder", &accrualNoContentResponse)
 		})
+
+		It("must save the accrual system poll attempt", func() {
+			attemptChan := make(chan *entities.AccrualAttempt, 1)
+
+			// Arrange
+			orderRepository = new(repositories.OrderRepositoryInterface)
+			orderRepository.EXPECT().WithContext(mock.Anything).Return(orderRepository).Maybe()
+			service = services.NewAccrualService(
+				accrualOptions,
+				orderRepository,
+				provider,
+				orderEventBroker,
+				metrics.New(),
+				zap.NewNop(),
+			)
+			orderRepository.EXPECT().CreateAccrualAttempt(mock.Anything).RunAndReturn(func(attempt *entities.AccrualAttempt) error {
+				select {
+				case attemptChan <- attempt:
+				default:
+				}
+
+				return nil
+			})
+			orderRepository.EXPECT().FindByNumber(mock.Anything).RunAndReturn(findNewOrder).Maybe()
+			orderRepository.EXPECT().UpdateOrderByAccrualOrder(mock.Anything).Return(nil).Maybe()
+
+			// Act
+			go service.ProcessOrders()
+			service.SendOrderToQueue(context.Background(), entities.Order{
+				Number: noContentOrderNumber,
+				UserID: userID,
+			})
+
+			// Assertions
+			var attempt *entities.AccrualAttempt
+			Eventually(attemptChan).Should(Receive(&attempt))
+			Expect(attempt.OrderNumber).To(Equal(noContentOrderNumber))
+			Expect(attempt.StatusCode).To(Equal(http.StatusNoContent))
+			Expect(attempt.Status).To(Equal(entities.OrderStatusProcessing))
+			Expect(attempt.Error).To(BeEmpty())
+		})
+
+		It("must publish events when the order is processed", func() {
+			events, unsubscribe := orderEventBroker.Subscribe(userID)
+			defer unsubscribe()
+
+			// Arrange
+			orderRepository.EXPECT().UpdateOrderByAccrualOrder(&accrualProcessedResponse).Return(nil)
+
+			// Act
+			go service.ProcessOrders()
+			service.SendOrderToQueue(context.Background(), entities.Order{
+				Number: processedOrderNumber,
+				UserID: userID,
+				Status: entities.OrderStatusNew,
+			})
+
+			// Assertions
+			var event models.OrderEvent
+			Eventually(events).Should(Receive(&event))
+			Expect(event.Type).To(Equal(models.OrderEventTypeStatusChanged))
+			Expect(event.Status).To(Equal(entities.OrderStatusProcessed))
+			Eventually(events).Should(Receive(&event))
+			Expect(event.Type).To(Equal(models.OrderEventTypeAccrued))
+			Expect(event.Accrual).To(Equal(accrualProcessedResponse.Accrual))
+		})
+
+		It("must leave a processing order to the retry poll instead of queuing it again", func() {
+			var updates atomic.Int32
+
+			// Arrange
+			orderRepository.EXPECT().UpdateOrderByAccrualOrder(&accrualProcessingResponse).RunAndReturn(func(response *models.AccrualOrderResponse) error {
+				updates.Add(1)
+
+				return nil
+			})
+
+			// Act
+			go service.ProcessOrders()
+			service.SendOrderToQueue(context.Background(), entities.Order{
+				Number: processingOrderNumber,
+				UserID: userID,
+			})
+
+			// Assertions
+			Eventually(updates.Load).Should(BeEquivalentTo(1))
+			Consistently(updates.Load, 100*time.Millisecond).Should(BeEquivalentTo(1))
+		})
+
+		It("must skip a result for an already finished order", func() {
+			// Arrange
+			orderRepository = new(repositories.OrderRepositoryInterface)
+			orderRepository.EXPECT().WithContext(mock.Anything).Return(orderRepository).Maybe()
+			orderRepository.EXPECT().FindByNumber(processedOrderNumber).Return(&entities.Order{
+				Number: processedOrderNumber,
+				UserID: userID,
+				Status: entities.OrderStatusProcessed,
+			}, nil)
+			service = services.NewAccrualService(
+				accrualOptions,
+				orderRepository,
+				provider,
+				orderEventBroker,
+				metrics.New(),
+				zap.NewNop(),
+			)
+
+			// Act
+			err := service.ApplyCallback(context.Background(), accrualProcessedResponse)
+
+			// Assertions
+			Expect(err).NotTo(HaveOccurred())
+			orderRepository.AssertNotCalled(GinkgoT(), "UpdateOrderByAccrualOrder", mock.Anything)
+		})
+
+		It("must skip a result for an order finished concurrently", func() {
+			events, unsubscribe := orderEventBroker.Subscribe(userID)
+			defer unsubscribe()
+
+			// Arrange
+			orderRepository.EXPECT().UpdateOrderByAccrualOrder(&accrualProcessedResponse).Return(apprepositories.ErrOrderFinished)
+
+			// Act
+			err := service.ApplyCallback(context.Background(), accrualProcessedResponse)
+
+			// Assertions
+			Expect(err).NotTo(HaveOccurred())
+			Consistently(events, 50*time.Millisecond).ShouldNot(Receive())
+		})
+	})
+
+	Describe("Tracing", func() {
+		var recorder *tracetest.SpanRecorder
+
+		BeforeEach(func() {
+			recorder = tracetest.NewSpanRecorder()
+			otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+		})
+
+		AfterEach(func() {
+			otel.SetTracerProvider(noop.NewTracerProvider())
+		})
+
+		It("must process a queued order in the trace of the request that uploaded it", func() {
+			// Arrange
+			orderRepository.EXPECT().UpdateOrderByAccrualOrder(&accrualProcessedResponse).Return(nil)
+
+			ctx, requestSpan := otel.Tracer("test").Start(context.Background(), "POST /api/user/orders")
+			requestSpan.End()
+
+			// Act
+			go service.ProcessOrders()
+			service.SendOrderToQueue(ctx, entities.Order{
+				Number: processedOrderNumber,
+				UserID: userID,
+			})
+
+			// Assertions
+			var processSpan sdktrace.ReadOnlySpan
+			Eventually(func() bool {
+				for _, span := range recorder.Ended() {
+					if span.Name() == "accrual.process_order" {
+						processSpan = span
+						return true
+					}
+				}
+				return false
+			}).Should(BeTrue())
+			Expect(processSpan.Parent().TraceID()).To(Equal(requestSpan.SpanContext().TraceID()))
+			Expect(processSpan.Parent().SpanID()).To(Equal(requestSpan.SpanContext().SpanID()))
+			Expect(processSpan.Attributes()).To(ContainElement(attribute.String("order.number", processedOrderNumber)))
+		})
 	})
 })
@@ -0,0 +1,51 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ShukinDmitriy/gophermart/internal/models"
+	"github.com/ShukinDmitriy/gophermart/internal/repositories"
+)
+
+// Ошибки бизнес-правил. Транспорт (REST, gRPC) переводит их в свои коды ответа
+var (
+	// ErrValidation запрос не прошёл валидацию, подробности в обёрнутой validator.ValidationErrors
+	ErrValidation = errors.New("validation failed")
+	// ErrInvalidCursor курсор страницы повреждён или подделан
+	ErrInvalidCursor = models.ErrInvalidCursor
+	// ErrLoginAlreadyExists логин уже занят, в том числе параллельной регистрацией
+	ErrLoginAlreadyExists = repositories.ErrLoginAlreadyExists
+	// ErrInvalidCredentials неверная пара логин/пароль
+	ErrInvalidCredentials = errors.New("invalid login or password")
+	// ErrInvalidOrderFormat номер заказа содержит не только цифры
+	ErrInvalidOrderFormat = errors.New("order number must contain only digits")
+	// ErrInvalidOrderNumber номер заказа не проходит проверку алгоритмом Луна
+	ErrInvalidOrderNumber = errors.New("invalid order number")
+	// ErrOrderNotFound заказ не найден. Чужой заказ не отличаем от несуществующего
+	ErrOrderNotFound = errors.New("order not found")
+	// ErrOrderOwnedByOtherUser номер заказа уже загружен другим пользователем
+	ErrOrderOwnedByOtherUser = errors.New("order uploaded by another user")
+	// ErrInsufficientFunds на бонусном счёте недостаточно баллов
+	ErrInsufficientFunds = repositories.ErrInsufficientFunds
+	// ErrBonusAccountNotFound у пользователя нет бонусного счёта
+	ErrBonusAccountNotFound = errors.New("bonus account not found")
+	// ErrRewardRuleNotFound правило вознаграждения не найдено или удалено
+	ErrRewardRuleNotFound = errors.New("reward rule not found")
+	// ErrBasketAlreadyExists состав заказа уже загружен, в том числе параллельным запросом
+	ErrBasketAlreadyExists = repositories.ErrBasketAlreadyExists
+	// ErrWebhookNotFound вебхук не найден, удалён или принадлежит другому владельцу
+	ErrWebhookNotFound = errors.New("webhook not found")
+	// ErrURLNotAllowed адрес вебхука или вебхука уведомлений на localhost или во внутренней сети
+	ErrURLNotAllowed = errors.New("url on localhost or in a private network is not allowed")
+	// ErrUserNotFound пользователь из токена не найден
+	ErrUserNotFound = errors.New("user not found")
+	// ErrNotificationNotFound уведомление не найдено или принадлежит другому пользователю
+	ErrNotificationNotFound = errors.New("notification not found")
+	// ErrNotificationChannelUnavailable канал уведомлений не настроен на сервере
+	ErrNotificationChannelUnavailable = errors.New("notification channel is not available")
+)
+
+func newValidationError(err error) error {
+	return fmt.Errorf("%w: %w", ErrValidation, err)
+}
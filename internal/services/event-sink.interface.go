@@ -0,0 +1,14 @@
+package services
+
+import (
+	"context"
+
+	"github.com/ShukinDmitriy/gophermart/internal/models"
+)
+
+// EventSinkInterface Получатель доменных событий. Publish без ошибки значит, что получатель
+// подтвердил все события пакета; при ошибке пакет публикуется заново целиком
+type EventSinkInterface interface {
+	Publish(ctx context.Context, events []models.DomainEvent) error
+	Close() error
+}
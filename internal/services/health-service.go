@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/ShukinDmitriy/gophermart/internal/models"
+	"github.com/ShukinDmitriy/gophermart/internal/repositories"
+)
+
+// healthCheckTimeout Ограничение на каждую проверку, чтобы зависшая БД не держала пробу
+const healthCheckTimeout = 2 * time.Second
+
+type HealthService struct {
+	healthRepository         repositories.HealthRepositoryInterface
+	accrualService           AccrualServiceInterface
+	expectedMigrationVersion uint
+	expectedAccrualWorkers   int
+	shuttingDown             atomic.Bool
+}
+
+func NewHealthService(
+	healthRepository repositories.HealthRepositoryInterface,
+	accrualService AccrualServiceInterface,
+	expectedMigrationVersion uint,
+	expectedAccrualWorkers int,
+) *HealthService {
+	return &HealthService{
+		healthRepository:         healthRepository,
+		accrualService:           accrualService,
+		expectedMigrationVersion: expectedMigrationVersion,
+		expectedAccrualWorkers:   expectedAccrualWorkers,
+	}
+}
+
+// SetShuttingDown Начало остановки: дальше готовность всегда отрицательная,
+// чтобы балансировщик перестал присылать запросы до закрытия сервера
+func (s *HealthService) SetShuttingDown() {
+	s.shuttingDown.Store(true)
+}
+
+// Ready Проверки готовности: БД отвечает, схема на ожидаемой версии, обработчики очереди запущены.
+// Доступность системы расчёта сюда не входит: без неё заказы принимаются и ждут в очереди
+func (s *HealthService) Ready(ctx context.Context) *models.ReadinessResponse {
+	res, _ := s.ready(ctx)
+
+	return res
+}
+
+// Health Подробное состояние для администраторов: готовность, доступность системы расчёта и очередь
+func (s *HealthService) Health(ctx context.Context) *models.HealthResponse {
+	readiness, migrations := s.ready(ctx)
+
+	accrual := models.AccrualHealth{
+		WorkersRunning:  s.accrualService.RunningWorkers(),
+		WorkersExpected: s.expectedAccrualWorkers,
+		Queue:           s.accrualService.QueueStats(),
+	}
+
+	pingCtx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err := s.accrualService.Ping(pingCtx)
+	accrual.LatencyMs = time.Since(start).Milliseconds()
+	if err != nil {
+		accrual.Error = err.Error()
+	} else {
+		accrual.Reachable = true
+	}
+
+	return &models.HealthResponse{
+		ReadinessResponse: *readiness,
+		ShuttingDown:      s.shuttingDown.Load(),
+		Accrual:           accrual,
+		Migrations:        migrations,
+	}
+}
+
+func (s *HealthService) ready(ctx context.Context) (*models.ReadinessResponse, models.MigrationsHealth) {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	healthRepository := s.healthRepository.WithContext(ctx)
+	checks := make(map[string]models.HealthCheck)
+
+	if s.shuttingDown.Load() {
+		checks["shutdown"] = failedCheck(errors.New("server is shutting down"))
+	}
+
+	checks["database"] = check(healthRepository.Ping())
+
+	migrations := models.MigrationsHealth{Expected: s.expectedMigrationVersion}
+	version, dirty, err := healthRepository.MigrationVersion()
+	migrations.Version, migrations.Dirty = version, dirty
+	switch {
+	case err != nil:
+		checks["migrations"] = failedCheck(err)
+	case dirty:
+		checks["migrations"] = failedCheck(fmt.Errorf("migration %d is dirty", version))
+	// схема новее ожидаемой при поэтапном обновлении: её уже подняла следующая версия сервиса
+	case version < s.expectedMigrationVersion:
+		checks["migrations"] = failedCheck(fmt.Errorf("schema version %d, expected at least %d", version, s.expectedMigrationVersion))
+	default:
+		checks["migrations"] = check(nil)
+	}
+
+	running := s.accrualService.RunningWorkers()
+	if running < s.expectedAccrualWorkers {
+		checks["accrual_workers"] = failedCheck(fmt.Errorf("%d of %d accrual workers running", running, s.expectedAccrualWorkers))
+	} else {
+		checks["accrual_workers"] = check(nil)
+	}
+
+	res := &models.ReadinessResponse{
+		Status: models.HealthStatusOK,
+		Checks: checks,
+	}
+	for _, c := range checks {
+		if c.Status != models.HealthStatusOK {
+			res.Status = models.HealthStatusFail
+		}
+	}
+
+	return res, migrations
+}
+
+func check(err error) models.HealthCheck {
+	if err != nil {
+		return failedCheck(err)
+	}
+
+	return models.HealthCheck{Status: models.HealthStatusOK}
+}
+
+func failedCheck(err error) models.HealthCheck {
+	return models.HealthCheck{
+		Status: models.HealthStatusFail,
+		Error:  err.Error(),
+	}
+}
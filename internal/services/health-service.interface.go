@@ -0,0 +1,13 @@
+package services
+
+import (
+	"context"
+
+	"github.com/ShukinDmitriy/gophermart/internal/models"
+)
+
+type HealthServiceInterface interface {
+	SetShuttingDown()
+	Ready(ctx context.Context) *models.ReadinessResponse
+	Health(ctx context.Context) *models.HealthResponse
+}
@@ -0,0 +1,131 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ShukinDmitriy/gophermart/internal/models"
+	"github.com/ShukinDmitriy/gophermart/internal/services"
+	"github.com/ShukinDmitriy/gophermart/mocks/internal_/repositories"
+	mockservices "github.com/ShukinDmitriy/gophermart/mocks/internal_/services"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+)
+
+var _ = Describe("HealthService", func() {
+	var healthRepository *repositories.HealthRepositoryInterface
+	var accrualService *mockservices.AccrualServiceInterface
+	var service *services.HealthService
+
+	expectedVersion := uint(6)
+	expectedWorkers := 2
+
+	BeforeEach(func() {
+		healthRepository = new(repositories.HealthRepositoryInterface)
+		healthRepository.EXPECT().WithContext(mock.Anything).Return(healthRepository).Maybe()
+		accrualService = new(mockservices.AccrualServiceInterface)
+		service = services.NewHealthService(healthRepository, accrualService, expectedVersion, expectedWorkers)
+	})
+
+	Describe("Ready", func() {
+		It("must be ready when the database, the schema and the workers are fine", func() {
+			// Arrange
+			healthRepository.EXPECT().Ping().Return(nil)
+			healthRepository.EXPECT().MigrationVersion().Return(expectedVersion, false, nil)
+			accrualService.EXPECT().RunningWorkers().Return(expectedWorkers)
+
+			// Act
+			res := service.Ready(context.Background())
+
+			// Assertions
+			Expect(res.Status).To(Equal(models.HealthStatusOK))
+			Expect(res.Checks).To(HaveLen(3))
+			for _, check := range res.Checks {
+				Expect(check.Status).To(Equal(models.HealthStatusOK))
+			}
+		})
+
+		It("must report every failed check", func() {
+			// Arrange
+			healthRepository.EXPECT().Ping().Return(errors.New("connection refused"))
+			healthRepository.EXPECT().MigrationVersion().Return(expectedVersion-1, false, nil)
+			accrualService.EXPECT().RunningWorkers().Return(1)
+
+			// Act
+			res := service.Ready(context.Background())
+
+			// Assertions
+			Expect(res.Status).To(Equal(models.HealthStatusFail))
+			Expect(res.Checks["database"]).To(Equal(models.HealthCheck{Status: models.HealthStatusFail, Error: "connection refused"}))
+			Expect(res.Checks["migrations"].Error).To(Equal("schema version 5, expected at least 6"))
+			Expect(res.Checks["accrual_workers"].Error).To(Equal("1 of 2 accrual workers running"))
+		})
+
+		It("must accept a schema newer than expected", func() {
+			// Arrange
+			healthRepository.EXPECT().Ping().Return(nil)
+			healthRepository.EXPECT().MigrationVersion().Return(expectedVersion+1, false, nil)
+			accrualService.EXPECT().RunningWorkers().Return(2)
+
+			// Act
+			res := service.Ready(context.Background())
+
+			// Assertions
+			Expect(res.Checks["migrations"].Status).To(Equal(models.HealthStatusOK))
+		})
+
+		It("must fail on a dirty migration", func() {
+			// Arrange
+			healthRepository.EXPECT().Ping().Return(nil)
+			healthRepository.EXPECT().MigrationVersion().Return(expectedVersion, true, nil)
+			accrualService.EXPECT().RunningWorkers().Return(expectedWorkers)
+
+			// Act
+			res := service.Ready(context.Background())
+
+			// Assertions
+			Expect(res.Status).To(Equal(models.HealthStatusFail))
+			Expect(res.Checks["migrations"].Error).To(Equal("migration 6 is dirty"))
+		})
+
+		It("must fail during shutdown even if everything else is fine", func() {
+			// Arrange
+			healthRepository.EXPECT().Ping().Return(nil)
+			healthRepository.EXPECT().MigrationVersion().Return(expectedVersion, false, nil)
+			accrualService.EXPECT().RunningWorkers().Return(expectedWorkers)
+
+			// Act
+			service.SetShuttingDown()
+			res := service.Ready(context.Background())
+
+			// Assertions
+			Expect(res.Status).To(Equal(models.HealthStatusFail))
+			Expect(res.Checks["shutdown"].Status).To(Equal(models.HealthStatusFail))
+		})
+	})
+
+	Describe("Health", func() {
+		It("must include accrual reachability and the queue state", func() {
+			// Arrange
+			queue := models.AccrualQueueStats{Depth: 3, Capacity: 1000, LastWaitMs: 250}
+			healthRepository.EXPECT().Ping().Return(nil)
+			healthRepository.EXPECT().MigrationVersion().Return(expectedVersion, false, nil)
+			accrualService.EXPECT().RunningWorkers().Return(expectedWorkers)
+			accrualService.EXPECT().QueueStats().Return(queue)
+			accrualService.EXPECT().Ping(mock.Anything).Return(errors.New("no such host"))
+
+			// Act
+			res := service.Health(context.Background())
+
+			// Assertions
+			Expect(res.Status).To(Equal(models.HealthStatusOK))
+			Expect(res.ShuttingDown).To(BeFalse())
+			Expect(res.Migrations).To(Equal(models.MigrationsHealth{Version: expectedVersion, Expected: expectedVersion}))
+			Expect(res.Accrual.Reachable).To(BeFalse())
+			Expect(res.Accrual.Error).To(Equal("no such host"))
+			Expect(res.Accrual.WorkersRunning).To(Equal(expectedWorkers))
+			Expect(res.Accrual.Queue).To(Equal(queue))
+		})
+	})
+})
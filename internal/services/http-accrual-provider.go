@@ -0,0 +1,120 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+	"github.com/ShukinDmitriy/gophermart/internal/logging"
+	"github.com/ShukinDmitriy/gophermart/internal/metrics"
+	"github.com/ShukinDmitriy/gophermart/internal/models"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// HTTPAccrualProvider Внешняя система расчёта начислений, GET {baseURL}/api/orders/{number}
+type HTTPAccrualProvider struct {
+	baseURL string
+	// rateLimitBackoff пауза после ответа 429
+	rateLimitBackoff time.Duration
+	httpClient       *http.Client
+	metrics          *metrics.Metrics
+	logger           *zap.Logger
+}
+
+func NewHTTPAccrualProvider(
+	baseURL string,
+	rateLimitBackoff time.Duration,
+	httpClient *http.Client,
+	metrics *metrics.Metrics,
+	logger *zap.Logger,
+) *HTTPAccrualProvider {
+	return &HTTPAccrualProvider{
+		baseURL:          baseURL,
+		rateLimitBackoff: rateLimitBackoff,
+		httpClient:       httpClient,
+		metrics:          metrics,
+		logger:           logger.Named("accrual"),
+	}
+}
+
+// Ping Проверка доступности системы расчёта: подходит любой HTTP ответ.
+// Запрос идёт в общий лимит системы расчёта, поэтому вызывается только по запросу администратора
+func (p *HTTPAccrualProvider) Ping(ctx context.Context) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, p.baseURL+"/api/orders/0", http.NoBody)
+	if err != nil {
+		return err
+	}
+
+	response, err := p.httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+
+	return response.Body.Close()
+}
+
+func (p *HTTPAccrualProvider) FetchOrder(ctx context.Context, orderNumber string) (*models.AccrualOrderResponse, int, error) {
+	log := p.logger.With(logging.OrderNumber(orderNumber)).With(logging.TraceID(ctx)...)
+	res := &models.AccrualOrderResponse{
+		Order:  orderNumber,
+		Status: entities.OrderStatusProcessing,
+	}
+
+	url := fmt.Sprintf("%s/api/orders/%s", p.baseURL, orderNumber)
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+	if err != nil {
+		log.Error("cannot create accrual request", zap.Error(err))
+		return res, 0, errors.New("cannot create request: " + err.Error())
+	}
+
+	start := time.Now()
+	response, err := p.httpClient.Do(request)
+	if err != nil {
+		p.metrics.ObserveAccrualRequest(0, time.Since(start))
+		log.Error("accrual request failed", zap.Error(err))
+		return res, 0, errors.New("cannot get order: " + err.Error())
+	}
+	defer response.Body.Close()
+	p.metrics.ObserveAccrualRequest(response.StatusCode, time.Since(start))
+
+	switch response.StatusCode {
+	case http.StatusOK:
+		body, err := io.ReadAll(response.Body)
+		if err != nil {
+			log.Error("cannot read accrual response", zap.Error(err))
+			return res, response.StatusCode, errors.New("cannot read response: " + err.Error())
+		}
+
+		err = json.Unmarshal(body, &res)
+		if err != nil {
+			log.Error("cannot decode accrual response", zap.Error(err))
+			return res, response.StatusCode, errors.New("cannot json unmarshal: " + err.Error())
+		}
+
+		return res, response.StatusCode, nil
+	case http.StatusNoContent:
+		res.Status = entities.OrderStatusProcessing
+		return res, response.StatusCode, nil
+	case http.StatusTooManyRequests:
+		log.Warn("accrual system rate limit exceeded")
+
+		// В задании указано "No more than N requests per minute allowed" значит будет простаивать 60 секунд
+		p.metrics.AccrualRateLimited(p.rateLimitBackoff)
+		time.Sleep(p.rateLimitBackoff)
+		p.metrics.AccrualBackoffFinished()
+
+		return res, response.StatusCode, errors.New("response too many request")
+	case http.StatusInternalServerError:
+		log.Warn("accrual system internal server error")
+		return res, response.StatusCode, errors.New("response internal server error")
+	default:
+		log.Warn("accrual system unknown response status", zap.Int("status", response.StatusCode))
+		return res, response.StatusCode, errors.New("response unknown status: " + strconv.Itoa(response.StatusCode))
+	}
+}
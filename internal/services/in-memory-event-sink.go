@@ -0,0 +1,51 @@
+package services
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ShukinDmitriy/gophermart/internal/models"
+)
+
+// InMemoryEventSink Накапливает опубликованные события в памяти, для тестов
+type InMemoryEventSink struct {
+	mu     sync.Mutex
+	events []models.DomainEvent
+	err    error
+}
+
+func NewInMemoryEventSink() *InMemoryEventSink {
+	return &InMemoryEventSink{}
+}
+
+func (s *InMemoryEventSink) Publish(_ context.Context, events []models.DomainEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.err != nil {
+		return s.err
+	}
+	s.events = append(s.events, events...)
+
+	return nil
+}
+
+// Fail Следующие публикации завершаются ошибкой err, nil возвращает получатель в строй
+func (s *InMemoryEventSink) Fail(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.err = err
+}
+
+// Events Опубликованные события в порядке публикации
+func (s *InMemoryEventSink) Events() []models.DomainEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]models.DomainEvent(nil), s.events...)
+}
+
+func (s *InMemoryEventSink) Close() error {
+	return nil
+}
@@ -0,0 +1,53 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/ShukinDmitriy/gophermart/internal/models"
+	"github.com/segmentio/kafka-go"
+)
+
+// KafkaEventSink Публикация событий в топик Kafka. Ключ сообщения — ключ агрегата,
+// поэтому события агрегата попадают в одну партицию и читаются по порядку
+type KafkaEventSink struct {
+	writer *kafka.Writer
+}
+
+func NewKafkaEventSink(brokers []string, topic string) *KafkaEventSink {
+	return &KafkaEventSink{
+		writer: &kafka.Writer{
+			Addr:         kafka.TCP(brokers...),
+			Topic:        topic,
+			Balancer:     &kafka.Hash{},
+			RequiredAcks: kafka.RequireAll,
+			BatchTimeout: 10 * time.Millisecond,
+		},
+	}
+}
+
+func (s *KafkaEventSink) Publish(ctx context.Context, events []models.DomainEvent) error {
+	messages := make([]kafka.Message, 0, len(events))
+	for _, event := range events {
+		data, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+
+		messages = append(messages, kafka.Message{
+			Key:   []byte(event.Key()),
+			Value: data,
+			Headers: []kafka.Header{
+				{Key: "event_id", Value: []byte(event.ID)},
+				{Key: "event_type", Value: []byte(event.Type)},
+			},
+		})
+	}
+
+	return s.writer.WriteMessages(ctx, messages...)
+}
+
+func (s *KafkaEventSink) Close() error {
+	return s.writer.Close()
+}
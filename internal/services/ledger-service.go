@@ -0,0 +1,155 @@
+package services
+
+import (
+	"context"
+
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+	"github.com/ShukinDmitriy/gophermart/internal/metrics"
+	"github.com/ShukinDmitriy/gophermart/internal/models"
+	"github.com/ShukinDmitriy/gophermart/internal/repositories"
+	"github.com/go-playground/validator/v10"
+)
+
+type LedgerService struct {
+	accountRepository   repositories.AccountRepositoryInterface
+	operationRepository repositories.OperationRepositoryInterface
+	orderRepository     repositories.OrderRepositoryInterface
+	webhookService      WebhookServiceInterface
+	metrics             *metrics.Metrics
+	validate            *validator.Validate
+}
+
+func NewLedgerService(
+	accountRepository repositories.AccountRepositoryInterface,
+	operationRepository repositories.OperationRepositoryInterface,
+	orderRepository repositories.OrderRepositoryInterface,
+	webhookService WebhookServiceInterface,
+	metrics *metrics.Metrics,
+) *LedgerService {
+	return &LedgerService{
+		accountRepository:   accountRepository,
+		operationRepository: operationRepository,
+		orderRepository:     orderRepository,
+		webhookService:      webhookService,
+		metrics:             metrics,
+		validate:            validator.New(validator.WithRequiredStructEnabled()),
+	}
+}
+
+// GetBalance Текущий баланс и сумма списаний за всё время
+func (s *LedgerService) GetBalance(userID uint) (*models.GetBalanceResponse, error) {
+	bonusAccount, err := s.getBonusAccount(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	withdrawn, err := s.operationRepository.GetWithdrawnByAccountID(bonusAccount.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.GetBalanceResponse{
+		Current:   bonusAccount.Sum,
+		Withdrawn: withdrawn,
+	}, nil
+}
+
+// Withdraw Списание баллов в счёт оплаты заказа. Номер заказа другого пользователя по спецификации
+// считается неверным номером (422), а не конфликтом
+func (s *LedgerService) Withdraw(userID uint, request models.CreateWithdrawRequest) error {
+	if err := s.validate.Struct(request); err != nil {
+		return newValidationError(err)
+	}
+
+	if err := CheckOrderNumber(request.Order); err != nil {
+		return ErrInvalidOrderNumber
+	}
+
+	bonusAccount, err := s.getBonusAccount(userID)
+	if err != nil {
+		return err
+	}
+
+	order, err := s.orderRepository.FindByNumber(request.Order)
+	if err != nil {
+		return err
+	}
+	if order != nil && order.UserID != userID {
+		return ErrInvalidOrderNumber
+	}
+
+	// остаток проверяется в транзакции списания, проверка здесь не защищает от параллельных запросов
+	err = s.operationRepository.CreateWithdrawn(bonusAccount.ID, request.Order, request.Sum)
+	if err != nil {
+		return err
+	}
+
+	s.metrics.PointsWithdrawn(request.Sum)
+	s.webhookService.Emit(context.Background(), models.WebhookEvent{
+		Type:   entities.WebhookEventBalanceWithdrawn,
+		UserID: userID,
+		Order:  request.Order,
+		Sum:    request.Sum,
+	})
+
+	return nil
+}
+
+// ListWithdrawals Списания пользователя. Пустой запрос возвращает все списания без курсора следующей страницы
+func (s *LedgerService) ListWithdrawals(userID uint, request models.GetWithdrawalsRequest) (*models.GetWithdrawalsPage, error) {
+	if err := s.validate.Struct(request); err != nil {
+		return nil, newValidationError(err)
+	}
+
+	bonusAccount, err := s.getBonusAccount(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if request.IsEmpty() {
+		withdrawals, err := s.operationRepository.GetWithdrawalsByAccountID(bonusAccount.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		return &models.GetWithdrawalsPage{Withdrawals: withdrawals}, nil
+	}
+
+	filter, err := request.ToFilter(bonusAccount.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.operationRepository.GetWithdrawalsPage(filter)
+}
+
+// ListOperations Все движения по бонусному счёту пользователя с остатком после каждой операции
+func (s *LedgerService) ListOperations(userID uint, request models.GetOperationsRequest) (*models.GetOperationsPage, error) {
+	if err := s.validate.Struct(request); err != nil {
+		return nil, newValidationError(err)
+	}
+
+	bonusAccount, err := s.getBonusAccount(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	filter, err := request.ToFilter(bonusAccount.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.operationRepository.GetOperationsPage(filter)
+}
+
+func (s *LedgerService) getBonusAccount(userID uint) (*entities.Account, error) {
+	bonusAccount, err := s.accountRepository.FindByUserID(userID, entities.AccountTypeBonus)
+	if err != nil {
+		return nil, err
+	}
+	if bonusAccount == nil {
+		return nil, ErrBonusAccountNotFound
+	}
+
+	return bonusAccount, nil
+}
@@ -0,0 +1,12 @@
+package services
+
+import (
+	"github.com/ShukinDmitriy/gophermart/internal/models"
+)
+
+type LedgerServiceInterface interface {
+	GetBalance(userID uint) (*models.GetBalanceResponse, error)
+	Withdraw(userID uint, request models.CreateWithdrawRequest) error
+	ListWithdrawals(userID uint, request models.GetWithdrawalsRequest) (*models.GetWithdrawalsPage, error)
+	ListOperations(userID uint, request models.GetOperationsRequest) (*models.GetOperationsPage, error)
+}
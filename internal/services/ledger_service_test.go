@@ -0,0 +1,124 @@
+package services_test
+
+import (
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+	"github.com/ShukinDmitriy/gophermart/internal/metrics"
+	"github.com/ShukinDmitriy/gophermart/internal/models"
+	"github.com/ShukinDmitriy/gophermart/internal/services"
+	"github.com/ShukinDmitriy/gophermart/mocks/internal_/repositories"
+	mockservices "github.com/ShukinDmitriy/gophermart/mocks/internal_/services"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+var _ = Describe("LedgerService", func() {
+	var accountRepository *repositories.AccountRepositoryInterface
+	var operationRepository *repositories.OperationRepositoryInterface
+	var orderRepository *repositories.OrderRepositoryInterface
+	var webhookService *mockservices.WebhookServiceInterface
+	var service *services.LedgerService
+
+	userID := uint(1)
+	orderNumber := "2377225624"
+	account := &entities.Account{
+		Model: gorm.Model{
+			ID: 7,
+		},
+		Sum: 500,
+	}
+
+	BeforeEach(func() {
+		accountRepository = new(repositories.AccountRepositoryInterface)
+		operationRepository = new(repositories.OperationRepositoryInterface)
+		orderRepository = new(repositories.OrderRepositoryInterface)
+		webhookService = new(mockservices.WebhookServiceInterface)
+		service = services.NewLedgerService(accountRepository, operationRepository, orderRepository, webhookService, metrics.New())
+	})
+
+	Describe("Withdraw", func() {
+		It("must withdraw from the bonus account", func() {
+			// Arrange
+			accountRepository.EXPECT().FindByUserID(userID, entities.AccountTypeBonus).Return(account, nil)
+			orderRepository.EXPECT().FindByNumber(orderNumber).Return(nil, nil)
+			operationRepository.EXPECT().CreateWithdrawn(account.ID, orderNumber, float32(100)).Return(nil)
+			webhookService.EXPECT().Emit(mock.Anything, mock.MatchedBy(func(event models.WebhookEvent) bool {
+				return event.Type == entities.WebhookEventBalanceWithdrawn && event.UserID == userID && event.Sum == 100
+			})).Return().Once()
+
+			// Act
+			err := service.Withdraw(userID, models.CreateWithdrawRequest{Order: orderNumber, Sum: 100})
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			webhookService.AssertExpectations(GinkgoT())
+		})
+
+		It("must not withdraw more than the balance", func() {
+			// Arrange
+			accountRepository.EXPECT().FindByUserID(userID, entities.AccountTypeBonus).Return(account, nil)
+			orderRepository.EXPECT().FindByNumber(orderNumber).Return(nil, nil)
+			// остаток проверяет репозиторий в транзакции списания
+			operationRepository.EXPECT().CreateWithdrawn(account.ID, orderNumber, float32(501)).Return(services.ErrInsufficientFunds)
+
+			// Act
+			err := service.Withdraw(userID, models.CreateWithdrawRequest{Order: orderNumber, Sum: 501})
+
+			// Assert
+			Expect(err).To(MatchError(services.ErrInsufficientFunds))
+		})
+
+		It("must not withdraw for an order of another user", func() {
+			// Arrange
+			accountRepository.EXPECT().FindByUserID(userID, entities.AccountTypeBonus).Return(account, nil)
+			orderRepository.EXPECT().FindByNumber(orderNumber).Return(&entities.Order{Number: orderNumber, UserID: 2}, nil)
+
+			// Act
+			err := service.Withdraw(userID, models.CreateWithdrawRequest{Order: orderNumber, Sum: 100})
+
+			// Assert
+			Expect(err).To(MatchError(services.ErrInvalidOrderNumber))
+		})
+
+		It("must reject an invalid request before touching the account", func() {
+			err := service.Withdraw(userID, models.CreateWithdrawRequest{Order: orderNumber})
+			Expect(err).To(MatchError(services.ErrValidation))
+
+			err = service.Withdraw(userID, models.CreateWithdrawRequest{Order: "12345678904", Sum: 100})
+			Expect(err).To(MatchError(services.ErrInvalidOrderNumber))
+		})
+
+		It("must return an error if the user has no bonus account", func() {
+			// Arrange
+			accountRepository.EXPECT().FindByUserID(userID, entities.AccountTypeBonus).Return(nil, nil)
+
+			// Act
+			err := service.Withdraw(userID, models.CreateWithdrawRequest{Order: orderNumber, Sum: 100})
+
+			// Assert
+			Expect(err).To(MatchError(services.ErrBonusAccountNotFound))
+		})
+	})
+
+	Describe("ListOperations", func() {
+		It("must reject a forged cursor", func() {
+			// Arrange
+			accountRepository.EXPECT().FindByUserID(userID, entities.AccountTypeBonus).Return(account, nil)
+
+			// Act
+			_, err := service.ListOperations(userID, models.GetOperationsRequest{Cursor: "forged"})
+
+			// Assert
+			Expect(err).To(MatchError(services.ErrInvalidCursor))
+		})
+
+		It("must reject an unknown operation type", func() {
+			// Act
+			_, err := service.ListOperations(userID, models.GetOperationsRequest{Type: []string{"bonus"}})
+
+			// Assert
+			Expect(err).To(MatchError(services.ErrValidation))
+		})
+	})
+})
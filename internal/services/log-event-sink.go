@@ -0,0 +1,36 @@
+package services
+
+import (
+	"context"
+
+	"github.com/ShukinDmitriy/gophermart/internal/models"
+	"go.uber.org/zap"
+)
+
+// LogEventSink Публикация событий в журнал приложения, когда внешний брокер не настроен
+type LogEventSink struct {
+	logger *zap.Logger
+}
+
+func NewLogEventSink(logger *zap.Logger) *LogEventSink {
+	return &LogEventSink{
+		logger: logger.Named("events"),
+	}
+}
+
+func (s *LogEventSink) Publish(_ context.Context, events []models.DomainEvent) error {
+	for _, event := range events {
+		s.logger.Info("domain event",
+			zap.String("event_id", event.ID),
+			zap.String("event_type", string(event.Type)),
+			zap.String("aggregate", event.Key()),
+			zap.ByteString("payload", event.Payload),
+		)
+	}
+
+	return nil
+}
+
+func (s *LogEventSink) Close() error {
+	return nil
+}
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ShukinDmitriy/gophermart/internal/models"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"go.uber.org/zap"
+)
+
+// EventHeaderKey заголовок с ключом агрегата события
+const EventHeaderKey = "Gophermart-Aggregate"
+
+// NATSEventSink Публикация событий в JetStream с темой <subject>.<тип события>.
+// Поток, принимающий эти темы, создаётся заранее. По Nats-Msg-Id JetStream отбрасывает повторы
+// в пределах окна дедупликации потока
+type NATSEventSink struct {
+	conn    *nats.Conn
+	js      jetstream.JetStream
+	subject string
+}
+
+func NewNATSEventSink(url string, subject string, logger *zap.Logger) (*NATSEventSink, error) {
+	logger = logger.Named("events")
+	conn, err := nats.Connect(url,
+		nats.Name("gophermart"),
+		nats.MaxReconnects(-1),
+		nats.DisconnectErrHandler(func(_ *nats.Conn, err error) {
+			logger.Warn("nats disconnected", zap.Error(err))
+		}),
+		nats.ReconnectHandler(func(conn *nats.Conn) {
+			logger.Info("nats reconnected", zap.String("url", conn.ConnectedUrlRedacted()))
+		}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("connect to nats: %w", err)
+	}
+
+	js, err := jetstream.New(conn)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return &NATSEventSink{
+		conn:    conn,
+		js:      js,
+		subject: subject,
+	}, nil
+}
+
+// Publish Публикует события по одному и ждёт подтверждения каждого
+func (s *NATSEventSink) Publish(ctx context.Context, events []models.DomainEvent) error {
+	for _, event := range events {
+		data, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+
+		msg := nats.NewMsg(s.subject + "." + string(event.Type))
+		msg.Header.Set(EventHeaderKey, event.Key())
+		msg.Data = data
+		if _, err = s.js.PublishMsg(ctx, msg, jetstream.WithMsgID(event.ID)); err != nil {
+			return fmt.Errorf("publish event %s: %w", event.ID, err)
+		}
+	}
+
+	return nil
+}
+
+func (s *NATSEventSink) Close() error {
+	return s.conn.Drain()
+}
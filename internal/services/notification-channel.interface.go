@@ -0,0 +1,13 @@
+package services
+
+import (
+	"context"
+
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+)
+
+// NotificationChannelInterface Внешний канал уведомлений. После ошибки отправка повторяется,
+// кроме errNotificationChannelDisabled: пользователь отключил канал после создания уведомления
+type NotificationChannelInterface interface {
+	Send(ctx context.Context, user *entities.User, notification *entities.Notification) error
+}
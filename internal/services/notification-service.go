@@ -0,0 +1,389 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+	"github.com/ShukinDmitriy/gophermart/internal/logging"
+	"github.com/ShukinDmitriy/gophermart/internal/metrics"
+	"github.com/ShukinDmitriy/gophermart/internal/models"
+	"github.com/ShukinDmitriy/gophermart/internal/netguard"
+	"github.com/ShukinDmitriy/gophermart/internal/repositories"
+	"github.com/go-playground/validator/v10"
+	"go.uber.org/zap"
+)
+
+const (
+	// notificationBatchSize отправок, забираемых за один проход
+	notificationBatchSize = 100
+	// notificationWorkers одновременных отправок
+	notificationWorkers = 8
+	// notificationSendTimeout на одну попытку отправки
+	notificationSendTimeout = 30 * time.Second
+	// notificationLease на это время отправка откладывается на время попытки, больше notificationSendTimeout
+	notificationLease = 5 * time.Minute
+	// notificationMaxBackoff наибольшая пауза между попытками
+	notificationMaxBackoff = 6 * time.Hour
+	// notificationMaxErrorLength ошибки длиннее обрезаются перед записью
+	notificationMaxErrorLength = 512
+)
+
+// errNotificationChannelDisabled пользователь отключил канал после создания уведомления, повторять нечего
+var errNotificationChannelDisabled = errors.New("notification channel is disabled by the user")
+
+// NotificationOptions Настройки отправки уведомлений по внешним каналам
+type NotificationOptions struct {
+	// MaxAttempts попыток отправки, после последней неудачной отправка помечается failed
+	MaxAttempts int
+	// RetryBackoff пауза перед второй попыткой, перед каждой следующей удваивается
+	RetryBackoff time.Duration
+	// PollInterval период проверки отправок, время попытки которых наступило
+	PollInterval time.Duration
+	// AllowPrivateNetworks принимать адрес вебхука на localhost и во внутренних сетях (разработка)
+	AllowPrivateNetworks bool
+}
+
+// NotificationService Уведомления пользователей об исходе обработки заказов. Уведомление всегда
+// сохраняется для GET /api/user/notifications, по почте и вебхуку отправляется по настройкам пользователя
+type NotificationService struct {
+	options                NotificationOptions
+	notificationRepository repositories.NotificationRepositoryInterface
+	userRepository         repositories.UserRepositoryInterface
+	// channels настроенные на сервере внешние каналы
+	channels map[entities.NotificationChannel]NotificationChannelInterface
+	metrics  *metrics.Metrics
+	logger   *zap.Logger
+	validate *validator.Validate
+	// wake будит Run после Notify, чтобы новые уведомления не ждали PollInterval
+	wake chan struct{}
+}
+
+func NewNotificationService(
+	options NotificationOptions,
+	notificationRepository repositories.NotificationRepositoryInterface,
+	userRepository repositories.UserRepositoryInterface,
+	channels map[entities.NotificationChannel]NotificationChannelInterface,
+	metrics *metrics.Metrics,
+	logger *zap.Logger,
+) *NotificationService {
+	return &NotificationService{
+		options:                options,
+		notificationRepository: notificationRepository,
+		userRepository:         userRepository,
+		channels:               channels,
+		metrics:                metrics,
+		logger:                 logger.Named("notification"),
+		validate:               validator.New(validator.WithRequiredStructEnabled()),
+		wake:                   make(chan struct{}, 1),
+	}
+}
+
+// Notify Сохранение уведомления и его отправок по включённым каналам пользователя.
+// Исход обработки заказа уже сохранён, поэтому ошибка только пишется в лог
+func (s *NotificationService) Notify(ctx context.Context, event models.NotificationEvent) {
+	log := s.logger.With(
+		zap.String("notification_type", string(event.Type)),
+		logging.UserID(event.UserID),
+		logging.OrderNumber(event.Order),
+	).With(logging.TraceID(ctx)...)
+
+	user, err := s.userRepository.FindBy(models.UserSearchFilter{ID: event.UserID})
+	if err != nil || user == nil {
+		log.Error("cannot find user", zap.Error(err))
+		return
+	}
+
+	subject, body, err := renderNotification(event.Type, notificationData{
+		Login:   user.Login,
+		Order:   event.Order,
+		Accrual: event.Accrual,
+	})
+	if err != nil {
+		log.Error("cannot render notification", zap.Error(err))
+		return
+	}
+
+	now := time.Now()
+	var deliveries []*entities.NotificationDelivery
+	for _, channel := range s.enabledChannels(user) {
+		deliveries = append(deliveries, &entities.NotificationDelivery{
+			Channel:       channel,
+			Status:        entities.NotificationDeliveryPending,
+			NextAttemptAt: now,
+		})
+	}
+
+	err = s.notificationRepository.WithContext(ctx).CreateNotification(&entities.Notification{
+		UserID:      event.UserID,
+		Type:        event.Type,
+		OrderNumber: event.Order,
+		Subject:     subject,
+		Body:        body,
+	}, deliveries)
+	if err != nil {
+		log.Error("cannot save notification", zap.Error(err))
+		return
+	}
+	if len(deliveries) == 0 {
+		return
+	}
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// GetNotifications Уведомления пользователя, новые первыми, и число непрочитанных
+func (s *NotificationService) GetNotifications(
+	userID uint,
+	request models.GetNotificationsRequest,
+) (*models.GetNotificationsResponse, error) {
+	if err := s.validate.Struct(request); err != nil {
+		return nil, newValidationError(err)
+	}
+
+	limit := request.Limit
+	if limit == 0 {
+		limit = models.GetNotificationsDefaultLimit
+	}
+	notifications, err := s.notificationRepository.GetNotifications(userID, request.Unread, limit)
+	if err != nil {
+		return nil, err
+	}
+	unread, err := s.notificationRepository.CountUnread(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &models.GetNotificationsResponse{
+		Unread:        unread,
+		Notifications: make([]models.NotificationResponse, 0, len(notifications)),
+	}
+	for _, notification := range notifications {
+		res.Notifications = append(res.Notifications, models.MapNotification(notification))
+	}
+
+	return res, nil
+}
+
+// MarkRead Отмечает уведомление прочитанным. Повторная отметка не меняет время прочтения
+func (s *NotificationService) MarkRead(userID uint, id uint) error {
+	notification, err := s.notificationRepository.FindNotification(id)
+	if err != nil {
+		return err
+	}
+	if notification == nil || notification.UserID != userID {
+		return ErrNotificationNotFound
+	}
+
+	_, err = s.notificationRepository.MarkRead(userID, []uint{id}, time.Now())
+
+	return err
+}
+
+func (s *NotificationService) MarkAllRead(userID uint) error {
+	_, err := s.notificationRepository.MarkRead(userID, nil, time.Now())
+
+	return err
+}
+
+func (s *NotificationService) GetPreferences(userID uint) (*models.NotificationPreferencesResponse, error) {
+	user, err := s.findUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := models.MapNotificationPreferences(user)
+
+	return &res, nil
+}
+
+// UpdatePreferences Замена настроек уведомлений. Новый адрес вебхука получает новый секрет подписи,
+// он возвращается только в этом ответе
+func (s *NotificationService) UpdatePreferences(
+	userID uint,
+	request models.NotificationPreferencesRequest,
+) (*models.NotificationPreferencesResponse, error) {
+	if err := s.validate.Struct(request); err != nil {
+		return nil, newValidationError(err)
+	}
+	if request.EmailEnabled && s.channels[entities.NotificationChannelEmail] == nil ||
+		request.WebhookURL != "" && s.channels[entities.NotificationChannelWebhook] == nil {
+		return nil, ErrNotificationChannelUnavailable
+	}
+	if request.WebhookURL != "" && !s.options.AllowPrivateNetworks && netguard.CheckURL(request.WebhookURL) != nil {
+		return nil, ErrURLNotAllowed
+	}
+
+	user, err := s.findUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	preferences := entities.NotificationPreferences{
+		Email:         request.EmailEnabled,
+		WebhookURL:    request.WebhookURL,
+		WebhookSecret: user.Notifications.WebhookSecret,
+	}
+	newSecret := ""
+	switch {
+	case request.WebhookURL == "":
+		preferences.WebhookSecret = ""
+	case request.WebhookURL != user.Notifications.WebhookURL:
+		if newSecret, err = randomHex(32); err != nil {
+			return nil, err
+		}
+		preferences.WebhookSecret = newSecret
+	}
+
+	if err = s.userRepository.UpdateNotificationPreferences(userID, request.Email, preferences); err != nil {
+		return nil, err
+	}
+
+	user.Email = request.Email
+	user.Notifications = preferences
+	res := models.MapNotificationPreferences(user)
+	res.WebhookSecret = newSecret
+
+	return &res, nil
+}
+
+// Run Отправка уведомлений до отмены ctx: раз в PollInterval и сразу после новых уведомлений
+func (s *NotificationService) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.options.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		// полный пакет значит, что наступивших отправок может быть больше
+		for s.DeliverPending(ctx) == notificationBatchSize {
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+	}
+}
+
+// DeliverPending Одна попытка по каждой наступившей отправке, не больше notificationBatchSize.
+// Возвращает число забранных отправок
+func (s *NotificationService) DeliverPending(ctx context.Context) int {
+	deliveries, err := s.notificationRepository.WithContext(ctx).ClaimDeliveries(time.Now(), notificationLease, notificationBatchSize)
+	if err != nil {
+		s.logger.Error("cannot claim notification deliveries", zap.Error(err))
+		return 0
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, notificationWorkers)
+	for _, delivery := range deliveries {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			s.attempt(ctx, delivery)
+		}()
+	}
+	wg.Wait()
+
+	return len(deliveries)
+}
+
+// attempt Попытка отправки и запись её итога
+func (s *NotificationService) attempt(ctx context.Context, delivery *entities.NotificationDelivery) {
+	log := s.logger.With(
+		zap.Uint("notification_id", delivery.NotificationID),
+		zap.Uint("delivery_id", delivery.ID),
+		zap.String("channel", string(delivery.Channel)),
+		zap.Int("attempt", delivery.Attempts),
+	)
+
+	err := s.send(ctx, delivery)
+
+	now := time.Now()
+	result := string(entities.NotificationDeliveryDelivered)
+	switch {
+	case err == nil:
+		delivery.Status = entities.NotificationDeliveryDelivered
+		delivery.LastError = ""
+		delivery.DeliveredAt = &now
+	case errors.Is(err, errNotificationChannelDisabled) || delivery.Attempts >= s.options.MaxAttempts:
+		delivery.Status = entities.NotificationDeliveryFailed
+		delivery.LastError = truncate(err.Error(), notificationMaxErrorLength)
+		result = string(entities.NotificationDeliveryFailed)
+		log.Warn("notification delivery failed", zap.Error(err))
+	default:
+		delivery.NextAttemptAt = now.Add(backoff(s.options.RetryBackoff, notificationMaxBackoff, delivery.Attempts))
+		delivery.LastError = truncate(err.Error(), notificationMaxErrorLength)
+		result = "retry"
+		log.Info("notification delivery will be retried", zap.Time("next_attempt_at", delivery.NextAttemptAt), zap.Error(err))
+	}
+	s.metrics.NotificationDeliveryAttempt(string(delivery.Channel), result)
+
+	// итог пишется и после отмены ctx, иначе отправка повторится только после notificationLease
+	err = s.notificationRepository.WithContext(context.WithoutCancel(ctx)).UpdateDelivery(delivery)
+	if err != nil {
+		log.Error("cannot save notification delivery", zap.Error(err))
+	}
+}
+
+// send Отправка по каналу с текущими настройками пользователя
+func (s *NotificationService) send(ctx context.Context, delivery *entities.NotificationDelivery) error {
+	channel := s.channels[delivery.Channel]
+	if channel == nil {
+		return ErrNotificationChannelUnavailable
+	}
+
+	notification, err := s.notificationRepository.WithContext(ctx).FindNotification(delivery.NotificationID)
+	if err != nil {
+		return err
+	}
+	if notification == nil {
+		return ErrNotificationNotFound
+	}
+	user, err := s.userRepository.FindBy(models.UserSearchFilter{ID: notification.UserID})
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return errNotificationChannelDisabled
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, notificationSendTimeout)
+	defer cancel()
+
+	return channel.Send(ctx, user, notification)
+}
+
+// enabledChannels Внешние каналы, включённые пользователем и настроенные на сервере
+func (s *NotificationService) enabledChannels(user *entities.User) []entities.NotificationChannel {
+	var channels []entities.NotificationChannel
+	if user.Notifications.Email && user.Email != "" && s.channels[entities.NotificationChannelEmail] != nil {
+		channels = append(channels, entities.NotificationChannelEmail)
+	}
+	if user.Notifications.WebhookURL != "" && s.channels[entities.NotificationChannelWebhook] != nil {
+		channels = append(channels, entities.NotificationChannelWebhook)
+	}
+
+	return channels
+}
+
+func (s *NotificationService) findUser(userID uint) (*entities.User, error) {
+	user, err := s.userRepository.FindBy(models.UserSearchFilter{ID: userID})
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+
+	return user, nil
+}
@@ -0,0 +1,16 @@
+package services
+
+import (
+	"context"
+
+	"github.com/ShukinDmitriy/gophermart/internal/models"
+)
+
+type NotificationServiceInterface interface {
+	Notify(ctx context.Context, event models.NotificationEvent)
+	GetNotifications(userID uint, request models.GetNotificationsRequest) (*models.GetNotificationsResponse, error)
+	MarkRead(userID uint, id uint) error
+	MarkAllRead(userID uint) error
+	GetPreferences(userID uint) (*models.NotificationPreferencesResponse, error)
+	UpdatePreferences(userID uint, request models.NotificationPreferencesRequest) (*models.NotificationPreferencesResponse, error)
+}
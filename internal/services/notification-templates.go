@@ -0,0 +1,56 @@
+package services
+
+import (
+	"embed"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+)
+
+// notificationTemplateFiles Шаблон на каждый тип уведомления: templates/notifications/<тип>.tmpl
+// с блоками subject и body
+//
+//go:embed templates/notifications/*.tmpl
+var notificationTemplateFiles embed.FS
+
+var notificationTemplates = parseNotificationTemplates(
+	entities.NotificationOrderProcessed,
+	entities.NotificationOrderInvalid,
+)
+
+// notificationData Значения, доступные в шаблонах
+type notificationData struct {
+	Login   string
+	Order   string
+	Accrual float32
+}
+
+func parseNotificationTemplates(types ...entities.NotificationType) map[entities.NotificationType]*template.Template {
+	templates := make(map[entities.NotificationType]*template.Template, len(types))
+	for _, notificationType := range types {
+		name := "templates/notifications/" + string(notificationType) + ".tmpl"
+		templates[notificationType] = template.Must(template.ParseFS(notificationTemplateFiles, name))
+	}
+
+	return templates
+}
+
+// renderNotification Тема и текст уведомления по шаблону его типа
+func renderNotification(notificationType entities.NotificationType, data notificationData) (string, string, error) {
+	tmpl, ok := notificationTemplates[notificationType]
+	if !ok {
+		return "", "", fmt.Errorf("no template for notification %q", notificationType)
+	}
+
+	var subject, body strings.Builder
+	if err := tmpl.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return "", "", err
+	}
+	if err := tmpl.ExecuteTemplate(&body, "body", data); err != nil {
+		return "", "", err
+	}
+
+	return strings.TrimSpace(subject.String()), strings.TrimSpace(body.String()), nil
+}
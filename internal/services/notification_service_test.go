@@ -0,0 +1,300 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+	"github.com/ShukinDmitriy/gophermart/internal/metrics"
+	"github.com/ShukinDmitriy/gophermart/internal/models"
+	apprepositories "github.com/ShukinDmitriy/gophermart/internal/repositories"
+	"github.com/ShukinDmitriy/gophermart/internal/repositories/memory"
+	"github.com/ShukinDmitriy/gophermart/internal/services"
+	"github.com/ShukinDmitriy/gophermart/internal/webhooktest"
+	mockservices "github.com/ShukinDmitriy/gophermart/mocks/internal_/services"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+// smtpMessage Письмо, принятое fakeSMTP
+type smtpMessage struct {
+	From string
+	To   string
+	Data string
+}
+
+// fakeSMTP Почтовый сервер без TLS и аутентификации, принимает письма в канал
+func fakeSMTP() (string, <-chan smtpMessage) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	Expect(err).NotTo(HaveOccurred())
+	DeferCleanup(listener.Close)
+
+	messages := make(chan smtpMessage, 10)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				text := textproto.NewConn(conn)
+				var message smtpMessage
+				_ = text.PrintfLine("220 localhost ESMTP")
+				for {
+					line, err := text.ReadLine()
+					if err != nil {
+						return
+					}
+					command := strings.ToUpper(line)
+					switch {
+					case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+						_ = text.PrintfLine("250 localhost")
+					case strings.HasPrefix(command, "MAIL FROM:"):
+						message.From = strings.Trim(line[len("MAIL FROM:"):], "<>")
+						_ = text.PrintfLine("250 OK")
+					case strings.HasPrefix(command, "RCPT TO:"):
+						message.To = strings.Trim(line[len("RCPT TO:"):], "<>")
+						_ = text.PrintfLine("250 OK")
+					case command == "DATA":
+						_ = text.PrintfLine("354 End data with <CR><LF>.<CR><LF>")
+						data, err := text.ReadDotBytes()
+						if err != nil {
+							return
+						}
+						message.Data = string(data)
+						messages <- message
+						_ = text.PrintfLine("250 OK")
+					case command == "QUIT":
+						_ = text.PrintfLine("221 Bye")
+						return
+					default:
+						_ = text.PrintfLine("250 OK")
+					}
+				}
+			}()
+		}
+	}()
+
+	return listener.Addr().String(), messages
+}
+
+var _ = Describe("NotificationService", func() {
+	var storage *apprepositories.Storage
+	var channels map[entities.NotificationChannel]services.NotificationChannelInterface
+	var service *services.NotificationService
+	var userID uint
+
+	processed := func() models.NotificationEvent {
+		return models.NotificationEvent{Type: entities.NotificationOrderProcessed, UserID: userID, Order: "12345678903", Accrual: 500}
+	}
+
+	BeforeEach(func() {
+		// Arrange
+		storage = memory.NewStorage()
+		user, err := storage.Users.Create(models.UserRegisterRequest{Login: "user", Password: "password"})
+		Expect(err).NotTo(HaveOccurred())
+		userID = user.ID
+		channels = map[entities.NotificationChannel]services.NotificationChannelInterface{
+			entities.NotificationChannelWebhook: services.NewWebhookNotificationChannel(http.DefaultClient),
+		}
+		service = services.NewNotificationService(
+			// получатели в тестах слушают на localhost
+			services.NotificationOptions{MaxAttempts: 3, RetryBackoff: 10 * time.Millisecond, PollInterval: time.Hour, AllowPrivateNetworks: true},
+			storage.Notifications,
+			storage.Users,
+			channels,
+			metrics.New(),
+			zap.NewNop(),
+		)
+	})
+
+	updatePreferences := func(request models.NotificationPreferencesRequest) *models.NotificationPreferencesResponse {
+		preferences, err := service.UpdatePreferences(userID, request)
+		Expect(err).NotTo(HaveOccurred())
+
+		return preferences
+	}
+
+	Describe("Preferences", func() {
+		It("must issue a new webhook secret only when the url changes", func() {
+			// Act
+			first := updatePreferences(models.NotificationPreferencesRequest{WebhookURL: "https://example.com/a"})
+			same := updatePreferences(models.NotificationPreferencesRequest{WebhookURL: "https://example.com/a"})
+			changed := updatePreferences(models.NotificationPreferencesRequest{WebhookURL: "https://example.com/b"})
+			stored, err := service.GetPreferences(userID)
+			Expect(err).NotTo(HaveOccurred())
+			disabled := updatePreferences(models.NotificationPreferencesRequest{})
+			user, err := storage.Users.FindBy(models.UserSearchFilter{ID: userID})
+			Expect(err).NotTo(HaveOccurred())
+
+			// Assert
+			Expect(first.WebhookSecret).To(HaveLen(64))
+			Expect(same.WebhookSecret).To(BeEmpty())
+			Expect(changed.WebhookSecret).To(HaveLen(64))
+			Expect(changed.WebhookSecret).NotTo(Equal(first.WebhookSecret))
+			Expect(stored.WebhookURL).To(Equal("https://example.com/b"))
+			Expect(stored.WebhookSecret).To(BeEmpty())
+			Expect(disabled.WebhookURL).To(BeEmpty())
+			Expect(user.Notifications.WebhookSecret).To(BeEmpty())
+		})
+
+		It("must refuse a channel that is not configured on the server", func() {
+			// Act
+			_, err := service.UpdatePreferences(userID, models.NotificationPreferencesRequest{
+				Email:        "user@example.com",
+				EmailEnabled: true,
+			})
+
+			// Assert
+			Expect(err).To(MatchError(services.ErrNotificationChannelUnavailable))
+		})
+
+		It("must refuse a webhook url on localhost or in a private network", func() {
+			// Arrange
+			guarded := services.NewNotificationService(services.NotificationOptions{MaxAttempts: 1}, storage.Notifications, storage.Users, channels, metrics.New(), zap.NewNop())
+
+			// Act
+			_, err := guarded.UpdatePreferences(userID, models.NotificationPreferencesRequest{WebhookURL: "http://10.0.0.1/hooks"})
+
+			// Assert
+			Expect(err).To(MatchError(services.ErrURLNotAllowed))
+		})
+	})
+
+	Describe("Delivery", func() {
+		It("must store an in-app notification without external channels", func() {
+			// Act
+			service.Notify(context.Background(), processed())
+			delivered := service.DeliverPending(context.Background())
+			res, err := service.GetNotifications(userID, models.GetNotificationsRequest{})
+			Expect(err).NotTo(HaveOccurred())
+
+			// Assert
+			Expect(delivered).To(BeZero())
+			Expect(res.Unread).To(Equal(int64(1)))
+			Expect(res.Notifications[0].Subject).To(Equal("Order 12345678903 is processed"))
+			Expect(res.Notifications[0].Body).To(ContainSubstring("500.00 points"))
+		})
+
+		It("must send an email with the rendered notification", func() {
+			// Arrange
+			address, messages := fakeSMTP()
+			channels[entities.NotificationChannelEmail] = services.NewSMTPNotificationChannel(services.SMTPOptions{
+				Address: address,
+				From:    "gophermart@example.com",
+			})
+			updatePreferences(models.NotificationPreferencesRequest{Email: "user@example.com", EmailEnabled: true})
+			service.Notify(context.Background(), processed())
+
+			// Act
+			delivered := service.DeliverPending(context.Background())
+
+			// Assert
+			Expect(delivered).To(Equal(1))
+			var message smtpMessage
+			Eventually(messages).Should(Receive(&message))
+			Expect(message.From).To(Equal("gophermart@example.com"))
+			Expect(message.To).To(Equal("user@example.com"))
+			Expect(message.Data).To(ContainSubstring("Subject: Order 12345678903 is processed"))
+			Expect(message.Data).To(ContainSubstring(services.NotificationHeaderID + ": "))
+			Expect(message.Data).To(ContainSubstring("500.00 points"))
+		})
+
+		It("must retry with backoff until the webhook accepts the notification", func() {
+			// Arrange
+			receiver := webhooktest.NewReceiver(http.StatusInternalServerError, http.StatusServiceUnavailable)
+			DeferCleanup(receiver.Close)
+			preferences := updatePreferences(models.NotificationPreferencesRequest{WebhookURL: receiver.URL})
+			service.Notify(context.Background(), processed())
+
+			// Act
+			Eventually(func() int {
+				service.DeliverPending(context.Background())
+				return len(receiver.Deliveries())
+			}).Should(Equal(3))
+
+			// Assert
+			deliveries := receiver.Deliveries()
+			Expect(deliveries[0].Header.Get(services.NotificationHeaderID)).To(Equal(deliveries[2].Header.Get(services.NotificationHeaderID)))
+			Expect(deliveries[2].Verify(preferences.WebhookSecret)).To(Succeed())
+			Expect(string(deliveries[2].Body)).To(ContainSubstring(`"order":"12345678903"`))
+			Consistently(func() int {
+				return service.DeliverPending(context.Background())
+			}, 50*time.Millisecond).Should(BeZero())
+		})
+
+		It("must give up after the last attempt", func() {
+			// Arrange
+			channel := new(mockservices.NotificationChannelInterface)
+			channel.EXPECT().Send(mock.Anything, mock.Anything, mock.Anything).Return(errors.New("connection refused"))
+			channels[entities.NotificationChannelWebhook] = channel
+			updatePreferences(models.NotificationPreferencesRequest{WebhookURL: "https://example.com/notifications"})
+			service.Notify(context.Background(), processed())
+
+			// Act
+			Eventually(func() int {
+				service.DeliverPending(context.Background())
+				return len(channel.Calls)
+			}).Should(Equal(3))
+
+			// Assert
+			Consistently(func() int {
+				return service.DeliverPending(context.Background())
+			}, 50*time.Millisecond).Should(BeZero())
+			Expect(channel.Calls).To(HaveLen(3))
+		})
+
+		It("must not send to a channel the user disabled after the notification", func() {
+			// Arrange
+			receiver := webhooktest.NewReceiver()
+			DeferCleanup(receiver.Close)
+			updatePreferences(models.NotificationPreferencesRequest{WebhookURL: receiver.URL})
+			service.Notify(context.Background(), processed())
+			updatePreferences(models.NotificationPreferencesRequest{})
+
+			// Act
+			claimed := service.DeliverPending(context.Background())
+			time.Sleep(20 * time.Millisecond)
+			again := service.DeliverPending(context.Background())
+
+			// Assert
+			Expect(claimed).To(Equal(1))
+			Expect(again).To(BeZero())
+			Expect(receiver.Deliveries()).To(BeEmpty())
+		})
+	})
+
+	Describe("Read marks", func() {
+		It("must mark only own notifications and keep the first read time", func() {
+			// Arrange
+			service.Notify(context.Background(), processed())
+			service.Notify(context.Background(), models.NotificationEvent{Type: entities.NotificationOrderInvalid, UserID: userID, Order: "79927398713"})
+			res, err := service.GetNotifications(userID, models.GetNotificationsRequest{})
+			Expect(err).NotTo(HaveOccurred())
+			first := res.Notifications[0]
+
+			// Act
+			Expect(service.MarkRead(userID, first.ID)).To(Succeed())
+			read, err := service.GetNotifications(userID, models.GetNotificationsRequest{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(service.MarkAllRead(userID)).To(Succeed())
+			all, err := service.GetNotifications(userID, models.GetNotificationsRequest{})
+			Expect(err).NotTo(HaveOccurred())
+			foreign := service.MarkRead(userID+1, first.ID)
+
+			// Assert
+			Expect(read.Unread).To(Equal(int64(1)))
+			Expect(all.Unread).To(BeZero())
+			Expect(all.Notifications[0].ReadAt).To(Equal(read.Notifications[0].ReadAt))
+			Expect(foreign).To(MatchError(services.ErrNotificationNotFound))
+		})
+	})
+})
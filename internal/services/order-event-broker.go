@@ -0,0 +1,72 @@
+package services
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ShukinDmitriy/gophermart/internal/models"
+)
+
+const orderEventSubscriberBuffer = 16
+
+// InMemoryOrderEventBroker Рассылка событий по заказам подписчикам внутри одного процесса
+type InMemoryOrderEventBroker struct {
+	mu          sync.RWMutex
+	subscribers map[uint]map[chan models.OrderEvent]struct{}
+}
+
+func NewInMemoryOrderEventBroker() *InMemoryOrderEventBroker {
+	return &InMemoryOrderEventBroker{
+		subscribers: make(map[uint]map[chan models.OrderEvent]struct{}),
+	}
+}
+
+func (b *InMemoryOrderEventBroker) Publish(event models.OrderEvent) error {
+	b.dispatch(event)
+
+	return nil
+}
+
+// Subscribe Подписка на события пользователя. Возвращаемую функцию нужно вызвать для отписки
+func (b *InMemoryOrderEventBroker) Subscribe(userID uint) (<-chan models.OrderEvent, func()) {
+	ch := make(chan models.OrderEvent, orderEventSubscriberBuffer)
+
+	b.mu.Lock()
+	if b.subscribers[userID] == nil {
+		b.subscribers[userID] = make(map[chan models.OrderEvent]struct{})
+	}
+	b.subscribers[userID][ch] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subscribers[userID], ch)
+			if len(b.subscribers[userID]) == 0 {
+				delete(b.subscribers, userID)
+			}
+			b.mu.Unlock()
+			close(ch)
+		})
+	}
+
+	return ch, unsubscribe
+}
+
+func (b *InMemoryOrderEventBroker) Listen(ctx context.Context) {
+	<-ctx.Done()
+}
+
+// dispatch Медленный подписчик не должен блокировать обработку заказов, поэтому событие для него отбрасывается
+func (b *InMemoryOrderEventBroker) dispatch(event models.OrderEvent) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for ch := range b.subscribers[event.UserID] {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
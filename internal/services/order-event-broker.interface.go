@@ -0,0 +1,13 @@
+package services
+
+import (
+	"context"
+
+	"github.com/ShukinDmitriy/gophermart/internal/models"
+)
+
+type OrderEventBrokerInterface interface {
+	Publish(event models.OrderEvent) error
+	Subscribe(userID uint) (<-chan models.OrderEvent, func())
+	Listen(ctx context.Context)
+}
@@ -0,0 +1,21 @@
+package services
+
+import (
+	"strconv"
+
+	"github.com/theplant/luhn"
+)
+
+// CheckOrderNumber Проверяет формат номера заказа и контрольную цифру по алгоритму Луна
+func CheckOrderNumber(number string) error {
+	orderNumber, err := strconv.Atoi(number)
+	if err != nil {
+		return ErrInvalidOrderFormat
+	}
+
+	if !luhn.Valid(orderNumber) {
+		return ErrInvalidOrderNumber
+	}
+
+	return nil
+}
@@ -0,0 +1,220 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+	"github.com/ShukinDmitriy/gophermart/internal/models"
+	"github.com/ShukinDmitriy/gophermart/internal/repositories"
+	"github.com/go-playground/validator/v10"
+)
+
+// uploadOrdersAttempts Сколько раз пакет пересобирается, если его номера загружают параллельно
+const uploadOrdersAttempts = 3
+
+// orderAccrualAttemptsLimit Сколько последних обращений к системе расчёта отдаётся в карточке заказа
+const orderAccrualAttemptsLimit = 50
+
+type OrderService struct {
+	orderRepository     repositories.OrderRepositoryInterface
+	operationRepository repositories.OperationRepositoryInterface
+	accrualService      AccrualServiceInterface
+	validate            *validator.Validate
+}
+
+func NewOrderService(
+	orderRepository repositories.OrderRepositoryInterface,
+	operationRepository repositories.OperationRepositoryInterface,
+	accrualService AccrualServiceInterface,
+) *OrderService {
+	return &OrderService{
+		orderRepository:     orderRepository,
+		operationRepository: operationRepository,
+		accrualService:      accrualService,
+		validate:            validator.New(validator.WithRequiredStructEnabled()),
+	}
+}
+
+// UploadOrder Загрузка номера заказа. Возвращает true, если заказ создан и отправлен на расчёт,
+// и false, если пользователь уже загружал этот номер
+func (s *OrderService) UploadOrder(ctx context.Context, userID uint, number string) (bool, error) {
+	if err := CheckOrderNumber(number); err != nil {
+		return false, err
+	}
+
+	orderRepository := s.orderRepository.WithContext(ctx)
+
+	existOrder, err := orderRepository.FindByNumber(number)
+	if err != nil {
+		return false, err
+	}
+
+	if existOrder != nil {
+		return false, checkOrderOwner(existOrder, userID)
+	}
+
+	order, err := orderRepository.Create(number, userID)
+	if errors.Is(err, repositories.ErrOrderAlreadyExists) {
+		// номер загружен параллельным запросом между проверкой и вставкой
+		existOrder, err = orderRepository.FindByNumber(number)
+		if err != nil {
+			return false, err
+		}
+		if existOrder == nil {
+			return false, repositories.ErrOrderAlreadyExists
+		}
+
+		return false, checkOrderOwner(existOrder, userID)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	s.accrualService.SendOrderToQueue(ctx, *order)
+
+	return true, nil
+}
+
+// UploadOrders Загрузка пакета номеров заказов. Корректные новые номера создаются одной транзакцией,
+// для каждого номера возвращается результат в порядке запроса
+func (s *OrderService) UploadOrders(ctx context.Context, userID uint, numbers []string) ([]models.CreateOrdersBatchResponse, error) {
+	results := make([]models.CreateOrdersBatchResponse, len(numbers))
+	var validNumbers []string
+	for i, number := range numbers {
+		results[i].Number = number
+		if CheckOrderNumber(number) != nil {
+			results[i].Result = models.CreateOrdersBatchResultInvalidFormat
+			continue
+		}
+		validNumbers = append(validNumbers, number)
+	}
+
+	orderRepository := s.orderRepository.WithContext(ctx)
+
+	var orders []*entities.Order
+	for attempt := 1; ; attempt++ {
+		newNumbers, err := classifyOrders(orderRepository, userID, validNumbers, results)
+		if err != nil {
+			return nil, err
+		}
+
+		orders, err = orderRepository.CreateBatch(newNumbers, userID)
+		// часть номеров загружена параллельным запросом: раскладываем номера заново
+		if errors.Is(err, repositories.ErrOrderAlreadyExists) && attempt < uploadOrdersAttempts {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		break
+	}
+
+	// Очередь ограничена, поэтому запрос не ждёт места в ней: заказы, которые не поместились,
+	// остаются в статусе NEW и попадают в расчёт через ProcessFailedOrders
+	for _, order := range orders {
+		if !s.accrualService.TrySendOrderToQueue(ctx, *order) {
+			break
+		}
+	}
+
+	return results, nil
+}
+
+// classifyOrders Результат для каждого корректного номера по уже загруженным заказам.
+// Возвращает номера, которые нужно создать
+func classifyOrders(
+	orderRepository repositories.OrderRepositoryInterface,
+	userID uint,
+	validNumbers []string,
+	results []models.CreateOrdersBatchResponse,
+) ([]string, error) {
+	existOrders, err := orderRepository.FindByNumbers(validNumbers)
+	if err != nil {
+		return nil, err
+	}
+
+	owners := make(map[string]uint, len(existOrders))
+	for _, existOrder := range existOrders {
+		owners[existOrder.Number] = existOrder.UserID
+	}
+
+	var newNumbers []string
+	for i := range results {
+		if results[i].Result == models.CreateOrdersBatchResultInvalidFormat {
+			continue
+		}
+
+		ownerID, exists := owners[results[i].Number]
+		switch {
+		case !exists:
+			results[i].Result = models.CreateOrdersBatchResultAccepted
+			owners[results[i].Number] = userID
+			newNumbers = append(newNumbers, results[i].Number)
+		case ownerID == userID:
+			results[i].Result = models.CreateOrdersBatchResultAlreadyUploaded
+		default:
+			results[i].Result = models.CreateOrdersBatchResultConflict
+		}
+	}
+
+	return newNumbers, nil
+}
+
+// checkOrderOwner Повторная загрузка своего заказа не ошибка, чужого конфликт
+func checkOrderOwner(order *entities.Order, userID uint) error {
+	if order.UserID != userID {
+		return ErrOrderOwnedByOtherUser
+	}
+
+	return nil
+}
+
+// GetOrder Заказ пользователя с историей статусов, обращениями к системе расчёта и операциями по счёту
+func (s *OrderService) GetOrder(userID uint, number string) (*models.GetOrderResponse, error) {
+	order, err := s.orderRepository.FindByNumber(number)
+	if err != nil {
+		return nil, err
+	}
+
+	if order == nil || order.UserID != userID {
+		return nil, ErrOrderNotFound
+	}
+
+	history, err := s.orderRepository.GetStatusHistory(order.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	attempts, err := s.orderRepository.GetAccrualAttempts(order.Number, orderAccrualAttemptsLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	operations, err := s.operationRepository.GetOperationsByOrderNumber(order.Number)
+	if err != nil {
+		return nil, err
+	}
+
+	res := models.MapOrderToGetOrderResponse(order, history, attempts, operations)
+
+	return &res, nil
+}
+
+// ListOrders Страница заказов пользователя. Без параметров возвращает первые GetOrdersDefaultLimit
+// заказов от самых новых к самым старым и курсор следующей страницы
+func (s *OrderService) ListOrders(userID uint, request models.GetOrdersRequest) (*models.GetOrdersPage, error) {
+	request.Normalize()
+
+	if err := s.validate.Struct(request); err != nil {
+		return nil, newValidationError(err)
+	}
+
+	filter, err := request.ToFilter(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.orderRepository.GetOrdersPage(filter)
+}
@@ -0,0 +1,14 @@
+package services
+
+import (
+	"context"
+
+	"github.com/ShukinDmitriy/gophermart/internal/models"
+)
+
+type OrderServiceInterface interface {
+	UploadOrder(ctx context.Context, userID uint, number string) (bool, error)
+	UploadOrders(ctx context.Context, userID uint, numbers []string) ([]models.CreateOrdersBatchResponse, error)
+	GetOrder(userID uint, number string) (*models.GetOrderResponse, error)
+	ListOrders(userID uint, request models.GetOrdersRequest) (*models.GetOrdersPage, error)
+}
@@ -0,0 +1,65 @@
+package services_test
+
+import (
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+	"github.com/ShukinDmitriy/gophermart/internal/models"
+	"github.com/ShukinDmitriy/gophermart/internal/services"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("InMemoryOrderEventBroker", func() {
+	var broker *services.InMemoryOrderEventBroker
+
+	event := models.OrderEvent{
+		Type:   models.OrderEventTypeStatusChanged,
+		UserID: 1,
+		Number: "12345678903",
+		Status: entities.OrderStatusProcessing,
+	}
+
+	BeforeEach(func() {
+		broker = services.NewInMemoryOrderEventBroker()
+	})
+
+	It("must deliver events only to subscribers of the user", func() {
+		// Arrange
+		events, unsubscribe := broker.Subscribe(1)
+		defer unsubscribe()
+		otherEvents, otherUnsubscribe := broker.Subscribe(2)
+		defer otherUnsubscribe()
+
+		// Act
+		err := broker.Publish(event)
+
+		// Assertions
+		Expect(err).NotTo(HaveOccurred())
+		Expect(events).To(Receive(Equal(event)))
+		Expect(otherEvents).NotTo(Receive())
+	})
+
+	It("must close the channel on unsubscribe", func() {
+		// Arrange
+		events, unsubscribe := broker.Subscribe(1)
+
+		// Act
+		unsubscribe()
+		unsubscribe()
+		err := broker.Publish(event)
+
+		// Assertions
+		Expect(err).NotTo(HaveOccurred())
+		Expect(events).To(BeClosed())
+	})
+
+	It("must not block on a slow subscriber", func() {
+		// Arrange
+		_, unsubscribe := broker.Subscribe(1)
+		defer unsubscribe()
+
+		// Act & Assertions
+		for i := 0; i < 100; i++ {
+			Expect(broker.Publish(event)).To(Succeed())
+		}
+	})
+})
@@ -0,0 +1,233 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+	"github.com/ShukinDmitriy/gophermart/internal/models"
+	apprepositories "github.com/ShukinDmitriy/gophermart/internal/repositories"
+	"github.com/ShukinDmitriy/gophermart/internal/services"
+	"github.com/ShukinDmitriy/gophermart/mocks/internal_/repositories"
+	mockservices "github.com/ShukinDmitriy/gophermart/mocks/internal_/services"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+var _ = Describe("OrderService", func() {
+	var orderRepository *repositories.OrderRepositoryInterface
+	var operationRepository *repositories.OperationRepositoryInterface
+	var accrualService *mockservices.AccrualServiceInterface
+	var service *services.OrderService
+
+	userID := uint(1)
+	otherUserID := uint(2)
+	orderNumber := "12345678903"
+	order := &entities.Order{
+		Model: gorm.Model{
+			ID: 3,
+		},
+		Number: orderNumber,
+		UserID: userID,
+		Status: entities.OrderStatusNew,
+	}
+
+	BeforeEach(func() {
+		orderRepository = new(repositories.OrderRepositoryInterface)
+		orderRepository.EXPECT().WithContext(mock.Anything).Return(orderRepository).Maybe()
+		operationRepository = new(repositories.OperationRepositoryInterface)
+		accrualService = new(mockservices.AccrualServiceInterface)
+		accrualService.EXPECT().SendOrderToQueue(mock.Anything, mock.Anything).Return()
+		service = services.NewOrderService(orderRepository, operationRepository, accrualService)
+	})
+
+	Describe("UploadOrder", func() {
+		It("must create a new order and send it for accrual", func() {
+			// Arrange
+			orderRepository.EXPECT().FindByNumber(orderNumber).Return(nil, nil)
+			orderRepository.EXPECT().Create(orderNumber, userID).Return(order, nil)
+
+			// Act
+			created, err := service.UploadOrder(context.Background(), userID, orderNumber)
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(created).To(BeTrue())
+			accrualService.AssertCalled(GinkgoT(), "SendOrderToQueue", mock.Anything, *order)
+		})
+
+		It("must not create the order again for the same user", func() {
+			// Arrange
+			orderRepository.EXPECT().FindByNumber(orderNumber).Return(order, nil)
+
+			// Act
+			created, err := service.UploadOrder(context.Background(), userID, orderNumber)
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(created).To(BeFalse())
+		})
+
+		It("must return a conflict if the order belongs to another user", func() {
+			// Arrange
+			orderRepository.EXPECT().FindByNumber(orderNumber).Return(order, nil)
+
+			// Act
+			_, err := service.UploadOrder(context.Background(), otherUserID, orderNumber)
+
+			// Assert
+			Expect(err).To(MatchError(services.ErrOrderOwnedByOtherUser))
+		})
+
+		It("must resolve a concurrent upload of the same number by the owner", func() {
+			// Arrange
+			orderRepository.EXPECT().FindByNumber(orderNumber).Return(nil, nil).Once()
+			orderRepository.EXPECT().Create(orderNumber, userID).Return(nil, apprepositories.ErrOrderAlreadyExists)
+			orderRepository.EXPECT().FindByNumber(orderNumber).Return(order, nil).Once()
+
+			// Act
+			created, err := service.UploadOrder(context.Background(), userID, orderNumber)
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(created).To(BeFalse())
+			accrualService.AssertNotCalled(GinkgoT(), "SendOrderToQueue", mock.Anything, mock.Anything)
+		})
+
+		It("must return a conflict if another user won the concurrent upload", func() {
+			// Arrange
+			orderRepository.EXPECT().FindByNumber(orderNumber).Return(nil, nil).Once()
+			orderRepository.EXPECT().Create(orderNumber, otherUserID).Return(nil, apprepositories.ErrOrderAlreadyExists)
+			orderRepository.EXPECT().FindByNumber(orderNumber).Return(order, nil).Once()
+
+			// Act
+			_, err := service.UploadOrder(context.Background(), otherUserID, orderNumber)
+
+			// Assert
+			Expect(err).To(MatchError(services.ErrOrderOwnedByOtherUser))
+		})
+
+		It("must reject numbers that are not digits or fail the Luhn check", func() {
+			_, err := service.UploadOrder(context.Background(), userID, "test")
+			Expect(err).To(MatchError(services.ErrInvalidOrderFormat))
+
+			_, err = service.UploadOrder(context.Background(), userID, "12345678904")
+			Expect(err).To(MatchError(services.ErrInvalidOrderNumber))
+		})
+	})
+
+	Describe("UploadOrders", func() {
+		It("must return a result for every number in the request order", func() {
+			// Arrange
+			newOrder := &entities.Order{Number: "9278923470", UserID: userID}
+			orderRepository.EXPECT().FindByNumbers([]string{orderNumber, "2377225624", "9278923470", "9278923470"}).
+				Return([]*entities.Order{order, {Number: "2377225624", UserID: otherUserID}}, nil)
+			orderRepository.EXPECT().CreateBatch([]string{"9278923470"}, userID).Return([]*entities.Order{newOrder}, nil)
+			accrualService.EXPECT().TrySendOrderToQueue(mock.Anything, *newOrder).Return(true)
+
+			// Act
+			results, err := service.UploadOrders(context.Background(), userID, []string{orderNumber, "12345678904", "2377225624", "9278923470", "9278923470"})
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results).To(Equal([]models.CreateOrdersBatchResponse{
+				{Number: orderNumber, Result: models.CreateOrdersBatchResultAlreadyUploaded},
+				{Number: "12345678904", Result: models.CreateOrdersBatchResultInvalidFormat},
+				{Number: "2377225624", Result: models.CreateOrdersBatchResultConflict},
+				{Number: "9278923470", Result: models.CreateOrdersBatchResultAccepted},
+				{Number: "9278923470", Result: models.CreateOrdersBatchResultAlreadyUploaded},
+			}))
+		})
+
+		It("must classify the batch again when its numbers are uploaded concurrently", func() {
+			// Arrange
+			numbers := []string{orderNumber, "9278923470"}
+			newOrder := &entities.Order{Number: "9278923470", UserID: userID}
+			orderRepository.EXPECT().FindByNumbers(numbers).Return(nil, nil).Once()
+			orderRepository.EXPECT().CreateBatch(numbers, userID).Return(nil, apprepositories.ErrOrderAlreadyExists)
+			orderRepository.EXPECT().FindByNumbers(numbers).Return([]*entities.Order{{Number: orderNumber, UserID: otherUserID}}, nil).Once()
+			orderRepository.EXPECT().CreateBatch([]string{"9278923470"}, userID).Return([]*entities.Order{newOrder}, nil)
+			accrualService.EXPECT().TrySendOrderToQueue(mock.Anything, *newOrder).Return(true)
+
+			// Act
+			results, err := service.UploadOrders(context.Background(), userID, numbers)
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results).To(Equal([]models.CreateOrdersBatchResponse{
+				{Number: orderNumber, Result: models.CreateOrdersBatchResultConflict},
+				{Number: "9278923470", Result: models.CreateOrdersBatchResultAccepted},
+			}))
+		})
+
+		It("must leave the orders that do not fit in the queue for the retry", func() {
+			// Arrange
+			numbers := []string{orderNumber, "9278923470"}
+			first := &entities.Order{Number: orderNumber, UserID: userID}
+			second := &entities.Order{Number: "9278923470", UserID: userID}
+			orderRepository.EXPECT().FindByNumbers(numbers).Return(nil, nil)
+			orderRepository.EXPECT().CreateBatch(numbers, userID).Return([]*entities.Order{first, second}, nil)
+			accrualService.EXPECT().TrySendOrderToQueue(mock.Anything, *first).Return(false)
+
+			// Act
+			results, err := service.UploadOrders(context.Background(), userID, numbers)
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results[0].Result).To(Equal(models.CreateOrdersBatchResultAccepted))
+			Expect(results[1].Result).To(Equal(models.CreateOrdersBatchResultAccepted))
+			accrualService.AssertNotCalled(GinkgoT(), "TrySendOrderToQueue", mock.Anything, *second)
+		})
+
+		It("must return the repository error", func() {
+			// Arrange
+			orderRepository.EXPECT().FindByNumbers([]string{orderNumber}).Return(nil, errors.New("test error"))
+
+			// Act
+			_, err := service.UploadOrders(context.Background(), userID, []string{orderNumber})
+
+			// Assert
+			Expect(err).To(MatchError("test error"))
+		})
+	})
+
+	Describe("GetOrder", func() {
+		It("must return the order with its history", func() {
+			// Arrange
+			orderRepository.EXPECT().FindByNumber(orderNumber).Return(order, nil)
+			orderRepository.EXPECT().GetStatusHistory(order.ID).Return(nil, nil)
+			orderRepository.EXPECT().GetAccrualAttempts(orderNumber, 50).Return(nil, nil)
+			operationRepository.EXPECT().GetOperationsByOrderNumber(orderNumber).Return(nil, nil)
+
+			// Act
+			res, err := service.GetOrder(userID, orderNumber)
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res.Number).To(Equal(orderNumber))
+		})
+
+		It("must not disclose an order of another user", func() {
+			// Arrange
+			orderRepository.EXPECT().FindByNumber(orderNumber).Return(order, nil)
+
+			// Act
+			_, err := service.GetOrder(otherUserID, orderNumber)
+
+			// Assert
+			Expect(err).To(MatchError(services.ErrOrderNotFound))
+		})
+	})
+
+	Describe("ListOrders", func() {
+		It("must reject an unknown status", func() {
+			// Act
+			_, err := service.ListOrders(userID, models.GetOrdersRequest{Status: []string{"DONE"}})
+
+			// Assert
+			Expect(err).To(MatchError(services.ErrValidation))
+		})
+	})
+})
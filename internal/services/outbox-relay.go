@@ -0,0 +1,162 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+	"github.com/ShukinDmitriy/gophermart/internal/metrics"
+	"github.com/ShukinDmitriy/gophermart/internal/models"
+	"github.com/ShukinDmitriy/gophermart/internal/repositories"
+	"go.uber.org/zap"
+)
+
+const (
+	// outboxBatchSize событий, забираемых за один проход
+	outboxBatchSize = 100
+	// outboxPublishTimeout на публикацию пакета
+	outboxPublishTimeout = 30 * time.Second
+	// outboxLease на это время события откладываются на время публикации. Больше outboxPublishTimeout,
+	// поэтому повтор начнётся, только если экземпляр, забравший события, упал
+	outboxLease = 2 * time.Minute
+	// outboxMaxBackoff наибольшая пауза между попытками. Попытки не ограничены:
+	// пропущенное событие нарушило бы порядок событий агрегата
+	outboxMaxBackoff = 5 * time.Minute
+	// outboxCleanupInterval период удаления опубликованных событий
+	outboxCleanupInterval = time.Hour
+	// outboxMaxErrorLength ошибки длиннее обрезаются перед записью
+	outboxMaxErrorLength = 512
+)
+
+// OutboxOptions Настройки публикации доменных событий
+type OutboxOptions struct {
+	// PollInterval период проверки новых событий
+	PollInterval time.Duration
+	// RetryBackoff пауза перед второй попыткой, перед каждой следующей удваивается
+	RetryBackoff time.Duration
+	// Retention столько опубликованные события хранятся в таблице
+	Retention time.Duration
+}
+
+// OutboxRelay Публикует события из outbox_events получателю. Событие помечается опубликованным
+// только после подтверждения получателя, поэтому доставка не реже одного раза: после сбоя
+// получатель может увидеть событие повторно с тем же ID
+type OutboxRelay struct {
+	options          OutboxOptions
+	outboxRepository repositories.OutboxRepositoryInterface
+	sink             EventSinkInterface
+	metrics          *metrics.Metrics
+	logger           *zap.Logger
+}
+
+func NewOutboxRelay(
+	options OutboxOptions,
+	outboxRepository repositories.OutboxRepositoryInterface,
+	sink EventSinkInterface,
+	metrics *metrics.Metrics,
+	logger *zap.Logger,
+) *OutboxRelay {
+	return &OutboxRelay{
+		options:          options,
+		outboxRepository: outboxRepository,
+		sink:             sink,
+		metrics:          metrics,
+		logger:           logger.Named("outbox"),
+	}
+}
+
+// Run Публикует события, пока не отменён ctx, и удаляет старые опубликованные
+func (r *OutboxRelay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.options.PollInterval)
+	defer ticker.Stop()
+	cleanup := time.NewTicker(outboxCleanupInterval)
+	defer cleanup.Stop()
+
+	for {
+		// полный пакет значит, что готовых к публикации событий может быть больше
+		for r.RelayPending(ctx) == outboxBatchSize {
+		}
+		r.updatePending(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-cleanup.C:
+			r.DeletePublished(ctx)
+		}
+	}
+}
+
+// RelayPending Одна попытка публикации первых неопубликованных событий агрегатов, не больше outboxBatchSize.
+// В пакете не больше одного события каждого агрегата. Возвращает число забранных событий
+func (r *OutboxRelay) RelayPending(ctx context.Context) int {
+	events, err := r.outboxRepository.WithContext(ctx).ClaimEvents(time.Now(), outboxLease, outboxBatchSize)
+	if err != nil {
+		r.logger.Error("cannot claim outbox events", zap.Error(err))
+		return 0
+	}
+	if len(events) == 0 {
+		return 0
+	}
+
+	domainEvents := make([]models.DomainEvent, 0, len(events))
+	for _, event := range events {
+		domainEvents = append(domainEvents, models.MapDomainEvent(event))
+	}
+
+	publishCtx, cancel := context.WithTimeout(ctx, outboxPublishTimeout)
+	err = r.sink.Publish(publishCtx, domainEvents)
+	cancel()
+
+	now := time.Now()
+	result := "published"
+	if err != nil {
+		result = "retry"
+		r.logger.Warn("cannot publish outbox events", zap.Int("events", len(events)), zap.Error(err))
+	}
+	r.metrics.OutboxPublishAttempt(result, len(events))
+
+	// итог пишется и после отмены ctx, иначе события повторятся только после outboxLease
+	repository := r.outboxRepository.WithContext(context.WithoutCancel(ctx))
+	for _, event := range events {
+		r.complete(event, now, err)
+		if err := repository.UpdateEvent(event); err != nil {
+			r.logger.Error("cannot save outbox event", zap.Uint("event_id", event.ID), zap.Error(err))
+		}
+	}
+
+	return len(events)
+}
+
+// DeletePublished Удаляет события, опубликованные раньше Retention назад
+func (r *OutboxRelay) DeletePublished(ctx context.Context) {
+	deleted, err := r.outboxRepository.WithContext(ctx).DeletePublished(time.Now().Add(-r.options.Retention))
+	if err != nil {
+		r.logger.Error("cannot delete published outbox events", zap.Error(err))
+		return
+	}
+	if deleted > 0 {
+		r.logger.Info("published outbox events deleted", zap.Int64("deleted", deleted))
+	}
+}
+
+func (r *OutboxRelay) complete(event *entities.OutboxEvent, now time.Time, err error) {
+	if err == nil {
+		event.PublishedAt = &now
+		event.LastError = ""
+		return
+	}
+
+	event.NextAttemptAt = now.Add(backoff(r.options.RetryBackoff, outboxMaxBackoff, event.Attempts))
+	event.LastError = truncate(err.Error(), outboxMaxErrorLength)
+}
+
+func (r *OutboxRelay) updatePending(ctx context.Context) {
+	pending, err := r.outboxRepository.WithContext(ctx).CountPending()
+	if err != nil {
+		r.logger.Error("cannot count pending outbox events", zap.Error(err))
+		return
+	}
+	r.metrics.SetOutboxPending(pending)
+}
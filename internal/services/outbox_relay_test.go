@@ -0,0 +1,113 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+	"github.com/ShukinDmitriy/gophermart/internal/metrics"
+	"github.com/ShukinDmitriy/gophermart/internal/models"
+	apprepositories "github.com/ShukinDmitriy/gophermart/internal/repositories"
+	"github.com/ShukinDmitriy/gophermart/internal/repositories/memory"
+	"github.com/ShukinDmitriy/gophermart/internal/services"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+)
+
+var _ = Describe("OutboxRelay", func() {
+	orderNumber := "12345678903"
+
+	var storage *apprepositories.Storage
+	var sink *services.InMemoryEventSink
+	var relay *services.OutboxRelay
+	var userID uint
+
+	BeforeEach(func() {
+		// Arrange
+		storage = memory.NewStorage()
+		sink = services.NewInMemoryEventSink()
+		relay = services.NewOutboxRelay(
+			services.OutboxOptions{PollInterval: 10 * time.Millisecond, RetryBackoff: 20 * time.Millisecond, Retention: time.Hour},
+			storage.Outbox,
+			sink,
+			metrics.New(),
+			zap.NewNop(),
+		)
+
+		user, err := storage.Users.Create(models.UserRegisterRequest{Login: "user", Password: "password"})
+		Expect(err).NotTo(HaveOccurred())
+		userID = user.ID
+		_, err = storage.Orders.Create(orderNumber, userID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(storage.Orders.UpdateOrderByAccrualOrder(&models.AccrualOrderResponse{Order: orderNumber, Status: entities.OrderStatusProcessing})).To(Succeed())
+	})
+
+	orderEvents := func() []entities.DomainEventType {
+		var types []entities.DomainEventType
+		for _, event := range sink.Events() {
+			if event.Key() == "order:"+orderNumber {
+				types = append(types, event.Type)
+			}
+		}
+
+		return types
+	}
+
+	It("must publish the events of an aggregate in order", func() {
+		// Act
+		ctx, cancel := context.WithCancel(context.Background())
+		DeferCleanup(cancel)
+		go relay.Run(ctx)
+
+		// Assert
+		Eventually(sink.Events).Should(HaveLen(3))
+		Expect(orderEvents()).To(Equal([]entities.DomainEventType{entities.DomainEventOrderUploaded, entities.DomainEventOrderStatusChanged}))
+		Eventually(storage.Outbox.CountPending).Should(BeZero())
+	})
+
+	It("must publish the same events again after a sink failure", func() {
+		// Arrange
+		sink.Fail(errors.New("sink unavailable"))
+
+		// Act
+		failed := relay.RelayPending(context.Background())
+		sink.Fail(nil)
+		early := relay.RelayPending(context.Background())
+		time.Sleep(30 * time.Millisecond)
+		retried := relay.RelayPending(context.Background())
+		next := relay.RelayPending(context.Background())
+
+		// Assert
+		Expect(failed).To(Equal(2))
+		// до конца паузы события не публикуются
+		Expect(early).To(BeZero())
+		Expect(retried).To(Equal(2))
+		Expect(next).To(Equal(1))
+		Expect(orderEvents()).To(Equal([]entities.DomainEventType{entities.DomainEventOrderUploaded, entities.DomainEventOrderStatusChanged}))
+		pending, err := storage.Outbox.CountPending()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pending).To(BeZero())
+	})
+
+	It("must delete only published events after the retention", func() {
+		// Arrange
+		for relay.RelayPending(context.Background()) > 0 {
+		}
+		_, err := storage.Orders.Create("9278923470", userID)
+		Expect(err).NotTo(HaveOccurred())
+		expired := services.NewOutboxRelay(services.OutboxOptions{Retention: -time.Minute}, storage.Outbox, sink, metrics.New(), zap.NewNop())
+
+		// Act
+		expired.DeletePublished(context.Background())
+
+		// Assert
+		left, err := storage.Outbox.DeletePublished(time.Now().Add(time.Hour))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(left).To(BeZero())
+		pending, err := storage.Outbox.CountPending()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pending).To(BeEquivalentTo(1))
+	})
+})
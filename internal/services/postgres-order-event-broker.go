@@ -0,0 +1,102 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/ShukinDmitriy/gophermart/internal/models"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	orderEventsChannel        = "order_events"
+	orderEventsReconnectDelay = 5 * time.Second
+)
+
+type postgresOrderEventPayload struct {
+	UserID uint              `json:"user_id"`
+	Event  models.OrderEvent `json:"event"`
+}
+
+// PostgresOrderEventBroker Рассылка событий по заказам между репликами через LISTEN/NOTIFY.
+// Каждая реплика слушает канал и раздаёт полученные события своим подписчикам.
+type PostgresOrderEventBroker struct {
+	db          *gorm.DB
+	databaseURI string
+	local       *InMemoryOrderEventBroker
+	logger      *zap.Logger
+}
+
+func NewPostgresOrderEventBroker(db *gorm.DB, databaseURI string, logger *zap.Logger) *PostgresOrderEventBroker {
+	return &PostgresOrderEventBroker{
+		db:          db,
+		databaseURI: databaseURI,
+		local:       NewInMemoryOrderEventBroker(),
+		logger:      logger.Named("order_events"),
+	}
+}
+
+func (b *PostgresOrderEventBroker) Publish(event models.OrderEvent) error {
+	payload, err := json.Marshal(postgresOrderEventPayload{
+		UserID: event.UserID,
+		Event:  event,
+	})
+	if err != nil {
+		return err
+	}
+
+	return b.db.Exec("select pg_notify(?, ?)", orderEventsChannel, string(payload)).Error
+}
+
+func (b *PostgresOrderEventBroker) Subscribe(userID uint) (<-chan models.OrderEvent, func()) {
+	return b.local.Subscribe(userID)
+}
+
+// Listen Слушает канал до отмены контекста, переподключаясь при обрыве соединения
+func (b *PostgresOrderEventBroker) Listen(ctx context.Context) {
+	for {
+		err := b.listen(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+
+		b.logger.Error("order events listener stopped", zap.Error(err))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(orderEventsReconnectDelay):
+		}
+	}
+}
+
+func (b *PostgresOrderEventBroker) listen(ctx context.Context) error {
+	conn, err := pgx.Connect(ctx, b.databaseURI)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(context.Background())
+
+	_, err = conn.Exec(ctx, "listen "+orderEventsChannel)
+	if err != nil {
+		return err
+	}
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+
+		var payload postgresOrderEventPayload
+		if err = json.Unmarshal([]byte(notification.Payload), &payload); err != nil {
+			continue
+		}
+
+		payload.Event.UserID = payload.UserID
+		b.local.dispatch(payload.Event)
+	}
+}
@@ -0,0 +1,306 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path"
+	"time"
+
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+	"github.com/ShukinDmitriy/gophermart/internal/logging"
+	"github.com/ShukinDmitriy/gophermart/internal/metrics"
+	"github.com/ShukinDmitriy/gophermart/internal/models"
+	"github.com/ShukinDmitriy/gophermart/internal/repositories"
+	"go.uber.org/zap"
+)
+
+// sumTolerance суммы хранятся как decimal(32, 2), поэтому сравниваем с точностью до копейки
+const sumTolerance = 0.005
+
+type ReconciliationService struct {
+	autoCorrect              bool
+	reportDir                string
+	accrualService           AccrualServiceInterface
+	accountRepository        repositories.AccountRepositoryInterface
+	operationRepository      repositories.OperationRepositoryInterface
+	orderRepository          repositories.OrderRepositoryInterface
+	reconciliationRepository repositories.ReconciliationRepositoryInterface
+	metrics                  *metrics.Metrics
+	logger                   *zap.Logger
+}
+
+func NewReconciliationService(
+	autoCorrect bool,
+	reportDir string,
+	accrualService AccrualServiceInterface,
+	accountRepository repositories.AccountRepositoryInterface,
+	operationRepository repositories.OperationRepositoryInterface,
+	orderRepository repositories.OrderRepositoryInterface,
+	reconciliationRepository repositories.ReconciliationRepositoryInterface,
+	metrics *metrics.Metrics,
+	logger *zap.Logger,
+) *ReconciliationService {
+	return &ReconciliationService{
+		autoCorrect:              autoCorrect,
+		reportDir:                reportDir,
+		accrualService:           accrualService,
+		accountRepository:        accountRepository,
+		operationRepository:      operationRepository,
+		orderRepository:          orderRepository,
+		reconciliationRepository: reconciliationRepository,
+		metrics:                  metrics,
+		logger:                   logger.Named("reconciliation"),
+	}
+}
+
+// RunNightly Ежесуточная сверка заказов, обработанных за прошедшие сутки. Останавливается с отменой ctx
+func (rs *ReconciliationService) RunNightly(ctx context.Context) {
+	for {
+		now := time.Now()
+		to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()).AddDate(0, 0, 1)
+
+		timer := time.NewTimer(time.Until(to))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		report, err := rs.Reconcile(ctx, to.AddDate(0, 0, -1), to)
+		if err != nil {
+			rs.logger.Error("reconciliation failed", zap.Error(err))
+			continue
+		}
+
+		reportPath, err := rs.SaveReport(report)
+		if err != nil {
+			rs.logger.Error("cannot save reconciliation report", zap.Error(err))
+			continue
+		}
+
+		rs.logger.Info("reconciliation finished", zap.String("report", reportPath))
+	}
+}
+
+// Reconcile Сверка начислений с системой расчёта за период [from, to).
+// Безопасными для автоматического исправления считаются расхождения,
+// не уменьшающие баланс пользователя:
+//   - отсутствующая операция начисления по обработанному заказу;
+//   - неверное значение orders.accrual, если операция начисления совпадает с системой расчёта или отсутствует.
+//
+// Расхождения статусов и сумм уже проведённых операций только попадают в отчёт.
+// Ошибка сверки одного заказа попадает в отчёт расхождением check_failed, остальные заказы проверяются дальше.
+// Запуск всегда завершается статусом: completed, если проверены все заказы, иначе failed.
+func (rs *ReconciliationService) Reconcile(ctx context.Context, from time.Time, to time.Time) (*models.ReconciliationReport, error) {
+	run := &entities.ReconciliationRun{
+		PeriodFrom:  from,
+		PeriodTo:    to,
+		AutoCorrect: rs.autoCorrect,
+		Status:      entities.ReconciliationRunStatusRunning,
+	}
+	err := rs.reconciliationRepository.WithContext(ctx).CreateRun(run)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.ReconciliationReport{
+		Discrepancies: []entities.ReconciliationDiscrepancy{},
+	}
+
+	err = rs.reconcileOrders(ctx, run, report)
+
+	finishedAt := time.Now()
+	run.FinishedAt = &finishedAt
+	run.Discrepancies = len(report.Discrepancies)
+	run.Status = entities.ReconciliationRunStatusCompleted
+	if err != nil {
+		run.Status = entities.ReconciliationRunStatusFailed
+	}
+
+	// итог запуска сохраняется и после отмены ctx, иначе прерванная сверка останется в статусе running
+	if updateErr := rs.reconciliationRepository.WithContext(context.WithoutCancel(ctx)).UpdateRun(run); updateErr != nil {
+		return nil, errors.Join(err, updateErr)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	report.Run = *run
+
+	return report, nil
+}
+
+// reconcileOrders Сверка заказов периода. Ошибку возвращает, только если сверку пришлось прервать
+func (rs *ReconciliationService) reconcileOrders(
+	ctx context.Context,
+	run *entities.ReconciliationRun,
+	report *models.ReconciliationReport,
+) error {
+	reconciliationRepository := rs.reconciliationRepository.WithContext(ctx)
+	orders, err := rs.orderRepository.WithContext(ctx).GetProcessedOrdersByPeriod(run.PeriodFrom, run.PeriodTo)
+	if err != nil {
+		return err
+	}
+
+	for _, order := range orders {
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+
+		discrepancies, err := rs.reconcileOrder(ctx, *order)
+		if err != nil {
+			rs.logger.Error("cannot reconcile order", logging.OrderNumber(order.Number), zap.Error(err))
+			discrepancies = []*entities.ReconciliationDiscrepancy{{
+				OrderNumber:  order.Number,
+				Type:         entities.ReconciliationDiscrepancyTypeCheckFailed,
+				LocalStatus:  order.Status,
+				LocalAccrual: order.Accrual,
+			}}
+		}
+
+		for _, discrepancy := range discrepancies {
+			discrepancy.RunID = run.ID
+			err = reconciliationRepository.CreateDiscrepancy(discrepancy)
+			if err != nil {
+				// расхождение остаётся в отчёте, даже если не сохранилось в базе
+				rs.logger.Error("cannot save reconciliation discrepancy", logging.OrderNumber(order.Number), zap.Error(err))
+			}
+
+			if discrepancy.Corrected {
+				run.Corrected++
+			}
+			report.Discrepancies = append(report.Discrepancies, *discrepancy)
+		}
+
+		run.OrdersChecked++
+	}
+
+	return nil
+}
+
+// SaveReport Сохранение отчёта о сверке в CSV-файл
+func (rs *ReconciliationService) SaveReport(report *models.ReconciliationReport) (string, error) {
+	err := os.MkdirAll(rs.reportDir, 0o755)
+	if err != nil {
+		return "", err
+	}
+
+	reportPath := path.Join(rs.reportDir, fmt.Sprintf(
+		"reconciliation_%d_%s.csv",
+		report.Run.ID,
+		report.Run.PeriodFrom.Format("20060102"),
+	))
+
+	file, err := os.Create(reportPath)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	err = report.WriteCSV(file)
+	if err != nil {
+		return "", err
+	}
+
+	return reportPath, nil
+}
+
+func (rs *ReconciliationService) reconcileOrder(ctx context.Context, order entities.Order) ([]*entities.ReconciliationDiscrepancy, error) {
+	discrepancy := func(discrepancyType entities.ReconciliationDiscrepancyType, remote *models.AccrualOrderResponse) *entities.ReconciliationDiscrepancy {
+		return &entities.ReconciliationDiscrepancy{
+			OrderNumber:   order.Number,
+			Type:          discrepancyType,
+			LocalStatus:   order.Status,
+			RemoteStatus:  remote.Status,
+			LocalAccrual:  order.Accrual,
+			RemoteAccrual: remote.Accrual,
+		}
+	}
+
+	remote, err := rs.accrualService.FetchOrder(ctx, order.Number)
+	if err != nil {
+		return []*entities.ReconciliationDiscrepancy{
+			discrepancy(entities.ReconciliationDiscrepancyTypeRemoteUnavailable, &models.AccrualOrderResponse{}),
+		}, nil
+	}
+
+	if remote.Status != order.Status {
+		return []*entities.ReconciliationDiscrepancy{
+			discrepancy(entities.ReconciliationDiscrepancyTypeStatusMismatch, remote),
+		}, nil
+	}
+
+	if order.Status != entities.OrderStatusProcessed {
+		return nil, nil
+	}
+
+	operation, err := rs.operationRepository.WithContext(ctx).FindAccrualByOrderNumber(order.Number)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*entities.ReconciliationDiscrepancy
+
+	if !sumEqual(order.Accrual, remote.Accrual) {
+		d := discrepancy(entities.ReconciliationDiscrepancyTypeAccrualMismatch, remote)
+		if operation != nil {
+			d.OperationSum = &operation.Sum
+		}
+
+		if rs.autoCorrect && (operation == nil || sumEqual(operation.Sum, remote.Accrual)) {
+			err = rs.orderRepository.WithContext(ctx).CorrectOrderAccrual(order.Number, remote.Accrual)
+			if err != nil {
+				rs.logger.Error("cannot correct order accrual", logging.OrderNumber(order.Number), zap.Error(err))
+			} else {
+				d.Corrected = true
+			}
+		}
+
+		result = append(result, d)
+	}
+
+	if operation == nil && remote.Accrual > 0 {
+		d := discrepancy(entities.ReconciliationDiscrepancyTypeOperationMissing, remote)
+
+		if rs.autoCorrect {
+			d.Corrected = rs.createMissingAccrual(ctx, order, remote)
+		}
+
+		result = append(result, d)
+	}
+
+	if operation != nil && !sumEqual(operation.Sum, remote.Accrual) {
+		d := discrepancy(entities.ReconciliationDiscrepancyTypeOperationMismatch, remote)
+		d.OperationSum = &operation.Sum
+
+		result = append(result, d)
+	}
+
+	return result, nil
+}
+
+func (rs *ReconciliationService) createMissingAccrual(ctx context.Context, order entities.Order, remote *models.AccrualOrderResponse) bool {
+	bonusAccount, err := rs.accountRepository.WithContext(ctx).FindByUserID(order.UserID, entities.AccountTypeBonus)
+	if err != nil || bonusAccount == nil {
+		rs.logger.Error("cannot find bonus account", logging.OrderNumber(order.Number), logging.UserID(order.UserID), zap.Error(err))
+		return false
+	}
+
+	err = rs.operationRepository.WithContext(ctx).CreateAccrual(bonusAccount.ID, remote.Order, remote.Accrual)
+	if err != nil {
+		rs.logger.Error("cannot create missing accrual", logging.OrderNumber(order.Number), logging.UserID(order.UserID), zap.Error(err))
+		return false
+	}
+
+	rs.metrics.PointsAccrued(remote.Accrual)
+
+	return true
+}
+
+func sumEqual(a float32, b float32) bool {
+	return math.Abs(float64(a)-float64(b)) < sumTolerance
+}
@@ -0,0 +1,14 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/ShukinDmitriy/gophermart/internal/models"
+)
+
+type ReconciliationServiceInterface interface {
+	Reconcile(ctx context.Context, from time.Time, to time.Time) (*models.ReconciliationReport, error)
+	SaveReport(report *models.ReconciliationReport) (string, error)
+	RunNightly(ctx context.Context)
+}
@@ -0,0 +1,279 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+	"github.com/ShukinDmitriy/gophermart/internal/metrics"
+	"github.com/ShukinDmitriy/gophermart/internal/models"
+	"github.com/ShukinDmitriy/gophermart/internal/services"
+	"github.com/ShukinDmitriy/gophermart/mocks/internal_/repositories"
+	mockservices "github.com/ShukinDmitriy/gophermart/mocks/internal_/services"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+var _ = Describe("ReconciliationService", func() {
+	var accrualService *mockservices.AccrualServiceInterface
+	var accountRepository *repositories.AccountRepositoryInterface
+	var operationRepository *repositories.OperationRepositoryInterface
+	var orderRepository *repositories.OrderRepositoryInterface
+	var reconciliationRepository *repositories.ReconciliationRepositoryInterface
+
+	userID := uint(1)
+	accountID := uint(2)
+	orderNumber := "61508349208"
+	from := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 1)
+	order := &entities.Order{
+		Number:  orderNumber,
+		UserID:  userID,
+		Status:  entities.OrderStatusProcessed,
+		Accrual: 500,
+	}
+
+	newService := func(autoCorrect bool) *services.ReconciliationService {
+		return services.NewReconciliationService(
+			autoCorrect,
+			"",
+			accrualService,
+			accountRepository,
+			operationRepository,
+			orderRepository,
+			reconciliationRepository,
+			metrics.New(),
+			zap.NewNop(),
+		)
+	}
+
+	BeforeEach(func() {
+		accrualService = new(mockservices.AccrualServiceInterface)
+		accountRepository = new(repositories.AccountRepositoryInterface)
+		operationRepository = new(repositories.OperationRepositoryInterface)
+		orderRepository = new(repositories.OrderRepositoryInterface)
+		reconciliationRepository = new(repositories.ReconciliationRepositoryInterface)
+		accountRepository.EXPECT().WithContext(mock.Anything).Return(accountRepository).Maybe()
+		operationRepository.EXPECT().WithContext(mock.Anything).Return(operationRepository).Maybe()
+		orderRepository.EXPECT().WithContext(mock.Anything).Return(orderRepository).Maybe()
+		reconciliationRepository.EXPECT().WithContext(mock.Anything).Return(reconciliationRepository).Maybe()
+
+		reconciliationRepository.EXPECT().CreateRun(mock.Anything).RunAndReturn(func(run *entities.ReconciliationRun) error {
+			run.ID = 10
+			return nil
+		})
+		reconciliationRepository.EXPECT().CreateDiscrepancy(mock.Anything).Return(nil)
+		reconciliationRepository.EXPECT().UpdateRun(mock.Anything).Return(nil)
+		orderRepository.EXPECT().GetProcessedOrdersByPeriod(from, to).Return([]*entities.Order{order}, nil)
+	})
+
+	Describe("Reconcile", func() {
+		It("must not report anything if the order matches", func() {
+			// Arrange
+			accrualService.EXPECT().FetchOrder(mock.Anything, orderNumber).Return(&models.AccrualOrderResponse{
+				Order:   orderNumber,
+				Status:  entities.OrderStatusProcessed,
+				Accrual: 500,
+			}, nil)
+			operationRepository.EXPECT().FindAccrualByOrderNumber(orderNumber).Return(&entities.Operation{Sum: 500}, nil)
+
+			// Act
+			report, err := newService(true).Reconcile(context.Background(), from, to)
+
+			// Assertions
+			Expect(err).NotTo(HaveOccurred())
+			Expect(report.Run.ID).To(Equal(uint(10)))
+			Expect(report.Run.OrdersChecked).To(Equal(1))
+			Expect(report.Run.Status).To(Equal(entities.ReconciliationRunStatusCompleted))
+			Expect(report.Discrepancies).To(BeEmpty())
+		})
+
+		It("must pass the run context to the accrual system and the repositories", func() {
+			// Arrange
+			type ctxKey struct{}
+			ctx := context.WithValue(context.Background(), ctxKey{}, "run")
+			withRunContext := mock.MatchedBy(func(c context.Context) bool {
+				return c.Value(ctxKey{}) == "run"
+			})
+			accrualService.EXPECT().FetchOrder(withRunContext, orderNumber).Return(&models.AccrualOrderResponse{
+				Order:   orderNumber,
+				Status:  entities.OrderStatusProcessed,
+				Accrual: 500,
+			}, nil)
+			operationRepository.EXPECT().FindAccrualByOrderNumber(orderNumber).Return(&entities.Operation{Sum: 500}, nil)
+
+			// Act
+			_, err := newService(true).Reconcile(ctx, from, to)
+
+			// Assertions
+			Expect(err).NotTo(HaveOccurred())
+			accrualService.AssertExpectations(GinkgoT())
+			orderRepository.AssertCalled(GinkgoT(), "WithContext", withRunContext)
+			operationRepository.AssertCalled(GinkgoT(), "WithContext", withRunContext)
+			reconciliationRepository.AssertCalled(GinkgoT(), "WithContext", withRunContext)
+		})
+
+		It("must create the missing accrual if auto-correction is enabled", func() {
+			// Arrange
+			remote := &models.AccrualOrderResponse{
+				Order:   orderNumber,
+				Status:  entities.OrderStatusProcessed,
+				Accrual: 500,
+			}
+			accrualService.EXPECT().FetchOrder(mock.Anything, orderNumber).Return(remote, nil)
+			operationRepository.EXPECT().FindAccrualByOrderNumber(orderNumber).Return(nil, nil)
+			accountRepository.EXPECT().FindByUserID(userID, entities.AccountTypeBonus).Return(&entities.Account{
+				Model: gorm.Model{ID: accountID},
+			}, nil)
+			operationRepository.EXPECT().CreateAccrual(accountID, orderNumber, remote.Accrual).Return(nil)
+
+			// Act
+			report, err := newService(true).Reconcile(context.Background(), from, to)
+
+			// Assertions
+			Expect(err).NotTo(HaveOccurred())
+			Expect(report.Discrepancies).To(HaveLen(1))
+			Expect(report.Discrepancies[0].Type).To(Equal(entities.ReconciliationDiscrepancyTypeOperationMissing))
+			Expect(report.Discrepancies[0].Corrected).To(BeTrue())
+			Expect(report.Run.Corrected).To(Equal(1))
+		})
+
+		It("must only report the missing accrual if auto-correction is disabled", func() {
+			// Arrange
+			accrualService.EXPECT().FetchOrder(mock.Anything, orderNumber).Return(&models.AccrualOrderResponse{
+				Order:   orderNumber,
+				Status:  entities.OrderStatusProcessed,
+				Accrual: 500,
+			}, nil)
+			operationRepository.EXPECT().FindAccrualByOrderNumber(orderNumber).Return(nil, nil)
+
+			// Act
+			report, err := newService(false).Reconcile(context.Background(), from, to)
+
+			// Assertions
+			Expect(err).NotTo(HaveOccurred())
+			Expect(report.Discrepancies).To(HaveLen(1))
+			Expect(report.Discrepancies[0].Corrected).To(BeFalse())
+			operationRepository.AssertNotCalled(GinkgoT(), "CreateAccrual", mock.Anything, mock.Anything, mock.Anything)
+		})
+
+		It("must never correct an accrual operation with a different sum", func() {
+			// Arrange
+			accrualService.EXPECT().FetchOrder(mock.Anything, orderNumber).Return(&models.AccrualOrderResponse{
+				Order:   orderNumber,
+				Status:  entities.OrderStatusProcessed,
+				Accrual: 400,
+			}, nil)
+			operationRepository.EXPECT().FindAccrualByOrderNumber(orderNumber).Return(&entities.Operation{Sum: 500}, nil)
+
+			// Act
+			report, err := newService(true).Reconcile(context.Background(), from, to)
+
+			// Assertions
+			Expect(err).NotTo(HaveOccurred())
+			Expect(report.Discrepancies).To(HaveLen(2))
+			Expect(report.Discrepancies[0].Type).To(Equal(entities.ReconciliationDiscrepancyTypeAccrualMismatch))
+			Expect(report.Discrepancies[0].Corrected).To(BeFalse())
+			Expect(report.Discrepancies[1].Type).To(Equal(entities.ReconciliationDiscrepancyTypeOperationMismatch))
+			Expect(report.Discrepancies[1].Corrected).To(BeFalse())
+			Expect(*report.Discrepancies[1].OperationSum).To(Equal(float32(500)))
+		})
+
+		It("must report a status mismatch", func() {
+			// Arrange
+			accrualService.EXPECT().FetchOrder(mock.Anything, orderNumber).Return(&models.AccrualOrderResponse{
+				Order:  orderNumber,
+				Status: entities.OrderStatusInvalid,
+			}, nil)
+
+			// Act
+			report, err := newService(true).Reconcile(context.Background(), from, to)
+
+			// Assertions
+			Expect(err).NotTo(HaveOccurred())
+			Expect(report.Discrepancies).To(HaveLen(1))
+			Expect(report.Discrepancies[0].Type).To(Equal(entities.ReconciliationDiscrepancyTypeStatusMismatch))
+			Expect(report.Discrepancies[0].RemoteStatus).To(Equal(entities.OrderStatusInvalid))
+		})
+
+		It("must report an unavailable accrual system", func() {
+			// Arrange
+			accrualService.EXPECT().FetchOrder(mock.Anything, orderNumber).Return(nil, errors.New("test error"))
+
+			// Act
+			report, err := newService(true).Reconcile(context.Background(), from, to)
+
+			// Assertions
+			Expect(err).NotTo(HaveOccurred())
+			Expect(report.Discrepancies).To(HaveLen(1))
+			Expect(report.Discrepancies[0].Type).To(Equal(entities.ReconciliationDiscrepancyTypeRemoteUnavailable))
+		})
+
+		It("must report an order that could not be checked and go on with the rest", func() {
+			// Arrange
+			other := &entities.Order{Number: "12345678903", UserID: userID, Status: entities.OrderStatusProcessed, Accrual: 100}
+			orderRepository = new(repositories.OrderRepositoryInterface)
+			orderRepository.EXPECT().WithContext(mock.Anything).Return(orderRepository).Maybe()
+			orderRepository.EXPECT().GetProcessedOrdersByPeriod(from, to).Return([]*entities.Order{order, other}, nil)
+			accrualService.EXPECT().FetchOrder(mock.Anything, orderNumber).Return(&models.AccrualOrderResponse{
+				Order:   orderNumber,
+				Status:  entities.OrderStatusProcessed,
+				Accrual: 500,
+			}, nil)
+			accrualService.EXPECT().FetchOrder(mock.Anything, other.Number).Return(&models.AccrualOrderResponse{
+				Order:   other.Number,
+				Status:  entities.OrderStatusProcessed,
+				Accrual: 100,
+			}, nil)
+			operationRepository.EXPECT().FindAccrualByOrderNumber(orderNumber).Return(nil, errors.New("test error"))
+			operationRepository.EXPECT().FindAccrualByOrderNumber(other.Number).Return(&entities.Operation{Sum: 100}, nil)
+
+			// Act
+			report, err := newService(true).Reconcile(context.Background(), from, to)
+
+			// Assertions
+			Expect(err).NotTo(HaveOccurred())
+			Expect(report.Run.OrdersChecked).To(Equal(2))
+			Expect(report.Run.Status).To(Equal(entities.ReconciliationRunStatusCompleted))
+			Expect(report.Discrepancies).To(HaveLen(1))
+			Expect(report.Discrepancies[0].OrderNumber).To(Equal(orderNumber))
+			Expect(report.Discrepancies[0].Type).To(Equal(entities.ReconciliationDiscrepancyTypeCheckFailed))
+		})
+
+		It("must finish the run as failed if the orders could not be loaded", func() {
+			// Arrange
+			orderRepository = new(repositories.OrderRepositoryInterface)
+			orderRepository.EXPECT().WithContext(mock.Anything).Return(orderRepository).Maybe()
+			orderRepository.EXPECT().GetProcessedOrdersByPeriod(from, to).Return(nil, errors.New("test error"))
+
+			// Act
+			_, err := newService(true).Reconcile(context.Background(), from, to)
+
+			// Assertions
+			Expect(err).To(MatchError("test error"))
+			reconciliationRepository.AssertCalled(GinkgoT(), "UpdateRun", mock.MatchedBy(func(run *entities.ReconciliationRun) bool {
+				return run.Status == entities.ReconciliationRunStatusFailed && run.FinishedAt != nil
+			}))
+		})
+
+		It("must finish the run as failed if it is cancelled", func() {
+			// Arrange
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			// Act
+			_, err := newService(true).Reconcile(ctx, from, to)
+
+			// Assertions
+			Expect(err).To(MatchError(context.Canceled))
+			accrualService.AssertNotCalled(GinkgoT(), "FetchOrder", mock.Anything, mock.Anything)
+			reconciliationRepository.AssertCalled(GinkgoT(), "UpdateRun", mock.MatchedBy(func(run *entities.ReconciliationRun) bool {
+				return run.Status == entities.ReconciliationRunStatusFailed && run.OrdersChecked == 0
+			}))
+		})
+	})
+})
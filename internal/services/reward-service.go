@@ -0,0 +1,167 @@
+package services
+
+import (
+	"time"
+
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+	"github.com/ShukinDmitriy/gophermart/internal/models"
+	"github.com/ShukinDmitriy/gophermart/internal/repositories"
+	"github.com/ShukinDmitriy/gophermart/internal/rewards"
+	"github.com/go-playground/validator/v10"
+)
+
+// RewardService Правила вознаграждения и составы заказов для локального расчёта начислений, см. RulesAccrualProvider
+type RewardService struct {
+	rewardRepository repositories.RewardRepositoryInterface
+	validate         *validator.Validate
+}
+
+func NewRewardService(rewardRepository repositories.RewardRepositoryInterface) *RewardService {
+	validate := validator.New(validator.WithRequiredStructEnabled())
+	validate.RegisterStructValidation(validateRewardRule, models.RewardRuleRequest{})
+
+	return &RewardService{
+		rewardRepository: rewardRepository,
+		validate:         validate,
+	}
+}
+
+// validateRewardRule Проверки, которые не выражаются тегами validate
+func validateRewardRule(sl validator.StructLevel) {
+	request := sl.Current().Interface().(models.RewardRuleRequest)
+
+	if request.Mechanic == entities.RewardMechanicPercent && request.Value > 100 {
+		sl.ReportError(request.Value, "Value", "Value", "lte", "100")
+	}
+	if rewards.ValidatePattern(request.SKUPattern) != nil {
+		sl.ReportError(request.SKUPattern, "SKUPattern", "SKUPattern", "pattern", "")
+	}
+	if request.ValidFrom != nil && request.ValidTo != nil && !request.ValidTo.After(*request.ValidFrom) {
+		sl.ReportError(request.ValidTo, "ValidTo", "ValidTo", "gtfield", "ValidFrom")
+	}
+}
+
+// GetRules Все правила в порядке применения
+func (s *RewardService) GetRules() ([]models.RewardRuleResponse, error) {
+	rules, err := s.rewardRepository.GetRules()
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]models.RewardRuleResponse, 0, len(rules))
+	for _, rule := range rules {
+		res = append(res, models.MapRewardRule(rule))
+	}
+
+	return res, nil
+}
+
+func (s *RewardService) CreateRule(request models.RewardRuleRequest) (*models.RewardRuleResponse, error) {
+	if err := s.validate.Struct(request); err != nil {
+		return nil, newValidationError(err)
+	}
+
+	rule := &entities.RewardRule{}
+	applyRewardRule(rule, request)
+	if err := s.rewardRepository.CreateRule(rule); err != nil {
+		return nil, err
+	}
+
+	res := models.MapRewardRule(rule)
+
+	return &res, nil
+}
+
+// UpdateRule Замена всех полей правила. Начисления по уже обработанным заказам не пересчитываются
+func (s *RewardService) UpdateRule(id uint, request models.RewardRuleRequest) (*models.RewardRuleResponse, error) {
+	if err := s.validate.Struct(request); err != nil {
+		return nil, newValidationError(err)
+	}
+
+	rule, err := s.findRule(id)
+	if err != nil {
+		return nil, err
+	}
+
+	applyRewardRule(rule, request)
+	if err = s.rewardRepository.UpdateRule(rule); err != nil {
+		return nil, err
+	}
+
+	if rule, err = s.findRule(id); err != nil {
+		return nil, err
+	}
+	res := models.MapRewardRule(rule)
+
+	return &res, nil
+}
+
+func (s *RewardService) DeleteRule(id uint) error {
+	if _, err := s.findRule(id); err != nil {
+		return err
+	}
+
+	return s.rewardRepository.DeleteRule(id)
+}
+
+// SubmitBasket Загрузка состава заказа. Номер проверяется так же, как при загрузке заказа пользователем,
+// сам заказ может быть загружен позже: до этого момента состав просто хранится
+func (s *RewardService) SubmitBasket(request models.OrderBasketRequest) (*models.OrderBasketResponse, error) {
+	if err := s.validate.Struct(request); err != nil {
+		return nil, newValidationError(err)
+	}
+
+	if err := CheckOrderNumber(request.Order); err != nil {
+		return nil, err
+	}
+
+	basket := &entities.OrderBasket{
+		OrderNumber: request.Order,
+		PurchasedAt: time.Now(),
+		Items:       make([]*entities.OrderBasketItem, 0, len(request.Items)),
+	}
+	if request.PurchasedAt != nil {
+		basket.PurchasedAt = *request.PurchasedAt
+	}
+	for _, item := range request.Items {
+		basket.Items = append(basket.Items, &entities.OrderBasketItem{
+			SKU:      item.SKU,
+			Category: item.Category,
+			Price:    item.Price,
+			Quantity: item.Quantity,
+		})
+	}
+
+	if err := s.rewardRepository.CreateBasket(basket); err != nil {
+		return nil, err
+	}
+
+	res := models.MapOrderBasket(basket)
+
+	return &res, nil
+}
+
+func (s *RewardService) findRule(id uint) (*entities.RewardRule, error) {
+	rule, err := s.rewardRepository.FindRule(id)
+	if err != nil {
+		return nil, err
+	}
+	if rule == nil {
+		return nil, ErrRewardRuleNotFound
+	}
+
+	return rule, nil
+}
+
+func applyRewardRule(rule *entities.RewardRule, request models.RewardRuleRequest) {
+	rule.Name = request.Name
+	rule.Mechanic = request.Mechanic
+	rule.Value = request.Value
+	rule.SKUPattern = request.SKUPattern
+	rule.Category = request.Category
+	rule.ValidFrom = request.ValidFrom
+	rule.ValidTo = request.ValidTo
+	rule.OrderCap = request.OrderCap
+	rule.Priority = request.Priority
+	rule.Active = request.Active == nil || *request.Active
+}
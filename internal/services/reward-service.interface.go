@@ -0,0 +1,11 @@
+package services
+
+import "github.com/ShukinDmitriy/gophermart/internal/models"
+
+type RewardServiceInterface interface {
+	GetRules() ([]models.RewardRuleResponse, error)
+	CreateRule(request models.RewardRuleRequest) (*models.RewardRuleResponse, error)
+	UpdateRule(id uint, request models.RewardRuleRequest) (*models.RewardRuleResponse, error)
+	DeleteRule(id uint) error
+	SubmitBasket(request models.OrderBasketRequest) (*models.OrderBasketResponse, error)
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+	"github.com/ShukinDmitriy/gophermart/internal/logging"
+	"github.com/ShukinDmitriy/gophermart/internal/models"
+	"github.com/ShukinDmitriy/gophermart/internal/repositories"
+	"github.com/ShukinDmitriy/gophermart/internal/rewards"
+	"go.uber.org/zap"
+)
+
+// RulesAccrualProvider Локальный расчёт начислений по правилам вознаграждения, действовавшим на момент покупки.
+// Пока состав заказа не загружен, заказ остаётся в обработке, как при ответе 204 системы расчёта
+type RulesAccrualProvider struct {
+	rewardRepository repositories.RewardRepositoryInterface
+	logger           *zap.Logger
+}
+
+func NewRulesAccrualProvider(rewardRepository repositories.RewardRepositoryInterface, logger *zap.Logger) *RulesAccrualProvider {
+	return &RulesAccrualProvider{
+		rewardRepository: rewardRepository,
+		logger:           logger.Named("accrual"),
+	}
+}
+
+// Ping Внешних зависимостей нет, правила хранятся в той же базе
+func (p *RulesAccrualProvider) Ping(context.Context) error {
+	return nil
+}
+
+func (p *RulesAccrualProvider) FetchOrder(ctx context.Context, orderNumber string) (*models.AccrualOrderResponse, int, error) {
+	log := p.logger.With(logging.OrderNumber(orderNumber)).With(logging.TraceID(ctx)...)
+	rewardRepository := p.rewardRepository.WithContext(ctx)
+	res := &models.AccrualOrderResponse{
+		Order:  orderNumber,
+		Status: entities.OrderStatusProcessing,
+	}
+
+	basket, err := rewardRepository.FindBasket(orderNumber)
+	if err != nil {
+		log.Error("cannot find order basket", zap.Error(err))
+		return res, http.StatusInternalServerError, err
+	}
+	if basket == nil {
+		return res, http.StatusNoContent, nil
+	}
+
+	rules, err := rewardRepository.GetActiveRules(basket.PurchasedAt)
+	if err != nil {
+		log.Error("cannot get reward rules", zap.Error(err))
+		return res, http.StatusInternalServerError, err
+	}
+
+	res.Status = entities.OrderStatusProcessed
+	res.Accrual = rewards.Calculate(rules, basket)
+
+	return res, http.StatusOK, nil
+}
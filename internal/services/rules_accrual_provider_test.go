@@ -0,0 +1,121 @@
+package services_test
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+	"github.com/ShukinDmitriy/gophermart/internal/metrics"
+	"github.com/ShukinDmitriy/gophermart/internal/models"
+	apprepositories "github.com/ShukinDmitriy/gophermart/internal/repositories"
+	"github.com/ShukinDmitriy/gophermart/internal/repositories/memory"
+	"github.com/ShukinDmitriy/gophermart/internal/services"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+)
+
+var _ = Describe("RulesAccrualProvider", func() {
+	orderNumber := "12345678903"
+	purchasedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	var storage *apprepositories.Storage
+	var rewardService *services.RewardService
+	var provider *services.RulesAccrualProvider
+
+	BeforeEach(func() {
+		// Arrange
+		storage = memory.NewStorage()
+		rewardService = services.NewRewardService(storage.Rewards)
+		provider = services.NewRulesAccrualProvider(storage.Rewards, zap.NewNop())
+
+		orderCap := float32(60)
+		until := purchasedAt.Add(-time.Hour)
+		for _, request := range []models.RewardRuleRequest{
+			{Name: "Bork", Mechanic: entities.RewardMechanicPercent, Value: 10, SKUPattern: "BORK-*", Priority: 10},
+			{Name: "Laptops", Mechanic: entities.RewardMechanicPoints, Value: 50, Category: "laptops", OrderCap: &orderCap},
+			{Name: "Expired", Mechanic: entities.RewardMechanicPoints, Value: 1000, ValidTo: &until, Priority: 100},
+		} {
+			_, err := rewardService.CreateRule(request)
+			Expect(err).NotTo(HaveOccurred())
+		}
+	})
+
+	submitBasket := func() {
+		_, err := rewardService.SubmitBasket(models.OrderBasketRequest{
+			Order:       orderNumber,
+			PurchasedAt: &purchasedAt,
+			Items: []models.OrderBasketItemRequest{
+				{SKU: "BORK-K1", Category: "kettles", Price: 7000, Quantity: 1},
+				{SKU: "ACER-1", Category: "laptops", Price: 50000, Quantity: 2},
+				{SKU: "CABLE", Category: "cables", Price: 300, Quantity: 1},
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	It("must keep the order processing until its basket is submitted", func() {
+		// Act
+		res, statusCode, err := provider.FetchOrder(context.Background(), orderNumber)
+
+		// Assert
+		Expect(err).NotTo(HaveOccurred())
+		Expect(statusCode).To(Equal(http.StatusNoContent))
+		Expect(res.Status).To(Equal(entities.OrderStatusProcessing))
+	})
+
+	It("must apply the rules valid at the purchase time", func() {
+		// Arrange
+		submitBasket()
+
+		// Act
+		res, statusCode, err := provider.FetchOrder(context.Background(), orderNumber)
+
+		// Assert
+		Expect(err).NotTo(HaveOccurred())
+		Expect(statusCode).To(Equal(http.StatusOK))
+		Expect(res.Status).To(Equal(entities.OrderStatusProcessed))
+		// 10% от 7000 и 2 × 50 баллов с лимитом 60 на заказ
+		Expect(res.Accrual).To(BeNumerically("~", 760, 0.001))
+	})
+
+	It("must accrue an uploaded order through the accrual service", func() {
+		// Arrange
+		user, err := storage.Users.Create(models.UserRegisterRequest{Login: "user", Password: "password"})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = storage.Orders.Create(orderNumber, user.ID)
+		Expect(err).NotTo(HaveOccurred())
+		service := services.NewAccrualService(
+			services.AccrualOptions{QueueSize: 100, RetryInterval: 5 * time.Millisecond, WaitForBasket: true},
+			storage.Orders,
+			provider,
+			services.NewInMemoryOrderEventBroker(),
+			metrics.New(),
+			zap.NewNop(),
+		)
+
+		// Act
+		go service.ProcessFailedOrders()
+		Eventually(func() ([]*entities.AccrualAttempt, error) {
+			return storage.Orders.GetAccrualAttempts(orderNumber, 100)
+		}).ShouldNot(BeEmpty())
+		// без состава заказ больше не опрашивается
+		Consistently(func() ([]*entities.AccrualAttempt, error) {
+			return storage.Orders.GetAccrualAttempts(orderNumber, 100)
+		}, 50*time.Millisecond).Should(HaveLen(1))
+		submitBasket()
+
+		// Assert
+		Eventually(func() float32 {
+			account, err := storage.Accounts.FindByUserID(user.ID, entities.AccountTypeBonus)
+			Expect(err).NotTo(HaveOccurred())
+
+			return account.Sum
+		}).WithTimeout(5 * time.Second).Should(BeNumerically("~", 760, 0.001))
+		attempts, err := storage.Orders.GetAccrualAttempts(orderNumber, 100)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(attempts[0].StatusCode).To(Equal(http.StatusNoContent))
+		Expect(attempts[len(attempts)-1].StatusCode).To(Equal(http.StatusOK))
+	})
+})
@@ -0,0 +1,118 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+)
+
+// SMTPOptions Почтовый сервер для уведомлений
+type SMTPOptions struct {
+	// Address host:port сервера
+	Address string
+	// Username пустой отключает аутентификацию. С аутентификацией сервер должен поддерживать STARTTLS,
+	// иначе net/smtp не отправит пароль открытым текстом
+	Username string
+	Password string
+	// From адрес отправителя
+	From string
+}
+
+// SMTPNotificationChannel Письма на адрес пользователя. STARTTLS используется, если сервер его предлагает
+type SMTPNotificationChannel struct {
+	options SMTPOptions
+}
+
+func NewSMTPNotificationChannel(options SMTPOptions) *SMTPNotificationChannel {
+	return &SMTPNotificationChannel{
+		options: options,
+	}
+}
+
+func (ch *SMTPNotificationChannel) Send(ctx context.Context, user *entities.User, notification *entities.Notification) error {
+	if !user.Notifications.Email || user.Email == "" {
+		return errNotificationChannelDisabled
+	}
+
+	host, _, err := net.SplitHostPort(ch.options.Address)
+	if err != nil {
+		return err
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", ch.options.Address)
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err = client.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+	if ch.options.Username != "" {
+		if err = client.Auth(smtp.PlainAuth("", ch.options.Username, ch.options.Password, host)); err != nil {
+			return err
+		}
+	}
+	if err = client.Mail(ch.options.From); err != nil {
+		return err
+	}
+	if err = client.Rcpt(user.Email); err != nil {
+		return err
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err = w.Write(ch.message(user, notification)); err != nil {
+		return err
+	}
+	if err = w.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
+
+// message Письмо text/plain в UTF-8, тема кодируется по RFC 2047
+func (ch *SMTPNotificationChannel) message(user *entities.User, notification *entities.Notification) []byte {
+	var buf bytes.Buffer
+	header := func(name string, value string) {
+		fmt.Fprintf(&buf, "%s: %s\r\n", name, value)
+	}
+
+	header("From", ch.options.From)
+	header("To", user.Email)
+	header("Subject", mime.QEncoding.Encode("utf-8", notification.Subject))
+	header("Date", time.Now().Format(time.RFC1123Z))
+	header(NotificationHeaderID, strconv.FormatUint(uint64(notification.ID), 10))
+	header("MIME-Version", "1.0")
+	header("Content-Type", "text/plain; charset=utf-8")
+	header("Content-Transfer-Encoding", "8bit")
+	buf.WriteString("\r\n")
+	buf.WriteString(strings.ReplaceAll(notification.Body, "\n", "\r\n"))
+	buf.WriteString("\r\n")
+
+	return buf.Bytes()
+}
@@ -0,0 +1,118 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+	"github.com/ShukinDmitriy/gophermart/internal/models"
+	"go.uber.org/zap"
+)
+
+// UserEventDispatcher Получатель событий outbox: передаёт события дальше, а после подтверждения
+// превращает исход обработки заказа и начисление в вебхуки и уведомления пользователя.
+// События записаны в транзакции изменения, поэтому вебхук не уходит раньше фиксации начисления
+// и не теряется, если процесс упал сразу после неё. При повторной публикации пакета вебхук
+// повторяется с тем же ID события
+type UserEventDispatcher struct {
+	next                EventSinkInterface
+	webhookService      WebhookServiceInterface
+	notificationService NotificationServiceInterface
+	logger              *zap.Logger
+}
+
+func NewUserEventDispatcher(
+	next EventSinkInterface,
+	webhookService WebhookServiceInterface,
+	notificationService NotificationServiceInterface,
+	logger *zap.Logger,
+) *UserEventDispatcher {
+	return &UserEventDispatcher{
+		next:                next,
+		webhookService:      webhookService,
+		notificationService: notificationService,
+		logger:              logger.Named("user_events"),
+	}
+}
+
+func (d *UserEventDispatcher) Publish(ctx context.Context, events []models.DomainEvent) error {
+	if err := d.next.Publish(ctx, events); err != nil {
+		return err
+	}
+
+	for _, event := range events {
+		d.dispatch(ctx, event)
+	}
+
+	return nil
+}
+
+func (d *UserEventDispatcher) Close() error {
+	return d.next.Close()
+}
+
+func (d *UserEventDispatcher) dispatch(ctx context.Context, event models.DomainEvent) {
+	switch event.Type {
+	case entities.DomainEventOrderStatusChanged:
+		var payload models.OrderStatusChangedEvent
+		if !d.decode(event, &payload) || payload.Status == payload.PreviousStatus {
+			return
+		}
+
+		switch payload.Status {
+		case entities.OrderStatusProcessed:
+			d.webhookService.Emit(ctx, models.WebhookEvent{
+				ID:         event.ID,
+				Type:       entities.WebhookEventOrderProcessed,
+				UserID:     payload.UserID,
+				Order:      payload.Order,
+				Status:     payload.Status,
+				Sum:        payload.Accrual,
+				OccurredAt: event.OccurredAt,
+			})
+			d.notificationService.Notify(ctx, models.NotificationEvent{
+				Type:    entities.NotificationOrderProcessed,
+				UserID:  payload.UserID,
+				Order:   payload.Order,
+				Accrual: payload.Accrual,
+			})
+		case entities.OrderStatusInvalid:
+			d.webhookService.Emit(ctx, models.WebhookEvent{
+				ID:         event.ID,
+				Type:       entities.WebhookEventOrderInvalid,
+				UserID:     payload.UserID,
+				Order:      payload.Order,
+				Status:     payload.Status,
+				OccurredAt: event.OccurredAt,
+			})
+			d.notificationService.Notify(ctx, models.NotificationEvent{
+				Type:   entities.NotificationOrderInvalid,
+				UserID: payload.UserID,
+				Order:  payload.Order,
+			})
+		}
+	case entities.DomainEventPointsAccrued:
+		var payload models.PointsEvent
+		if !d.decode(event, &payload) {
+			return
+		}
+
+		d.webhookService.Emit(ctx, models.WebhookEvent{
+			ID:         event.ID,
+			Type:       entities.WebhookEventBalanceAccrued,
+			UserID:     payload.UserID,
+			Order:      payload.Order,
+			Sum:        payload.Sum,
+			OccurredAt: event.OccurredAt,
+		})
+	}
+}
+
+func (d *UserEventDispatcher) decode(event models.DomainEvent, payload any) bool {
+	if err := json.Unmarshal(event.Payload, payload); err != nil {
+		d.logger.Error("cannot decode domain event", zap.String("event_id", event.ID), zap.String("event_type", string(event.Type)), zap.Error(err))
+		return false
+	}
+
+	return true
+}
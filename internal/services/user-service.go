@@ -0,0 +1,59 @@
+package services
+
+import (
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+	"github.com/ShukinDmitriy/gophermart/internal/models"
+	"github.com/ShukinDmitriy/gophermart/internal/repositories"
+	"github.com/go-playground/validator/v10"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type UserService struct {
+	userRepository repositories.UserRepositoryInterface
+	validate       *validator.Validate
+}
+
+func NewUserService(userRepository repositories.UserRepositoryInterface) *UserService {
+	return &UserService{
+		userRepository: userRepository,
+		validate:       validator.New(validator.WithRequiredStructEnabled()),
+	}
+}
+
+// Register Регистрация пользователя, логин должен быть свободен
+func (s *UserService) Register(request models.UserRegisterRequest) (*models.UserInfoResponse, error) {
+	if err := s.validate.Struct(request); err != nil {
+		return nil, newValidationError(err)
+	}
+
+	existUser, err := s.userRepository.FindBy(models.UserSearchFilter{Login: request.Login})
+	if err != nil {
+		return nil, err
+	}
+	if existUser != nil {
+		return nil, ErrLoginAlreadyExists
+	}
+
+	return s.userRepository.Create(request)
+}
+
+// Login Проверка логина и пароля
+func (s *UserService) Login(request models.UserLoginRequest) (*entities.User, error) {
+	if err := s.validate.Struct(request); err != nil {
+		return nil, newValidationError(err)
+	}
+
+	existUser, err := s.userRepository.FindBy(models.UserSearchFilter{Login: request.Login})
+	if err != nil {
+		return nil, err
+	}
+	if existUser == nil {
+		return nil, ErrInvalidCredentials
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(existUser.Password), []byte(request.Password)) != nil {
+		return nil, ErrInvalidCredentials
+	}
+
+	return existUser, nil
+}
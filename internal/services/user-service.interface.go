@@ -0,0 +1,11 @@
+package services
+
+import (
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+	"github.com/ShukinDmitriy/gophermart/internal/models"
+)
+
+type UserServiceInterface interface {
+	Register(request models.UserRegisterRequest) (*models.UserInfoResponse, error)
+	Login(request models.UserLoginRequest) (*entities.User, error)
+}
@@ -0,0 +1,105 @@
+package services_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+	"github.com/ShukinDmitriy/gophermart/internal/models"
+	"github.com/ShukinDmitriy/gophermart/internal/services"
+	mockservices "github.com/ShukinDmitriy/gophermart/mocks/internal_/services"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+var _ = Describe("UserEventDispatcher", func() {
+	var sink *services.InMemoryEventSink
+	var webhookService *mockservices.WebhookServiceInterface
+	var notificationService *mockservices.NotificationServiceInterface
+	var dispatcher *services.UserEventDispatcher
+
+	domainEvent := func(id string, eventType entities.DomainEventType, payload any) models.DomainEvent {
+		raw, err := json.Marshal(payload)
+		Expect(err).NotTo(HaveOccurred())
+
+		return models.DomainEvent{ID: id, Type: eventType, Payload: raw}
+	}
+	processed := domainEvent("1", entities.DomainEventOrderStatusChanged, models.OrderStatusChangedEvent{
+		Order:          "12345678903",
+		UserID:         1,
+		Status:         entities.OrderStatusProcessed,
+		PreviousStatus: entities.OrderStatusProcessing,
+		Accrual:        500,
+	})
+	accrued := domainEvent("2", entities.DomainEventPointsAccrued, models.PointsEvent{
+		AccountID: 1,
+		UserID:    1,
+		Order:     "12345678903",
+		Sum:       500,
+	})
+
+	BeforeEach(func() {
+		// Arrange
+		sink = services.NewInMemoryEventSink()
+		webhookService = new(mockservices.WebhookServiceInterface)
+		notificationService = new(mockservices.NotificationServiceInterface)
+		dispatcher = services.NewUserEventDispatcher(sink, webhookService, notificationService, zap.NewNop())
+	})
+
+	It("must emit webhooks and a notification for a processed order", func() {
+		// Arrange
+		webhookService.EXPECT().Emit(mock.Anything, mock.MatchedBy(func(event models.WebhookEvent) bool {
+			return event.ID == "1" && event.Type == entities.WebhookEventOrderProcessed && event.Sum == 500
+		})).Return().Once()
+		webhookService.EXPECT().Emit(mock.Anything, mock.MatchedBy(func(event models.WebhookEvent) bool {
+			return event.ID == "2" && event.Type == entities.WebhookEventBalanceAccrued && event.Sum == 500
+		})).Return().Once()
+		notificationService.EXPECT().Notify(mock.Anything, mock.MatchedBy(func(event models.NotificationEvent) bool {
+			return event.Type == entities.NotificationOrderProcessed && event.Accrual == 500
+		})).Return().Once()
+
+		// Act
+		err := dispatcher.Publish(context.Background(), []models.DomainEvent{processed, accrued})
+
+		// Assert
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sink.Events()).To(HaveLen(2))
+		webhookService.AssertExpectations(GinkgoT())
+		notificationService.AssertExpectations(GinkgoT())
+	})
+
+	It("must not emit anything when the events are not published", func() {
+		// Arrange
+		sink.Fail(errors.New("broker is down"))
+
+		// Act
+		err := dispatcher.Publish(context.Background(), []models.DomainEvent{processed, accrued})
+
+		// Assert
+		Expect(err).To(HaveOccurred())
+		webhookService.AssertNotCalled(GinkgoT(), "Emit", mock.Anything, mock.Anything)
+		notificationService.AssertNotCalled(GinkgoT(), "Notify", mock.Anything, mock.Anything)
+	})
+
+	It("must not emit anything for an accrual correction", func() {
+		// Arrange
+		corrected := domainEvent("3", entities.DomainEventOrderStatusChanged, models.OrderStatusChangedEvent{
+			Order:          "12345678903",
+			UserID:         1,
+			Status:         entities.OrderStatusProcessed,
+			PreviousStatus: entities.OrderStatusProcessed,
+			Accrual:        600,
+		})
+
+		// Act
+		err := dispatcher.Publish(context.Background(), []models.DomainEvent{corrected})
+
+		// Assert
+		Expect(err).NotTo(HaveOccurred())
+		webhookService.AssertNotCalled(GinkgoT(), "Emit", mock.Anything, mock.Anything)
+		notificationService.AssertNotCalled(GinkgoT(), "Notify", mock.Anything, mock.Anything)
+	})
+})
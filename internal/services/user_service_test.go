@@ -0,0 +1,92 @@
+package services_test
+
+import (
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+	"github.com/ShukinDmitriy/gophermart/internal/models"
+	apprepositories "github.com/ShukinDmitriy/gophermart/internal/repositories"
+	"github.com/ShukinDmitriy/gophermart/internal/services"
+	"github.com/ShukinDmitriy/gophermart/mocks/internal_/repositories"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var _ = Describe("UserService", func() {
+	var userRepository *repositories.UserRepositoryInterface
+	var service *services.UserService
+
+	login := "user"
+	password := "password"
+	passwordHash, _ := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	user := &entities.User{
+		Login:    login,
+		Password: string(passwordHash),
+	}
+
+	BeforeEach(func() {
+		userRepository = new(repositories.UserRepositoryInterface)
+		service = services.NewUserService(userRepository)
+	})
+
+	Describe("Register", func() {
+		It("must not register a taken login", func() {
+			// Arrange
+			userRepository.EXPECT().FindBy(models.UserSearchFilter{Login: login}).Return(user, nil)
+
+			// Act
+			_, err := service.Register(models.UserRegisterRequest{Login: login, Password: password})
+
+			// Assert
+			Expect(err).To(MatchError(services.ErrLoginAlreadyExists))
+		})
+
+		It("must not register a login taken by a concurrent registration", func() {
+			// Arrange
+			request := models.UserRegisterRequest{Login: login, Password: password}
+			userRepository.EXPECT().FindBy(models.UserSearchFilter{Login: login}).Return(nil, nil)
+			userRepository.EXPECT().Create(request).Return(nil, apprepositories.ErrLoginAlreadyExists)
+
+			// Act
+			_, err := service.Register(request)
+
+			// Assert
+			Expect(err).To(MatchError(services.ErrLoginAlreadyExists))
+		})
+
+		It("must validate the request", func() {
+			// Act
+			_, err := service.Register(models.UserRegisterRequest{Login: login})
+
+			// Assert
+			Expect(err).To(MatchError(services.ErrValidation))
+		})
+	})
+
+	Describe("Login", func() {
+		It("must return the user for the correct password", func() {
+			// Arrange
+			userRepository.EXPECT().FindBy(models.UserSearchFilter{Login: login}).Return(user, nil)
+
+			// Act
+			res, err := service.Login(models.UserLoginRequest{Login: login, Password: password})
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res).To(Equal(user))
+		})
+
+		It("must not distinguish an unknown login from a wrong password", func() {
+			// Arrange
+			userRepository.EXPECT().FindBy(models.UserSearchFilter{Login: "unknown"}).Return(nil, nil)
+			userRepository.EXPECT().FindBy(models.UserSearchFilter{Login: login}).Return(user, nil)
+
+			// Act
+			_, unknownErr := service.Login(models.UserLoginRequest{Login: "unknown", Password: password})
+			_, wrongErr := service.Login(models.UserLoginRequest{Login: login, Password: "wrongpassword"})
+
+			// Assert
+			Expect(unknownErr).To(MatchError(services.ErrInvalidCredentials))
+			Expect(wrongErr).To(MatchError(services.ErrInvalidCredentials))
+		})
+	})
+})
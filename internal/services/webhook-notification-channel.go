@@ -0,0 +1,74 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+	"github.com/ShukinDmitriy/gophermart/internal/models"
+	"github.com/ShukinDmitriy/gophermart/internal/signature"
+)
+
+// NotificationHeaderID идентификатор уведомления, не меняется при повторах
+const NotificationHeaderID = "X-Gophermart-Notification"
+
+// notificationWebhookPayload Тело запроса: уведомление в том же виде, что и в GET /api/user/notifications
+type notificationWebhookPayload struct {
+	UserID uint `json:"user_id"`
+	models.NotificationResponse
+}
+
+// WebhookNotificationChannel Подписанный POST на адрес из настроек пользователя. Подпись та же,
+// что у вебхуков событий, ключ — секрет, выданный при сохранении адреса
+type WebhookNotificationChannel struct {
+	httpClient *http.Client
+}
+
+func NewWebhookNotificationChannel(httpClient *http.Client) *WebhookNotificationChannel {
+	return &WebhookNotificationChannel{
+		httpClient: httpClient,
+	}
+}
+
+func (ch *WebhookNotificationChannel) Send(ctx context.Context, user *entities.User, notification *entities.Notification) error {
+	if user.Notifications.WebhookURL == "" {
+		return errNotificationChannelDisabled
+	}
+
+	body, err := json.Marshal(notificationWebhookPayload{
+		UserID:               user.ID,
+		NotificationResponse: models.MapNotification(notification),
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, user.Notifications.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "gophermart-notifications")
+	req.Header.Set(WebhookHeaderEvent, string(notification.Type))
+	req.Header.Set(NotificationHeaderID, strconv.FormatUint(uint64(notification.ID), 10))
+	signature.SetHeaders(req.Header, user.Notifications.WebhookSecret, body, time.Now())
+
+	response, err := ch.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(response.Body, 64<<10))
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf("unexpected status %d", response.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,430 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+	"github.com/ShukinDmitriy/gophermart/internal/logging"
+	"github.com/ShukinDmitriy/gophermart/internal/metrics"
+	"github.com/ShukinDmitriy/gophermart/internal/models"
+	"github.com/ShukinDmitriy/gophermart/internal/netguard"
+	"github.com/ShukinDmitriy/gophermart/internal/repositories"
+	"github.com/ShukinDmitriy/gophermart/internal/signature"
+	"github.com/go-playground/validator/v10"
+	"go.uber.org/zap"
+)
+
+const (
+	// WebhookHeaderEvent тип события доставки
+	WebhookHeaderEvent = "X-Gophermart-Event"
+	// WebhookHeaderDelivery идентификатор доставки, не меняется при повторах
+	WebhookHeaderDelivery = "X-Gophermart-Delivery"
+)
+
+const (
+	// webhookBatchSize доставок, забираемых за один проход
+	webhookBatchSize = 100
+	// webhookWorkers одновременных запросов к получателям, медленный получатель не задерживает остальных
+	webhookWorkers = 8
+	// webhookLease на это время доставка откладывается на время попытки. Больше таймаута запроса,
+	// поэтому повтор начнётся, только если экземпляр, забравший доставку, упал
+	webhookLease = 5 * time.Minute
+	// webhookMaxBackoff наибольшая пауза между попытками
+	webhookMaxBackoff = 6 * time.Hour
+	// webhookMaxErrorLength ошибки длиннее обрезаются перед записью в журнал доставок
+	webhookMaxErrorLength = 512
+)
+
+// WebhookOptions Настройки доставки вебхуков
+type WebhookOptions struct {
+	// MaxAttempts попыток доставки события, после последней неудачной доставка помечается failed
+	MaxAttempts int
+	// RetryBackoff пауза перед второй попыткой, перед каждой следующей удваивается
+	RetryBackoff time.Duration
+	// PollInterval период проверки доставок, время попытки которых наступило
+	PollInterval time.Duration
+	// AllowPrivateNetworks принимать адреса на localhost и во внутренних сетях (разработка)
+	AllowPrivateNetworks bool
+}
+
+type WebhookService struct {
+	options           WebhookOptions
+	webhookRepository repositories.WebhookRepositoryInterface
+	httpClient        *http.Client
+	metrics           *metrics.Metrics
+	logger            *zap.Logger
+	validate          *validator.Validate
+	// wake будит Run после Emit, чтобы новые события не ждали PollInterval
+	wake chan struct{}
+}
+
+func NewWebhookService(
+	options WebhookOptions,
+	webhookRepository repositories.WebhookRepositoryInterface,
+	httpClient *http.Client,
+	metrics *metrics.Metrics,
+	logger *zap.Logger,
+) *WebhookService {
+	return &WebhookService{
+		options:           options,
+		webhookRepository: webhookRepository,
+		httpClient:        httpClient,
+		metrics:           metrics,
+		logger:            logger.Named("webhook"),
+		validate:          validator.New(validator.WithRequiredStructEnabled()),
+		wake:              make(chan struct{}, 1),
+	}
+}
+
+// CreateWebhook Новый вебхук. Секрет подписи генерируется здесь и возвращается только в этом ответе
+func (s *WebhookService) CreateWebhook(owner *uint, request models.WebhookRequest) (*models.WebhookResponse, error) {
+	if err := s.validate.Struct(request); err != nil {
+		return nil, newValidationError(err)
+	}
+	if !s.options.AllowPrivateNetworks && netguard.CheckURL(request.URL) != nil {
+		return nil, ErrURLNotAllowed
+	}
+
+	secret, err := randomHex(32)
+	if err != nil {
+		return nil, err
+	}
+
+	webhook := &entities.Webhook{
+		UserID: owner,
+		URL:    request.URL,
+		Secret: secret,
+		Events: request.Events,
+	}
+	if err = s.webhookRepository.CreateWebhook(webhook); err != nil {
+		return nil, err
+	}
+
+	res := models.MapWebhook(webhook)
+	res.Secret = secret
+
+	return &res, nil
+}
+
+func (s *WebhookService) GetWebhooks(owner *uint) ([]models.WebhookResponse, error) {
+	webhooks, err := s.webhookRepository.GetWebhooks(owner)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]models.WebhookResponse, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		res = append(res, models.MapWebhook(webhook))
+	}
+
+	return res, nil
+}
+
+// DeleteWebhook Удаление вебхука. Ожидающие доставки на него завершатся неудачей при следующей попытке
+func (s *WebhookService) DeleteWebhook(owner *uint, id uint) error {
+	if _, err := s.findOwned(owner, id); err != nil {
+		return err
+	}
+
+	return s.webhookRepository.DeleteWebhook(id)
+}
+
+// GetDeliveries Журнал доставок вебхука, новые первыми
+func (s *WebhookService) GetDeliveries(
+	owner *uint,
+	id uint,
+	request models.GetWebhookDeliveriesRequest,
+) ([]models.WebhookDeliveryResponse, error) {
+	if err := s.validate.Struct(request); err != nil {
+		return nil, newValidationError(err)
+	}
+	if _, err := s.findOwned(owner, id); err != nil {
+		return nil, err
+	}
+
+	limit := request.Limit
+	if limit == 0 {
+		limit = models.GetWebhookDeliveriesDefaultLimit
+	}
+	deliveries, err := s.webhookRepository.GetDeliveries(id, request.Statuses(), limit)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]models.WebhookDeliveryResponse, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		res = append(res, models.MapWebhookDelivery(delivery))
+	}
+
+	return res, nil
+}
+
+// Emit Сохранение доставок события на все подписанные вебхуки пользователя и интеграторов.
+// Изменение, о котором событие, уже сохранено, поэтому ошибка только пишется в лог
+func (s *WebhookService) Emit(ctx context.Context, event models.WebhookEvent) {
+	log := s.logger.With(
+		zap.String("event_type", string(event.Type)),
+		logging.UserID(event.UserID),
+		logging.OrderNumber(event.Order),
+	).With(logging.TraceID(ctx)...)
+	webhookRepository := s.webhookRepository.WithContext(ctx)
+
+	webhooks, err := webhookRepository.GetWebhooksForUser(event.UserID)
+	if err != nil {
+		log.Error("cannot get webhooks", zap.Error(err))
+		return
+	}
+
+	var subscribed []*entities.Webhook
+	for _, webhook := range webhooks {
+		if webhook.Subscribed(event.Type) {
+			subscribed = append(subscribed, webhook)
+		}
+	}
+	if len(subscribed) == 0 {
+		return
+	}
+
+	// событие из outbox приходит со своим ID, с ним же оно повторяется после повторной публикации
+	if event.ID == "" {
+		if event.ID, err = randomHex(16); err != nil {
+			log.Error("cannot generate event id", zap.Error(err))
+			return
+		}
+	}
+	now := time.Now()
+	if time.Time(event.OccurredAt).IsZero() {
+		event.OccurredAt = models.JSONTime(now)
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		log.Error("cannot marshal event", zap.Error(err))
+		return
+	}
+
+	deliveries := make([]*entities.WebhookDelivery, 0, len(subscribed))
+	for _, webhook := range subscribed {
+		deliveries = append(deliveries, &entities.WebhookDelivery{
+			WebhookID:     webhook.ID,
+			EventID:       event.ID,
+			EventType:     event.Type,
+			Payload:       string(payload),
+			Status:        entities.WebhookDeliveryPending,
+			NextAttemptAt: now,
+		})
+	}
+	if err = webhookRepository.CreateDeliveries(deliveries); err != nil {
+		log.Error("cannot save webhook deliveries", zap.Error(err))
+		return
+	}
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run Доставка событий до отмены ctx: раз в PollInterval и сразу после новых событий
+func (s *WebhookService) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.options.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		// полный пакет значит, что наступивших доставок может быть больше
+		for s.DeliverPending(ctx) == webhookBatchSize {
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+	}
+}
+
+// DeliverPending Одна попытка по каждой наступившей доставке, не больше webhookBatchSize.
+// Возвращает число забранных доставок
+func (s *WebhookService) DeliverPending(ctx context.Context) int {
+	deliveries, err := s.webhookRepository.WithContext(ctx).ClaimDeliveries(time.Now(), webhookLease, webhookBatchSize)
+	if err != nil {
+		s.logger.Error("cannot claim webhook deliveries", zap.Error(err))
+		return 0
+	}
+
+	webhooks := make(map[uint]*entities.Webhook)
+	for _, delivery := range deliveries {
+		if _, ok := webhooks[delivery.WebhookID]; ok {
+			continue
+		}
+		webhook, err := s.webhookRepository.WithContext(ctx).FindWebhook(delivery.WebhookID)
+		if err != nil {
+			s.logger.Error("cannot find webhook", zap.Uint("webhook_id", delivery.WebhookID), zap.Error(err))
+			return 0
+		}
+		webhooks[delivery.WebhookID] = webhook
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, webhookWorkers)
+	for _, delivery := range deliveries {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			s.attempt(ctx, webhooks[delivery.WebhookID], delivery)
+		}()
+	}
+	wg.Wait()
+
+	return len(deliveries)
+}
+
+// attempt Попытка доставки и запись её итога. webhook nil, если вебхук удалён после события
+func (s *WebhookService) attempt(ctx context.Context, webhook *entities.Webhook, delivery *entities.WebhookDelivery) {
+	log := s.logger.With(
+		zap.Uint("webhook_id", delivery.WebhookID),
+		zap.Uint("delivery_id", delivery.ID),
+		zap.String("event_type", string(delivery.EventType)),
+		zap.Int("attempt", delivery.Attempts),
+	)
+
+	var err error
+	if webhook == nil {
+		delivery.LastStatusCode, err = 0, ErrWebhookNotFound
+	} else {
+		delivery.LastStatusCode, err = s.send(ctx, webhook, delivery)
+	}
+
+	now := time.Now()
+	result := string(entities.WebhookDeliveryDelivered)
+	switch {
+	case err == nil:
+		delivery.Status = entities.WebhookDeliveryDelivered
+		delivery.LastError = ""
+		delivery.DeliveredAt = &now
+	case webhook == nil || delivery.Attempts >= s.options.MaxAttempts:
+		delivery.Status = entities.WebhookDeliveryFailed
+		delivery.LastError = deliveryError(err)
+		result = string(entities.WebhookDeliveryFailed)
+		log.Warn("webhook delivery failed", zap.Error(err))
+	default:
+		delivery.NextAttemptAt = now.Add(backoff(s.options.RetryBackoff, webhookMaxBackoff, delivery.Attempts))
+		delivery.LastError = deliveryError(err)
+		result = "retry"
+		log.Info("webhook delivery will be retried", zap.Time("next_attempt_at", delivery.NextAttemptAt), zap.Error(err))
+	}
+	s.metrics.WebhookDeliveryAttempt(result)
+
+	// итог пишется и после отмены ctx, иначе доставка повторится только после webhookLease
+	err = s.webhookRepository.WithContext(context.WithoutCancel(ctx)).UpdateDelivery(delivery)
+	if err != nil {
+		log.Error("cannot save webhook delivery", zap.Error(err))
+	}
+}
+
+// send Подписанный запрос получателю. Доставленным считается любой ответ 2xx
+func (s *WebhookService) send(ctx context.Context, webhook *entities.Webhook, delivery *entities.WebhookDelivery) (int, error) {
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "gophermart-webhooks")
+	req.Header.Set(WebhookHeaderEvent, string(delivery.EventType))
+	req.Header.Set(WebhookHeaderDelivery, strconv.FormatUint(uint64(delivery.ID), 10))
+	signature.SetHeaders(req.Header, webhook.Secret, body, time.Now())
+
+	response, err := s.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+	// ответ не нужен, но дочитанное тело позволяет переиспользовать соединение
+	_, _ = io.Copy(io.Discard, io.LimitReader(response.Body, 64<<10))
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return response.StatusCode, fmt.Errorf("unexpected status %d", response.StatusCode)
+	}
+
+	return response.StatusCode, nil
+}
+
+// deliveryError Текст ошибки для журнала доставок, который видит владелец вебхука. Ошибка соединения
+// раскрывает адреса и устройство внутренней сети, поэтому вместо неё пишется только её вид, а сама она — в лог
+func deliveryError(err error) string {
+	var urlErr *url.Error
+	switch {
+	case errors.Is(err, netguard.ErrForbiddenAddress):
+		return netguard.ErrForbiddenAddress.Error()
+	case errors.As(err, &urlErr) && urlErr.Timeout():
+		return "request timed out"
+	case errors.As(err, &urlErr):
+		return "request failed"
+	}
+
+	return truncate(err.Error(), webhookMaxErrorLength)
+}
+
+// findOwned Вебхук владельца. Чужой вебхук не отличается от несуществующего
+func (s *WebhookService) findOwned(owner *uint, id uint) (*entities.Webhook, error) {
+	webhook, err := s.webhookRepository.FindWebhook(id)
+	if err != nil {
+		return nil, err
+	}
+	if webhook == nil || !sameOwner(webhook.UserID, owner) {
+		return nil, ErrWebhookNotFound
+	}
+
+	return webhook, nil
+}
+
+func sameOwner(a *uint, b *uint) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+
+	return *a == *b
+}
+
+func randomHex(size int) (string, error) {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(buf), nil
+}
+
+// backoff Пауза после неудачной попытки attempt: initial, затем вдвое больше каждый раз, но не больше maximum
+func backoff(initial time.Duration, maximum time.Duration, attempt int) time.Duration {
+	backoff := initial
+	for i := 1; i < attempt && backoff < maximum; i++ {
+		backoff *= 2
+	}
+
+	return min(backoff, maximum)
+}
+
+func truncate(s string, length int) string {
+	if len(s) <= length {
+		return s
+	}
+
+	return s[:length]
+}
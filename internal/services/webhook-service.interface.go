@@ -0,0 +1,16 @@
+package services
+
+import (
+	"context"
+
+	"github.com/ShukinDmitriy/gophermart/internal/models"
+)
+
+// WebhookServiceInterface owner владелец вебхуков: пользователь или nil для вебхуков интеграторов
+type WebhookServiceInterface interface {
+	CreateWebhook(owner *uint, request models.WebhookRequest) (*models.WebhookResponse, error)
+	GetWebhooks(owner *uint) ([]models.WebhookResponse, error)
+	DeleteWebhook(owner *uint, id uint) error
+	GetDeliveries(owner *uint, id uint, request models.GetWebhookDeliveriesRequest) ([]models.WebhookDeliveryResponse, error)
+	Emit(ctx context.Context, event models.WebhookEvent)
+}
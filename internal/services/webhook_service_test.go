@@ -0,0 +1,237 @@
+package services_test
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/ShukinDmitriy/gophermart/internal/entities"
+	"github.com/ShukinDmitriy/gophermart/internal/metrics"
+	"github.com/ShukinDmitriy/gophermart/internal/models"
+	"github.com/ShukinDmitriy/gophermart/internal/netguard"
+	apprepositories "github.com/ShukinDmitriy/gophermart/internal/repositories"
+	"github.com/ShukinDmitriy/gophermart/internal/repositories/memory"
+	"github.com/ShukinDmitriy/gophermart/internal/services"
+	"github.com/ShukinDmitriy/gophermart/internal/webhooktest"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+)
+
+var _ = Describe("WebhookService", func() {
+	var storage *apprepositories.Storage
+	var service *services.WebhookService
+	var userID uint
+
+	accrued := func() models.WebhookEvent {
+		return models.WebhookEvent{Type: entities.WebhookEventBalanceAccrued, UserID: userID, Order: "12345678903", Sum: 500}
+	}
+
+	BeforeEach(func() {
+		// Arrange
+		storage = memory.NewStorage()
+		user, err := storage.Users.Create(models.UserRegisterRequest{Login: "user", Password: "password"})
+		Expect(err).NotTo(HaveOccurred())
+		userID = user.ID
+		service = services.NewWebhookService(
+			// получатели в тестах слушают на localhost
+			services.WebhookOptions{MaxAttempts: 3, RetryBackoff: 10 * time.Millisecond, PollInterval: time.Hour, AllowPrivateNetworks: true},
+			storage.Webhooks,
+			http.DefaultClient,
+			metrics.New(),
+			zap.NewNop(),
+		)
+	})
+
+	register := func(owner *uint, url string, events ...entities.WebhookEventType) *models.WebhookResponse {
+		webhook, err := service.CreateWebhook(owner, models.WebhookRequest{URL: url, Events: events})
+		Expect(err).NotTo(HaveOccurred())
+
+		return webhook
+	}
+
+	Describe("Delivery", func() {
+		It("must deliver a signed event to the subscribed webhooks of the user and integrators", func() {
+			// Arrange
+			own, integrator, unsubscribed := webhooktest.NewReceiver(), webhooktest.NewReceiver(), webhooktest.NewReceiver()
+			DeferCleanup(own.Close)
+			DeferCleanup(integrator.Close)
+			DeferCleanup(unsubscribed.Close)
+			ownWebhook := register(&userID, own.URL, entities.WebhookEventBalanceAccrued, entities.WebhookEventOrderProcessed)
+			integratorWebhook := register(nil, integrator.URL, entities.WebhookEventBalanceAccrued)
+			register(&userID, unsubscribed.URL, entities.WebhookEventOrderInvalid)
+			ctx, cancel := context.WithCancel(context.Background())
+			DeferCleanup(cancel)
+			go service.Run(ctx)
+
+			// Act
+			service.Emit(context.Background(), accrued())
+
+			// Assert
+			var ownDelivery, integratorDelivery webhooktest.Delivery
+			Eventually(own.Received()).Should(Receive(&ownDelivery))
+			Eventually(integrator.Received()).Should(Receive(&integratorDelivery))
+			Expect(ownDelivery.Verify(ownWebhook.Secret)).To(Succeed())
+			Expect(ownDelivery.Verify(integratorWebhook.Secret)).NotTo(Succeed())
+			Expect(integratorDelivery.Verify(integratorWebhook.Secret)).To(Succeed())
+			Expect(ownDelivery.Header.Get(services.WebhookHeaderEvent)).To(Equal("balance.accrued"))
+			Expect(ownDelivery.Event.ID).NotTo(BeEmpty())
+			Expect(ownDelivery.Event.ID).To(Equal(integratorDelivery.Event.ID))
+			Expect(ownDelivery.Event.Sum).To(BeNumerically("==", 500))
+			Consistently(unsubscribed.Received(), 50*time.Millisecond).ShouldNot(Receive())
+		})
+
+		It("must retry with backoff until the receiver accepts the event", func() {
+			// Arrange
+			receiver := webhooktest.NewReceiver(http.StatusInternalServerError, http.StatusServiceUnavailable)
+			DeferCleanup(receiver.Close)
+			webhook := register(&userID, receiver.URL, entities.WebhookEventBalanceAccrued)
+			service.Emit(context.Background(), accrued())
+
+			// Act
+			Eventually(func() int {
+				service.DeliverPending(context.Background())
+				return len(receiver.Deliveries())
+			}).Should(Equal(3))
+
+			// Assert
+			deliveries := receiver.Deliveries()
+			Expect(deliveries[0].Header.Get(services.WebhookHeaderDelivery)).To(Equal(deliveries[2].Header.Get(services.WebhookHeaderDelivery)))
+			// вторая пауза вдвое длиннее первой
+			Expect(deliveries[2].ReceivedAt.Sub(deliveries[1].ReceivedAt)).To(BeNumerically(">=", 20*time.Millisecond))
+			log, err := service.GetDeliveries(&userID, webhook.ID, models.GetWebhookDeliveriesRequest{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(log).To(HaveLen(1))
+			Expect(log[0].Status).To(Equal(entities.WebhookDeliveryDelivered))
+			Expect(log[0].Attempts).To(Equal(3))
+			Expect(log[0].LastStatusCode).To(Equal(http.StatusNoContent))
+			Expect(log[0].DeliveredAt).NotTo(BeNil())
+		})
+
+		It("must give up after the last attempt and keep the failure in the log", func() {
+			// Arrange
+			receiver := webhooktest.NewReceiver(http.StatusInternalServerError, http.StatusInternalServerError, http.StatusBadGateway)
+			DeferCleanup(receiver.Close)
+			webhook := register(&userID, receiver.URL, entities.WebhookEventBalanceAccrued)
+			service.Emit(context.Background(), accrued())
+
+			// Act
+			Eventually(func() []models.WebhookDeliveryResponse {
+				service.DeliverPending(context.Background())
+				log, err := service.GetDeliveries(&userID, webhook.ID, models.GetWebhookDeliveriesRequest{Status: []string{"failed"}})
+				Expect(err).NotTo(HaveOccurred())
+				return log
+			}).Should(HaveLen(1))
+			service.DeliverPending(context.Background())
+
+			// Assert
+			Expect(receiver.Deliveries()).To(HaveLen(3))
+			log, err := service.GetDeliveries(&userID, webhook.ID, models.GetWebhookDeliveriesRequest{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(log[0].Attempts).To(Equal(3))
+			Expect(log[0].LastStatusCode).To(Equal(http.StatusBadGateway))
+			Expect(log[0].LastError).To(ContainSubstring("502"))
+			Expect(log[0].NextAttemptAt).To(BeNil())
+		})
+
+		It("must fail the pending deliveries of a deleted webhook", func() {
+			// Arrange
+			receiver := webhooktest.NewReceiver()
+			DeferCleanup(receiver.Close)
+			webhook := register(&userID, receiver.URL, entities.WebhookEventBalanceAccrued)
+			service.Emit(context.Background(), accrued())
+			Expect(service.DeleteWebhook(&userID, webhook.ID)).To(Succeed())
+
+			// Act
+			claimed := service.DeliverPending(context.Background())
+
+			// Assert
+			Expect(claimed).To(Equal(1))
+			Expect(receiver.Deliveries()).To(BeEmpty())
+			deliveries, err := storage.Webhooks.GetDeliveries(webhook.ID, nil, 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deliveries[0].Status).To(Equal(entities.WebhookDeliveryFailed))
+		})
+
+		It("must not connect to a private address and keep the connection error out of the log", func() {
+			// Arrange
+			receiver := webhooktest.NewReceiver()
+			DeferCleanup(receiver.Close)
+			webhook := register(&userID, receiver.URL, entities.WebhookEventBalanceAccrued)
+			guarded := services.NewWebhookService(
+				services.WebhookOptions{MaxAttempts: 3, RetryBackoff: time.Hour},
+				storage.Webhooks,
+				&http.Client{Transport: netguard.NewTransport(), CheckRedirect: netguard.NoRedirect},
+				metrics.New(),
+				zap.NewNop(),
+			)
+			guarded.Emit(context.Background(), accrued())
+
+			// Act
+			claimed := guarded.DeliverPending(context.Background())
+
+			// Assert
+			Expect(claimed).To(Equal(1))
+			Expect(receiver.Deliveries()).To(BeEmpty())
+			log, err := guarded.GetDeliveries(&userID, webhook.ID, models.GetWebhookDeliveriesRequest{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(log[0].Status).To(Equal(entities.WebhookDeliveryPending))
+			Expect(log[0].LastError).To(Equal("destination address is not allowed"))
+		})
+	})
+
+	Describe("Management", func() {
+		It("must reject a webhook on localhost or in a private network", func() {
+			// Arrange
+			guarded := services.NewWebhookService(services.WebhookOptions{MaxAttempts: 1}, storage.Webhooks, http.DefaultClient, metrics.New(), zap.NewNop())
+
+			// Act
+			_, loopbackErr := guarded.CreateWebhook(&userID, models.WebhookRequest{
+				URL:    "http://127.0.0.1:8080/hooks",
+				Events: []entities.WebhookEventType{entities.WebhookEventBalanceAccrued},
+			})
+			_, metadataErr := guarded.CreateWebhook(nil, models.WebhookRequest{
+				URL:    "http://169.254.169.254/latest/meta-data",
+				Events: []entities.WebhookEventType{entities.WebhookEventBalanceAccrued},
+			})
+
+			// Assert
+			Expect(loopbackErr).To(MatchError(services.ErrURLNotAllowed))
+			Expect(metadataErr).To(MatchError(services.ErrURLNotAllowed))
+		})
+
+		It("must hide webhooks of other owners", func() {
+			// Arrange
+			webhook := register(&userID, "https://crm.example.com/hooks", entities.WebhookEventBalanceWithdrawn)
+			otherID := userID + 1
+
+			// Act
+			integrators, err := service.GetWebhooks(nil)
+			Expect(err).NotTo(HaveOccurred())
+			deleteErr := service.DeleteWebhook(&otherID, webhook.ID)
+			_, logErr := service.GetDeliveries(nil, webhook.ID, models.GetWebhookDeliveriesRequest{})
+			owned, err := service.GetWebhooks(&userID)
+			Expect(err).NotTo(HaveOccurred())
+
+			// Assert
+			Expect(integrators).To(BeEmpty())
+			Expect(deleteErr).To(MatchError(services.ErrWebhookNotFound))
+			Expect(logErr).To(MatchError(services.ErrWebhookNotFound))
+			Expect(owned).To(HaveLen(1))
+			Expect(owned[0].Secret).To(BeEmpty())
+			Expect(webhook.Secret).To(HaveLen(64))
+		})
+
+		It("must reject a webhook without a valid url or known events", func() {
+			// Act
+			_, noURL := service.CreateWebhook(&userID, models.WebhookRequest{URL: "crm", Events: []entities.WebhookEventType{entities.WebhookEventBalanceAccrued}})
+			_, unknownEvent := service.CreateWebhook(&userID, models.WebhookRequest{URL: "https://crm.example.com", Events: []entities.WebhookEventType{"order.uploaded"}})
+			_, noEvents := service.CreateWebhook(&userID, models.WebhookRequest{URL: "https://crm.example.com"})
+
+			// Assert
+			Expect(noURL).To(MatchError(services.ErrValidation))
+			Expect(unknownEvent).To(MatchError(services.ErrValidation))
+			Expect(noEvents).To(MatchError(services.ErrValidation))
+		})
+	})
+})
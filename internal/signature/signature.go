@@ -0,0 +1,71 @@
+// Package signature Подпись HTTP запросов между сервисами: HMAC-SHA256 от времени отправки и тела запроса.
+// Время входит в подпись, поэтому перехваченный запрос нельзя отправить повторно позже допустимого окна
+package signature
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// HeaderTimestamp время отправки, unix секунды
+	HeaderTimestamp = "X-Gophermart-Timestamp"
+	// HeaderSignature "sha256=" и HMAC-SHA256 в hex от строки "{timestamp}.{body}"
+	HeaderSignature = "X-Gophermart-Signature"
+)
+
+const prefix = "sha256="
+
+var (
+	// ErrMissing нет заголовка времени или подписи
+	ErrMissing = errors.New("request signature is missing")
+	// ErrExpired время отправки дальше допустимого окна от текущего
+	ErrExpired = errors.New("request timestamp is outside the allowed window")
+	// ErrMismatch подпись не совпадает с телом запроса
+	ErrMismatch = errors.New("request signature does not match")
+)
+
+// Sign Значение заголовка HeaderSignature для тела body, отправленного в момент timestamp
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "."))
+	mac.Write(body)
+
+	return prefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// SetHeaders Подписывает запрос с телом body, отправляемый в момент now
+func SetHeaders(header http.Header, secret string, body []byte, now time.Time) {
+	timestamp := now.Unix()
+	header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	header.Set(HeaderSignature, Sign(secret, timestamp, body))
+}
+
+// Verify Проверка подписи запроса с телом body: время отправки не дальше tolerance от now в обе стороны
+// и подпись совпадает. Подписи сравниваются за постоянное время
+func Verify(header http.Header, secret string, body []byte, now time.Time, tolerance time.Duration) error {
+	rawTimestamp, sig := header.Get(HeaderTimestamp), header.Get(HeaderSignature)
+	if rawTimestamp == "" || !strings.HasPrefix(sig, prefix) {
+		return ErrMissing
+	}
+
+	timestamp, err := strconv.ParseInt(rawTimestamp, 10, 64)
+	if err != nil {
+		return ErrMissing
+	}
+	if skew := now.Sub(time.Unix(timestamp, 0)); skew > tolerance || skew < -tolerance {
+		return ErrExpired
+	}
+
+	if !hmac.Equal([]byte(sig), []byte(Sign(secret, timestamp, body))) {
+		return ErrMismatch
+	}
+
+	return nil
+}
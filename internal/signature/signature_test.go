@@ -0,0 +1,98 @@
+package signature
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerify(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	body := []byte(`{"order":"12345678903","status":"PROCESSED","accrual":500}`)
+	signed := func(at time.Time) http.Header {
+		header := http.Header{}
+		SetHeaders(header, "secret", body, at)
+		return header
+	}
+
+	tests := []struct {
+		name    string
+		header  http.Header
+		secret  string
+		body    []byte
+		wantErr error
+	}{
+		{
+			name:   "valid",
+			header: signed(now),
+			secret: "secret",
+			body:   body,
+		},
+		{
+			name:   "clock skew within tolerance",
+			header: signed(now.Add(4 * time.Minute)),
+			secret: "secret",
+			body:   body,
+		},
+		{
+			name:    "no headers",
+			header:  http.Header{},
+			secret:  "secret",
+			body:    body,
+			wantErr: ErrMissing,
+		},
+		{
+			name: "malformed timestamp",
+			header: http.Header{
+				HeaderTimestamp: {"yesterday"},
+				HeaderSignature: {Sign("secret", now.Unix(), body)},
+			},
+			secret:  "secret",
+			body:    body,
+			wantErr: ErrMissing,
+		},
+		{
+			name:    "replayed later",
+			header:  signed(now.Add(-10 * time.Minute)),
+			secret:  "secret",
+			body:    body,
+			wantErr: ErrExpired,
+		},
+		{
+			name:    "other secret",
+			header:  signed(now),
+			secret:  "other",
+			body:    body,
+			wantErr: ErrMismatch,
+		},
+		{
+			name:    "tampered body",
+			header:  signed(now),
+			secret:  "secret",
+			body:    []byte(`{"order":"12345678903","status":"PROCESSED","accrual":5000}`),
+			wantErr: ErrMismatch,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Verify(tt.header, tt.secret, tt.body, now, 5*time.Minute)
+
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestSign(t *testing.T) {
+	header := http.Header{}
+	now := time.Unix(1714564800, 0)
+
+	SetHeaders(header, "secret", []byte("{}"), now)
+
+	assert.Equal(t, strconv.FormatInt(now.Unix(), 10), header.Get(HeaderTimestamp))
+	// printf '1714564800.{}' | openssl dgst -sha256 -hmac secret
+	assert.Equal(t, "sha256=6772f83f980eaa45c478fbaeb2e3661d945e7ba97f73c65ac97333a82742e16f", header.Get(HeaderSignature))
+}
@@ -0,0 +1,73 @@
+// Package sqlite Подключение к SQLite для развёртывания на одном сервере без Postgres
+package sqlite
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	gormsqlite "gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// DriverName Имя драйвера в database/sql
+const DriverName = "sqlite3"
+
+// busyTimeout Сколько соединение ждёт блокировку записи, прежде чем вернуть SQLITE_BUSY
+const busyTimeout = 5 * time.Second
+
+// option Параметр подключения go-sqlite3 и его короткое имя
+type option struct {
+	name  string
+	alias string
+	value string
+}
+
+// options Настройки, без которых репозитории работают неправильно:
+//   - внешние ключи SQLite по умолчанию не проверяет;
+//   - в режиме WAL чтение не ждёт завершения записи;
+//   - immediate транзакция сразу берёт блокировку записи. Иначе две транзакции, начавшие с чтения,
+//     не могут перейти к записи, и одна из них падает с SQLITE_BUSY без ожидания;
+//   - busy_timeout заставляет параллельные записи ждать друг друга, а не падать сразу.
+var options = []option{
+	{name: "_foreign_keys", alias: "_fk", value: "1"},
+	{name: "_journal_mode", alias: "_journal", value: "WAL"},
+	{name: "_txlock", value: "immediate"},
+	{name: "_busy_timeout", alias: "_timeout", value: strconv.FormatInt(busyTimeout.Milliseconds(), 10)},
+}
+
+// DSN Строка подключения к файлу path с обязательными настройками.
+// Параметры, уже указанные в path, не меняются
+func DSN(path string) (string, error) {
+	file, rawQuery, _ := strings.Cut(path, "?")
+
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return "", fmt.Errorf("invalid sqlite parameters: %w", err)
+	}
+
+	for _, o := range options {
+		if !query.Has(o.name) && (o.alias == "" || !query.Has(o.alias)) {
+			query.Set(o.name, o.value)
+		}
+	}
+
+	return file + "?" + query.Encode(), nil
+}
+
+// Open Подключение GORM к файлу path. SQLite хранит время строкой и сравнивает его как строку,
+// поэтому GORM проставляет created_at и updated_at в UTC
+func Open(path string, config *gorm.Config) (*gorm.DB, error) {
+	dsn, err := DSN(path)
+	if err != nil {
+		return nil, err
+	}
+
+	config.NowFunc = func() time.Time {
+		return time.Now().UTC()
+	}
+
+	return gorm.Open(gormsqlite.Open(dsn), config)
+}
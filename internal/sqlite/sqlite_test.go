@@ -0,0 +1,44 @@
+package sqlite
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDSN(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "file path",
+			path: "/var/lib/gophermart/gophermart.db",
+			want: "/var/lib/gophermart/gophermart.db?_busy_timeout=5000&_foreign_keys=1&_journal_mode=WAL&_txlock=immediate",
+		},
+		{
+			name: "explicit parameters are kept",
+			path: "file:gophermart.db?_journal_mode=DELETE&_timeout=100&cache=private",
+			want: "file:gophermart.db?_foreign_keys=1&_journal_mode=DELETE&_timeout=100&_txlock=immediate&cache=private",
+		},
+		{
+			name:    "invalid parameters",
+			path:    "gophermart.db?_txlock=%zz",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dsn, err := DSN(tt.path)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, dsn)
+		})
+	}
+}
@@ -0,0 +1,94 @@
+// Package webhooktest Получатель вебхуков на httptest.Server для тестов: записывает доставки
+// и отвечает заданными кодами, чтобы проверять подпись, повторы и журнал доставок
+package webhooktest
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	"github.com/ShukinDmitriy/gophermart/internal/models"
+	"github.com/ShukinDmitriy/gophermart/internal/signature"
+)
+
+// Delivery Запрос, полученный получателем
+type Delivery struct {
+	Header     http.Header
+	Body       []byte
+	Event      models.WebhookEvent
+	ReceivedAt time.Time
+	// StatusCode код, которым получатель ответил
+	StatusCode int
+}
+
+// Verify Проверка подписи доставки секретом вебхука
+func (d Delivery) Verify(secret string) error {
+	return signature.Verify(d.Header, secret, d.Body, d.ReceivedAt, time.Minute)
+}
+
+type Receiver struct {
+	// URL адрес для регистрации вебхука
+	URL string
+
+	server     *httptest.Server
+	mu         sync.Mutex
+	responses  []int
+	deliveries []Delivery
+	received   chan Delivery
+}
+
+// NewReceiver Получатель отвечает на первые запросы кодами responses по порядку, на остальные 204
+func NewReceiver(responses ...int) *Receiver {
+	r := &Receiver{
+		responses: responses,
+		received:  make(chan Delivery, 100),
+	}
+	r.server = httptest.NewServer(http.HandlerFunc(r.serveHTTP))
+	r.URL = r.server.URL
+
+	return r
+}
+
+func (r *Receiver) Close() {
+	r.server.Close()
+}
+
+// Deliveries Все полученные запросы по порядку
+func (r *Receiver) Deliveries() []Delivery {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]Delivery(nil), r.deliveries...)
+}
+
+// Received Канал полученных запросов, для ожидания доставки в тестах
+func (r *Receiver) Received() <-chan Delivery {
+	return r.received
+}
+
+func (r *Receiver) serveHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	delivery := Delivery{
+		Header:     req.Header.Clone(),
+		Body:       body,
+		ReceivedAt: time.Now(),
+		StatusCode: http.StatusNoContent,
+	}
+	_ = json.Unmarshal(body, &delivery.Event)
+
+	r.mu.Lock()
+	if len(r.responses) > 0 {
+		delivery.StatusCode, r.responses = r.responses[0], r.responses[1:]
+	}
+	r.deliveries = append(r.deliveries, delivery)
+	r.mu.Unlock()
+
+	select {
+	case r.received <- delivery:
+	default:
+	}
+	w.WriteHeader(delivery.StatusCode)
+}
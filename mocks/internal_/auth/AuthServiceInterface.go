@@ -1,9 +1,11 @@
-// Code generated by mockery v2.43.2. DO NOT EDIT.
+// Code generated by mockery v2.53.7. DO NOT EDIT.
 
 package auth
 
 import (
+	auth "github.com/ShukinDmitriy/gophermart/internal/auth"
 	echo "github.com/labstack/echo/v4"
+
 	mock "github.com/stretchr/testify/mock"
 
 	models "github.com/ShukinDmitriy/gophermart/internal/models"
@@ -22,6 +24,64 @@ func (_m *AuthServiceInterface) EXPECT() *AuthServiceInterface_Expecter {
 	return &AuthServiceInterface_Expecter{mock: &_m.Mock}
 }
 
+// GenerateTokens provides a mock function with given fields: user
+func (_m *AuthServiceInterface) GenerateTokens(user *models.UserInfoResponse) (*auth.Tokens, error) {
+	ret := _m.Called(user)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GenerateTokens")
+	}
+
+	var r0 *auth.Tokens
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*models.UserInfoResponse) (*auth.Tokens, error)); ok {
+		return rf(user)
+	}
+	if rf, ok := ret.Get(0).(func(*models.UserInfoResponse) *auth.Tokens); ok {
+		r0 = rf(user)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*auth.Tokens)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*models.UserInfoResponse) error); ok {
+		r1 = rf(user)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AuthServiceInterface_GenerateTokens_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GenerateTokens'
+type AuthServiceInterface_GenerateTokens_Call struct {
+	*mock.Call
+}
+
+// GenerateTokens is a helper method to define mock.On call
+//   - user *models.UserInfoResponse
+func (_e *AuthServiceInterface_Expecter) GenerateTokens(user interface{}) *AuthServiceInterface_GenerateTokens_Call {
+	return &AuthServiceInterface_GenerateTokens_Call{Call: _e.mock.On("GenerateTokens", user)}
+}
+
+func (_c *AuthServiceInterface_GenerateTokens_Call) Run(run func(user *models.UserInfoResponse)) *AuthServiceInterface_GenerateTokens_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*models.UserInfoResponse))
+	})
+	return _c
+}
+
+func (_c *AuthServiceInterface_GenerateTokens_Call) Return(_a0 *auth.Tokens, _a1 error) *AuthServiceInterface_GenerateTokens_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *AuthServiceInterface_GenerateTokens_Call) RunAndReturn(run func(*models.UserInfoResponse) (*auth.Tokens, error)) *AuthServiceInterface_GenerateTokens_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GenerateTokensAndSetCookies provides a mock function with given fields: c, user
 func (_m *AuthServiceInterface) GenerateTokensAndSetCookies(c echo.Context, user *models.UserInfoResponse) error {
 	ret := _m.Called(c, user)
@@ -115,6 +175,62 @@ func (_c *AuthServiceInterface_GetUserID_Call) RunAndReturn(run func(echo.Contex
 	return _c
 }
 
+// ParseAccessToken provides a mock function with given fields: tokenString
+func (_m *AuthServiceInterface) ParseAccessToken(tokenString string) (uint, error) {
+	ret := _m.Called(tokenString)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ParseAccessToken")
+	}
+
+	var r0 uint
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (uint, error)); ok {
+		return rf(tokenString)
+	}
+	if rf, ok := ret.Get(0).(func(string) uint); ok {
+		r0 = rf(tokenString)
+	} else {
+		r0 = ret.Get(0).(uint)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(tokenString)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AuthServiceInterface_ParseAccessToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ParseAccessToken'
+type AuthServiceInterface_ParseAccessToken_Call struct {
+	*mock.Call
+}
+
+// ParseAccessToken is a helper method to define mock.On call
+//   - tokenString string
+func (_e *AuthServiceInterface_Expecter) ParseAccessToken(tokenString interface{}) *AuthServiceInterface_ParseAccessToken_Call {
+	return &AuthServiceInterface_ParseAccessToken_Call{Call: _e.mock.On("ParseAccessToken", tokenString)}
+}
+
+func (_c *AuthServiceInterface_ParseAccessToken_Call) Run(run func(tokenString string)) *AuthServiceInterface_ParseAccessToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *AuthServiceInterface_ParseAccessToken_Call) Return(_a0 uint, _a1 error) *AuthServiceInterface_ParseAccessToken_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *AuthServiceInterface_ParseAccessToken_Call) RunAndReturn(run func(string) (uint, error)) *AuthServiceInterface_ParseAccessToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewAuthServiceInterface creates a new instance of AuthServiceInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewAuthServiceInterface(t interface {
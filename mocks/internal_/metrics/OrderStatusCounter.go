@@ -0,0 +1,93 @@
+// Code generated by mockery v2.53.7. DO NOT EDIT.
+
+package metrics
+
+import (
+	entities "github.com/ShukinDmitriy/gophermart/internal/entities"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// OrderStatusCounter is an autogenerated mock type for the OrderStatusCounter type
+type OrderStatusCounter struct {
+	mock.Mock
+}
+
+type OrderStatusCounter_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *OrderStatusCounter) EXPECT() *OrderStatusCounter_Expecter {
+	return &OrderStatusCounter_Expecter{mock: &_m.Mock}
+}
+
+// CountByStatus provides a mock function with no fields
+func (_m *OrderStatusCounter) CountByStatus() (map[entities.OrderStatus]int64, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountByStatus")
+	}
+
+	var r0 map[entities.OrderStatus]int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (map[entities.OrderStatus]int64, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() map[entities.OrderStatus]int64); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[entities.OrderStatus]int64)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OrderStatusCounter_CountByStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CountByStatus'
+type OrderStatusCounter_CountByStatus_Call struct {
+	*mock.Call
+}
+
+// CountByStatus is a helper method to define mock.On call
+func (_e *OrderStatusCounter_Expecter) CountByStatus() *OrderStatusCounter_CountByStatus_Call {
+	return &OrderStatusCounter_CountByStatus_Call{Call: _e.mock.On("CountByStatus")}
+}
+
+func (_c *OrderStatusCounter_CountByStatus_Call) Run(run func()) *OrderStatusCounter_CountByStatus_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *OrderStatusCounter_CountByStatus_Call) Return(_a0 map[entities.OrderStatus]int64, _a1 error) *OrderStatusCounter_CountByStatus_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *OrderStatusCounter_CountByStatus_Call) RunAndReturn(run func() (map[entities.OrderStatus]int64, error)) *OrderStatusCounter_CountByStatus_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewOrderStatusCounter creates a new instance of OrderStatusCounter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewOrderStatusCounter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *OrderStatusCounter {
+	mock := &OrderStatusCounter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
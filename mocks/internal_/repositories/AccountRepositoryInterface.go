@@ -1,10 +1,14 @@
-// Code generated by mockery v2.43.2. DO NOT EDIT.
+// Code generated by mockery v2.53.7. DO NOT EDIT.
 
 package repositories
 
 import (
+	context "context"
+
 	entities "github.com/ShukinDmitriy/gophermart/internal/entities"
 	mock "github.com/stretchr/testify/mock"
+
+	repositories "github.com/ShukinDmitriy/gophermart/internal/repositories"
 )
 
 // AccountRepositoryInterface is an autogenerated mock type for the AccountRepositoryInterface type
@@ -79,6 +83,54 @@ func (_c *AccountRepositoryInterface_FindByUserID_Call) RunAndReturn(run func(ui
 	return _c
 }
 
+// WithContext provides a mock function with given fields: ctx
+func (_m *AccountRepositoryInterface) WithContext(ctx context.Context) repositories.AccountRepositoryInterface {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WithContext")
+	}
+
+	var r0 repositories.AccountRepositoryInterface
+	if rf, ok := ret.Get(0).(func(context.Context) repositories.AccountRepositoryInterface); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(repositories.AccountRepositoryInterface)
+		}
+	}
+
+	return r0
+}
+
+// AccountRepositoryInterface_WithContext_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'WithContext'
+type AccountRepositoryInterface_WithContext_Call struct {
+	*mock.Call
+}
+
+// WithContext is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *AccountRepositoryInterface_Expecter) WithContext(ctx interface{}) *AccountRepositoryInterface_WithContext_Call {
+	return &AccountRepositoryInterface_WithContext_Call{Call: _e.mock.On("WithContext", ctx)}
+}
+
+func (_c *AccountRepositoryInterface_WithContext_Call) Run(run func(ctx context.Context)) *AccountRepositoryInterface_WithContext_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *AccountRepositoryInterface_WithContext_Call) Return(_a0 repositories.AccountRepositoryInterface) *AccountRepositoryInterface_WithContext_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *AccountRepositoryInterface_WithContext_Call) RunAndReturn(run func(context.Context) repositories.AccountRepositoryInterface) *AccountRepositoryInterface_WithContext_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewAccountRepositoryInterface creates a new instance of AccountRepositoryInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewAccountRepositoryInterface(t interface {
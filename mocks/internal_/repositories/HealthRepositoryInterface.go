@@ -0,0 +1,192 @@
+// Code generated by mockery v2.53.7. DO NOT EDIT.
+
+package repositories
+
+import (
+	context "context"
+
+	repositories "github.com/ShukinDmitriy/gophermart/internal/repositories"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// HealthRepositoryInterface is an autogenerated mock type for the HealthRepositoryInterface type
+type HealthRepositoryInterface struct {
+	mock.Mock
+}
+
+type HealthRepositoryInterface_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *HealthRepositoryInterface) EXPECT() *HealthRepositoryInterface_Expecter {
+	return &HealthRepositoryInterface_Expecter{mock: &_m.Mock}
+}
+
+// MigrationVersion provides a mock function with no fields
+func (_m *HealthRepositoryInterface) MigrationVersion() (uint, bool, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for MigrationVersion")
+	}
+
+	var r0 uint
+	var r1 bool
+	var r2 error
+	if rf, ok := ret.Get(0).(func() (uint, bool, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() uint); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(uint)
+	}
+
+	if rf, ok := ret.Get(1).(func() bool); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	if rf, ok := ret.Get(2).(func() error); ok {
+		r2 = rf()
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// HealthRepositoryInterface_MigrationVersion_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MigrationVersion'
+type HealthRepositoryInterface_MigrationVersion_Call struct {
+	*mock.Call
+}
+
+// MigrationVersion is a helper method to define mock.On call
+func (_e *HealthRepositoryInterface_Expecter) MigrationVersion() *HealthRepositoryInterface_MigrationVersion_Call {
+	return &HealthRepositoryInterface_MigrationVersion_Call{Call: _e.mock.On("MigrationVersion")}
+}
+
+func (_c *HealthRepositoryInterface_MigrationVersion_Call) Run(run func()) *HealthRepositoryInterface_MigrationVersion_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *HealthRepositoryInterface_MigrationVersion_Call) Return(version uint, dirty bool, err error) *HealthRepositoryInterface_MigrationVersion_Call {
+	_c.Call.Return(version, dirty, err)
+	return _c
+}
+
+func (_c *HealthRepositoryInterface_MigrationVersion_Call) RunAndReturn(run func() (uint, bool, error)) *HealthRepositoryInterface_MigrationVersion_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Ping provides a mock function with no fields
+func (_m *HealthRepositoryInterface) Ping() error {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Ping")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// HealthRepositoryInterface_Ping_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Ping'
+type HealthRepositoryInterface_Ping_Call struct {
+	*mock.Call
+}
+
+// Ping is a helper method to define mock.On call
+func (_e *HealthRepositoryInterface_Expecter) Ping() *HealthRepositoryInterface_Ping_Call {
+	return &HealthRepositoryInterface_Ping_Call{Call: _e.mock.On("Ping")}
+}
+
+func (_c *HealthRepositoryInterface_Ping_Call) Run(run func()) *HealthRepositoryInterface_Ping_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *HealthRepositoryInterface_Ping_Call) Return(_a0 error) *HealthRepositoryInterface_Ping_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *HealthRepositoryInterface_Ping_Call) RunAndReturn(run func() error) *HealthRepositoryInterface_Ping_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// WithContext provides a mock function with given fields: ctx
+func (_m *HealthRepositoryInterface) WithContext(ctx context.Context) repositories.HealthRepositoryInterface {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WithContext")
+	}
+
+	var r0 repositories.HealthRepositoryInterface
+	if rf, ok := ret.Get(0).(func(context.Context) repositories.HealthRepositoryInterface); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(repositories.HealthRepositoryInterface)
+		}
+	}
+
+	return r0
+}
+
+// HealthRepositoryInterface_WithContext_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'WithContext'
+type HealthRepositoryInterface_WithContext_Call struct {
+	*mock.Call
+}
+
+// WithContext is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *HealthRepositoryInterface_Expecter) WithContext(ctx interface{}) *HealthRepositoryInterface_WithContext_Call {
+	return &HealthRepositoryInterface_WithContext_Call{Call: _e.mock.On("WithContext", ctx)}
+}
+
+func (_c *HealthRepositoryInterface_WithContext_Call) Run(run func(ctx context.Context)) *HealthRepositoryInterface_WithContext_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *HealthRepositoryInterface_WithContext_Call) Return(_a0 repositories.HealthRepositoryInterface) *HealthRepositoryInterface_WithContext_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *HealthRepositoryInterface_WithContext_Call) RunAndReturn(run func(context.Context) repositories.HealthRepositoryInterface) *HealthRepositoryInterface_WithContext_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewHealthRepositoryInterface creates a new instance of HealthRepositoryInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewHealthRepositoryInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *HealthRepositoryInterface {
+	mock := &HealthRepositoryInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,474 @@
+// Code generated by mockery v2.53.7. DO NOT EDIT.
+
+package repositories
+
+import (
+	context "context"
+
+	entities "github.com/ShukinDmitriy/gophermart/internal/entities"
+	mock "github.com/stretchr/testify/mock"
+
+	repositories "github.com/ShukinDmitriy/gophermart/internal/repositories"
+
+	time "time"
+)
+
+// NotificationRepositoryInterface is an autogenerated mock type for the NotificationRepositoryInterface type
+type NotificationRepositoryInterface struct {
+	mock.Mock
+}
+
+type NotificationRepositoryInterface_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *NotificationRepositoryInterface) EXPECT() *NotificationRepositoryInterface_Expecter {
+	return &NotificationRepositoryInterface_Expecter{mock: &_m.Mock}
+}
+
+// ClaimDeliveries provides a mock function with given fields: now, lease, limit
+func (_m *NotificationRepositoryInterface) ClaimDeliveries(now time.Time, lease time.Duration, limit int) ([]*entities.NotificationDelivery, error) {
+	ret := _m.Called(now, lease, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClaimDeliveries")
+	}
+
+	var r0 []*entities.NotificationDelivery
+	var r1 error
+	if rf, ok := ret.Get(0).(func(time.Time, time.Duration, int) ([]*entities.NotificationDelivery, error)); ok {
+		return rf(now, lease, limit)
+	}
+	if rf, ok := ret.Get(0).(func(time.Time, time.Duration, int) []*entities.NotificationDelivery); ok {
+		r0 = rf(now, lease, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entities.NotificationDelivery)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(time.Time, time.Duration, int) error); ok {
+		r1 = rf(now, lease, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NotificationRepositoryInterface_ClaimDeliveries_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ClaimDeliveries'
+type NotificationRepositoryInterface_ClaimDeliveries_Call struct {
+	*mock.Call
+}
+
+// ClaimDeliveries is a helper method to define mock.On call
+//   - now time.Time
+//   - lease time.Duration
+//   - limit int
+func (_e *NotificationRepositoryInterface_Expecter) ClaimDeliveries(now interface{}, lease interface{}, limit interface{}) *NotificationRepositoryInterface_ClaimDeliveries_Call {
+	return &NotificationRepositoryInterface_ClaimDeliveries_Call{Call: _e.mock.On("ClaimDeliveries", now, lease, limit)}
+}
+
+func (_c *NotificationRepositoryInterface_ClaimDeliveries_Call) Run(run func(now time.Time, lease time.Duration, limit int)) *NotificationRepositoryInterface_ClaimDeliveries_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(time.Time), args[1].(time.Duration), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *NotificationRepositoryInterface_ClaimDeliveries_Call) Return(_a0 []*entities.NotificationDelivery, _a1 error) *NotificationRepositoryInterface_ClaimDeliveries_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *NotificationRepositoryInterface_ClaimDeliveries_Call) RunAndReturn(run func(time.Time, time.Duration, int) ([]*entities.NotificationDelivery, error)) *NotificationRepositoryInterface_ClaimDeliveries_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CountUnread provides a mock function with given fields: userID
+func (_m *NotificationRepositoryInterface) CountUnread(userID uint) (int64, error) {
+	ret := _m.Called(userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountUnread")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint) (int64, error)); ok {
+		return rf(userID)
+	}
+	if rf, ok := ret.Get(0).(func(uint) int64); ok {
+		r0 = rf(userID)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NotificationRepositoryInterface_CountUnread_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CountUnread'
+type NotificationRepositoryInterface_CountUnread_Call struct {
+	*mock.Call
+}
+
+// CountUnread is a helper method to define mock.On call
+//   - userID uint
+func (_e *NotificationRepositoryInterface_Expecter) CountUnread(userID interface{}) *NotificationRepositoryInterface_CountUnread_Call {
+	return &NotificationRepositoryInterface_CountUnread_Call{Call: _e.mock.On("CountUnread", userID)}
+}
+
+func (_c *NotificationRepositoryInterface_CountUnread_Call) Run(run func(userID uint)) *NotificationRepositoryInterface_CountUnread_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(uint))
+	})
+	return _c
+}
+
+func (_c *NotificationRepositoryInterface_CountUnread_Call) Return(_a0 int64, _a1 error) *NotificationRepositoryInterface_CountUnread_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *NotificationRepositoryInterface_CountUnread_Call) RunAndReturn(run func(uint) (int64, error)) *NotificationRepositoryInterface_CountUnread_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateNotification provides a mock function with given fields: notification, deliveries
+func (_m *NotificationRepositoryInterface) CreateNotification(notification *entities.Notification, deliveries []*entities.NotificationDelivery) error {
+	ret := _m.Called(notification, deliveries)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateNotification")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*entities.Notification, []*entities.NotificationDelivery) error); ok {
+		r0 = rf(notification, deliveries)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NotificationRepositoryInterface_CreateNotification_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateNotification'
+type NotificationRepositoryInterface_CreateNotification_Call struct {
+	*mock.Call
+}
+
+// CreateNotification is a helper method to define mock.On call
+//   - notification *entities.Notification
+//   - deliveries []*entities.NotificationDelivery
+func (_e *NotificationRepositoryInterface_Expecter) CreateNotification(notification interface{}, deliveries interface{}) *NotificationRepositoryInterface_CreateNotification_Call {
+	return &NotificationRepositoryInterface_CreateNotification_Call{Call: _e.mock.On("CreateNotification", notification, deliveries)}
+}
+
+func (_c *NotificationRepositoryInterface_CreateNotification_Call) Run(run func(notification *entities.Notification, deliveries []*entities.NotificationDelivery)) *NotificationRepositoryInterface_CreateNotification_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*entities.Notification), args[1].([]*entities.NotificationDelivery))
+	})
+	return _c
+}
+
+func (_c *NotificationRepositoryInterface_CreateNotification_Call) Return(_a0 error) *NotificationRepositoryInterface_CreateNotification_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *NotificationRepositoryInterface_CreateNotification_Call) RunAndReturn(run func(*entities.Notification, []*entities.NotificationDelivery) error) *NotificationRepositoryInterface_CreateNotification_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindNotification provides a mock function with given fields: id
+func (_m *NotificationRepositoryInterface) FindNotification(id uint) (*entities.Notification, error) {
+	ret := _m.Called(id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindNotification")
+	}
+
+	var r0 *entities.Notification
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint) (*entities.Notification, error)); ok {
+		return rf(id)
+	}
+	if rf, ok := ret.Get(0).(func(uint) *entities.Notification); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.Notification)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NotificationRepositoryInterface_FindNotification_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindNotification'
+type NotificationRepositoryInterface_FindNotification_Call struct {
+	*mock.Call
+}
+
+// FindNotification is a helper method to define mock.On call
+//   - id uint
+func (_e *NotificationRepositoryInterface_Expecter) FindNotification(id interface{}) *NotificationRepositoryInterface_FindNotification_Call {
+	return &NotificationRepositoryInterface_FindNotification_Call{Call: _e.mock.On("FindNotification", id)}
+}
+
+func (_c *NotificationRepositoryInterface_FindNotification_Call) Run(run func(id uint)) *NotificationRepositoryInterface_FindNotification_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(uint))
+	})
+	return _c
+}
+
+func (_c *NotificationRepositoryInterface_FindNotification_Call) Return(_a0 *entities.Notification, _a1 error) *NotificationRepositoryInterface_FindNotification_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *NotificationRepositoryInterface_FindNotification_Call) RunAndReturn(run func(uint) (*entities.Notification, error)) *NotificationRepositoryInterface_FindNotification_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetNotifications provides a mock function with given fields: userID, unreadOnly, limit
+func (_m *NotificationRepositoryInterface) GetNotifications(userID uint, unreadOnly bool, limit int) ([]*entities.Notification, error) {
+	ret := _m.Called(userID, unreadOnly, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetNotifications")
+	}
+
+	var r0 []*entities.Notification
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint, bool, int) ([]*entities.Notification, error)); ok {
+		return rf(userID, unreadOnly, limit)
+	}
+	if rf, ok := ret.Get(0).(func(uint, bool, int) []*entities.Notification); ok {
+		r0 = rf(userID, unreadOnly, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entities.Notification)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint, bool, int) error); ok {
+		r1 = rf(userID, unreadOnly, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NotificationRepositoryInterface_GetNotifications_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetNotifications'
+type NotificationRepositoryInterface_GetNotifications_Call struct {
+	*mock.Call
+}
+
+// GetNotifications is a helper method to define mock.On call
+//   - userID uint
+//   - unreadOnly bool
+//   - limit int
+func (_e *NotificationRepositoryInterface_Expecter) GetNotifications(userID interface{}, unreadOnly interface{}, limit interface{}) *NotificationRepositoryInterface_GetNotifications_Call {
+	return &NotificationRepositoryInterface_GetNotifications_Call{Call: _e.mock.On("GetNotifications", userID, unreadOnly, limit)}
+}
+
+func (_c *NotificationRepositoryInterface_GetNotifications_Call) Run(run func(userID uint, unreadOnly bool, limit int)) *NotificationRepositoryInterface_GetNotifications_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(uint), args[1].(bool), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *NotificationRepositoryInterface_GetNotifications_Call) Return(_a0 []*entities.Notification, _a1 error) *NotificationRepositoryInterface_GetNotifications_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *NotificationRepositoryInterface_GetNotifications_Call) RunAndReturn(run func(uint, bool, int) ([]*entities.Notification, error)) *NotificationRepositoryInterface_GetNotifications_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MarkRead provides a mock function with given fields: userID, ids, readAt
+func (_m *NotificationRepositoryInterface) MarkRead(userID uint, ids []uint, readAt time.Time) (int64, error) {
+	ret := _m.Called(userID, ids, readAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkRead")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint, []uint, time.Time) (int64, error)); ok {
+		return rf(userID, ids, readAt)
+	}
+	if rf, ok := ret.Get(0).(func(uint, []uint, time.Time) int64); ok {
+		r0 = rf(userID, ids, readAt)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(uint, []uint, time.Time) error); ok {
+		r1 = rf(userID, ids, readAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NotificationRepositoryInterface_MarkRead_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MarkRead'
+type NotificationRepositoryInterface_MarkRead_Call struct {
+	*mock.Call
+}
+
+// MarkRead is a helper method to define mock.On call
+//   - userID uint
+//   - ids []uint
+//   - readAt time.Time
+func (_e *NotificationRepositoryInterface_Expecter) MarkRead(userID interface{}, ids interface{}, readAt interface{}) *NotificationRepositoryInterface_MarkRead_Call {
+	return &NotificationRepositoryInterface_MarkRead_Call{Call: _e.mock.On("MarkRead", userID, ids, readAt)}
+}
+
+func (_c *NotificationRepositoryInterface_MarkRead_Call) Run(run func(userID uint, ids []uint, readAt time.Time)) *NotificationRepositoryInterface_MarkRead_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(uint), args[1].([]uint), args[2].(time.Time))
+	})
+	return _c
+}
+
+func (_c *NotificationRepositoryInterface_MarkRead_Call) Return(_a0 int64, _a1 error) *NotificationRepositoryInterface_MarkRead_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *NotificationRepositoryInterface_MarkRead_Call) RunAndReturn(run func(uint, []uint, time.Time) (int64, error)) *NotificationRepositoryInterface_MarkRead_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateDelivery provides a mock function with given fields: delivery
+func (_m *NotificationRepositoryInterface) UpdateDelivery(delivery *entities.NotificationDelivery) error {
+	ret := _m.Called(delivery)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateDelivery")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*entities.NotificationDelivery) error); ok {
+		r0 = rf(delivery)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NotificationRepositoryInterface_UpdateDelivery_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateDelivery'
+type NotificationRepositoryInterface_UpdateDelivery_Call struct {
+	*mock.Call
+}
+
+// UpdateDelivery is a helper method to define mock.On call
+//   - delivery *entities.NotificationDelivery
+func (_e *NotificationRepositoryInterface_Expecter) UpdateDelivery(delivery interface{}) *NotificationRepositoryInterface_UpdateDelivery_Call {
+	return &NotificationRepositoryInterface_UpdateDelivery_Call{Call: _e.mock.On("UpdateDelivery", delivery)}
+}
+
+func (_c *NotificationRepositoryInterface_UpdateDelivery_Call) Run(run func(delivery *entities.NotificationDelivery)) *NotificationRepositoryInterface_UpdateDelivery_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*entities.NotificationDelivery))
+	})
+	return _c
+}
+
+func (_c *NotificationRepositoryInterface_UpdateDelivery_Call) Return(_a0 error) *NotificationRepositoryInterface_UpdateDelivery_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *NotificationRepositoryInterface_UpdateDelivery_Call) RunAndReturn(run func(*entities.NotificationDelivery) error) *NotificationRepositoryInterface_UpdateDelivery_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// WithContext provides a mock function with given fields: ctx
+func (_m *NotificationRepositoryInterface) WithContext(ctx context.Context) repositories.NotificationRepositoryInterface {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WithContext")
+	}
+
+	var r0 repositories.NotificationRepositoryInterface
+	if rf, ok := ret.Get(0).(func(context.Context) repositories.NotificationRepositoryInterface); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(repositories.NotificationRepositoryInterface)
+		}
+	}
+
+	return r0
+}
+
+// NotificationRepositoryInterface_WithContext_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'WithContext'
+type NotificationRepositoryInterface_WithContext_Call struct {
+	*mock.Call
+}
+
+// WithContext is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *NotificationRepositoryInterface_Expecter) WithContext(ctx interface{}) *NotificationRepositoryInterface_WithContext_Call {
+	return &NotificationRepositoryInterface_WithContext_Call{Call: _e.mock.On("WithContext", ctx)}
+}
+
+func (_c *NotificationRepositoryInterface_WithContext_Call) Run(run func(ctx context.Context)) *NotificationRepositoryInterface_WithContext_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *NotificationRepositoryInterface_WithContext_Call) Return(_a0 repositories.NotificationRepositoryInterface) *NotificationRepositoryInterface_WithContext_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *NotificationRepositoryInterface_WithContext_Call) RunAndReturn(run func(context.Context) repositories.NotificationRepositoryInterface) *NotificationRepositoryInterface_WithContext_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewNotificationRepositoryInterface creates a new instance of NotificationRepositoryInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewNotificationRepositoryInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *NotificationRepositoryInterface {
+	mock := &NotificationRepositoryInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
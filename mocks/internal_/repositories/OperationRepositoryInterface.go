@@ -1,10 +1,16 @@
-// Code generated by mockery v2.43.2. DO NOT EDIT.
+// Code generated by mockery v2.53.7. DO NOT EDIT.
 
 package repositories
 
 import (
-	models "github.com/ShukinDmitriy/gophermart/internal/models"
+	context "context"
+
+	entities "github.com/ShukinDmitriy/gophermart/internal/entities"
 	mock "github.com/stretchr/testify/mock"
+
+	models "github.com/ShukinDmitriy/gophermart/internal/models"
+
+	repositories "github.com/ShukinDmitriy/gophermart/internal/repositories"
 )
 
 // OperationRepositoryInterface is an autogenerated mock type for the OperationRepositoryInterface type
@@ -116,6 +122,180 @@ func (_c *OperationRepositoryInterface_CreateWithdrawn_Call) RunAndReturn(run fu
 	return _c
 }
 
+// FindAccrualByOrderNumber provides a mock function with given fields: orderNumber
+func (_m *OperationRepositoryInterface) FindAccrualByOrderNumber(orderNumber string) (*entities.Operation, error) {
+	ret := _m.Called(orderNumber)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindAccrualByOrderNumber")
+	}
+
+	var r0 *entities.Operation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*entities.Operation, error)); ok {
+		return rf(orderNumber)
+	}
+	if rf, ok := ret.Get(0).(func(string) *entities.Operation); ok {
+		r0 = rf(orderNumber)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.Operation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(orderNumber)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OperationRepositoryInterface_FindAccrualByOrderNumber_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindAccrualByOrderNumber'
+type OperationRepositoryInterface_FindAccrualByOrderNumber_Call struct {
+	*mock.Call
+}
+
+// FindAccrualByOrderNumber is a helper method to define mock.On call
+//   - orderNumber string
+func (_e *OperationRepositoryInterface_Expecter) FindAccrualByOrderNumber(orderNumber interface{}) *OperationRepositoryInterface_FindAccrualByOrderNumber_Call {
+	return &OperationRepositoryInterface_FindAccrualByOrderNumber_Call{Call: _e.mock.On("FindAccrualByOrderNumber", orderNumber)}
+}
+
+func (_c *OperationRepositoryInterface_FindAccrualByOrderNumber_Call) Run(run func(orderNumber string)) *OperationRepositoryInterface_FindAccrualByOrderNumber_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *OperationRepositoryInterface_FindAccrualByOrderNumber_Call) Return(_a0 *entities.Operation, _a1 error) *OperationRepositoryInterface_FindAccrualByOrderNumber_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *OperationRepositoryInterface_FindAccrualByOrderNumber_Call) RunAndReturn(run func(string) (*entities.Operation, error)) *OperationRepositoryInterface_FindAccrualByOrderNumber_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetOperationsByOrderNumber provides a mock function with given fields: orderNumber
+func (_m *OperationRepositoryInterface) GetOperationsByOrderNumber(orderNumber string) ([]*entities.Operation, error) {
+	ret := _m.Called(orderNumber)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOperationsByOrderNumber")
+	}
+
+	var r0 []*entities.Operation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]*entities.Operation, error)); ok {
+		return rf(orderNumber)
+	}
+	if rf, ok := ret.Get(0).(func(string) []*entities.Operation); ok {
+		r0 = rf(orderNumber)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entities.Operation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(orderNumber)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OperationRepositoryInterface_GetOperationsByOrderNumber_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetOperationsByOrderNumber'
+type OperationRepositoryInterface_GetOperationsByOrderNumber_Call struct {
+	*mock.Call
+}
+
+// GetOperationsByOrderNumber is a helper method to define mock.On call
+//   - orderNumber string
+func (_e *OperationRepositoryInterface_Expecter) GetOperationsByOrderNumber(orderNumber interface{}) *OperationRepositoryInterface_GetOperationsByOrderNumber_Call {
+	return &OperationRepositoryInterface_GetOperationsByOrderNumber_Call{Call: _e.mock.On("GetOperationsByOrderNumber", orderNumber)}
+}
+
+func (_c *OperationRepositoryInterface_GetOperationsByOrderNumber_Call) Run(run func(orderNumber string)) *OperationRepositoryInterface_GetOperationsByOrderNumber_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *OperationRepositoryInterface_GetOperationsByOrderNumber_Call) Return(_a0 []*entities.Operation, _a1 error) *OperationRepositoryInterface_GetOperationsByOrderNumber_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *OperationRepositoryInterface_GetOperationsByOrderNumber_Call) RunAndReturn(run func(string) ([]*entities.Operation, error)) *OperationRepositoryInterface_GetOperationsByOrderNumber_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetOperationsPage provides a mock function with given fields: filter
+func (_m *OperationRepositoryInterface) GetOperationsPage(filter models.OperationSearchFilter) (*models.GetOperationsPage, error) {
+	ret := _m.Called(filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOperationsPage")
+	}
+
+	var r0 *models.GetOperationsPage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(models.OperationSearchFilter) (*models.GetOperationsPage, error)); ok {
+		return rf(filter)
+	}
+	if rf, ok := ret.Get(0).(func(models.OperationSearchFilter) *models.GetOperationsPage); ok {
+		r0 = rf(filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.GetOperationsPage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(models.OperationSearchFilter) error); ok {
+		r1 = rf(filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OperationRepositoryInterface_GetOperationsPage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetOperationsPage'
+type OperationRepositoryInterface_GetOperationsPage_Call struct {
+	*mock.Call
+}
+
+// GetOperationsPage is a helper method to define mock.On call
+//   - filter models.OperationSearchFilter
+func (_e *OperationRepositoryInterface_Expecter) GetOperationsPage(filter interface{}) *OperationRepositoryInterface_GetOperationsPage_Call {
+	return &OperationRepositoryInterface_GetOperationsPage_Call{Call: _e.mock.On("GetOperationsPage", filter)}
+}
+
+func (_c *OperationRepositoryInterface_GetOperationsPage_Call) Run(run func(filter models.OperationSearchFilter)) *OperationRepositoryInterface_GetOperationsPage_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(models.OperationSearchFilter))
+	})
+	return _c
+}
+
+func (_c *OperationRepositoryInterface_GetOperationsPage_Call) Return(_a0 *models.GetOperationsPage, _a1 error) *OperationRepositoryInterface_GetOperationsPage_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *OperationRepositoryInterface_GetOperationsPage_Call) RunAndReturn(run func(models.OperationSearchFilter) (*models.GetOperationsPage, error)) *OperationRepositoryInterface_GetOperationsPage_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetWithdrawalsByAccountID provides a mock function with given fields: accountID
 func (_m *OperationRepositoryInterface) GetWithdrawalsByAccountID(accountID uint) ([]models.GetWithdrawalsResponse, error) {
 	ret := _m.Called(accountID)
@@ -174,6 +354,64 @@ func (_c *OperationRepositoryInterface_GetWithdrawalsByAccountID_Call) RunAndRet
 	return _c
 }
 
+// GetWithdrawalsPage provides a mock function with given fields: filter
+func (_m *OperationRepositoryInterface) GetWithdrawalsPage(filter models.OperationSearchFilter) (*models.GetWithdrawalsPage, error) {
+	ret := _m.Called(filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWithdrawalsPage")
+	}
+
+	var r0 *models.GetWithdrawalsPage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(models.OperationSearchFilter) (*models.GetWithdrawalsPage, error)); ok {
+		return rf(filter)
+	}
+	if rf, ok := ret.Get(0).(func(models.OperationSearchFilter) *models.GetWithdrawalsPage); ok {
+		r0 = rf(filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.GetWithdrawalsPage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(models.OperationSearchFilter) error); ok {
+		r1 = rf(filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OperationRepositoryInterface_GetWithdrawalsPage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetWithdrawalsPage'
+type OperationRepositoryInterface_GetWithdrawalsPage_Call struct {
+	*mock.Call
+}
+
+// GetWithdrawalsPage is a helper method to define mock.On call
+//   - filter models.OperationSearchFilter
+func (_e *OperationRepositoryInterface_Expecter) GetWithdrawalsPage(filter interface{}) *OperationRepositoryInterface_GetWithdrawalsPage_Call {
+	return &OperationRepositoryInterface_GetWithdrawalsPage_Call{Call: _e.mock.On("GetWithdrawalsPage", filter)}
+}
+
+func (_c *OperationRepositoryInterface_GetWithdrawalsPage_Call) Run(run func(filter models.OperationSearchFilter)) *OperationRepositoryInterface_GetWithdrawalsPage_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(models.OperationSearchFilter))
+	})
+	return _c
+}
+
+func (_c *OperationRepositoryInterface_GetWithdrawalsPage_Call) Return(_a0 *models.GetWithdrawalsPage, _a1 error) *OperationRepositoryInterface_GetWithdrawalsPage_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *OperationRepositoryInterface_GetWithdrawalsPage_Call) RunAndReturn(run func(models.OperationSearchFilter) (*models.GetWithdrawalsPage, error)) *OperationRepositoryInterface_GetWithdrawalsPage_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetWithdrawnByAccountID provides a mock function with given fields: accountID
 func (_m *OperationRepositoryInterface) GetWithdrawnByAccountID(accountID uint) (float32, error) {
 	ret := _m.Called(accountID)
@@ -230,6 +468,54 @@ func (_c *OperationRepositoryInterface_GetWithdrawnByAccountID_Call) RunAndRetur
 	return _c
 }
 
+// WithContext provides a mock function with given fields: ctx
+func (_m *OperationRepositoryInterface) WithContext(ctx context.Context) repositories.OperationRepositoryInterface {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WithContext")
+	}
+
+	var r0 repositories.OperationRepositoryInterface
+	if rf, ok := ret.Get(0).(func(context.Context) repositories.OperationRepositoryInterface); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(repositories.OperationRepositoryInterface)
+		}
+	}
+
+	return r0
+}
+
+// OperationRepositoryInterface_WithContext_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'WithContext'
+type OperationRepositoryInterface_WithContext_Call struct {
+	*mock.Call
+}
+
+// WithContext is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *OperationRepositoryInterface_Expecter) WithContext(ctx interface{}) *OperationRepositoryInterface_WithContext_Call {
+	return &OperationRepositoryInterface_WithContext_Call{Call: _e.mock.On("WithContext", ctx)}
+}
+
+func (_c *OperationRepositoryInterface_WithContext_Call) Run(run func(ctx context.Context)) *OperationRepositoryInterface_WithContext_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *OperationRepositoryInterface_WithContext_Call) Return(_a0 repositories.OperationRepositoryInterface) *OperationRepositoryInterface_WithContext_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *OperationRepositoryInterface_WithContext_Call) RunAndReturn(run func(context.Context) repositories.OperationRepositoryInterface) *OperationRepositoryInterface_WithContext_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewOperationRepositoryInterface creates a new instance of OperationRepositoryInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewOperationRepositoryInterface(t interface {
@@ -1,12 +1,18 @@
-// Code generated by mockery v2.43.2. DO NOT EDIT.
+// Code generated by mockery v2.53.7. DO NOT EDIT.
 
 package repositories
 
 import (
+	context "context"
+
 	entities "github.com/ShukinDmitriy/gophermart/internal/entities"
 	mock "github.com/stretchr/testify/mock"
 
 	models "github.com/ShukinDmitriy/gophermart/internal/models"
+
+	repositories "github.com/ShukinDmitriy/gophermart/internal/repositories"
+
+	time "time"
 )
 
 // OrderRepositoryInterface is an autogenerated mock type for the OrderRepositoryInterface type
@@ -22,6 +28,110 @@ func (_m *OrderRepositoryInterface) EXPECT() *OrderRepositoryInterface_Expecter
 	return &OrderRepositoryInterface_Expecter{mock: &_m.Mock}
 }
 
+// CorrectOrderAccrual provides a mock function with given fields: orderNumber, accrual
+func (_m *OrderRepositoryInterface) CorrectOrderAccrual(orderNumber string, accrual float32) error {
+	ret := _m.Called(orderNumber, accrual)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CorrectOrderAccrual")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, float32) error); ok {
+		r0 = rf(orderNumber, accrual)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// OrderRepositoryInterface_CorrectOrderAccrual_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CorrectOrderAccrual'
+type OrderRepositoryInterface_CorrectOrderAccrual_Call struct {
+	*mock.Call
+}
+
+// CorrectOrderAccrual is a helper method to define mock.On call
+//   - orderNumber string
+//   - accrual float32
+func (_e *OrderRepositoryInterface_Expecter) CorrectOrderAccrual(orderNumber interface{}, accrual interface{}) *OrderRepositoryInterface_CorrectOrderAccrual_Call {
+	return &OrderRepositoryInterface_CorrectOrderAccrual_Call{Call: _e.mock.On("CorrectOrderAccrual", orderNumber, accrual)}
+}
+
+func (_c *OrderRepositoryInterface_CorrectOrderAccrual_Call) Run(run func(orderNumber string, accrual float32)) *OrderRepositoryInterface_CorrectOrderAccrual_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(float32))
+	})
+	return _c
+}
+
+func (_c *OrderRepositoryInterface_CorrectOrderAccrual_Call) Return(_a0 error) *OrderRepositoryInterface_CorrectOrderAccrual_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *OrderRepositoryInterface_CorrectOrderAccrual_Call) RunAndReturn(run func(string, float32) error) *OrderRepositoryInterface_CorrectOrderAccrual_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CountByStatus provides a mock function with no fields
+func (_m *OrderRepositoryInterface) CountByStatus() (map[entities.OrderStatus]int64, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountByStatus")
+	}
+
+	var r0 map[entities.OrderStatus]int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (map[entities.OrderStatus]int64, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() map[entities.OrderStatus]int64); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[entities.OrderStatus]int64)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OrderRepositoryInterface_CountByStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CountByStatus'
+type OrderRepositoryInterface_CountByStatus_Call struct {
+	*mock.Call
+}
+
+// CountByStatus is a helper method to define mock.On call
+func (_e *OrderRepositoryInterface_Expecter) CountByStatus() *OrderRepositoryInterface_CountByStatus_Call {
+	return &OrderRepositoryInterface_CountByStatus_Call{Call: _e.mock.On("CountByStatus")}
+}
+
+func (_c *OrderRepositoryInterface_CountByStatus_Call) Run(run func()) *OrderRepositoryInterface_CountByStatus_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *OrderRepositoryInterface_CountByStatus_Call) Return(_a0 map[entities.OrderStatus]int64, _a1 error) *OrderRepositoryInterface_CountByStatus_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *OrderRepositoryInterface_CountByStatus_Call) RunAndReturn(run func() (map[entities.OrderStatus]int64, error)) *OrderRepositoryInterface_CountByStatus_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Create provides a mock function with given fields: number, userID
 func (_m *OrderRepositoryInterface) Create(number string, userID uint) (*entities.Order, error) {
 	ret := _m.Called(number, userID)
@@ -81,6 +191,111 @@ func (_c *OrderRepositoryInterface_Create_Call) RunAndReturn(run func(string, ui
 	return _c
 }
 
+// CreateAccrualAttempt provides a mock function with given fields: attempt
+func (_m *OrderRepositoryInterface) CreateAccrualAttempt(attempt *entities.AccrualAttempt) error {
+	ret := _m.Called(attempt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateAccrualAttempt")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*entities.AccrualAttempt) error); ok {
+		r0 = rf(attempt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// OrderRepositoryInterface_CreateAccrualAttempt_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateAccrualAttempt'
+type OrderRepositoryInterface_CreateAccrualAttempt_Call struct {
+	*mock.Call
+}
+
+// CreateAccrualAttempt is a helper method to define mock.On call
+//   - attempt *entities.AccrualAttempt
+func (_e *OrderRepositoryInterface_Expecter) CreateAccrualAttempt(attempt interface{}) *OrderRepositoryInterface_CreateAccrualAttempt_Call {
+	return &OrderRepositoryInterface_CreateAccrualAttempt_Call{Call: _e.mock.On("CreateAccrualAttempt", attempt)}
+}
+
+func (_c *OrderRepositoryInterface_CreateAccrualAttempt_Call) Run(run func(attempt *entities.AccrualAttempt)) *OrderRepositoryInterface_CreateAccrualAttempt_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*entities.AccrualAttempt))
+	})
+	return _c
+}
+
+func (_c *OrderRepositoryInterface_CreateAccrualAttempt_Call) Return(_a0 error) *OrderRepositoryInterface_CreateAccrualAttempt_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *OrderRepositoryInterface_CreateAccrualAttempt_Call) RunAndReturn(run func(*entities.AccrualAttempt) error) *OrderRepositoryInterface_CreateAccrualAttempt_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateBatch provides a mock function with given fields: numbers, userID
+func (_m *OrderRepositoryInterface) CreateBatch(numbers []string, userID uint) ([]*entities.Order, error) {
+	ret := _m.Called(numbers, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateBatch")
+	}
+
+	var r0 []*entities.Order
+	var r1 error
+	if rf, ok := ret.Get(0).(func([]string, uint) ([]*entities.Order, error)); ok {
+		return rf(numbers, userID)
+	}
+	if rf, ok := ret.Get(0).(func([]string, uint) []*entities.Order); ok {
+		r0 = rf(numbers, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entities.Order)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func([]string, uint) error); ok {
+		r1 = rf(numbers, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OrderRepositoryInterface_CreateBatch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateBatch'
+type OrderRepositoryInterface_CreateBatch_Call struct {
+	*mock.Call
+}
+
+// CreateBatch is a helper method to define mock.On call
+//   - numbers []string
+//   - userID uint
+func (_e *OrderRepositoryInterface_Expecter) CreateBatch(numbers interface{}, userID interface{}) *OrderRepositoryInterface_CreateBatch_Call {
+	return &OrderRepositoryInterface_CreateBatch_Call{Call: _e.mock.On("CreateBatch", numbers, userID)}
+}
+
+func (_c *OrderRepositoryInterface_CreateBatch_Call) Run(run func(numbers []string, userID uint)) *OrderRepositoryInterface_CreateBatch_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].([]string), args[1].(uint))
+	})
+	return _c
+}
+
+func (_c *OrderRepositoryInterface_CreateBatch_Call) Return(_a0 []*entities.Order, _a1 error) *OrderRepositoryInterface_CreateBatch_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *OrderRepositoryInterface_CreateBatch_Call) RunAndReturn(run func([]string, uint) ([]*entities.Order, error)) *OrderRepositoryInterface_CreateBatch_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // FindByNumber provides a mock function with given fields: number
 func (_m *OrderRepositoryInterface) FindByNumber(number string) (*entities.Order, error) {
 	ret := _m.Called(number)
@@ -139,29 +354,29 @@ func (_c *OrderRepositoryInterface_FindByNumber_Call) RunAndReturn(run func(stri
 	return _c
 }
 
-// GetOrdersByUserID provides a mock function with given fields: userID
-func (_m *OrderRepositoryInterface) GetOrdersByUserID(userID uint) ([]*models.GetOrdersResponse, error) {
-	ret := _m.Called(userID)
+// FindByNumbers provides a mock function with given fields: numbers
+func (_m *OrderRepositoryInterface) FindByNumbers(numbers []string) ([]*entities.Order, error) {
+	ret := _m.Called(numbers)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetOrdersByUserID")
+		panic("no return value specified for FindByNumbers")
 	}
 
-	var r0 []*models.GetOrdersResponse
+	var r0 []*entities.Order
 	var r1 error
-	if rf, ok := ret.Get(0).(func(uint) ([]*models.GetOrdersResponse, error)); ok {
-		return rf(userID)
+	if rf, ok := ret.Get(0).(func([]string) ([]*entities.Order, error)); ok {
+		return rf(numbers)
 	}
-	if rf, ok := ret.Get(0).(func(uint) []*models.GetOrdersResponse); ok {
-		r0 = rf(userID)
+	if rf, ok := ret.Get(0).(func([]string) []*entities.Order); ok {
+		r0 = rf(numbers)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*models.GetOrdersResponse)
+			r0 = ret.Get(0).([]*entities.Order)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(uint) error); ok {
-		r1 = rf(userID)
+	if rf, ok := ret.Get(1).(func([]string) error); ok {
+		r1 = rf(numbers)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -169,35 +384,94 @@ func (_m *OrderRepositoryInterface) GetOrdersByUserID(userID uint) ([]*models.Ge
 	return r0, r1
 }
 
-// OrderRepositoryInterface_GetOrdersByUserID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetOrdersByUserID'
-type OrderRepositoryInterface_GetOrdersByUserID_Call struct {
+// OrderRepositoryInterface_FindByNumbers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindByNumbers'
+type OrderRepositoryInterface_FindByNumbers_Call struct {
 	*mock.Call
 }
 
-// GetOrdersByUserID is a helper method to define mock.On call
-//   - userID uint
-func (_e *OrderRepositoryInterface_Expecter) GetOrdersByUserID(userID interface{}) *OrderRepositoryInterface_GetOrdersByUserID_Call {
-	return &OrderRepositoryInterface_GetOrdersByUserID_Call{Call: _e.mock.On("GetOrdersByUserID", userID)}
+// FindByNumbers is a helper method to define mock.On call
+//   - numbers []string
+func (_e *OrderRepositoryInterface_Expecter) FindByNumbers(numbers interface{}) *OrderRepositoryInterface_FindByNumbers_Call {
+	return &OrderRepositoryInterface_FindByNumbers_Call{Call: _e.mock.On("FindByNumbers", numbers)}
 }
 
-func (_c *OrderRepositoryInterface_GetOrdersByUserID_Call) Run(run func(userID uint)) *OrderRepositoryInterface_GetOrdersByUserID_Call {
+func (_c *OrderRepositoryInterface_FindByNumbers_Call) Run(run func(numbers []string)) *OrderRepositoryInterface_FindByNumbers_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(uint))
+		run(args[0].([]string))
+	})
+	return _c
+}
+
+func (_c *OrderRepositoryInterface_FindByNumbers_Call) Return(_a0 []*entities.Order, _a1 error) *OrderRepositoryInterface_FindByNumbers_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *OrderRepositoryInterface_FindByNumbers_Call) RunAndReturn(run func([]string) ([]*entities.Order, error)) *OrderRepositoryInterface_FindByNumbers_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetAccrualAttempts provides a mock function with given fields: orderNumber, limit
+func (_m *OrderRepositoryInterface) GetAccrualAttempts(orderNumber string, limit int) ([]*entities.AccrualAttempt, error) {
+	ret := _m.Called(orderNumber, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAccrualAttempts")
+	}
+
+	var r0 []*entities.AccrualAttempt
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, int) ([]*entities.AccrualAttempt, error)); ok {
+		return rf(orderNumber, limit)
+	}
+	if rf, ok := ret.Get(0).(func(string, int) []*entities.AccrualAttempt); ok {
+		r0 = rf(orderNumber, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entities.AccrualAttempt)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, int) error); ok {
+		r1 = rf(orderNumber, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OrderRepositoryInterface_GetAccrualAttempts_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAccrualAttempts'
+type OrderRepositoryInterface_GetAccrualAttempts_Call struct {
+	*mock.Call
+}
+
+// GetAccrualAttempts is a helper method to define mock.On call
+//   - orderNumber string
+//   - limit int
+func (_e *OrderRepositoryInterface_Expecter) GetAccrualAttempts(orderNumber interface{}, limit interface{}) *OrderRepositoryInterface_GetAccrualAttempts_Call {
+	return &OrderRepositoryInterface_GetAccrualAttempts_Call{Call: _e.mock.On("GetAccrualAttempts", orderNumber, limit)}
+}
+
+func (_c *OrderRepositoryInterface_GetAccrualAttempts_Call) Run(run func(orderNumber string, limit int)) *OrderRepositoryInterface_GetAccrualAttempts_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(int))
 	})
 	return _c
 }
 
-func (_c *OrderRepositoryInterface_GetOrdersByUserID_Call) Return(_a0 []*models.GetOrdersResponse, _a1 error) *OrderRepositoryInterface_GetOrdersByUserID_Call {
+func (_c *OrderRepositoryInterface_GetAccrualAttempts_Call) Return(_a0 []*entities.AccrualAttempt, _a1 error) *OrderRepositoryInterface_GetAccrualAttempts_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *OrderRepositoryInterface_GetOrdersByUserID_Call) RunAndReturn(run func(uint) ([]*models.GetOrdersResponse, error)) *OrderRepositoryInterface_GetOrdersByUserID_Call {
+func (_c *OrderRepositoryInterface_GetAccrualAttempts_Call) RunAndReturn(run func(string, int) ([]*entities.AccrualAttempt, error)) *OrderRepositoryInterface_GetAccrualAttempts_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetOrdersForProcess provides a mock function with given fields:
+// GetOrdersForProcess provides a mock function with no fields
 func (_m *OrderRepositoryInterface) GetOrdersForProcess() ([]*entities.Order, error) {
 	ret := _m.Called()
 
@@ -254,6 +528,238 @@ func (_c *OrderRepositoryInterface_GetOrdersForProcess_Call) RunAndReturn(run fu
 	return _c
 }
 
+// GetOrdersPage provides a mock function with given fields: filter
+func (_m *OrderRepositoryInterface) GetOrdersPage(filter models.OrderSearchFilter) (*models.GetOrdersPage, error) {
+	ret := _m.Called(filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOrdersPage")
+	}
+
+	var r0 *models.GetOrdersPage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(models.OrderSearchFilter) (*models.GetOrdersPage, error)); ok {
+		return rf(filter)
+	}
+	if rf, ok := ret.Get(0).(func(models.OrderSearchFilter) *models.GetOrdersPage); ok {
+		r0 = rf(filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.GetOrdersPage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(models.OrderSearchFilter) error); ok {
+		r1 = rf(filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OrderRepositoryInterface_GetOrdersPage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetOrdersPage'
+type OrderRepositoryInterface_GetOrdersPage_Call struct {
+	*mock.Call
+}
+
+// GetOrdersPage is a helper method to define mock.On call
+//   - filter models.OrderSearchFilter
+func (_e *OrderRepositoryInterface_Expecter) GetOrdersPage(filter interface{}) *OrderRepositoryInterface_GetOrdersPage_Call {
+	return &OrderRepositoryInterface_GetOrdersPage_Call{Call: _e.mock.On("GetOrdersPage", filter)}
+}
+
+func (_c *OrderRepositoryInterface_GetOrdersPage_Call) Run(run func(filter models.OrderSearchFilter)) *OrderRepositoryInterface_GetOrdersPage_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(models.OrderSearchFilter))
+	})
+	return _c
+}
+
+func (_c *OrderRepositoryInterface_GetOrdersPage_Call) Return(_a0 *models.GetOrdersPage, _a1 error) *OrderRepositoryInterface_GetOrdersPage_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *OrderRepositoryInterface_GetOrdersPage_Call) RunAndReturn(run func(models.OrderSearchFilter) (*models.GetOrdersPage, error)) *OrderRepositoryInterface_GetOrdersPage_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetOrdersWithBasketForProcess provides a mock function with no fields
+func (_m *OrderRepositoryInterface) GetOrdersWithBasketForProcess() ([]*entities.Order, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOrdersWithBasketForProcess")
+	}
+
+	var r0 []*entities.Order
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]*entities.Order, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []*entities.Order); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entities.Order)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OrderRepositoryInterface_GetOrdersWithBasketForProcess_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetOrdersWithBasketForProcess'
+type OrderRepositoryInterface_GetOrdersWithBasketForProcess_Call struct {
+	*mock.Call
+}
+
+// GetOrdersWithBasketForProcess is a helper method to define mock.On call
+func (_e *OrderRepositoryInterface_Expecter) GetOrdersWithBasketForProcess() *OrderRepositoryInterface_GetOrdersWithBasketForProcess_Call {
+	return &OrderRepositoryInterface_GetOrdersWithBasketForProcess_Call{Call: _e.mock.On("GetOrdersWithBasketForProcess")}
+}
+
+func (_c *OrderRepositoryInterface_GetOrdersWithBasketForProcess_Call) Run(run func()) *OrderRepositoryInterface_GetOrdersWithBasketForProcess_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *OrderRepositoryInterface_GetOrdersWithBasketForProcess_Call) Return(_a0 []*entities.Order, _a1 error) *OrderRepositoryInterface_GetOrdersWithBasketForProcess_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *OrderRepositoryInterface_GetOrdersWithBasketForProcess_Call) RunAndReturn(run func() ([]*entities.Order, error)) *OrderRepositoryInterface_GetOrdersWithBasketForProcess_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetProcessedOrdersByPeriod provides a mock function with given fields: from, to
+func (_m *OrderRepositoryInterface) GetProcessedOrdersByPeriod(from time.Time, to time.Time) ([]*entities.Order, error) {
+	ret := _m.Called(from, to)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetProcessedOrdersByPeriod")
+	}
+
+	var r0 []*entities.Order
+	var r1 error
+	if rf, ok := ret.Get(0).(func(time.Time, time.Time) ([]*entities.Order, error)); ok {
+		return rf(from, to)
+	}
+	if rf, ok := ret.Get(0).(func(time.Time, time.Time) []*entities.Order); ok {
+		r0 = rf(from, to)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entities.Order)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(time.Time, time.Time) error); ok {
+		r1 = rf(from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OrderRepositoryInterface_GetProcessedOrdersByPeriod_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetProcessedOrdersByPeriod'
+type OrderRepositoryInterface_GetProcessedOrdersByPeriod_Call struct {
+	*mock.Call
+}
+
+// GetProcessedOrdersByPeriod is a helper method to define mock.On call
+//   - from time.Time
+//   - to time.Time
+func (_e *OrderRepositoryInterface_Expecter) GetProcessedOrdersByPeriod(from interface{}, to interface{}) *OrderRepositoryInterface_GetProcessedOrdersByPeriod_Call {
+	return &OrderRepositoryInterface_GetProcessedOrdersByPeriod_Call{Call: _e.mock.On("GetProcessedOrdersByPeriod", from, to)}
+}
+
+func (_c *OrderRepositoryInterface_GetProcessedOrdersByPeriod_Call) Run(run func(from time.Time, to time.Time)) *OrderRepositoryInterface_GetProcessedOrdersByPeriod_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(time.Time), args[1].(time.Time))
+	})
+	return _c
+}
+
+func (_c *OrderRepositoryInterface_GetProcessedOrdersByPeriod_Call) Return(_a0 []*entities.Order, _a1 error) *OrderRepositoryInterface_GetProcessedOrdersByPeriod_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *OrderRepositoryInterface_GetProcessedOrdersByPeriod_Call) RunAndReturn(run func(time.Time, time.Time) ([]*entities.Order, error)) *OrderRepositoryInterface_GetProcessedOrdersByPeriod_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetStatusHistory provides a mock function with given fields: orderID
+func (_m *OrderRepositoryInterface) GetStatusHistory(orderID uint) ([]*entities.OrderStatusHistory, error) {
+	ret := _m.Called(orderID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStatusHistory")
+	}
+
+	var r0 []*entities.OrderStatusHistory
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint) ([]*entities.OrderStatusHistory, error)); ok {
+		return rf(orderID)
+	}
+	if rf, ok := ret.Get(0).(func(uint) []*entities.OrderStatusHistory); ok {
+		r0 = rf(orderID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entities.OrderStatusHistory)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(orderID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OrderRepositoryInterface_GetStatusHistory_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetStatusHistory'
+type OrderRepositoryInterface_GetStatusHistory_Call struct {
+	*mock.Call
+}
+
+// GetStatusHistory is a helper method to define mock.On call
+//   - orderID uint
+func (_e *OrderRepositoryInterface_Expecter) GetStatusHistory(orderID interface{}) *OrderRepositoryInterface_GetStatusHistory_Call {
+	return &OrderRepositoryInterface_GetStatusHistory_Call{Call: _e.mock.On("GetStatusHistory", orderID)}
+}
+
+func (_c *OrderRepositoryInterface_GetStatusHistory_Call) Run(run func(orderID uint)) *OrderRepositoryInterface_GetStatusHistory_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(uint))
+	})
+	return _c
+}
+
+func (_c *OrderRepositoryInterface_GetStatusHistory_Call) Return(_a0 []*entities.OrderStatusHistory, _a1 error) *OrderRepositoryInterface_GetStatusHistory_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *OrderRepositoryInterface_GetStatusHistory_Call) RunAndReturn(run func(uint) ([]*entities.OrderStatusHistory, error)) *OrderRepositoryInterface_GetStatusHistory_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // UpdateOrderByAccrualOrder provides a mock function with given fields: accrualOrder
 func (_m *OrderRepositoryInterface) UpdateOrderByAccrualOrder(accrualOrder *models.AccrualOrderResponse) error {
 	ret := _m.Called(accrualOrder)
@@ -300,6 +806,54 @@ func (_c *OrderRepositoryInterface_UpdateOrderByAccrualOrder_Call) RunAndReturn(
 	return _c
 }
 
+// WithContext provides a mock function with given fields: ctx
+func (_m *OrderRepositoryInterface) WithContext(ctx context.Context) repositories.OrderRepositoryInterface {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WithContext")
+	}
+
+	var r0 repositories.OrderRepositoryInterface
+	if rf, ok := ret.Get(0).(func(context.Context) repositories.OrderRepositoryInterface); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(repositories.OrderRepositoryInterface)
+		}
+	}
+
+	return r0
+}
+
+// OrderRepositoryInterface_WithContext_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'WithContext'
+type OrderRepositoryInterface_WithContext_Call struct {
+	*mock.Call
+}
+
+// WithContext is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *OrderRepositoryInterface_Expecter) WithContext(ctx interface{}) *OrderRepositoryInterface_WithContext_Call {
+	return &OrderRepositoryInterface_WithContext_Call{Call: _e.mock.On("WithContext", ctx)}
+}
+
+func (_c *OrderRepositoryInterface_WithContext_Call) Run(run func(ctx context.Context)) *OrderRepositoryInterface_WithContext_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *OrderRepositoryInterface_WithContext_Call) Return(_a0 repositories.OrderRepositoryInterface) *OrderRepositoryInterface_WithContext_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *OrderRepositoryInterface_WithContext_Call) RunAndReturn(run func(context.Context) repositories.OrderRepositoryInterface) *OrderRepositoryInterface_WithContext_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewOrderRepositoryInterface creates a new instance of OrderRepositoryInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewOrderRepositoryInterface(t interface {
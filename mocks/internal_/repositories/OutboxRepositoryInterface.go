@@ -0,0 +1,306 @@
+// Code generated by mockery v2.53.7. DO NOT EDIT.
+
+package repositories
+
+import (
+	context "context"
+
+	entities "github.com/ShukinDmitriy/gophermart/internal/entities"
+	mock "github.com/stretchr/testify/mock"
+
+	repositories "github.com/ShukinDmitriy/gophermart/internal/repositories"
+
+	time "time"
+)
+
+// OutboxRepositoryInterface is an autogenerated mock type for the OutboxRepositoryInterface type
+type OutboxRepositoryInterface struct {
+	mock.Mock
+}
+
+type OutboxRepositoryInterface_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *OutboxRepositoryInterface) EXPECT() *OutboxRepositoryInterface_Expecter {
+	return &OutboxRepositoryInterface_Expecter{mock: &_m.Mock}
+}
+
+// ClaimEvents provides a mock function with given fields: now, lease, limit
+func (_m *OutboxRepositoryInterface) ClaimEvents(now time.Time, lease time.Duration, limit int) ([]*entities.OutboxEvent, error) {
+	ret := _m.Called(now, lease, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClaimEvents")
+	}
+
+	var r0 []*entities.OutboxEvent
+	var r1 error
+	if rf, ok := ret.Get(0).(func(time.Time, time.Duration, int) ([]*entities.OutboxEvent, error)); ok {
+		return rf(now, lease, limit)
+	}
+	if rf, ok := ret.Get(0).(func(time.Time, time.Duration, int) []*entities.OutboxEvent); ok {
+		r0 = rf(now, lease, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entities.OutboxEvent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(time.Time, time.Duration, int) error); ok {
+		r1 = rf(now, lease, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OutboxRepositoryInterface_ClaimEvents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ClaimEvents'
+type OutboxRepositoryInterface_ClaimEvents_Call struct {
+	*mock.Call
+}
+
+// ClaimEvents is a helper method to define mock.On call
+//   - now time.Time
+//   - lease time.Duration
+//   - limit int
+func (_e *OutboxRepositoryInterface_Expecter) ClaimEvents(now interface{}, lease interface{}, limit interface{}) *OutboxRepositoryInterface_ClaimEvents_Call {
+	return &OutboxRepositoryInterface_ClaimEvents_Call{Call: _e.mock.On("ClaimEvents", now, lease, limit)}
+}
+
+func (_c *OutboxRepositoryInterface_ClaimEvents_Call) Run(run func(now time.Time, lease time.Duration, limit int)) *OutboxRepositoryInterface_ClaimEvents_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(time.Time), args[1].(time.Duration), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *OutboxRepositoryInterface_ClaimEvents_Call) Return(_a0 []*entities.OutboxEvent, _a1 error) *OutboxRepositoryInterface_ClaimEvents_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *OutboxRepositoryInterface_ClaimEvents_Call) RunAndReturn(run func(time.Time, time.Duration, int) ([]*entities.OutboxEvent, error)) *OutboxRepositoryInterface_ClaimEvents_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CountPending provides a mock function with no fields
+func (_m *OutboxRepositoryInterface) CountPending() (int64, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountPending")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (int64, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() int64); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OutboxRepositoryInterface_CountPending_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CountPending'
+type OutboxRepositoryInterface_CountPending_Call struct {
+	*mock.Call
+}
+
+// CountPending is a helper method to define mock.On call
+func (_e *OutboxRepositoryInterface_Expecter) CountPending() *OutboxRepositoryInterface_CountPending_Call {
+	return &OutboxRepositoryInterface_CountPending_Call{Call: _e.mock.On("CountPending")}
+}
+
+func (_c *OutboxRepositoryInterface_CountPending_Call) Run(run func()) *OutboxRepositoryInterface_CountPending_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *OutboxRepositoryInterface_CountPending_Call) Return(_a0 int64, _a1 error) *OutboxRepositoryInterface_CountPending_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *OutboxRepositoryInterface_CountPending_Call) RunAndReturn(run func() (int64, error)) *OutboxRepositoryInterface_CountPending_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeletePublished provides a mock function with given fields: before
+func (_m *OutboxRepositoryInterface) DeletePublished(before time.Time) (int64, error) {
+	ret := _m.Called(before)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeletePublished")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(time.Time) (int64, error)); ok {
+		return rf(before)
+	}
+	if rf, ok := ret.Get(0).(func(time.Time) int64); ok {
+		r0 = rf(before)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(time.Time) error); ok {
+		r1 = rf(before)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OutboxRepositoryInterface_DeletePublished_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeletePublished'
+type OutboxRepositoryInterface_DeletePublished_Call struct {
+	*mock.Call
+}
+
+// DeletePublished is a helper method to define mock.On call
+//   - before time.Time
+func (_e *OutboxRepositoryInterface_Expecter) DeletePublished(before interface{}) *OutboxRepositoryInterface_DeletePublished_Call {
+	return &OutboxRepositoryInterface_DeletePublished_Call{Call: _e.mock.On("DeletePublished", before)}
+}
+
+func (_c *OutboxRepositoryInterface_DeletePublished_Call) Run(run func(before time.Time)) *OutboxRepositoryInterface_DeletePublished_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(time.Time))
+	})
+	return _c
+}
+
+func (_c *OutboxRepositoryInterface_DeletePublished_Call) Return(_a0 int64, _a1 error) *OutboxRepositoryInterface_DeletePublished_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *OutboxRepositoryInterface_DeletePublished_Call) RunAndReturn(run func(time.Time) (int64, error)) *OutboxRepositoryInterface_DeletePublished_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateEvent provides a mock function with given fields: event
+func (_m *OutboxRepositoryInterface) UpdateEvent(event *entities.OutboxEvent) error {
+	ret := _m.Called(event)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateEvent")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*entities.OutboxEvent) error); ok {
+		r0 = rf(event)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// OutboxRepositoryInterface_UpdateEvent_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateEvent'
+type OutboxRepositoryInterface_UpdateEvent_Call struct {
+	*mock.Call
+}
+
+// UpdateEvent is a helper method to define mock.On call
+//   - event *entities.OutboxEvent
+func (_e *OutboxRepositoryInterface_Expecter) UpdateEvent(event interface{}) *OutboxRepositoryInterface_UpdateEvent_Call {
+	return &OutboxRepositoryInterface_UpdateEvent_Call{Call: _e.mock.On("UpdateEvent", event)}
+}
+
+func (_c *OutboxRepositoryInterface_UpdateEvent_Call) Run(run func(event *entities.OutboxEvent)) *OutboxRepositoryInterface_UpdateEvent_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*entities.OutboxEvent))
+	})
+	return _c
+}
+
+func (_c *OutboxRepositoryInterface_UpdateEvent_Call) Return(_a0 error) *OutboxRepositoryInterface_UpdateEvent_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *OutboxRepositoryInterface_UpdateEvent_Call) RunAndReturn(run func(*entities.OutboxEvent) error) *OutboxRepositoryInterface_UpdateEvent_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// WithContext provides a mock function with given fields: ctx
+func (_m *OutboxRepositoryInterface) WithContext(ctx context.Context) repositories.OutboxRepositoryInterface {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WithContext")
+	}
+
+	var r0 repositories.OutboxRepositoryInterface
+	if rf, ok := ret.Get(0).(func(context.Context) repositories.OutboxRepositoryInterface); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(repositories.OutboxRepositoryInterface)
+		}
+	}
+
+	return r0
+}
+
+// OutboxRepositoryInterface_WithContext_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'WithContext'
+type OutboxRepositoryInterface_WithContext_Call struct {
+	*mock.Call
+}
+
+// WithContext is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *OutboxRepositoryInterface_Expecter) WithContext(ctx interface{}) *OutboxRepositoryInterface_WithContext_Call {
+	return &OutboxRepositoryInterface_WithContext_Call{Call: _e.mock.On("WithContext", ctx)}
+}
+
+func (_c *OutboxRepositoryInterface_WithContext_Call) Run(run func(ctx context.Context)) *OutboxRepositoryInterface_WithContext_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *OutboxRepositoryInterface_WithContext_Call) Return(_a0 repositories.OutboxRepositoryInterface) *OutboxRepositoryInterface_WithContext_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *OutboxRepositoryInterface_WithContext_Call) RunAndReturn(run func(context.Context) repositories.OutboxRepositoryInterface) *OutboxRepositoryInterface_WithContext_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewOutboxRepositoryInterface creates a new instance of OutboxRepositoryInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewOutboxRepositoryInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *OutboxRepositoryInterface {
+	mock := &OutboxRepositoryInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,225 @@
+// Code generated by mockery v2.53.7. DO NOT EDIT.
+
+package repositories
+
+import (
+	context "context"
+
+	entities "github.com/ShukinDmitriy/gophermart/internal/entities"
+	mock "github.com/stretchr/testify/mock"
+
+	repositories "github.com/ShukinDmitriy/gophermart/internal/repositories"
+)
+
+// ReconciliationRepositoryInterface is an autogenerated mock type for the ReconciliationRepositoryInterface type
+type ReconciliationRepositoryInterface struct {
+	mock.Mock
+}
+
+type ReconciliationRepositoryInterface_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *ReconciliationRepositoryInterface) EXPECT() *ReconciliationRepositoryInterface_Expecter {
+	return &ReconciliationRepositoryInterface_Expecter{mock: &_m.Mock}
+}
+
+// CreateDiscrepancy provides a mock function with given fields: discrepancy
+func (_m *ReconciliationRepositoryInterface) CreateDiscrepancy(discrepancy *entities.ReconciliationDiscrepancy) error {
+	ret := _m.Called(discrepancy)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateDiscrepancy")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*entities.ReconciliationDiscrepancy) error); ok {
+		r0 = rf(discrepancy)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReconciliationRepositoryInterface_CreateDiscrepancy_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateDiscrepancy'
+type ReconciliationRepositoryInterface_CreateDiscrepancy_Call struct {
+	*mock.Call
+}
+
+// CreateDiscrepancy is a helper method to define mock.On call
+//   - discrepancy *entities.ReconciliationDiscrepancy
+func (_e *ReconciliationRepositoryInterface_Expecter) CreateDiscrepancy(discrepancy interface{}) *ReconciliationRepositoryInterface_CreateDiscrepancy_Call {
+	return &ReconciliationRepositoryInterface_CreateDiscrepancy_Call{Call: _e.mock.On("CreateDiscrepancy", discrepancy)}
+}
+
+func (_c *ReconciliationRepositoryInterface_CreateDiscrepancy_Call) Run(run func(discrepancy *entities.ReconciliationDiscrepancy)) *ReconciliationRepositoryInterface_CreateDiscrepancy_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*entities.ReconciliationDiscrepancy))
+	})
+	return _c
+}
+
+func (_c *ReconciliationRepositoryInterface_CreateDiscrepancy_Call) Return(_a0 error) *ReconciliationRepositoryInterface_CreateDiscrepancy_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *ReconciliationRepositoryInterface_CreateDiscrepancy_Call) RunAndReturn(run func(*entities.ReconciliationDiscrepancy) error) *ReconciliationRepositoryInterface_CreateDiscrepancy_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateRun provides a mock function with given fields: run
+func (_m *ReconciliationRepositoryInterface) CreateRun(run *entities.ReconciliationRun) error {
+	ret := _m.Called(run)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateRun")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*entities.ReconciliationRun) error); ok {
+		r0 = rf(run)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReconciliationRepositoryInterface_CreateRun_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateRun'
+type ReconciliationRepositoryInterface_CreateRun_Call struct {
+	*mock.Call
+}
+
+// CreateRun is a helper method to define mock.On call
+//   - run *entities.ReconciliationRun
+func (_e *ReconciliationRepositoryInterface_Expecter) CreateRun(run interface{}) *ReconciliationRepositoryInterface_CreateRun_Call {
+	return &ReconciliationRepositoryInterface_CreateRun_Call{Call: _e.mock.On("CreateRun", run)}
+}
+
+func (_c *ReconciliationRepositoryInterface_CreateRun_Call) Run(run func(run *entities.ReconciliationRun)) *ReconciliationRepositoryInterface_CreateRun_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*entities.ReconciliationRun))
+	})
+	return _c
+}
+
+func (_c *ReconciliationRepositoryInterface_CreateRun_Call) Return(_a0 error) *ReconciliationRepositoryInterface_CreateRun_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *ReconciliationRepositoryInterface_CreateRun_Call) RunAndReturn(run func(*entities.ReconciliationRun) error) *ReconciliationRepositoryInterface_CreateRun_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateRun provides a mock function with given fields: run
+func (_m *ReconciliationRepositoryInterface) UpdateRun(run *entities.ReconciliationRun) error {
+	ret := _m.Called(run)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateRun")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*entities.ReconciliationRun) error); ok {
+		r0 = rf(run)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReconciliationRepositoryInterface_UpdateRun_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateRun'
+type ReconciliationRepositoryInterface_UpdateRun_Call struct {
+	*mock.Call
+}
+
+// UpdateRun is a helper method to define mock.On call
+//   - run *entities.ReconciliationRun
+func (_e *ReconciliationRepositoryInterface_Expecter) UpdateRun(run interface{}) *ReconciliationRepositoryInterface_UpdateRun_Call {
+	return &ReconciliationRepositoryInterface_UpdateRun_Call{Call: _e.mock.On("UpdateRun", run)}
+}
+
+func (_c *ReconciliationRepositoryInterface_UpdateRun_Call) Run(run func(run *entities.ReconciliationRun)) *ReconciliationRepositoryInterface_UpdateRun_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*entities.ReconciliationRun))
+	})
+	return _c
+}
+
+func (_c *ReconciliationRepositoryInterface_UpdateRun_Call) Return(_a0 error) *ReconciliationRepositoryInterface_UpdateRun_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *ReconciliationRepositoryInterface_UpdateRun_Call) RunAndReturn(run func(*entities.ReconciliationRun) error) *ReconciliationRepositoryInterface_UpdateRun_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// WithContext provides a mock function with given fields: ctx
+func (_m *ReconciliationRepositoryInterface) WithContext(ctx context.Context) repositories.ReconciliationRepositoryInterface {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WithContext")
+	}
+
+	var r0 repositories.ReconciliationRepositoryInterface
+	if rf, ok := ret.Get(0).(func(context.Context) repositories.ReconciliationRepositoryInterface); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(repositories.ReconciliationRepositoryInterface)
+		}
+	}
+
+	return r0
+}
+
+// ReconciliationRepositoryInterface_WithContext_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'WithContext'
+type ReconciliationRepositoryInterface_WithContext_Call struct {
+	*mock.Call
+}
+
+// WithContext is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *ReconciliationRepositoryInterface_Expecter) WithContext(ctx interface{}) *ReconciliationRepositoryInterface_WithContext_Call {
+	return &ReconciliationRepositoryInterface_WithContext_Call{Call: _e.mock.On("WithContext", ctx)}
+}
+
+func (_c *ReconciliationRepositoryInterface_WithContext_Call) Run(run func(ctx context.Context)) *ReconciliationRepositoryInterface_WithContext_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *ReconciliationRepositoryInterface_WithContext_Call) Return(_a0 repositories.ReconciliationRepositoryInterface) *ReconciliationRepositoryInterface_WithContext_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *ReconciliationRepositoryInterface_WithContext_Call) RunAndReturn(run func(context.Context) repositories.ReconciliationRepositoryInterface) *ReconciliationRepositoryInterface_WithContext_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewReconciliationRepositoryInterface creates a new instance of ReconciliationRepositoryInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewReconciliationRepositoryInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ReconciliationRepositoryInterface {
+	mock := &ReconciliationRepositoryInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,504 @@
+// Code generated by mockery v2.53.7. DO NOT EDIT.
+
+package repositories
+
+import (
+	context "context"
+
+	entities "github.com/ShukinDmitriy/gophermart/internal/entities"
+	mock "github.com/stretchr/testify/mock"
+
+	repositories "github.com/ShukinDmitriy/gophermart/internal/repositories"
+
+	time "time"
+)
+
+// RewardRepositoryInterface is an autogenerated mock type for the RewardRepositoryInterface type
+type RewardRepositoryInterface struct {
+	mock.Mock
+}
+
+type RewardRepositoryInterface_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *RewardRepositoryInterface) EXPECT() *RewardRepositoryInterface_Expecter {
+	return &RewardRepositoryInterface_Expecter{mock: &_m.Mock}
+}
+
+// CreateBasket provides a mock function with given fields: basket
+func (_m *RewardRepositoryInterface) CreateBasket(basket *entities.OrderBasket) error {
+	ret := _m.Called(basket)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateBasket")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*entities.OrderBasket) error); ok {
+		r0 = rf(basket)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RewardRepositoryInterface_CreateBasket_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateBasket'
+type RewardRepositoryInterface_CreateBasket_Call struct {
+	*mock.Call
+}
+
+// CreateBasket is a helper method to define mock.On call
+//   - basket *entities.OrderBasket
+func (_e *RewardRepositoryInterface_Expecter) CreateBasket(basket interface{}) *RewardRepositoryInterface_CreateBasket_Call {
+	return &RewardRepositoryInterface_CreateBasket_Call{Call: _e.mock.On("CreateBasket", basket)}
+}
+
+func (_c *RewardRepositoryInterface_CreateBasket_Call) Run(run func(basket *entities.OrderBasket)) *RewardRepositoryInterface_CreateBasket_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*entities.OrderBasket))
+	})
+	return _c
+}
+
+func (_c *RewardRepositoryInterface_CreateBasket_Call) Return(_a0 error) *RewardRepositoryInterface_CreateBasket_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *RewardRepositoryInterface_CreateBasket_Call) RunAndReturn(run func(*entities.OrderBasket) error) *RewardRepositoryInterface_CreateBasket_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateRule provides a mock function with given fields: rule
+func (_m *RewardRepositoryInterface) CreateRule(rule *entities.RewardRule) error {
+	ret := _m.Called(rule)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateRule")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*entities.RewardRule) error); ok {
+		r0 = rf(rule)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RewardRepositoryInterface_CreateRule_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateRule'
+type RewardRepositoryInterface_CreateRule_Call struct {
+	*mock.Call
+}
+
+// CreateRule is a helper method to define mock.On call
+//   - rule *entities.RewardRule
+func (_e *RewardRepositoryInterface_Expecter) CreateRule(rule interface{}) *RewardRepositoryInterface_CreateRule_Call {
+	return &RewardRepositoryInterface_CreateRule_Call{Call: _e.mock.On("CreateRule", rule)}
+}
+
+func (_c *RewardRepositoryInterface_CreateRule_Call) Run(run func(rule *entities.RewardRule)) *RewardRepositoryInterface_CreateRule_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*entities.RewardRule))
+	})
+	return _c
+}
+
+func (_c *RewardRepositoryInterface_CreateRule_Call) Return(_a0 error) *RewardRepositoryInterface_CreateRule_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *RewardRepositoryInterface_CreateRule_Call) RunAndReturn(run func(*entities.RewardRule) error) *RewardRepositoryInterface_CreateRule_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteRule provides a mock function with given fields: id
+func (_m *RewardRepositoryInterface) DeleteRule(id uint) error {
+	ret := _m.Called(id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteRule")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uint) error); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RewardRepositoryInterface_DeleteRule_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteRule'
+type RewardRepositoryInterface_DeleteRule_Call struct {
+	*mock.Call
+}
+
+// DeleteRule is a helper method to define mock.On call
+//   - id uint
+func (_e *RewardRepositoryInterface_Expecter) DeleteRule(id interface{}) *RewardRepositoryInterface_DeleteRule_Call {
+	return &RewardRepositoryInterface_DeleteRule_Call{Call: _e.mock.On("DeleteRule", id)}
+}
+
+func (_c *RewardRepositoryInterface_DeleteRule_Call) Run(run func(id uint)) *RewardRepositoryInterface_DeleteRule_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(uint))
+	})
+	return _c
+}
+
+func (_c *RewardRepositoryInterface_DeleteRule_Call) Return(_a0 error) *RewardRepositoryInterface_DeleteRule_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *RewardRepositoryInterface_DeleteRule_Call) RunAndReturn(run func(uint) error) *RewardRepositoryInterface_DeleteRule_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindBasket provides a mock function with given fields: orderNumber
+func (_m *RewardRepositoryInterface) FindBasket(orderNumber string) (*entities.OrderBasket, error) {
+	ret := _m.Called(orderNumber)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindBasket")
+	}
+
+	var r0 *entities.OrderBasket
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*entities.OrderBasket, error)); ok {
+		return rf(orderNumber)
+	}
+	if rf, ok := ret.Get(0).(func(string) *entities.OrderBasket); ok {
+		r0 = rf(orderNumber)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.OrderBasket)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(orderNumber)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RewardRepositoryInterface_FindBasket_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindBasket'
+type RewardRepositoryInterface_FindBasket_Call struct {
+	*mock.Call
+}
+
+// FindBasket is a helper method to define mock.On call
+//   - orderNumber string
+func (_e *RewardRepositoryInterface_Expecter) FindBasket(orderNumber interface{}) *RewardRepositoryInterface_FindBasket_Call {
+	return &RewardRepositoryInterface_FindBasket_Call{Call: _e.mock.On("FindBasket", orderNumber)}
+}
+
+func (_c *RewardRepositoryInterface_FindBasket_Call) Run(run func(orderNumber string)) *RewardRepositoryInterface_FindBasket_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *RewardRepositoryInterface_FindBasket_Call) Return(_a0 *entities.OrderBasket, _a1 error) *RewardRepositoryInterface_FindBasket_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *RewardRepositoryInterface_FindBasket_Call) RunAndReturn(run func(string) (*entities.OrderBasket, error)) *RewardRepositoryInterface_FindBasket_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindRule provides a mock function with given fields: id
+func (_m *RewardRepositoryInterface) FindRule(id uint) (*entities.RewardRule, error) {
+	ret := _m.Called(id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindRule")
+	}
+
+	var r0 *entities.RewardRule
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint) (*entities.RewardRule, error)); ok {
+		return rf(id)
+	}
+	if rf, ok := ret.Get(0).(func(uint) *entities.RewardRule); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.RewardRule)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RewardRepositoryInterface_FindRule_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindRule'
+type RewardRepositoryInterface_FindRule_Call struct {
+	*mock.Call
+}
+
+// FindRule is a helper method to define mock.On call
+//   - id uint
+func (_e *RewardRepositoryInterface_Expecter) FindRule(id interface{}) *RewardRepositoryInterface_FindRule_Call {
+	return &RewardRepositoryInterface_FindRule_Call{Call: _e.mock.On("FindRule", id)}
+}
+
+func (_c *RewardRepositoryInterface_FindRule_Call) Run(run func(id uint)) *RewardRepositoryInterface_FindRule_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(uint))
+	})
+	return _c
+}
+
+func (_c *RewardRepositoryInterface_FindRule_Call) Return(_a0 *entities.RewardRule, _a1 error) *RewardRepositoryInterface_FindRule_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *RewardRepositoryInterface_FindRule_Call) RunAndReturn(run func(uint) (*entities.RewardRule, error)) *RewardRepositoryInterface_FindRule_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetActiveRules provides a mock function with given fields: at
+func (_m *RewardRepositoryInterface) GetActiveRules(at time.Time) ([]*entities.RewardRule, error) {
+	ret := _m.Called(at)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetActiveRules")
+	}
+
+	var r0 []*entities.RewardRule
+	var r1 error
+	if rf, ok := ret.Get(0).(func(time.Time) ([]*entities.RewardRule, error)); ok {
+		return rf(at)
+	}
+	if rf, ok := ret.Get(0).(func(time.Time) []*entities.RewardRule); ok {
+		r0 = rf(at)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entities.RewardRule)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(time.Time) error); ok {
+		r1 = rf(at)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RewardRepositoryInterface_GetActiveRules_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetActiveRules'
+type RewardRepositoryInterface_GetActiveRules_Call struct {
+	*mock.Call
+}
+
+// GetActiveRules is a helper method to define mock.On call
+//   - at time.Time
+func (_e *RewardRepositoryInterface_Expecter) GetActiveRules(at interface{}) *RewardRepositoryInterface_GetActiveRules_Call {
+	return &RewardRepositoryInterface_GetActiveRules_Call{Call: _e.mock.On("GetActiveRules", at)}
+}
+
+func (_c *RewardRepositoryInterface_GetActiveRules_Call) Run(run func(at time.Time)) *RewardRepositoryInterface_GetActiveRules_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(time.Time))
+	})
+	return _c
+}
+
+func (_c *RewardRepositoryInterface_GetActiveRules_Call) Return(_a0 []*entities.RewardRule, _a1 error) *RewardRepositoryInterface_GetActiveRules_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *RewardRepositoryInterface_GetActiveRules_Call) RunAndReturn(run func(time.Time) ([]*entities.RewardRule, error)) *RewardRepositoryInterface_GetActiveRules_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetRules provides a mock function with no fields
+func (_m *RewardRepositoryInterface) GetRules() ([]*entities.RewardRule, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRules")
+	}
+
+	var r0 []*entities.RewardRule
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]*entities.RewardRule, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []*entities.RewardRule); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entities.RewardRule)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RewardRepositoryInterface_GetRules_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetRules'
+type RewardRepositoryInterface_GetRules_Call struct {
+	*mock.Call
+}
+
+// GetRules is a helper method to define mock.On call
+func (_e *RewardRepositoryInterface_Expecter) GetRules() *RewardRepositoryInterface_GetRules_Call {
+	return &RewardRepositoryInterface_GetRules_Call{Call: _e.mock.On("GetRules")}
+}
+
+func (_c *RewardRepositoryInterface_GetRules_Call) Run(run func()) *RewardRepositoryInterface_GetRules_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *RewardRepositoryInterface_GetRules_Call) Return(_a0 []*entities.RewardRule, _a1 error) *RewardRepositoryInterface_GetRules_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *RewardRepositoryInterface_GetRules_Call) RunAndReturn(run func() ([]*entities.RewardRule, error)) *RewardRepositoryInterface_GetRules_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateRule provides a mock function with given fields: rule
+func (_m *RewardRepositoryInterface) UpdateRule(rule *entities.RewardRule) error {
+	ret := _m.Called(rule)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateRule")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*entities.RewardRule) error); ok {
+		r0 = rf(rule)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RewardRepositoryInterface_UpdateRule_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateRule'
+type RewardRepositoryInterface_UpdateRule_Call struct {
+	*mock.Call
+}
+
+// UpdateRule is a helper method to define mock.On call
+//   - rule *entities.RewardRule
+func (_e *RewardRepositoryInterface_Expecter) UpdateRule(rule interface{}) *RewardRepositoryInterface_UpdateRule_Call {
+	return &RewardRepositoryInterface_UpdateRule_Call{Call: _e.mock.On("UpdateRule", rule)}
+}
+
+func (_c *RewardRepositoryInterface_UpdateRule_Call) Run(run func(rule *entities.RewardRule)) *RewardRepositoryInterface_UpdateRule_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*entities.RewardRule))
+	})
+	return _c
+}
+
+func (_c *RewardRepositoryInterface_UpdateRule_Call) Return(_a0 error) *RewardRepositoryInterface_UpdateRule_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *RewardRepositoryInterface_UpdateRule_Call) RunAndReturn(run func(*entities.RewardRule) error) *RewardRepositoryInterface_UpdateRule_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// WithContext provides a mock function with given fields: ctx
+func (_m *RewardRepositoryInterface) WithContext(ctx context.Context) repositories.RewardRepositoryInterface {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WithContext")
+	}
+
+	var r0 repositories.RewardRepositoryInterface
+	if rf, ok := ret.Get(0).(func(context.Context) repositories.RewardRepositoryInterface); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(repositories.RewardRepositoryInterface)
+		}
+	}
+
+	return r0
+}
+
+// RewardRepositoryInterface_WithContext_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'WithContext'
+type RewardRepositoryInterface_WithContext_Call struct {
+	*mock.Call
+}
+
+// WithContext is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *RewardRepositoryInterface_Expecter) WithContext(ctx interface{}) *RewardRepositoryInterface_WithContext_Call {
+	return &RewardRepositoryInterface_WithContext_Call{Call: _e.mock.On("WithContext", ctx)}
+}
+
+func (_c *RewardRepositoryInterface_WithContext_Call) Run(run func(ctx context.Context)) *RewardRepositoryInterface_WithContext_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *RewardRepositoryInterface_WithContext_Call) Return(_a0 repositories.RewardRepositoryInterface) *RewardRepositoryInterface_WithContext_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *RewardRepositoryInterface_WithContext_Call) RunAndReturn(run func(context.Context) repositories.RewardRepositoryInterface) *RewardRepositoryInterface_WithContext_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewRewardRepositoryInterface creates a new instance of RewardRepositoryInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRewardRepositoryInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *RewardRepositoryInterface {
+	mock := &RewardRepositoryInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
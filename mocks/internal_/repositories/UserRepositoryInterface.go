@@ -1,4 +1,4 @@
-// Code generated by mockery v2.43.2. DO NOT EDIT.
+// Code generated by mockery v2.53.7. DO NOT EDIT.
 
 package repositories
 
@@ -254,6 +254,54 @@ func (_c *UserRepositoryInterface_GeneratePasswordHash_Call) RunAndReturn(run fu
 	return _c
 }
 
+// UpdateNotificationPreferences provides a mock function with given fields: userID, email, preferences
+func (_m *UserRepositoryInterface) UpdateNotificationPreferences(userID uint, email string, preferences entities.NotificationPreferences) error {
+	ret := _m.Called(userID, email, preferences)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateNotificationPreferences")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uint, string, entities.NotificationPreferences) error); ok {
+		r0 = rf(userID, email, preferences)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UserRepositoryInterface_UpdateNotificationPreferences_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateNotificationPreferences'
+type UserRepositoryInterface_UpdateNotificationPreferences_Call struct {
+	*mock.Call
+}
+
+// UpdateNotificationPreferences is a helper method to define mock.On call
+//   - userID uint
+//   - email string
+//   - preferences entities.NotificationPreferences
+func (_e *UserRepositoryInterface_Expecter) UpdateNotificationPreferences(userID interface{}, email interface{}, preferences interface{}) *UserRepositoryInterface_UpdateNotificationPreferences_Call {
+	return &UserRepositoryInterface_UpdateNotificationPreferences_Call{Call: _e.mock.On("UpdateNotificationPreferences", userID, email, preferences)}
+}
+
+func (_c *UserRepositoryInterface_UpdateNotificationPreferences_Call) Run(run func(userID uint, email string, preferences entities.NotificationPreferences)) *UserRepositoryInterface_UpdateNotificationPreferences_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(uint), args[1].(string), args[2].(entities.NotificationPreferences))
+	})
+	return _c
+}
+
+func (_c *UserRepositoryInterface_UpdateNotificationPreferences_Call) Return(_a0 error) *UserRepositoryInterface_UpdateNotificationPreferences_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *UserRepositoryInterface_UpdateNotificationPreferences_Call) RunAndReturn(run func(uint, string, entities.NotificationPreferences) error) *UserRepositoryInterface_UpdateNotificationPreferences_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewUserRepositoryInterface creates a new instance of UserRepositoryInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewUserRepositoryInterface(t interface {
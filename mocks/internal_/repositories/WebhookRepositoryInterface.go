@@ -0,0 +1,567 @@
+// Code generated by mockery v2.53.7. DO NOT EDIT.
+
+package repositories
+
+import (
+	context "context"
+
+	entities "github.com/ShukinDmitriy/gophermart/internal/entities"
+	mock "github.com/stretchr/testify/mock"
+
+	repositories "github.com/ShukinDmitriy/gophermart/internal/repositories"
+
+	time "time"
+)
+
+// WebhookRepositoryInterface is an autogenerated mock type for the WebhookRepositoryInterface type
+type WebhookRepositoryInterface struct {
+	mock.Mock
+}
+
+type WebhookRepositoryInterface_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *WebhookRepositoryInterface) EXPECT() *WebhookRepositoryInterface_Expecter {
+	return &WebhookRepositoryInterface_Expecter{mock: &_m.Mock}
+}
+
+// ClaimDeliveries provides a mock function with given fields: now, lease, limit
+func (_m *WebhookRepositoryInterface) ClaimDeliveries(now time.Time, lease time.Duration, limit int) ([]*entities.WebhookDelivery, error) {
+	ret := _m.Called(now, lease, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClaimDeliveries")
+	}
+
+	var r0 []*entities.WebhookDelivery
+	var r1 error
+	if rf, ok := ret.Get(0).(func(time.Time, time.Duration, int) ([]*entities.WebhookDelivery, error)); ok {
+		return rf(now, lease, limit)
+	}
+	if rf, ok := ret.Get(0).(func(time.Time, time.Duration, int) []*entities.WebhookDelivery); ok {
+		r0 = rf(now, lease, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entities.WebhookDelivery)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(time.Time, time.Duration, int) error); ok {
+		r1 = rf(now, lease, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// WebhookRepositoryInterface_ClaimDeliveries_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ClaimDeliveries'
+type WebhookRepositoryInterface_ClaimDeliveries_Call struct {
+	*mock.Call
+}
+
+// ClaimDeliveries is a helper method to define mock.On call
+//   - now time.Time
+//   - lease time.Duration
+//   - limit int
+func (_e *WebhookRepositoryInterface_Expecter) ClaimDeliveries(now interface{}, lease interface{}, limit interface{}) *WebhookRepositoryInterface_ClaimDeliveries_Call {
+	return &WebhookRepositoryInterface_ClaimDeliveries_Call{Call: _e.mock.On("ClaimDeliveries", now, lease, limit)}
+}
+
+func (_c *WebhookRepositoryInterface_ClaimDeliveries_Call) Run(run func(now time.Time, lease time.Duration, limit int)) *WebhookRepositoryInterface_ClaimDeliveries_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(time.Time), args[1].(time.Duration), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *WebhookRepositoryInterface_ClaimDeliveries_Call) Return(_a0 []*entities.WebhookDelivery, _a1 error) *WebhookRepositoryInterface_ClaimDeliveries_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *WebhookRepositoryInterface_ClaimDeliveries_Call) RunAndReturn(run func(time.Time, time.Duration, int) ([]*entities.WebhookDelivery, error)) *WebhookRepositoryInterface_ClaimDeliveries_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateDeliveries provides a mock function with given fields: deliveries
+func (_m *WebhookRepositoryInterface) CreateDeliveries(deliveries []*entities.WebhookDelivery) error {
+	ret := _m.Called(deliveries)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateDeliveries")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func([]*entities.WebhookDelivery) error); ok {
+		r0 = rf(deliveries)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// WebhookRepositoryInterface_CreateDeliveries_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateDeliveries'
+type WebhookRepositoryInterface_CreateDeliveries_Call struct {
+	*mock.Call
+}
+
+// CreateDeliveries is a helper method to define mock.On call
+//   - deliveries []*entities.WebhookDelivery
+func (_e *WebhookRepositoryInterface_Expecter) CreateDeliveries(deliveries interface{}) *WebhookRepositoryInterface_CreateDeliveries_Call {
+	return &WebhookRepositoryInterface_CreateDeliveries_Call{Call: _e.mock.On("CreateDeliveries", deliveries)}
+}
+
+func (_c *WebhookRepositoryInterface_CreateDeliveries_Call) Run(run func(deliveries []*entities.WebhookDelivery)) *WebhookRepositoryInterface_CreateDeliveries_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].([]*entities.WebhookDelivery))
+	})
+	return _c
+}
+
+func (_c *WebhookRepositoryInterface_CreateDeliveries_Call) Return(_a0 error) *WebhookRepositoryInterface_CreateDeliveries_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *WebhookRepositoryInterface_CreateDeliveries_Call) RunAndReturn(run func([]*entities.WebhookDelivery) error) *WebhookRepositoryInterface_CreateDeliveries_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateWebhook provides a mock function with given fields: webhook
+func (_m *WebhookRepositoryInterface) CreateWebhook(webhook *entities.Webhook) error {
+	ret := _m.Called(webhook)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateWebhook")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*entities.Webhook) error); ok {
+		r0 = rf(webhook)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// WebhookRepositoryInterface_CreateWebhook_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateWebhook'
+type WebhookRepositoryInterface_CreateWebhook_Call struct {
+	*mock.Call
+}
+
+// CreateWebhook is a helper method to define mock.On call
+//   - webhook *entities.Webhook
+func (_e *WebhookRepositoryInterface_Expecter) CreateWebhook(webhook interface{}) *WebhookRepositoryInterface_CreateWebhook_Call {
+	return &WebhookRepositoryInterface_CreateWebhook_Call{Call: _e.mock.On("CreateWebhook", webhook)}
+}
+
+func (_c *WebhookRepositoryInterface_CreateWebhook_Call) Run(run func(webhook *entities.Webhook)) *WebhookRepositoryInterface_CreateWebhook_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*entities.Webhook))
+	})
+	return _c
+}
+
+func (_c *WebhookRepositoryInterface_CreateWebhook_Call) Return(_a0 error) *WebhookRepositoryInterface_CreateWebhook_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *WebhookRepositoryInterface_CreateWebhook_Call) RunAndReturn(run func(*entities.Webhook) error) *WebhookRepositoryInterface_CreateWebhook_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteWebhook provides a mock function with given fields: id
+func (_m *WebhookRepositoryInterface) DeleteWebhook(id uint) error {
+	ret := _m.Called(id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteWebhook")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uint) error); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// WebhookRepositoryInterface_DeleteWebhook_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteWebhook'
+type WebhookRepositoryInterface_DeleteWebhook_Call struct {
+	*mock.Call
+}
+
+// DeleteWebhook is a helper method to define mock.On call
+//   - id uint
+func (_e *WebhookRepositoryInterface_Expecter) DeleteWebhook(id interface{}) *WebhookRepositoryInterface_DeleteWebhook_Call {
+	return &WebhookRepositoryInterface_DeleteWebhook_Call{Call: _e.mock.On("DeleteWebhook", id)}
+}
+
+func (_c *WebhookRepositoryInterface_DeleteWebhook_Call) Run(run func(id uint)) *WebhookRepositoryInterface_DeleteWebhook_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(uint))
+	})
+	return _c
+}
+
+func (_c *WebhookRepositoryInterface_DeleteWebhook_Call) Return(_a0 error) *WebhookRepositoryInterface_DeleteWebhook_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *WebhookRepositoryInterface_DeleteWebhook_Call) RunAndReturn(run func(uint) error) *WebhookRepositoryInterface_DeleteWebhook_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindWebhook provides a mock function with given fields: id
+func (_m *WebhookRepositoryInterface) FindWebhook(id uint) (*entities.Webhook, error) {
+	ret := _m.Called(id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindWebhook")
+	}
+
+	var r0 *entities.Webhook
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint) (*entities.Webhook, error)); ok {
+		return rf(id)
+	}
+	if rf, ok := ret.Get(0).(func(uint) *entities.Webhook); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.Webhook)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// WebhookRepositoryInterface_FindWebhook_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindWebhook'
+type WebhookRepositoryInterface_FindWebhook_Call struct {
+	*mock.Call
+}
+
+// FindWebhook is a helper method to define mock.On call
+//   - id uint
+func (_e *WebhookRepositoryInterface_Expecter) FindWebhook(id interface{}) *WebhookRepositoryInterface_FindWebhook_Call {
+	return &WebhookRepositoryInterface_FindWebhook_Call{Call: _e.mock.On("FindWebhook", id)}
+}
+
+func (_c *WebhookRepositoryInterface_FindWebhook_Call) Run(run func(id uint)) *WebhookRepositoryInterface_FindWebhook_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(uint))
+	})
+	return _c
+}
+
+func (_c *WebhookRepositoryInterface_FindWebhook_Call) Return(_a0 *entities.Webhook, _a1 error) *WebhookRepositoryInterface_FindWebhook_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *WebhookRepositoryInterface_FindWebhook_Call) RunAndReturn(run func(uint) (*entities.Webhook, error)) *WebhookRepositoryInterface_FindWebhook_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetDeliveries provides a mock function with given fields: webhookID, statuses, limit
+func (_m *WebhookRepositoryInterface) GetDeliveries(webhookID uint, statuses []entities.WebhookDeliveryStatus, limit int) ([]*entities.WebhookDelivery, error) {
+	ret := _m.Called(webhookID, statuses, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDeliveries")
+	}
+
+	var r0 []*entities.WebhookDelivery
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint, []entities.WebhookDeliveryStatus, int) ([]*entities.WebhookDelivery, error)); ok {
+		return rf(webhookID, statuses, limit)
+	}
+	if rf, ok := ret.Get(0).(func(uint, []entities.WebhookDeliveryStatus, int) []*entities.WebhookDelivery); ok {
+		r0 = rf(webhookID, statuses, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entities.WebhookDelivery)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint, []entities.WebhookDeliveryStatus, int) error); ok {
+		r1 = rf(webhookID, statuses, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// WebhookRepositoryInterface_GetDeliveries_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetDeliveries'
+type WebhookRepositoryInterface_GetDeliveries_Call struct {
+	*mock.Call
+}
+
+// GetDeliveries is a helper method to define mock.On call
+//   - webhookID uint
+//   - statuses []entities.WebhookDeliveryStatus
+//   - limit int
+func (_e *WebhookRepositoryInterface_Expecter) GetDeliveries(webhookID interface{}, statuses interface{}, limit interface{}) *WebhookRepositoryInterface_GetDeliveries_Call {
+	return &WebhookRepositoryInterface_GetDeliveries_Call{Call: _e.mock.On("GetDeliveries", webhookID, statuses, limit)}
+}
+
+func (_c *WebhookRepositoryInterface_GetDeliveries_Call) Run(run func(webhookID uint, statuses []entities.WebhookDeliveryStatus, limit int)) *WebhookRepositoryInterface_GetDeliveries_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(uint), args[1].([]entities.WebhookDeliveryStatus), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *WebhookRepositoryInterface_GetDeliveries_Call) Return(_a0 []*entities.WebhookDelivery, _a1 error) *WebhookRepositoryInterface_GetDeliveries_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *WebhookRepositoryInterface_GetDeliveries_Call) RunAndReturn(run func(uint, []entities.WebhookDeliveryStatus, int) ([]*entities.WebhookDelivery, error)) *WebhookRepositoryInterface_GetDeliveries_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetWebhooks provides a mock function with given fields: userID
+func (_m *WebhookRepositoryInterface) GetWebhooks(userID *uint) ([]*entities.Webhook, error) {
+	ret := _m.Called(userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWebhooks")
+	}
+
+	var r0 []*entities.Webhook
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*uint) ([]*entities.Webhook, error)); ok {
+		return rf(userID)
+	}
+	if rf, ok := ret.Get(0).(func(*uint) []*entities.Webhook); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entities.Webhook)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*uint) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// WebhookRepositoryInterface_GetWebhooks_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetWebhooks'
+type WebhookRepositoryInterface_GetWebhooks_Call struct {
+	*mock.Call
+}
+
+// GetWebhooks is a helper method to define mock.On call
+//   - userID *uint
+func (_e *WebhookRepositoryInterface_Expecter) GetWebhooks(userID interface{}) *WebhookRepositoryInterface_GetWebhooks_Call {
+	return &WebhookRepositoryInterface_GetWebhooks_Call{Call: _e.mock.On("GetWebhooks", userID)}
+}
+
+func (_c *WebhookRepositoryInterface_GetWebhooks_Call) Run(run func(userID *uint)) *WebhookRepositoryInterface_GetWebhooks_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*uint))
+	})
+	return _c
+}
+
+func (_c *WebhookRepositoryInterface_GetWebhooks_Call) Return(_a0 []*entities.Webhook, _a1 error) *WebhookRepositoryInterface_GetWebhooks_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *WebhookRepositoryInterface_GetWebhooks_Call) RunAndReturn(run func(*uint) ([]*entities.Webhook, error)) *WebhookRepositoryInterface_GetWebhooks_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetWebhooksForUser provides a mock function with given fields: userID
+func (_m *WebhookRepositoryInterface) GetWebhooksForUser(userID uint) ([]*entities.Webhook, error) {
+	ret := _m.Called(userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWebhooksForUser")
+	}
+
+	var r0 []*entities.Webhook
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint) ([]*entities.Webhook, error)); ok {
+		return rf(userID)
+	}
+	if rf, ok := ret.Get(0).(func(uint) []*entities.Webhook); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entities.Webhook)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// WebhookRepositoryInterface_GetWebhooksForUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetWebhooksForUser'
+type WebhookRepositoryInterface_GetWebhooksForUser_Call struct {
+	*mock.Call
+}
+
+// GetWebhooksForUser is a helper method to define mock.On call
+//   - userID uint
+func (_e *WebhookRepositoryInterface_Expecter) GetWebhooksForUser(userID interface{}) *WebhookRepositoryInterface_GetWebhooksForUser_Call {
+	return &WebhookRepositoryInterface_GetWebhooksForUser_Call{Call: _e.mock.On("GetWebhooksForUser", userID)}
+}
+
+func (_c *WebhookRepositoryInterface_GetWebhooksForUser_Call) Run(run func(userID uint)) *WebhookRepositoryInterface_GetWebhooksForUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(uint))
+	})
+	return _c
+}
+
+func (_c *WebhookRepositoryInterface_GetWebhooksForUser_Call) Return(_a0 []*entities.Webhook, _a1 error) *WebhookRepositoryInterface_GetWebhooksForUser_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *WebhookRepositoryInterface_GetWebhooksForUser_Call) RunAndReturn(run func(uint) ([]*entities.Webhook, error)) *WebhookRepositoryInterface_GetWebhooksForUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateDelivery provides a mock function with given fields: delivery
+func (_m *WebhookRepositoryInterface) UpdateDelivery(delivery *entities.WebhookDelivery) error {
+	ret := _m.Called(delivery)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateDelivery")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*entities.WebhookDelivery) error); ok {
+		r0 = rf(delivery)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// WebhookRepositoryInterface_UpdateDelivery_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateDelivery'
+type WebhookRepositoryInterface_UpdateDelivery_Call struct {
+	*mock.Call
+}
+
+// UpdateDelivery is a helper method to define mock.On call
+//   - delivery *entities.WebhookDelivery
+func (_e *WebhookRepositoryInterface_Expecter) UpdateDelivery(delivery interface{}) *WebhookRepositoryInterface_UpdateDelivery_Call {
+	return &WebhookRepositoryInterface_UpdateDelivery_Call{Call: _e.mock.On("UpdateDelivery", delivery)}
+}
+
+func (_c *WebhookRepositoryInterface_UpdateDelivery_Call) Run(run func(delivery *entities.WebhookDelivery)) *WebhookRepositoryInterface_UpdateDelivery_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*entities.WebhookDelivery))
+	})
+	return _c
+}
+
+func (_c *WebhookRepositoryInterface_UpdateDelivery_Call) Return(_a0 error) *WebhookRepositoryInterface_UpdateDelivery_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *WebhookRepositoryInterface_UpdateDelivery_Call) RunAndReturn(run func(*entities.WebhookDelivery) error) *WebhookRepositoryInterface_UpdateDelivery_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// WithContext provides a mock function with given fields: ctx
+func (_m *WebhookRepositoryInterface) WithContext(ctx context.Context) repositories.WebhookRepositoryInterface {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WithContext")
+	}
+
+	var r0 repositories.WebhookRepositoryInterface
+	if rf, ok := ret.Get(0).(func(context.Context) repositories.WebhookRepositoryInterface); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(repositories.WebhookRepositoryInterface)
+		}
+	}
+
+	return r0
+}
+
+// WebhookRepositoryInterface_WithContext_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'WithContext'
+type WebhookRepositoryInterface_WithContext_Call struct {
+	*mock.Call
+}
+
+// WithContext is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *WebhookRepositoryInterface_Expecter) WithContext(ctx interface{}) *WebhookRepositoryInterface_WithContext_Call {
+	return &WebhookRepositoryInterface_WithContext_Call{Call: _e.mock.On("WithContext", ctx)}
+}
+
+func (_c *WebhookRepositoryInterface_WithContext_Call) Run(run func(ctx context.Context)) *WebhookRepositoryInterface_WithContext_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *WebhookRepositoryInterface_WithContext_Call) Return(_a0 repositories.WebhookRepositoryInterface) *WebhookRepositoryInterface_WithContext_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *WebhookRepositoryInterface_WithContext_Call) RunAndReturn(run func(context.Context) repositories.WebhookRepositoryInterface) *WebhookRepositoryInterface_WithContext_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewWebhookRepositoryInterface creates a new instance of WebhookRepositoryInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewWebhookRepositoryInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *WebhookRepositoryInterface {
+	mock := &WebhookRepositoryInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,149 @@
+// Code generated by mockery v2.53.7. DO NOT EDIT.
+
+package services
+
+import (
+	context "context"
+
+	models "github.com/ShukinDmitriy/gophermart/internal/models"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// AccrualProvider is an autogenerated mock type for the AccrualProvider type
+type AccrualProvider struct {
+	mock.Mock
+}
+
+type AccrualProvider_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *AccrualProvider) EXPECT() *AccrualProvider_Expecter {
+	return &AccrualProvider_Expecter{mock: &_m.Mock}
+}
+
+// FetchOrder provides a mock function with given fields: ctx, orderNumber
+func (_m *AccrualProvider) FetchOrder(ctx context.Context, orderNumber string) (*models.AccrualOrderResponse, int, error) {
+	ret := _m.Called(ctx, orderNumber)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FetchOrder")
+	}
+
+	var r0 *models.AccrualOrderResponse
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.AccrualOrderResponse, int, error)); ok {
+		return rf(ctx, orderNumber)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.AccrualOrderResponse); ok {
+		r0 = rf(ctx, orderNumber)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.AccrualOrderResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) int); ok {
+		r1 = rf(ctx, orderNumber)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string) error); ok {
+		r2 = rf(ctx, orderNumber)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// AccrualProvider_FetchOrder_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FetchOrder'
+type AccrualProvider_FetchOrder_Call struct {
+	*mock.Call
+}
+
+// FetchOrder is a helper method to define mock.On call
+//   - ctx context.Context
+//   - orderNumber string
+func (_e *AccrualProvider_Expecter) FetchOrder(ctx interface{}, orderNumber interface{}) *AccrualProvider_FetchOrder_Call {
+	return &AccrualProvider_FetchOrder_Call{Call: _e.mock.On("FetchOrder", ctx, orderNumber)}
+}
+
+func (_c *AccrualProvider_FetchOrder_Call) Run(run func(ctx context.Context, orderNumber string)) *AccrualProvider_FetchOrder_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *AccrualProvider_FetchOrder_Call) Return(res *models.AccrualOrderResponse, statusCode int, err error) *AccrualProvider_FetchOrder_Call {
+	_c.Call.Return(res, statusCode, err)
+	return _c
+}
+
+func (_c *AccrualProvider_FetchOrder_Call) RunAndReturn(run func(context.Context, string) (*models.AccrualOrderResponse, int, error)) *AccrualProvider_FetchOrder_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Ping provides a mock function with given fields: ctx
+func (_m *AccrualProvider) Ping(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Ping")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AccrualProvider_Ping_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Ping'
+type AccrualProvider_Ping_Call struct {
+	*mock.Call
+}
+
+// Ping is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *AccrualProvider_Expecter) Ping(ctx interface{}) *AccrualProvider_Ping_Call {
+	return &AccrualProvider_Ping_Call{Call: _e.mock.On("Ping", ctx)}
+}
+
+func (_c *AccrualProvider_Ping_Call) Run(run func(ctx context.Context)) *AccrualProvider_Ping_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *AccrualProvider_Ping_Call) Return(_a0 error) *AccrualProvider_Ping_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *AccrualProvider_Ping_Call) RunAndReturn(run func(context.Context) error) *AccrualProvider_Ping_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewAccrualProvider creates a new instance of AccrualProvider. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAccrualProvider(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *AccrualProvider {
+	mock := &AccrualProvider{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -1,11 +1,14 @@
-// Code generated by mockery v2.43.2. DO NOT EDIT.
+// Code generated by mockery v2.53.7. DO NOT EDIT.
 
 package services
 
 import (
+	context "context"
+
 	entities "github.com/ShukinDmitriy/gophermart/internal/entities"
-	echo "github.com/labstack/echo/v4"
 	mock "github.com/stretchr/testify/mock"
+
+	models "github.com/ShukinDmitriy/gophermart/internal/models"
 )
 
 // AccrualServiceInterface is an autogenerated mock type for the AccrualServiceInterface type
@@ -21,9 +24,161 @@ func (_m *AccrualServiceInterface) EXPECT() *AccrualServiceInterface_Expecter {
 	return &AccrualServiceInterface_Expecter{mock: &_m.Mock}
 }
 
-// ProcessFailedOrders provides a mock function with given fields: e
-func (_m *AccrualServiceInterface) ProcessFailedOrders(e *echo.Echo) {
-	_m.Called(e)
+// ApplyCallback provides a mock function with given fields: ctx, accrualOrder
+func (_m *AccrualServiceInterface) ApplyCallback(ctx context.Context, accrualOrder models.AccrualOrderResponse) error {
+	ret := _m.Called(ctx, accrualOrder)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ApplyCallback")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.AccrualOrderResponse) error); ok {
+		r0 = rf(ctx, accrualOrder)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AccrualServiceInterface_ApplyCallback_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ApplyCallback'
+type AccrualServiceInterface_ApplyCallback_Call struct {
+	*mock.Call
+}
+
+// ApplyCallback is a helper method to define mock.On call
+//   - ctx context.Context
+//   - accrualOrder models.AccrualOrderResponse
+func (_e *AccrualServiceInterface_Expecter) ApplyCallback(ctx interface{}, accrualOrder interface{}) *AccrualServiceInterface_ApplyCallback_Call {
+	return &AccrualServiceInterface_ApplyCallback_Call{Call: _e.mock.On("ApplyCallback", ctx, accrualOrder)}
+}
+
+func (_c *AccrualServiceInterface_ApplyCallback_Call) Run(run func(ctx context.Context, accrualOrder models.AccrualOrderResponse)) *AccrualServiceInterface_ApplyCallback_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.AccrualOrderResponse))
+	})
+	return _c
+}
+
+func (_c *AccrualServiceInterface_ApplyCallback_Call) Return(_a0 error) *AccrualServiceInterface_ApplyCallback_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *AccrualServiceInterface_ApplyCallback_Call) RunAndReturn(run func(context.Context, models.AccrualOrderResponse) error) *AccrualServiceInterface_ApplyCallback_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FetchOrder provides a mock function with given fields: ctx, orderNumber
+func (_m *AccrualServiceInterface) FetchOrder(ctx context.Context, orderNumber string) (*models.AccrualOrderResponse, error) {
+	ret := _m.Called(ctx, orderNumber)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FetchOrder")
+	}
+
+	var r0 *models.AccrualOrderResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.AccrualOrderResponse, error)); ok {
+		return rf(ctx, orderNumber)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.AccrualOrderResponse); ok {
+		r0 = rf(ctx, orderNumber)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.AccrualOrderResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, orderNumber)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AccrualServiceInterface_FetchOrder_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FetchOrder'
+type AccrualServiceInterface_FetchOrder_Call struct {
+	*mock.Call
+}
+
+// FetchOrder is a helper method to define mock.On call
+//   - ctx context.Context
+//   - orderNumber string
+func (_e *AccrualServiceInterface_Expecter) FetchOrder(ctx interface{}, orderNumber interface{}) *AccrualServiceInterface_FetchOrder_Call {
+	return &AccrualServiceInterface_FetchOrder_Call{Call: _e.mock.On("FetchOrder", ctx, orderNumber)}
+}
+
+func (_c *AccrualServiceInterface_FetchOrder_Call) Run(run func(ctx context.Context, orderNumber string)) *AccrualServiceInterface_FetchOrder_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *AccrualServiceInterface_FetchOrder_Call) Return(_a0 *models.AccrualOrderResponse, _a1 error) *AccrualServiceInterface_FetchOrder_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *AccrualServiceInterface_FetchOrder_Call) RunAndReturn(run func(context.Context, string) (*models.AccrualOrderResponse, error)) *AccrualServiceInterface_FetchOrder_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Ping provides a mock function with given fields: ctx
+func (_m *AccrualServiceInterface) Ping(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Ping")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AccrualServiceInterface_Ping_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Ping'
+type AccrualServiceInterface_Ping_Call struct {
+	*mock.Call
+}
+
+// Ping is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *AccrualServiceInterface_Expecter) Ping(ctx interface{}) *AccrualServiceInterface_Ping_Call {
+	return &AccrualServiceInterface_Ping_Call{Call: _e.mock.On("Ping", ctx)}
+}
+
+func (_c *AccrualServiceInterface_Ping_Call) Run(run func(ctx context.Context)) *AccrualServiceInterface_Ping_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *AccrualServiceInterface_Ping_Call) Return(_a0 error) *AccrualServiceInterface_Ping_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *AccrualServiceInterface_Ping_Call) RunAndReturn(run func(context.Context) error) *AccrualServiceInterface_Ping_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ProcessFailedOrders provides a mock function with no fields
+func (_m *AccrualServiceInterface) ProcessFailedOrders() {
+	_m.Called()
 }
 
 // AccrualServiceInterface_ProcessFailedOrders_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ProcessFailedOrders'
@@ -32,14 +187,13 @@ type AccrualServiceInterface_ProcessFailedOrders_Call struct {
 }
 
 // ProcessFailedOrders is a helper method to define mock.On call
-//   - e *echo.Echo
-func (_e *AccrualServiceInterface_Expecter) ProcessFailedOrders(e interface{}) *AccrualServiceInterface_ProcessFailedOrders_Call {
-	return &AccrualServiceInterface_ProcessFailedOrders_Call{Call: _e.mock.On("ProcessFailedOrders", e)}
+func (_e *AccrualServiceInterface_Expecter) ProcessFailedOrders() *AccrualServiceInterface_ProcessFailedOrders_Call {
+	return &AccrualServiceInterface_ProcessFailedOrders_Call{Call: _e.mock.On("ProcessFailedOrders")}
 }
 
-func (_c *AccrualServiceInterface_ProcessFailedOrders_Call) Run(run func(e *echo.Echo)) *AccrualServiceInterface_ProcessFailedOrders_Call {
+func (_c *AccrualServiceInterface_ProcessFailedOrders_Call) Run(run func()) *AccrualServiceInterface_ProcessFailedOrders_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(*echo.Echo))
+		run()
 	})
 	return _c
 }
@@ -49,14 +203,14 @@ func (_c *AccrualServiceInterface_ProcessFailedOrders_Call) Return() *AccrualSer
 	return _c
 }
 
-func (_c *AccrualServiceInterface_ProcessFailedOrders_Call) RunAndReturn(run func(*echo.Echo)) *AccrualServiceInterface_ProcessFailedOrders_Call {
-	_c.Call.Return(run)
+func (_c *AccrualServiceInterface_ProcessFailedOrders_Call) RunAndReturn(run func()) *AccrualServiceInterface_ProcessFailedOrders_Call {
+	_c.Run(run)
 	return _c
 }
 
-// ProcessOrders provides a mock function with given fields: e
-func (_m *AccrualServiceInterface) ProcessOrders(e *echo.Echo) {
-	_m.Called(e)
+// ProcessOrders provides a mock function with no fields
+func (_m *AccrualServiceInterface) ProcessOrders() {
+	_m.Called()
 }
 
 // AccrualServiceInterface_ProcessOrders_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ProcessOrders'
@@ -65,14 +219,13 @@ type AccrualServiceInterface_ProcessOrders_Call struct {
 }
 
 // ProcessOrders is a helper method to define mock.On call
-//   - e *echo.Echo
-func (_e *AccrualServiceInterface_Expecter) ProcessOrders(e interface{}) *AccrualServiceInterface_ProcessOrders_Call {
-	return &AccrualServiceInterface_ProcessOrders_Call{Call: _e.mock.On("ProcessOrders", e)}
+func (_e *AccrualServiceInterface_Expecter) ProcessOrders() *AccrualServiceInterface_ProcessOrders_Call {
+	return &AccrualServiceInterface_ProcessOrders_Call{Call: _e.mock.On("ProcessOrders")}
 }
 
-func (_c *AccrualServiceInterface_ProcessOrders_Call) Run(run func(e *echo.Echo)) *AccrualServiceInterface_ProcessOrders_Call {
+func (_c *AccrualServiceInterface_ProcessOrders_Call) Run(run func()) *AccrualServiceInterface_ProcessOrders_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(*echo.Echo))
+		run()
 	})
 	return _c
 }
@@ -82,14 +235,104 @@ func (_c *AccrualServiceInterface_ProcessOrders_Call) Return() *AccrualServiceIn
 	return _c
 }
 
-func (_c *AccrualServiceInterface_ProcessOrders_Call) RunAndReturn(run func(*echo.Echo)) *AccrualServiceInterface_ProcessOrders_Call {
+func (_c *AccrualServiceInterface_ProcessOrders_Call) RunAndReturn(run func()) *AccrualServiceInterface_ProcessOrders_Call {
+	_c.Run(run)
+	return _c
+}
+
+// QueueStats provides a mock function with no fields
+func (_m *AccrualServiceInterface) QueueStats() models.AccrualQueueStats {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for QueueStats")
+	}
+
+	var r0 models.AccrualQueueStats
+	if rf, ok := ret.Get(0).(func() models.AccrualQueueStats); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(models.AccrualQueueStats)
+	}
+
+	return r0
+}
+
+// AccrualServiceInterface_QueueStats_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'QueueStats'
+type AccrualServiceInterface_QueueStats_Call struct {
+	*mock.Call
+}
+
+// QueueStats is a helper method to define mock.On call
+func (_e *AccrualServiceInterface_Expecter) QueueStats() *AccrualServiceInterface_QueueStats_Call {
+	return &AccrualServiceInterface_QueueStats_Call{Call: _e.mock.On("QueueStats")}
+}
+
+func (_c *AccrualServiceInterface_QueueStats_Call) Run(run func()) *AccrualServiceInterface_QueueStats_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *AccrualServiceInterface_QueueStats_Call) Return(_a0 models.AccrualQueueStats) *AccrualServiceInterface_QueueStats_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *AccrualServiceInterface_QueueStats_Call) RunAndReturn(run func() models.AccrualQueueStats) *AccrualServiceInterface_QueueStats_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// SendOrderToQueue provides a mock function with given fields: order
-func (_m *AccrualServiceInterface) SendOrderToQueue(order entities.Order) {
-	_m.Called(order)
+// RunningWorkers provides a mock function with no fields
+func (_m *AccrualServiceInterface) RunningWorkers() int {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for RunningWorkers")
+	}
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func() int); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	return r0
+}
+
+// AccrualServiceInterface_RunningWorkers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RunningWorkers'
+type AccrualServiceInterface_RunningWorkers_Call struct {
+	*mock.Call
+}
+
+// RunningWorkers is a helper method to define mock.On call
+func (_e *AccrualServiceInterface_Expecter) RunningWorkers() *AccrualServiceInterface_RunningWorkers_Call {
+	return &AccrualServiceInterface_RunningWorkers_Call{Call: _e.mock.On("RunningWorkers")}
+}
+
+func (_c *AccrualServiceInterface_RunningWorkers_Call) Run(run func()) *AccrualServiceInterface_RunningWorkers_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *AccrualServiceInterface_RunningWorkers_Call) Return(_a0 int) *AccrualServiceInterface_RunningWorkers_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *AccrualServiceInterface_RunningWorkers_Call) RunAndReturn(run func() int) *AccrualServiceInterface_RunningWorkers_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SendOrderToQueue provides a mock function with given fields: ctx, order
+func (_m *AccrualServiceInterface) SendOrderToQueue(ctx context.Context, order entities.Order) {
+	_m.Called(ctx, order)
 }
 
 // AccrualServiceInterface_SendOrderToQueue_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SendOrderToQueue'
@@ -98,14 +341,15 @@ type AccrualServiceInterface_SendOrderToQueue_Call struct {
 }
 
 // SendOrderToQueue is a helper method to define mock.On call
+//   - ctx context.Context
 //   - order entities.Order
-func (_e *AccrualServiceInterface_Expecter) SendOrderToQueue(order interface{}) *AccrualServiceInterface_SendOrderToQueue_Call {
-	return &AccrualServiceInterface_SendOrderToQueue_Call{Call: _e.mock.On("SendOrderToQueue", order)}
+func (_e *AccrualServiceInterface_Expecter) SendOrderToQueue(ctx interface{}, order interface{}) *AccrualServiceInterface_SendOrderToQueue_Call {
+	return &AccrualServiceInterface_SendOrderToQueue_Call{Call: _e.mock.On("SendOrderToQueue", ctx, order)}
 }
 
-func (_c *AccrualServiceInterface_SendOrderToQueue_Call) Run(run func(order entities.Order)) *AccrualServiceInterface_SendOrderToQueue_Call {
+func (_c *AccrualServiceInterface_SendOrderToQueue_Call) Run(run func(ctx context.Context, order entities.Order)) *AccrualServiceInterface_SendOrderToQueue_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(entities.Order))
+		run(args[0].(context.Context), args[1].(entities.Order))
 	})
 	return _c
 }
@@ -115,7 +359,54 @@ func (_c *AccrualServiceInterface_SendOrderToQueue_Call) Return() *AccrualServic
 	return _c
 }
 
-func (_c *AccrualServiceInterface_SendOrderToQueue_Call) RunAndReturn(run func(entities.Order)) *AccrualServiceInterface_SendOrderToQueue_Call {
+func (_c *AccrualServiceInterface_SendOrderToQueue_Call) RunAndReturn(run func(context.Context, entities.Order)) *AccrualServiceInterface_SendOrderToQueue_Call {
+	_c.Run(run)
+	return _c
+}
+
+// TrySendOrderToQueue provides a mock function with given fields: ctx, order
+func (_m *AccrualServiceInterface) TrySendOrderToQueue(ctx context.Context, order entities.Order) bool {
+	ret := _m.Called(ctx, order)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TrySendOrderToQueue")
+	}
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(context.Context, entities.Order) bool); ok {
+		r0 = rf(ctx, order)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// AccrualServiceInterface_TrySendOrderToQueue_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'TrySendOrderToQueue'
+type AccrualServiceInterface_TrySendOrderToQueue_Call struct {
+	*mock.Call
+}
+
+// TrySendOrderToQueue is a helper method to define mock.On call
+//   - ctx context.Context
+//   - order entities.Order
+func (_e *AccrualServiceInterface_Expecter) TrySendOrderToQueue(ctx interface{}, order interface{}) *AccrualServiceInterface_TrySendOrderToQueue_Call {
+	return &AccrualServiceInterface_TrySendOrderToQueue_Call{Call: _e.mock.On("TrySendOrderToQueue", ctx, order)}
+}
+
+func (_c *AccrualServiceInterface_TrySendOrderToQueue_Call) Run(run func(ctx context.Context, order entities.Order)) *AccrualServiceInterface_TrySendOrderToQueue_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(entities.Order))
+	})
+	return _c
+}
+
+func (_c *AccrualServiceInterface_TrySendOrderToQueue_Call) Return(_a0 bool) *AccrualServiceInterface_TrySendOrderToQueue_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *AccrualServiceInterface_TrySendOrderToQueue_Call) RunAndReturn(run func(context.Context, entities.Order) bool) *AccrualServiceInterface_TrySendOrderToQueue_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -0,0 +1,129 @@
+// Code generated by mockery v2.53.7. DO NOT EDIT.
+
+package services
+
+import (
+	context "context"
+
+	models "github.com/ShukinDmitriy/gophermart/internal/models"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// EventSinkInterface is an autogenerated mock type for the EventSinkInterface type
+type EventSinkInterface struct {
+	mock.Mock
+}
+
+type EventSinkInterface_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *EventSinkInterface) EXPECT() *EventSinkInterface_Expecter {
+	return &EventSinkInterface_Expecter{mock: &_m.Mock}
+}
+
+// Close provides a mock function with no fields
+func (_m *EventSinkInterface) Close() error {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Close")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// EventSinkInterface_Close_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Close'
+type EventSinkInterface_Close_Call struct {
+	*mock.Call
+}
+
+// Close is a helper method to define mock.On call
+func (_e *EventSinkInterface_Expecter) Close() *EventSinkInterface_Close_Call {
+	return &EventSinkInterface_Close_Call{Call: _e.mock.On("Close")}
+}
+
+func (_c *EventSinkInterface_Close_Call) Run(run func()) *EventSinkInterface_Close_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *EventSinkInterface_Close_Call) Return(_a0 error) *EventSinkInterface_Close_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *EventSinkInterface_Close_Call) RunAndReturn(run func() error) *EventSinkInterface_Close_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Publish provides a mock function with given fields: ctx, events
+func (_m *EventSinkInterface) Publish(ctx context.Context, events []models.DomainEvent) error {
+	ret := _m.Called(ctx, events)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Publish")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []models.DomainEvent) error); ok {
+		r0 = rf(ctx, events)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// EventSinkInterface_Publish_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Publish'
+type EventSinkInterface_Publish_Call struct {
+	*mock.Call
+}
+
+// Publish is a helper method to define mock.On call
+//   - ctx context.Context
+//   - events []models.DomainEvent
+func (_e *EventSinkInterface_Expecter) Publish(ctx interface{}, events interface{}) *EventSinkInterface_Publish_Call {
+	return &EventSinkInterface_Publish_Call{Call: _e.mock.On("Publish", ctx, events)}
+}
+
+func (_c *EventSinkInterface_Publish_Call) Run(run func(ctx context.Context, events []models.DomainEvent)) *EventSinkInterface_Publish_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]models.DomainEvent))
+	})
+	return _c
+}
+
+func (_c *EventSinkInterface_Publish_Call) Return(_a0 error) *EventSinkInterface_Publish_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *EventSinkInterface_Publish_Call) RunAndReturn(run func(context.Context, []models.DomainEvent) error) *EventSinkInterface_Publish_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewEventSinkInterface creates a new instance of EventSinkInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewEventSinkInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *EventSinkInterface {
+	mock := &EventSinkInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,165 @@
+// Code generated by mockery v2.53.7. DO NOT EDIT.
+
+package services
+
+import (
+	context "context"
+
+	models "github.com/ShukinDmitriy/gophermart/internal/models"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// HealthServiceInterface is an autogenerated mock type for the HealthServiceInterface type
+type HealthServiceInterface struct {
+	mock.Mock
+}
+
+type HealthServiceInterface_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *HealthServiceInterface) EXPECT() *HealthServiceInterface_Expecter {
+	return &HealthServiceInterface_Expecter{mock: &_m.Mock}
+}
+
+// Health provides a mock function with given fields: ctx
+func (_m *HealthServiceInterface) Health(ctx context.Context) *models.HealthResponse {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Health")
+	}
+
+	var r0 *models.HealthResponse
+	if rf, ok := ret.Get(0).(func(context.Context) *models.HealthResponse); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.HealthResponse)
+		}
+	}
+
+	return r0
+}
+
+// HealthServiceInterface_Health_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Health'
+type HealthServiceInterface_Health_Call struct {
+	*mock.Call
+}
+
+// Health is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *HealthServiceInterface_Expecter) Health(ctx interface{}) *HealthServiceInterface_Health_Call {
+	return &HealthServiceInterface_Health_Call{Call: _e.mock.On("Health", ctx)}
+}
+
+func (_c *HealthServiceInterface_Health_Call) Run(run func(ctx context.Context)) *HealthServiceInterface_Health_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *HealthServiceInterface_Health_Call) Return(_a0 *models.HealthResponse) *HealthServiceInterface_Health_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *HealthServiceInterface_Health_Call) RunAndReturn(run func(context.Context) *models.HealthResponse) *HealthServiceInterface_Health_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Ready provides a mock function with given fields: ctx
+func (_m *HealthServiceInterface) Ready(ctx context.Context) *models.ReadinessResponse {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Ready")
+	}
+
+	var r0 *models.ReadinessResponse
+	if rf, ok := ret.Get(0).(func(context.Context) *models.ReadinessResponse); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.ReadinessResponse)
+		}
+	}
+
+	return r0
+}
+
+// HealthServiceInterface_Ready_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Ready'
+type HealthServiceInterface_Ready_Call struct {
+	*mock.Call
+}
+
+// Ready is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *HealthServiceInterface_Expecter) Ready(ctx interface{}) *HealthServiceInterface_Ready_Call {
+	return &HealthServiceInterface_Ready_Call{Call: _e.mock.On("Ready", ctx)}
+}
+
+func (_c *HealthServiceInterface_Ready_Call) Run(run func(ctx context.Context)) *HealthServiceInterface_Ready_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *HealthServiceInterface_Ready_Call) Return(_a0 *models.ReadinessResponse) *HealthServiceInterface_Ready_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *HealthServiceInterface_Ready_Call) RunAndReturn(run func(context.Context) *models.ReadinessResponse) *HealthServiceInterface_Ready_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetShuttingDown provides a mock function with no fields
+func (_m *HealthServiceInterface) SetShuttingDown() {
+	_m.Called()
+}
+
+// HealthServiceInterface_SetShuttingDown_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetShuttingDown'
+type HealthServiceInterface_SetShuttingDown_Call struct {
+	*mock.Call
+}
+
+// SetShuttingDown is a helper method to define mock.On call
+func (_e *HealthServiceInterface_Expecter) SetShuttingDown() *HealthServiceInterface_SetShuttingDown_Call {
+	return &HealthServiceInterface_SetShuttingDown_Call{Call: _e.mock.On("SetShuttingDown")}
+}
+
+func (_c *HealthServiceInterface_SetShuttingDown_Call) Run(run func()) *HealthServiceInterface_SetShuttingDown_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *HealthServiceInterface_SetShuttingDown_Call) Return() *HealthServiceInterface_SetShuttingDown_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *HealthServiceInterface_SetShuttingDown_Call) RunAndReturn(run func()) *HealthServiceInterface_SetShuttingDown_Call {
+	_c.Run(run)
+	return _c
+}
+
+// NewHealthServiceInterface creates a new instance of HealthServiceInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewHealthServiceInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *HealthServiceInterface {
+	mock := &HealthServiceInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,258 @@
+// Code generated by mockery v2.53.7. DO NOT EDIT.
+
+package services
+
+import (
+	models "github.com/ShukinDmitriy/gophermart/internal/models"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// LedgerServiceInterface is an autogenerated mock type for the LedgerServiceInterface type
+type LedgerServiceInterface struct {
+	mock.Mock
+}
+
+type LedgerServiceInterface_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *LedgerServiceInterface) EXPECT() *LedgerServiceInterface_Expecter {
+	return &LedgerServiceInterface_Expecter{mock: &_m.Mock}
+}
+
+// GetBalance provides a mock function with given fields: userID
+func (_m *LedgerServiceInterface) GetBalance(userID uint) (*models.GetBalanceResponse, error) {
+	ret := _m.Called(userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBalance")
+	}
+
+	var r0 *models.GetBalanceResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint) (*models.GetBalanceResponse, error)); ok {
+		return rf(userID)
+	}
+	if rf, ok := ret.Get(0).(func(uint) *models.GetBalanceResponse); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.GetBalanceResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LedgerServiceInterface_GetBalance_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetBalance'
+type LedgerServiceInterface_GetBalance_Call struct {
+	*mock.Call
+}
+
+// GetBalance is a helper method to define mock.On call
+//   - userID uint
+func (_e *LedgerServiceInterface_Expecter) GetBalance(userID interface{}) *LedgerServiceInterface_GetBalance_Call {
+	return &LedgerServiceInterface_GetBalance_Call{Call: _e.mock.On("GetBalance", userID)}
+}
+
+func (_c *LedgerServiceInterface_GetBalance_Call) Run(run func(userID uint)) *LedgerServiceInterface_GetBalance_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(uint))
+	})
+	return _c
+}
+
+func (_c *LedgerServiceInterface_GetBalance_Call) Return(_a0 *models.GetBalanceResponse, _a1 error) *LedgerServiceInterface_GetBalance_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *LedgerServiceInterface_GetBalance_Call) RunAndReturn(run func(uint) (*models.GetBalanceResponse, error)) *LedgerServiceInterface_GetBalance_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListOperations provides a mock function with given fields: userID, request
+func (_m *LedgerServiceInterface) ListOperations(userID uint, request models.GetOperationsRequest) (*models.GetOperationsPage, error) {
+	ret := _m.Called(userID, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListOperations")
+	}
+
+	var r0 *models.GetOperationsPage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint, models.GetOperationsRequest) (*models.GetOperationsPage, error)); ok {
+		return rf(userID, request)
+	}
+	if rf, ok := ret.Get(0).(func(uint, models.GetOperationsRequest) *models.GetOperationsPage); ok {
+		r0 = rf(userID, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.GetOperationsPage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint, models.GetOperationsRequest) error); ok {
+		r1 = rf(userID, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LedgerServiceInterface_ListOperations_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListOperations'
+type LedgerServiceInterface_ListOperations_Call struct {
+	*mock.Call
+}
+
+// ListOperations is a helper method to define mock.On call
+//   - userID uint
+//   - request models.GetOperationsRequest
+func (_e *LedgerServiceInterface_Expecter) ListOperations(userID interface{}, request interface{}) *LedgerServiceInterface_ListOperations_Call {
+	return &LedgerServiceInterface_ListOperations_Call{Call: _e.mock.On("ListOperations", userID, request)}
+}
+
+func (_c *LedgerServiceInterface_ListOperations_Call) Run(run func(userID uint, request models.GetOperationsRequest)) *LedgerServiceInterface_ListOperations_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(uint), args[1].(models.GetOperationsRequest))
+	})
+	return _c
+}
+
+func (_c *LedgerServiceInterface_ListOperations_Call) Return(_a0 *models.GetOperationsPage, _a1 error) *LedgerServiceInterface_ListOperations_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *LedgerServiceInterface_ListOperations_Call) RunAndReturn(run func(uint, models.GetOperationsRequest) (*models.GetOperationsPage, error)) *LedgerServiceInterface_ListOperations_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListWithdrawals provides a mock function with given fields: userID, request
+func (_m *LedgerServiceInterface) ListWithdrawals(userID uint, request models.GetWithdrawalsRequest) (*models.GetWithdrawalsPage, error) {
+	ret := _m.Called(userID, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListWithdrawals")
+	}
+
+	var r0 *models.GetWithdrawalsPage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint, models.GetWithdrawalsRequest) (*models.GetWithdrawalsPage, error)); ok {
+		return rf(userID, request)
+	}
+	if rf, ok := ret.Get(0).(func(uint, models.GetWithdrawalsRequest) *models.GetWithdrawalsPage); ok {
+		r0 = rf(userID, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.GetWithdrawalsPage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint, models.GetWithdrawalsRequest) error); ok {
+		r1 = rf(userID, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LedgerServiceInterface_ListWithdrawals_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListWithdrawals'
+type LedgerServiceInterface_ListWithdrawals_Call struct {
+	*mock.Call
+}
+
+// ListWithdrawals is a helper method to define mock.On call
+//   - userID uint
+//   - request models.GetWithdrawalsRequest
+func (_e *LedgerServiceInterface_Expecter) ListWithdrawals(userID interface{}, request interface{}) *LedgerServiceInterface_ListWithdrawals_Call {
+	return &LedgerServiceInterface_ListWithdrawals_Call{Call: _e.mock.On("ListWithdrawals", userID, request)}
+}
+
+func (_c *LedgerServiceInterface_ListWithdrawals_Call) Run(run func(userID uint, request models.GetWithdrawalsRequest)) *LedgerServiceInterface_ListWithdrawals_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(uint), args[1].(models.GetWithdrawalsRequest))
+	})
+	return _c
+}
+
+func (_c *LedgerServiceInterface_ListWithdrawals_Call) Return(_a0 *models.GetWithdrawalsPage, _a1 error) *LedgerServiceInterface_ListWithdrawals_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *LedgerServiceInterface_ListWithdrawals_Call) RunAndReturn(run func(uint, models.GetWithdrawalsRequest) (*models.GetWithdrawalsPage, error)) *LedgerServiceInterface_ListWithdrawals_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Withdraw provides a mock function with given fields: userID, request
+func (_m *LedgerServiceInterface) Withdraw(userID uint, request models.CreateWithdrawRequest) error {
+	ret := _m.Called(userID, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Withdraw")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uint, models.CreateWithdrawRequest) error); ok {
+		r0 = rf(userID, request)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// LedgerServiceInterface_Withdraw_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Withdraw'
+type LedgerServiceInterface_Withdraw_Call struct {
+	*mock.Call
+}
+
+// Withdraw is a helper method to define mock.On call
+//   - userID uint
+//   - request models.CreateWithdrawRequest
+func (_e *LedgerServiceInterface_Expecter) Withdraw(userID interface{}, request interface{}) *LedgerServiceInterface_Withdraw_Call {
+	return &LedgerServiceInterface_Withdraw_Call{Call: _e.mock.On("Withdraw", userID, request)}
+}
+
+func (_c *LedgerServiceInterface_Withdraw_Call) Run(run func(userID uint, request models.CreateWithdrawRequest)) *LedgerServiceInterface_Withdraw_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(uint), args[1].(models.CreateWithdrawRequest))
+	})
+	return _c
+}
+
+func (_c *LedgerServiceInterface_Withdraw_Call) Return(_a0 error) *LedgerServiceInterface_Withdraw_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *LedgerServiceInterface_Withdraw_Call) RunAndReturn(run func(uint, models.CreateWithdrawRequest) error) *LedgerServiceInterface_Withdraw_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewLedgerServiceInterface creates a new instance of LedgerServiceInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewLedgerServiceInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *LedgerServiceInterface {
+	mock := &LedgerServiceInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
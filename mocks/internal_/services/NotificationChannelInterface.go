@@ -0,0 +1,85 @@
+// Code generated by mockery v2.53.7. DO NOT EDIT.
+
+package services
+
+import (
+	context "context"
+
+	entities "github.com/ShukinDmitriy/gophermart/internal/entities"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NotificationChannelInterface is an autogenerated mock type for the NotificationChannelInterface type
+type NotificationChannelInterface struct {
+	mock.Mock
+}
+
+type NotificationChannelInterface_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *NotificationChannelInterface) EXPECT() *NotificationChannelInterface_Expecter {
+	return &NotificationChannelInterface_Expecter{mock: &_m.Mock}
+}
+
+// Send provides a mock function with given fields: ctx, user, notification
+func (_m *NotificationChannelInterface) Send(ctx context.Context, user *entities.User, notification *entities.Notification) error {
+	ret := _m.Called(ctx, user, notification)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Send")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.User, *entities.Notification) error); ok {
+		r0 = rf(ctx, user, notification)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NotificationChannelInterface_Send_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Send'
+type NotificationChannelInterface_Send_Call struct {
+	*mock.Call
+}
+
+// Send is a helper method to define mock.On call
+//   - ctx context.Context
+//   - user *entities.User
+//   - notification *entities.Notification
+func (_e *NotificationChannelInterface_Expecter) Send(ctx interface{}, user interface{}, notification interface{}) *NotificationChannelInterface_Send_Call {
+	return &NotificationChannelInterface_Send_Call{Call: _e.mock.On("Send", ctx, user, notification)}
+}
+
+func (_c *NotificationChannelInterface_Send_Call) Run(run func(ctx context.Context, user *entities.User, notification *entities.Notification)) *NotificationChannelInterface_Send_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*entities.User), args[2].(*entities.Notification))
+	})
+	return _c
+}
+
+func (_c *NotificationChannelInterface_Send_Call) Return(_a0 error) *NotificationChannelInterface_Send_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *NotificationChannelInterface_Send_Call) RunAndReturn(run func(context.Context, *entities.User, *entities.Notification) error) *NotificationChannelInterface_Send_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewNotificationChannelInterface creates a new instance of NotificationChannelInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewNotificationChannelInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *NotificationChannelInterface {
+	mock := &NotificationChannelInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
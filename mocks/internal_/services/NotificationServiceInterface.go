@@ -0,0 +1,340 @@
+// Code generated by mockery v2.53.7. DO NOT EDIT.
+
+package services
+
+import (
+	context "context"
+
+	models "github.com/ShukinDmitriy/gophermart/internal/models"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NotificationServiceInterface is an autogenerated mock type for the NotificationServiceInterface type
+type NotificationServiceInterface struct {
+	mock.Mock
+}
+
+type NotificationServiceInterface_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *NotificationServiceInterface) EXPECT() *NotificationServiceInterface_Expecter {
+	return &NotificationServiceInterface_Expecter{mock: &_m.Mock}
+}
+
+// GetNotifications provides a mock function with given fields: userID, request
+func (_m *NotificationServiceInterface) GetNotifications(userID uint, request models.GetNotificationsRequest) (*models.GetNotificationsResponse, error) {
+	ret := _m.Called(userID, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetNotifications")
+	}
+
+	var r0 *models.GetNotificationsResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint, models.GetNotificationsRequest) (*models.GetNotificationsResponse, error)); ok {
+		return rf(userID, request)
+	}
+	if rf, ok := ret.Get(0).(func(uint, models.GetNotificationsRequest) *models.GetNotificationsResponse); ok {
+		r0 = rf(userID, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.GetNotificationsResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint, models.GetNotificationsRequest) error); ok {
+		r1 = rf(userID, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NotificationServiceInterface_GetNotifications_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetNotifications'
+type NotificationServiceInterface_GetNotifications_Call struct {
+	*mock.Call
+}
+
+// GetNotifications is a helper method to define mock.On call
+//   - userID uint
+//   - request models.GetNotificationsRequest
+func (_e *NotificationServiceInterface_Expecter) GetNotifications(userID interface{}, request interface{}) *NotificationServiceInterface_GetNotifications_Call {
+	return &NotificationServiceInterface_GetNotifications_Call{Call: _e.mock.On("GetNotifications", userID, request)}
+}
+
+func (_c *NotificationServiceInterface_GetNotifications_Call) Run(run func(userID uint, request models.GetNotificationsRequest)) *NotificationServiceInterface_GetNotifications_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(uint), args[1].(models.GetNotificationsRequest))
+	})
+	return _c
+}
+
+func (_c *NotificationServiceInterface_GetNotifications_Call) Return(_a0 *models.GetNotificationsResponse, _a1 error) *NotificationServiceInterface_GetNotifications_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *NotificationServiceInterface_GetNotifications_Call) RunAndReturn(run func(uint, models.GetNotificationsRequest) (*models.GetNotificationsResponse, error)) *NotificationServiceInterface_GetNotifications_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetPreferences provides a mock function with given fields: userID
+func (_m *NotificationServiceInterface) GetPreferences(userID uint) (*models.NotificationPreferencesResponse, error) {
+	ret := _m.Called(userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPreferences")
+	}
+
+	var r0 *models.NotificationPreferencesResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint) (*models.NotificationPreferencesResponse, error)); ok {
+		return rf(userID)
+	}
+	if rf, ok := ret.Get(0).(func(uint) *models.NotificationPreferencesResponse); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.NotificationPreferencesResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NotificationServiceInterface_GetPreferences_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPreferences'
+type NotificationServiceInterface_GetPreferences_Call struct {
+	*mock.Call
+}
+
+// GetPreferences is a helper method to define mock.On call
+//   - userID uint
+func (_e *NotificationServiceInterface_Expecter) GetPreferences(userID interface{}) *NotificationServiceInterface_GetPreferences_Call {
+	return &NotificationServiceInterface_GetPreferences_Call{Call: _e.mock.On("GetPreferences", userID)}
+}
+
+func (_c *NotificationServiceInterface_GetPreferences_Call) Run(run func(userID uint)) *NotificationServiceInterface_GetPreferences_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(uint))
+	})
+	return _c
+}
+
+func (_c *NotificationServiceInterface_GetPreferences_Call) Return(_a0 *models.NotificationPreferencesResponse, _a1 error) *NotificationServiceInterface_GetPreferences_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *NotificationServiceInterface_GetPreferences_Call) RunAndReturn(run func(uint) (*models.NotificationPreferencesResponse, error)) *NotificationServiceInterface_GetPreferences_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MarkAllRead provides a mock function with given fields: userID
+func (_m *NotificationServiceInterface) MarkAllRead(userID uint) error {
+	ret := _m.Called(userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkAllRead")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uint) error); ok {
+		r0 = rf(userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NotificationServiceInterface_MarkAllRead_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MarkAllRead'
+type NotificationServiceInterface_MarkAllRead_Call struct {
+	*mock.Call
+}
+
+// MarkAllRead is a helper method to define mock.On call
+//   - userID uint
+func (_e *NotificationServiceInterface_Expecter) MarkAllRead(userID interface{}) *NotificationServiceInterface_MarkAllRead_Call {
+	return &NotificationServiceInterface_MarkAllRead_Call{Call: _e.mock.On("MarkAllRead", userID)}
+}
+
+func (_c *NotificationServiceInterface_MarkAllRead_Call) Run(run func(userID uint)) *NotificationServiceInterface_MarkAllRead_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(uint))
+	})
+	return _c
+}
+
+func (_c *NotificationServiceInterface_MarkAllRead_Call) Return(_a0 error) *NotificationServiceInterface_MarkAllRead_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *NotificationServiceInterface_MarkAllRead_Call) RunAndReturn(run func(uint) error) *NotificationServiceInterface_MarkAllRead_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MarkRead provides a mock function with given fields: userID, id
+func (_m *NotificationServiceInterface) MarkRead(userID uint, id uint) error {
+	ret := _m.Called(userID, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkRead")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uint, uint) error); ok {
+		r0 = rf(userID, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NotificationServiceInterface_MarkRead_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MarkRead'
+type NotificationServiceInterface_MarkRead_Call struct {
+	*mock.Call
+}
+
+// MarkRead is a helper method to define mock.On call
+//   - userID uint
+//   - id uint
+func (_e *NotificationServiceInterface_Expecter) MarkRead(userID interface{}, id interface{}) *NotificationServiceInterface_MarkRead_Call {
+	return &NotificationServiceInterface_MarkRead_Call{Call: _e.mock.On("MarkRead", userID, id)}
+}
+
+func (_c *NotificationServiceInterface_MarkRead_Call) Run(run func(userID uint, id uint)) *NotificationServiceInterface_MarkRead_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(uint), args[1].(uint))
+	})
+	return _c
+}
+
+func (_c *NotificationServiceInterface_MarkRead_Call) Return(_a0 error) *NotificationServiceInterface_MarkRead_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *NotificationServiceInterface_MarkRead_Call) RunAndReturn(run func(uint, uint) error) *NotificationServiceInterface_MarkRead_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Notify provides a mock function with given fields: ctx, event
+func (_m *NotificationServiceInterface) Notify(ctx context.Context, event models.NotificationEvent) {
+	_m.Called(ctx, event)
+}
+
+// NotificationServiceInterface_Notify_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Notify'
+type NotificationServiceInterface_Notify_Call struct {
+	*mock.Call
+}
+
+// Notify is a helper method to define mock.On call
+//   - ctx context.Context
+//   - event models.NotificationEvent
+func (_e *NotificationServiceInterface_Expecter) Notify(ctx interface{}, event interface{}) *NotificationServiceInterface_Notify_Call {
+	return &NotificationServiceInterface_Notify_Call{Call: _e.mock.On("Notify", ctx, event)}
+}
+
+func (_c *NotificationServiceInterface_Notify_Call) Run(run func(ctx context.Context, event models.NotificationEvent)) *NotificationServiceInterface_Notify_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.NotificationEvent))
+	})
+	return _c
+}
+
+func (_c *NotificationServiceInterface_Notify_Call) Return() *NotificationServiceInterface_Notify_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *NotificationServiceInterface_Notify_Call) RunAndReturn(run func(context.Context, models.NotificationEvent)) *NotificationServiceInterface_Notify_Call {
+	_c.Run(run)
+	return _c
+}
+
+// UpdatePreferences provides a mock function with given fields: userID, request
+func (_m *NotificationServiceInterface) UpdatePreferences(userID uint, request models.NotificationPreferencesRequest) (*models.NotificationPreferencesResponse, error) {
+	ret := _m.Called(userID, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdatePreferences")
+	}
+
+	var r0 *models.NotificationPreferencesResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint, models.NotificationPreferencesRequest) (*models.NotificationPreferencesResponse, error)); ok {
+		return rf(userID, request)
+	}
+	if rf, ok := ret.Get(0).(func(uint, models.NotificationPreferencesRequest) *models.NotificationPreferencesResponse); ok {
+		r0 = rf(userID, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.NotificationPreferencesResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint, models.NotificationPreferencesRequest) error); ok {
+		r1 = rf(userID, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NotificationServiceInterface_UpdatePreferences_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdatePreferences'
+type NotificationServiceInterface_UpdatePreferences_Call struct {
+	*mock.Call
+}
+
+// UpdatePreferences is a helper method to define mock.On call
+//   - userID uint
+//   - request models.NotificationPreferencesRequest
+func (_e *NotificationServiceInterface_Expecter) UpdatePreferences(userID interface{}, request interface{}) *NotificationServiceInterface_UpdatePreferences_Call {
+	return &NotificationServiceInterface_UpdatePreferences_Call{Call: _e.mock.On("UpdatePreferences", userID, request)}
+}
+
+func (_c *NotificationServiceInterface_UpdatePreferences_Call) Run(run func(userID uint, request models.NotificationPreferencesRequest)) *NotificationServiceInterface_UpdatePreferences_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(uint), args[1].(models.NotificationPreferencesRequest))
+	})
+	return _c
+}
+
+func (_c *NotificationServiceInterface_UpdatePreferences_Call) Return(_a0 *models.NotificationPreferencesResponse, _a1 error) *NotificationServiceInterface_UpdatePreferences_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *NotificationServiceInterface_UpdatePreferences_Call) RunAndReturn(run func(uint, models.NotificationPreferencesRequest) (*models.NotificationPreferencesResponse, error)) *NotificationServiceInterface_UpdatePreferences_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewNotificationServiceInterface creates a new instance of NotificationServiceInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewNotificationServiceInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *NotificationServiceInterface {
+	mock := &NotificationServiceInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
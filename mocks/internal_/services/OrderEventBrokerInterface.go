@@ -0,0 +1,176 @@
+// Code generated by mockery v2.53.7. DO NOT EDIT.
+
+package services
+
+import (
+	context "context"
+
+	models "github.com/ShukinDmitriy/gophermart/internal/models"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// OrderEventBrokerInterface is an autogenerated mock type for the OrderEventBrokerInterface type
+type OrderEventBrokerInterface struct {
+	mock.Mock
+}
+
+type OrderEventBrokerInterface_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *OrderEventBrokerInterface) EXPECT() *OrderEventBrokerInterface_Expecter {
+	return &OrderEventBrokerInterface_Expecter{mock: &_m.Mock}
+}
+
+// Listen provides a mock function with given fields: ctx
+func (_m *OrderEventBrokerInterface) Listen(ctx context.Context) {
+	_m.Called(ctx)
+}
+
+// OrderEventBrokerInterface_Listen_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Listen'
+type OrderEventBrokerInterface_Listen_Call struct {
+	*mock.Call
+}
+
+// Listen is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *OrderEventBrokerInterface_Expecter) Listen(ctx interface{}) *OrderEventBrokerInterface_Listen_Call {
+	return &OrderEventBrokerInterface_Listen_Call{Call: _e.mock.On("Listen", ctx)}
+}
+
+func (_c *OrderEventBrokerInterface_Listen_Call) Run(run func(ctx context.Context)) *OrderEventBrokerInterface_Listen_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *OrderEventBrokerInterface_Listen_Call) Return() *OrderEventBrokerInterface_Listen_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *OrderEventBrokerInterface_Listen_Call) RunAndReturn(run func(context.Context)) *OrderEventBrokerInterface_Listen_Call {
+	_c.Run(run)
+	return _c
+}
+
+// Publish provides a mock function with given fields: event
+func (_m *OrderEventBrokerInterface) Publish(event models.OrderEvent) error {
+	ret := _m.Called(event)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Publish")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(models.OrderEvent) error); ok {
+		r0 = rf(event)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// OrderEventBrokerInterface_Publish_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Publish'
+type OrderEventBrokerInterface_Publish_Call struct {
+	*mock.Call
+}
+
+// Publish is a helper method to define mock.On call
+//   - event models.OrderEvent
+func (_e *OrderEventBrokerInterface_Expecter) Publish(event interface{}) *OrderEventBrokerInterface_Publish_Call {
+	return &OrderEventBrokerInterface_Publish_Call{Call: _e.mock.On("Publish", event)}
+}
+
+func (_c *OrderEventBrokerInterface_Publish_Call) Run(run func(event models.OrderEvent)) *OrderEventBrokerInterface_Publish_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(models.OrderEvent))
+	})
+	return _c
+}
+
+func (_c *OrderEventBrokerInterface_Publish_Call) Return(_a0 error) *OrderEventBrokerInterface_Publish_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *OrderEventBrokerInterface_Publish_Call) RunAndReturn(run func(models.OrderEvent) error) *OrderEventBrokerInterface_Publish_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Subscribe provides a mock function with given fields: userID
+func (_m *OrderEventBrokerInterface) Subscribe(userID uint) (<-chan models.OrderEvent, func()) {
+	ret := _m.Called(userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Subscribe")
+	}
+
+	var r0 <-chan models.OrderEvent
+	var r1 func()
+	if rf, ok := ret.Get(0).(func(uint) (<-chan models.OrderEvent, func())); ok {
+		return rf(userID)
+	}
+	if rf, ok := ret.Get(0).(func(uint) <-chan models.OrderEvent); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan models.OrderEvent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint) func()); ok {
+		r1 = rf(userID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(func())
+		}
+	}
+
+	return r0, r1
+}
+
+// OrderEventBrokerInterface_Subscribe_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Subscribe'
+type OrderEventBrokerInterface_Subscribe_Call struct {
+	*mock.Call
+}
+
+// Subscribe is a helper method to define mock.On call
+//   - userID uint
+func (_e *OrderEventBrokerInterface_Expecter) Subscribe(userID interface{}) *OrderEventBrokerInterface_Subscribe_Call {
+	return &OrderEventBrokerInterface_Subscribe_Call{Call: _e.mock.On("Subscribe", userID)}
+}
+
+func (_c *OrderEventBrokerInterface_Subscribe_Call) Run(run func(userID uint)) *OrderEventBrokerInterface_Subscribe_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(uint))
+	})
+	return _c
+}
+
+func (_c *OrderEventBrokerInterface_Subscribe_Call) Return(_a0 <-chan models.OrderEvent, _a1 func()) *OrderEventBrokerInterface_Subscribe_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *OrderEventBrokerInterface_Subscribe_Call) RunAndReturn(run func(uint) (<-chan models.OrderEvent, func())) *OrderEventBrokerInterface_Subscribe_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewOrderEventBrokerInterface creates a new instance of OrderEventBrokerInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewOrderEventBrokerInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *OrderEventBrokerInterface {
+	mock := &OrderEventBrokerInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,273 @@
+// Code generated by mockery v2.53.7. DO NOT EDIT.
+
+package services
+
+import (
+	context "context"
+
+	models "github.com/ShukinDmitriy/gophermart/internal/models"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// OrderServiceInterface is an autogenerated mock type for the OrderServiceInterface type
+type OrderServiceInterface struct {
+	mock.Mock
+}
+
+type OrderServiceInterface_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *OrderServiceInterface) EXPECT() *OrderServiceInterface_Expecter {
+	return &OrderServiceInterface_Expecter{mock: &_m.Mock}
+}
+
+// GetOrder provides a mock function with given fields: userID, number
+func (_m *OrderServiceInterface) GetOrder(userID uint, number string) (*models.GetOrderResponse, error) {
+	ret := _m.Called(userID, number)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOrder")
+	}
+
+	var r0 *models.GetOrderResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint, string) (*models.GetOrderResponse, error)); ok {
+		return rf(userID, number)
+	}
+	if rf, ok := ret.Get(0).(func(uint, string) *models.GetOrderResponse); ok {
+		r0 = rf(userID, number)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.GetOrderResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint, string) error); ok {
+		r1 = rf(userID, number)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OrderServiceInterface_GetOrder_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetOrder'
+type OrderServiceInterface_GetOrder_Call struct {
+	*mock.Call
+}
+
+// GetOrder is a helper method to define mock.On call
+//   - userID uint
+//   - number string
+func (_e *OrderServiceInterface_Expecter) GetOrder(userID interface{}, number interface{}) *OrderServiceInterface_GetOrder_Call {
+	return &OrderServiceInterface_GetOrder_Call{Call: _e.mock.On("GetOrder", userID, number)}
+}
+
+func (_c *OrderServiceInterface_GetOrder_Call) Run(run func(userID uint, number string)) *OrderServiceInterface_GetOrder_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(uint), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *OrderServiceInterface_GetOrder_Call) Return(_a0 *models.GetOrderResponse, _a1 error) *OrderServiceInterface_GetOrder_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *OrderServiceInterface_GetOrder_Call) RunAndReturn(run func(uint, string) (*models.GetOrderResponse, error)) *OrderServiceInterface_GetOrder_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListOrders provides a mock function with given fields: userID, request
+func (_m *OrderServiceInterface) ListOrders(userID uint, request models.GetOrdersRequest) (*models.GetOrdersPage, error) {
+	ret := _m.Called(userID, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListOrders")
+	}
+
+	var r0 *models.GetOrdersPage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint, models.GetOrdersRequest) (*models.GetOrdersPage, error)); ok {
+		return rf(userID, request)
+	}
+	if rf, ok := ret.Get(0).(func(uint, models.GetOrdersRequest) *models.GetOrdersPage); ok {
+		r0 = rf(userID, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.GetOrdersPage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint, models.GetOrdersRequest) error); ok {
+		r1 = rf(userID, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OrderServiceInterface_ListOrders_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListOrders'
+type OrderServiceInterface_ListOrders_Call struct {
+	*mock.Call
+}
+
+// ListOrders is a helper method to define mock.On call
+//   - userID uint
+//   - request models.GetOrdersRequest
+func (_e *OrderServiceInterface_Expecter) ListOrders(userID interface{}, request interface{}) *OrderServiceInterface_ListOrders_Call {
+	return &OrderServiceInterface_ListOrders_Call{Call: _e.mock.On("ListOrders", userID, request)}
+}
+
+func (_c *OrderServiceInterface_ListOrders_Call) Run(run func(userID uint, request models.GetOrdersRequest)) *OrderServiceInterface_ListOrders_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(uint), args[1].(models.GetOrdersRequest))
+	})
+	return _c
+}
+
+func (_c *OrderServiceInterface_ListOrders_Call) Return(_a0 *models.GetOrdersPage, _a1 error) *OrderServiceInterface_ListOrders_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *OrderServiceInterface_ListOrders_Call) RunAndReturn(run func(uint, models.GetOrdersRequest) (*models.GetOrdersPage, error)) *OrderServiceInterface_ListOrders_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UploadOrder provides a mock function with given fields: ctx, userID, number
+func (_m *OrderServiceInterface) UploadOrder(ctx context.Context, userID uint, number string) (bool, error) {
+	ret := _m.Called(ctx, userID, number)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UploadOrder")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, string) (bool, error)); ok {
+		return rf(ctx, userID, number)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint, string) bool); ok {
+		r0 = rf(ctx, userID, number)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint, string) error); ok {
+		r1 = rf(ctx, userID, number)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OrderServiceInterface_UploadOrder_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UploadOrder'
+type OrderServiceInterface_UploadOrder_Call struct {
+	*mock.Call
+}
+
+// UploadOrder is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uint
+//   - number string
+func (_e *OrderServiceInterface_Expecter) UploadOrder(ctx interface{}, userID interface{}, number interface{}) *OrderServiceInterface_UploadOrder_Call {
+	return &OrderServiceInterface_UploadOrder_Call{Call: _e.mock.On("UploadOrder", ctx, userID, number)}
+}
+
+func (_c *OrderServiceInterface_UploadOrder_Call) Run(run func(ctx context.Context, userID uint, number string)) *OrderServiceInterface_UploadOrder_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *OrderServiceInterface_UploadOrder_Call) Return(_a0 bool, _a1 error) *OrderServiceInterface_UploadOrder_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *OrderServiceInterface_UploadOrder_Call) RunAndReturn(run func(context.Context, uint, string) (bool, error)) *OrderServiceInterface_UploadOrder_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UploadOrders provides a mock function with given fields: ctx, userID, numbers
+func (_m *OrderServiceInterface) UploadOrders(ctx context.Context, userID uint, numbers []string) ([]models.CreateOrdersBatchResponse, error) {
+	ret := _m.Called(ctx, userID, numbers)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UploadOrders")
+	}
+
+	var r0 []models.CreateOrdersBatchResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, []string) ([]models.CreateOrdersBatchResponse, error)); ok {
+		return rf(ctx, userID, numbers)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint, []string) []models.CreateOrdersBatchResponse); ok {
+		r0 = rf(ctx, userID, numbers)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.CreateOrdersBatchResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint, []string) error); ok {
+		r1 = rf(ctx, userID, numbers)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OrderServiceInterface_UploadOrders_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UploadOrders'
+type OrderServiceInterface_UploadOrders_Call struct {
+	*mock.Call
+}
+
+// UploadOrders is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uint
+//   - numbers []string
+func (_e *OrderServiceInterface_Expecter) UploadOrders(ctx interface{}, userID interface{}, numbers interface{}) *OrderServiceInterface_UploadOrders_Call {
+	return &OrderServiceInterface_UploadOrders_Call{Call: _e.mock.On("UploadOrders", ctx, userID, numbers)}
+}
+
+func (_c *OrderServiceInterface_UploadOrders_Call) Run(run func(ctx context.Context, userID uint, numbers []string)) *OrderServiceInterface_UploadOrders_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint), args[2].([]string))
+	})
+	return _c
+}
+
+func (_c *OrderServiceInterface_UploadOrders_Call) Return(_a0 []models.CreateOrdersBatchResponse, _a1 error) *OrderServiceInterface_UploadOrders_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *OrderServiceInterface_UploadOrders_Call) RunAndReturn(run func(context.Context, uint, []string) ([]models.CreateOrdersBatchResponse, error)) *OrderServiceInterface_UploadOrders_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewOrderServiceInterface creates a new instance of OrderServiceInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewOrderServiceInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *OrderServiceInterface {
+	mock := &OrderServiceInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,188 @@
+// Code generated by mockery v2.53.7. DO NOT EDIT.
+
+package services
+
+import (
+	context "context"
+
+	models "github.com/ShukinDmitriy/gophermart/internal/models"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// ReconciliationServiceInterface is an autogenerated mock type for the ReconciliationServiceInterface type
+type ReconciliationServiceInterface struct {
+	mock.Mock
+}
+
+type ReconciliationServiceInterface_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *ReconciliationServiceInterface) EXPECT() *ReconciliationServiceInterface_Expecter {
+	return &ReconciliationServiceInterface_Expecter{mock: &_m.Mock}
+}
+
+// Reconcile provides a mock function with given fields: ctx, from, to
+func (_m *ReconciliationServiceInterface) Reconcile(ctx context.Context, from time.Time, to time.Time) (*models.ReconciliationReport, error) {
+	ret := _m.Called(ctx, from, to)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Reconcile")
+	}
+
+	var r0 *models.ReconciliationReport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time) (*models.ReconciliationReport, error)); ok {
+		return rf(ctx, from, to)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time) *models.ReconciliationReport); ok {
+		r0 = rf(ctx, from, to)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.ReconciliationReport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, time.Time) error); ok {
+		r1 = rf(ctx, from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReconciliationServiceInterface_Reconcile_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Reconcile'
+type ReconciliationServiceInterface_Reconcile_Call struct {
+	*mock.Call
+}
+
+// Reconcile is a helper method to define mock.On call
+//   - ctx context.Context
+//   - from time.Time
+//   - to time.Time
+func (_e *ReconciliationServiceInterface_Expecter) Reconcile(ctx interface{}, from interface{}, to interface{}) *ReconciliationServiceInterface_Reconcile_Call {
+	return &ReconciliationServiceInterface_Reconcile_Call{Call: _e.mock.On("Reconcile", ctx, from, to)}
+}
+
+func (_c *ReconciliationServiceInterface_Reconcile_Call) Run(run func(ctx context.Context, from time.Time, to time.Time)) *ReconciliationServiceInterface_Reconcile_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time), args[2].(time.Time))
+	})
+	return _c
+}
+
+func (_c *ReconciliationServiceInterface_Reconcile_Call) Return(_a0 *models.ReconciliationReport, _a1 error) *ReconciliationServiceInterface_Reconcile_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *ReconciliationServiceInterface_Reconcile_Call) RunAndReturn(run func(context.Context, time.Time, time.Time) (*models.ReconciliationReport, error)) *ReconciliationServiceInterface_Reconcile_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RunNightly provides a mock function with given fields: ctx
+func (_m *ReconciliationServiceInterface) RunNightly(ctx context.Context) {
+	_m.Called(ctx)
+}
+
+// ReconciliationServiceInterface_RunNightly_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RunNightly'
+type ReconciliationServiceInterface_RunNightly_Call struct {
+	*mock.Call
+}
+
+// RunNightly is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *ReconciliationServiceInterface_Expecter) RunNightly(ctx interface{}) *ReconciliationServiceInterface_RunNightly_Call {
+	return &ReconciliationServiceInterface_RunNightly_Call{Call: _e.mock.On("RunNightly", ctx)}
+}
+
+func (_c *ReconciliationServiceInterface_RunNightly_Call) Run(run func(ctx context.Context)) *ReconciliationServiceInterface_RunNightly_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *ReconciliationServiceInterface_RunNightly_Call) Return() *ReconciliationServiceInterface_RunNightly_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *ReconciliationServiceInterface_RunNightly_Call) RunAndReturn(run func(context.Context)) *ReconciliationServiceInterface_RunNightly_Call {
+	_c.Run(run)
+	return _c
+}
+
+// SaveReport provides a mock function with given fields: report
+func (_m *ReconciliationServiceInterface) SaveReport(report *models.ReconciliationReport) (string, error) {
+	ret := _m.Called(report)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveReport")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*models.ReconciliationReport) (string, error)); ok {
+		return rf(report)
+	}
+	if rf, ok := ret.Get(0).(func(*models.ReconciliationReport) string); ok {
+		r0 = rf(report)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(*models.ReconciliationReport) error); ok {
+		r1 = rf(report)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReconciliationServiceInterface_SaveReport_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveReport'
+type ReconciliationServiceInterface_SaveReport_Call struct {
+	*mock.Call
+}
+
+// SaveReport is a helper method to define mock.On call
+//   - report *models.ReconciliationReport
+func (_e *ReconciliationServiceInterface_Expecter) SaveReport(report interface{}) *ReconciliationServiceInterface_SaveReport_Call {
+	return &ReconciliationServiceInterface_SaveReport_Call{Call: _e.mock.On("SaveReport", report)}
+}
+
+func (_c *ReconciliationServiceInterface_SaveReport_Call) Run(run func(report *models.ReconciliationReport)) *ReconciliationServiceInterface_SaveReport_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*models.ReconciliationReport))
+	})
+	return _c
+}
+
+func (_c *ReconciliationServiceInterface_SaveReport_Call) Return(_a0 string, _a1 error) *ReconciliationServiceInterface_SaveReport_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *ReconciliationServiceInterface_SaveReport_Call) RunAndReturn(run func(*models.ReconciliationReport) (string, error)) *ReconciliationServiceInterface_SaveReport_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewReconciliationServiceInterface creates a new instance of ReconciliationServiceInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewReconciliationServiceInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ReconciliationServiceInterface {
+	mock := &ReconciliationServiceInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
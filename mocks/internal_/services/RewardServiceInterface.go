@@ -0,0 +1,313 @@
+// Code generated by mockery v2.53.7. DO NOT EDIT.
+
+package services
+
+import (
+	models "github.com/ShukinDmitriy/gophermart/internal/models"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// RewardServiceInterface is an autogenerated mock type for the RewardServiceInterface type
+type RewardServiceInterface struct {
+	mock.Mock
+}
+
+type RewardServiceInterface_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *RewardServiceInterface) EXPECT() *RewardServiceInterface_Expecter {
+	return &RewardServiceInterface_Expecter{mock: &_m.Mock}
+}
+
+// CreateRule provides a mock function with given fields: request
+func (_m *RewardServiceInterface) CreateRule(request models.RewardRuleRequest) (*models.RewardRuleResponse, error) {
+	ret := _m.Called(request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateRule")
+	}
+
+	var r0 *models.RewardRuleResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(models.RewardRuleRequest) (*models.RewardRuleResponse, error)); ok {
+		return rf(request)
+	}
+	if rf, ok := ret.Get(0).(func(models.RewardRuleRequest) *models.RewardRuleResponse); ok {
+		r0 = rf(request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.RewardRuleResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(models.RewardRuleRequest) error); ok {
+		r1 = rf(request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RewardServiceInterface_CreateRule_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateRule'
+type RewardServiceInterface_CreateRule_Call struct {
+	*mock.Call
+}
+
+// CreateRule is a helper method to define mock.On call
+//   - request models.RewardRuleRequest
+func (_e *RewardServiceInterface_Expecter) CreateRule(request interface{}) *RewardServiceInterface_CreateRule_Call {
+	return &RewardServiceInterface_CreateRule_Call{Call: _e.mock.On("CreateRule", request)}
+}
+
+func (_c *RewardServiceInterface_CreateRule_Call) Run(run func(request models.RewardRuleRequest)) *RewardServiceInterface_CreateRule_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(models.RewardRuleRequest))
+	})
+	return _c
+}
+
+func (_c *RewardServiceInterface_CreateRule_Call) Return(_a0 *models.RewardRuleResponse, _a1 error) *RewardServiceInterface_CreateRule_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *RewardServiceInterface_CreateRule_Call) RunAndReturn(run func(models.RewardRuleRequest) (*models.RewardRuleResponse, error)) *RewardServiceInterface_CreateRule_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteRule provides a mock function with given fields: id
+func (_m *RewardServiceInterface) DeleteRule(id uint) error {
+	ret := _m.Called(id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteRule")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uint) error); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RewardServiceInterface_DeleteRule_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteRule'
+type RewardServiceInterface_DeleteRule_Call struct {
+	*mock.Call
+}
+
+// DeleteRule is a helper method to define mock.On call
+//   - id uint
+func (_e *RewardServiceInterface_Expecter) DeleteRule(id interface{}) *RewardServiceInterface_DeleteRule_Call {
+	return &RewardServiceInterface_DeleteRule_Call{Call: _e.mock.On("DeleteRule", id)}
+}
+
+func (_c *RewardServiceInterface_DeleteRule_Call) Run(run func(id uint)) *RewardServiceInterface_DeleteRule_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(uint))
+	})
+	return _c
+}
+
+func (_c *RewardServiceInterface_DeleteRule_Call) Return(_a0 error) *RewardServiceInterface_DeleteRule_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *RewardServiceInterface_DeleteRule_Call) RunAndReturn(run func(uint) error) *RewardServiceInterface_DeleteRule_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetRules provides a mock function with no fields
+func (_m *RewardServiceInterface) GetRules() ([]models.RewardRuleResponse, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRules")
+	}
+
+	var r0 []models.RewardRuleResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]models.RewardRuleResponse, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []models.RewardRuleResponse); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.RewardRuleResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RewardServiceInterface_GetRules_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetRules'
+type RewardServiceInterface_GetRules_Call struct {
+	*mock.Call
+}
+
+// GetRules is a helper method to define mock.On call
+func (_e *RewardServiceInterface_Expecter) GetRules() *RewardServiceInterface_GetRules_Call {
+	return &RewardServiceInterface_GetRules_Call{Call: _e.mock.On("GetRules")}
+}
+
+func (_c *RewardServiceInterface_GetRules_Call) Run(run func()) *RewardServiceInterface_GetRules_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *RewardServiceInterface_GetRules_Call) Return(_a0 []models.RewardRuleResponse, _a1 error) *RewardServiceInterface_GetRules_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *RewardServiceInterface_GetRules_Call) RunAndReturn(run func() ([]models.RewardRuleResponse, error)) *RewardServiceInterface_GetRules_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SubmitBasket provides a mock function with given fields: request
+func (_m *RewardServiceInterface) SubmitBasket(request models.OrderBasketRequest) (*models.OrderBasketResponse, error) {
+	ret := _m.Called(request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SubmitBasket")
+	}
+
+	var r0 *models.OrderBasketResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(models.OrderBasketRequest) (*models.OrderBasketResponse, error)); ok {
+		return rf(request)
+	}
+	if rf, ok := ret.Get(0).(func(models.OrderBasketRequest) *models.OrderBasketResponse); ok {
+		r0 = rf(request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.OrderBasketResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(models.OrderBasketRequest) error); ok {
+		r1 = rf(request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RewardServiceInterface_SubmitBasket_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SubmitBasket'
+type RewardServiceInterface_SubmitBasket_Call struct {
+	*mock.Call
+}
+
+// SubmitBasket is a helper method to define mock.On call
+//   - request models.OrderBasketRequest
+func (_e *RewardServiceInterface_Expecter) SubmitBasket(request interface{}) *RewardServiceInterface_SubmitBasket_Call {
+	return &RewardServiceInterface_SubmitBasket_Call{Call: _e.mock.On("SubmitBasket", request)}
+}
+
+func (_c *RewardServiceInterface_SubmitBasket_Call) Run(run func(request models.OrderBasketRequest)) *RewardServiceInterface_SubmitBasket_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(models.OrderBasketRequest))
+	})
+	return _c
+}
+
+func (_c *RewardServiceInterface_SubmitBasket_Call) Return(_a0 *models.OrderBasketResponse, _a1 error) *RewardServiceInterface_SubmitBasket_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *RewardServiceInterface_SubmitBasket_Call) RunAndReturn(run func(models.OrderBasketRequest) (*models.OrderBasketResponse, error)) *RewardServiceInterface_SubmitBasket_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateRule provides a mock function with given fields: id, request
+func (_m *RewardServiceInterface) UpdateRule(id uint, request models.RewardRuleRequest) (*models.RewardRuleResponse, error) {
+	ret := _m.Called(id, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateRule")
+	}
+
+	var r0 *models.RewardRuleResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint, models.RewardRuleRequest) (*models.RewardRuleResponse, error)); ok {
+		return rf(id, request)
+	}
+	if rf, ok := ret.Get(0).(func(uint, models.RewardRuleRequest) *models.RewardRuleResponse); ok {
+		r0 = rf(id, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.RewardRuleResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint, models.RewardRuleRequest) error); ok {
+		r1 = rf(id, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RewardServiceInterface_UpdateRule_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateRule'
+type RewardServiceInterface_UpdateRule_Call struct {
+	*mock.Call
+}
+
+// UpdateRule is a helper method to define mock.On call
+//   - id uint
+//   - request models.RewardRuleRequest
+func (_e *RewardServiceInterface_Expecter) UpdateRule(id interface{}, request interface{}) *RewardServiceInterface_UpdateRule_Call {
+	return &RewardServiceInterface_UpdateRule_Call{Call: _e.mock.On("UpdateRule", id, request)}
+}
+
+func (_c *RewardServiceInterface_UpdateRule_Call) Run(run func(id uint, request models.RewardRuleRequest)) *RewardServiceInterface_UpdateRule_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(uint), args[1].(models.RewardRuleRequest))
+	})
+	return _c
+}
+
+func (_c *RewardServiceInterface_UpdateRule_Call) Return(_a0 *models.RewardRuleResponse, _a1 error) *RewardServiceInterface_UpdateRule_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *RewardServiceInterface_UpdateRule_Call) RunAndReturn(run func(uint, models.RewardRuleRequest) (*models.RewardRuleResponse, error)) *RewardServiceInterface_UpdateRule_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewRewardServiceInterface creates a new instance of RewardServiceInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRewardServiceInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *RewardServiceInterface {
+	mock := &RewardServiceInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,153 @@
+// Code generated by mockery v2.53.7. DO NOT EDIT.
+
+package services
+
+import (
+	entities "github.com/ShukinDmitriy/gophermart/internal/entities"
+	mock "github.com/stretchr/testify/mock"
+
+	models "github.com/ShukinDmitriy/gophermart/internal/models"
+)
+
+// UserServiceInterface is an autogenerated mock type for the UserServiceInterface type
+type UserServiceInterface struct {
+	mock.Mock
+}
+
+type UserServiceInterface_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *UserServiceInterface) EXPECT() *UserServiceInterface_Expecter {
+	return &UserServiceInterface_Expecter{mock: &_m.Mock}
+}
+
+// Login provides a mock function with given fields: request
+func (_m *UserServiceInterface) Login(request models.UserLoginRequest) (*entities.User, error) {
+	ret := _m.Called(request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Login")
+	}
+
+	var r0 *entities.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(models.UserLoginRequest) (*entities.User, error)); ok {
+		return rf(request)
+	}
+	if rf, ok := ret.Get(0).(func(models.UserLoginRequest) *entities.User); ok {
+		r0 = rf(request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(models.UserLoginRequest) error); ok {
+		r1 = rf(request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UserServiceInterface_Login_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Login'
+type UserServiceInterface_Login_Call struct {
+	*mock.Call
+}
+
+// Login is a helper method to define mock.On call
+//   - request models.UserLoginRequest
+func (_e *UserServiceInterface_Expecter) Login(request interface{}) *UserServiceInterface_Login_Call {
+	return &UserServiceInterface_Login_Call{Call: _e.mock.On("Login", request)}
+}
+
+func (_c *UserServiceInterface_Login_Call) Run(run func(request models.UserLoginRequest)) *UserServiceInterface_Login_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(models.UserLoginRequest))
+	})
+	return _c
+}
+
+func (_c *UserServiceInterface_Login_Call) Return(_a0 *entities.User, _a1 error) *UserServiceInterface_Login_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *UserServiceInterface_Login_Call) RunAndReturn(run func(models.UserLoginRequest) (*entities.User, error)) *UserServiceInterface_Login_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Register provides a mock function with given fields: request
+func (_m *UserServiceInterface) Register(request models.UserRegisterRequest) (*models.UserInfoResponse, error) {
+	ret := _m.Called(request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Register")
+	}
+
+	var r0 *models.UserInfoResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(models.UserRegisterRequest) (*models.UserInfoResponse, error)); ok {
+		return rf(request)
+	}
+	if rf, ok := ret.Get(0).(func(models.UserRegisterRequest) *models.UserInfoResponse); ok {
+		r0 = rf(request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.UserInfoResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(models.UserRegisterRequest) error); ok {
+		r1 = rf(request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UserServiceInterface_Register_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Register'
+type UserServiceInterface_Register_Call struct {
+	*mock.Call
+}
+
+// Register is a helper method to define mock.On call
+//   - request models.UserRegisterRequest
+func (_e *UserServiceInterface_Expecter) Register(request interface{}) *UserServiceInterface_Register_Call {
+	return &UserServiceInterface_Register_Call{Call: _e.mock.On("Register", request)}
+}
+
+func (_c *UserServiceInterface_Register_Call) Run(run func(request models.UserRegisterRequest)) *UserServiceInterface_Register_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(models.UserRegisterRequest))
+	})
+	return _c
+}
+
+func (_c *UserServiceInterface_Register_Call) Return(_a0 *models.UserInfoResponse, _a1 error) *UserServiceInterface_Register_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *UserServiceInterface_Register_Call) RunAndReturn(run func(models.UserRegisterRequest) (*models.UserInfoResponse, error)) *UserServiceInterface_Register_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewUserServiceInterface creates a new instance of UserServiceInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewUserServiceInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *UserServiceInterface {
+	mock := &UserServiceInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,295 @@
+// Code generated by mockery v2.53.7. DO NOT EDIT.
+
+package services
+
+import (
+	context "context"
+
+	models "github.com/ShukinDmitriy/gophermart/internal/models"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// WebhookServiceInterface is an autogenerated mock type for the WebhookServiceInterface type
+type WebhookServiceInterface struct {
+	mock.Mock
+}
+
+type WebhookServiceInterface_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *WebhookServiceInterface) EXPECT() *WebhookServiceInterface_Expecter {
+	return &WebhookServiceInterface_Expecter{mock: &_m.Mock}
+}
+
+// CreateWebhook provides a mock function with given fields: owner, request
+func (_m *WebhookServiceInterface) CreateWebhook(owner *uint, request models.WebhookRequest) (*models.WebhookResponse, error) {
+	ret := _m.Called(owner, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateWebhook")
+	}
+
+	var r0 *models.WebhookResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*uint, models.WebhookRequest) (*models.WebhookResponse, error)); ok {
+		return rf(owner, request)
+	}
+	if rf, ok := ret.Get(0).(func(*uint, models.WebhookRequest) *models.WebhookResponse); ok {
+		r0 = rf(owner, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.WebhookResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*uint, models.WebhookRequest) error); ok {
+		r1 = rf(owner, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// WebhookServiceInterface_CreateWebhook_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateWebhook'
+type WebhookServiceInterface_CreateWebhook_Call struct {
+	*mock.Call
+}
+
+// CreateWebhook is a helper method to define mock.On call
+//   - owner *uint
+//   - request models.WebhookRequest
+func (_e *WebhookServiceInterface_Expecter) CreateWebhook(owner interface{}, request interface{}) *WebhookServiceInterface_CreateWebhook_Call {
+	return &WebhookServiceInterface_CreateWebhook_Call{Call: _e.mock.On("CreateWebhook", owner, request)}
+}
+
+func (_c *WebhookServiceInterface_CreateWebhook_Call) Run(run func(owner *uint, request models.WebhookRequest)) *WebhookServiceInterface_CreateWebhook_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*uint), args[1].(models.WebhookRequest))
+	})
+	return _c
+}
+
+func (_c *WebhookServiceInterface_CreateWebhook_Call) Return(_a0 *models.WebhookResponse, _a1 error) *WebhookServiceInterface_CreateWebhook_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *WebhookServiceInterface_CreateWebhook_Call) RunAndReturn(run func(*uint, models.WebhookRequest) (*models.WebhookResponse, error)) *WebhookServiceInterface_CreateWebhook_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteWebhook provides a mock function with given fields: owner, id
+func (_m *WebhookServiceInterface) DeleteWebhook(owner *uint, id uint) error {
+	ret := _m.Called(owner, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteWebhook")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*uint, uint) error); ok {
+		r0 = rf(owner, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// WebhookServiceInterface_DeleteWebhook_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteWebhook'
+type WebhookServiceInterface_DeleteWebhook_Call struct {
+	*mock.Call
+}
+
+// DeleteWebhook is a helper method to define mock.On call
+//   - owner *uint
+//   - id uint
+func (_e *WebhookServiceInterface_Expecter) DeleteWebhook(owner interface{}, id interface{}) *WebhookServiceInterface_DeleteWebhook_Call {
+	return &WebhookServiceInterface_DeleteWebhook_Call{Call: _e.mock.On("DeleteWebhook", owner, id)}
+}
+
+func (_c *WebhookServiceInterface_DeleteWebhook_Call) Run(run func(owner *uint, id uint)) *WebhookServiceInterface_DeleteWebhook_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*uint), args[1].(uint))
+	})
+	return _c
+}
+
+func (_c *WebhookServiceInterface_DeleteWebhook_Call) Return(_a0 error) *WebhookServiceInterface_DeleteWebhook_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *WebhookServiceInterface_DeleteWebhook_Call) RunAndReturn(run func(*uint, uint) error) *WebhookServiceInterface_DeleteWebhook_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Emit provides a mock function with given fields: ctx, event
+func (_m *WebhookServiceInterface) Emit(ctx context.Context, event models.WebhookEvent) {
+	_m.Called(ctx, event)
+}
+
+// WebhookServiceInterface_Emit_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Emit'
+type WebhookServiceInterface_Emit_Call struct {
+	*mock.Call
+}
+
+// Emit is a helper method to define mock.On call
+//   - ctx context.Context
+//   - event models.WebhookEvent
+func (_e *WebhookServiceInterface_Expecter) Emit(ctx interface{}, event interface{}) *WebhookServiceInterface_Emit_Call {
+	return &WebhookServiceInterface_Emit_Call{Call: _e.mock.On("Emit", ctx, event)}
+}
+
+func (_c *WebhookServiceInterface_Emit_Call) Run(run func(ctx context.Context, event models.WebhookEvent)) *WebhookServiceInterface_Emit_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.WebhookEvent))
+	})
+	return _c
+}
+
+func (_c *WebhookServiceInterface_Emit_Call) Return() *WebhookServiceInterface_Emit_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *WebhookServiceInterface_Emit_Call) RunAndReturn(run func(context.Context, models.WebhookEvent)) *WebhookServiceInterface_Emit_Call {
+	_c.Run(run)
+	return _c
+}
+
+// GetDeliveries provides a mock function with given fields: owner, id, request
+func (_m *WebhookServiceInterface) GetDeliveries(owner *uint, id uint, request models.GetWebhookDeliveriesRequest) ([]models.WebhookDeliveryResponse, error) {
+	ret := _m.Called(owner, id, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDeliveries")
+	}
+
+	var r0 []models.WebhookDeliveryResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*uint, uint, models.GetWebhookDeliveriesRequest) ([]models.WebhookDeliveryResponse, error)); ok {
+		return rf(owner, id, request)
+	}
+	if rf, ok := ret.Get(0).(func(*uint, uint, models.GetWebhookDeliveriesRequest) []models.WebhookDeliveryResponse); ok {
+		r0 = rf(owner, id, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.WebhookDeliveryResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*uint, uint, models.GetWebhookDeliveriesRequest) error); ok {
+		r1 = rf(owner, id, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// WebhookServiceInterface_GetDeliveries_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetDeliveries'
+type WebhookServiceInterface_GetDeliveries_Call struct {
+	*mock.Call
+}
+
+// GetDeliveries is a helper method to define mock.On call
+//   - owner *uint
+//   - id uint
+//   - request models.GetWebhookDeliveriesRequest
+func (_e *WebhookServiceInterface_Expecter) GetDeliveries(owner interface{}, id interface{}, request interface{}) *WebhookServiceInterface_GetDeliveries_Call {
+	return &WebhookServiceInterface_GetDeliveries_Call{Call: _e.mock.On("GetDeliveries", owner, id, request)}
+}
+
+func (_c *WebhookServiceInterface_GetDeliveries_Call) Run(run func(owner *uint, id uint, request models.GetWebhookDeliveriesRequest)) *WebhookServiceInterface_GetDeliveries_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*uint), args[1].(uint), args[2].(models.GetWebhookDeliveriesRequest))
+	})
+	return _c
+}
+
+func (_c *WebhookServiceInterface_GetDeliveries_Call) Return(_a0 []models.WebhookDeliveryResponse, _a1 error) *WebhookServiceInterface_GetDeliveries_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *WebhookServiceInterface_GetDeliveries_Call) RunAndReturn(run func(*uint, uint, models.GetWebhookDeliveriesRequest) ([]models.WebhookDeliveryResponse, error)) *WebhookServiceInterface_GetDeliveries_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetWebhooks provides a mock function with given fields: owner
+func (_m *WebhookServiceInterface) GetWebhooks(owner *uint) ([]models.WebhookResponse, error) {
+	ret := _m.Called(owner)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWebhooks")
+	}
+
+	var r0 []models.WebhookResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*uint) ([]models.WebhookResponse, error)); ok {
+		return rf(owner)
+	}
+	if rf, ok := ret.Get(0).(func(*uint) []models.WebhookResponse); ok {
+		r0 = rf(owner)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.WebhookResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*uint) error); ok {
+		r1 = rf(owner)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// WebhookServiceInterface_GetWebhooks_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetWebhooks'
+type WebhookServiceInterface_GetWebhooks_Call struct {
+	*mock.Call
+}
+
+// GetWebhooks is a helper method to define mock.On call
+//   - owner *uint
+func (_e *WebhookServiceInterface_Expecter) GetWebhooks(owner interface{}) *WebhookServiceInterface_GetWebhooks_Call {
+	return &WebhookServiceInterface_GetWebhooks_Call{Call: _e.mock.On("GetWebhooks", owner)}
+}
+
+func (_c *WebhookServiceInterface_GetWebhooks_Call) Run(run func(owner *uint)) *WebhookServiceInterface_GetWebhooks_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*uint))
+	})
+	return _c
+}
+
+func (_c *WebhookServiceInterface_GetWebhooks_Call) Return(_a0 []models.WebhookResponse, _a1 error) *WebhookServiceInterface_GetWebhooks_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *WebhookServiceInterface_GetWebhooks_Call) RunAndReturn(run func(*uint) ([]models.WebhookResponse, error)) *WebhookServiceInterface_GetWebhooks_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewWebhookServiceInterface creates a new instance of WebhookServiceInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewWebhookServiceInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *WebhookServiceInterface {
+	mock := &WebhookServiceInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -5,5 +5,5 @@ import "github.com/ShukinDmitriy/gophermart/internal/config"
 // this is required to generate mock files
 // this file is not used anywhere
 func main() {
-	_ = config.NewConfig()
+	_ = config.Default()
 }